@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// PlatformDailyStat 平台每日统计
+type PlatformDailyStat struct {
+	SnapshotDate   time.Time `db:"snapshot_date"`   // 统计日期
+	TotalCompanies int64     `db:"total_companies"` // 截至当天的公司总数
+	TotalUsers     int64     `db:"total_users"`     // 截至当天的用户总数
+	TotalEmployees int64     `db:"total_employees"` // 截至当天的员工总数
+	TotalTasks     int64     `db:"total_tasks"`     // 截至当天的任务总数
+	NewCompanies   int64     `db:"new_companies"`   // 当天新增公司数
+	NewUsers       int64     `db:"new_users"`       // 当天新注册用户数
+	NewTasks       int64     `db:"new_tasks"`       // 当天新建任务数
+	UpdateTime     time.Time `db:"update_time"`     // 最近聚合时间
+}
+
+const platformDailyStatRows = "`snapshot_date`, `total_companies`, `total_users`, `total_employees`, `total_tasks`, `new_companies`, `new_users`, `new_tasks`, `update_time`"
+
+type PlatformStatsModel interface {
+	Compute(ctx context.Context, date time.Time) (*PlatformDailyStat, error)
+	Upsert(ctx context.Context, data *PlatformDailyStat) error
+	FindRange(ctx context.Context, start, end time.Time) ([]*PlatformDailyStat, error)
+}
+
+type defaultPlatformStatsModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewPlatformStatsModel(conn sqlx.SqlConn) PlatformStatsModel {
+	return &defaultPlatformStatsModel{
+		conn:  conn,
+		table: "`stats_platform_daily`",
+	}
+}
+
+// Compute 统计截至某天结束时的平台数据（一条 SQL 完成，走各表的 create_time 索引）
+func (m *defaultPlatformStatsModel) Compute(ctx context.Context, date time.Time) (*PlatformDailyStat, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	start := day.Format("2006-01-02 15:04:05")
+	end := day.AddDate(0, 0, 1).Format("2006-01-02 15:04:05")
+
+	query := "SELECT " +
+		"(SELECT COUNT(*) FROM `company` WHERE `delete_time` IS NULL AND `create_time` < ?) AS `total_companies`, " +
+		"(SELECT COUNT(*) FROM `user` WHERE `delete_time` IS NULL AND `create_time` < ?) AS `total_users`, " +
+		"(SELECT COUNT(*) FROM `employee` WHERE `delete_time` IS NULL AND `create_time` < ?) AS `total_employees`, " +
+		"(SELECT COUNT(*) FROM `task` WHERE `delete_time` IS NULL AND `create_time` < ?) AS `total_tasks`, " +
+		"(SELECT COUNT(*) FROM `company` WHERE `delete_time` IS NULL AND `create_time` >= ? AND `create_time` < ?) AS `new_companies`, " +
+		"(SELECT COUNT(*) FROM `user` WHERE `delete_time` IS NULL AND `create_time` >= ? AND `create_time` < ?) AS `new_users`, " +
+		"(SELECT COUNT(*) FROM `task` WHERE `delete_time` IS NULL AND `create_time` >= ? AND `create_time` < ?) AS `new_tasks`"
+	var resp struct {
+		TotalCompanies int64 `db:"total_companies"`
+		TotalUsers     int64 `db:"total_users"`
+		TotalEmployees int64 `db:"total_employees"`
+		TotalTasks     int64 `db:"total_tasks"`
+		NewCompanies   int64 `db:"new_companies"`
+		NewUsers       int64 `db:"new_users"`
+		NewTasks       int64 `db:"new_tasks"`
+	}
+	err := m.conn.QueryRowCtx(ctx, &resp, query, end, end, end, end, start, end, start, end, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &PlatformDailyStat{
+		SnapshotDate:   day,
+		TotalCompanies: resp.TotalCompanies,
+		TotalUsers:     resp.TotalUsers,
+		TotalEmployees: resp.TotalEmployees,
+		TotalTasks:     resp.TotalTasks,
+		NewCompanies:   resp.NewCompanies,
+		NewUsers:       resp.NewUsers,
+		NewTasks:       resp.NewTasks,
+		UpdateTime:     time.Now(),
+	}, nil
+}
+
+// Upsert 写入某天的平台统计，已存在时覆盖
+func (m *defaultPlatformStatsModel) Upsert(ctx context.Context, data *PlatformDailyStat) error {
+	query := fmt.Sprintf("INSERT INTO %s (`snapshot_date`, `total_companies`, `total_users`, `total_employees`, `total_tasks`, `new_companies`, `new_users`, `new_tasks`) VALUES (?, ?, ?, ?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `total_companies` = VALUES(`total_companies`), `total_users` = VALUES(`total_users`), `total_employees` = VALUES(`total_employees`), "+
+		"`total_tasks` = VALUES(`total_tasks`), `new_companies` = VALUES(`new_companies`), `new_users` = VALUES(`new_users`), `new_tasks` = VALUES(`new_tasks`), `update_time` = NOW()", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.SnapshotDate.Format("2006-01-02"), data.TotalCompanies, data.TotalUsers, data.TotalEmployees,
+		data.TotalTasks, data.NewCompanies, data.NewUsers, data.NewTasks)
+	return err
+}
+
+// FindRange 查询日期范围内的平台统计，按日期升序
+func (m *defaultPlatformStatsModel) FindRange(ctx context.Context, start, end time.Time) ([]*PlatformDailyStat, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `snapshot_date` >= ? AND `snapshot_date` <= ? ORDER BY `snapshot_date` ASC", platformDailyStatRows, m.table)
+	var resp []*PlatformDailyStat
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	return resp, err
+}
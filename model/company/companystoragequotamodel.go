@@ -0,0 +1,69 @@
+package company
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// CompanyStorageQuota 公司存储配额，没有记录的公司使用系统设置
+type CompanyStorageQuota struct {
+	CompanyId      string         `db:"company_id"`       // 公司ID
+	CompanyQuotaMb int64          `db:"company_quota_mb"` // 公司总配额（MB），-1 表示使用系统设置，0 表示不限制
+	UserQuotaMb    int64          `db:"user_quota_mb"`    // 每个员工的配额（MB），-1 表示使用系统设置，0 表示不限制
+	UpdateBy       sql.NullString `db:"update_by"`        // 最后修改的管理员ID
+	CreateTime     time.Time      `db:"create_time"`      // 创建时间
+	UpdateTime     time.Time      `db:"update_time"`      // 更新时间
+}
+
+const companyStorageQuotaRows = "`company_id`, `company_quota_mb`, `user_quota_mb`, `update_by`, `create_time`, `update_time`"
+
+type CompanyStorageQuotaModel interface {
+	FindOne(ctx context.Context, companyId string) (*CompanyStorageQuota, error)
+	Upsert(ctx context.Context, data *CompanyStorageQuota) error
+	Delete(ctx context.Context, companyId string) error
+}
+
+type defaultCompanyStorageQuotaModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewCompanyStorageQuotaModel(conn sqlx.SqlConn) CompanyStorageQuotaModel {
+	return &defaultCompanyStorageQuotaModel{
+		conn:  conn,
+		table: "`company_storage_quota`",
+	}
+}
+
+func (m *defaultCompanyStorageQuotaModel) FindOne(ctx context.Context, companyId string) (*CompanyStorageQuota, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? LIMIT 1", companyStorageQuotaRows, m.table)
+	var resp CompanyStorageQuota
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Upsert 写入公司存储配额，已存在时覆盖
+func (m *defaultCompanyStorageQuotaModel) Upsert(ctx context.Context, data *CompanyStorageQuota) error {
+	query := fmt.Sprintf("INSERT INTO %s (`company_id`, `company_quota_mb`, `user_quota_mb`, `update_by`) VALUES (?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `company_quota_mb` = VALUES(`company_quota_mb`), `user_quota_mb` = VALUES(`user_quota_mb`), `update_by` = VALUES(`update_by`), `update_time` = NOW()", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.CompanyId, data.CompanyQuotaMb, data.UserQuotaMb, data.UpdateBy)
+	return err
+}
+
+// Delete 删除公司存储配额（恢复为系统设置）
+func (m *defaultCompanyStorageQuotaModel) Delete(ctx context.Context, companyId string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `company_id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, companyId)
+	return err
+}
@@ -0,0 +1,81 @@
+package company
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// CompanyUploadPolicy 公司上传策略，没有记录的公司使用系统默认策略
+type CompanyUploadPolicy struct {
+	CompanyId     string         `db:"company_id"`       // 公司ID
+	AllowedTypes  string         `db:"allowed_types"`    // 允许的文件类型，逗号分隔，为空时允许全部类型
+	MaxFileSizeMb int64          `db:"max_file_size_mb"` // 单个文件大小上限（MB），0 表示使用系统设置
+	UpdateBy      sql.NullString `db:"update_by"`        // 最后修改人员工ID
+	CreateTime    time.Time      `db:"create_time"`      // 创建时间
+	UpdateTime    time.Time      `db:"update_time"`      // 更新时间
+}
+
+const companyUploadPolicyRows = "`company_id`, `allowed_types`, `max_file_size_mb`, `update_by`, `create_time`, `update_time`"
+
+type CompanyUploadPolicyModel interface {
+	FindOne(ctx context.Context, companyId string) (*CompanyUploadPolicy, error)
+	Upsert(ctx context.Context, data *CompanyUploadPolicy) error
+	Delete(ctx context.Context, companyId string) error
+}
+
+type defaultCompanyUploadPolicyModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewCompanyUploadPolicyModel(conn sqlx.SqlConn) CompanyUploadPolicyModel {
+	return &defaultCompanyUploadPolicyModel{
+		conn:  conn,
+		table: "`company_upload_policy`",
+	}
+}
+
+func (m *defaultCompanyUploadPolicyModel) FindOne(ctx context.Context, companyId string) (*CompanyUploadPolicy, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? LIMIT 1", companyUploadPolicyRows, m.table)
+	var resp CompanyUploadPolicy
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Upsert 写入公司上传策略，已存在时覆盖
+func (m *defaultCompanyUploadPolicyModel) Upsert(ctx context.Context, data *CompanyUploadPolicy) error {
+	query := fmt.Sprintf("INSERT INTO %s (`company_id`, `allowed_types`, `max_file_size_mb`, `update_by`) VALUES (?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `allowed_types` = VALUES(`allowed_types`), `max_file_size_mb` = VALUES(`max_file_size_mb`), `update_by` = VALUES(`update_by`), `update_time` = NOW()", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.CompanyId, data.AllowedTypes, data.MaxFileSizeMb, data.UpdateBy)
+	return err
+}
+
+// Delete 删除公司上传策略（恢复为系统默认策略）
+func (m *defaultCompanyUploadPolicyModel) Delete(ctx context.Context, companyId string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `company_id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, companyId)
+	return err
+}
+
+// AllowedTypeList 允许的文件类型列表，为空时允许全部类型
+func (p *CompanyUploadPolicy) AllowedTypeList() []string {
+	var types []string
+	for _, t := range strings.Split(p.AllowedTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
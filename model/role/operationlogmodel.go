@@ -1,15 +1,39 @@
 package role
 
-import "github.com/zeromicro/go-zero/core/stores/sqlx"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
 
 var _ OperationLogModel = (*customOperationLogModel)(nil)
 
+// OperationLogFilter 操作日志查询条件，零值字段不参与过滤
+type OperationLogFilter struct {
+	CompanyId     string
+	UserId        string
+	EmployeeId    string
+	OperationType string
+	EntityType    string
+	EntityId      string
+	Status        *int64
+	StartTime     time.Time
+	EndTime       time.Time
+}
+
 type (
 	// OperationLogModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customOperationLogModel.
 	OperationLogModel interface {
 		operationLogModel
 		withSession(session sqlx.Session) OperationLogModel
+
+		// 审计日志查询
+		Search(ctx context.Context, filter OperationLogFilter, page, pageSize int) ([]*OperationLog, int64, error)
+		FindForExport(ctx context.Context, filter OperationLogFilter, limit int) ([]*OperationLog, error)
 	}
 
 	customOperationLogModel struct {
@@ -27,3 +51,75 @@ func NewOperationLogModel(conn sqlx.SqlConn) OperationLogModel {
 func (m *customOperationLogModel) withSession(session sqlx.Session) OperationLogModel {
 	return NewOperationLogModel(sqlx.NewSqlConnFromSession(session))
 }
+
+// Search 按条件分页查询操作日志，按时间倒序
+func (m *customOperationLogModel) Search(ctx context.Context, filter OperationLogFilter, page, pageSize int) ([]*OperationLog, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	where, args := filter.build()
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", m.table, where)
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY `create_time` DESC LIMIT ? OFFSET ?", operationLogRows, m.table, where)
+	var resp []*OperationLog
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, append(args, pageSize, (page-1)*pageSize)...)
+	return resp, total, err
+}
+
+// FindForExport 按条件查询操作日志用于导出，最多返回 limit 条
+func (m *customOperationLogModel) FindForExport(ctx context.Context, filter OperationLogFilter, limit int) ([]*OperationLog, error) {
+	if limit <= 0 {
+		limit = 10000
+	}
+	where, args := filter.build()
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY `create_time` DESC LIMIT ?", operationLogRows, m.table, where)
+	var resp []*OperationLog
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, append(args, limit)...)
+	return resp, err
+}
+
+// build 生成 WHERE 子句和参数
+func (f OperationLogFilter) build() (string, []interface{}) {
+	conds := []string{"1 = 1"}
+	args := make([]interface{}, 0, 8)
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if f.CompanyId != "" {
+		add("`company_id` = ?", f.CompanyId)
+	}
+	if f.UserId != "" {
+		add("`user_id` = ?", f.UserId)
+	}
+	if f.EmployeeId != "" {
+		add("`employee_id` = ?", f.EmployeeId)
+	}
+	if f.OperationType != "" {
+		add("`operation_type` = ?", f.OperationType)
+	}
+	if f.EntityType != "" {
+		add("`entity_type` = ?", f.EntityType)
+	}
+	if f.EntityId != "" {
+		add("`entity_id` = ?", f.EntityId)
+	}
+	if f.Status != nil {
+		add("`status` = ?", *f.Status)
+	}
+	if !f.StartTime.IsZero() {
+		add("`create_time` >= ?", f.StartTime)
+	}
+	if !f.EndTime.IsZero() {
+		add("`create_time` <= ?", f.EndTime)
+	}
+	return strings.Join(conds, " AND "), args
+}
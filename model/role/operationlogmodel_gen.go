@@ -40,9 +40,12 @@ type (
 		Id            string         `db:"id"`             // 日志id
 		UserId        sql.NullString `db:"user_id"`        // 操作用户id
 		EmployeeId    sql.NullString `db:"employee_id"`    // 操作员工id
+		CompanyId     sql.NullString `db:"company_id"`     // 操作所属公司id
 		OperationType string         `db:"operation_type"` // 操作类型
 		OperationName string         `db:"operation_name"` // 操作名称
 		OperationDesc sql.NullString `db:"operation_desc"` // 操作描述
+		EntityType    sql.NullString `db:"entity_type"`    // 操作实体类型
+		EntityId      sql.NullString `db:"entity_id"`      // 操作实体id
 		RequestMethod sql.NullString `db:"request_method"` // 请求方法
 		RequestUrl    sql.NullString `db:"request_url"`    // 请求URL
 		RequestParams sql.NullString `db:"request_params"` // 请求参数
@@ -84,14 +87,14 @@ func (m *defaultOperationLogModel) FindOne(ctx context.Context, id string) (*Ope
 }
 
 func (m *defaultOperationLogModel) Insert(ctx context.Context, data *OperationLog) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, operationLogRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.UserId, data.EmployeeId, data.OperationType, data.OperationName, data.OperationDesc, data.RequestMethod, data.RequestUrl, data.RequestParams, data.ResponseData, data.IpAddress, data.UserAgent, data.ExecutionTime, data.Status, data.ErrorMessage)
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, operationLogRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.UserId, data.EmployeeId, data.CompanyId, data.OperationType, data.OperationName, data.OperationDesc, data.EntityType, data.EntityId, data.RequestMethod, data.RequestUrl, data.RequestParams, data.ResponseData, data.IpAddress, data.UserAgent, data.ExecutionTime, data.Status, data.ErrorMessage)
 	return ret, err
 }
 
 func (m *defaultOperationLogModel) Update(ctx context.Context, data *OperationLog) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, operationLogRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, data.UserId, data.EmployeeId, data.OperationType, data.OperationName, data.OperationDesc, data.RequestMethod, data.RequestUrl, data.RequestParams, data.ResponseData, data.IpAddress, data.UserAgent, data.ExecutionTime, data.Status, data.ErrorMessage, data.Id)
+	_, err := m.conn.ExecCtx(ctx, query, data.UserId, data.EmployeeId, data.CompanyId, data.OperationType, data.OperationName, data.OperationDesc, data.EntityType, data.EntityId, data.RequestMethod, data.RequestUrl, data.RequestParams, data.ResponseData, data.IpAddress, data.UserAgent, data.ExecutionTime, data.Status, data.ErrorMessage, data.Id)
 	return err
 }
 
@@ -1,15 +1,30 @@
 package role
 
-import "github.com/zeromicro/go-zero/core/stores/sqlx"
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
 
 var _ SystemConfigModel = (*customSystemConfigModel)(nil)
 
+// 配置类型常量
+const (
+	ConfigTypeString = 0 // 字符串
+	ConfigTypeNumber = 1 // 数字
+	ConfigTypeBool   = 2 // 布尔
+	ConfigTypeJSON   = 3 // JSON
+)
+
 type (
 	// SystemConfigModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customSystemConfigModel.
 	SystemConfigModel interface {
 		systemConfigModel
 		withSession(session sqlx.Session) SystemConfigModel
+		FindAll(ctx context.Context) ([]*SystemConfig, error)
+		FindByGroup(ctx context.Context, group string) ([]*SystemConfig, error)
 	}
 
 	customSystemConfigModel struct {
@@ -27,3 +42,19 @@ func NewSystemConfigModel(conn sqlx.SqlConn) SystemConfigModel {
 func (m *customSystemConfigModel) withSession(session sqlx.Session) SystemConfigModel {
 	return NewSystemConfigModel(sqlx.NewSqlConnFromSession(session))
 }
+
+// FindAll 查询全部配置项（包含已禁用的配置）
+func (m *customSystemConfigModel) FindAll(ctx context.Context) ([]*SystemConfig, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY `config_group`, `config_key`", systemConfigRows, m.table)
+	var resp []*SystemConfig
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// FindByGroup 按分组查询配置项
+func (m *customSystemConfigModel) FindByGroup(ctx context.Context, group string) ([]*SystemConfig, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `config_group` = ? ORDER BY `config_key`", systemConfigRows, m.table)
+	var resp []*SystemConfig
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, group)
+	return resp, err
+}
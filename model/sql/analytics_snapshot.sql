@@ -0,0 +1,45 @@
+-- 统计快照：按天预聚合员工 / 部门 / 公司维度的任务指标，仪表盘和平台统计直接读取，不再按请求全表扫描
+-- 每天一行（scope_type + scope_id + snapshot_date 唯一），重复聚合同一天时覆盖更新，可按日期范围回填
+
+CREATE TABLE `stats_daily_snapshot` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `snapshot_date` DATE NOT NULL COMMENT '统计日期',
+    `scope_type` VARCHAR(20) NOT NULL COMMENT '统计维度 employee/department/company',
+    `scope_id` VARCHAR(32) NOT NULL COMMENT '维度ID（员工ID/部门ID/公司ID）',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `created_count` INT NOT NULL DEFAULT 0 COMMENT '当天创建的任务节点数',
+    `completed_count` INT NOT NULL DEFAULT 0 COMMENT '当天完成的任务节点数',
+    `total_count` INT NOT NULL DEFAULT 0 COMMENT '截至当天的任务节点总数',
+    `total_completed_count` INT NOT NULL DEFAULT 0 COMMENT '截至当天已完成的任务节点数',
+    `open_count` INT NOT NULL DEFAULT 0 COMMENT '当天结束时未完成的任务节点数（工作量）',
+    `open_estimated_days` INT NOT NULL DEFAULT 0 COMMENT '未完成任务节点的预计天数之和（工作量）',
+    `overdue_count` INT NOT NULL DEFAULT 0 COMMENT '当天结束时已逾期未完成的任务节点数',
+    `critical_count` INT NOT NULL DEFAULT 0 COMMENT '当天结束时未完成的紧急任务节点数',
+    `deadline_completed_count` INT NOT NULL DEFAULT 0 COMMENT '截至当天完成且有截止时间的任务节点数',
+    `on_time_count` INT NOT NULL DEFAULT 0 COMMENT '其中按时完成的任务节点数',
+    `cycle_hours_total` BIGINT NOT NULL DEFAULT 0 COMMENT '截至当天已完成任务节点的周期（创建到完成）小时数之和',
+    `member_count` INT NOT NULL DEFAULT 0 COMMENT '在职成员数（部门/公司维度）',
+    `active_member_count` INT NOT NULL DEFAULT 0 COMMENT '最近30天有任务更新的成员数（部门/公司维度）',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_stats_scope_date` (`scope_type`, `scope_id`, `snapshot_date`),
+    KEY `idx_stats_company_date` (`company_id`, `snapshot_date`),
+    KEY `idx_stats_date_type` (`snapshot_date`, `scope_type`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='每日统计快照表';
+
+CREATE TABLE `stats_platform_daily` (
+    `snapshot_date` DATE NOT NULL COMMENT '统计日期',
+    `total_companies` INT NOT NULL DEFAULT 0 COMMENT '截至当天的公司总数',
+    `total_users` INT NOT NULL DEFAULT 0 COMMENT '截至当天的用户总数',
+    `total_employees` INT NOT NULL DEFAULT 0 COMMENT '截至当天的员工总数',
+    `total_tasks` INT NOT NULL DEFAULT 0 COMMENT '截至当天的任务总数',
+    `new_companies` INT NOT NULL DEFAULT 0 COMMENT '当天新增公司数',
+    `new_users` INT NOT NULL DEFAULT 0 COMMENT '当天新注册用户数',
+    `new_tasks` INT NOT NULL DEFAULT 0 COMMENT '当天新建任务数',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`snapshot_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='平台每日统计表';
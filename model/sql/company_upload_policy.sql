@@ -0,0 +1,16 @@
+-- =====================================================
+-- 公司上传策略 - 数据库迁移脚本
+-- 公司可以限制允许上传的文件类型（按文件内容识别）和单个文件大小，
+-- 没有配置的公司允许全部可识别的类型，大小上限使用系统设置 upload.max_file_size_mb
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS `company_upload_policy` (
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `allowed_types` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '允许的文件类型，逗号分隔，如 image,pdf,word；为空时允许全部类型',
+    `max_file_size_mb` INT NOT NULL DEFAULT 0 COMMENT '单个文件大小上限（MB），0 表示使用系统设置，不能超过系统设置',
+    `update_by` VARCHAR(32) COMMENT '最后修改人员工ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`company_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='公司上传策略表';
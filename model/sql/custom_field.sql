@@ -0,0 +1,27 @@
+-- 自定义字段：公司为任务或任务节点定义的扩展字段
+CREATE TABLE `custom_field` (
+    `id` VARCHAR(32) NOT NULL COMMENT '字段ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `entity_type` VARCHAR(16) NOT NULL COMMENT '适用对象 task-任务 node-任务节点',
+    `name` VARCHAR(32) NOT NULL COMMENT '字段名称',
+    `field_type` VARCHAR(16) NOT NULL COMMENT '字段类型 text/number/date/select/multi_select/employee',
+    `options` TEXT NULL COMMENT '单选/多选的选项（JSON）',
+    `required` TINYINT NOT NULL DEFAULT 0 COMMENT '是否必填 0-否 1-是',
+    `sort` INT NOT NULL DEFAULT 0 COMMENT '显示顺序',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_custom_field_name` (`company_id`, `entity_type`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='自定义字段表';
+
+-- 自定义字段的值：数字保存为十进制字符串，日期为 YYYY-MM-DD，多选为逗号分隔的选项ID
+CREATE TABLE `custom_field_value` (
+    `field_id` VARCHAR(32) NOT NULL COMMENT '字段ID',
+    `entity_id` VARCHAR(32) NOT NULL COMMENT '任务ID或任务节点ID',
+    `value` VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '字段值',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`field_id`, `entity_id`),
+    KEY `idx_custom_field_value_entity` (`entity_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='自定义字段值表';
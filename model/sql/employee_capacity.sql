@@ -0,0 +1,15 @@
+-- 员工产能：每周可用工时，未配置的员工使用系统设置 capacity.default_weekly_hours
+CREATE TABLE `employee_capacity` (
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '员工ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `weekly_hours` DECIMAL(5,1) NOT NULL DEFAULT 40 COMMENT '每周可用工时（小时）',
+    `update_by` VARCHAR(32) COMMENT '最后修改人员工ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`employee_id`),
+    KEY `idx_employee_capacity_company` (`company_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='员工产能表';
+
+-- 按状态和截止时间查询未完成节点的工作量
+ALTER TABLE `task_node` ADD KEY `idx_task_node_status_deadline` (`node_status`, `node_deadline`);
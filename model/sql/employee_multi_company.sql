@@ -0,0 +1,8 @@
+-- 用户多公司成员身份
+-- 一个用户可在多家公司各持有一条员工记录，唯一约束由 user_id 调整为 (user_id, company_id)
+-- 工作邮箱默认取用户邮箱，跨公司会重复，唯一约束同样收窄到公司内
+ALTER TABLE `employee` ADD UNIQUE KEY `uk_employee_user_company` (`user_id`, `company_id`);
+ALTER TABLE `employee` DROP INDEX `uk_employee_user`;
+ALTER TABLE `employee` ADD UNIQUE KEY `uk_employee_company_email` (`company_id`, `email`);
+ALTER TABLE `employee` DROP INDEX `uk_employee_work_email`;
+CREATE INDEX `idx_employee_user` ON `employee` (`user_id`, `status`);
@@ -0,0 +1,24 @@
+-- 员工外出/休假代理表
+-- 外出期间审批、通知、智能派发候选人均转给代理人，开始时转交待审批事项，结束时向本人发送汇总
+CREATE TABLE `employee_out_of_office` (
+    `id` VARCHAR(32) NOT NULL COMMENT '记录ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '外出员工ID',
+    `delegate_id` VARCHAR(32) NOT NULL COMMENT '代理人员工ID',
+    `start_time` TIMESTAMP NOT NULL COMMENT '开始时间',
+    `end_time` TIMESTAMP NOT NULL COMMENT '结束时间',
+    `reason` VARCHAR(255) COMMENT '外出原因',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-待生效 1-生效中 2-已结束 3-已取消',
+    `routed_items` TEXT COMMENT '代理期间转交给代理人的事项（JSON）',
+    `routed_notifications` INT NOT NULL DEFAULT 0 COMMENT '代理期间转发给代理人的通知数',
+    `create_by` VARCHAR(32) COMMENT '创建人员工ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_ooo_employee_period` (`employee_id`, `status`, `start_time`, `end_time`),
+    KEY `idx_ooo_delegate` (`delegate_id`, `status`),
+    KEY `idx_ooo_company` (`company_id`),
+    KEY `idx_ooo_status_start` (`status`, `start_time`),
+    KEY `idx_ooo_status_end` (`status`, `end_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='员工外出代理表';
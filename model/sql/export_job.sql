@@ -0,0 +1,23 @@
+-- 报表导出任务：异步生成 CSV/XLSX/PDF 文件并写入文件存储，完成后通知发起人下载
+CREATE TABLE `export_job` (
+    `id` VARCHAR(32) NOT NULL COMMENT '导出任务ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '发起人员工ID',
+    `export_type` VARCHAR(32) NOT NULL COMMENT '导出内容 task_list/node_list/employee_list/handover_list/dashboard_stats/task_report',
+    `format` VARCHAR(8) NOT NULL COMMENT '文件格式 csv/xlsx/pdf',
+    `params` TEXT COMMENT '导出条件（JSON）',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-排队中 1-生成中 2-已完成 3-失败',
+    `file_name` VARCHAR(255) COMMENT '文件名',
+    `file_key` VARCHAR(500) COMMENT '文件存储Key',
+    `file_url` VARCHAR(1000) COMMENT '下载地址',
+    `file_size` BIGINT NOT NULL DEFAULT 0 COMMENT '文件大小（字节）',
+    `row_count` INT NOT NULL DEFAULT 0 COMMENT '导出的数据行数',
+    `error_message` VARCHAR(500) COMMENT '失败原因',
+    `finish_time` TIMESTAMP NULL COMMENT '完成时间',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_export_job_employee` (`employee_id`, `create_time`),
+    KEY `idx_export_job_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='报表导出任务表';
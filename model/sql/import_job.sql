@@ -0,0 +1,21 @@
+-- 批量导入任务：上传 CSV/XLSX 后先校验生成报告，确认后分批写入；按批次记录进度，中断后可从断点继续
+CREATE TABLE `import_job` (
+    `id` VARCHAR(32) NOT NULL COMMENT '导入任务ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '发起人员工ID',
+    `import_type` VARCHAR(32) NOT NULL COMMENT '导入内容 department/position/employee/task',
+    `file_name` VARCHAR(255) NOT NULL COMMENT '上传的文件名',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-校验未通过 1-待导入 2-导入中 3-已中断 4-已完成',
+    `total_rows` INT NOT NULL DEFAULT 0 COMMENT '待导入的记录数',
+    `error_count` INT NOT NULL DEFAULT 0 COMMENT '校验错误数',
+    `applied_rows` INT NOT NULL DEFAULT 0 COMMENT '已写入的记录数（断点）',
+    `records` LONGTEXT NOT NULL COMMENT '解析后的记录及校验结果（JSON）',
+    `error_message` VARCHAR(500) COMMENT '中断原因',
+    `finish_time` TIMESTAMP NULL COMMENT '完成时间',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_import_job_company` (`company_id`, `create_time`),
+    KEY `idx_import_job_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='批量导入任务表';
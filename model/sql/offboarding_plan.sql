@@ -0,0 +1,35 @@
+-- 离职交接计划：列出离职员工名下的全部事项并逐项指定接收人，执行后才能确认离职
+CREATE TABLE `offboarding_plan` (
+    `id` VARCHAR(32) NOT NULL COMMENT '计划ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '离职员工ID',
+    `approval_id` VARCHAR(64) NOT NULL COMMENT '离职审批ID（task_handover.handover_id）',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-待执行 1-已执行',
+    `creator_id` VARCHAR(32) NOT NULL COMMENT '生成计划的员工ID',
+    `applied_by` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '执行计划的员工ID',
+    `applied_time` TIMESTAMP NULL COMMENT '执行时间',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_offboarding_plan_approval` (`approval_id`),
+    KEY `idx_offboarding_plan_employee` (`employee_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='离职交接计划表';
+
+-- 离职交接计划事项：每个事项对应离职员工担任的一个角色或拥有的一个对象
+CREATE TABLE `offboarding_plan_item` (
+    `id` VARCHAR(32) NOT NULL COMMENT '事项ID',
+    `plan_id` VARCHAR(32) NOT NULL COMMENT '计划ID',
+    `item_type` VARCHAR(24) NOT NULL COMMENT '事项类型 node_executor/node_leader/task_leader/handover_approval/node_approval/checklist/subordinate',
+    `target_id` VARCHAR(64) NOT NULL COMMENT '节点、任务、交接、审批记录、清单或下属员工ID',
+    `task_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '所属任务ID',
+    `target_name` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '事项名称',
+    `suggested_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '建议的接收人员工ID',
+    `suggest_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '建议理由',
+    `assignee_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '确认的接收人员工ID，为空表示未分配',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_offboarding_item_plan` (`plan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='离职交接计划事项表';
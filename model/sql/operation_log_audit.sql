@@ -0,0 +1,9 @@
+-- 请求审计日志
+-- 审计中间件记录所有变更类接口调用，按公司、实体检索
+ALTER TABLE `operation_log` ADD COLUMN `company_id` VARCHAR(32) COMMENT '操作所属公司id' AFTER `employee_id`;
+ALTER TABLE `operation_log` ADD COLUMN `entity_type` VARCHAR(50) COMMENT '操作实体类型' AFTER `operation_desc`;
+ALTER TABLE `operation_log` ADD COLUMN `entity_id` VARCHAR(64) COMMENT '操作实体id' AFTER `entity_type`;
+
+ALTER TABLE `operation_log` ADD INDEX `idx_log_company_time` (`company_id`, `create_time`);
+ALTER TABLE `operation_log` ADD INDEX `idx_log_entity` (`entity_type`, `entity_id`);
+ALTER TABLE `operation_log` ADD INDEX `idx_log_employee_time` (`employee_id`, `create_time`);
@@ -0,0 +1,27 @@
+-- =====================================================
+-- 存储配额和用量 - 数据库迁移脚本
+-- 平台管理员可以为公司单独设置公司总配额和每个员工的配额，
+-- 没有设置的公司使用系统设置 storage.company_quota_mb / storage.user_quota_mb；
+-- 用量在上传和删除附件时更新，头像不计入用量
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS `company_storage_quota` (
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `company_quota_mb` BIGINT NOT NULL DEFAULT -1 COMMENT '公司总配额（MB），-1 表示使用系统设置，0 表示不限制',
+    `user_quota_mb` BIGINT NOT NULL DEFAULT -1 COMMENT '每个员工的配额（MB），-1 表示使用系统设置，0 表示不限制',
+    `update_by` VARCHAR(32) COMMENT '最后修改的管理员ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`company_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='公司存储配额表';
+
+CREATE TABLE IF NOT EXISTS `storage_usage` (
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '员工ID，为空的记录是公司合计',
+    `used_bytes` BIGINT NOT NULL DEFAULT 0 COMMENT '已使用的存储（字节）',
+    `file_count` BIGINT NOT NULL DEFAULT 0 COMMENT '存储的文件数（附件的每个版本单独计算）',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`company_id`, `employee_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='存储用量表';
@@ -116,6 +116,7 @@ CREATE TABLE `task_handover` (
     `handover_status` TINYINT NOT NULL DEFAULT 0 COMMENT '交接状态 0-待确认 1-已接受 2-已拒绝 3-已完成',
     `handover_reason` TEXT COMMENT '交接原因',
     `handover_note` TEXT COMMENT '交接备注',
+    `transition_note` TEXT COMMENT '交接说明（展示给接收人）',
     `approver_id` VARCHAR(32) COMMENT '审批人员工id',
     `approve_time` TIMESTAMP NULL COMMENT '审批时间',
     `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
@@ -0,0 +1,79 @@
+-- =====================================================
+-- 任务分配关系 - 数据库迁移脚本
+-- 把 task.leader_id / responsible_employee_ids / node_employee_ids 和
+-- task_node.executor_id / leader_id 中逗号分隔的员工ID拆成一人一行，
+-- 用于按员工查询任务和节点；原字段保留用于展示，写入时由模型层同步
+-- =====================================================
+
+-- 任务分配表
+CREATE TABLE IF NOT EXISTS `task_assignment` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '分配ID',
+    `task_id` VARCHAR(32) NOT NULL COMMENT '任务ID',
+    `task_node_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '任务节点ID，任务级角色为空',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '员工ID',
+    `role` VARCHAR(16) NOT NULL COMMENT '角色 task_leader-任务负责人 responsible-任务责任人 node_member-节点员工 executor-节点执行人 node_leader-节点负责人',
+    `assigned_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '分配时间',
+    `assigned_by` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '分配人员工ID，历史数据为空',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_task_assignment` (`task_id`, `task_node_id`, `role`, `employee_id`),
+    KEY `idx_task_assignment_employee` (`employee_id`, `role`),
+    KEY `idx_task_assignment_node` (`task_node_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务分配表';
+
+-- =====================================================
+-- 从现有数据迁移：按逗号拆分ID列表（单个列表最多 100 个ID），已存在的分配保持不变
+-- =====================================================
+INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`)
+SELECT s.`task_id`, '', s.`employee_id`, 'task_leader', s.`assigned_at` FROM (
+    SELECT t.`task_id`, TRIM(t.`leader_id`) AS `employee_id`, COALESCE(t.`create_time`, NOW()) AS `assigned_at`
+    FROM `task` t WHERE t.`delete_time` IS NULL
+) s WHERE s.`employee_id` <> '';
+
+INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`)
+SELECT s.`task_id`, '', s.`employee_id`, 'responsible', s.`assigned_at` FROM (
+    SELECT t.`task_id`, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(t.`responsible_employee_ids`, ',', d.`i`), ',', -1)) AS `employee_id`,
+        COALESCE(t.`create_time`, NOW()) AS `assigned_at`
+    FROM `task` t
+    JOIN (SELECT a.`n` * 10 + b.`n` + 1 AS `i` FROM
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) a,
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) b
+    ) d ON d.`i` <= 1 + LENGTH(t.`responsible_employee_ids`) - LENGTH(REPLACE(t.`responsible_employee_ids`, ',', ''))
+    WHERE t.`delete_time` IS NULL AND t.`responsible_employee_ids` <> ''
+) s WHERE s.`employee_id` <> '';
+
+INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`)
+SELECT s.`task_id`, '', s.`employee_id`, 'node_member', s.`assigned_at` FROM (
+    SELECT t.`task_id`, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(t.`node_employee_ids`, ',', d.`i`), ',', -1)) AS `employee_id`,
+        COALESCE(t.`create_time`, NOW()) AS `assigned_at`
+    FROM `task` t
+    JOIN (SELECT a.`n` * 10 + b.`n` + 1 AS `i` FROM
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) a,
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) b
+    ) d ON d.`i` <= 1 + LENGTH(t.`node_employee_ids`) - LENGTH(REPLACE(t.`node_employee_ids`, ',', ''))
+    WHERE t.`delete_time` IS NULL AND t.`node_employee_ids` <> ''
+) s WHERE s.`employee_id` <> '';
+
+INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`)
+SELECT s.`task_id`, s.`task_node_id`, s.`employee_id`, 'executor', s.`assigned_at` FROM (
+    SELECT n.`task_id`, n.`task_node_id`, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(n.`executor_id`, ',', d.`i`), ',', -1)) AS `employee_id`,
+        COALESCE(n.`create_time`, NOW()) AS `assigned_at`
+    FROM `task_node` n
+    JOIN (SELECT a.`n` * 10 + b.`n` + 1 AS `i` FROM
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) a,
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) b
+    ) d ON d.`i` <= 1 + LENGTH(n.`executor_id`) - LENGTH(REPLACE(n.`executor_id`, ',', ''))
+    WHERE n.`delete_time` IS NULL AND n.`executor_id` <> ''
+) s WHERE s.`employee_id` <> '';
+
+INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`)
+SELECT s.`task_id`, s.`task_node_id`, s.`employee_id`, 'node_leader', s.`assigned_at` FROM (
+    SELECT n.`task_id`, n.`task_node_id`, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(n.`leader_id`, ',', d.`i`), ',', -1)) AS `employee_id`,
+        COALESCE(n.`create_time`, NOW()) AS `assigned_at`
+    FROM `task_node` n
+    JOIN (SELECT a.`n` * 10 + b.`n` + 1 AS `i` FROM
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) a,
+        (SELECT 0 AS `n` UNION ALL SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4 UNION ALL SELECT 5 UNION ALL SELECT 6 UNION ALL SELECT 7 UNION ALL SELECT 8 UNION ALL SELECT 9) b
+    ) d ON d.`i` <= 1 + LENGTH(n.`leader_id`) - LENGTH(REPLACE(n.`leader_id`, ',', ''))
+    WHERE n.`delete_time` IS NULL AND n.`leader_id` <> ''
+) s WHERE s.`employee_id` <> '';
@@ -0,0 +1,42 @@
+-- 看板：按任务或部门展示任务节点，列映射到节点状态，卡片按排序值排列
+CREATE TABLE `task_board` (
+    `id` VARCHAR(32) NOT NULL COMMENT '看板ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `scope_type` VARCHAR(16) NOT NULL COMMENT '范围类型 task-任务 department-部门',
+    `scope_id` VARCHAR(32) NOT NULL COMMENT '任务ID或部门ID',
+    `name` VARCHAR(64) NOT NULL COMMENT '看板名称',
+    `creator_id` VARCHAR(32) NOT NULL COMMENT '创建人员工ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_task_board_scope` (`scope_type`, `scope_id`),
+    KEY `idx_task_board_company` (`company_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='看板表';
+
+-- 看板列：每列对应一个节点状态，多个列可以对应同一状态，wip_limit 为 0 表示不限制
+CREATE TABLE `task_board_column` (
+    `id` VARCHAR(32) NOT NULL COMMENT '列ID',
+    `board_id` VARCHAR(32) NOT NULL COMMENT '看板ID',
+    `name` VARCHAR(32) NOT NULL COMMENT '列名称',
+    `node_status` TINYINT NOT NULL COMMENT '对应的节点状态 0-未开始 1-进行中 2-已完成 3-已逾期',
+    `wip_limit` INT NOT NULL DEFAULT 0 COMMENT '在制品上限，0 表示不限制',
+    `sort` INT NOT NULL DEFAULT 0 COMMENT '列顺序',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_task_board_column_board` (`board_id`, `sort`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='看板列表';
+
+-- 看板卡片：节点所在的列和列内排序值（LexoRank 风格的字符串，按字典序排序）
+CREATE TABLE `task_board_card` (
+    `board_id` VARCHAR(32) NOT NULL COMMENT '看板ID',
+    `task_node_id` VARCHAR(32) NOT NULL COMMENT '任务节点ID',
+    `column_id` VARCHAR(32) NOT NULL COMMENT '列ID',
+    `rank` VARCHAR(64) COLLATE utf8mb4_bin NOT NULL COMMENT '列内排序值',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`board_id`, `task_node_id`),
+    KEY `idx_task_board_card_column` (`board_id`, `column_id`, `rank`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='看板卡片表';
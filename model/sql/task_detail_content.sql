@@ -0,0 +1,17 @@
+-- =====================================================
+-- 任务和节点详情的渲染结果 - 数据库迁移脚本
+-- 任务详情、节点详情的 Markdown 原文仍保存在 task.task_detail 和 task_node.node_detail，
+-- 写入时渲染为净化后的 HTML 和纯文本保存在本表，读取详情时不再重复渲染；
+-- source_hash 为渲染时原文的 SHA-256，原文经导入、模板等途径修改后据此发现并重新渲染
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS `task_detail_content` (
+    `task_id` VARCHAR(32) NOT NULL COMMENT '任务ID',
+    `task_node_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '任务节点ID，任务详情为空',
+    `source_hash` CHAR(64) NOT NULL COMMENT '渲染时原文的 SHA-256',
+    `content_html` MEDIUMTEXT NOT NULL COMMENT '渲染并净化后的 HTML',
+    `content_text` MEDIUMTEXT NOT NULL COMMENT '纯文本，用于搜索和通知',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`task_id`, `task_node_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务详情渲染结果表';
@@ -0,0 +1,39 @@
+-- =====================================================
+-- 节点级/部分交接 - 数据库迁移脚本
+-- 交接可以只包含指定节点的执行人/负责人角色、清单和附件，
+-- 未指定交接事项的交接仍按整个任务交接
+-- =====================================================
+
+-- 交接事项表
+CREATE TABLE IF NOT EXISTS `task_handover_item` (
+    `id` VARCHAR(32) NOT NULL COMMENT '交接事项ID',
+    `handover_id` VARCHAR(32) NOT NULL COMMENT '交接ID',
+    `item_type` VARCHAR(16) NOT NULL COMMENT '事项类型 node_executor-节点执行人 node_leader-节点负责人 checklist-清单 attachment-附件',
+    `task_node_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '所属任务节点ID',
+    `target_id` VARCHAR(64) NOT NULL COMMENT '节点ID、清单ID或附件文件ID',
+    `target_name` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '发起时的节点名称、清单内容或文件名',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-待交接 1-已交接 2-已跳过',
+    `remark` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '跳过原因',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_handover_item_handover` (`handover_id`),
+    KEY `idx_handover_item_target` (`item_type`, `target_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务交接事项表';
+
+-- =====================================================
+-- 扩展任务交接表：添加给接收人的交接说明
+-- =====================================================
+SET @dbname = DATABASE();
+SET @tablename = 'task_handover';
+SET @columnname = 'transition_note';
+SET @preparedStatement = (SELECT IF(
+  (SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS 
+   WHERE TABLE_SCHEMA = @dbname AND TABLE_NAME = @tablename AND COLUMN_NAME = @columnname) > 0,
+  'SELECT 1',
+  'ALTER TABLE `task_handover` ADD COLUMN `transition_note` TEXT COMMENT ''交接说明（展示给接收人）'' AFTER `handover_note`'
+));
+PREPARE alterIfNotExists FROM @preparedStatement;
+EXECUTE alterIfNotExists;
+DEALLOCATE PREPARE alterIfNotExists;
@@ -0,0 +1,23 @@
+-- 任务标签：公司自定义的带颜色标签，一个任务可以有多个标签
+CREATE TABLE `task_label` (
+    `id` VARCHAR(32) NOT NULL COMMENT '标签ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `name` VARCHAR(32) NOT NULL COMMENT '标签名称',
+    `color` VARCHAR(7) NOT NULL DEFAULT '#1677ff' COMMENT '标签颜色（#RRGGBB）',
+    `description` VARCHAR(200) NOT NULL DEFAULT '' COMMENT '说明',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_task_label_name` (`company_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务标签表';
+
+-- 任务与标签的关联
+CREATE TABLE `task_label_relation` (
+    `task_id` VARCHAR(32) NOT NULL COMMENT '任务ID',
+    `label_id` VARCHAR(32) NOT NULL COMMENT '标签ID',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+    PRIMARY KEY (`task_id`, `label_id`),
+    KEY `idx_task_label_relation_label` (`label_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务标签关联表';
@@ -0,0 +1,9 @@
+-- 任务日志结构化事件：记录节点状态和清单完成状态的变更，用于燃尽图 / 燃起图 / 累积流图
+-- 原有日志只有文本内容，新增字段为空的旧日志由节点完成时间、清单完成时间推算
+ALTER TABLE `task_log`
+    ADD COLUMN `event_type` VARCHAR(32) NULL COMMENT '结构化事件 node_status-节点状态变更 checklist_status-清单完成状态变更',
+    ADD COLUMN `checklist_id` VARCHAR(32) NULL COMMENT '清单ID（清单事件）',
+    ADD COLUMN `from_status` TINYINT NULL COMMENT '变更前状态（节点状态或清单是否完成）',
+    ADD COLUMN `to_status` TINYINT NULL COMMENT '变更后状态（节点状态或清单是否完成）';
+
+ALTER TABLE `task_log` ADD KEY `idx_task_log_task_event` (`task_id`, `event_type`, `create_time`);
@@ -0,0 +1,16 @@
+-- 任务视图：保存的任务搜索条件和排序，可设为个人视图或公司内共享
+CREATE TABLE `task_view` (
+    `id` VARCHAR(32) NOT NULL COMMENT '视图ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '创建人员工ID',
+    `name` VARCHAR(64) NOT NULL COMMENT '视图名称',
+    `shared` TINYINT NOT NULL DEFAULT 0 COMMENT '是否共享 0-个人 1-公司内共享',
+    `filter` TEXT NOT NULL COMMENT '搜索条件（JSON）',
+    `sort` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '排序字段（JSON）',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_task_view_employee` (`employee_id`),
+    KEY `idx_task_view_company` (`company_id`, `shared`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务视图表';
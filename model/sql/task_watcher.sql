@@ -0,0 +1,29 @@
+-- =====================================================
+-- 任务关注 - 数据库迁移脚本
+-- 员工可以关注任务或单个节点，关注后接收其变更通知；
+-- 评论、被@提及和被分配到任务或节点时自动关注，主动取消关注后不再自动关注
+-- =====================================================
+
+-- 任务关注表
+CREATE TABLE IF NOT EXISTS `task_watcher` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '关注ID',
+    `task_id` VARCHAR(32) NOT NULL COMMENT '任务ID',
+    `task_node_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '任务节点ID，关注整个任务时为空',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '员工ID',
+    `source` VARCHAR(16) NOT NULL DEFAULT 'manual' COMMENT '来源 manual-主动关注 comment-评论 mention-被@提及 assigned-被分配',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态 0-已取消关注 1-关注中',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_task_watcher` (`task_id`, `task_node_id`, `employee_id`),
+    KEY `idx_task_watcher_employee` (`employee_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务关注表';
+
+-- =====================================================
+-- 从现有分配关系迁移：已分配的员工自动关注对应的任务或节点，已有的关注记录保持不变
+-- =====================================================
+INSERT IGNORE INTO `task_watcher` (`task_id`, `task_node_id`, `employee_id`, `source`, `create_time`)
+SELECT `task_id`, `task_node_id`, `employee_id`, 'assigned', MIN(`assigned_at`)
+FROM `task_assignment`
+GROUP BY `task_id`, `task_node_id`, `employee_id`;
@@ -0,0 +1,50 @@
+-- 工时记录：计时器与手工填报，按任务节点汇总到 task_node / task 的 actual_hours
+-- task_node 原先没有 actual_hours 字段（UpdateActualHours 会失败），在此补齐
+ALTER TABLE `task_node` ADD COLUMN `actual_hours` DECIMAL(10,2) NOT NULL DEFAULT 0 COMMENT '实际工时（小时，由工时记录汇总）' AFTER `actual_days`;
+
+CREATE TABLE `time_entry` (
+    `id` VARCHAR(32) NOT NULL COMMENT '工时记录ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `task_id` VARCHAR(32) NOT NULL COMMENT '任务ID',
+    `task_node_id` VARCHAR(32) NOT NULL COMMENT '任务节点ID',
+    `checklist_id` VARCHAR(32) COMMENT '关联的任务清单ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '填报员工ID',
+    `entry_type` TINYINT NOT NULL DEFAULT 0 COMMENT '来源 0-计时器 1-手工填报',
+    `work_date` DATE NOT NULL COMMENT '工作日期',
+    `start_time` TIMESTAMP NULL COMMENT '计时开始时间',
+    `end_time` TIMESTAMP NULL COMMENT '计时结束时间，计时中为空',
+    `duration_minutes` INT NOT NULL DEFAULT 0 COMMENT '时长（分钟）',
+    `note` VARCHAR(500) COMMENT '备注',
+    `timesheet_id` VARCHAR(32) COMMENT '所属周工时单ID（提交后写入）',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `delete_time` TIMESTAMP NULL COMMENT '删除时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_time_entry_employee_date` (`employee_id`, `work_date`),
+    KEY `idx_time_entry_node` (`task_node_id`),
+    KEY `idx_time_entry_task` (`task_id`),
+    KEY `idx_time_entry_company_date` (`company_id`, `work_date`),
+    KEY `idx_time_entry_timesheet` (`timesheet_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='工时记录表';
+
+-- 周工时单：员工按周提交，由上级（ApproverFinder）审批
+CREATE TABLE `timesheet` (
+    `id` VARCHAR(32) NOT NULL COMMENT '工时单ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '员工ID',
+    `week_start` DATE NOT NULL COMMENT '周一日期',
+    `total_minutes` INT NOT NULL DEFAULT 0 COMMENT '提交时的总时长（分钟）',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态 1-待审批 2-已通过 3-已驳回',
+    `approver_id` VARCHAR(32) COMMENT '审批人员工ID',
+    `approve_note` VARCHAR(500) COMMENT '审批意见',
+    `submit_time` TIMESTAMP NULL COMMENT '提交时间',
+    `approve_time` TIMESTAMP NULL COMMENT '审批时间',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_timesheet_employee_week` (`employee_id`, `week_start`),
+    KEY `idx_timesheet_approver` (`approver_id`, `status`),
+    KEY `idx_timesheet_company_week` (`company_id`, `week_start`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='周工时单表';
@@ -0,0 +1,46 @@
+-- =====================================================
+-- 分片上传和直传会话 - 数据库迁移脚本
+-- 大文件按分片上传（可断点续传），或由客户端通过预签名URL直接上传到文件存储；
+-- 完成后校验整个文件的 SHA-256，检查通过后创建普通的附件记录（MongoDB upload_file）
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS `upload_session` (
+    `id` VARCHAR(32) NOT NULL COMMENT '上传会话ID',
+    `company_id` VARCHAR(32) NOT NULL COMMENT '公司ID',
+    `employee_id` VARCHAR(32) NOT NULL COMMENT '上传人员工ID',
+    `mode` VARCHAR(16) NOT NULL COMMENT '上传方式 chunked-分片上传 direct-预签名直传',
+    `file_name` VARCHAR(255) NOT NULL COMMENT '文件名',
+    `file_size` BIGINT NOT NULL COMMENT '文件大小（字节）',
+    `checksum` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '整个文件的 SHA-256（十六进制），为空时不校验',
+    `part_size` BIGINT NOT NULL DEFAULT 0 COMMENT '分片大小（字节），直传为 0',
+    `part_count` INT NOT NULL DEFAULT 0 COMMENT '分片数，直传为 0',
+    `module` VARCHAR(32) NOT NULL COMMENT '附件模块',
+    `category` VARCHAR(32) NOT NULL COMMENT '附件分类',
+    `related_id` VARCHAR(64) NOT NULL COMMENT '关联ID（任务ID等）',
+    `task_node_id` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '关联的任务节点ID',
+    `description` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '附件描述',
+    `tags` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '附件标签',
+    `storage_key` VARCHAR(500) NOT NULL COMMENT '文件存储Key',
+    `storage_upload_id` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '存储端的分片上传ID',
+    `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态 0-上传中 1-合并中 2-已完成 3-已取消 4-失败',
+    `file_id` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '完成后创建的附件ID',
+    `error_message` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '失败原因',
+    `expire_time` TIMESTAMP NOT NULL COMMENT '过期时间，过期未完成的会话被定时清理',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `update_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    KEY `idx_upload_session_employee` (`employee_id`, `create_time`),
+    KEY `idx_upload_session_status` (`status`, `expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='分片上传和直传会话表';
+
+CREATE TABLE IF NOT EXISTS `upload_session_part` (
+    `session_id` VARCHAR(32) NOT NULL COMMENT '上传会话ID',
+    `part_number` INT NOT NULL COMMENT '分片序号，从 1 开始',
+    `size` BIGINT NOT NULL COMMENT '分片大小（字节）',
+    `md5` VARCHAR(32) NOT NULL COMMENT '分片内容的 MD5（十六进制）',
+    `etag` VARCHAR(128) NOT NULL COMMENT '存储端返回的 ETag',
+    `create_time` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP COMMENT '上传时间',
+
+    PRIMARY KEY (`session_id`, `part_number`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='分片上传已上传的分片表';
@@ -50,9 +50,10 @@ CREATE TABLE `employee` (
     `delete_time` TIMESTAMP NULL COMMENT '删除时间',
     
     PRIMARY KEY (`id`),
-    UNIQUE KEY `uk_employee_user` (`user_id`),
+    UNIQUE KEY `uk_employee_user_company` (`user_id`, `company_id`),
     UNIQUE KEY `uk_employee_id` (`employee_id`),
-    UNIQUE KEY `uk_employee_work_email` (`email`),
+    UNIQUE KEY `uk_employee_company_email` (`company_id`, `email`),
+    KEY `idx_employee_user` (`user_id`, `status`),
     KEY `idx_employee_company` (`company_id`),
     KEY `idx_employee_department` (`department_id`),
     KEY `idx_employee_position` (`position_id`),
@@ -6,8 +6,8 @@ CREATE TABLE `user_permission` (
     `permission_code` VARCHAR(50) NOT NULL COMMENT '权限编码',
     `permission_name` VARCHAR(100) NOT NULL COMMENT '权限名称',
     `resource_type` TINYINT NOT NULL COMMENT '资源类型 0-菜单 1-按钮 2-接口 3-数据',
-    `resource_id` VARCHAR(32) COMMENT '资源id',
-    `grant_type` TINYINT NOT NULL DEFAULT 0 COMMENT '授权类型 0-直接授权 1-角色授权 2-部门授权',
+    `resource_id` VARCHAR(32) COMMENT '资源id（直接授权时为所属公司id）',
+    `grant_type` TINYINT NOT NULL DEFAULT 0 COMMENT '授权类型 0-直接授权 1-角色授权 2-部门授权 3-临时委派',
     `grant_by` VARCHAR(32) COMMENT '授权人id',
     `expire_time` TIMESTAMP NULL COMMENT '过期时间',
     `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态 0-禁用 1-正常',
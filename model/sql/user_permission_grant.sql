@@ -0,0 +1,14 @@
+-- 用户直接授权 / 临时委派
+-- user_permission.resource_id 存放授权所属的公司ID，grant_type 新增 3-临时委派
+
+ALTER TABLE `user_permission`
+    MODIFY COLUMN `resource_id` VARCHAR(32) COMMENT '资源id（直接授权时为所属公司id）';
+ALTER TABLE `user_permission`
+    MODIFY COLUMN `grant_type` TINYINT NOT NULL DEFAULT 0 COMMENT '授权类型 0-直接授权 1-角色授权 2-部门授权 3-临时委派';
+
+-- 鉴权中间件按 用户+公司+状态 查询生效授权，定时任务按过期时间回收
+ALTER TABLE `user_permission` ADD INDEX `idx_permission_user_company` (`user_id`, `resource_id`, `status`);
+ALTER TABLE `user_permission` ADD INDEX `idx_permission_expire` (`status`, `expire_time`);
+
+-- 授权审计按操作类型和时间查询
+ALTER TABLE `operation_log` ADD INDEX `idx_log_type_time` (`operation_type`, `create_time`);
@@ -0,0 +1,178 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 自定义字段适用的对象
+const (
+	CustomFieldEntityTask = "task" // 任务
+	CustomFieldEntityNode = "node" // 任务节点
+)
+
+// 自定义字段类型
+const (
+	CustomFieldText        = "text"         // 文本
+	CustomFieldNumber      = "number"       // 数字
+	CustomFieldDate        = "date"         // 日期
+	CustomFieldSelect      = "select"       // 单选
+	CustomFieldMultiSelect = "multi_select" // 多选
+	CustomFieldEmployee    = "employee"     // 员工
+)
+
+// CustomField 公司为任务或任务节点定义的扩展字段
+type CustomField struct {
+	Id         string         `db:"id"`          // 字段ID
+	CompanyId  string         `db:"company_id"`  // 公司ID
+	EntityType string         `db:"entity_type"` // 适用对象 task/node
+	Name       string         `db:"name"`        // 字段名称
+	FieldType  string         `db:"field_type"`  // 字段类型
+	Options    sql.NullString `db:"options"`     // 单选/多选的选项（JSON）
+	Required   int64          `db:"required"`    // 是否必填 0-否 1-是
+	Sort       int64          `db:"sort"`        // 显示顺序
+	CreateTime time.Time      `db:"create_time"` // 创建时间
+	UpdateTime time.Time      `db:"update_time"` // 更新时间
+}
+
+// CustomFieldValue 自定义字段的值
+type CustomFieldValue struct {
+	FieldId    string    `db:"field_id"`    // 字段ID
+	EntityId   string    `db:"entity_id"`   // 任务ID或任务节点ID
+	Value      string    `db:"value"`       // 字段值
+	UpdateTime time.Time `db:"update_time"` // 更新时间
+}
+
+const customFieldRows = "`id`, `company_id`, `entity_type`, `name`, `field_type`, `options`, `required`, `sort`, `create_time`, `update_time`"
+
+type CustomFieldModel interface {
+	Insert(ctx context.Context, data *CustomField) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*CustomField, error)
+	FindByName(ctx context.Context, companyId, entityType, name string) (*CustomField, error)
+	Update(ctx context.Context, data *CustomField) error
+	// Delete 删除字段及其全部值
+	Delete(ctx context.Context, id string) error
+	// FindByCompany 公司的字段，entityType 为空时返回全部，按显示顺序排序
+	FindByCompany(ctx context.Context, companyId, entityType string) ([]*CustomField, error)
+	CountByCompany(ctx context.Context, companyId string) (int64, error)
+	// SetValues 写入对象的字段值，值为空字符串时删除
+	SetValues(ctx context.Context, entityId string, values map[string]string) error
+	// FindValues 批量查询对象的字段值
+	FindValues(ctx context.Context, entityIds []string) ([]*CustomFieldValue, error)
+}
+
+type defaultCustomFieldModel struct {
+	conn       sqlx.SqlConn
+	table      string
+	valueTable string
+}
+
+func NewCustomFieldModel(conn sqlx.SqlConn) CustomFieldModel {
+	return &defaultCustomFieldModel{
+		conn:       conn,
+		table:      "`custom_field`",
+		valueTable: "`custom_field_value`",
+	}
+}
+
+func (m *defaultCustomFieldModel) Insert(ctx context.Context, data *CustomField) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, customFieldRows)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EntityType, data.Name, data.FieldType, data.Options,
+		data.Required, data.Sort, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultCustomFieldModel) FindOne(ctx context.Context, id string) (*CustomField, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", customFieldRows, m.table)
+	var resp CustomField
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultCustomFieldModel) FindByName(ctx context.Context, companyId, entityType, name string) (*CustomField, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND `entity_type` = ? AND `name` = ? LIMIT 1", customFieldRows, m.table)
+	var resp CustomField
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId, entityType, name)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultCustomFieldModel) Update(ctx context.Context, data *CustomField) error {
+	query := fmt.Sprintf("UPDATE %s SET `name` = ?, `options` = ?, `required` = ?, `sort` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Name, data.Options, data.Required, data.Sort, time.Now(), data.Id)
+	return err
+}
+
+func (m *defaultCustomFieldModel) Delete(ctx context.Context, id string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `field_id` = ?", m.valueTable), id); err != nil {
+			return err
+		}
+		_, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table), id)
+		return err
+	})
+}
+
+func (m *defaultCustomFieldModel) FindByCompany(ctx context.Context, companyId, entityType string) ([]*CustomField, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ?", customFieldRows, m.table)
+	args := []interface{}{companyId}
+	if entityType != "" {
+		query += " AND `entity_type` = ?"
+		args = append(args, entityType)
+	}
+	query += " ORDER BY `sort` ASC, `create_time` ASC"
+	var resp []*CustomField
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+func (m *defaultCustomFieldModel) CountByCompany(ctx context.Context, companyId string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `company_id` = ?", m.table)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, companyId)
+	return count, err
+}
+
+func (m *defaultCustomFieldModel) SetValues(ctx context.Context, entityId string, values map[string]string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for fieldId, value := range values {
+			var err error
+			if value == "" {
+				_, err = session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `field_id` = ? AND `entity_id` = ?", m.valueTable), fieldId, entityId)
+			} else {
+				_, err = session.ExecCtx(ctx, fmt.Sprintf("INSERT INTO %s (`field_id`, `entity_id`, `value`, `update_time`) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `value` = VALUES(`value`), `update_time` = VALUES(`update_time`)", m.valueTable),
+					fieldId, entityId, value, time.Now())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *defaultCustomFieldModel) FindValues(ctx context.Context, entityIds []string) ([]*CustomFieldValue, error) {
+	if len(entityIds) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("SELECT `field_id`, `entity_id`, `value`, `update_time` FROM %s WHERE `entity_id` IN (%s)", m.valueTable, placeholders(len(entityIds)))
+	var resp []*CustomFieldValue
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, appendStrings(nil, entityIds)...)
+	return resp, err
+}
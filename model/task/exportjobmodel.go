@@ -0,0 +1,134 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// ExportJob 报表导出任务
+type ExportJob struct {
+	Id           string         `db:"id"`            // 导出任务ID
+	CompanyId    string         `db:"company_id"`    // 公司ID
+	EmployeeId   string         `db:"employee_id"`   // 发起人员工ID
+	ExportType   string         `db:"export_type"`   // 导出内容
+	Format       string         `db:"format"`        // 文件格式 csv/xlsx/pdf
+	Params       sql.NullString `db:"params"`        // 导出条件（JSON）
+	Status       int64          `db:"status"`        // 状态 0-排队中 1-生成中 2-已完成 3-失败
+	FileName     sql.NullString `db:"file_name"`     // 文件名
+	FileKey      sql.NullString `db:"file_key"`      // 文件存储Key
+	FileUrl      sql.NullString `db:"file_url"`      // 下载地址
+	FileSize     int64          `db:"file_size"`     // 文件大小（字节）
+	RowCount     int64          `db:"row_count"`     // 导出的数据行数
+	ErrorMessage sql.NullString `db:"error_message"` // 失败原因
+	FinishTime   sql.NullTime   `db:"finish_time"`   // 完成时间
+	CreateTime   time.Time      `db:"create_time"`   // 创建时间
+	UpdateTime   time.Time      `db:"update_time"`   // 更新时间
+}
+
+// 导出任务状态
+const (
+	ExportStatusPending = 0 // 排队中
+	ExportStatusRunning = 1 // 生成中
+	ExportStatusDone    = 2 // 已完成
+	ExportStatusFailed  = 3 // 失败
+)
+
+const exportJobRows = "`id`, `company_id`, `employee_id`, `export_type`, `format`, `params`, `status`, `file_name`, `file_key`, `file_url`, `file_size`, `row_count`, `error_message`, `finish_time`, `create_time`, `update_time`"
+
+type ExportJobModel interface {
+	Insert(ctx context.Context, data *ExportJob) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*ExportJob, error)
+	UpdateStatus(ctx context.Context, id string, status int64) error
+	Finish(ctx context.Context, data *ExportJob) error
+	FindByEmployee(ctx context.Context, employeeId string, page, pageSize int) ([]*ExportJob, int64, error)
+	FindFinishedBefore(ctx context.Context, before time.Time, limit int) ([]*ExportJob, error)
+	FailUnfinished(ctx context.Context, message string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type defaultExportJobModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewExportJobModel(conn sqlx.SqlConn) ExportJobModel {
+	return &defaultExportJobModel{
+		conn:  conn,
+		table: "`export_job`",
+	}
+}
+
+func (m *defaultExportJobModel) Insert(ctx context.Context, data *ExportJob) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `employee_id`, `export_type`, `format`, `params`, `status`, `create_time`, `update_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.ExportType, data.Format, data.Params, data.Status, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultExportJobModel) FindOne(ctx context.Context, id string) (*ExportJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", exportJobRows, m.table)
+	var resp ExportJob
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// UpdateStatus 更新导出任务状态
+func (m *defaultExportJobModel) UpdateStatus(ctx context.Context, id string, status int64) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, time.Now(), id)
+	return err
+}
+
+// Finish 写入导出结果（成功时的文件信息或失败原因）
+func (m *defaultExportJobModel) Finish(ctx context.Context, data *ExportJob) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `file_name` = ?, `file_key` = ?, `file_url` = ?, `file_size` = ?, `row_count` = ?, `error_message` = ?, `finish_time` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Status, data.FileName, data.FileKey, data.FileUrl, data.FileSize, data.RowCount, data.ErrorMessage, data.FinishTime, time.Now(), data.Id)
+	return err
+}
+
+// FindByEmployee 分页查询员工发起的导出任务，按创建时间倒序
+func (m *defaultExportJobModel) FindByEmployee(ctx context.Context, employeeId string, page, pageSize int) ([]*ExportJob, int64, error) {
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `employee_id` = ?", m.table)
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, employeeId); err != nil {
+		return nil, 0, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? ORDER BY `create_time` DESC LIMIT ? OFFSET ?", exportJobRows, m.table)
+	var resp []*ExportJob
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, employeeId, pageSize, (page-1)*pageSize)
+	return resp, total, err
+}
+
+// FindFinishedBefore 查询在指定时间之前结束的导出任务（用于清理过期文件）
+func (m *defaultExportJobModel) FindFinishedBefore(ctx context.Context, before time.Time, limit int) ([]*ExportJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `status` IN (?, ?) AND `create_time` < ? ORDER BY `create_time` ASC LIMIT ?", exportJobRows, m.table)
+	var resp []*ExportJob
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, ExportStatusDone, ExportStatusFailed, before, limit)
+	return resp, err
+}
+
+// FailUnfinished 将排队中和生成中的导出任务标记为失败（服务重启后这些任务不会再继续执行）
+func (m *defaultExportJobModel) FailUnfinished(ctx context.Context, message string) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `error_message` = ?, `finish_time` = ?, `update_time` = ? WHERE `status` IN (?, ?)", m.table)
+	now := time.Now()
+	result, err := m.conn.ExecCtx(ctx, query, ExportStatusFailed, message, now, now, ExportStatusPending, ExportStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (m *defaultExportJobModel) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
@@ -0,0 +1,155 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// ImportJob 批量导入任务
+type ImportJob struct {
+	Id           string         `db:"id"`            // 导入任务ID
+	CompanyId    string         `db:"company_id"`    // 公司ID
+	EmployeeId   string         `db:"employee_id"`   // 发起人员工ID
+	ImportType   string         `db:"import_type"`   // 导入内容
+	FileName     string         `db:"file_name"`     // 上传的文件名
+	Status       int64          `db:"status"`        // 状态 0-校验未通过 1-待导入 2-导入中 3-已中断 4-已完成
+	TotalRows    int64          `db:"total_rows"`    // 待导入的记录数
+	ErrorCount   int64          `db:"error_count"`   // 校验错误数
+	AppliedRows  int64          `db:"applied_rows"`  // 已写入的记录数（断点）
+	Records      string         `db:"records"`       // 解析后的记录及校验结果（JSON）
+	ErrorMessage sql.NullString `db:"error_message"` // 中断原因
+	FinishTime   sql.NullTime   `db:"finish_time"`   // 完成时间
+	CreateTime   time.Time      `db:"create_time"`   // 创建时间
+	UpdateTime   time.Time      `db:"update_time"`   // 更新时间
+}
+
+// 导入任务状态
+const (
+	ImportStatusInvalid     = 0 // 校验未通过
+	ImportStatusReady       = 1 // 待导入
+	ImportStatusRunning     = 2 // 导入中
+	ImportStatusInterrupted = 3 // 已中断，可继续导入
+	ImportStatusDone        = 4 // 已完成
+)
+
+const importJobRows = "`id`, `company_id`, `employee_id`, `import_type`, `file_name`, `status`, `total_rows`, `error_count`, `applied_rows`, `records`, `error_message`, `finish_time`, `create_time`, `update_time`"
+
+// importJobSummaryRows 列表查询不读取记录内容
+const importJobSummaryRows = "`id`, `company_id`, `employee_id`, `import_type`, `file_name`, `status`, `total_rows`, `error_count`, `applied_rows`, '' AS `records`, `error_message`, `finish_time`, `create_time`, `update_time`"
+
+type ImportJobModel interface {
+	Insert(ctx context.Context, data *ImportJob) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*ImportJob, error)
+	UpdateRecords(ctx context.Context, id string, status, totalRows, errorCount int64, records string) error
+	MarkRunning(ctx context.Context, id string) (bool, error)
+	UpdateProgress(ctx context.Context, id string, appliedRows int64) error
+	Finish(ctx context.Context, id string, status int64, errorMessage string) error
+	FindByCompany(ctx context.Context, companyId, employeeId string, page, pageSize int) ([]*ImportJob, int64, error)
+	InterruptRunning(ctx context.Context, message string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type defaultImportJobModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewImportJobModel(conn sqlx.SqlConn) ImportJobModel {
+	return &defaultImportJobModel{
+		conn:  conn,
+		table: "`import_job`",
+	}
+}
+
+func (m *defaultImportJobModel) Insert(ctx context.Context, data *ImportJob) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `employee_id`, `import_type`, `file_name`, `status`, `total_rows`, `error_count`, `applied_rows`, `records`, `create_time`, `update_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.ImportType, data.FileName, data.Status, data.TotalRows, data.ErrorCount, data.AppliedRows, data.Records, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultImportJobModel) FindOne(ctx context.Context, id string) (*ImportJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", importJobRows, m.table)
+	var resp ImportJob
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// UpdateRecords 写入重新校验后的记录和状态
+func (m *defaultImportJobModel) UpdateRecords(ctx context.Context, id string, status, totalRows, errorCount int64, records string) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `total_rows` = ?, `error_count` = ?, `records` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, totalRows, errorCount, records, time.Now(), id)
+	return err
+}
+
+// MarkRunning 将待导入或已中断的任务标记为导入中，返回 false 表示任务已被其他请求启动
+func (m *defaultImportJobModel) MarkRunning(ctx context.Context, id string) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `error_message` = NULL, `update_time` = ? WHERE `id` = ? AND `status` IN (?, ?)", m.table)
+	result, err := m.conn.ExecCtx(ctx, query, ImportStatusRunning, time.Now(), id, ImportStatusReady, ImportStatusInterrupted)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// UpdateProgress 更新断点，与该批次的数据写入在同一事务中执行
+func (m *defaultImportJobModel) UpdateProgress(ctx context.Context, id string, appliedRows int64) error {
+	query := fmt.Sprintf("UPDATE %s SET `applied_rows` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, appliedRows, time.Now(), id)
+	return err
+}
+
+// Finish 写入导入结果（完成或中断原因）
+func (m *defaultImportJobModel) Finish(ctx context.Context, id string, status int64, errorMessage string) error {
+	now := time.Now()
+	finishTime := sql.NullTime{Time: now, Valid: status == ImportStatusDone}
+	message := sql.NullString{String: errorMessage, Valid: errorMessage != ""}
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `error_message` = ?, `finish_time` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, message, finishTime, now, id)
+	return err
+}
+
+// FindByCompany 分页查询公司的导入任务，employeeId 不为空时只查询该员工发起的，按创建时间倒序
+func (m *defaultImportJobModel) FindByCompany(ctx context.Context, companyId, employeeId string, page, pageSize int) ([]*ImportJob, int64, error) {
+	where := "`company_id` = ?"
+	args := []interface{}{companyId}
+	if employeeId != "" {
+		where += " AND `employee_id` = ?"
+		args = append(args, employeeId)
+	}
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", m.table, where)
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY `create_time` DESC LIMIT ? OFFSET ?", importJobSummaryRows, m.table, where)
+	var resp []*ImportJob
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, append(args, pageSize, (page-1)*pageSize)...)
+	return resp, total, err
+}
+
+// InterruptRunning 将导入中的任务标记为已中断（服务重启后可由发起人继续导入）
+func (m *defaultImportJobModel) InterruptRunning(ctx context.Context, message string) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `error_message` = ?, `update_time` = ? WHERE `status` = ?", m.table)
+	result, err := m.conn.ExecCtx(ctx, query, ImportStatusInterrupted, message, time.Now(), ImportStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (m *defaultImportJobModel) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
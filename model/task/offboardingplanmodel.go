@@ -0,0 +1,357 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 离职交接事项类型
+const (
+	OffboardingNodeExecutor     = "node_executor"     // 未完成节点的执行人
+	OffboardingNodeLeader       = "node_leader"       // 未完成节点的负责人
+	OffboardingTaskLeader       = "task_leader"       // 未完成任务的负责人
+	OffboardingHandoverApproval = "handover_approval" // 待审批的任务交接
+	OffboardingNodeApproval     = "node_approval"     // 待审批的节点完成审批
+	OffboardingChecklist        = "checklist"         // 未完成的清单
+	OffboardingSubordinate      = "subordinate"       // 直属下属
+)
+
+// 离职交接计划状态
+const (
+	OffboardingPlanDraft   = 0 // 待执行
+	OffboardingPlanApplied = 1 // 已执行
+)
+
+// ErrOffboardingPlanApplied 计划已被执行（并发执行时后提交的一方）
+var ErrOffboardingPlanApplied = errors.New("offboarding plan already applied")
+
+// OffboardingPlan 离职交接计划
+type OffboardingPlan struct {
+	Id          string       `db:"id"`           // 计划ID
+	CompanyId   string       `db:"company_id"`   // 公司ID
+	EmployeeId  string       `db:"employee_id"`  // 离职员工ID
+	ApprovalId  string       `db:"approval_id"`  // 离职审批ID
+	Status      int64        `db:"status"`       // 状态 0-待执行 1-已执行
+	CreatorId   string       `db:"creator_id"`   // 生成计划的员工ID
+	AppliedBy   string       `db:"applied_by"`   // 执行计划的员工ID
+	AppliedTime sql.NullTime `db:"applied_time"` // 执行时间
+	CreateTime  time.Time    `db:"create_time"`  // 创建时间
+	UpdateTime  time.Time    `db:"update_time"`  // 更新时间
+}
+
+// OffboardingPlanItem 离职交接计划事项
+type OffboardingPlanItem struct {
+	Id            string    `db:"id"`             // 事项ID
+	PlanId        string    `db:"plan_id"`        // 计划ID
+	ItemType      string    `db:"item_type"`      // 事项类型
+	TargetId      string    `db:"target_id"`      // 节点、任务、交接、审批记录、清单或下属员工ID
+	TaskId        string    `db:"task_id"`        // 所属任务ID
+	TargetName    string    `db:"target_name"`    // 事项名称
+	SuggestedId   string    `db:"suggested_id"`   // 建议的接收人
+	SuggestReason string    `db:"suggest_reason"` // 建议理由
+	AssigneeId    string    `db:"assignee_id"`    // 确认的接收人，为空表示未分配
+	CreateTime    time.Time `db:"create_time"`    // 创建时间
+	UpdateTime    time.Time `db:"update_time"`    // 更新时间
+}
+
+const (
+	offboardingPlanRows     = "`id`, `company_id`, `employee_id`, `approval_id`, `status`, `creator_id`, `applied_by`, `applied_time`, `create_time`, `update_time`"
+	offboardingPlanItemRows = "`id`, `plan_id`, `item_type`, `target_id`, `task_id`, `target_name`, `suggested_id`, `suggest_reason`, `assignee_id`, `create_time`, `update_time`"
+)
+
+type OffboardingPlanModel interface {
+	// Insert 创建计划及其事项
+	Insert(ctx context.Context, plan *OffboardingPlan, items []*OffboardingPlanItem) error
+	FindOne(ctx context.Context, id string) (*OffboardingPlan, error)
+	FindByApprovalId(ctx context.Context, approvalId string) (*OffboardingPlan, error)
+	// FindItems 计划的全部事项，按事项类型和写入顺序排序
+	FindItems(ctx context.Context, planId string) ([]*OffboardingPlanItem, error)
+	// ReplaceItems 重新生成计划的事项，计划回到待执行状态
+	ReplaceItems(ctx context.Context, planId string, items []*OffboardingPlanItem) error
+	// UpdateAssignees 批量设置事项的接收人，assignees 为 事项ID -> 接收人
+	UpdateAssignees(ctx context.Context, planId string, assignees map[string]string) error
+	// Apply 在一个事务中把全部事项转给各自的接收人并将计划标记为已执行。
+	// 事项的归属在生成计划后已变化时跳过该事项；names 为接收人ID -> 姓名，用于更新审批记录中的审批人姓名。
+	// 计划已不是待执行状态时返回 ErrOffboardingPlanApplied
+	Apply(ctx context.Context, plan *OffboardingPlan, items []*OffboardingPlanItem, operatorId string, names map[string]string) error
+}
+
+type defaultOffboardingPlanModel struct {
+	conn      sqlx.SqlConn
+	table     string
+	itemTable string
+}
+
+func NewOffboardingPlanModel(conn sqlx.SqlConn) OffboardingPlanModel {
+	return &defaultOffboardingPlanModel{
+		conn:      conn,
+		table:     "`offboarding_plan`",
+		itemTable: "`offboarding_plan_item`",
+	}
+}
+
+func (m *defaultOffboardingPlanModel) Insert(ctx context.Context, plan *OffboardingPlan, items []*OffboardingPlanItem) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, offboardingPlanRows)
+		if _, err := session.ExecCtx(ctx, query, plan.Id, plan.CompanyId, plan.EmployeeId, plan.ApprovalId, plan.Status,
+			plan.CreatorId, plan.AppliedBy, plan.AppliedTime, plan.CreateTime, plan.UpdateTime); err != nil {
+			return err
+		}
+		return m.insertItems(ctx, session, items)
+	})
+}
+
+func (m *defaultOffboardingPlanModel) insertItems(ctx context.Context, session sqlx.Session, items []*OffboardingPlanItem) error {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.itemTable, offboardingPlanItemRows)
+	for _, it := range items {
+		if _, err := session.ExecCtx(ctx, query, it.Id, it.PlanId, it.ItemType, it.TargetId, it.TaskId, it.TargetName,
+			it.SuggestedId, it.SuggestReason, it.AssigneeId, it.CreateTime, it.UpdateTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *defaultOffboardingPlanModel) FindOne(ctx context.Context, id string) (*OffboardingPlan, error) {
+	return m.findPlan(ctx, "`id`", id)
+}
+
+func (m *defaultOffboardingPlanModel) FindByApprovalId(ctx context.Context, approvalId string) (*OffboardingPlan, error) {
+	return m.findPlan(ctx, "`approval_id`", approvalId)
+}
+
+func (m *defaultOffboardingPlanModel) findPlan(ctx context.Context, column, value string) (*OffboardingPlan, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? LIMIT 1", offboardingPlanRows, m.table, column)
+	var resp OffboardingPlan
+	err := m.conn.QueryRowCtx(ctx, &resp, query, value)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultOffboardingPlanModel) FindItems(ctx context.Context, planId string) ([]*OffboardingPlanItem, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `plan_id` = ? ORDER BY `item_type` ASC, `create_time` ASC, `id` ASC", offboardingPlanItemRows, m.itemTable)
+	var resp []*OffboardingPlanItem
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, planId)
+	return resp, err
+}
+
+func (m *defaultOffboardingPlanModel) ReplaceItems(ctx context.Context, planId string, items []*OffboardingPlanItem) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `plan_id` = ?", m.itemTable), planId); err != nil {
+			return err
+		}
+		if err := m.insertItems(ctx, session, items); err != nil {
+			return err
+		}
+		query := fmt.Sprintf("UPDATE %s SET `status` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+		_, err := session.ExecCtx(ctx, query, OffboardingPlanDraft, time.Now(), planId)
+		return err
+	})
+}
+
+func (m *defaultOffboardingPlanModel) UpdateAssignees(ctx context.Context, planId string, assignees map[string]string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		now := time.Now()
+		query := fmt.Sprintf("UPDATE %s SET `assignee_id` = ?, `update_time` = ? WHERE `id` = ? AND `plan_id` = ?", m.itemTable)
+		for id, assignee := range assignees {
+			if _, err := session.ExecCtx(ctx, query, assignee, now, id, planId); err != nil {
+				return err
+			}
+		}
+		_, err := session.ExecCtx(ctx, fmt.Sprintf("UPDATE %s SET `update_time` = ? WHERE `id` = ?", m.table), now, planId)
+		return err
+	})
+}
+
+func (m *defaultOffboardingPlanModel) Apply(ctx context.Context, plan *OffboardingPlan, items []*OffboardingPlanItem, operatorId string, names map[string]string) error {
+	from := plan.EmployeeId
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		// 先把计划标记为已执行，只有待执行的计划能更新成功，并发执行同一计划时后到的一方回滚
+		query := fmt.Sprintf("UPDATE %s SET `status` = ?, `applied_by` = ?, `applied_time` = ?, `update_time` = ? WHERE `id` = ? AND `status` = ?", m.table)
+		now := time.Now()
+		ret, err := session.ExecCtx(ctx, query, OffboardingPlanApplied, operatorId, now, now, plan.Id, OffboardingPlanDraft)
+		if err != nil {
+			return err
+		}
+		if rows, err := ret.RowsAffected(); err != nil {
+			return err
+		} else if rows == 0 {
+			return ErrOffboardingPlanApplied
+		}
+
+		// 节点角色变化后需要同步任务的节点员工列表：任务ID -> 新加入的接收人
+		nodeAssignees := make(map[string][]string)
+		for _, it := range items {
+			to := it.AssigneeId
+			switch it.ItemType {
+			case OffboardingNodeExecutor, OffboardingNodeLeader:
+				column, role := "`executor_id`", AssignmentExecutor
+				if it.ItemType == OffboardingNodeLeader {
+					column, role = "`leader_id`", AssignmentNodeLeader
+				}
+				updated, changed, err := replaceInColumn(ctx, session, "`task_node`", column, "`task_node_id`", it.TargetId, from, to)
+				if err != nil {
+					return err
+				}
+				if changed {
+					if err := syncAssignments(ctx, session, it.TaskId, it.TargetId, role, updated); err != nil {
+						return err
+					}
+					nodeAssignees[it.TaskId] = append(nodeAssignees[it.TaskId], to)
+				}
+			case OffboardingTaskLeader:
+				query := "UPDATE `task` SET `leader_id` = ?, `update_time` = NOW() WHERE `task_id` = ? AND `leader_id` = ?"
+				ret, err := session.ExecCtx(ctx, query, to, it.TargetId, from)
+				if err != nil {
+					return err
+				}
+				if rows, _ := ret.RowsAffected(); rows > 0 {
+					if err := syncAssignments(ctx, session, it.TargetId, "", AssignmentTaskLeader, to); err != nil {
+						return err
+					}
+				}
+				updated, changed, err := replaceInColumn(ctx, session, "`task`", "`responsible_employee_ids`", "`task_id`", it.TargetId, from, to)
+				if err != nil {
+					return err
+				}
+				if changed {
+					if err := syncAssignments(ctx, session, it.TargetId, "", AssignmentResponsible, updated); err != nil {
+						return err
+					}
+				}
+			case OffboardingHandoverApproval:
+				query := "UPDATE `task_handover` SET `approver_id` = ?, `update_time` = NOW() WHERE `handover_id` = ? AND `approver_id` = ? AND `handover_status` IN (0, 1)"
+				if _, err := session.ExecCtx(ctx, query, to, it.TargetId, from); err != nil {
+					return err
+				}
+			case OffboardingNodeApproval:
+				query := "UPDATE `handover_approval` SET `approver_id` = ?, `approver_name` = ?, `update_time` = NOW() WHERE `approval_id` = ? AND `approver_id` = ? AND `approval_type` = 0"
+				if _, err := session.ExecCtx(ctx, query, to, names[to], it.TargetId, from); err != nil {
+					return err
+				}
+			case OffboardingChecklist:
+				query := "UPDATE `task_checklist` SET `creator_id` = ? WHERE `checklist_id` = ? AND `creator_id` = ? AND `delete_time` IS NULL"
+				if _, err := session.ExecCtx(ctx, query, to, it.TargetId, from); err != nil {
+					return err
+				}
+			case OffboardingSubordinate:
+				query := "UPDATE `employee` SET `supervisor_id` = ?, `update_time` = NOW() WHERE `id` = ? AND `supervisor_id` = ?"
+				if _, err := session.ExecCtx(ctx, query, to, it.TargetId, from); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown offboarding item type: %s", it.ItemType)
+			}
+		}
+
+		for taskId, assignees := range nodeAssignees {
+			if err := syncNodeEmployees(ctx, session, taskId, from, assignees); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replaceInColumn 在逗号分隔的员工ID列中把 from 替换为 to（to 已在列表中时只移除 from），返回新的列表和是否有变化
+func replaceInColumn(ctx context.Context, session sqlx.Session, table, column, keyColumn, key, from, to string) (string, bool, error) {
+	var current sql.NullString
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? FOR UPDATE", column, table, keyColumn)
+	if err := session.QueryRowCtx(ctx, &current, query, key); err != nil {
+		if err == sqlx.ErrNotFound {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	updated, changed := replaceID(current.String, from, to)
+	if !changed {
+		return current.String, false, nil
+	}
+	query = fmt.Sprintf("UPDATE %s SET %s = ?, `update_time` = NOW() WHERE %s = ?", table, column, keyColumn)
+	_, err := session.ExecCtx(ctx, query, updated, key)
+	return updated, err == nil, err
+}
+
+// syncNodeEmployees 节点角色转移后同步任务的节点员工列表：加入接收人，
+// 离职员工不再担任该任务任何未删除节点的执行人或负责人时移除（部分事项未转移时仍保留）
+func syncNodeEmployees(ctx context.Context, session sqlx.Session, taskId, from string, assignees []string) error {
+	var current sql.NullString
+	if err := session.QueryRowCtx(ctx, &current, "SELECT `node_employee_ids` FROM `task` WHERE `task_id` = ? FOR UPDATE", taskId); err != nil {
+		if err == sqlx.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	var nodes []struct {
+		ExecutorId string `db:"executor_id"`
+		LeaderId   string `db:"leader_id"`
+	}
+	if err := session.QueryRowsCtx(ctx, &nodes, "SELECT `executor_id`, `leader_id` FROM `task_node` WHERE `task_id` = ? AND `delete_time` IS NULL", taskId); err != nil {
+		return err
+	}
+	fromInvolved := false
+	for _, n := range nodes {
+		if n.LeaderId == from {
+			fromInvolved = true
+		}
+		for _, id := range strings.Split(n.ExecutorId, ",") {
+			if strings.TrimSpace(id) == from {
+				fromInvolved = true
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range append(strings.Split(current.String, ","), assignees...) {
+		id = strings.TrimSpace(id)
+		if id == "" || (id == from && !fromInvolved) || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	updated := strings.Join(ids, ",")
+	if updated == current.String {
+		return nil
+	}
+	if _, err := session.ExecCtx(ctx, "UPDATE `task` SET `node_employee_ids` = ?, `update_time` = NOW() WHERE `task_id` = ?", updated, taskId); err != nil {
+		return err
+	}
+	return syncAssignments(ctx, session, taskId, "", AssignmentNodeMember, updated)
+}
+
+// replaceID 在逗号分隔的ID列表中把 from 替换为 to，保持顺序并去重
+func replaceID(list, from, to string) (string, bool) {
+	var ids []string
+	seen := make(map[string]bool)
+	changed := false
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if id == from {
+			id = to
+			changed = true
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ","), changed
+}
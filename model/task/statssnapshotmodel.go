@@ -0,0 +1,174 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 统计快照维度
+const (
+	StatsScopeEmployee   = "employee"
+	StatsScopeDepartment = "department"
+	StatsScopeCompany    = "company"
+)
+
+// StatsSnapshot 某个维度某一天的任务统计快照
+type StatsSnapshot struct {
+	SnapshotDate           time.Time `db:"snapshot_date"`            // 统计日期
+	ScopeType              string    `db:"scope_type"`               // 维度 employee/department/company
+	ScopeId                string    `db:"scope_id"`                 // 维度ID
+	CompanyId              string    `db:"company_id"`               // 公司ID
+	CreatedCount           int64     `db:"created_count"`            // 当天创建数
+	CompletedCount         int64     `db:"completed_count"`          // 当天完成数
+	TotalCount             int64     `db:"total_count"`              // 截至当天的总数
+	TotalCompletedCount    int64     `db:"total_completed_count"`    // 截至当天的完成数
+	OpenCount              int64     `db:"open_count"`               // 未完成数
+	OpenEstimatedDays      int64     `db:"open_estimated_days"`      // 未完成节点预计天数之和
+	OverdueCount           int64     `db:"overdue_count"`            // 逾期未完成数
+	CriticalCount          int64     `db:"critical_count"`           // 紧急未完成数
+	DeadlineCompletedCount int64     `db:"deadline_completed_count"` // 有截止时间的完成数
+	OnTimeCount            int64     `db:"on_time_count"`            // 按时完成数
+	CycleHoursTotal        int64     `db:"cycle_hours_total"`        // 完成周期小时数之和
+	MemberCount            int64     `db:"member_count"`             // 在职成员数
+	ActiveMemberCount      int64     `db:"active_member_count"`      // 活跃成员数
+	UpdateTime             time.Time `db:"update_time"`              // 最近聚合时间
+}
+
+// OnTimeRate 按时完成率（百分比）
+func (s *StatsSnapshot) OnTimeRate() int64 {
+	if s.DeadlineCompletedCount == 0 {
+		return 0
+	}
+	return s.OnTimeCount * 100 / s.DeadlineCompletedCount
+}
+
+// AvgCycleDays 平均完成天数
+func (s *StatsSnapshot) AvgCycleDays() int64 {
+	if s.TotalCompletedCount == 0 {
+		return 0
+	}
+	return s.CycleHoursTotal / s.TotalCompletedCount / 24
+}
+
+// StatsNodeFact 聚合用的任务节点字段投影
+type StatsNodeFact struct {
+	TaskNodeId    string       `db:"task_node_id"`
+	DepartmentId  string       `db:"department_id"`
+	ExecutorId    string       `db:"executor_id"`
+	LeaderId      string       `db:"leader_id"`
+	NodeStatus    int64        `db:"node_status"`
+	NodePriority  int64        `db:"node_priority"`
+	EstimatedDays int64        `db:"estimated_days"`
+	NodeDeadline  time.Time    `db:"node_deadline"`
+	FinishTime    sql.NullTime `db:"finish_time"` // 已完成节点的完成时间（无完成时间时取更新时间）
+	CreateTime    time.Time    `db:"create_time"`
+	UpdateTime    time.Time    `db:"update_time"`
+}
+
+const statsSnapshotRows = "`snapshot_date`, `scope_type`, `scope_id`, `company_id`, `created_count`, `completed_count`, `total_count`, `total_completed_count`, `open_count`, `open_estimated_days`, `overdue_count`, `critical_count`, `deadline_completed_count`, `on_time_count`, `cycle_hours_total`, `member_count`, `active_member_count`, `update_time`"
+
+// 单条 INSERT 写入的快照行数
+const statsUpsertBatch = 200
+
+type StatsSnapshotModel interface {
+	Upsert(ctx context.Context, rows []*StatsSnapshot) error
+	FindOne(ctx context.Context, scopeType, scopeId string, date time.Time) (*StatsSnapshot, error)
+	FindRange(ctx context.Context, scopeType, scopeId string, start, end time.Time) ([]*StatsSnapshot, error)
+	FindTopCompanies(ctx context.Context, date time.Time, limit int) ([]*StatsSnapshot, error)
+	ListNodeFacts(ctx context.Context, companyId string) ([]*StatsNodeFact, error)
+}
+
+type defaultStatsSnapshotModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewStatsSnapshotModel(conn sqlx.SqlConn) StatsSnapshotModel {
+	return &defaultStatsSnapshotModel{
+		conn:  conn,
+		table: "`stats_daily_snapshot`",
+	}
+}
+
+// Upsert 批量写入快照，同一维度同一天已存在时覆盖
+func (m *defaultStatsSnapshotModel) Upsert(ctx context.Context, rows []*StatsSnapshot) error {
+	for start := 0; start < len(rows); start += statsUpsertBatch {
+		end := start + statsUpsertBatch
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batch := rows[start:end]
+
+		placeholders := make([]string, 0, len(batch))
+		args := make([]interface{}, 0, len(batch)*17)
+		for _, r := range batch {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			args = append(args, r.SnapshotDate.Format("2006-01-02"), r.ScopeType, r.ScopeId, r.CompanyId,
+				r.CreatedCount, r.CompletedCount, r.TotalCount, r.TotalCompletedCount, r.OpenCount, r.OpenEstimatedDays,
+				r.OverdueCount, r.CriticalCount, r.DeadlineCompletedCount, r.OnTimeCount, r.CycleHoursTotal,
+				r.MemberCount, r.ActiveMemberCount)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (`snapshot_date`, `scope_type`, `scope_id`, `company_id`, `created_count`, `completed_count`, `total_count`, `total_completed_count`, `open_count`, `open_estimated_days`, `overdue_count`, `critical_count`, `deadline_completed_count`, `on_time_count`, `cycle_hours_total`, `member_count`, `active_member_count`) VALUES %s "+
+			"ON DUPLICATE KEY UPDATE `company_id` = VALUES(`company_id`), `created_count` = VALUES(`created_count`), `completed_count` = VALUES(`completed_count`), "+
+			"`total_count` = VALUES(`total_count`), `total_completed_count` = VALUES(`total_completed_count`), `open_count` = VALUES(`open_count`), "+
+			"`open_estimated_days` = VALUES(`open_estimated_days`), `overdue_count` = VALUES(`overdue_count`), `critical_count` = VALUES(`critical_count`), "+
+			"`deadline_completed_count` = VALUES(`deadline_completed_count`), `on_time_count` = VALUES(`on_time_count`), `cycle_hours_total` = VALUES(`cycle_hours_total`), "+
+			"`member_count` = VALUES(`member_count`), `active_member_count` = VALUES(`active_member_count`), `update_time` = NOW()",
+			m.table, strings.Join(placeholders, ", "))
+		if _, err := m.conn.ExecCtx(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindOne 查询某个维度某一天的快照
+func (m *defaultStatsSnapshotModel) FindOne(ctx context.Context, scopeType, scopeId string, date time.Time) (*StatsSnapshot, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `scope_type` = ? AND `scope_id` = ? AND `snapshot_date` = ? LIMIT 1", statsSnapshotRows, m.table)
+	var resp StatsSnapshot
+	err := m.conn.QueryRowCtx(ctx, &resp, query, scopeType, scopeId, date.Format("2006-01-02"))
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindRange 查询某个维度在日期范围内的快照，按日期升序
+func (m *defaultStatsSnapshotModel) FindRange(ctx context.Context, scopeType, scopeId string, start, end time.Time) ([]*StatsSnapshot, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `scope_type` = ? AND `scope_id` = ? AND `snapshot_date` >= ? AND `snapshot_date` <= ? ORDER BY `snapshot_date` ASC", statsSnapshotRows, m.table)
+	var resp []*StatsSnapshot
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, scopeType, scopeId, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	return resp, err
+}
+
+// FindTopCompanies 查询某一天成员数最多的公司快照
+func (m *defaultStatsSnapshotModel) FindTopCompanies(ctx context.Context, date time.Time, limit int) ([]*StatsSnapshot, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `snapshot_date` = ? AND `scope_type` = ? ORDER BY `member_count` DESC LIMIT ?", statsSnapshotRows, m.table)
+	var resp []*StatsSnapshot
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, date.Format("2006-01-02"), StatsScopeCompany, limit)
+	return resp, err
+}
+
+// ListNodeFacts 查询公司全部未删除任务节点的统计字段（只取聚合需要的列），执行人和负责人取自分配表
+func (m *defaultStatsSnapshotModel) ListNodeFacts(ctx context.Context, companyId string) ([]*StatsNodeFact, error) {
+	query := "SELECT n.`task_node_id`, n.`department_id`, " +
+		"COALESCE((SELECT GROUP_CONCAT(a.`employee_id`) FROM `task_assignment` a WHERE a.`task_node_id` = n.`task_node_id` AND a.`role` = 'executor'), '') AS `executor_id`, " +
+		"COALESCE((SELECT GROUP_CONCAT(a.`employee_id`) FROM `task_assignment` a WHERE a.`task_node_id` = n.`task_node_id` AND a.`role` = 'node_leader'), '') AS `leader_id`, " +
+		"n.`node_status`, n.`node_priority`, n.`estimated_days`, n.`node_deadline`, " +
+		"CASE WHEN n.`node_status` = 2 THEN COALESCE(n.`node_finish_time`, n.`update_time`) END AS `finish_time`, " +
+		"COALESCE(n.`create_time`, n.`node_start_time`) AS `create_time`, COALESCE(n.`update_time`, n.`create_time`, n.`node_start_time`) AS `update_time` " +
+		"FROM `task_node` n JOIN `task` t ON t.`task_id` = n.`task_id` " +
+		"WHERE t.`company_id` = ? AND t.`delete_time` IS NULL AND n.`delete_time` IS NULL"
+	var resp []*StatsNodeFact
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId)
+	return resp, err
+}
@@ -0,0 +1,204 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 任务分配角色
+const (
+	AssignmentTaskLeader  = "task_leader" // 任务负责人 task.leader_id
+	AssignmentResponsible = "responsible" // 任务责任人 task.responsible_employee_ids
+	AssignmentNodeMember  = "node_member" // 节点员工 task.node_employee_ids
+	AssignmentExecutor    = "executor"    // 节点执行人 task_node.executor_id
+	AssignmentNodeLeader  = "node_leader" // 节点负责人 task_node.leader_id
+)
+
+// InvolvedAssignmentRoles 视为"参与任务"的分配角色
+var InvolvedAssignmentRoles = []string{AssignmentTaskLeader, AssignmentResponsible, AssignmentExecutor, AssignmentNodeLeader}
+
+// TaskAssignment 任务分配关系，一名员工在任务或节点上的一个角色对应一行。
+// 任务和节点表中逗号分隔的员工ID字段保留用于展示，写入时由任务和节点模型同步到本表
+type TaskAssignment struct {
+	Id         int64     `db:"id"`           // 分配ID
+	TaskId     string    `db:"task_id"`      // 任务ID
+	TaskNodeId string    `db:"task_node_id"` // 任务节点ID，任务级角色为空
+	EmployeeId string    `db:"employee_id"`  // 员工ID
+	Role       string    `db:"role"`         // 角色
+	AssignedAt time.Time `db:"assigned_at"`  // 分配时间
+	AssignedBy string    `db:"assigned_by"`  // 分配人员工ID
+}
+
+const taskAssignmentRows = "`id`, `task_id`, `task_node_id`, `employee_id`, `role`, `assigned_at`, `assigned_by`"
+
+type TaskAssignmentModel interface {
+	// FindByTask 任务及其全部节点的分配关系
+	FindByTask(ctx context.Context, taskId string) ([]*TaskAssignment, error)
+	// FindByNode 节点的分配关系
+	FindByNode(ctx context.Context, taskNodeId string) ([]*TaskAssignment, error)
+	// FindByEmployee 员工在未删除任务上的分配关系，roles 为空时返回全部角色
+	FindByEmployee(ctx context.Context, employeeId string, roles ...string) ([]*TaskAssignment, error)
+	// FindTaskIdsByEmployee 员工以指定角色参与的未删除任务ID
+	FindTaskIdsByEmployee(ctx context.Context, employeeId string, roles ...string) ([]string, error)
+	// HasRole 员工是否在任务（含其节点）上担任指定角色之一
+	HasRole(ctx context.Context, taskId, employeeId string, roles ...string) (bool, error)
+}
+
+type defaultTaskAssignmentModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTaskAssignmentModel(conn sqlx.SqlConn) TaskAssignmentModel {
+	return &defaultTaskAssignmentModel{
+		conn:  conn,
+		table: "`task_assignment`",
+	}
+}
+
+func (m *defaultTaskAssignmentModel) FindByTask(ctx context.Context, taskId string) ([]*TaskAssignment, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_id` = ? ORDER BY `task_node_id` ASC, `role` ASC, `id` ASC", taskAssignmentRows, m.table)
+	var resp []*TaskAssignment
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, taskId)
+	return resp, err
+}
+
+func (m *defaultTaskAssignmentModel) FindByNode(ctx context.Context, taskNodeId string) ([]*TaskAssignment, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_node_id` = ? ORDER BY `role` ASC, `id` ASC", taskAssignmentRows, m.table)
+	var resp []*TaskAssignment
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, taskNodeId)
+	return resp, err
+}
+
+func (m *defaultTaskAssignmentModel) FindByEmployee(ctx context.Context, employeeId string, roles ...string) ([]*TaskAssignment, error) {
+	where, args := employeeRoleWhere(employeeId, roles)
+	query := fmt.Sprintf("SELECT a.`id`, a.`task_id`, a.`task_node_id`, a.`employee_id`, a.`role`, a.`assigned_at`, a.`assigned_by` "+
+		"FROM %s a JOIN `task` t ON t.`task_id` = a.`task_id` WHERE %s AND t.`delete_time` IS NULL ORDER BY a.`assigned_at` DESC, a.`id` DESC", m.table, where)
+	var resp []*TaskAssignment
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+func (m *defaultTaskAssignmentModel) FindTaskIdsByEmployee(ctx context.Context, employeeId string, roles ...string) ([]string, error) {
+	where, args := employeeRoleWhere(employeeId, roles)
+	query := fmt.Sprintf("SELECT DISTINCT a.`task_id` FROM %s a JOIN `task` t ON t.`task_id` = a.`task_id` WHERE %s AND t.`delete_time` IS NULL", m.table, where)
+	var resp []string
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+func (m *defaultTaskAssignmentModel) HasRole(ctx context.Context, taskId, employeeId string, roles ...string) (bool, error) {
+	where, args := employeeRoleWhere(employeeId, roles)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s a WHERE a.`task_id` = ? AND %s", m.table, where)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, append([]interface{}{taskId}, args...)...)
+	return count > 0, err
+}
+
+// employeeRoleWhere 按员工和角色过滤的条件（分配表别名为 a）
+func employeeRoleWhere(employeeId string, roles []string) (string, []interface{}) {
+	args := []interface{}{employeeId}
+	if len(roles) == 0 {
+		return "a.`employee_id` = ?", args
+	}
+	return "a.`employee_id` = ? AND a.`role` IN (" + placeholders(len(roles)) + ")", appendStrings(args, roles)
+}
+
+// assignmentSubquery 按员工和角色查询节点ID的子查询，参数为员工ID
+func assignmentSubquery(role string) string {
+	return "SELECT `task_node_id` FROM `task_assignment` WHERE `employee_id` = ? AND `role` = '" + role + "'"
+}
+
+// involvedTaskSubquery 员工参与的任务ID子查询，参数为员工ID
+func involvedTaskSubquery() string {
+	return "SELECT `task_id` FROM `task_assignment` WHERE `employee_id` = ? AND `role` IN ('" +
+		strings.Join(InvolvedAssignmentRoles, "', '") + "')"
+}
+
+// splitEmployeeIDs 拆分逗号分隔的员工ID列表，去除空白和重复
+func splitEmployeeIDs(list string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// assignerFromContext 当前操作的员工ID，由登录中间件写入上下文，后台任务中为空
+func assignerFromContext(ctx context.Context) string {
+	employeeID, _ := ctx.Value("employeeId").(string)
+	return employeeID
+}
+
+// transactAssignments 在同一事务中写入任务或节点并同步分配关系；conn 已是事务会话（NewSqlConnFromSession 创建，
+// 无法获取底层连接）时直接在该会话中执行，由外层事务统一提交
+func transactAssignments(ctx context.Context, conn sqlx.SqlConn, fn func(ctx context.Context, session sqlx.Session) error) error {
+	if _, err := conn.RawDB(); err != nil {
+		return fn(ctx, conn)
+	}
+	return conn.TransactCtx(ctx, fn)
+}
+
+// syncAssignments 把任务或节点某个角色的员工ID列表同步到分配表：移除不在列表中的员工，
+// 新增列表中的员工，已有的分配保留原分配时间和分配人
+func syncAssignments(ctx context.Context, session sqlx.Session, taskId, taskNodeId, role, idList string) error {
+	ids := splitEmployeeIDs(idList)
+	query := "DELETE FROM `task_assignment` WHERE `task_id` = ? AND `task_node_id` = ? AND `role` = ?"
+	args := []interface{}{taskId, taskNodeId, role}
+	if len(ids) > 0 {
+		query += " AND `employee_id` NOT IN (" + placeholders(len(ids)) + ")"
+		args = appendStrings(args, ids)
+	}
+	if _, err := session.ExecCtx(ctx, query, args...); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	assigner := assignerFromContext(ctx)
+	values := make([]string, 0, len(ids))
+	args = make([]interface{}, 0, len(ids)*5)
+	for _, id := range ids {
+		values = append(values, "(?, ?, ?, ?, ?)")
+		args = append(args, taskId, taskNodeId, id, role, assigner)
+	}
+	query = "INSERT IGNORE INTO `task_assignment` (`task_id`, `task_node_id`, `employee_id`, `role`, `assigned_by`) VALUES " + strings.Join(values, ", ")
+	_, err := session.ExecCtx(ctx, query, args...)
+	return err
+}
+
+// syncTaskAssignments 同步任务级角色：负责人、责任人和节点员工
+func syncTaskAssignments(ctx context.Context, session sqlx.Session, data *Task) error {
+	if err := syncAssignments(ctx, session, data.TaskId, "", AssignmentTaskLeader, data.LeaderId.String); err != nil {
+		return err
+	}
+	if err := syncAssignments(ctx, session, data.TaskId, "", AssignmentResponsible, data.ResponsibleEmployeeIds.String); err != nil {
+		return err
+	}
+	return syncAssignments(ctx, session, data.TaskId, "", AssignmentNodeMember, data.NodeEmployeeIds.String)
+}
+
+// syncNodeAssignments 同步节点角色：执行人和负责人
+func syncNodeAssignments(ctx context.Context, session sqlx.Session, data *TaskNode) error {
+	if err := syncAssignments(ctx, session, data.TaskId, data.TaskNodeId, AssignmentExecutor, data.ExecutorId); err != nil {
+		return err
+	}
+	return syncAssignments(ctx, session, data.TaskId, data.TaskNodeId, AssignmentNodeLeader, data.LeaderId)
+}
+
+// deleteNodeAssignments 删除节点的全部分配关系
+func deleteNodeAssignments(ctx context.Context, session sqlx.Session, taskNodeId string) error {
+	_, err := session.ExecCtx(ctx, "DELETE FROM `task_assignment` WHERE `task_node_id` = ?", taskNodeId)
+	return err
+}
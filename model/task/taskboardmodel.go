@@ -0,0 +1,246 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 看板范围
+const (
+	BoardScopeTask       = "task"       // 任务看板：展示任务的全部节点
+	BoardScopeDepartment = "department" // 部门看板：展示部门负责的节点
+)
+
+// TaskBoard 看板
+type TaskBoard struct {
+	Id         string    `db:"id"`          // 看板ID
+	CompanyId  string    `db:"company_id"`  // 公司ID
+	ScopeType  string    `db:"scope_type"`  // 范围类型 task/department
+	ScopeId    string    `db:"scope_id"`    // 任务ID或部门ID
+	Name       string    `db:"name"`        // 看板名称
+	CreatorId  string    `db:"creator_id"`  // 创建人员工ID
+	CreateTime time.Time `db:"create_time"` // 创建时间
+	UpdateTime time.Time `db:"update_time"` // 更新时间
+}
+
+// TaskBoardColumn 看板列，映射到一个节点状态
+type TaskBoardColumn struct {
+	Id         string    `db:"id"`          // 列ID
+	BoardId    string    `db:"board_id"`    // 看板ID
+	Name       string    `db:"name"`        // 列名称
+	NodeStatus int64     `db:"node_status"` // 对应的节点状态
+	WipLimit   int64     `db:"wip_limit"`   // 在制品上限，0 表示不限制
+	Sort       int64     `db:"sort"`        // 列顺序
+	CreateTime time.Time `db:"create_time"` // 创建时间
+	UpdateTime time.Time `db:"update_time"` // 更新时间
+}
+
+// TaskBoardCard 节点在看板中的位置
+type TaskBoardCard struct {
+	BoardId    string    `db:"board_id"`     // 看板ID
+	TaskNodeId string    `db:"task_node_id"` // 任务节点ID
+	ColumnId   string    `db:"column_id"`    // 列ID
+	Rank       string    `db:"rank"`         // 列内排序值
+	UpdateTime time.Time `db:"update_time"`  // 更新时间
+}
+
+const (
+	taskBoardRows       = "`id`, `company_id`, `scope_type`, `scope_id`, `name`, `creator_id`, `create_time`, `update_time`"
+	taskBoardColumnRows = "`id`, `board_id`, `name`, `node_status`, `wip_limit`, `sort`, `create_time`, `update_time`"
+	taskBoardCardRows   = "`board_id`, `task_node_id`, `column_id`, `rank`, `update_time`"
+)
+
+type TaskBoardModel interface {
+	// Insert 创建看板及其列
+	Insert(ctx context.Context, board *TaskBoard, columns []*TaskBoardColumn) error
+	FindOne(ctx context.Context, id string) (*TaskBoard, error)
+	// LockOne 查询看板并加行锁，需在事务会话中使用，用于串行化同一看板的卡片移动
+	LockOne(ctx context.Context, id string) (*TaskBoard, error)
+	FindByScope(ctx context.Context, scopeType, scopeId string) (*TaskBoard, error)
+	// Update 更新看板名称，columns 不为 nil 时替换全部列：保留已有ID的列，删除未保留的列及其卡片位置
+	Update(ctx context.Context, board *TaskBoard, columns []*TaskBoardColumn) error
+	// Delete 删除看板、列和卡片位置
+	Delete(ctx context.Context, id string) error
+	// FindColumns 看板的列，按列顺序排序
+	FindColumns(ctx context.Context, boardId string) ([]*TaskBoardColumn, error)
+	// FindCards 看板中已记录位置的卡片，按列和排序值排序
+	FindCards(ctx context.Context, boardId string) ([]*TaskBoardCard, error)
+	// SaveCards 写入卡片位置（存在时覆盖）
+	SaveCards(ctx context.Context, cards []*TaskBoardCard) error
+}
+
+type defaultTaskBoardModel struct {
+	conn        sqlx.SqlConn
+	table       string
+	columnTable string
+	cardTable   string
+}
+
+func NewTaskBoardModel(conn sqlx.SqlConn) TaskBoardModel {
+	return &defaultTaskBoardModel{
+		conn:        conn,
+		table:       "`task_board`",
+		columnTable: "`task_board_column`",
+		cardTable:   "`task_board_card`",
+	}
+}
+
+func (m *defaultTaskBoardModel) Insert(ctx context.Context, board *TaskBoard, columns []*TaskBoardColumn) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", m.table, taskBoardRows)
+		if _, err := session.ExecCtx(ctx, query, board.Id, board.CompanyId, board.ScopeType, board.ScopeId, board.Name,
+			board.CreatorId, board.CreateTime, board.UpdateTime); err != nil {
+			return err
+		}
+		return m.insertColumns(ctx, session, columns)
+	})
+}
+
+func (m *defaultTaskBoardModel) insertColumns(ctx context.Context, session sqlx.Session, columns []*TaskBoardColumn) error {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", m.columnTable, taskBoardColumnRows)
+	for _, c := range columns {
+		if _, err := session.ExecCtx(ctx, query, c.Id, c.BoardId, c.Name, c.NodeStatus, c.WipLimit, c.Sort, c.CreateTime, c.UpdateTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *defaultTaskBoardModel) FindOne(ctx context.Context, id string) (*TaskBoard, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", taskBoardRows, m.table)
+	var resp TaskBoard
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskBoardModel) LockOne(ctx context.Context, id string) (*TaskBoard, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1 FOR UPDATE", taskBoardRows, m.table)
+	var resp TaskBoard
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskBoardModel) FindByScope(ctx context.Context, scopeType, scopeId string) (*TaskBoard, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `scope_type` = ? AND `scope_id` = ? LIMIT 1", taskBoardRows, m.table)
+	var resp TaskBoard
+	err := m.conn.QueryRowCtx(ctx, &resp, query, scopeType, scopeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskBoardModel) Update(ctx context.Context, board *TaskBoard, columns []*TaskBoardColumn) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := fmt.Sprintf("UPDATE %s SET `name` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+		if _, err := session.ExecCtx(ctx, query, board.Name, time.Now(), board.Id); err != nil {
+			return err
+		}
+		if columns == nil {
+			return nil
+		}
+
+		var existing []string
+		if err := session.QueryRowsCtx(ctx, &existing, fmt.Sprintf("SELECT `id` FROM %s WHERE `board_id` = ?", m.columnTable), board.Id); err != nil {
+			return err
+		}
+		kept := make(map[string]bool, len(columns))
+		for _, c := range columns {
+			kept[c.Id] = true
+		}
+		for _, id := range existing {
+			if kept[id] {
+				continue
+			}
+			// 删除列后其中的节点回到对应状态的第一列
+			if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `board_id` = ? AND `column_id` = ?", m.cardTable), board.Id, id); err != nil {
+				return err
+			}
+			if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.columnTable), id); err != nil {
+				return err
+			}
+		}
+
+		isExisting := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			isExisting[id] = true
+		}
+		var added []*TaskBoardColumn
+		update := fmt.Sprintf("UPDATE %s SET `name` = ?, `node_status` = ?, `wip_limit` = ?, `sort` = ?, `update_time` = ? WHERE `id` = ?", m.columnTable)
+		for _, c := range columns {
+			if !isExisting[c.Id] {
+				added = append(added, c)
+				continue
+			}
+			if _, err := session.ExecCtx(ctx, update, c.Name, c.NodeStatus, c.WipLimit, c.Sort, time.Now(), c.Id); err != nil {
+				return err
+			}
+		}
+		return m.insertColumns(ctx, session, added)
+	})
+}
+
+func (m *defaultTaskBoardModel) Delete(ctx context.Context, id string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, table := range []string{m.cardTable, m.columnTable} {
+			if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `board_id` = ?", table), id); err != nil {
+				return err
+			}
+		}
+		_, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table), id)
+		return err
+	})
+}
+
+func (m *defaultTaskBoardModel) FindColumns(ctx context.Context, boardId string) ([]*TaskBoardColumn, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `board_id` = ? ORDER BY `sort` ASC, `create_time` ASC", taskBoardColumnRows, m.columnTable)
+	var resp []*TaskBoardColumn
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, boardId)
+	return resp, err
+}
+
+func (m *defaultTaskBoardModel) FindCards(ctx context.Context, boardId string) ([]*TaskBoardCard, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `board_id` = ? ORDER BY `column_id` ASC, `rank` ASC", taskBoardCardRows, m.cardTable)
+	var resp []*TaskBoardCard
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, boardId)
+	return resp, err
+}
+
+func (m *defaultTaskBoardModel) SaveCards(ctx context.Context, cards []*TaskBoardCard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(cards))
+	args := make([]interface{}, 0, len(cards)*5)
+	now := time.Now()
+	for _, c := range cards {
+		values = append(values, "(?, ?, ?, ?, ?)")
+		args = append(args, c.BoardId, c.TaskNodeId, c.ColumnId, c.Rank, now)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE `column_id` = VALUES(`column_id`), `rank` = VALUES(`rank`), `update_time` = VALUES(`update_time`)",
+		m.cardTable, taskBoardCardRows, strings.Join(values, ", "))
+	_, err := m.conn.ExecCtx(ctx, query, args...)
+	return err
+}
@@ -17,6 +17,8 @@ type (
 		taskChecklistModel
 		// 根据任务节点ID查询所有清单（不含已删除）
 		FindByTaskNodeId(ctx context.Context, taskNodeId string) ([]*TaskChecklist, error)
+		// 根据任务ID查询所有未删除节点的清单（不含已删除）
+		FindByTaskId(ctx context.Context, taskId string) ([]*TaskChecklist, error)
 		// 根据任务节点ID和创建者ID查询清单（不含已删除）
 		FindByTaskNodeIdAndCreator(ctx context.Context, taskNodeId, creatorId string) ([]*TaskChecklist, error)
 		// 统计任务节点的清单数量
@@ -57,6 +59,15 @@ func (m *customTaskChecklistModel) FindByTaskNodeId(ctx context.Context, taskNod
 	return resp, err
 }
 
+// FindByTaskId 查询任务下所有未删除节点的清单（不含已删除）
+func (m *customTaskChecklistModel) FindByTaskId(ctx context.Context, taskId string) ([]*TaskChecklist, error) {
+	query := fmt.Sprintf("select c.`checklist_id`, c.`task_node_id`, c.`creator_id`, c.`content`, c.`is_completed`, c.`complete_time`, c.`sort_order`, c.`create_time`, c.`update_time`, c.`delete_time` "+
+		"from %s c join `task_node` n on n.`task_node_id` = c.`task_node_id` where n.`task_id` = ? and n.`delete_time` is null and c.`delete_time` is null order by c.`create_time` asc", m.table)
+	var resp []*TaskChecklist
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, taskId)
+	return resp, err
+}
+
 // FindByTaskNodeIdAndCreator 根据任务节点ID和创建者ID查询清单（不含已删除）
 func (m *customTaskChecklistModel) FindByTaskNodeIdAndCreator(ctx context.Context, taskNodeId, creatorId string) ([]*TaskChecklist, error) {
 	query := fmt.Sprintf("select %s from %s where `task_node_id` = ? and `creator_id` = ? and `delete_time` is null order by `sort_order` asc, `create_time` asc", taskChecklistRows, m.table)
@@ -206,14 +217,14 @@ func (m *customTaskChecklistModel) FindByExecutorIdWithPage(ctx context.Context,
 	var countQuery, dataQuery string
 	var args []interface{}
 
-	// 构建JOIN查询，关联task_node表获取executor_id
+	// 构建JOIN查询，通过分配表匹配节点执行人（支持多执行人）
 	baseJoin := fmt.Sprintf(`
 		FROM %s tc
 		INNER JOIN task_node tn ON tc.task_node_id = tn.task_node_id
-		WHERE tn.executor_id = ? 
+		WHERE tn.task_node_id IN (%s)
 		AND tc.delete_time IS NULL 
 		AND tn.delete_time IS NULL
-	`, m.table)
+	`, m.table, assignmentSubquery(AssignmentExecutor))
 
 	if isCompleted == 0 || isCompleted == 1 {
 		// 筛选特定状态
@@ -240,25 +251,25 @@ func (m *customTaskChecklistModel) FindByExecutorIdWithPage(ctx context.Context,
 			SELECT %s 
 			FROM %s tc
 			INNER JOIN task_node tn ON tc.task_node_id = tn.task_node_id
-			WHERE tn.executor_id = ? 
+			WHERE tn.task_node_id IN (%s)
 			AND tc.delete_time IS NULL 
 			AND tn.delete_time IS NULL
 			AND tc.is_completed = ?
 			ORDER BY tc.create_time DESC 
 			LIMIT ?, ?
-		`, selectColumns, m.table)
+		`, selectColumns, m.table, assignmentSubquery(AssignmentExecutor))
 		args = []interface{}{executorId, isCompleted, offset, pageSize}
 	} else {
 		dataQuery = fmt.Sprintf(`
 			SELECT %s 
 			FROM %s tc
 			INNER JOIN task_node tn ON tc.task_node_id = tn.task_node_id
-			WHERE tn.executor_id = ? 
+			WHERE tn.task_node_id IN (%s)
 			AND tc.delete_time IS NULL 
 			AND tn.delete_time IS NULL
 			ORDER BY tc.create_time DESC 
 			LIMIT ?, ?
-		`, selectColumns, m.table)
+		`, selectColumns, m.table, assignmentSubquery(AssignmentExecutor))
 		args = []interface{}{executorId, offset, pageSize}
 	}
 
@@ -277,11 +288,11 @@ func (m *customTaskChecklistModel) CountUncompletedByExecutorId(ctx context.Cont
 		SELECT COUNT(*) 
 		FROM %s tc
 		INNER JOIN task_node tn ON tc.task_node_id = tn.task_node_id
-		WHERE tn.executor_id = ? 
+		WHERE tn.task_node_id IN (%s)
 		AND tc.is_completed = 0 
 		AND tc.delete_time IS NULL 
 		AND tn.delete_time IS NULL
-	`, m.table)
+	`, m.table, assignmentSubquery(AssignmentExecutor))
 
 	var count int64
 	err := m.conn.QueryRowCtx(ctx, &count, query, executorId)
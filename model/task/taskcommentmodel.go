@@ -1,6 +1,16 @@
 package task
 
-import "github.com/zeromicro/go-zero/core/stores/mon"
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MaxCommentRevisions 每条评论保留的历史版本数，超出时丢弃最早的版本
+const MaxCommentRevisions = 50
 
 var _ Task_commentModel = (*customTask_commentModel)(nil)
 
@@ -9,6 +19,16 @@ type (
 	// and implement the added methods in customTask_commentModel.
 	Task_commentModel interface {
 		task_commentModel
+		// FindThreads 分页查询任务或节点的顶级评论，taskNodeID 不为空时按节点查询
+		FindThreads(ctx context.Context, taskID, taskNodeID string, page, pageSize int64) ([]*Task_comment, int64, error)
+		// FindRepliesPage 分页查询评论的回复，按时间升序
+		FindRepliesPage(ctx context.Context, parentID string, page, pageSize int64) ([]*Task_comment, int64, error)
+		// FindRepliesByParents 一次查询多条评论各自最早的 limit 条回复和回复总数，返回父评论ID -> 回复、父评论ID -> 回复数
+		FindRepliesByParents(ctx context.Context, parentIDs []string, limit int64) (map[string][]*Task_comment, map[string]int64, error)
+		// EditContent 修改评论内容并保存旧版本，editCount 与库中不一致时返回 false
+		EditContent(ctx context.Context, data *Task_comment, editCount int64, revision Task_commentRevision) (bool, error)
+		// ToggleReaction 切换用户对评论的表情回应，返回切换后是否处于已回应状态
+		ToggleReaction(ctx context.Context, commentID, emoji, userID string) (bool, error)
 	}
 
 	customTask_commentModel struct {
@@ -24,3 +44,127 @@ func NewTask_commentModel(url, db, collection string) Task_commentModel {
 	}
 }
 
+func (m *customTask_commentModel) FindThreads(ctx context.Context, taskID, taskNodeID string, page, pageSize int64) ([]*Task_comment, int64, error) {
+	filter := bson.M{"parentId": bson.M{"$in": bson.A{"", nil}}, "isDeleted": bson.M{"$ne": true}}
+	if taskNodeID != "" {
+		filter["taskNodeId"] = taskNodeID
+	} else {
+		filter["taskId"] = taskID
+	}
+	return m.findPage(ctx, filter, -1, page, pageSize)
+}
+
+func (m *customTask_commentModel) FindRepliesPage(ctx context.Context, parentID string, page, pageSize int64) ([]*Task_comment, int64, error) {
+	filter := bson.M{"parentId": parentID, "isDeleted": bson.M{"$ne": true}}
+	return m.findPage(ctx, filter, 1, page, pageSize)
+}
+
+func (m *customTask_commentModel) FindRepliesByParents(ctx context.Context, parentIDs []string, limit int64) (map[string][]*Task_comment, map[string]int64, error) {
+	replies := make(map[string][]*Task_comment, len(parentIDs))
+	counts := make(map[string]int64, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return replies, counts, nil
+	}
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"parentId": bson.M{"$in": parentIDs}, "isDeleted": bson.M{"$ne": true}}},
+		bson.M{"$project": bson.M{"revisions": 0}},
+		bson.M{"$sort": bson.D{{Key: "createAt", Value: 1}, {Key: "_id", Value: 1}}},
+		bson.M{"$group": bson.M{"_id": "$parentId", "count": bson.M{"$sum": 1}, "replies": bson.M{"$push": "$$ROOT"}}},
+		bson.M{"$project": bson.M{"count": 1, "replies": bson.M{"$slice": bson.A{"$replies", limit}}}},
+	}
+
+	var rows []struct {
+		ParentID string          `bson:"_id"`
+		Count    int64           `bson:"count"`
+		Replies  []*Task_comment `bson:"replies"`
+	}
+	if err := m.conn.Aggregate(ctx, &rows, pipeline); err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		replies[row.ParentID] = row.Replies
+		counts[row.ParentID] = row.Count
+	}
+	return replies, counts, nil
+}
+
+// findPage 按创建时间排序分页查询，列表查询不返回历史版本
+func (m *customTask_commentModel) findPage(ctx context.Context, filter bson.M, order int, page, pageSize int64) ([]*Task_comment, int64, error) {
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*Task_comment{}, 0, nil
+	}
+
+	skip := (page - 1) * pageSize
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createAt", Value: order}, {Key: "_id", Value: order}}).
+		SetSkip(skip).
+		SetLimit(pageSize).
+		SetProjection(bson.M{"revisions": 0})
+
+	var data []*Task_comment
+	if err := m.conn.Find(ctx, &data, filter, opts); err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+func (m *customTask_commentModel) EditContent(ctx context.Context, data *Task_comment, editCount int64, revision Task_commentRevision) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"commentId": data.CommentID, "editCount": editCount, "isDeleted": bson.M{"$ne": true}}
+	if editCount == 0 {
+		// 旧数据没有 editCount 字段
+		filter["editCount"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	res, err := m.conn.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"content":         data.Content,
+			"contentHtml":     data.ContentHTML,
+			"contentText":     data.ContentText,
+			"atEmployeeIds":   data.AtEmployeeIDs,
+			"atEmployeeNames": data.AtEmployeeNames,
+			"atGroupIds":      data.AtGroupIDs,
+			"edited":          true,
+			"editedAt":        now,
+			"updateAt":        now,
+			"editCount":       editCount + 1,
+		},
+		"$push": bson.M{
+			"revisions": bson.M{"$each": bson.A{revision}, "$slice": -MaxCommentRevisions},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (m *customTask_commentModel) ToggleReaction(ctx context.Context, commentID, emoji, userID string) (bool, error) {
+	field := "reactions." + emoji
+	// 未回应时加入，已回应时匹配不到再移除，两步都按用户条件过滤，并发重复请求不会重复计数
+	res, err := m.conn.UpdateOne(ctx,
+		bson.M{"commentId": commentID, "isDeleted": bson.M{"$ne": true}, field: bson.M{"$ne": userID}},
+		bson.M{"$addToSet": bson.M{field: userID}, "$set": bson.M{"updateAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if res.MatchedCount > 0 {
+		return true, nil
+	}
+
+	res, err = m.conn.UpdateOne(ctx,
+		bson.M{"commentId": commentID, "isDeleted": bson.M{"$ne": true}, field: userID},
+		bson.M{"$pull": bson.M{field: userID}, "$set": bson.M{"updateAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if res.MatchedCount == 0 {
+		return false, ErrNotFound
+	}
+	return false, nil
+}
@@ -16,10 +16,12 @@ type Task_comment struct {
 	UserID         string        `bson:"userId" json:"userId" index:"userId"`                      // 评论用户ID
 	EmployeeID     string        `bson:"employeeId" json:"employeeId" index:"employeeId"`          // 员工ID
 	EmployeeName   string        `bson:"employeeName" json:"employeeName"`                         // 员工姓名
-	Content        string        `bson:"content" json:"content"`                                   // 评论内容
-	ContentHTML    string        `bson:"contentHtml" json:"contentHtml"`                           // HTML格式内容(支持富文本)
+	Content        string        `bson:"content" json:"content"`                                   // 评论内容(Markdown原文)
+	ContentHTML    string        `bson:"contentHtml" json:"contentHtml"`                           // 服务端渲染并净化的HTML
+	ContentText    string        `bson:"contentText" json:"contentText"`                           // 纯文本，用于搜索和通知
 	AtEmployeeIDs  []string      `bson:"atEmployeeIds" json:"atEmployeeIds"`                       // @的员工ID列表
 	AtEmployeeNames []string     `bson:"atEmployeeNames" json:"atEmployeeNames"`                   // @的员工姓名列表
+	AtGroupIDs     []string      `bson:"atGroupIds,omitempty" json:"atGroupIds,omitempty"`         // @的部门和角色ID列表
 	ParentID       string        `bson:"parentId" json:"parentId"`                                 // 父评论ID(用于回复)
 	ReplyToUserID  string        `bson:"replyToUserId" json:"replyToUserId"`                       // 回复的用户ID
 	ReplyToName    string        `bson:"replyToName" json:"replyToName"`                           // 回复的用户姓名
@@ -27,9 +29,23 @@ type Task_comment struct {
 	AttachmentURLs []string      `bson:"attachmentUrls" json:"attachmentUrls"`                     // 附件URL列表
 	LikeCount      int64         `bson:"likeCount" json:"likeCount"`                               // 点赞数
 	LikedBy        []string      `bson:"likedBy" json:"likedBy"`                                   // 点赞的用户ID列表
+	Reactions      map[string][]string `bson:"reactions,omitempty" json:"reactions,omitempty"`    // 表情回应，表情 -> 回应的用户ID列表
+	Edited         bool          `bson:"edited" json:"edited"`                                     // 是否编辑过
+	EditedAt       time.Time     `bson:"editedAt,omitempty" json:"editedAt,omitempty"`             // 最后编辑时间
+	EditCount      int64         `bson:"editCount" json:"editCount"`                               // 编辑次数，编辑时用作版本号
+	Revisions      []Task_commentRevision `bson:"revisions,omitempty" json:"revisions,omitempty"` // 历史版本，按编辑时间升序
 	IsDeleted      bool          `bson:"isDeleted" json:"isDeleted"`                               // 是否已删除
 	DeletedAt      time.Time     `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`           // 删除时间
 	UpdateAt       time.Time     `bson:"updateAt,omitempty" json:"updateAt,omitempty"`
 	CreateAt       time.Time     `bson:"createAt,omitempty" json:"createAt,omitempty" index:"createAt"`
 }
 
+
+// Task_commentRevision 评论的历史版本，编辑时保存被替换的内容
+type Task_commentRevision struct {
+	Content     string    `bson:"content" json:"content"`         // 编辑前的内容
+	ContentHTML string    `bson:"contentHtml" json:"contentHtml"` // 编辑前的HTML内容
+	EditedBy    string    `bson:"editedBy" json:"editedBy"`       // 编辑人员工ID
+	EditorName  string    `bson:"editorName" json:"editorName"`   // 编辑人姓名
+	EditedAt    time.Time `bson:"editedAt" json:"editedAt"`       // 编辑时间
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// TaskDetailContent 任务详情（节点ID为空）或节点详情的渲染结果
+type TaskDetailContent struct {
+	TaskId      string    `db:"task_id"`      // 任务ID
+	TaskNodeId  string    `db:"task_node_id"` // 任务节点ID，任务详情为空
+	SourceHash  string    `db:"source_hash"`  // 渲染时原文的 SHA-256
+	ContentHtml string    `db:"content_html"` // 渲染并净化后的 HTML
+	ContentText string    `db:"content_text"` // 纯文本
+	UpdateTime  time.Time `db:"update_time"`  // 更新时间
+}
+
+const taskDetailContentRows = "`task_id`, `task_node_id`, `source_hash`, `content_html`, `content_text`, `update_time`"
+
+type TaskDetailContentModel interface {
+	// FindOne 任务详情或节点详情的渲染结果，没有记录时返回 ErrNotFound
+	FindOne(ctx context.Context, taskId, taskNodeId string) (*TaskDetailContent, error)
+	// FindByTask 任务详情及其全部节点详情的渲染结果
+	FindByTask(ctx context.Context, taskId string) ([]*TaskDetailContent, error)
+	// Save 写入渲染结果（存在时覆盖）
+	Save(ctx context.Context, data *TaskDetailContent) error
+}
+
+type defaultTaskDetailContentModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTaskDetailContentModel(conn sqlx.SqlConn) TaskDetailContentModel {
+	return &defaultTaskDetailContentModel{
+		conn:  conn,
+		table: "`task_detail_content`",
+	}
+}
+
+func (m *defaultTaskDetailContentModel) FindOne(ctx context.Context, taskId, taskNodeId string) (*TaskDetailContent, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_id` = ? AND `task_node_id` = ? LIMIT 1", taskDetailContentRows, m.table)
+	var resp TaskDetailContent
+	err := m.conn.QueryRowCtx(ctx, &resp, query, taskId, taskNodeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskDetailContentModel) FindByTask(ctx context.Context, taskId string) ([]*TaskDetailContent, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_id` = ?", taskDetailContentRows, m.table)
+	var resp []*TaskDetailContent
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, taskId)
+	return resp, err
+}
+
+func (m *defaultTaskDetailContentModel) Save(ctx context.Context, data *TaskDetailContent) error {
+	query := fmt.Sprintf("INSERT INTO %s (`task_id`, `task_node_id`, `source_hash`, `content_html`, `content_text`) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `source_hash` = VALUES(`source_hash`), `content_html` = VALUES(`content_html`), `content_text` = VALUES(`content_text`)", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.TaskId, data.TaskNodeId, data.SourceHash, data.ContentHtml, data.ContentText)
+	return err
+}
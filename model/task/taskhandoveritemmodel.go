@@ -0,0 +1,104 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 交接事项类型
+const (
+	HandoverItemNodeExecutor = "node_executor" // 节点执行人角色
+	HandoverItemNodeLeader   = "node_leader"   // 节点负责人角色
+	HandoverItemChecklist    = "checklist"     // 清单归属
+	HandoverItemAttachment   = "attachment"    // 附件归属
+)
+
+// 交接事项状态
+const (
+	HandoverItemPending     = 0 // 待交接
+	HandoverItemTransferred = 1 // 已交接
+	HandoverItemSkipped     = 2 // 已跳过（审批时原归属已变化）
+)
+
+// TaskHandoverItem 交接事项，交接包含事项时只转移这些事项，不包含时按整个任务交接
+type TaskHandoverItem struct {
+	Id         string    `db:"id"`           // 交接事项ID
+	HandoverId string    `db:"handover_id"`  // 交接ID
+	ItemType   string    `db:"item_type"`    // 事项类型
+	TaskNodeId string    `db:"task_node_id"` // 所属任务节点ID
+	TargetId   string    `db:"target_id"`    // 节点ID、清单ID或附件文件ID
+	TargetName string    `db:"target_name"`  // 发起时的节点名称、清单内容或文件名
+	Status     int64     `db:"status"`       // 状态 0-待交接 1-已交接 2-已跳过
+	Remark     string    `db:"remark"`       // 跳过原因
+	CreateTime time.Time `db:"create_time"`  // 创建时间
+	UpdateTime time.Time `db:"update_time"`  // 更新时间
+}
+
+const taskHandoverItemRows = "`id`, `handover_id`, `item_type`, `task_node_id`, `target_id`, `target_name`, `status`, `remark`, `create_time`, `update_time`"
+
+type TaskHandoverItemModel interface {
+	// InsertBatch 批量写入交接事项
+	InsertBatch(ctx context.Context, items []*TaskHandoverItem) error
+	// FindByHandoverId 交接的全部事项，按写入顺序排序
+	FindByHandoverId(ctx context.Context, handoverId string) ([]*TaskHandoverItem, error)
+	// FindByHandoverIds 批量查询多个交接的事项
+	FindByHandoverIds(ctx context.Context, handoverIds []string) ([]*TaskHandoverItem, error)
+	// UpdateStatus 更新事项状态和跳过原因
+	UpdateStatus(ctx context.Context, id string, status int64, remark string) error
+}
+
+type defaultTaskHandoverItemModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTaskHandoverItemModel(conn sqlx.SqlConn) TaskHandoverItemModel {
+	return &defaultTaskHandoverItemModel{
+		conn:  conn,
+		table: "`task_handover_item`",
+	}
+}
+
+func (m *defaultTaskHandoverItemModel) InsertBatch(ctx context.Context, items []*TaskHandoverItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items)*10)
+	for _, it := range items {
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, it.Id, it.HandoverId, it.ItemType, it.TaskNodeId, it.TargetId, it.TargetName,
+			it.Status, it.Remark, it.CreateTime, it.UpdateTime)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", m.table, taskHandoverItemRows, strings.Join(values, ", "))
+	_, err := m.conn.ExecCtx(ctx, query, args...)
+	return err
+}
+
+func (m *defaultTaskHandoverItemModel) FindByHandoverId(ctx context.Context, handoverId string) ([]*TaskHandoverItem, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `handover_id` = ? ORDER BY `create_time` ASC, `id` ASC", taskHandoverItemRows, m.table)
+	var resp []*TaskHandoverItem
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, handoverId)
+	return resp, err
+}
+
+func (m *defaultTaskHandoverItemModel) FindByHandoverIds(ctx context.Context, handoverIds []string) ([]*TaskHandoverItem, error) {
+	if len(handoverIds) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `handover_id` IN (%s) ORDER BY `create_time` ASC, `id` ASC",
+		taskHandoverItemRows, m.table, placeholders(len(handoverIds)))
+	var resp []*TaskHandoverItem
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, appendStrings(nil, handoverIds)...)
+	return resp, err
+}
+
+func (m *defaultTaskHandoverItemModel) UpdateStatus(ctx context.Context, id string, status int64, remark string) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `remark` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, remark, time.Now(), id)
+	return err
+}
@@ -36,7 +36,11 @@ type (
 		GetTaskHandoverCountByTaskNode(ctx context.Context, taskNodeID string) (int64, error)
 		GetTaskHandoverCountByFromEmployee(ctx context.Context, fromEmployeeID string) (int64, error)
 		GetTaskHandoverCountByToEmployee(ctx context.Context, toEmployeeID string) (int64, error)
+		CountPendingByApprover(ctx context.Context, approverID string) (int64, error)
+		CountPendingByFromEmployee(ctx context.Context, fromEmployeeID string) (int64, error)
 		FindPendingApprovalsByEmployee(ctx context.Context, employeeID string, page, pageSize int) ([]*TaskHandover, int64, error)
+		FindPendingByApprover(ctx context.Context, approverID string) ([]*TaskHandover, error)
+		UpdateApprover(ctx context.Context, id string, approverID string) error
 	}
 
 	customTaskHandoverModel struct {
@@ -246,6 +250,22 @@ func (m *customTaskHandoverModel) GetTaskHandoverCountByToEmployee(ctx context.C
 	return count, err
 }
 
+// CountPendingByApprover 统计审批人待审批的交接数量
+func (m *customTaskHandoverModel) CountPendingByApprover(ctx context.Context, approverID string) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM task_handover WHERE approver_id = ? AND handover_status = 1`
+	err := m.conn.QueryRowCtx(ctx, &count, query, approverID)
+	return count, err
+}
+
+// CountPendingByFromEmployee 统计员工发起的待审批交接数量
+func (m *customTaskHandoverModel) CountPendingByFromEmployee(ctx context.Context, fromEmployeeID string) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM task_handover WHERE from_employee_id = ? AND handover_status = 1`
+	err := m.conn.QueryRowCtx(ctx, &count, query, fromEmployeeID)
+	return count, err
+}
+
 // FindByEmployeeInvolved 查询与员工相关的所有交接（作为发起人或接收人或审批人）
 func (m *customTaskHandoverModel) FindByEmployeeInvolved(ctx context.Context, employeeID string, page, pageSize int) ([]*TaskHandover, int64, error) {
 	var taskHandovers []*TaskHandover
@@ -289,3 +309,18 @@ func (m *customTaskHandoverModel) FindPendingApprovalsByEmployee(ctx context.Con
 	err = m.conn.QueryRowsCtx(ctx, &taskHandovers, query, employeeID, employeeID, pageSize, offset)
 	return taskHandovers, total, err
 }
+
+// FindPendingByApprover 查询审批人为指定员工且尚未结束的交接（待接收人确认或待上级审批）
+func (m *customTaskHandoverModel) FindPendingByApprover(ctx context.Context, approverID string) ([]*TaskHandover, error) {
+	var taskHandovers []*TaskHandover
+	query := `SELECT * FROM task_handover WHERE approver_id = ? AND handover_status IN (0, 1) ORDER BY create_time ASC`
+	err := m.conn.QueryRowsCtx(ctx, &taskHandovers, query, approverID)
+	return taskHandovers, err
+}
+
+// UpdateApprover 更新交接审批人
+func (m *customTaskHandoverModel) UpdateApprover(ctx context.Context, id string, approverID string) error {
+	query := `UPDATE task_handover SET approver_id = ?, update_time = NOW() WHERE handover_id = ?`
+	_, err := m.conn.ExecCtx(ctx, query, approverID, id)
+	return err
+}
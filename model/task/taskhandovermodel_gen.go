@@ -45,6 +45,7 @@ type (
 		HandoverStatus int64          `db:"handover_status"`  // 交接状态 0-待确认 1-已接受 2-已拒绝 3-已完成
 		HandoverReason sql.NullString `db:"handover_reason"`  // 交接原因
 		HandoverNote   sql.NullString `db:"handover_note"`    // 交接备注
+		TransitionNote sql.NullString `db:"transition_note"`  // 交接说明（展示给接收人）
 		ApproverId     sql.NullString `db:"approver_id"`      // 审批人员工id
 		ApproveTime    sql.NullTime   `db:"approve_time"`     // 审批时间
 		CreateTime     time.Time      `db:"create_time"`      // 创建时间
@@ -80,14 +81,14 @@ func (m *defaultTaskHandoverModel) FindOne(ctx context.Context, handoverId strin
 }
 
 func (m *defaultTaskHandoverModel) Insert(ctx context.Context, data *TaskHandover) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, taskHandoverRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.HandoverId, data.TaskId, data.FromEmployeeId, data.ToEmployeeId, data.HandoverType, data.HandoverStatus, data.HandoverReason, data.HandoverNote, data.ApproverId, data.ApproveTime)
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, taskHandoverRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.HandoverId, data.TaskId, data.FromEmployeeId, data.ToEmployeeId, data.HandoverType, data.HandoverStatus, data.HandoverReason, data.HandoverNote, data.TransitionNote, data.ApproverId, data.ApproveTime)
 	return ret, err
 }
 
 func (m *defaultTaskHandoverModel) Update(ctx context.Context, data *TaskHandover) error {
 	query := fmt.Sprintf("update %s set %s where `handover_id` = ?", m.table, taskHandoverRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, data.TaskId, data.FromEmployeeId, data.ToEmployeeId, data.HandoverType, data.HandoverStatus, data.HandoverReason, data.HandoverNote, data.ApproverId, data.ApproveTime, data.HandoverId)
+	_, err := m.conn.ExecCtx(ctx, query, data.TaskId, data.FromEmployeeId, data.ToEmployeeId, data.HandoverType, data.HandoverStatus, data.HandoverReason, data.HandoverNote, data.TransitionNote, data.ApproverId, data.ApproveTime, data.HandoverId)
 	return err
 }
 
@@ -0,0 +1,173 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// TaskLabel 公司自定义的任务标签
+type TaskLabel struct {
+	Id          string    `db:"id"`          // 标签ID
+	CompanyId   string    `db:"company_id"`  // 公司ID
+	Name        string    `db:"name"`        // 标签名称
+	Color       string    `db:"color"`       // 标签颜色（#RRGGBB）
+	Description string    `db:"description"` // 说明
+	CreateTime  time.Time `db:"create_time"` // 创建时间
+	UpdateTime  time.Time `db:"update_time"` // 更新时间
+}
+
+// TaskLabelRef 任务关联的标签
+type TaskLabelRef struct {
+	TaskId string `db:"task_id"`
+	TaskLabel
+}
+
+// TaskLabelUsage 标签关联的任务数
+type TaskLabelUsage struct {
+	LabelId   string `db:"label_id"`
+	TaskCount int64  `db:"task_count"`
+}
+
+const taskLabelRows = "`id`, `company_id`, `name`, `color`, `description`, `create_time`, `update_time`"
+
+type TaskLabelModel interface {
+	Insert(ctx context.Context, data *TaskLabel) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*TaskLabel, error)
+	FindByName(ctx context.Context, companyId, name string) (*TaskLabel, error)
+	Update(ctx context.Context, data *TaskLabel) error
+	// Delete 删除标签及其与任务的关联
+	Delete(ctx context.Context, id string) error
+	FindByCompany(ctx context.Context, companyId string) ([]*TaskLabel, error)
+	CountByCompany(ctx context.Context, companyId string) (int64, error)
+	// CountTasks 公司各标签关联的未删除任务数
+	CountTasks(ctx context.Context, companyId string) ([]*TaskLabelUsage, error)
+	// SetTaskLabels 替换任务的全部标签
+	SetTaskLabels(ctx context.Context, taskId string, labelIds []string) error
+	// FindByTaskIds 批量查询任务的标签，按标签名称排序
+	FindByTaskIds(ctx context.Context, taskIds []string) ([]*TaskLabelRef, error)
+}
+
+type defaultTaskLabelModel struct {
+	conn          sqlx.SqlConn
+	table         string
+	relationTable string
+}
+
+func NewTaskLabelModel(conn sqlx.SqlConn) TaskLabelModel {
+	return &defaultTaskLabelModel{
+		conn:          conn,
+		table:         "`task_label`",
+		relationTable: "`task_label_relation`",
+	}
+}
+
+func (m *defaultTaskLabelModel) Insert(ctx context.Context, data *TaskLabel) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?)", m.table, taskLabelRows)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.Name, data.Color, data.Description, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultTaskLabelModel) FindOne(ctx context.Context, id string) (*TaskLabel, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", taskLabelRows, m.table)
+	var resp TaskLabel
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskLabelModel) FindByName(ctx context.Context, companyId, name string) (*TaskLabel, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND `name` = ? LIMIT 1", taskLabelRows, m.table)
+	var resp TaskLabel
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId, name)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskLabelModel) Update(ctx context.Context, data *TaskLabel) error {
+	query := fmt.Sprintf("UPDATE %s SET `name` = ?, `color` = ?, `description` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Name, data.Color, data.Description, time.Now(), data.Id)
+	return err
+}
+
+func (m *defaultTaskLabelModel) Delete(ctx context.Context, id string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `label_id` = ?", m.relationTable), id); err != nil {
+			return err
+		}
+		_, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table), id)
+		return err
+	})
+}
+
+func (m *defaultTaskLabelModel) FindByCompany(ctx context.Context, companyId string) ([]*TaskLabel, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? ORDER BY `name` ASC", taskLabelRows, m.table)
+	var resp []*TaskLabel
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId)
+	return resp, err
+}
+
+func (m *defaultTaskLabelModel) CountByCompany(ctx context.Context, companyId string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `company_id` = ?", m.table)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, companyId)
+	return count, err
+}
+
+func (m *defaultTaskLabelModel) CountTasks(ctx context.Context, companyId string) ([]*TaskLabelUsage, error) {
+	query := fmt.Sprintf(`SELECT r.label_id, COUNT(*) AS task_count FROM %s r
+        JOIN %s l ON l.id = r.label_id
+        JOIN task t ON t.task_id = r.task_id AND t.delete_time IS NULL
+        WHERE l.company_id = ? GROUP BY r.label_id`, m.relationTable, m.table)
+	var resp []*TaskLabelUsage
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId)
+	return resp, err
+}
+
+func (m *defaultTaskLabelModel) SetTaskLabels(ctx context.Context, taskId string, labelIds []string) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, fmt.Sprintf("DELETE FROM %s WHERE `task_id` = ?", m.relationTable), taskId); err != nil {
+			return err
+		}
+		if len(labelIds) == 0 {
+			return nil
+		}
+		values := make([]string, 0, len(labelIds))
+		args := make([]interface{}, 0, len(labelIds)*3)
+		now := time.Now()
+		for _, id := range labelIds {
+			values = append(values, "(?, ?, ?)")
+			args = append(args, taskId, id, now)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (`task_id`, `label_id`, `create_time`) VALUES %s", m.relationTable, strings.Join(values, ", "))
+		_, err := session.ExecCtx(ctx, query, args...)
+		return err
+	})
+}
+
+func (m *defaultTaskLabelModel) FindByTaskIds(ctx context.Context, taskIds []string) ([]*TaskLabelRef, error) {
+	if len(taskIds) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf(`SELECT r.task_id, l.id, l.company_id, l.name, l.color, l.description, l.create_time, l.update_time
+        FROM %s r JOIN %s l ON l.id = r.label_id
+        WHERE r.task_id IN (%s) ORDER BY l.name ASC`, m.relationTable, m.table, placeholders(len(taskIds)))
+	var resp []*TaskLabelRef
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, appendStrings(nil, taskIds)...)
+	return resp, err
+}
@@ -2,7 +2,9 @@ package task
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
@@ -28,6 +30,8 @@ type (
 		GetTaskLogCountByTaskNode(ctx context.Context, taskNodeID string) (int64, error)
 		GetTaskLogCountByOperator(ctx context.Context, operatorID string) (int64, error)
 		GetTaskLogCountByLogType(ctx context.Context, logType string) (int64, error)
+		InsertEvent(ctx context.Context, data *TaskLog, event *TaskLogEvent) error
+		FindEventsByTask(ctx context.Context, taskID string) ([]*TaskLogEvent, error)
 	}
 
 	customTaskLogModel struct {
@@ -169,3 +173,38 @@ func (m *customTaskLogModel) GetTaskLogCountByLogType(ctx context.Context, logTy
 	err := m.conn.QueryRowCtx(ctx, &count, query, logType)
 	return count, err
 }
+
+// 结构化日志事件类型
+const (
+	TaskLogEventNodeStatus      = "node_status"      // 节点状态变更
+	TaskLogEventChecklistStatus = "checklist_status" // 清单完成状态变更
+)
+
+// TaskLogTypeStatus 状态变更日志类型
+const TaskLogTypeStatus = 8
+
+// TaskLogEvent 任务日志中的结构化状态变更
+type TaskLogEvent struct {
+	TaskNodeId  sql.NullString `db:"task_node_id"`
+	ChecklistId sql.NullString `db:"checklist_id"`
+	EventType   string         `db:"event_type"`
+	FromStatus  sql.NullInt64  `db:"from_status"`
+	ToStatus    int64          `db:"to_status"`
+	CreateTime  time.Time      `db:"create_time"`
+}
+
+// InsertEvent 写入带结构化状态变更的任务日志
+func (m *customTaskLogModel) InsertEvent(ctx context.Context, data *TaskLog, event *TaskLogEvent) error {
+	query := "INSERT INTO task_log (`log_id`, `task_id`, `task_node_id`, `employee_id`, `log_type`, `log_content`, `event_type`, `checklist_id`, `from_status`, `to_status`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := m.conn.ExecCtx(ctx, query, data.LogId, data.TaskId, data.TaskNodeId, data.EmployeeId, data.LogType, data.LogContent,
+		event.EventType, event.ChecklistId, event.FromStatus, event.ToStatus)
+	return err
+}
+
+// FindEventsByTask 查询任务的结构化状态变更，按时间升序
+func (m *customTaskLogModel) FindEventsByTask(ctx context.Context, taskID string) ([]*TaskLogEvent, error) {
+	var events []*TaskLogEvent
+	query := "SELECT `task_node_id`, `checklist_id`, `event_type`, `from_status`, `to_status`, `create_time` FROM task_log WHERE task_id = ? AND event_type IS NOT NULL ORDER BY create_time ASC"
+	err := m.conn.QueryRowsCtx(ctx, &events, query, taskID)
+	return events, err
+}
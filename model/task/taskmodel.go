@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -27,10 +28,17 @@ type (
 		FindByPage(ctx context.Context, page, pageSize int) ([]*Task, int64, error)
 		// 用户参与的任务（创建者/负责人/节点执行人）
 		FindByInvolved(ctx context.Context, employeeID string, page, pageSize int) ([]*Task, int64, error)
+		// 公司未完成的任务，按截止时间升序
+		FindOpenByCompany(ctx context.Context, companyID string, limit int) ([]*Task, error)
 		SearchTasks(ctx context.Context, keyword string, page, pageSize int) ([]*Task, int64, error)
+		// 高级搜索：结构化条件、多字段排序和游标分页
+		SearchByFilter(ctx context.Context, filter *TaskSearchFilter, sorts []TaskSortField, cursor string, limit int) ([]*Task, error)
+		CountByFilter(ctx context.Context, filter *TaskSearchFilter) (int64, error)
+		// 员工参与的全部任务ID
+		FindInvolvedIDs(ctx context.Context, companyId, employeeId string) ([]string, error)
 		UpdateStatus(ctx context.Context, id string, status int) error
 		UpdateProgress(ctx context.Context, id string, progress int) error
-		UpdateActualHours(ctx context.Context, id string, actualHours int) error
+		UpdateActualHours(ctx context.Context, id string, actualHours float64) error
 		UpdateBasicInfo(ctx context.Context, id, title, description string) error
 		UpdateDeadline(ctx context.Context, id string, deadline string) error
 		SoftDelete(ctx context.Context, id string) error
@@ -82,6 +90,14 @@ func (m *customTaskModel) FindByCompany(ctx context.Context, companyID string, p
 	return tasks, total, err
 }
 
+// FindOpenByCompany 查询公司未完成（未开始/进行中）的任务，按截止时间升序
+func (m *customTaskModel) FindOpenByCompany(ctx context.Context, companyID string, limit int) ([]*Task, error) {
+	var tasks []*Task
+	query := `SELECT * FROM task WHERE company_id = ? AND task_status IN (0, 1) AND delete_time IS NULL ORDER BY task_deadline ASC LIMIT ?`
+	err := m.conn.QueryRowsCtx(ctx, &tasks, query, companyID, limit)
+	return tasks, err
+}
+
 // FindByDepartment 根据部门ID查找任务
 func (m *customTaskModel) FindByDepartment(ctx context.Context, departmentID string, page, pageSize int) ([]*Task, int64, error) {
 	var tasks []*Task
@@ -108,42 +124,18 @@ func (m *customTaskModel) FindByInvolved(ctx context.Context, employeeID string,
 	var tasks []*Task
 	var total int64
 
-	// 统计总数 - 通过子查询从 task_node 表查找员工参与的任务
-	// 条件：1. 任务创建者 2. 任务负责人 3. 节点执行人(executor_id支持逗号分隔) 4. 节点负责人
-	countQuery := `SELECT COUNT(DISTINCT t.task_id) FROM task t
-        WHERE (
-            t.task_creator = ? 
-            OR t.leader_id = ?
-            OR FIND_IN_SET(?, t.responsible_employee_ids)
-            OR EXISTS (
-                SELECT 1 FROM task_node tn 
-                WHERE tn.task_id = t.task_id 
-                AND tn.delete_time IS NULL
-                AND (FIND_IN_SET(?, tn.executor_id) OR tn.leader_id = ?)
-            )
-        )
-        AND t.delete_time IS NULL`
-	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, employeeID, employeeID, employeeID, employeeID, employeeID); err != nil {
+	// 统计总数 - 通过分配表查找员工参与的任务
+	// 条件：1. 任务创建者 2. 任务负责人/责任人 3. 节点执行人 4. 节点负责人（节点均支持多人）
+	where := `(t.task_creator = ? OR t.task_id IN (` + involvedTaskSubquery() + `)) AND t.delete_time IS NULL`
+	args := []interface{}{employeeID, employeeID}
+	if err := m.conn.QueryRowCtx(ctx, &total, `SELECT COUNT(*) FROM task t WHERE `+where, args...); err != nil {
 		return nil, 0, err
 	}
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	query := `SELECT DISTINCT t.* FROM task t
-        WHERE (
-            t.task_creator = ? 
-            OR t.leader_id = ?
-            OR FIND_IN_SET(?, t.responsible_employee_ids)
-            OR EXISTS (
-                SELECT 1 FROM task_node tn 
-                WHERE tn.task_id = t.task_id 
-                AND tn.delete_time IS NULL
-                AND (FIND_IN_SET(?, tn.executor_id) OR tn.leader_id = ?)
-            )
-        )
-        AND t.delete_time IS NULL 
-        ORDER BY t.create_time DESC LIMIT ? OFFSET ?`
-	if err := m.conn.QueryRowsCtx(ctx, &tasks, query, employeeID, employeeID, employeeID, employeeID, employeeID, pageSize, offset); err != nil {
+	query := `SELECT t.* FROM task t WHERE ` + where + ` ORDER BY t.create_time DESC LIMIT ? OFFSET ?`
+	if err := m.conn.QueryRowsCtx(ctx, &tasks, query, append(args, pageSize, offset)...); err != nil {
 		return nil, 0, err
 	}
 	return tasks, total, nil
@@ -248,7 +240,7 @@ func (m *customTaskModel) UpdateProgress(ctx context.Context, id string, progres
 }
 
 // UpdateActualHours 更新实际工时
-func (m *customTaskModel) UpdateActualHours(ctx context.Context, id string, actualHours int) error {
+func (m *customTaskModel) UpdateActualHours(ctx context.Context, id string, actualHours float64) error {
 	query := `UPDATE task SET actual_hours = ?, update_time = NOW() WHERE task_id = ? AND delete_time IS NULL`
 	_, err := m.conn.ExecCtx(ctx, query, actualHours, id)
 	return err
@@ -353,6 +345,33 @@ func (m *customTaskModel) UpdateNodeCount(ctx context.Context, taskId string, to
 // UpdateNodeEmployeeIds 更新任务的节点员工ID列表
 func (m *customTaskModel) UpdateNodeEmployeeIds(ctx context.Context, taskId string, nodeEmployeeIds string) error {
 	query := `UPDATE task SET node_employee_ids = ?, update_time = NOW() WHERE task_id = ? AND delete_time IS NULL`
-	_, err := m.conn.ExecCtx(ctx, query, nodeEmployeeIds, taskId)
-	return err
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, query, nodeEmployeeIds, taskId); err != nil {
+			return err
+		}
+		return syncAssignments(ctx, session, taskId, "", AssignmentNodeMember, nodeEmployeeIds)
+	})
+}
+
+// Insert 写入任务并在同一事务中同步任务级分配关系
+func (m *customTaskModel) Insert(ctx context.Context, data *Task) (sql.Result, error) {
+	var ret sql.Result
+	err := transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		var err error
+		if ret, err = newTaskModel(sqlx.NewSqlConnFromSession(session)).Insert(ctx, data); err != nil {
+			return err
+		}
+		return syncTaskAssignments(ctx, session, data)
+	})
+	return ret, err
+}
+
+// Update 更新任务并在同一事务中同步任务级分配关系
+func (m *customTaskModel) Update(ctx context.Context, data *Task) error {
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if err := newTaskModel(sqlx.NewSqlConnFromSession(session)).Update(ctx, data); err != nil {
+			return err
+		}
+		return syncTaskAssignments(ctx, session, data)
+	})
 }
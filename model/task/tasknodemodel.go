@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
@@ -30,7 +31,7 @@ type (
 		SearchTaskNodes(ctx context.Context, keyword string, page, pageSize int) ([]*TaskNode, int64, error)
 		UpdateStatus(ctx context.Context, id string, status int) error
 		UpdateProgress(ctx context.Context, id string, progress int) error
-		UpdateActualHours(ctx context.Context, id string, actualHours int) error
+		UpdateActualHours(ctx context.Context, id string, actualHours float64) error
 		UpdateExecutor(ctx context.Context, id, executorID string) error
 		UpdateLeader(ctx context.Context, id, leaderID string) error
 		UpdateDeadline(ctx context.Context, id, deadline string) error
@@ -53,6 +54,8 @@ type (
 		UpdateChecklistCount(ctx context.Context, taskNodeId string, totalCount, completedCount int64) error
 		// GetCompletedNodeCountByTask 获取任务下已完成的节点数
 		GetCompletedNodeCountByTask(ctx context.Context, taskID string) (int64, error)
+		// FindOpenWorkloadByCompany 查询公司全部未完成节点的工作量字段
+		FindOpenWorkloadByCompany(ctx context.Context, companyID string) ([]*NodeWorkload, error)
 	}
 
 	customTaskNodeModel struct {
@@ -149,8 +152,8 @@ func (m *customTaskNodeModel) FindByExecutor(ctx context.Context, executorID str
 	var taskNodes []*TaskNode
 	var total int64
 
-	// 查询总数（支持多执行人，使用 FIND_IN_SET）
-	countQuery := `SELECT COUNT(*) FROM task_node WHERE FIND_IN_SET(?, executor_id) AND delete_time IS NULL`
+	// 查询总数（支持多执行人，通过分配表查询）
+	countQuery := `SELECT COUNT(*) FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentExecutor) + `) AND delete_time IS NULL`
 	err := m.conn.QueryRowCtx(ctx, &total, countQuery, executorID)
 	if err != nil {
 		return nil, 0, err
@@ -164,7 +167,7 @@ func (m *customTaskNodeModel) FindByExecutor(ctx context.Context, executorID str
         node_deadline, node_start_time, estimated_days, actual_days,
         node_status, node_finish_time, executor_id, leader_id, progress, node_priority,
         create_time, update_time, delete_time
-        FROM task_node WHERE FIND_IN_SET(?, executor_id) AND delete_time IS NULL ORDER BY create_time DESC LIMIT ? OFFSET ? `
+        FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentExecutor) + `) AND delete_time IS NULL ORDER BY create_time DESC LIMIT ? OFFSET ? `
 	err = m.conn.QueryRowsCtx(ctx, &taskNodes, query, executorID, pageSize, offset)
 	fmt.Println(pageSize)
 	return taskNodes, total, err
@@ -175,8 +178,8 @@ func (m *customTaskNodeModel) FindByLeader(ctx context.Context, leaderID string,
 	var taskNodes []*TaskNode
 	var total int64
 
-	// 查询总数（支持多负责人存储，使用 FIND_IN_SET）
-	countQuery := `SELECT COUNT(*) FROM task_node WHERE FIND_IN_SET(?, leader_id) AND delete_time IS NULL`
+	// 查询总数（支持多负责人存储，通过分配表查询）
+	countQuery := `SELECT COUNT(*) FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentNodeLeader) + `) AND delete_time IS NULL`
 	err := m.conn.QueryRowCtx(ctx, &total, countQuery, leaderID)
 	if err != nil {
 		return nil, 0, err
@@ -191,7 +194,7 @@ func (m *customTaskNodeModel) FindByLeader(ctx context.Context, leaderID string,
         node_deadline, node_start_time, estimated_days, actual_days,
         node_status, node_finish_time, executor_id, leader_id, progress, node_priority,
         create_time, update_time, delete_time
-        FROM task_node WHERE FIND_IN_SET(?, leader_id) AND delete_time IS NULL ORDER BY create_time DESC LIMIT ? OFFSET ?`
+        FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentNodeLeader) + `) AND delete_time IS NULL ORDER BY create_time DESC LIMIT ? OFFSET ?`
 	err = m.conn.QueryRowsCtx(ctx, &taskNodes, query, leaderID, pageSize, offset)
 	return taskNodes, total, err
 }
@@ -292,7 +295,7 @@ func (m *customTaskNodeModel) UpdateProgress(ctx context.Context, id string, pro
 }
 
 // UpdateActualHours 更新任务节点实际工时
-func (m *customTaskNodeModel) UpdateActualHours(ctx context.Context, id string, actualHours int) error {
+func (m *customTaskNodeModel) UpdateActualHours(ctx context.Context, id string, actualHours float64) error {
 	query := `UPDATE task_node SET actual_hours = ?, update_time = NOW() WHERE task_node_id = ? AND delete_time IS NULL`
 	_, err := m.conn.ExecCtx(ctx, query, actualHours, id)
 	return err
@@ -301,15 +304,13 @@ func (m *customTaskNodeModel) UpdateActualHours(ctx context.Context, id string,
 // UpdateExecutor 更新任务节点执行人
 func (m *customTaskNodeModel) UpdateExecutor(ctx context.Context, id, executorID string) error {
 	query := `UPDATE task_node SET executor_id = ?, update_time = NOW() WHERE task_node_id = ? AND delete_time IS NULL`
-	_, err := m.conn.ExecCtx(ctx, query, executorID, id)
-	return err
+	return m.updateRole(ctx, query, id, AssignmentExecutor, executorID)
 }
 
 // UpdateLeader 更新任务节点负责人
 func (m *customTaskNodeModel) UpdateLeader(ctx context.Context, id, leaderID string) error {
 	query := `UPDATE task_node SET leader_id = ?, update_time = NOW() WHERE task_node_id = ? AND delete_time IS NULL`
-	_, err := m.conn.ExecCtx(ctx, query, leaderID, id)
-	return err
+	return m.updateRole(ctx, query, id, AssignmentNodeLeader, leaderID)
 }
 
 // UpdateDeadline 更新任务节点截止时间
@@ -322,8 +323,12 @@ func (m *customTaskNodeModel) UpdateDeadline(ctx context.Context, id, deadline s
 // SoftDelete 软删除任务节点
 func (m *customTaskNodeModel) SoftDelete(ctx context.Context, id string) error {
 	query := `UPDATE task_node SET delete_time = NOW() WHERE task_node_id = ? AND delete_time IS NULL`
-	_, err := m.conn.ExecCtx(ctx, query, id)
-	return err
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, query, id); err != nil {
+			return err
+		}
+		return deleteNodeAssignments(ctx, session, id)
+	})
 }
 
 // BatchUpdateStatus 批量更新任务节点状态
@@ -381,7 +386,7 @@ func (m *customTaskNodeModel) GetTaskNodeCountByDepartment(ctx context.Context,
 func (m *customTaskNodeModel) GetTaskNodeCountByExecutor(ctx context.Context, executorID string) (int64, error) {
 	var count int64
 	// 支持多执行人
-	query := `SELECT COUNT(*) FROM task_node WHERE FIND_IN_SET(?, executor_id) AND delete_time IS NULL`
+	query := `SELECT COUNT(*) FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentExecutor) + `) AND delete_time IS NULL`
 	err := m.conn.QueryRowCtx(ctx, &count, query, executorID)
 	return count, err
 }
@@ -390,7 +395,7 @@ func (m *customTaskNodeModel) GetTaskNodeCountByExecutor(ctx context.Context, ex
 func (m *customTaskNodeModel) GetTaskNodeCountByLeader(ctx context.Context, leaderID string) (int64, error) {
 	var count int64
 	// 支持多负责人
-	query := `SELECT COUNT(*) FROM task_node WHERE FIND_IN_SET(?, leader_id) AND delete_time IS NULL`
+	query := `SELECT COUNT(*) FROM task_node WHERE task_node_id IN (` + assignmentSubquery(AssignmentNodeLeader) + `) AND delete_time IS NULL`
 	err := m.conn.QueryRowCtx(ctx, &count, query, leaderID)
 	return count, err
 }
@@ -401,7 +406,7 @@ func (m *customTaskNodeModel) GetTaskNodeCountByEmployee(ctx context.Context, em
 	var count int64
 	// 使用 DISTINCT 去重，统计该员工作为执行人或负责人的所有任务节点
 	query := `SELECT COUNT(DISTINCT task_node_id) FROM task_node 
-		WHERE (FIND_IN_SET(?, executor_id) OR FIND_IN_SET(?, leader_id)) 
+		WHERE (task_node_id IN (` + assignmentSubquery(AssignmentExecutor) + `) OR task_node_id IN (` + assignmentSubquery(AssignmentNodeLeader) + `)) 
 		AND delete_time IS NULL`
 	err := m.conn.QueryRowCtx(ctx, &count, query, employeeID, employeeID)
 	return count, err
@@ -424,28 +429,85 @@ func (m *customTaskNodeModel) InsertTask(ctx context.Context, data *TaskNode) (s
 		 node_finish_time, executor_id, leader_id, progress, node_priority, delete_time) 
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	ret, err := m.conn.ExecCtx(ctx, query,
-		data.TaskNodeId,     // 1
-		data.TaskId,         // 2
-		data.DepartmentId,   // 3
-		data.NodeName,       // 4
-		data.NodeDetail,     // 5
-		data.ExNodeIds,      // 6 - 这是生成的代码遗漏的字段
-		data.NodeDeadline,   // 7
-		data.NodeStartTime,  // 8
-		data.EstimatedDays,  // 9
-		data.ActualDays,     // 10
-		data.NodeStatus,     // 11
-		data.NodeFinishTime, // 12
-		data.ExecutorId,     // 13
-		data.LeaderId,       // 14
-		data.Progress,       // 15
-		data.NodePriority,   // 16
-		data.DeleteTime,     // 17
-	)
+	var ret sql.Result
+	err := transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		var err error
+		if ret, err = session.ExecCtx(ctx, query,
+			data.TaskNodeId,     // 1
+			data.TaskId,         // 2
+			data.DepartmentId,   // 3
+			data.NodeName,       // 4
+			data.NodeDetail,     // 5
+			data.ExNodeIds,      // 6 - 这是生成的代码遗漏的字段
+			data.NodeDeadline,   // 7
+			data.NodeStartTime,  // 8
+			data.EstimatedDays,  // 9
+			data.ActualDays,     // 10
+			data.NodeStatus,     // 11
+			data.NodeFinishTime, // 12
+			data.ExecutorId,     // 13
+			data.LeaderId,       // 14
+			data.Progress,       // 15
+			data.NodePriority,   // 16
+			data.DeleteTime,     // 17
+		); err != nil {
+			return err
+		}
+		return syncNodeAssignments(ctx, session, data)
+	})
+	return ret, err
+}
+
+// Insert 写入节点并在同一事务中同步节点分配关系
+func (m *customTaskNodeModel) Insert(ctx context.Context, data *TaskNode) (sql.Result, error) {
+	var ret sql.Result
+	err := transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		var err error
+		if ret, err = newTaskNodeModel(sqlx.NewSqlConnFromSession(session)).Insert(ctx, data); err != nil {
+			return err
+		}
+		return syncNodeAssignments(ctx, session, data)
+	})
 	return ret, err
 }
 
+// Update 更新节点并在同一事务中同步节点分配关系
+func (m *customTaskNodeModel) Update(ctx context.Context, data *TaskNode) error {
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if err := newTaskNodeModel(sqlx.NewSqlConnFromSession(session)).Update(ctx, data); err != nil {
+			return err
+		}
+		return syncNodeAssignments(ctx, session, data)
+	})
+}
+
+// Delete 删除节点及其分配关系
+func (m *customTaskNodeModel) Delete(ctx context.Context, taskNodeId string) error {
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if err := newTaskNodeModel(sqlx.NewSqlConnFromSession(session)).Delete(ctx, taskNodeId); err != nil {
+			return err
+		}
+		return deleteNodeAssignments(ctx, session, taskNodeId)
+	})
+}
+
+// updateRole 只更新节点的一个角色字段，并在同一事务中按节点所属任务同步该角色的分配关系
+func (m *customTaskNodeModel) updateRole(ctx context.Context, query, taskNodeId, role, idList string) error {
+	return transactAssignments(ctx, m.conn, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, query, idList, taskNodeId); err != nil {
+			return err
+		}
+		var taskId string
+		if err := session.QueryRowCtx(ctx, &taskId, "SELECT task_id FROM task_node WHERE task_node_id = ?", taskNodeId); err != nil {
+			if err == sqlx.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		return syncAssignments(ctx, session, taskId, taskNodeId, role, idList)
+	})
+}
+
 // UpdateChecklistCount 更新任务节点的清单统计数
 // 注意：需要先在数据库中添加 total_checklist_count 和 completed_checklist_count 字段
 func (m *customTaskNodeModel) UpdateChecklistCount(ctx context.Context, taskNodeId string, totalCount, completedCount int64) error {
@@ -461,3 +523,27 @@ func (m *customTaskNodeModel) GetCompletedNodeCountByTask(ctx context.Context, t
 	err := m.conn.QueryRowCtx(ctx, &count, query, taskID)
 	return count, err
 }
+
+// NodeWorkload 工作量计算用的未完成节点字段投影
+type NodeWorkload struct {
+	TaskNodeId    string    `db:"task_node_id"`
+	TaskId        string    `db:"task_id"`
+	NodeName      string    `db:"node_name"`
+	ExecutorId    string    `db:"executor_id"`
+	EstimatedDays int64     `db:"estimated_days"`
+	Progress      int64     `db:"progress"`
+	NodeStartTime time.Time `db:"node_start_time"`
+	NodeDeadline  time.Time `db:"node_deadline"`
+}
+
+// FindOpenWorkloadByCompany 查询公司全部未完成（未开始/进行中/已逾期）且有执行人的节点
+func (m *customTaskNodeModel) FindOpenWorkloadByCompany(ctx context.Context, companyID string) ([]*NodeWorkload, error) {
+	var nodes []*NodeWorkload
+	query := `SELECT n.task_node_id, n.task_id, n.node_name, n.executor_id, n.estimated_days, n.progress,
+        COALESCE(n.node_start_time, n.create_time) AS node_start_time, n.node_deadline
+        FROM task_node n JOIN task t ON t.task_id = n.task_id
+        WHERE t.company_id = ? AND t.delete_time IS NULL AND n.delete_time IS NULL
+        AND n.node_status IN (0, 1, 3) AND n.executor_id <> ''`
+	err := m.conn.QueryRowsCtx(ctx, &nodes, query, companyID)
+	return nodes, err
+}
@@ -0,0 +1,382 @@
+package task
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor 游标无法解析或与排序条件不匹配
+var ErrInvalidCursor = errors.New("invalid task search cursor")
+
+// 逾期条件
+const (
+	OverdueAny = 0 // 不限
+	OverdueYes = 1 // 已过截止时间且未完成
+	OverdueNo  = 2 // 未逾期
+)
+
+// 前置节点状态条件
+const (
+	PrerequisiteBlocked = "blocked" // 存在未完成节点在等待未完成的前置节点
+	PrerequisiteReady   = "ready"   // 没有被前置节点阻塞的节点
+)
+
+// TaskSearchFilter 任务高级搜索条件，列表类条件内部为“或”，不同条件之间为“且”
+type TaskSearchFilter struct {
+	CompanyId          string     // 公司ID（必填）
+	InvolvedEmployeeId string     // 只查询该员工参与的任务（创建者/负责人/节点执行人/节点负责人）
+	Keyword            string     // 标题或详情关键字
+	Statuses           []int64    // 任务状态
+	Priorities         []int64    // 任务优先级
+	TaskTypes          []int64    // 任务类型
+	AssigneeIds        []string   // 节点执行人
+	LeaderIds          []string   // 任务负责人
+	CreatorIds         []string   // 任务创建者
+	DepartmentIds      []string   // 涉及部门（任务涉及部门或节点所属部门）
+	DeadlineFrom       *time.Time // 截止时间下限（含）
+	DeadlineTo         *time.Time // 截止时间上限（含）
+	Overdue            int        // 逾期条件 OverdueAny/OverdueYes/OverdueNo
+	ProgressMin        *int64     // 进度下限（含）
+	ProgressMax        *int64     // 进度上限（含）
+	Prerequisite       string     // 前置节点状态 PrerequisiteBlocked/PrerequisiteReady
+	LabelIds           []string   // 任务标签
+	LabelMatchAll      bool       // 为 true 时需要包含全部标签，否则包含任一标签
+	CustomFields       []CustomFieldCondition
+}
+
+// CustomFieldCondition 自定义字段条件，设置的各项之间为“且”
+type CustomFieldCondition struct {
+	FieldId  string
+	Node     bool     // 节点字段：任一未删除的节点满足即可
+	Values   []string // 等于其中任一值（单选、员工）
+	AnyOf    []string // 多选字段包含其中任一选项
+	Contains string   // 文本包含
+	Numeric  bool     // Min/Max 按数字比较，否则按字符串比较（日期 YYYY-MM-DD）
+	Min      string   // 下限（含）
+	Max      string   // 上限（含）
+}
+
+// TaskSortField 排序字段
+type TaskSortField struct {
+	Field string // deadline/createTime/updateTime/priority/status/progress/title
+	Desc  bool
+}
+
+// taskSortKind 排序字段的值类型，决定游标中值的编码方式
+type taskSortKind int
+
+const (
+	sortKindInt taskSortKind = iota
+	sortKindTime
+	sortKindString
+)
+
+type taskSortColumn struct {
+	column string
+	kind   taskSortKind
+	value  func(t *Task) interface{}
+}
+
+// taskSortColumns 支持排序的字段
+var taskSortColumns = map[string]taskSortColumn{
+	"deadline":   {"t.task_deadline", sortKindTime, func(t *Task) interface{} { return t.TaskDeadline }},
+	"createTime": {"t.create_time", sortKindTime, func(t *Task) interface{} { return t.CreateTime }},
+	"updateTime": {"t.update_time", sortKindTime, func(t *Task) interface{} { return t.UpdateTime }},
+	"priority":   {"t.task_priority", sortKindInt, func(t *Task) interface{} { return t.TaskPriority }},
+	"status":     {"t.task_status", sortKindInt, func(t *Task) interface{} { return t.TaskStatus }},
+	"progress":   {"t.task_progress", sortKindInt, func(t *Task) interface{} { return t.TaskProgress }},
+	"title":      {"t.task_title", sortKindString, func(t *Task) interface{} { return t.TaskTitle }},
+}
+
+// IsTaskSortField 判断是否为支持排序的字段
+func IsTaskSortField(field string) bool {
+	_, ok := taskSortColumns[field]
+	return ok
+}
+
+// taskCursor 游标记录上一页最后一条任务的排序字段值，task_id 作为最终排序键保证顺序唯一
+type taskCursor struct {
+	Values []string `json:"v"`
+	TaskId string   `json:"id"`
+}
+
+// EncodeTaskCursor 根据上一页最后一条任务生成下一页游标
+func EncodeTaskCursor(last *Task, sorts []TaskSortField) string {
+	cursor := taskCursor{TaskId: last.TaskId, Values: make([]string, 0, len(sorts))}
+	for _, s := range sorts {
+		col := taskSortColumns[s.Field]
+		switch v := col.value(last).(type) {
+		case time.Time:
+			cursor.Values = append(cursor.Values, v.Format(time.RFC3339Nano))
+		case int64:
+			cursor.Values = append(cursor.Values, strconv.FormatInt(v, 10))
+		case string:
+			cursor.Values = append(cursor.Values, v)
+		}
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTaskCursor 解析游标中的排序字段值
+func decodeTaskCursor(raw string, sorts []TaskSortField) ([]interface{}, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	var cursor taskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.TaskId == "" || len(cursor.Values) != len(sorts) {
+		return nil, "", ErrInvalidCursor
+	}
+	values := make([]interface{}, 0, len(sorts))
+	for i, s := range sorts {
+		switch taskSortColumns[s.Field].kind {
+		case sortKindTime:
+			v, err := time.Parse(time.RFC3339Nano, cursor.Values[i])
+			if err != nil {
+				return nil, "", ErrInvalidCursor
+			}
+			values = append(values, v)
+		case sortKindInt:
+			v, err := strconv.ParseInt(cursor.Values[i], 10, 64)
+			if err != nil {
+				return nil, "", ErrInvalidCursor
+			}
+			values = append(values, v)
+		default:
+			values = append(values, cursor.Values[i])
+		}
+	}
+	return values, cursor.TaskId, nil
+}
+
+// buildSearchWhere 生成搜索条件的 WHERE 子句（任务表别名为 t）
+func buildSearchWhere(f *TaskSearchFilter) (string, []interface{}) {
+	conds := []string{"t.company_id = ?", "t.delete_time IS NULL"}
+	args := []interface{}{f.CompanyId}
+
+	if f.InvolvedEmployeeId != "" {
+		conds = append(conds, "(t.task_creator = ? OR t.task_id IN ("+involvedTaskSubquery()+"))")
+		args = append(args, f.InvolvedEmployeeId, f.InvolvedEmployeeId)
+	}
+	if f.Keyword != "" {
+		pattern := "%" + f.Keyword + "%"
+		conds = append(conds, "(t.task_title LIKE ? OR t.task_detail LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	if len(f.Statuses) > 0 {
+		conds = append(conds, "t.task_status IN ("+placeholders(len(f.Statuses))+")")
+		args = appendInts(args, f.Statuses)
+	}
+	if len(f.Priorities) > 0 {
+		conds = append(conds, "t.task_priority IN ("+placeholders(len(f.Priorities))+")")
+		args = appendInts(args, f.Priorities)
+	}
+	if len(f.TaskTypes) > 0 {
+		conds = append(conds, "t.task_type IN ("+placeholders(len(f.TaskTypes))+")")
+		args = appendInts(args, f.TaskTypes)
+	}
+	if len(f.AssigneeIds) > 0 {
+		conds = append(conds, "t.task_id IN (SELECT a.task_id FROM task_assignment a WHERE a.role = '"+AssignmentExecutor+
+			"' AND a.employee_id IN ("+placeholders(len(f.AssigneeIds))+"))")
+		args = appendStrings(args, f.AssigneeIds)
+	}
+	if len(f.LeaderIds) > 0 {
+		conds = append(conds, "t.leader_id IN ("+placeholders(len(f.LeaderIds))+")")
+		args = appendStrings(args, f.LeaderIds)
+	}
+	if len(f.CreatorIds) > 0 {
+		conds = append(conds, "t.task_creator IN ("+placeholders(len(f.CreatorIds))+")")
+		args = appendStrings(args, f.CreatorIds)
+	}
+	if len(f.DepartmentIds) > 0 {
+		match := strings.Repeat("FIND_IN_SET(?, t.department_ids) OR ", len(f.DepartmentIds))
+		conds = append(conds, "("+match+"EXISTS (SELECT 1 FROM task_node tn WHERE tn.task_id = t.task_id AND tn.delete_time IS NULL AND tn.department_id IN ("+placeholders(len(f.DepartmentIds))+")))")
+		args = appendStrings(args, f.DepartmentIds)
+		args = appendStrings(args, f.DepartmentIds)
+	}
+	if f.DeadlineFrom != nil {
+		conds = append(conds, "t.task_deadline >= ?")
+		args = append(args, *f.DeadlineFrom)
+	}
+	if f.DeadlineTo != nil {
+		conds = append(conds, "t.task_deadline <= ?")
+		args = append(args, *f.DeadlineTo)
+	}
+	switch f.Overdue {
+	case OverdueYes:
+		conds = append(conds, "t.task_status IN (0, 1) AND t.task_deadline < NOW()")
+	case OverdueNo:
+		conds = append(conds, "NOT (t.task_status IN (0, 1) AND t.task_deadline < NOW())")
+	}
+	if f.ProgressMin != nil {
+		conds = append(conds, "t.task_progress >= ?")
+		args = append(args, *f.ProgressMin)
+	}
+	if f.ProgressMax != nil {
+		conds = append(conds, "t.task_progress <= ?")
+		args = append(args, *f.ProgressMax)
+	}
+	// 节点未完成，且前置节点中存在未完成（状态不为已完成）的节点
+	blocked := `EXISTS (SELECT 1 FROM task_node n WHERE n.task_id = t.task_id AND n.delete_time IS NULL AND n.node_status <> 2
+            AND EXISTS (SELECT 1 FROM task_node p WHERE p.task_id = n.task_id AND p.delete_time IS NULL
+                AND p.node_status <> 2 AND FIND_IN_SET(p.task_node_id, n.ex_node_ids)))`
+	switch f.Prerequisite {
+	case PrerequisiteBlocked:
+		conds = append(conds, blocked)
+	case PrerequisiteReady:
+		conds = append(conds, "NOT "+blocked)
+	}
+	if len(f.LabelIds) > 0 {
+		in := "SELECT %s FROM task_label_relation lr WHERE lr.task_id = t.task_id AND lr.label_id IN (" + placeholders(len(f.LabelIds)) + ")"
+		if f.LabelMatchAll {
+			conds = append(conds, "("+fmt.Sprintf(in, "COUNT(*)")+") = ?")
+			args = appendStrings(args, f.LabelIds)
+			args = append(args, len(f.LabelIds))
+		} else {
+			conds = append(conds, "EXISTS ("+fmt.Sprintf(in, "1")+")")
+			args = appendStrings(args, f.LabelIds)
+		}
+	}
+	for _, c := range f.CustomFields {
+		cond, condArgs := buildCustomFieldWhere(c)
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// buildCustomFieldWhere 生成自定义字段条件：任务字段匹配任务本身，节点字段匹配任一未删除的节点
+func buildCustomFieldWhere(c CustomFieldCondition) (string, []interface{}) {
+	match := []string{"cv.field_id = ?"}
+	args := []interface{}{c.FieldId}
+	if len(c.Values) > 0 {
+		match = append(match, "cv.value IN ("+placeholders(len(c.Values))+")")
+		args = appendStrings(args, c.Values)
+	}
+	if len(c.AnyOf) > 0 {
+		match = append(match, "("+strings.TrimSuffix(strings.Repeat("FIND_IN_SET(?, cv.value) OR ", len(c.AnyOf)), " OR ")+")")
+		args = appendStrings(args, c.AnyOf)
+	}
+	if c.Contains != "" {
+		match = append(match, "cv.value LIKE ?")
+		args = append(args, "%"+c.Contains+"%")
+	}
+	value := "cv.value"
+	if c.Numeric {
+		value = "CAST(cv.value AS DECIMAL(30, 6))"
+	}
+	if c.Min != "" {
+		match = append(match, value+" >= ?")
+		args = append(args, c.Min)
+	}
+	if c.Max != "" {
+		match = append(match, value+" <= ?")
+		args = append(args, c.Max)
+	}
+	if c.Node {
+		return "EXISTS (SELECT 1 FROM task_node cn JOIN custom_field_value cv ON cv.entity_id = cn.task_node_id WHERE cn.task_id = t.task_id AND cn.delete_time IS NULL AND " +
+			strings.Join(match, " AND ") + ")", args
+	}
+	return "EXISTS (SELECT 1 FROM custom_field_value cv WHERE cv.entity_id = t.task_id AND " + strings.Join(match, " AND ") + ")", args
+}
+
+// buildCursorWhere 生成游标条件：排序字段依次比较，相等时比较下一个字段，最后比较 task_id
+func buildCursorWhere(sorts []TaskSortField, values []interface{}, lastID string) (string, []interface{}) {
+	columns := make([]string, 0, len(sorts)+1)
+	ops := make([]string, 0, len(sorts)+1)
+	for _, s := range sorts {
+		columns = append(columns, taskSortColumns[s.Field].column)
+		if s.Desc {
+			ops = append(ops, "<")
+		} else {
+			ops = append(ops, ">")
+		}
+	}
+	columns = append(columns, "t.task_id")
+	ops = append(ops, ">")
+	values = append(values, lastID)
+
+	var branches []string
+	var args []interface{}
+	for i := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			args = append(args, values[j])
+		}
+		parts = append(parts, columns[i]+" "+ops[i]+" ?")
+		args = append(args, values[i])
+		branches = append(branches, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
+
+// SearchByFilter 按条件和排序查询任务，cursor 为空时从第一条开始，最多返回 limit 条
+func (m *customTaskModel) SearchByFilter(ctx context.Context, filter *TaskSearchFilter, sorts []TaskSortField, cursor string, limit int) ([]*Task, error) {
+	where, args := buildSearchWhere(filter)
+	if cursor != "" {
+		values, lastID, err := decodeTaskCursor(cursor, sorts)
+		if err != nil {
+			return nil, err
+		}
+		cursorWhere, cursorArgs := buildCursorWhere(sorts, values, lastID)
+		where += " AND " + cursorWhere
+		args = append(args, cursorArgs...)
+	}
+
+	orders := make([]string, 0, len(sorts)+1)
+	for _, s := range sorts {
+		order := taskSortColumns[s.Field].column + " ASC"
+		if s.Desc {
+			order = taskSortColumns[s.Field].column + " DESC"
+		}
+		orders = append(orders, order)
+	}
+	orders = append(orders, "t.task_id ASC")
+
+	var tasks []*Task
+	query := fmt.Sprintf("SELECT t.* FROM task t WHERE %s ORDER BY %s LIMIT ?", where, strings.Join(orders, ", "))
+	err := m.conn.QueryRowsCtx(ctx, &tasks, query, append(args, limit)...)
+	return tasks, err
+}
+
+// CountByFilter 统计满足条件的任务数（不含游标条件）
+func (m *customTaskModel) CountByFilter(ctx context.Context, filter *TaskSearchFilter) (int64, error) {
+	where, args := buildSearchWhere(filter)
+	var total int64
+	err := m.conn.QueryRowCtx(ctx, &total, "SELECT COUNT(*) FROM task t WHERE "+where, args...)
+	return total, err
+}
+
+// FindInvolvedIDs 员工在公司内参与的全部任务ID，用于限定全文搜索范围
+func (m *customTaskModel) FindInvolvedIDs(ctx context.Context, companyId, employeeId string) ([]string, error) {
+	where, args := buildSearchWhere(&TaskSearchFilter{CompanyId: companyId, InvolvedEmployeeId: employeeId})
+	var ids []string
+	err := m.conn.QueryRowsCtx(ctx, &ids, "SELECT t.task_id FROM task t WHERE "+where, args...)
+	return ids, err
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func appendInts(args []interface{}, values []int64) []interface{} {
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
+
+func appendStrings(args []interface{}, values []string) []interface{} {
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// TaskView 保存的任务搜索视图
+type TaskView struct {
+	Id         string    `db:"id"`          // 视图ID
+	CompanyId  string    `db:"company_id"`  // 公司ID
+	EmployeeId string    `db:"employee_id"` // 创建人员工ID
+	Name       string    `db:"name"`        // 视图名称
+	Shared     int64     `db:"shared"`      // 是否共享 0-个人 1-公司内共享
+	Filter     string    `db:"filter"`      // 搜索条件（JSON）
+	Sort       string    `db:"sort"`        // 排序字段（JSON）
+	CreateTime time.Time `db:"create_time"` // 创建时间
+	UpdateTime time.Time `db:"update_time"` // 更新时间
+}
+
+const taskViewRows = "`id`, `company_id`, `employee_id`, `name`, `shared`, `filter`, `sort`, `create_time`, `update_time`"
+
+type TaskViewModel interface {
+	Insert(ctx context.Context, data *TaskView) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*TaskView, error)
+	Update(ctx context.Context, data *TaskView) error
+	Delete(ctx context.Context, id string) error
+	// FindVisible 员工可用的视图：自己创建的和公司内共享的
+	FindVisible(ctx context.Context, companyId, employeeId string) ([]*TaskView, error)
+	CountByEmployee(ctx context.Context, employeeId string) (int64, error)
+}
+
+type defaultTaskViewModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTaskViewModel(conn sqlx.SqlConn) TaskViewModel {
+	return &defaultTaskViewModel{
+		conn:  conn,
+		table: "`task_view`",
+	}
+}
+
+func (m *defaultTaskViewModel) Insert(ctx context.Context, data *TaskView) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, taskViewRows)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.Name, data.Shared, data.Filter, data.Sort, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultTaskViewModel) FindOne(ctx context.Context, id string) (*TaskView, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", taskViewRows, m.table)
+	var resp TaskView
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskViewModel) Update(ctx context.Context, data *TaskView) error {
+	query := fmt.Sprintf("UPDATE %s SET `name` = ?, `shared` = ?, `filter` = ?, `sort` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Name, data.Shared, data.Filter, data.Sort, time.Now(), data.Id)
+	return err
+}
+
+func (m *defaultTaskViewModel) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+// FindVisible 查询员工自己的视图和公司内共享的视图，自己的排在前面
+func (m *defaultTaskViewModel) FindVisible(ctx context.Context, companyId, employeeId string) ([]*TaskView, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND (`employee_id` = ? OR `shared` = 1) ORDER BY (`employee_id` = ?) DESC, `create_time` ASC", taskViewRows, m.table)
+	var resp []*TaskView
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId, employeeId, employeeId)
+	return resp, err
+}
+
+func (m *defaultTaskViewModel) CountByEmployee(ctx context.Context, employeeId string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `employee_id` = ?", m.table)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, employeeId)
+	return count, err
+}
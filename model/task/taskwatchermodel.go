@@ -0,0 +1,152 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 关注来源
+const (
+	WatchSourceManual   = "manual"   // 主动关注
+	WatchSourceComment  = "comment"  // 发表评论
+	WatchSourceMention  = "mention"  // 被@提及
+	WatchSourceAssigned = "assigned" // 被分配到任务或节点
+)
+
+// 关注状态
+const (
+	WatchStatusUnwatched = 0 // 已取消关注，不再自动关注
+	WatchStatusWatching  = 1 // 关注中
+)
+
+// TaskWatcher 任务关注，员工关注整个任务（节点ID为空）或单个节点。
+// 取消关注保留记录并置为已取消，自动关注不会覆盖员工主动取消的关注
+type TaskWatcher struct {
+	Id         int64     `db:"id"`           // 关注ID
+	TaskId     string    `db:"task_id"`      // 任务ID
+	TaskNodeId string    `db:"task_node_id"` // 任务节点ID，关注整个任务时为空
+	EmployeeId string    `db:"employee_id"`  // 员工ID
+	Source     string    `db:"source"`       // 来源
+	Status     int64     `db:"status"`       // 状态
+	CreateTime time.Time `db:"create_time"`  // 创建时间
+	UpdateTime time.Time `db:"update_time"`  // 更新时间
+}
+
+const taskWatcherRows = "`id`, `task_id`, `task_node_id`, `employee_id`, `source`, `status`, `create_time`, `update_time`"
+
+type TaskWatcherModel interface {
+	// FindOne 员工对任务或节点的关注记录，没有记录时返回 ErrNotFound
+	FindOne(ctx context.Context, taskId, taskNodeId, employeeId string) (*TaskWatcher, error)
+	// FindWatching 关注任务或节点的员工，不含已取消关注的
+	FindWatching(ctx context.Context, taskId, taskNodeId string) ([]*TaskWatcher, error)
+	// FindRecipientIds 接收变更通知的关注者：taskNodeId 为空时为任务及其全部节点的关注者，
+	// 否则为该节点和整个任务的关注者
+	FindRecipientIds(ctx context.Context, taskId, taskNodeId string) ([]string, error)
+	// Watch 主动关注，已取消的关注重新生效
+	Watch(ctx context.Context, taskId, taskNodeId, employeeId string) error
+	// Unwatch 取消关注，没有关注记录时也写入已取消状态，阻止之后的自动关注
+	Unwatch(ctx context.Context, taskId, taskNodeId, employeeId string) error
+	// AutoWatch 自动关注，已有关注记录（包括已取消的）保持不变
+	AutoWatch(ctx context.Context, taskId, taskNodeId, source string, employeeIds ...string) error
+	// WatchAssignees 任务及其节点当前的分配人员自动关注对应的任务或节点，已有关注记录（包括已取消的）保持不变
+	WatchAssignees(ctx context.Context, taskId string) error
+}
+
+type defaultTaskWatcherModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTaskWatcherModel(conn sqlx.SqlConn) TaskWatcherModel {
+	return &defaultTaskWatcherModel{
+		conn:  conn,
+		table: "`task_watcher`",
+	}
+}
+
+func (m *defaultTaskWatcherModel) FindOne(ctx context.Context, taskId, taskNodeId, employeeId string) (*TaskWatcher, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_id` = ? AND `task_node_id` = ? AND `employee_id` = ? LIMIT 1", taskWatcherRows, m.table)
+	var resp TaskWatcher
+	err := m.conn.QueryRowCtx(ctx, &resp, query, taskId, taskNodeId, employeeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultTaskWatcherModel) FindWatching(ctx context.Context, taskId, taskNodeId string) ([]*TaskWatcher, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `task_id` = ? AND `task_node_id` = ? AND `status` = %d ORDER BY `create_time` ASC, `id` ASC",
+		taskWatcherRows, m.table, WatchStatusWatching)
+	var resp []*TaskWatcher
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, taskId, taskNodeId)
+	return resp, err
+}
+
+func (m *defaultTaskWatcherModel) FindRecipientIds(ctx context.Context, taskId, taskNodeId string) ([]string, error) {
+	query := fmt.Sprintf("SELECT DISTINCT `employee_id` FROM %s WHERE `task_id` = ? AND `status` = %d", m.table, WatchStatusWatching)
+	args := []interface{}{taskId}
+	if taskNodeId != "" {
+		query += " AND `task_node_id` IN ('', ?)"
+		args = append(args, taskNodeId)
+	}
+	var resp []string
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+func (m *defaultTaskWatcherModel) Watch(ctx context.Context, taskId, taskNodeId, employeeId string) error {
+	return m.setStatus(ctx, taskId, taskNodeId, employeeId, WatchStatusWatching)
+}
+
+func (m *defaultTaskWatcherModel) Unwatch(ctx context.Context, taskId, taskNodeId, employeeId string) error {
+	return m.setStatus(ctx, taskId, taskNodeId, employeeId, WatchStatusUnwatched)
+}
+
+// setStatus 写入员工主动设置的关注状态，来源记为主动关注
+func (m *defaultTaskWatcherModel) setStatus(ctx context.Context, taskId, taskNodeId, employeeId string, status int) error {
+	query := fmt.Sprintf("INSERT INTO %s (`task_id`, `task_node_id`, `employee_id`, `source`, `status`) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `source` = VALUES(`source`), `status` = VALUES(`status`)", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, taskId, taskNodeId, employeeId, WatchSourceManual, status)
+	return err
+}
+
+func (m *defaultTaskWatcherModel) AutoWatch(ctx context.Context, taskId, taskNodeId, source string, employeeIds ...string) error {
+	return autoWatch(ctx, m.conn, taskId, taskNodeId, source, employeeIds)
+}
+
+func (m *defaultTaskWatcherModel) WatchAssignees(ctx context.Context, taskId string) error {
+	query := fmt.Sprintf("INSERT IGNORE INTO %s (`task_id`, `task_node_id`, `employee_id`, `source`) "+
+		"SELECT DISTINCT `task_id`, `task_node_id`, `employee_id`, ? FROM `task_assignment` WHERE `task_id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, WatchSourceAssigned, taskId)
+	return err
+}
+
+// autoWatch 批量自动关注
+func autoWatch(ctx context.Context, session sqlx.Session, taskId, taskNodeId, source string, employeeIds []string) error {
+	if taskId == "" || len(employeeIds) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(employeeIds))
+	args := make([]interface{}, 0, len(employeeIds)*4)
+	for _, id := range employeeIds {
+		if id == "" {
+			continue
+		}
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, taskId, taskNodeId, id, source)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	query := "INSERT IGNORE INTO `task_watcher` (`task_id`, `task_node_id`, `employee_id`, `source`) VALUES " + strings.Join(values, ", ")
+	_, err := session.ExecCtx(ctx, query, args...)
+	return err
+}
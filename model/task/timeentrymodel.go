@@ -0,0 +1,232 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// TimeEntry 工时记录（计时器或手工填报）
+type TimeEntry struct {
+	Id              string         `db:"id"`               // 工时记录ID
+	CompanyId       string         `db:"company_id"`       // 公司ID
+	TaskId          string         `db:"task_id"`          // 任务ID
+	TaskNodeId      string         `db:"task_node_id"`     // 任务节点ID
+	ChecklistId     sql.NullString `db:"checklist_id"`     // 关联的任务清单ID
+	EmployeeId      string         `db:"employee_id"`      // 填报员工ID
+	EntryType       int64          `db:"entry_type"`       // 来源 0-计时器 1-手工填报
+	WorkDate        time.Time      `db:"work_date"`        // 工作日期
+	StartTime       sql.NullTime   `db:"start_time"`       // 计时开始时间
+	EndTime         sql.NullTime   `db:"end_time"`         // 计时结束时间，计时中为空
+	DurationMinutes int64          `db:"duration_minutes"` // 时长（分钟）
+	Note            sql.NullString `db:"note"`             // 备注
+	TimesheetId     sql.NullString `db:"timesheet_id"`     // 所属周工时单ID
+	CreateTime      time.Time      `db:"create_time"`      // 创建时间
+	UpdateTime      time.Time      `db:"update_time"`      // 更新时间
+	DeleteTime      sql.NullTime   `db:"delete_time"`      // 删除时间
+}
+
+// 工时记录来源
+const (
+	TimeEntryTypeTimer  = 0 // 计时器
+	TimeEntryTypeManual = 1 // 手工填报
+)
+
+// Running 是否为计时中的记录
+func (e *TimeEntry) Running() bool {
+	return e.EntryType == TimeEntryTypeTimer && !e.EndTime.Valid
+}
+
+// TimeEntryFilter 工时记录查询条件，零值字段不参与过滤
+type TimeEntryFilter struct {
+	CompanyId  string
+	EmployeeId string
+	TaskId     string
+	TaskNodeId string
+	StartDate  time.Time
+	EndDate    time.Time
+}
+
+// TimeEntryNodeStat 员工在某个节点上的工时汇总（用于预计与实际工时对比）
+type TimeEntryNodeStat struct {
+	EmployeeId    string `db:"employee_id"`
+	TaskNodeId    string `db:"task_node_id"`
+	DepartmentId  string `db:"department_id"`
+	EstimatedDays int64  `db:"estimated_days"`
+	Minutes       int64  `db:"minutes"`
+}
+
+const timeEntryRows = "`id`, `company_id`, `task_id`, `task_node_id`, `checklist_id`, `employee_id`, `entry_type`, `work_date`, `start_time`, `end_time`, `duration_minutes`, `note`, `timesheet_id`, `create_time`, `update_time`, `delete_time`"
+
+// 已结束（计入工时）的记录：手工填报或已停止的计时器
+const timeEntryFinished = "`delete_time` IS NULL AND (`entry_type` = 1 OR `end_time` IS NOT NULL)"
+
+type TimeEntryModel interface {
+	Insert(ctx context.Context, data *TimeEntry) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*TimeEntry, error)
+	Update(ctx context.Context, data *TimeEntry) error
+	SoftDelete(ctx context.Context, id string) error
+	FindRunningByEmployee(ctx context.Context, employeeId string) (*TimeEntry, error)
+	Search(ctx context.Context, filter TimeEntryFilter, page, pageSize int) ([]*TimeEntry, int64, error)
+	FindByEmployeeRange(ctx context.Context, employeeId string, start, end time.Time) ([]*TimeEntry, error)
+	SumMinutesByNode(ctx context.Context, taskNodeId string) (int64, error)
+	SumMinutesByTask(ctx context.Context, taskId string) (int64, error)
+	BindTimesheet(ctx context.Context, employeeId string, start, end time.Time, timesheetId string) error
+	StatByNode(ctx context.Context, companyId string, start, end time.Time) ([]*TimeEntryNodeStat, error)
+}
+
+type defaultTimeEntryModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTimeEntryModel(conn sqlx.SqlConn) TimeEntryModel {
+	return &defaultTimeEntryModel{
+		conn:  conn,
+		table: "`time_entry`",
+	}
+}
+
+func (m *defaultTimeEntryModel) Insert(ctx context.Context, data *TimeEntry) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `task_id`, `task_node_id`, `checklist_id`, `employee_id`, `entry_type`, `work_date`, `start_time`, `end_time`, `duration_minutes`, `note`, `timesheet_id`, `create_time`, `update_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.TaskId, data.TaskNodeId, data.ChecklistId, data.EmployeeId, data.EntryType, data.WorkDate, data.StartTime, data.EndTime, data.DurationMinutes, data.Note, data.TimesheetId, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultTimeEntryModel) FindOne(ctx context.Context, id string) (*TimeEntry, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? AND `delete_time` IS NULL LIMIT 1", timeEntryRows, m.table)
+	var resp TimeEntry
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Update 更新工时记录的时间、时长、备注和关联清单
+func (m *defaultTimeEntryModel) Update(ctx context.Context, data *TimeEntry) error {
+	query := fmt.Sprintf("UPDATE %s SET `checklist_id` = ?, `work_date` = ?, `start_time` = ?, `end_time` = ?, `duration_minutes` = ?, `note` = ?, `timesheet_id` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.ChecklistId, data.WorkDate, data.StartTime, data.EndTime, data.DurationMinutes, data.Note, data.TimesheetId, time.Now(), data.Id)
+	return err
+}
+
+func (m *defaultTimeEntryModel) SoftDelete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("UPDATE %s SET `delete_time` = NOW() WHERE `id` = ? AND `delete_time` IS NULL", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+// FindRunningByEmployee 查询员工正在计时的记录（同一时间只允许一个计时器）
+func (m *defaultTimeEntryModel) FindRunningByEmployee(ctx context.Context, employeeId string) (*TimeEntry, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `entry_type` = 0 AND `end_time` IS NULL AND `delete_time` IS NULL ORDER BY `start_time` DESC LIMIT 1", timeEntryRows, m.table)
+	var resp TimeEntry
+	err := m.conn.QueryRowCtx(ctx, &resp, query, employeeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Search 按条件分页查询工时记录，按工作日期倒序
+func (m *defaultTimeEntryModel) Search(ctx context.Context, filter TimeEntryFilter, page, pageSize int) ([]*TimeEntry, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	where, args := filter.build()
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", m.table, where)
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY `work_date` DESC, `create_time` DESC LIMIT ? OFFSET ?", timeEntryRows, m.table, where)
+	var resp []*TimeEntry
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, append(args, pageSize, (page-1)*pageSize)...)
+	return resp, total, err
+}
+
+// FindByEmployeeRange 查询员工在日期范围内的全部工时记录（含计时中）
+func (m *defaultTimeEntryModel) FindByEmployeeRange(ctx context.Context, employeeId string, start, end time.Time) ([]*TimeEntry, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `work_date` >= ? AND `work_date` <= ? AND `delete_time` IS NULL ORDER BY `work_date`, `create_time`", timeEntryRows, m.table)
+	var resp []*TimeEntry
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, employeeId, start, end)
+	return resp, err
+}
+
+// SumMinutesByNode 汇总节点的已记录工时（分钟）
+func (m *defaultTimeEntryModel) SumMinutesByNode(ctx context.Context, taskNodeId string) (int64, error) {
+	query := fmt.Sprintf("SELECT COALESCE(SUM(`duration_minutes`), 0) FROM %s WHERE `task_node_id` = ? AND %s", m.table, timeEntryFinished)
+	var total int64
+	err := m.conn.QueryRowCtx(ctx, &total, query, taskNodeId)
+	return total, err
+}
+
+// SumMinutesByTask 汇总任务的已记录工时（分钟）
+func (m *defaultTimeEntryModel) SumMinutesByTask(ctx context.Context, taskId string) (int64, error) {
+	query := fmt.Sprintf("SELECT COALESCE(SUM(`duration_minutes`), 0) FROM %s WHERE `task_id` = ? AND %s", m.table, timeEntryFinished)
+	var total int64
+	err := m.conn.QueryRowCtx(ctx, &total, query, taskId)
+	return total, err
+}
+
+// BindTimesheet 将员工在日期范围内已结束的工时记录归入周工时单
+func (m *defaultTimeEntryModel) BindTimesheet(ctx context.Context, employeeId string, start, end time.Time, timesheetId string) error {
+	query := fmt.Sprintf("UPDATE %s SET `timesheet_id` = ? WHERE `employee_id` = ? AND `work_date` >= ? AND `work_date` <= ? AND %s", m.table, timeEntryFinished)
+	_, err := m.conn.ExecCtx(ctx, query, timesheetId, employeeId, start, end)
+	return err
+}
+
+// StatByNode 按员工和节点汇总公司在日期范围内的工时，附带节点的预计天数和所属部门
+func (m *defaultTimeEntryModel) StatByNode(ctx context.Context, companyId string, start, end time.Time) ([]*TimeEntryNodeStat, error) {
+	query := fmt.Sprintf("SELECT e.`employee_id`, e.`task_node_id`, n.`department_id`, n.`estimated_days`, SUM(e.`duration_minutes`) AS `minutes` "+
+		"FROM %s e JOIN `task_node` n ON n.`task_node_id` = e.`task_node_id` "+
+		"WHERE e.`company_id` = ? AND e.`work_date` >= ? AND e.`work_date` <= ? AND e.`delete_time` IS NULL AND (e.`entry_type` = 1 OR e.`end_time` IS NOT NULL) "+
+		"GROUP BY e.`employee_id`, e.`task_node_id`, n.`department_id`, n.`estimated_days`", m.table)
+	var resp []*TimeEntryNodeStat
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId, start, end)
+	return resp, err
+}
+
+// build 生成 WHERE 子句和参数
+func (f TimeEntryFilter) build() (string, []interface{}) {
+	conds := []string{"`delete_time` IS NULL"}
+	args := make([]interface{}, 0, 6)
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if f.CompanyId != "" {
+		add("`company_id` = ?", f.CompanyId)
+	}
+	if f.EmployeeId != "" {
+		add("`employee_id` = ?", f.EmployeeId)
+	}
+	if f.TaskId != "" {
+		add("`task_id` = ?", f.TaskId)
+	}
+	if f.TaskNodeId != "" {
+		add("`task_node_id` = ?", f.TaskNodeId)
+	}
+	if !f.StartDate.IsZero() {
+		add("`work_date` >= ?", f.StartDate)
+	}
+	if !f.EndDate.IsZero() {
+		add("`work_date` <= ?", f.EndDate)
+	}
+	return strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,121 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Timesheet 周工时单
+type Timesheet struct {
+	Id           string         `db:"id"`            // 工时单ID
+	CompanyId    string         `db:"company_id"`    // 公司ID
+	EmployeeId   string         `db:"employee_id"`   // 员工ID
+	WeekStart    time.Time      `db:"week_start"`    // 周一日期
+	TotalMinutes int64          `db:"total_minutes"` // 提交时的总时长（分钟）
+	Status       int64          `db:"status"`        // 状态 1-待审批 2-已通过 3-已驳回
+	ApproverId   sql.NullString `db:"approver_id"`   // 审批人员工ID
+	ApproveNote  sql.NullString `db:"approve_note"`  // 审批意见
+	SubmitTime   sql.NullTime   `db:"submit_time"`   // 提交时间
+	ApproveTime  sql.NullTime   `db:"approve_time"`  // 审批时间
+	CreateTime   time.Time      `db:"create_time"`   // 创建时间
+	UpdateTime   time.Time      `db:"update_time"`   // 更新时间
+}
+
+// 周工时单状态
+const (
+	TimesheetStatusPending  = 1 // 待审批
+	TimesheetStatusApproved = 2 // 已通过
+	TimesheetStatusRejected = 3 // 已驳回
+)
+
+// Locked 工时单待审批或已通过时，该周的工时记录不允许再修改
+func (t *Timesheet) Locked() bool {
+	return t.Status == TimesheetStatusPending || t.Status == TimesheetStatusApproved
+}
+
+const timesheetRows = "`id`, `company_id`, `employee_id`, `week_start`, `total_minutes`, `status`, `approver_id`, `approve_note`, `submit_time`, `approve_time`, `create_time`, `update_time`"
+
+type TimesheetModel interface {
+	Insert(ctx context.Context, data *Timesheet) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*Timesheet, error)
+	Update(ctx context.Context, data *Timesheet) error
+	FindByEmployeeWeek(ctx context.Context, employeeId string, weekStart time.Time) (*Timesheet, error)
+	FindByEmployee(ctx context.Context, employeeId string, limit int) ([]*Timesheet, error)
+	FindPendingByApprover(ctx context.Context, approverId string) ([]*Timesheet, error)
+}
+
+type defaultTimesheetModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewTimesheetModel(conn sqlx.SqlConn) TimesheetModel {
+	return &defaultTimesheetModel{
+		conn:  conn,
+		table: "`timesheet`",
+	}
+}
+
+func (m *defaultTimesheetModel) Insert(ctx context.Context, data *Timesheet) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `employee_id`, `week_start`, `total_minutes`, `status`, `approver_id`, `approve_note`, `submit_time`, `approve_time`, `create_time`, `update_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.WeekStart, data.TotalMinutes, data.Status, data.ApproverId, data.ApproveNote, data.SubmitTime, data.ApproveTime, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultTimesheetModel) FindOne(ctx context.Context, id string) (*Timesheet, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", timesheetRows, m.table)
+	var resp Timesheet
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Update 更新工时单的提交与审批信息
+func (m *defaultTimesheetModel) Update(ctx context.Context, data *Timesheet) error {
+	query := fmt.Sprintf("UPDATE %s SET `total_minutes` = ?, `status` = ?, `approver_id` = ?, `approve_note` = ?, `submit_time` = ?, `approve_time` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.TotalMinutes, data.Status, data.ApproverId, data.ApproveNote, data.SubmitTime, data.ApproveTime, time.Now(), data.Id)
+	return err
+}
+
+// FindByEmployeeWeek 查询员工某一周的工时单
+func (m *defaultTimesheetModel) FindByEmployeeWeek(ctx context.Context, employeeId string, weekStart time.Time) (*Timesheet, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `week_start` = ? LIMIT 1", timesheetRows, m.table)
+	var resp Timesheet
+	err := m.conn.QueryRowCtx(ctx, &resp, query, employeeId, weekStart)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByEmployee 查询员工最近的工时单
+func (m *defaultTimesheetModel) FindByEmployee(ctx context.Context, employeeId string, limit int) ([]*Timesheet, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? ORDER BY `week_start` DESC LIMIT ?", timesheetRows, m.table)
+	var resp []*Timesheet
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, employeeId, limit)
+	return resp, err
+}
+
+// FindPendingByApprover 查询待指定审批人审批的工时单
+func (m *defaultTimesheetModel) FindPendingByApprover(ctx context.Context, approverId string) ([]*Timesheet, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `approver_id` = ? AND `status` = ? ORDER BY `submit_time` ASC", timesheetRows, m.table)
+	var resp []*Timesheet
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, approverId, TimesheetStatusPending)
+	return resp, err
+}
@@ -1,6 +1,12 @@
 package upload
 
-import "github.com/zeromicro/go-zero/core/stores/mon"
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
 
 var _ Attachment_commentModel = (*customAttachment_commentModel)(nil)
 
@@ -9,6 +15,10 @@ type (
 	// and implement the added methods in customAttachment_commentModel.
 	Attachment_commentModel interface {
 		attachment_commentModel
+		// FindByFileVersion 附件某个版本上的评论，没有版本字段的旧评论属于第 1 版
+		FindByFileVersion(ctx context.Context, fileID string, version, page, pageSize int64) ([]*Attachment_comment, int64, error)
+		// CountByFileVersion 附件各版本的评论数
+		CountByFileVersion(ctx context.Context, fileID string) (map[int64]int64, error)
 	}
 
 	customAttachment_commentModel struct {
@@ -24,3 +34,48 @@ func NewAttachment_commentModel(url, db, collection string) Attachment_commentMo
 	}
 }
 
+func (m *customAttachment_commentModel) FindByFileVersion(ctx context.Context, fileID string, version, page, pageSize int64) ([]*Attachment_comment, int64, error) {
+	filter := bson.M{"fileId": fileID, "fileVersion": version, "isDeleted": bson.M{"$ne": true}}
+	if version <= 1 {
+		filter["fileVersion"] = bson.M{"$in": bson.A{0, 1, nil}}
+	}
+
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (page - 1) * pageSize
+	opts := options.Find().SetSort(bson.M{"createAt": -1}).SetSkip(skip).SetLimit(pageSize)
+
+	var data []*Attachment_comment
+	if err := m.conn.Find(ctx, &data, filter, opts); err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+func (m *customAttachment_commentModel) CountByFileVersion(ctx context.Context, fileID string) (map[int64]int64, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"fileId": fileID, "isDeleted": bson.M{"$ne": true}}},
+		bson.M{"$group": bson.M{"_id": bson.M{"$ifNull": bson.A{"$fileVersion", 1}}, "count": bson.M{"$sum": 1}}},
+	}
+
+	var rows []struct {
+		Version int64 `bson:"_id"`
+		Count   int64 `bson:"count"`
+	}
+	if err := m.conn.Aggregate(ctx, &rows, pipeline); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		v := row.Version
+		if v <= 0 {
+			v = 1
+		}
+		counts[v] += row.Count
+	}
+	return counts, nil
+}
@@ -12,12 +12,15 @@ type Attachment_comment struct {
 	ID              bson.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
 	CommentID       string          `bson:"commentId" json:"commentId" index:"commentId"`             // 评论ID
 	FileID          string          `bson:"fileId" json:"fileId" index:"fileId"`                      // 附件文件ID
+	FileVersion     int64           `bson:"fileVersion,omitempty" json:"fileVersion,omitempty"`       // 标注所在的附件版本，旧评论没有该字段，视为第 1 版
 	TaskID          string          `bson:"taskId" json:"taskId" index:"taskId"`                      // 关联任务ID
 	TaskNodeID      string          `bson:"taskNodeId" json:"taskNodeId" index:"taskNodeId"`          // 关联任务节点ID
 	UserID          string          `bson:"userId" json:"userId" index:"userId"`                      // 评论用户ID
 	EmployeeID      string          `bson:"employeeId" json:"employeeId"`                             // 员工ID
 	EmployeeName    string          `bson:"employeeName" json:"employeeName"`                         // 员工姓名
-	Content         string          `bson:"content" json:"content"`                                   // 评论内容
+	Content         string          `bson:"content" json:"content"`                                   // 评论内容(Markdown原文)
+	ContentHTML     string          `bson:"contentHtml" json:"contentHtml"`                           // 服务端渲染并净化的HTML
+	ContentText     string          `bson:"contentText" json:"contentText"`                           // 纯文本，用于搜索和通知
 	AtEmployeeIDs   []string        `bson:"atEmployeeIds" json:"atEmployeeIds"`                       // @的员工ID列表
 	AtEmployeeNames []string        `bson:"atEmployeeNames" json:"atEmployeeNames"`                   // @的员工姓名列表
 	AnnotationType  string          `bson:"annotationType" json:"annotationType"`                     // 标注类型: point/rect/highlight/arrow
@@ -0,0 +1,139 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// StorageUsage 公司或员工的存储用量（MySQL），EmployeeId 为空的记录是公司合计
+type StorageUsage struct {
+	CompanyId  string    `db:"company_id"`  // 公司ID
+	EmployeeId string    `db:"employee_id"` // 员工ID，为空时是公司合计
+	UsedBytes  int64     `db:"used_bytes"`  // 已使用的存储（字节）
+	FileCount  int64     `db:"file_count"`  // 存储的文件数
+	UpdateTime time.Time `db:"update_time"` // 更新时间
+}
+
+// 超出配额的范围
+const (
+	StorageScopeCompany = "company" // 公司总配额
+	StorageScopeUser    = "user"    // 员工配额
+)
+
+const storageUsageRows = "`company_id`, `employee_id`, `used_bytes`, `file_count`, `update_time`"
+
+// errStorageQuotaExceeded 超出配额时回滚事务
+var errStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// StorageUsageModel 存储用量计数
+type StorageUsageModel interface {
+	// Charge 公司和员工用量同时增加 bytes 和一个文件，limit 为 0 时不限制；
+	// 任一方超出配额时都不增加，返回超出的范围（StorageScope*），未超出时返回空字符串
+	Charge(ctx context.Context, companyId, employeeId string, bytes, companyLimit, userLimit int64) (string, error)
+	// Release 公司和员工用量同时减少，不会减到 0 以下
+	Release(ctx context.Context, companyId, employeeId string, bytes, files int64) error
+	FindOne(ctx context.Context, companyId, employeeId string) (*StorageUsage, error)
+	// FindByCompany 公司合计和全部员工的用量
+	FindByCompany(ctx context.Context, companyId string) ([]*StorageUsage, error)
+	// Replace 用重新统计的结果替换公司的全部用量记录
+	Replace(ctx context.Context, companyId string, rows []*StorageUsage) error
+}
+
+type defaultStorageUsageModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewStorageUsageModel(conn sqlx.SqlConn) StorageUsageModel {
+	return &defaultStorageUsageModel{
+		conn:  conn,
+		table: "`storage_usage`",
+	}
+}
+
+func (m *defaultStorageUsageModel) Charge(ctx context.Context, companyId, employeeId string, bytes, companyLimit, userLimit int64) (string, error) {
+	var exceeded string
+	err := m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		insert := fmt.Sprintf("INSERT IGNORE INTO %s (`company_id`, `employee_id`) VALUES (?, ?), (?, ?)", m.table)
+		if _, err := session.ExecCtx(ctx, insert, companyId, "", companyId, employeeId); err != nil {
+			return err
+		}
+		// 先更新公司合计，行锁让同一公司的并发上传依次检查配额
+		update := fmt.Sprintf("UPDATE %s SET `used_bytes` = `used_bytes` + ?, `file_count` = `file_count` + 1 "+
+			"WHERE `company_id` = ? AND `employee_id` = ? AND (? = 0 OR `used_bytes` + ? <= ?)", m.table)
+		for _, scope := range []struct {
+			name       string
+			employeeId string
+			limit      int64
+		}{
+			{StorageScopeCompany, "", companyLimit},
+			{StorageScopeUser, employeeId, userLimit},
+		} {
+			result, err := session.ExecCtx(ctx, update, bytes, companyId, scope.employeeId, scope.limit, bytes, scope.limit)
+			if err != nil {
+				return err
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				exceeded = scope.name
+				return errStorageQuotaExceeded
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errStorageQuotaExceeded) {
+		return exceeded, nil
+	}
+	return "", err
+}
+
+func (m *defaultStorageUsageModel) Release(ctx context.Context, companyId, employeeId string, bytes, files int64) error {
+	query := fmt.Sprintf("UPDATE %s SET `used_bytes` = GREATEST(`used_bytes` - ?, 0), `file_count` = GREATEST(`file_count` - ?, 0) "+
+		"WHERE `company_id` = ? AND `employee_id` IN (?, ?)", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, bytes, files, companyId, "", employeeId)
+	return err
+}
+
+func (m *defaultStorageUsageModel) FindOne(ctx context.Context, companyId, employeeId string) (*StorageUsage, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND `employee_id` = ? LIMIT 1", storageUsageRows, m.table)
+	var resp StorageUsage
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId, employeeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultStorageUsageModel) FindByCompany(ctx context.Context, companyId string) ([]*StorageUsage, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? ORDER BY `used_bytes` DESC", storageUsageRows, m.table)
+	var resp []*StorageUsage
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId)
+	return resp, err
+}
+
+func (m *defaultStorageUsageModel) Replace(ctx context.Context, companyId string, rows []*StorageUsage) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		del := fmt.Sprintf("DELETE FROM %s WHERE `company_id` = ?", m.table)
+		if _, err := session.ExecCtx(ctx, del, companyId); err != nil {
+			return err
+		}
+		insert := fmt.Sprintf("INSERT INTO %s (`company_id`, `employee_id`, `used_bytes`, `file_count`) VALUES (?, ?, ?, ?)", m.table)
+		for _, row := range rows {
+			if _, err := session.ExecCtx(ctx, insert, companyId, row.EmployeeId, row.UsedBytes, row.FileCount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
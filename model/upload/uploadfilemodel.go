@@ -2,6 +2,7 @@ package upload
 
 import (
 	"context"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/mon"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -17,6 +18,12 @@ type (
 		upload_fileModel
 		FindByTaskNodeID(ctx context.Context, taskNodeID string) ([]*Upload_file, error)
 		FindByUploaderID(ctx context.Context, uploaderID string, page, pageSize int64, fileType, module string) ([]*Upload_file, int64, error)
+		// ReplaceVersions 设置当前版本和保留的版本列表，expected 为读取时记录上的 version 字段，已被其他请求修改时返回 false
+		ReplaceVersions(ctx context.Context, fileID string, expected int64, current Upload_fileVersion, versions []Upload_fileVersion) (bool, error)
+		// FindWithVersionsBefore 有早于指定时间的版本且不止一个版本的附件，用于按天数清理历史版本
+		FindWithVersionsBefore(ctx context.Context, before time.Time, limit int64) ([]*Upload_file, error)
+		// StorageUsage 按 groupBy（StorageGroup*）汇总指定员工上传的存储用量，按用量降序
+		StorageUsage(ctx context.Context, uploaderIDs []string, groupBy string) ([]*StorageUsageGroup, error)
 	}
 
 	customUpload_fileModel struct {
@@ -78,3 +85,97 @@ func (m *customUpload_fileModel) FindByUploaderID(ctx context.Context, uploaderI
 
 	return data, total, nil
 }
+
+func (m *customUpload_fileModel) ReplaceVersions(ctx context.Context, fileID string, expected int64, current Upload_fileVersion, versions []Upload_fileVersion) (bool, error) {
+	filter := bson.M{"fileId": fileID, "version": expected}
+	if expected == 0 {
+		// 旧附件没有 version 字段
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	res, err := m.conn.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"fileName":      current.FileName,
+			"filePath":      current.FilePath,
+			"fileUrl":       current.FileURL,
+			"fileType":      current.FileType,
+			"fileSize":      current.FileSize,
+			"mimeType":      current.MimeType,
+			"thumbnailPath": current.ThumbnailPath,
+			"thumbnailUrl":  current.ThumbnailURL,
+			"previewPath":   current.PreviewPath,
+			"previewUrl":    current.PreviewURL,
+			"version":       current.Version,
+			"versions":      versions,
+			"updateAt":      time.Now(),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (m *customUpload_fileModel) FindWithVersionsBefore(ctx context.Context, before time.Time, limit int64) ([]*Upload_file, error) {
+	filter := bson.M{
+		"versions.1": bson.M{"$exists": true},
+		"versions":   bson.M{"$elemMatch": bson.M{"createAt": bson.M{"$lt": before}}},
+	}
+	opts := options.Find().SetSort(bson.M{"updateAt": 1}).SetLimit(limit)
+
+	var data []*Upload_file
+	if err := m.conn.Find(ctx, &data, filter, opts); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *customUpload_fileModel) StorageUsage(ctx context.Context, uploaderIDs []string, groupBy string) ([]*StorageUsageGroup, error) {
+	if len(uploaderIDs) == 0 {
+		return nil, nil
+	}
+	// 与 Upload_file.StorageObjects 相同的计算方式：附件的每个存储对象计算一次，
+	// 归属于引用该对象的最早版本的上传人；没有版本记录的旧附件按当前文件计算
+	objects := bson.A{
+		bson.M{"$match": bson.M{
+			"$nor": bson.A{bson.M{"module": AvatarModule, "category": AvatarCategory}},
+			"$or": bson.A{
+				bson.M{"uploaderId": bson.M{"$in": uploaderIDs}},
+				bson.M{"versions.uploaderId": bson.M{"$in": uploaderIDs}},
+			},
+		}},
+		bson.M{"$project": bson.M{
+			"module":    1,
+			"relatedId": 1,
+			"items": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$versions", bson.A{}}}}, 0}},
+				"$versions",
+				bson.A{bson.M{"version": 1, "uploaderId": "$uploaderId", "filePath": "$filePath", "fileSize": "$fileSize", "fileType": "$fileType"}},
+			}},
+		}},
+		bson.M{"$unwind": "$items"},
+		bson.M{"$sort": bson.M{"items.version": 1}},
+		bson.M{"$group": bson.M{
+			"_id":        bson.M{"doc": "$_id", "path": "$items.filePath"},
+			"uploaderId": bson.M{"$first": "$items.uploaderId"},
+			"fileSize":   bson.M{"$first": "$items.fileSize"},
+			"fileType":   bson.M{"$first": "$items.fileType"},
+			"module":     bson.M{"$first": "$module"},
+			"relatedId":  bson.M{"$first": "$relatedId"},
+		}},
+	}
+	match := bson.M{"uploaderId": bson.M{"$in": uploaderIDs}}
+	if groupBy == StorageGroupTask {
+		match["module"] = "task"
+	}
+	pipeline := append(objects,
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{"_id": "$" + groupBy, "bytes": bson.M{"$sum": "$fileSize"}, "files": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.M{"bytes": -1}},
+	)
+
+	var rows []*StorageUsageGroup
+	if err := m.conn.Aggregate(ctx, &rows, pipeline); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
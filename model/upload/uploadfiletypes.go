@@ -20,7 +20,139 @@ type Upload_file struct {
 	TaskNodeID  string        `bson:"taskNodeId" json:"taskNodeId"` // 关联的任务节点ID
 	Description string        `bson:"description" json:"description"`
 	Tags        string        `bson:"tags" json:"tags"`
-	UploaderID  string        `bson:"uploaderId" json:"uploaderId"` // 上传者ID
-	UpdateAt    time.Time     `bson:"updateAt,omitempty" json:"updateAt,omitempty"`
-	CreateAt    time.Time     `bson:"createAt,omitempty" json:"createAt,omitempty"`
+	UploaderID  string        `bson:"uploaderId" json:"uploaderId"`                 // 上传者ID
+	MimeType    string        `bson:"mimeType,omitempty" json:"mimeType,omitempty"` // 按文件内容识别的MIME类型
+	// 缩略图和预览图，只有图片和PDF有
+	Upload_fileRendition `bson:",inline"`
+	// 版本：文件字段（fileName/filePath/fileUrl/fileType/fileSize）始终是当前版本，
+	// Versions 按版本号升序保存保留的全部版本（包括当前版本），旧附件没有版本记录
+	Version  int64                `bson:"version,omitempty" json:"version,omitempty"`   // 当前版本号
+	Versions []Upload_fileVersion `bson:"versions,omitempty" json:"versions,omitempty"` // 版本记录
+	UpdateAt time.Time            `bson:"updateAt,omitempty" json:"updateAt,omitempty"`
+	CreateAt time.Time            `bson:"createAt,omitempty" json:"createAt,omitempty"`
+}
+
+// Upload_fileVersion 附件的一个版本，恢复历史版本时新版本与原版本共用同一个存储对象
+type Upload_fileVersion struct {
+	Version      int64     `bson:"version" json:"version"`                               // 版本号，从 1 开始
+	FileName     string    `bson:"fileName" json:"fileName"`                             // 文件名
+	FilePath     string    `bson:"filePath" json:"filePath"`                             // 存储Key
+	FileURL      string    `bson:"fileUrl" json:"fileUrl"`                               // 访问URL
+	FileType     string    `bson:"fileType" json:"fileType"`                             // 文件类型
+	FileSize     int64     `bson:"fileSize" json:"fileSize"`                             // 文件大小
+	UploaderID   string    `bson:"uploaderId" json:"uploaderId"`                         // 上传该版本的员工ID
+	MimeType     string    `bson:"mimeType,omitempty" json:"mimeType,omitempty"`         // 按文件内容识别的MIME类型
+	ChangeNote   string    `bson:"changeNote,omitempty" json:"changeNote,omitempty"`     // 版本说明
+	RestoredFrom int64     `bson:"restoredFrom,omitempty" json:"restoredFrom,omitempty"` // 从哪个历史版本恢复
+	CreateAt     time.Time `bson:"createAt" json:"createAt"`                             // 上传时间
+
+	Upload_fileRendition `bson:",inline"`
+}
+
+// Upload_fileRendition 缩略图和预览图的存储Key和访问URL，没有生成时为空
+type Upload_fileRendition struct {
+	ThumbnailPath string `bson:"thumbnailPath,omitempty" json:"thumbnailPath,omitempty"` // 缩略图存储Key
+	ThumbnailURL  string `bson:"thumbnailUrl,omitempty" json:"thumbnailUrl,omitempty"`   // 缩略图访问URL
+	PreviewPath   string `bson:"previewPath,omitempty" json:"previewPath,omitempty"`     // 预览图存储Key
+	PreviewURL    string `bson:"previewUrl,omitempty" json:"previewUrl,omitempty"`       // 预览图访问URL
+}
+
+// Paths 缩略图和预览图的存储Key，忽略未生成的
+func (r Upload_fileRendition) Paths() []string {
+	var paths []string
+	for _, p := range []string{r.ThumbnailPath, r.PreviewPath} {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// CurrentVersion 当前版本号，没有版本记录的旧附件为 1
+func (f *Upload_file) CurrentVersion() int64 {
+	if f.Version > 0 {
+		return f.Version
+	}
+	return 1
+}
+
+// VersionList 按版本号升序的全部版本，旧附件返回由当前文件字段构成的第 1 版
+func (f *Upload_file) VersionList() []Upload_fileVersion {
+	if len(f.Versions) > 0 {
+		return f.Versions
+	}
+	return []Upload_fileVersion{{
+		Version:    1,
+		FileName:   f.FileName,
+		FilePath:   f.FilePath,
+		FileURL:    f.FileURL,
+		FileType:   f.FileType,
+		FileSize:   f.FileSize,
+		UploaderID: f.UploaderID,
+		MimeType:   f.MimeType,
+		CreateAt:   f.CreateAt,
+
+		Upload_fileRendition: f.Upload_fileRendition,
+	}}
+}
+
+// FindVersion 查找指定版本，已被保留策略清理或不存在时返回 false
+func (f *Upload_file) FindVersion(version int64) (Upload_fileVersion, bool) {
+	for _, v := range f.VersionList() {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return Upload_fileVersion{}, false
+}
+
+// 头像的模块和分类，头像只能通过头像接口上传，不计入存储用量
+const (
+	AvatarModule   = "user"
+	AvatarCategory = "avatar"
+)
+
+// 存储用量的汇总方式
+const (
+	StorageGroupUploader = "uploaderId" // 按上传人
+	StorageGroupModule   = "module"     // 按业务模块
+	StorageGroupFileType = "fileType"   // 按文件类型
+	StorageGroupTask     = "relatedId"  // 按任务，只统计任务模块的附件
+)
+
+// StorageUsageGroup 存储用量的一个汇总项
+type StorageUsageGroup struct {
+	Key   string `bson:"_id"`   // 汇总的值，如上传人ID、模块、文件类型或任务ID
+	Bytes int64  `bson:"bytes"` // 存储用量（字节）
+	Files int64  `bson:"files"` // 存储对象数
+}
+
+// StorageAccounted 附件是否计入存储用量
+func (f *Upload_file) StorageAccounted() bool {
+	return !IsAvatar(f.Module, f.Category)
+}
+
+// IsAvatar 模块和分类是否为头像
+func IsAvatar(module, category string) bool {
+	return module == AvatarModule && category == AvatarCategory
+}
+
+// StorageObjects 附件占用的存储对象：每个文件存储Key取引用它的最早版本，
+// 恢复历史版本与原版本共用存储对象，不重复计算；缩略图和预览图不计入
+func (f *Upload_file) StorageObjects() []Upload_fileVersion {
+	return StorageObjects(f.VersionList())
+}
+
+// StorageObjects 版本列表中的存储对象，见 Upload_file.StorageObjects
+func StorageObjects(versions []Upload_fileVersion) []Upload_fileVersion {
+	seen := make(map[string]bool, len(versions))
+	objects := make([]Upload_fileVersion, 0, len(versions))
+	for _, v := range versions {
+		if v.FilePath == "" || seen[v.FilePath] {
+			continue
+		}
+		seen[v.FilePath] = true
+		objects = append(objects, v)
+	}
+	return objects
 }
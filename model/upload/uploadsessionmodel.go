@@ -0,0 +1,197 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// UploadSession 分片上传或预签名直传会话（MySQL）
+type UploadSession struct {
+	Id              string    `db:"id"`                // 上传会话ID
+	CompanyId       string    `db:"company_id"`        // 公司ID
+	EmployeeId      string    `db:"employee_id"`       // 上传人员工ID
+	Mode            string    `db:"mode"`              // 上传方式 chunked/direct
+	FileName        string    `db:"file_name"`         // 文件名
+	FileSize        int64     `db:"file_size"`         // 文件大小（字节）
+	Checksum        string    `db:"checksum"`          // 整个文件的 SHA-256，为空时不校验
+	PartSize        int64     `db:"part_size"`         // 分片大小（字节）
+	PartCount       int64     `db:"part_count"`        // 分片数
+	Module          string    `db:"module"`            // 附件模块
+	Category        string    `db:"category"`          // 附件分类
+	RelatedId       string    `db:"related_id"`        // 关联ID
+	TaskNodeId      string    `db:"task_node_id"`      // 关联的任务节点ID
+	Description     string    `db:"description"`       // 附件描述
+	Tags            string    `db:"tags"`              // 附件标签
+	StorageKey      string    `db:"storage_key"`       // 文件存储Key
+	StorageUploadId string    `db:"storage_upload_id"` // 存储端的分片上传ID
+	Status          int64     `db:"status"`            // 状态，见 UploadSessionStatus*
+	FileId          string    `db:"file_id"`           // 完成后创建的附件ID
+	ErrorMessage    string    `db:"error_message"`     // 失败原因
+	ExpireTime      time.Time `db:"expire_time"`       // 过期时间
+	CreateTime      time.Time `db:"create_time"`       // 创建时间
+	UpdateTime      time.Time `db:"update_time"`       // 更新时间
+}
+
+// UploadSessionPart 分片上传已上传的分片
+type UploadSessionPart struct {
+	SessionId  string    `db:"session_id"`  // 上传会话ID
+	PartNumber int64     `db:"part_number"` // 分片序号，从 1 开始
+	Size       int64     `db:"size"`        // 分片大小（字节）
+	Md5        string    `db:"md5"`         // 分片内容的 MD5
+	Etag       string    `db:"etag"`        // 存储端返回的 ETag
+	CreateTime time.Time `db:"create_time"` // 上传时间
+}
+
+// 上传方式
+const (
+	UploadModeChunked = "chunked" // 分片上传，分片经服务端转发到存储
+	UploadModeDirect  = "direct"  // 客户端通过预签名URL直接上传到存储
+)
+
+// 上传会话状态
+const (
+	UploadSessionStatusUploading  = 0 // 上传中
+	UploadSessionStatusCompleting = 1 // 合并中
+	UploadSessionStatusCompleted  = 2 // 已完成
+	UploadSessionStatusAborted    = 3 // 已取消（包括过期清理）
+	UploadSessionStatusFailed     = 4 // 失败（校验未通过等）
+)
+
+const uploadSessionRows = "`id`, `company_id`, `employee_id`, `mode`, `file_name`, `file_size`, `checksum`, `part_size`, `part_count`, `module`, `category`, `related_id`, `task_node_id`, `description`, `tags`, `storage_key`, `storage_upload_id`, `status`, `file_id`, `error_message`, `expire_time`, `create_time`, `update_time`"
+
+const uploadSessionPartRows = "`session_id`, `part_number`, `size`, `md5`, `etag`, `create_time`"
+
+// UploadSessionModel 上传会话和已上传分片
+type UploadSessionModel interface {
+	Insert(ctx context.Context, data *UploadSession) error
+	FindOne(ctx context.Context, id string) (*UploadSession, error)
+	TransitStatus(ctx context.Context, id string, from, to int64) (bool, error)
+	BeginComplete(ctx context.Context, id string, deadline time.Time) (bool, error)
+	Complete(ctx context.Context, id, fileId string) error
+	Fail(ctx context.Context, id, message string) error
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*UploadSession, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	UpsertPart(ctx context.Context, part *UploadSessionPart) error
+	FindParts(ctx context.Context, sessionId string) ([]*UploadSessionPart, error)
+	DeleteParts(ctx context.Context, sessionId string) error
+}
+
+type defaultUploadSessionModel struct {
+	conn      sqlx.SqlConn
+	table     string
+	partTable string
+}
+
+func NewUploadSessionModel(conn sqlx.SqlConn) UploadSessionModel {
+	return &defaultUploadSessionModel{
+		conn:      conn,
+		table:     "`upload_session`",
+		partTable: "`upload_session_part`",
+	}
+}
+
+func (m *defaultUploadSessionModel) Insert(ctx context.Context, data *UploadSession) error {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `employee_id`, `mode`, `file_name`, `file_size`, `checksum`, `part_size`, `part_count`, `module`, `category`, `related_id`, `task_node_id`, `description`, `tags`, `storage_key`, `storage_upload_id`, `status`, `expire_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.Mode, data.FileName, data.FileSize, data.Checksum,
+		data.PartSize, data.PartCount, data.Module, data.Category, data.RelatedId, data.TaskNodeId, data.Description, data.Tags,
+		data.StorageKey, data.StorageUploadId, data.Status, data.ExpireTime)
+	return err
+}
+
+func (m *defaultUploadSessionModel) FindOne(ctx context.Context, id string) (*UploadSession, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", uploadSessionRows, m.table)
+	var resp UploadSession
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TransitStatus 会话状态为 from 时改为 to，返回是否修改成功（防止并发完成或取消同一个会话）
+func (m *defaultUploadSessionModel) TransitStatus(ctx context.Context, id string, from, to int64) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ? WHERE `id` = ? AND `status` = ?", m.table)
+	result, err := m.conn.ExecCtx(ctx, query, to, id, from)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// BeginComplete 上传中的会话进入合并中，并把过期时间延后到 deadline，避免合并大文件期间被过期清理
+func (m *defaultUploadSessionModel) BeginComplete(ctx context.Context, id string, deadline time.Time) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `expire_time` = GREATEST(`expire_time`, ?) WHERE `id` = ? AND `status` = ?", m.table)
+	result, err := m.conn.ExecCtx(ctx, query, UploadSessionStatusCompleting, deadline, id, UploadSessionStatusUploading)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// Complete 标记会话已完成并记录创建的附件ID
+func (m *defaultUploadSessionModel) Complete(ctx context.Context, id, fileId string) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `file_id` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, UploadSessionStatusCompleted, fileId, id)
+	return err
+}
+
+// Fail 标记会话失败
+func (m *defaultUploadSessionModel) Fail(ctx context.Context, id, message string) error {
+	if len([]rune(message)) > 500 {
+		message = string([]rune(message)[:500])
+	}
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `error_message` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, UploadSessionStatusFailed, message, id)
+	return err
+}
+
+// FindExpired 查询已过期但仍在上传或合并中的会话
+func (m *defaultUploadSessionModel) FindExpired(ctx context.Context, now time.Time, limit int) ([]*UploadSession, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `status` IN (?, ?) AND `expire_time` < ? ORDER BY `expire_time` ASC LIMIT ?", uploadSessionRows, m.table)
+	var resp []*UploadSession
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, UploadSessionStatusUploading, UploadSessionStatusCompleting, now, limit)
+	return resp, err
+}
+
+// DeleteFinishedBefore 删除在指定时间之前结束（完成、取消或失败）的会话记录
+func (m *defaultUploadSessionModel) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `status` IN (?, ?, ?) AND `update_time` < ?", m.table)
+	result, err := m.conn.ExecCtx(ctx, query, UploadSessionStatusCompleted, UploadSessionStatusAborted, UploadSessionStatusFailed, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// UpsertPart 记录已上传的分片，重传同一分片时覆盖
+func (m *defaultUploadSessionModel) UpsertPart(ctx context.Context, part *UploadSessionPart) error {
+	query := fmt.Sprintf("INSERT INTO %s (`session_id`, `part_number`, `size`, `md5`, `etag`) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `size` = VALUES(`size`), `md5` = VALUES(`md5`), `etag` = VALUES(`etag`), `create_time` = NOW()", m.partTable)
+	_, err := m.conn.ExecCtx(ctx, query, part.SessionId, part.PartNumber, part.Size, part.Md5, part.Etag)
+	return err
+}
+
+// FindParts 查询会话已上传的分片，按分片序号排序
+func (m *defaultUploadSessionModel) FindParts(ctx context.Context, sessionId string) ([]*UploadSessionPart, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `session_id` = ? ORDER BY `part_number` ASC", uploadSessionPartRows, m.partTable)
+	var resp []*UploadSessionPart
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, sessionId)
+	return resp, err
+}
+
+// DeleteParts 删除会话的分片记录
+func (m *defaultUploadSessionModel) DeleteParts(ctx context.Context, sessionId string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `session_id` = ?", m.partTable)
+	_, err := m.conn.ExecCtx(ctx, query, sessionId)
+	return err
+}
@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// EmployeeCapacity 员工每周可用工时
+type EmployeeCapacity struct {
+	EmployeeId  string         `db:"employee_id"`  // 员工ID
+	CompanyId   string         `db:"company_id"`   // 公司ID
+	WeeklyHours float64        `db:"weekly_hours"` // 每周可用工时（小时）
+	UpdateBy    sql.NullString `db:"update_by"`    // 最后修改人员工ID
+	CreateTime  time.Time      `db:"create_time"`  // 创建时间
+	UpdateTime  time.Time      `db:"update_time"`  // 更新时间
+}
+
+const employeeCapacityRows = "`employee_id`, `company_id`, `weekly_hours`, `update_by`, `create_time`, `update_time`"
+
+type EmployeeCapacityModel interface {
+	FindOne(ctx context.Context, employeeId string) (*EmployeeCapacity, error)
+	FindByEmployeeIds(ctx context.Context, employeeIds []string) ([]*EmployeeCapacity, error)
+	Upsert(ctx context.Context, data *EmployeeCapacity) error
+	Delete(ctx context.Context, employeeId string) error
+}
+
+type defaultEmployeeCapacityModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewEmployeeCapacityModel(conn sqlx.SqlConn) EmployeeCapacityModel {
+	return &defaultEmployeeCapacityModel{
+		conn:  conn,
+		table: "`employee_capacity`",
+	}
+}
+
+func (m *defaultEmployeeCapacityModel) FindOne(ctx context.Context, employeeId string) (*EmployeeCapacity, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? LIMIT 1", employeeCapacityRows, m.table)
+	var resp EmployeeCapacity
+	err := m.conn.QueryRowCtx(ctx, &resp, query, employeeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByEmployeeIds 批量查询员工的产能配置，未配置的员工不在结果中
+func (m *defaultEmployeeCapacityModel) FindByEmployeeIds(ctx context.Context, employeeIds []string) ([]*EmployeeCapacity, error) {
+	if len(employeeIds) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(employeeIds)), ", ")
+	args := make([]interface{}, 0, len(employeeIds))
+	for _, id := range employeeIds {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` IN (%s)", employeeCapacityRows, m.table, placeholders)
+	var resp []*EmployeeCapacity
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+// Upsert 写入员工的每周可用工时，已存在时覆盖
+func (m *defaultEmployeeCapacityModel) Upsert(ctx context.Context, data *EmployeeCapacity) error {
+	query := fmt.Sprintf("INSERT INTO %s (`employee_id`, `company_id`, `weekly_hours`, `update_by`) VALUES (?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `company_id` = VALUES(`company_id`), `weekly_hours` = VALUES(`weekly_hours`), `update_by` = VALUES(`update_by`), `update_time` = NOW()", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.EmployeeId, data.CompanyId, data.WeeklyHours, data.UpdateBy)
+	return err
+}
+
+// Delete 删除员工的产能配置（恢复为系统默认值）
+func (m *defaultEmployeeCapacityModel) Delete(ctx context.Context, employeeId string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE `employee_id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, employeeId)
+	return err
+}
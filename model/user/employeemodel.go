@@ -19,9 +19,14 @@ type (
 
 		// 员工CRUD操作
 		FindByUserID(ctx context.Context, userID string) (*Employee, error)
+		FindByUserIDAndCompanyID(ctx context.Context, userID, companyID string) (*Employee, error)
+		FindAllByUserID(ctx context.Context, userID string) ([]*Employee, error)
+		HasOtherActiveMembership(ctx context.Context, userID, excludeID string) (bool, error)
 		FindByCompanyID(ctx context.Context, companyID string) ([]*Employee, error)
+		FindAllByCompanyID(ctx context.Context, companyID string) ([]*Employee, error)
 		FindByDepartmentID(ctx context.Context, departmentID string) ([]*Employee, error)
 		FindByPositionID(ctx context.Context, positionID string) ([]*Employee, error)
+		FindByRoleID(ctx context.Context, roleID string) ([]*Employee, error)
 		FindByEmployeeID(ctx context.Context, employeeID string) (*Employee, error)
 		FindByStatus(ctx context.Context, status int) ([]*Employee, error)
 		FindByPage(ctx context.Context, page, pageSize int) ([]*Employee, int64, error)
@@ -72,8 +77,9 @@ func (m *customEmployeeModel) withSession(session sqlx.Session) EmployeeModel {
 }
 
 // FindByUserID 根据用户ID查找员工
+// 用户可在多家公司任职时返回最近加入且在职的员工记录；需要限定公司时使用 FindByUserIDAndCompanyID
 func (m *customEmployeeModel) FindByUserID(ctx context.Context, userID string) (*Employee, error) {
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `delete_time` IS NULL", employeeRows, m.table)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `delete_time` IS NULL ORDER BY `status` = 0, `create_time` DESC LIMIT 1", employeeRows, m.table)
 	var resp Employee
 	err := m.conn.QueryRowCtx(ctx, &resp, query, userID)
 	switch err {
@@ -86,6 +92,39 @@ func (m *customEmployeeModel) FindByUserID(ctx context.Context, userID string) (
 	}
 }
 
+// FindByUserIDAndCompanyID 查找用户在指定公司的员工记录
+func (m *customEmployeeModel) FindByUserIDAndCompanyID(ctx context.Context, userID, companyID string) (*Employee, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `company_id` = ? AND `delete_time` IS NULL LIMIT 1", employeeRows, m.table)
+	var resp Employee
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID, companyID)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindAllByUserID 查询用户在所有公司的员工记录（未删除），按加入时间倒序
+func (m *customEmployeeModel) FindAllByUserID(ctx context.Context, userID string) ([]*Employee, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `delete_time` IS NULL ORDER BY `create_time` DESC", employeeRows, m.table)
+	var resp []*Employee
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID)
+	return resp, err
+}
+
+// HasOtherActiveMembership 判断用户除指定员工记录外是否还在其他公司在职
+func (m *customEmployeeModel) HasOtherActiveMembership(ctx context.Context, userID, excludeID string) (bool, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `user_id` = ? AND `id` != ? AND `status` != 0 AND `delete_time` IS NULL", m.table)
+	var count int64
+	if err := m.conn.QueryRowCtx(ctx, &count, query, userID, excludeID); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // FindByCompanyID 根据公司ID查找员工
 func (m *customEmployeeModel) FindByCompanyID(ctx context.Context, companyID string) ([]*Employee, error) {
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND `delete_time` IS NULL ORDER BY `create_time` DESC", employeeRows, m.table)
@@ -94,6 +133,14 @@ func (m *customEmployeeModel) FindByCompanyID(ctx context.Context, companyID str
 	return resp, err
 }
 
+// FindAllByCompanyID 根据公司ID查找员工，包括已删除的员工（统计离职员工留下的数据时使用）
+func (m *customEmployeeModel) FindAllByCompanyID(ctx context.Context, companyID string) ([]*Employee, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? ORDER BY `create_time` DESC", employeeRows, m.table)
+	var resp []*Employee
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyID)
+	return resp, err
+}
+
 // FindByDepartmentID 根据部门ID查找员工
 func (m *customEmployeeModel) FindByDepartmentID(ctx context.Context, departmentID string) ([]*Employee, error) {
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE `department_id` = ? AND `delete_time` IS NULL ORDER BY `create_time` DESC", employeeRows, m.table)
@@ -110,6 +157,16 @@ func (m *customEmployeeModel) FindByPositionID(ctx context.Context, positionID s
 	return resp, err
 }
 
+// FindByRoleID 根据角色ID查找员工（通过职位授予的有效角色）
+func (m *customEmployeeModel) FindByRoleID(ctx context.Context, roleID string) ([]*Employee, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `position_id` IN (SELECT `position_id` FROM `position_role` "+
+		"WHERE `role_id` = ? AND `status` = 1 AND (`expire_time` IS NULL OR `expire_time` > NOW())) AND `delete_time` IS NULL "+
+		"ORDER BY `create_time` DESC", employeeRows, m.table)
+	var resp []*Employee
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, roleID)
+	return resp, err
+}
+
 // FindByEmployeeID 根据员工编号查找员工
 func (m *customEmployeeModel) FindByEmployeeID(ctx context.Context, employeeID string) (*Employee, error) {
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `delete_time` IS NULL", employeeRows, m.table)
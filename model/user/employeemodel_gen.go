@@ -27,9 +27,9 @@ type (
 	employeeModel interface {
 		Insert(ctx context.Context, data *Employee) (sql.Result, error)
 		FindOne(ctx context.Context, id string) (*Employee, error)
-		FindOneByEmail(ctx context.Context, email sql.NullString) (*Employee, error)
+		FindOneByCompanyIdEmail(ctx context.Context, companyId string, email sql.NullString) (*Employee, error)
 		FindOneByEmployeeId(ctx context.Context, employeeId string) (*Employee, error)
-		FindOneByUserId(ctx context.Context, userId string) (*Employee, error)
+		FindOneByUserIdCompanyId(ctx context.Context, userId string, companyId string) (*Employee, error)
 		Update(ctx context.Context, data *Employee) error
 		Delete(ctx context.Context, id string) error
 	}
@@ -88,10 +88,10 @@ func (m *defaultEmployeeModel) FindOne(ctx context.Context, id string) (*Employe
 	}
 }
 
-func (m *defaultEmployeeModel) FindOneByEmail(ctx context.Context, email sql.NullString) (*Employee, error) {
+func (m *defaultEmployeeModel) FindOneByCompanyIdEmail(ctx context.Context, companyId string, email sql.NullString) (*Employee, error) {
 	var resp Employee
-	query := fmt.Sprintf("select %s from %s where `email` = ? limit 1", employeeRows, m.table)
-	err := m.conn.QueryRowCtx(ctx, &resp, query, email)
+	query := fmt.Sprintf("select %s from %s where `company_id` = ? and `email` = ? limit 1", employeeRows, m.table)
+	err := m.conn.QueryRowCtx(ctx, &resp, query, companyId, email)
 	switch err {
 	case nil:
 		return &resp, nil
@@ -116,10 +116,10 @@ func (m *defaultEmployeeModel) FindOneByEmployeeId(ctx context.Context, employee
 	}
 }
 
-func (m *defaultEmployeeModel) FindOneByUserId(ctx context.Context, userId string) (*Employee, error) {
+func (m *defaultEmployeeModel) FindOneByUserIdCompanyId(ctx context.Context, userId string, companyId string) (*Employee, error) {
 	var resp Employee
-	query := fmt.Sprintf("select %s from %s where `user_id` = ? limit 1", employeeRows, m.table)
-	err := m.conn.QueryRowCtx(ctx, &resp, query, userId)
+	query := fmt.Sprintf("select %s from %s where `user_id` = ? and `company_id` = ? limit 1", employeeRows, m.table)
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userId, companyId)
 	switch err {
 	case nil:
 		return &resp, nil
@@ -0,0 +1,179 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// OutOfOffice 员工外出/休假代理记录
+type OutOfOffice struct {
+	Id                  string         `db:"id"`                   // 记录ID
+	CompanyId           string         `db:"company_id"`           // 公司ID
+	EmployeeId          string         `db:"employee_id"`          // 外出员工ID
+	DelegateId          string         `db:"delegate_id"`          // 代理人员工ID
+	StartTime           time.Time      `db:"start_time"`           // 开始时间
+	EndTime             time.Time      `db:"end_time"`             // 结束时间
+	Reason              sql.NullString `db:"reason"`               // 外出原因
+	Status              int64          `db:"status"`               // 状态 0-待生效 1-生效中 2-已结束 3-已取消
+	RoutedItems         sql.NullString `db:"routed_items"`         // 代理期间转交给代理人的事项（JSON）
+	RoutedNotifications int64          `db:"routed_notifications"` // 代理期间转发给代理人的通知数
+	CreateBy            sql.NullString `db:"create_by"`            // 创建人员工ID
+	CreateTime          time.Time      `db:"create_time"`          // 创建时间
+	UpdateTime          time.Time      `db:"update_time"`          // 更新时间
+}
+
+// 外出代理状态常量
+const (
+	OutOfOfficeStatusScheduled = 0 // 待生效
+	OutOfOfficeStatusActive    = 1 // 生效中
+	OutOfOfficeStatusEnded     = 2 // 已结束
+	OutOfOfficeStatusCanceled  = 3 // 已取消
+)
+
+const outOfOfficeRows = "`id`, `company_id`, `employee_id`, `delegate_id`, `start_time`, `end_time`, `reason`, `status`, `routed_items`, `routed_notifications`, `create_by`, `create_time`, `update_time`"
+
+type OutOfOfficeModel interface {
+	Insert(ctx context.Context, data *OutOfOffice) (sql.Result, error)
+	FindOne(ctx context.Context, id string) (*OutOfOffice, error)
+	Update(ctx context.Context, data *OutOfOffice) error
+	FindCurrentByEmployeeId(ctx context.Context, employeeId string) (*OutOfOffice, error)
+	FindByEmployeeId(ctx context.Context, employeeId string, includeFinished bool) ([]*OutOfOffice, error)
+	FindCurrentByDelegateId(ctx context.Context, delegateId string) ([]*OutOfOffice, error)
+	CountOverlapping(ctx context.Context, employeeId string, startTime, endTime time.Time) (int64, error)
+	FindOverlappingByCompany(ctx context.Context, companyId string, startTime, endTime time.Time) ([]*OutOfOffice, error)
+	FindDueToStart(ctx context.Context, limit int) ([]*OutOfOffice, error)
+	FindDueToEnd(ctx context.Context, limit int) ([]*OutOfOffice, error)
+	UpdateStatus(ctx context.Context, id string, status int) error
+	IncrRoutedNotifications(ctx context.Context, id string) error
+}
+
+type defaultOutOfOfficeModel struct {
+	conn  sqlx.SqlConn
+	table string
+}
+
+func NewOutOfOfficeModel(conn sqlx.SqlConn) OutOfOfficeModel {
+	return &defaultOutOfOfficeModel{
+		conn:  conn,
+		table: "`employee_out_of_office`",
+	}
+}
+
+func (m *defaultOutOfOfficeModel) Insert(ctx context.Context, data *OutOfOffice) (sql.Result, error) {
+	query := fmt.Sprintf("INSERT INTO %s (`id`, `company_id`, `employee_id`, `delegate_id`, `start_time`, `end_time`, `reason`, `status`, `routed_items`, `routed_notifications`, `create_by`, `create_time`, `update_time`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table)
+	return m.conn.ExecCtx(ctx, query, data.Id, data.CompanyId, data.EmployeeId, data.DelegateId, data.StartTime, data.EndTime, data.Reason, data.Status, data.RoutedItems, data.RoutedNotifications, data.CreateBy, data.CreateTime, data.UpdateTime)
+}
+
+func (m *defaultOutOfOfficeModel) FindOne(ctx context.Context, id string) (*OutOfOffice, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `id` = ? LIMIT 1", outOfOfficeRows, m.table)
+	var resp OutOfOffice
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Update 更新外出记录（转发通知数由 IncrRoutedNotifications 单独累加，不在此覆盖）
+func (m *defaultOutOfOfficeModel) Update(ctx context.Context, data *OutOfOffice) error {
+	query := fmt.Sprintf("UPDATE %s SET `delegate_id` = ?, `start_time` = ?, `end_time` = ?, `reason` = ?, `status` = ?, `routed_items` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.DelegateId, data.StartTime, data.EndTime, data.Reason, data.Status, data.RoutedItems, time.Now(), data.Id)
+	return err
+}
+
+// FindCurrentByEmployeeId 查询员工当前处于外出期间的记录
+// 按时间判断而不是只看状态，定时任务尚未激活的记录在开始时间到达后同样生效
+func (m *defaultOutOfOfficeModel) FindCurrentByEmployeeId(ctx context.Context, employeeId string) (*OutOfOffice, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `status` IN (0, 1) AND `start_time` <= NOW() AND `end_time` > NOW() ORDER BY `start_time` DESC LIMIT 1", outOfOfficeRows, m.table)
+	var resp OutOfOffice
+	err := m.conn.QueryRowCtx(ctx, &resp, query, employeeId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByEmployeeId 查询员工的外出记录，includeFinished 为 false 时只返回待生效和生效中的记录
+func (m *defaultOutOfOfficeModel) FindByEmployeeId(ctx context.Context, employeeId string, includeFinished bool) ([]*OutOfOffice, error) {
+	var resp []*OutOfOffice
+	if includeFinished {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? ORDER BY `start_time` DESC", outOfOfficeRows, m.table)
+		err := m.conn.QueryRowsCtx(ctx, &resp, query, employeeId)
+		return resp, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `employee_id` = ? AND `status` IN (0, 1) ORDER BY `start_time` ASC", outOfOfficeRows, m.table)
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, employeeId)
+	return resp, err
+}
+
+// FindCurrentByDelegateId 查询当前由指定员工代理的外出记录
+func (m *defaultOutOfOfficeModel) FindCurrentByDelegateId(ctx context.Context, delegateId string) ([]*OutOfOffice, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `delegate_id` = ? AND `status` IN (0, 1) AND `start_time` <= NOW() AND `end_time` > NOW() ORDER BY `start_time` ASC", outOfOfficeRows, m.table)
+	var resp []*OutOfOffice
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, delegateId)
+	return resp, err
+}
+
+// CountOverlapping 统计与指定时间段重叠的未结束外出记录数
+func (m *defaultOutOfOfficeModel) CountOverlapping(ctx context.Context, employeeId string, startTime, endTime time.Time) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE `employee_id` = ? AND `status` IN (0, 1) AND `start_time` < ? AND `end_time` > ?", m.table)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, employeeId, endTime, startTime)
+	return count, err
+}
+
+// FindOverlappingByCompany 查询公司与时间段有重叠的待生效/生效中外出记录
+func (m *defaultOutOfOfficeModel) FindOverlappingByCompany(ctx context.Context, companyId string, startTime, endTime time.Time) ([]*OutOfOffice, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `company_id` = ? AND `status` IN (0, 1) AND `start_time` < ? AND `end_time` > ?", outOfOfficeRows, m.table)
+	var resp []*OutOfOffice
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, companyId, endTime, startTime)
+	return resp, err
+}
+
+// FindDueToStart 查询已到开始时间但尚未激活的记录
+func (m *defaultOutOfOfficeModel) FindDueToStart(ctx context.Context, limit int) ([]*OutOfOffice, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `status` = 0 AND `start_time` <= NOW() ORDER BY `start_time` ASC LIMIT ?", outOfOfficeRows, m.table)
+	var resp []*OutOfOffice
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, limit)
+	return resp, err
+}
+
+// FindDueToEnd 查询已到结束时间但仍处于生效中的记录
+func (m *defaultOutOfOfficeModel) FindDueToEnd(ctx context.Context, limit int) ([]*OutOfOffice, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `status` = 1 AND `end_time` <= NOW() ORDER BY `end_time` ASC LIMIT ?", outOfOfficeRows, m.table)
+	var resp []*OutOfOffice
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, limit)
+	return resp, err
+}
+
+// UpdateStatus 更新外出记录状态
+func (m *defaultOutOfOfficeModel) UpdateStatus(ctx context.Context, id string, status int) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `update_time` = ? WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, time.Now(), id)
+	return err
+}
+
+// IncrRoutedNotifications 代理期间转发通知计数加一
+func (m *defaultOutOfOfficeModel) IncrRoutedNotifications(ctx context.Context, id string) error {
+	query := fmt.Sprintf("UPDATE %s SET `routed_notifications` = `routed_notifications` + 1 WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
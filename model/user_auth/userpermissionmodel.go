@@ -9,6 +9,28 @@ import (
 
 var _ UserPermissionModel = (*customUserPermissionModel)(nil)
 
+// 授权类型常量
+const (
+	GrantTypeDirect     = 0 // 直接授权
+	GrantTypeRole       = 1 // 角色授权
+	GrantTypeDepartment = 2 // 部门授权
+	GrantTypeDelegation = 3 // 临时委派（代理他人职责）
+)
+
+// 资源类型常量
+const (
+	ResourceTypeMenu   = 0 // 菜单
+	ResourceTypeButton = 1 // 按钮
+	ResourceTypeAPI    = 2 // 接口
+	ResourceTypeData   = 3 // 数据
+)
+
+// 授权状态常量
+const (
+	PermissionStatusRevoked = 0 // 已撤销/已失效
+	PermissionStatusActive  = 1 // 正常
+)
+
 type (
 	// UserPermissionModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customUserPermissionModel.
@@ -16,6 +38,13 @@ type (
 		userPermissionModel
 		withSession(session sqlx.Session) UserPermissionModel
 		DeleteByUserIdAndGrantType(ctx context.Context, userId string, grantType int64) error
+
+		// 直接授权/临时委派相关方法
+		FindActiveByUserId(ctx context.Context, userId, companyId string) ([]*UserPermission, error)
+		FindByUserId(ctx context.Context, userId, companyId string, includeInactive bool) ([]*UserPermission, error)
+		FindActiveByUserIdAndCode(ctx context.Context, userId, companyId, permissionCode string, grantType int64) (*UserPermission, error)
+		FindExpired(ctx context.Context, limit int) ([]*UserPermission, error)
+		UpdateStatus(ctx context.Context, id string, status int) error
 	}
 
 	customUserPermissionModel struct {
@@ -40,3 +69,56 @@ func (m *customUserPermissionModel) DeleteByUserIdAndGrantType(ctx context.Conte
 	_, err := m.conn.ExecCtx(ctx, query, userId, grantType)
 	return err
 }
+
+// FindActiveByUserId 查询用户在指定公司下当前生效的授权（未撤销且未过期）
+// resource_id 存放授权所属的公司ID
+func (m *customUserPermissionModel) FindActiveByUserId(ctx context.Context, userId, companyId string) ([]*UserPermission, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `resource_id` = ? AND `status` = 1 AND (`expire_time` IS NULL OR `expire_time` > NOW()) ORDER BY `create_time` DESC", userPermissionRows, m.table)
+	var resp []*UserPermission
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userId, companyId)
+	return resp, err
+}
+
+// FindByUserId 查询用户在指定公司下的授权记录，includeInactive 为 true 时包含已撤销/已过期的记录
+func (m *customUserPermissionModel) FindByUserId(ctx context.Context, userId, companyId string, includeInactive bool) ([]*UserPermission, error) {
+	if !includeInactive {
+		return m.FindActiveByUserId(ctx, userId, companyId)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `resource_id` = ? ORDER BY `create_time` DESC", userPermissionRows, m.table)
+	var resp []*UserPermission
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userId, companyId)
+	return resp, err
+}
+
+// FindActiveByUserIdAndCode 查询用户在指定公司下某个权限码指定授权类型的生效授权
+func (m *customUserPermissionModel) FindActiveByUserIdAndCode(ctx context.Context, userId, companyId, permissionCode string, grantType int64) (*UserPermission, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `user_id` = ? AND `resource_id` = ? AND `permission_code` = ? AND `grant_type` = ? AND `status` = 1 AND (`expire_time` IS NULL OR `expire_time` > NOW()) LIMIT 1", userPermissionRows, m.table)
+	var resp UserPermission
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userId, companyId, permissionCode, grantType)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindExpired 查询已过期但仍处于生效状态的授权
+func (m *customUserPermissionModel) FindExpired(ctx context.Context, limit int) ([]*UserPermission, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE `status` = 1 AND `expire_time` IS NOT NULL AND `expire_time` <= NOW() ORDER BY `expire_time` ASC LIMIT ?", userPermissionRows, m.table)
+	var resp []*UserPermission
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, limit)
+	return resp, err
+}
+
+// UpdateStatus 更新授权状态
+func (m *customUserPermissionModel) UpdateStatus(ctx context.Context, id string, status int) error {
+	query := fmt.Sprintf("UPDATE %s SET `status` = ?, `update_time` = NOW() WHERE `id` = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, status, id)
+	return err
+}
@@ -43,7 +43,7 @@ type (
 		PermissionName string         `db:"permission_name"` // 权限名称
 		ResourceType   int64          `db:"resource_type"`   // 资源类型 0-菜单 1-按钮 2-接口 3-数据
 		ResourceId     sql.NullString `db:"resource_id"`     // 资源id
-		GrantType      int64          `db:"grant_type"`      // 授权类型 0-直接授权 1-角色授权 2-部门授权
+		GrantType      int64          `db:"grant_type"`      // 授权类型 0-直接授权 1-角色授权 2-部门授权 3-临时委派
 		GrantBy        sql.NullString `db:"grant_by"`        // 授权人id
 		ExpireTime     sql.NullTime   `db:"expire_time"`     // 过期时间
 		Status         int64          `db:"status"`          // 状态 0-禁用 1-正常
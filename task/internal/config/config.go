@@ -20,6 +20,8 @@ type Config struct {
 		// BaseURL 用于邮件中的前端访问地址，如：https://task.yourcompany.com
 		// 如果为空，则邮件中的链接会使用相对路径
 		BaseURL string `json:"baseURL"`
+		// ConfigSecret 系统配置表中加密项的密钥，为空时使用 JWT 密钥
+		ConfigSecret string `json:"configSecret,optional"`
 	} `json:"system"`
 
 	// 文件存储配置
@@ -37,6 +39,22 @@ type Config struct {
 			Bucket    string `json:"bucket"`
 			Region    string `json:"region"`
 		} `json:"cos"`
+		// Scanner 上传文件病毒扫描
+		Scanner struct {
+			// Type 扫描器类型: clamav、stub(测试用，只检出EICAR测试文件) 或 none(不扫描)
+			Type string `json:"type,default=none"`
+			// Address clamd 地址，本地 socket 路径（如 /var/run/clamav/clamd.ctl）或 host:port
+			Address string `json:"address,optional"`
+			// Timeout 单个文件的扫描超时
+			Timeout time.Duration `json:"timeout,default=60s"`
+			// FailOpen 扫描服务不可用时是否放行，默认拒绝上传
+			FailOpen bool `json:"failOpen,optional"`
+		} `json:"scanner,optional"`
+		// Preview 缩略图和预览图
+		Preview struct {
+			// PDFRenderer 渲染PDF首页的 pdftoppm 可执行文件路径，为空时不生成PDF预览
+			PDFRenderer string `json:"pdfRenderer,optional"`
+		} `json:"preview,optional"`
 	} `json:"fileStorage"`
 
 	// 数据库配置
@@ -112,6 +130,11 @@ type Config struct {
 		Model   string `json:"model"`
 	} `json:"glm"`
 
+	// 全文搜索配置
+	Search struct {
+		IndexPath string `json:"indexPath,default=./data/search_index"` // 索引目录
+	} `json:"search,optional"`
+
 	// 限流配置
 	RateLimit struct {
 		Enabled       bool `json:"enabled"`       // 是否启用限流
@@ -254,6 +277,11 @@ func (c *Config) ApplyEnvOverrides() {
 		c.System.BaseURL = v
 		overrideCount++
 	}
+	if v := os.Getenv("SYSTEM_CONFIG_SECRET"); v != "" {
+		c.System.ConfigSecret = v
+		overrideCount++
+		logx.Info("[Config] SYSTEM_CONFIG_SECRET 已从环境变量覆盖")
+	}
 
 	// FileStorage - COS配置
 	if v := os.Getenv("TENCENT_CLOUD_SECRET_ID"); v != "" {
@@ -287,6 +315,25 @@ func (c *Config) ApplyEnvOverrides() {
 		logx.Infof("[Config] FILE_STORAGE_TYPE 已从环境变量覆盖: %s", v)
 	}
 
+	if v := os.Getenv("CLAMAV_ADDRESS"); v != "" {
+		c.FileStorage.Scanner.Type = "clamav"
+		c.FileStorage.Scanner.Address = v
+		overrideCount++
+		logx.Infof("[Config] CLAMAV_ADDRESS 已从环境变量覆盖: %s", v)
+	}
+	if v := os.Getenv("PDF_RENDERER_PATH"); v != "" {
+		c.FileStorage.Preview.PDFRenderer = v
+		overrideCount++
+		logx.Infof("[Config] PDF_RENDERER_PATH 已从环境变量覆盖: %s", v)
+	}
+
+	// 全文搜索
+	if v := os.Getenv("SEARCH_INDEX_PATH"); v != "" {
+		c.Search.IndexPath = v
+		overrideCount++
+		logx.Infof("[Config] SEARCH_INDEX_PATH 已从环境变量覆盖: %s", v)
+	}
+
 	// Server (go-zero RestConf)
 	if v := os.Getenv("PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil {
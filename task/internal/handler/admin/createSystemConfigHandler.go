@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+
+	"task_Project/task/internal/logic/admin"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// CreateSystemConfigHandler 新增系统配置
+func CreateSystemConfigHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SystemConfigSaveRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.ValidationError(err.Error()))
+			return
+		}
+
+		l := admin.NewCreateSystemConfigLogic(r.Context(), svcCtx)
+		resp, err := l.CreateSystemConfig(&req)
+		if err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.InternalError(err.Error()))
+		} else {
+			httpx.WriteJson(w, http.StatusOK, resp)
+		}
+	}
+}
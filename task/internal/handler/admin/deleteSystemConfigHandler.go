@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+
+	"task_Project/task/internal/logic/admin"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// DeleteSystemConfigHandler 删除系统配置
+func DeleteSystemConfigHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SystemConfigDeleteRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.ValidationError(err.Error()))
+			return
+		}
+
+		l := admin.NewDeleteSystemConfigLogic(r.Context(), svcCtx)
+		resp, err := l.DeleteSystemConfig(&req)
+		if err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.InternalError(err.Error()))
+		} else {
+			httpx.WriteJson(w, http.StatusOK, resp)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+
+	"task_Project/task/internal/logic/admin"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// RecalculateStorageHandler 按附件记录重新统计公司存储用量
+func RecalculateStorageHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AdminStorageRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError(err.Error()))
+			return
+		}
+
+		l := admin.NewRecalculateStorageLogic(r.Context(), svcCtx)
+		resp, err := l.RecalculateStorage(&req)
+		if err != nil {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.InternalError(err.Error()))
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"net/http"
+
+	"task_Project/task/internal/logic/admin"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// SearchReindexHandler 重建全文搜索索引
+func SearchReindexHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := admin.NewSearchReindexLogic(r.Context(), svcCtx)
+		resp, err := l.SearchReindex()
+		if err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.InternalError(err.Error()))
+		} else {
+			httpx.WriteJson(w, http.StatusOK, resp)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+
+	"task_Project/task/internal/logic/admin"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// SystemConfigListHandler 系统配置列表
+func SystemConfigListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SystemConfigListRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.ValidationError(err.Error()))
+			return
+		}
+
+		l := admin.NewSystemConfigListLogic(r.Context(), svcCtx)
+		resp, err := l.SystemConfigList(&req)
+		if err != nil {
+			httpx.WriteJson(w, http.StatusOK, utils.Response.InternalError(err.Error()))
+		} else {
+			httpx.WriteJson(w, http.StatusOK, resp)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package auditlog
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/auditlog"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+)
+
+// 导出公司审计日志（CSV）
+func ExportAuditLogHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AuditLogSearchRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := auditlog.NewExportAuditLogLogic(r.Context(), svcCtx)
+		// 导出成功时直接写入文件内容，失败时返回统一的 JSON 响应
+		if resp := l.ExportAuditLog(&req, w); resp != nil {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/dataimport"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 20 << 20
+
+// 上传导入文件并校验（试运行，不写入数据）
+func UploadImportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UploadImportRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError("请上传导入文件"))
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		if len(data) > maxImportFileSize {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError("导入文件不能超过 20MB"))
+			return
+		}
+
+		l := dataimport.NewUploadImportLogic(r.Context(), svcCtx)
+		resp, err := l.UploadImport(&req, header.Filename, data)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
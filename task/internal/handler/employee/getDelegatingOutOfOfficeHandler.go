@@ -0,0 +1,25 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package employee
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/employee"
+	"task_Project/task/internal/svc"
+)
+
+// 获取当前代理中的外出记录
+func GetDelegatingOutOfOfficeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := employee.NewGetDelegatingOutOfOfficeLogic(r.Context(), svcCtx)
+		resp, err := l.GetDelegatingOutOfOffice()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -5,23 +5,34 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	admin "task_Project/task/internal/handler/admin"
 	ai "task_Project/task/internal/handler/ai"
+	auditlog "task_Project/task/internal/handler/auditlog"
 	auth "task_Project/task/internal/handler/auth"
+	board "task_Project/task/internal/handler/board"
 	checklist "task_Project/task/internal/handler/checklist"
 	company "task_Project/task/internal/handler/company"
+	customfield "task_Project/task/internal/handler/customfield"
 	dashboard "task_Project/task/internal/handler/dashboard"
+	dataimport "task_Project/task/internal/handler/dataimport"
 	department "task_Project/task/internal/handler/department"
 	employee "task_Project/task/internal/handler/employee"
+	export "task_Project/task/internal/handler/export"
 	handover "task_Project/task/internal/handler/handover"
+	label "task_Project/task/internal/handler/label"
 	notification "task_Project/task/internal/handler/notification"
+	permission "task_Project/task/internal/handler/permission"
 	position "task_Project/task/internal/handler/position"
 	role "task_Project/task/internal/handler/role"
+	search "task_Project/task/internal/handler/search"
 	task "task_Project/task/internal/handler/task"
 	tasknode "task_Project/task/internal/handler/tasknode"
+	timetrack "task_Project/task/internal/handler/timetrack"
 	upload "task_Project/task/internal/handler/upload"
 	user "task_Project/task/internal/handler/user"
+	workload "task_Project/task/internal/handler/workload"
 	"task_Project/task/internal/svc"
 
 	"github.com/zeromicro/go-zero/rest"
@@ -91,6 +102,24 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 			Path:    "/company/enable",
 			Handler: admin.EnableCompanyHandler(serverCtx),
 		},
+		{
+			// 查询公司存储用量和汇总
+			Method:  http.MethodPost,
+			Path:    "/storage/usage",
+			Handler: admin.AdminStorageUsageHandler(serverCtx),
+		},
+		{
+			// 修改公司存储配额
+			Method:  http.MethodPost,
+			Path:    "/storage/quota/update",
+			Handler: admin.UpdateStorageQuotaHandler(serverCtx),
+		},
+		{
+			// 按附件记录重新统计公司存储用量
+			Method:  http.MethodPost,
+			Path:    "/storage/recalculate",
+			Handler: admin.RecalculateStorageHandler(serverCtx),
+		},
 
 		{
 			// 获取登录记录
@@ -110,12 +139,48 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 			Path:    "/dashboard/stats",
 			Handler: admin.GetPlatformStatsHandler(serverCtx),
 		},
+		{
+			// 回填统计快照
+			Method:  http.MethodPost,
+			Path:    "/dashboard/stats/backfill",
+			Handler: admin.StatsBackfillHandler(serverCtx),
+		},
+		{
+			// 重建全文搜索索引
+			Method:  http.MethodPost,
+			Path:    "/search/reindex",
+			Handler: admin.SearchReindexHandler(serverCtx),
+		},
 		{
 			// 获取服务器指标
 			Method:  http.MethodGet,
 			Path:    "/metrics",
 			Handler: admin.MetricsHandler(serverCtx),
 		},
+		{
+			// 系统配置列表
+			Method:  http.MethodPost,
+			Path:    "/config/list",
+			Handler: admin.SystemConfigListHandler(serverCtx),
+		},
+		{
+			// 新增系统配置
+			Method:  http.MethodPost,
+			Path:    "/config/create",
+			Handler: admin.CreateSystemConfigHandler(serverCtx),
+		},
+		{
+			// 修改系统配置
+			Method:  http.MethodPut,
+			Path:    "/config/update",
+			Handler: admin.UpdateSystemConfigHandler(serverCtx),
+		},
+		{
+			// 删除系统配置
+			Method:  http.MethodPost,
+			Path:    "/config/delete",
+			Handler: admin.DeleteSystemConfigHandler(serverCtx),
+		},
 	}
 
 	// 为需要管理员认证的路由添加中间件
@@ -139,6 +204,24 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/ai"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 导出公司审计日志（CSV）
+				Method:  http.MethodPost,
+				Path:    "/export",
+				Handler: auditlog.ExportAuditLogHandler(serverCtx),
+			},
+			{
+				// 检索公司审计日志
+				Method:  http.MethodPost,
+				Path:    "/search",
+				Handler: auditlog.SearchAuditLogHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/auditlog"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -153,6 +236,12 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/logout",
 				Handler: auth.LogoutHandler(serverCtx),
 			},
+			{
+				// 获取当前用户所在的全部公司
+				Method:  http.MethodPost,
+				Path:    "/memberships",
+				Handler: auth.GetMembershipsHandler(serverCtx),
+			},
 			{
 				// 用户注册
 				Method:  http.MethodPost,
@@ -171,6 +260,12 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/send-code",
 				Handler: auth.SendVerificationCodeHandler(serverCtx),
 			},
+			{
+				// 切换当前公司（重新签发令牌）
+				Method:  http.MethodPost,
+				Path:    "/switch-company",
+				Handler: auth.SwitchCompanyHandler(serverCtx),
+			},
 		},
 		rest.WithPrefix("/api/v1/auth"),
 	)
@@ -405,6 +500,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/leave/approve",
 				Handler: employee.ConfirmLeaveApprovalHandler(serverCtx),
 			},
+			{
+				// 生成或刷新离职交接计划
+				Method:  http.MethodPost,
+				Path:    "/leave/plan",
+				Handler: employee.GenerateOffboardingPlanHandler(serverCtx),
+			},
+			{
+				// 执行离职交接计划
+				Method:  http.MethodPost,
+				Path:    "/leave/plan/apply",
+				Handler: employee.ApplyOffboardingPlanHandler(serverCtx),
+			},
+			{
+				// 设置离职交接事项的接收人
+				Method:  http.MethodPost,
+				Path:    "/leave/plan/assign",
+				Handler: employee.AssignOffboardingPlanHandler(serverCtx),
+			},
+			{
+				// 查询离职交接计划
+				Method:  http.MethodPost,
+				Path:    "/leave/plan/get",
+				Handler: employee.GetOffboardingPlanHandler(serverCtx),
+			},
 			{
 				// 获取员工列表
 				Method:  http.MethodPost,
@@ -417,6 +536,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/me",
 				Handler: employee.GetSelfEmployeeHandler(serverCtx),
 			},
+			{
+				// 取消外出代理
+				Method:  http.MethodPost,
+				Path:    "/outofoffice/cancel",
+				Handler: employee.CancelOutOfOfficeHandler(serverCtx),
+			},
+			{
+				// 获取当前代理中的外出记录
+				Method:  http.MethodGet,
+				Path:    "/outofoffice/delegating",
+				Handler: employee.GetDelegatingOutOfOfficeHandler(serverCtx),
+			},
+			{
+				// 获取外出代理列表
+				Method:  http.MethodPost,
+				Path:    "/outofoffice/list",
+				Handler: employee.GetOutOfOfficeListHandler(serverCtx),
+			},
+			{
+				// 设置外出代理
+				Method:  http.MethodPost,
+				Path:    "/outofoffice/set",
+				Handler: employee.SetOutOfOfficeHandler(serverCtx),
+			},
 			{
 				// 更新员工直属上级
 				Method:  http.MethodPut,
@@ -433,6 +576,204 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/employee"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建报表导出任务
+				Method:  http.MethodPost,
+				Path:    "/create",
+				Handler: export.CreateExportHandler(serverCtx),
+			},
+			{
+				// 删除导出记录及文件
+				Method:  http.MethodPost,
+				Path:    "/delete",
+				Handler: export.DeleteExportHandler(serverCtx),
+			},
+			{
+				// 查询导出任务
+				Method:  http.MethodPost,
+				Path:    "/get",
+				Handler: export.GetExportHandler(serverCtx),
+			},
+			{
+				// 我的导出记录
+				Method:  http.MethodPost,
+				Path:    "/list",
+				Handler: export.ExportListHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/export"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 执行导入（中断后从断点继续）
+				Method:  http.MethodPost,
+				Path:    "/apply",
+				Handler: dataimport.ApplyImportHandler(serverCtx),
+			},
+			{
+				// 导入文件的列说明
+				Method:  http.MethodPost,
+				Path:    "/columns",
+				Handler: dataimport.ImportColumnsHandler(serverCtx),
+			},
+			{
+				// 删除导入记录
+				Method:  http.MethodPost,
+				Path:    "/delete",
+				Handler: dataimport.DeleteImportHandler(serverCtx),
+			},
+			{
+				// 查询导入任务及校验报告
+				Method:  http.MethodPost,
+				Path:    "/get",
+				Handler: dataimport.GetImportHandler(serverCtx),
+			},
+			{
+				// 公司导入记录
+				Method:  http.MethodPost,
+				Path:    "/list",
+				Handler: dataimport.ImportListHandler(serverCtx),
+			},
+			{
+				// 上传导入文件并校验（试运行，不写入数据）
+				Method:  http.MethodPost,
+				Path:    "/upload",
+				Handler: dataimport.UploadImportHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/import"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 全文搜索任务、节点、清单、评论和附件
+				Method:  http.MethodPost,
+				Path:    "/query",
+				Handler: search.FullTextSearchHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/search"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 设置任务的标签
+				Method:  http.MethodPost,
+				Path:    "/assign",
+				Handler: label.SetTaskLabelsHandler(serverCtx),
+			},
+			{
+				// 创建任务标签
+				Method:  http.MethodPost,
+				Path:    "/create",
+				Handler: label.CreateTaskLabelHandler(serverCtx),
+			},
+			{
+				// 删除任务标签
+				Method:  http.MethodPost,
+				Path:    "/delete",
+				Handler: label.DeleteTaskLabelHandler(serverCtx),
+			},
+			{
+				// 公司的任务标签
+				Method:  http.MethodGet,
+				Path:    "/list",
+				Handler: label.TaskLabelListHandler(serverCtx),
+			},
+			{
+				// 更新任务标签
+				Method:  http.MethodPut,
+				Path:    "/update",
+				Handler: label.UpdateTaskLabelHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/label"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建自定义字段
+				Method:  http.MethodPost,
+				Path:    "/create",
+				Handler: customfield.CreateCustomFieldHandler(serverCtx),
+			},
+			{
+				// 删除自定义字段及其全部值
+				Method:  http.MethodPost,
+				Path:    "/delete",
+				Handler: customfield.DeleteCustomFieldHandler(serverCtx),
+			},
+			{
+				// 公司的自定义字段
+				Method:  http.MethodPost,
+				Path:    "/list",
+				Handler: customfield.CustomFieldListHandler(serverCtx),
+			},
+			{
+				// 更新自定义字段
+				Method:  http.MethodPut,
+				Path:    "/update",
+				Handler: customfield.UpdateCustomFieldHandler(serverCtx),
+			},
+			{
+				// 查询任务或节点的自定义字段值
+				Method:  http.MethodPost,
+				Path:    "/values/get",
+				Handler: customfield.GetCustomFieldValuesHandler(serverCtx),
+			},
+			{
+				// 填写任务或节点的自定义字段
+				Method:  http.MethodPost,
+				Path:    "/values/set",
+				Handler: customfield.SetCustomFieldValuesHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/customfield"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建任务或部门看板
+				Method:  http.MethodPost,
+				Path:    "/create",
+				Handler: board.CreateBoardHandler(serverCtx),
+			},
+			{
+				// 删除看板
+				Method:  http.MethodPost,
+				Path:    "/delete",
+				Handler: board.DeleteBoardHandler(serverCtx),
+			},
+			{
+				// 查询看板及各列的卡片
+				Method:  http.MethodPost,
+				Path:    "/get",
+				Handler: board.GetBoardHandler(serverCtx),
+			},
+			{
+				// 拖动卡片到指定列和位置
+				Method:  http.MethodPost,
+				Path:    "/move",
+				Handler: board.MoveBoardCardHandler(serverCtx),
+			},
+			{
+				// 更新看板名称和列
+				Method:  http.MethodPut,
+				Path:    "/update",
+				Handler: board.UpdateBoardHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/board"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -459,6 +800,12 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/get",
 				Handler: handover.GetHandoverHandler(serverCtx),
 			},
+			{
+				// 查询任务中可交接的节点角色、清单和附件
+				Method:  http.MethodPost,
+				Path:    "/items",
+				Handler: handover.GetHandoverItemsHandler(serverCtx),
+			},
 			{
 				// 获取交接列表
 				Method:  http.MethodPost,
@@ -511,6 +858,36 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/notification"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 临时委派：将员工的全部权限临时授予代理人
+				Method:  http.MethodPost,
+				Path:    "/delegate",
+				Handler: permission.DelegatePermissionHandler(serverCtx),
+			},
+			{
+				// 直接授予员工权限
+				Method:  http.MethodPost,
+				Path:    "/grant",
+				Handler: permission.GrantPermissionHandler(serverCtx),
+			},
+			{
+				// 查询员工的直接授权列表
+				Method:  http.MethodPost,
+				Path:    "/list",
+				Handler: permission.UserPermissionListHandler(serverCtx),
+			},
+			{
+				// 撤销授权
+				Method:  http.MethodPost,
+				Path:    "/revoke",
+				Handler: permission.RevokePermissionHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/permission"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -603,6 +980,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 
 	server.AddRoutes(
 		[]rest.Route{
+			{
+				// 延期风险任务列表
+				Method:  http.MethodPost,
+				Path:    "/at-risk",
+				Handler: task.GetAtRiskTasksHandler(serverCtx),
+			},
+			{
+				// 任务燃尽图
+				Method:  http.MethodPost,
+				Path:    "/burndown",
+				Handler: task.GetTaskBurndownHandler(serverCtx),
+			},
+			{
+				// 任务燃起图
+				Method:  http.MethodPost,
+				Path:    "/burnup",
+				Handler: task.GetTaskBurnupHandler(serverCtx),
+			},
+			{
+				// 任务累积流图
+				Method:  http.MethodPost,
+				Path:    "/cfd",
+				Handler: task.GetTaskCumulativeFlowHandler(serverCtx),
+			},
 			{
 				// 创建任务评论
 				Method:  http.MethodPost,
@@ -627,6 +1028,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/comment/list",
 				Handler: task.GetTaskCommentsHandler(serverCtx),
 			},
+			{
+				// 切换任务评论表情回应
+				Method:  http.MethodPost,
+				Path:    "/comment/react",
+				Handler: task.ReactTaskCommentHandler(serverCtx),
+			},
+			{
+				// 分页获取任务评论的回复
+				Method:  http.MethodPost,
+				Path:    "/comment/replies",
+				Handler: task.GetTaskCommentRepliesHandler(serverCtx),
+			},
+			{
+				// 获取任务评论的历史版本
+				Method:  http.MethodPost,
+				Path:    "/comment/revisions",
+				Handler: task.GetTaskCommentRevisionsHandler(serverCtx),
+			},
+			{
+				// 编辑任务评论
+				Method:  http.MethodPost,
+				Path:    "/comment/update",
+				Handler: task.UpdateTaskCommentHandler(serverCtx),
+			},
 			{
 				// 任务完成
 				Method:  http.MethodPost,
@@ -657,6 +1082,12 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/dispatch",
 				Handler: task.AutoDispatchHandler(serverCtx),
 			},
+			{
+				// 任务完成预测
+				Method:  http.MethodPost,
+				Path:    "/forecast",
+				Handler: task.GetTaskForecastHandler(serverCtx),
+			},
 			{
 				// 获取任务信息
 				Method:  http.MethodPost,
@@ -675,12 +1106,60 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/progress",
 				Handler: task.UpdateTaskProgressHandler(serverCtx),
 			},
+			{
+				// 任务高级搜索
+				Method:  http.MethodPost,
+				Path:    "/search",
+				Handler: task.SearchTasksHandler(serverCtx),
+			},
 			{
 				// 更新任务信息
 				Method:  http.MethodPut,
 				Path:    "/update",
 				Handler: task.UpdateTaskHandler(serverCtx),
 			},
+			{
+				// 创建任务视图
+				Method:  http.MethodPost,
+				Path:    "/view/create",
+				Handler: task.CreateTaskViewHandler(serverCtx),
+			},
+			{
+				// 删除任务视图
+				Method:  http.MethodPost,
+				Path:    "/view/delete",
+				Handler: task.DeleteTaskViewHandler(serverCtx),
+			},
+			{
+				// 获取可用的任务视图
+				Method:  http.MethodGet,
+				Path:    "/view/list",
+				Handler: task.GetTaskViewsHandler(serverCtx),
+			},
+			{
+				// 更新任务视图
+				Method:  http.MethodPut,
+				Path:    "/view/update",
+				Handler: task.UpdateTaskViewHandler(serverCtx),
+			},
+			{
+				// 获取任务或节点的关注者
+				Method:  http.MethodPost,
+				Path:    "/watch/list",
+				Handler: task.GetTaskWatchersHandler(serverCtx),
+			},
+			{
+				// 关注任务或节点
+				Method:  http.MethodPost,
+				Path:    "/watch/subscribe",
+				Handler: task.WatchTaskHandler(serverCtx),
+			},
+			{
+				// 取消关注任务或节点
+				Method:  http.MethodPost,
+				Path:    "/watch/unsubscribe",
+				Handler: task.UnwatchTaskHandler(serverCtx),
+			},
 		},
 		rest.WithPrefix("/api/v1/task"),
 	)
@@ -733,6 +1212,84 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/tasknode"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 手工填报工时
+				Method:  http.MethodPost,
+				Path:    "/entry/create",
+				Handler: timetrack.CreateTimeEntryHandler(serverCtx),
+			},
+			{
+				// 删除工时记录
+				Method:  http.MethodPost,
+				Path:    "/entry/delete",
+				Handler: timetrack.DeleteTimeEntryHandler(serverCtx),
+			},
+			{
+				// 查询工时记录
+				Method:  http.MethodPost,
+				Path:    "/entry/list",
+				Handler: timetrack.TimeEntryListHandler(serverCtx),
+			},
+			{
+				// 修改工时记录
+				Method:  http.MethodPut,
+				Path:    "/entry/update",
+				Handler: timetrack.UpdateTimeEntryHandler(serverCtx),
+			},
+			{
+				// 预计与实际工时对比报表
+				Method:  http.MethodPost,
+				Path:    "/report",
+				Handler: timetrack.TimeReportHandler(serverCtx),
+			},
+			{
+				// 获取当前计时
+				Method:  http.MethodPost,
+				Path:    "/timer/current",
+				Handler: timetrack.GetRunningTimerHandler(serverCtx),
+			},
+			{
+				// 开始计时
+				Method:  http.MethodPost,
+				Path:    "/timer/start",
+				Handler: timetrack.StartTimerHandler(serverCtx),
+			},
+			{
+				// 停止计时
+				Method:  http.MethodPost,
+				Path:    "/timer/stop",
+				Handler: timetrack.StopTimerHandler(serverCtx),
+			},
+			{
+				// 获取周工时单
+				Method:  http.MethodPost,
+				Path:    "/timesheet/get",
+				Handler: timetrack.GetTimesheetHandler(serverCtx),
+			},
+			{
+				// 待我审批的工时单
+				Method:  http.MethodPost,
+				Path:    "/timesheet/pending",
+				Handler: timetrack.PendingTimesheetsHandler(serverCtx),
+			},
+			{
+				// 审批周工时单
+				Method:  http.MethodPost,
+				Path:    "/timesheet/review",
+				Handler: timetrack.ReviewTimesheetHandler(serverCtx),
+			},
+			{
+				// 提交周工时单
+				Method:  http.MethodPost,
+				Path:    "/timesheet/submit",
+				Handler: timetrack.SubmitTimesheetHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/timetrack"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -789,12 +1346,48 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/file/proxy",
 				Handler: upload.ProxyFileHandler(serverCtx),
 			},
+			{
+				// 上传附件新版本
+				Method:  http.MethodPost,
+				Path:    "/file/version",
+				Handler: upload.UploadFileVersionHandler(serverCtx),
+			},
+			{
+				// 恢复附件历史版本
+				Method:  http.MethodPost,
+				Path:    "/file/version/restore",
+				Handler: upload.RestoreFileVersionHandler(serverCtx),
+			},
+			{
+				// 获取附件版本列表
+				Method:  http.MethodPost,
+				Path:    "/file/versions",
+				Handler: upload.GetFileVersionsHandler(serverCtx),
+			},
 			{
 				// 获取我的附件列表
 				Method:  http.MethodPost,
 				Path:    "/my/list",
 				Handler: upload.GetMyAttachmentsHandler(serverCtx),
 			},
+			{
+				// 获取公司上传策略
+				Method:  http.MethodPost,
+				Path:    "/policy/get",
+				Handler: upload.GetUploadPolicyHandler(serverCtx),
+			},
+			{
+				// 修改公司上传策略
+				Method:  http.MethodPost,
+				Path:    "/policy/update",
+				Handler: upload.UpdateUploadPolicyHandler(serverCtx),
+			},
+			{
+				// 查询公司和当前员工的存储用量
+				Method:  http.MethodPost,
+				Path:    "/storage/usage",
+				Handler: upload.GetStorageUsageHandler(serverCtx),
+			},
 			{
 				// 获取任务附件列表
 				Method:  http.MethodPost,
@@ -811,6 +1404,44 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/upload"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 取消上传会话并删除已上传的内容
+				Method:  http.MethodPost,
+				Path:    "/session/abort",
+				Handler: upload.AbortUploadSessionHandler(serverCtx),
+			},
+			{
+				// 完成上传：合并分片、校验并检查文件后创建附件
+				Method:  http.MethodPost,
+				Path:    "/session/complete",
+				Handler: upload.CompleteUploadSessionHandler(serverCtx),
+			},
+			{
+				// 创建大文件上传会话（分片上传或预签名直传）
+				Method:  http.MethodPost,
+				Path:    "/session/init",
+				Handler: upload.InitUploadSessionHandler(serverCtx),
+			},
+			{
+				// 上传分片（multipart 的 chunk 字段）
+				Method:  http.MethodPost,
+				Path:    "/session/part",
+				Handler: upload.UploadSessionPartHandler(serverCtx),
+			},
+			{
+				// 查询上传会话和已上传的分片，用于断点续传
+				Method:  http.MethodPost,
+				Path:    "/session/parts",
+				Handler: upload.GetUploadSessionPartsHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/upload"),
+		rest.WithTimeout(3600000*time.Millisecond),
+		rest.WithMaxBytes(73400320),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -822,4 +1453,28 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		},
 		rest.WithPrefix("/api/v1/user"),
 	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 设置员工每周可用工时
+				Method:  http.MethodPut,
+				Path:    "/capacity",
+				Handler: workload.SetCapacityHandler(serverCtx),
+			},
+			{
+				// 员工工作负载
+				Method:  http.MethodPost,
+				Path:    "/employee",
+				Handler: workload.EmployeeWorkloadHandler(serverCtx),
+			},
+			{
+				// 部门工作负载热力图
+				Method:  http.MethodPost,
+				Path:    "/heatmap",
+				Handler: workload.WorkloadHeatmapHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/workload"),
+	)
 }
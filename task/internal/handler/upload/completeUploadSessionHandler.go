@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/middleware"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+)
+
+// 完成上传：合并分片、校验并检查文件后创建附件
+func CompleteUploadSessionHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UploadSessionRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := upload.NewCompleteUploadSessionLogic(r.Context(), svcCtx)
+		resp, err := l.CompleteUploadSession(&req, middleware.ClientIP(r))
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
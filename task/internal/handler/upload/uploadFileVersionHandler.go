@@ -0,0 +1,41 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/middleware"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 上传附件新版本
+func UploadFileVersionHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UploadFileVersionRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		// 新版本文件通过 multipart 的 file 字段上传
+		fileData, handler, err := r.FormFile("file")
+		if err != nil {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError("请上传新版本文件"))
+			return
+		}
+		defer fileData.Close()
+
+		l := upload.NewUploadFileVersionLogic(r.Context(), svcCtx)
+		resp, err := l.UploadFileVersion(&req, handler, fileData, middleware.ClientIP(r))
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
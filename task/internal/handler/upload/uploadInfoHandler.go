@@ -8,6 +8,7 @@ import (
 
 	"github.com/zeromicro/go-zero/rest/httpx"
 	"task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/middleware"
 	"task_Project/task/internal/svc"
 	"task_Project/task/internal/types"
 	"task_Project/task/internal/utils"
@@ -33,7 +34,7 @@ func UploadInfoHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 		l := upload.NewUploadInfoLogic(r.Context(), svcCtx)
-		resp, err := l.UploadInfo(&req, handler, fileData)
+		resp, err := l.UploadInfo(&req, handler, fileData, middleware.ClientIP(r))
 		if err != nil {
 			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError(err.Error()))
 		} else {
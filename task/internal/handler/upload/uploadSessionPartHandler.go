@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 上传分片（multipart 的 chunk 字段）
+func UploadSessionPartHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UploadSessionPartRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		// 分片内容通过 multipart 的 chunk 字段上传
+		fileData, handler, err := r.FormFile("chunk")
+		if err != nil {
+			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError("请上传分片内容"))
+			return
+		}
+		defer fileData.Close()
+
+		l := upload.NewUploadSessionPartLogic(r.Context(), svcCtx)
+		resp, err := l.UploadSessionPart(&req, handler, fileData)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
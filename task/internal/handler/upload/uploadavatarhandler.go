@@ -5,6 +5,7 @@ import (
 
 	"github.com/zeromicro/go-zero/rest/httpx"
 	"task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/middleware"
 	"task_Project/task/internal/svc"
 	"task_Project/task/internal/types"
 	"task_Project/task/internal/utils"
@@ -28,7 +29,7 @@ func UploadAvatarHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		defer fileData.Close()
 
 		l := upload.NewUploadAvatarLogic(r.Context(), svcCtx)
-		resp, err := l.UploadAvatar(&req, handler, fileData)
+		resp, err := l.UploadAvatar(&req, handler, fileData, middleware.ClientIP(r))
 		if err != nil {
 			httpx.OkJsonCtx(r.Context(), w, utils.Response.ValidationError(err.Error()))
 		} else {
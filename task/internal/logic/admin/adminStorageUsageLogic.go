@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"context"
+
+	uploadlogic "task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type AdminStorageUsageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询公司存储用量和汇总
+func NewAdminStorageUsageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AdminStorageUsageLogic {
+	return &AdminStorageUsageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AdminStorageUsage 公司的存储用量、配额以及按员工、任务、模块和文件类型的汇总
+func (l *AdminStorageUsageLogic) AdminStorageUsage(req *types.AdminStorageRequest) (*types.BaseResponse, error) {
+	if _, err := l.svcCtx.CompanyModel.FindOne(l.ctx, req.CompanyID); err != nil {
+		return utils.Response.Error(404, "公司不存在"), nil
+	}
+
+	info, err := uploadlogic.BuildStorageUsageInfo(l.ctx, l.svcCtx, req.CompanyID, "", true)
+	if err != nil {
+		logx.Errorf("查询公司存储用量失败: companyId=%s, err=%v", req.CompanyID, err)
+		return utils.Response.Error(500, "查询存储用量失败"), nil
+	}
+	return utils.Response.Success(info), nil
+}
@@ -56,6 +56,8 @@ func (l *CompanyListLogic) CompanyList(req *types.AdminCompanyListRequest) (resp
 			departmentCount, _ := l.svcCtx.DepartmentModel.GetDepartmentCountByCompany(l.ctx, c.Id)
 			// 获取任务数量
 			taskCount, _ := l.svcCtx.TaskModel.GetTaskCountByCompany(l.ctx, c.Id)
+			// 获取存储用量和配额
+			storageUsed, storageQuota := l.storageUsage(c.Id)
 
 			companies = append(companies, types.AdminCompanyInfo{
 				ID:                c.Id,
@@ -71,6 +73,8 @@ func (l *CompanyListLogic) CompanyList(req *types.AdminCompanyListRequest) (resp
 				EmployeeCount:     employeeCount,
 				DepartmentCount:   departmentCount,
 				TaskCount:         taskCount,
+				StorageUsedBytes:  storageUsed,
+				StorageQuotaBytes: storageQuota,
 				CreateTime:        c.CreateTime.Format("2006-01-02 15:04:05"),
 				UpdateTime:        c.UpdateTime.Format("2006-01-02 15:04:05"),
 			})
@@ -91,6 +95,8 @@ func (l *CompanyListLogic) CompanyList(req *types.AdminCompanyListRequest) (resp
 			departmentCount, _ := l.svcCtx.DepartmentModel.GetDepartmentCountByCompany(l.ctx, c.Id)
 			// 获取任务数量
 			taskCount, _ := l.svcCtx.TaskModel.GetTaskCountByCompany(l.ctx, c.Id)
+			// 获取存储用量和配额
+			storageUsed, storageQuota := l.storageUsage(c.Id)
 
 			companies = append(companies, types.AdminCompanyInfo{
 				ID:                c.Id,
@@ -106,6 +112,8 @@ func (l *CompanyListLogic) CompanyList(req *types.AdminCompanyListRequest) (resp
 				EmployeeCount:     employeeCount,
 				DepartmentCount:   departmentCount,
 				TaskCount:         taskCount,
+				StorageUsedBytes:  storageUsed,
+				StorageQuotaBytes: storageQuota,
 				CreateTime:        c.CreateTime.Format("2006-01-02 15:04:05"),
 				UpdateTime:        c.UpdateTime.Format("2006-01-02 15:04:05"),
 			})
@@ -130,3 +138,12 @@ func (l *CompanyListLogic) CompanyList(req *types.AdminCompanyListRequest) (resp
 		"pageSize": pageSize,
 	}), nil
 }
+
+// storageUsage 公司已使用的存储和公司配额（字节），查询失败时用量为 0
+func (l *CompanyListLogic) storageUsage(companyID string) (int64, int64) {
+	var used int64
+	if usage, err := l.svcCtx.StorageQuotaService.UsageOf(l.ctx, companyID, ""); err == nil {
+		used = usage.UsedBytes
+	}
+	return used, l.svcCtx.StorageQuotaService.Limits(l.ctx, companyID).CompanyBytes
+}
@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateSystemConfigLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 新增系统配置
+func NewCreateSystemConfigLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateSystemConfigLogic {
+	return &CreateSystemConfigLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateSystemConfig 新增配置项，配置键已存在时返回错误
+func (l *CreateSystemConfigLogic) CreateSystemConfig(req *types.SystemConfigSaveRequest) (*types.BaseResponse, error) {
+	req.ConfigKey = strings.TrimSpace(req.ConfigKey)
+	if req.ConfigKey == "" {
+		return utils.Response.ValidationError("配置键不能为空"), nil
+	}
+	if _, exists := l.svcCtx.SystemConfigService.Row(req.ConfigKey); exists {
+		return utils.Response.Error(409, "配置项已存在"), nil
+	}
+	if resp := saveSystemConfig(l.ctx, l.svcCtx, req, false); resp != nil {
+		return resp, nil
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "config", "create", "新增系统配置: "+req.ConfigKey, adminID, "", "")
+	}
+	return utils.Response.Success("配置已新增"), nil
+}
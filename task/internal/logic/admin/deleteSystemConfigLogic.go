@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteSystemConfigLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除系统配置
+func NewDeleteSystemConfigLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteSystemConfigLogic {
+	return &DeleteSystemConfigLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteSystemConfig 删除配置项，内置配置项删除后恢复默认值
+func (l *DeleteSystemConfigLogic) DeleteSystemConfig(req *types.SystemConfigDeleteRequest) (*types.BaseResponse, error) {
+	if err := l.svcCtx.SystemConfigService.Delete(l.ctx, req.ConfigKey); err != nil {
+		if errors.Is(err, svc.ErrSettingNotFound) {
+			return utils.Response.Error(404, "配置项不存在"), nil
+		}
+		l.Errorf("删除系统配置失败: key=%s, err=%v", req.ConfigKey, err)
+		return utils.Response.Error(500, "删除系统配置失败"), nil
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "config", "delete", "删除系统配置: "+req.ConfigKey, adminID, "", "")
+	}
+	return utils.Response.Success("配置已删除"), nil
+}
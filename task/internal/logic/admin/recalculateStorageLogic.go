@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RecalculateStorageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 按附件记录重新统计公司存储用量
+func NewRecalculateStorageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RecalculateStorageLogic {
+	return &RecalculateStorageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RecalculateStorage 按附件记录重新统计公司和各员工的用量计数，用于修正计数偏差和统计启用配额前上传的附件
+func (l *RecalculateStorageLogic) RecalculateStorage(req *types.AdminStorageRequest) (*types.BaseResponse, error) {
+	if _, err := l.svcCtx.CompanyModel.FindOne(l.ctx, req.CompanyID); err != nil {
+		return utils.Response.Error(404, "公司不存在"), nil
+	}
+
+	total, err := l.svcCtx.StorageQuotaService.Recalculate(l.ctx, req.CompanyID)
+	if err != nil {
+		logx.Errorf("重新统计公司存储用量失败: companyId=%s, err=%v", req.CompanyID, err)
+		return utils.Response.Error(500, "重新统计存储用量失败"), nil
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "storage", "recalculate", "重新统计公司存储用量: "+req.CompanyID, adminID, "", "")
+	}
+	return utils.Response.Success(types.StorageUsageItem{
+		UsedBytes:  total.UsedBytes,
+		FileCount:  total.FileCount,
+		QuotaBytes: l.svcCtx.StorageQuotaService.Limits(l.ctx, req.CompanyID).CompanyBytes,
+	}), nil
+}
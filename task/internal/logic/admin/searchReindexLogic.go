@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SearchReindexLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 重建全文搜索索引
+func NewSearchReindexLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchReindexLogic {
+	return &SearchReindexLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SearchReindex 在后台从数据库重建全文搜索索引，用于索引损坏或丢失事件后的修复，同一时间只允许一个重建任务
+func (l *SearchReindexLogic) SearchReindex() (*types.BaseResponse, error) {
+	if l.svcCtx.SearchService == nil {
+		return utils.Response.BusinessError("search_unavailable"), nil
+	}
+	if l.svcCtx.SearchService.Rebuilding() {
+		return utils.Response.BusinessError("search_rebuild_running"), nil
+	}
+
+	go func() {
+		if _, err := l.svcCtx.SearchService.Rebuild(context.Background()); err != nil && !errors.Is(err, svc.ErrSearchRebuildRunning) {
+			logx.Errorf("[Search] 重建索引失败: %v", err)
+		}
+	}()
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "search", "reindex", "重建全文搜索索引", adminID, "", "")
+	}
+	return utils.Response.Success("索引重建已开始，完成前搜索结果可能不完整"), nil
+}
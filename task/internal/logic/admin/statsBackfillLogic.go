@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type StatsBackfillLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 回填统计快照
+func NewStatsBackfillLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StatsBackfillLogic {
+	return &StatsBackfillLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// StatsBackfill 按日期范围重新聚合统计快照，在后台执行，同一时间只允许一个回填任务
+func (l *StatsBackfillLogic) StatsBackfill(req *types.StatsBackfillRequest) (*types.BaseResponse, error) {
+	start, err1 := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	end, err2 := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+	if err1 != nil || err2 != nil {
+		return utils.Response.ValidationError("日期格式应为 YYYY-MM-DD"), nil
+	}
+	if req.CompanyId != "" {
+		if _, err := l.svcCtx.CompanyModel.FindOne(l.ctx, req.CompanyId); err != nil {
+			return utils.Response.Error(404, "公司不存在"), nil
+		}
+	}
+
+	if err := l.svcCtx.AnalyticsService.Backfill(req.CompanyId, start, end); err != nil {
+		switch {
+		case errors.Is(err, svc.ErrStatsRangeInvalid):
+			return utils.Response.ValidationError(fmt.Sprintf("日期范围无效：结束日期不能早于开始日期或晚于今天，且不超过 %d 天", svc.MaxStatsBackfillDays)), nil
+		case errors.Is(err, svc.ErrStatsBackfillRunning):
+			return utils.Response.Error(409, "已有回填任务正在执行，请稍后再试"), nil
+		}
+		l.Errorf("回填统计快照失败: %v", err)
+		return utils.Response.Error(500, "回填统计快照失败"), nil
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "stats", "backfill",
+			fmt.Sprintf("回填统计快照: companyId=%s, %s~%s", req.CompanyId, req.StartDate, req.EndDate), adminID, "", "")
+	}
+	return utils.Response.Success("回填任务已开始，完成后统计数据自动更新"), nil
+}
@@ -2,9 +2,9 @@ package admin
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	adminModel "task_Project/model/admin"
 	"task_Project/task/internal/svc"
 	"task_Project/task/internal/types"
 	"task_Project/task/internal/utils"
@@ -27,144 +27,71 @@ func NewGetPlatformStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *
 	}
 }
 
-func (l *GetPlatformStatsLogic) GetPlatformStats() (resp *types.BaseResponse, err error) {
-	// 1. 获取公司总数
-	totalCompanies, err := l.svcCtx.CompanyModel.GetCompanyCount(l.ctx)
-	if err != nil {
-		logx.Errorf("获取公司总数失败: %v", err)
-		totalCompanies = 0
-	}
+// 平台趋势的天数
+const platformTrendDays = 30
 
-	// 2. 获取用户总数
-	totalUsers, err := l.svcCtx.UserModel.GetUserCount(l.ctx)
-	if err != nil {
-		logx.Errorf("获取用户总数失败: %v", err)
-		totalUsers = 0
-	}
+// 公司员工分布展示的公司数
+const platformDistributionLimit = 100
 
-	// 3. 获取任务总数
-	totalTasks, err := l.svcCtx.TaskModel.GetTaskCount(l.ctx)
-	if err != nil {
-		logx.Errorf("获取任务总数失败: %v", err)
-		totalTasks = 0
-	}
-
-	// 4. 获取员工总数
-	totalEmployees, err := l.svcCtx.EmployeeModel.GetEmployeeCount(l.ctx)
+// GetPlatformStats 平台统计读取每日统计快照，当天没有数据时先统计一次
+func (l *GetPlatformStatsLogic) GetPlatformStats() (resp *types.BaseResponse, err error) {
+	rows, err := l.svcCtx.AnalyticsService.PlatformTrend(l.ctx, platformTrendDays)
 	if err != nil {
-		logx.Errorf("获取员工总数失败: %v", err)
-		totalEmployees = 0
+		logx.Errorf("获取平台统计快照失败: %v", err)
+		return utils.Response.InternalError("获取统计数据失败"), nil
 	}
 
-	// 5. 获取公司员工分布
-	companyDistribution, err := l.getCompanyEmployeeDistribution()
-	if err != nil {
-		logx.Errorf("获取公司员工分布失败: %v", err)
-		companyDistribution = []types.CompanyEmployeeCount{}
+	statsResp := types.PlatformStatsResponse{
+		CompanyDistribution: l.getCompanyEmployeeDistribution(),
+		UserTrend:           make([]types.TrendData, 0, platformTrendDays),
+		TaskTrend:           make([]types.TrendData, 0, platformTrendDays),
 	}
-
-	// 6. 获取用户注册趋势（最近30天）
-	userTrend, err := l.getUserRegistrationTrend(30)
-	if err != nil {
-		logx.Errorf("获取用户注册趋势失败: %v", err)
-		userTrend = []types.TrendData{}
+	if len(rows) > 0 {
+		latest := rows[len(rows)-1]
+		statsResp.TotalCompanies = latest.TotalCompanies
+		statsResp.TotalUsers = latest.TotalUsers
+		statsResp.TotalTasks = latest.TotalTasks
+		statsResp.TotalEmployees = latest.TotalEmployees
 	}
 
-	// 7. 获取任务创建趋势（最近30天）
-	taskTrend, err := l.getTaskCreationTrend(30)
-	if err != nil {
-		logx.Errorf("获取任务创建趋势失败: %v", err)
-		taskTrend = []types.TrendData{}
+	// 用户注册趋势和任务创建趋势（最近30天，缺失的日期补零）
+	byDate := make(map[string]*adminModel.PlatformDailyStat, len(rows))
+	for _, r := range rows {
+		byDate[r.SnapshotDate.Format("2006-01-02")] = r
 	}
-
-	statsResp := types.PlatformStatsResponse{
-		TotalCompanies:      totalCompanies,
-		TotalUsers:          totalUsers,
-		TotalTasks:          totalTasks,
-		TotalEmployees:      totalEmployees,
-		CompanyDistribution: companyDistribution,
-		UserTrend:           userTrend,
-		TaskTrend:           taskTrend,
+	now := time.Now()
+	for i := platformTrendDays - 1; i >= 0; i-- {
+		dateStr := now.AddDate(0, 0, -i).Format("2006-01-02")
+		var newUsers, newTasks int64
+		if r, ok := byDate[dateStr]; ok {
+			newUsers, newTasks = r.NewUsers, r.NewTasks
+		}
+		statsResp.UserTrend = append(statsResp.UserTrend, types.TrendData{Date: dateStr, Count: newUsers})
+		statsResp.TaskTrend = append(statsResp.TaskTrend, types.TrendData{Date: dateStr, Count: newTasks})
 	}
 
 	return utils.Response.SuccessWithData(statsResp), nil
 }
 
-// getCompanyEmployeeDistribution 获取公司员工分布
-func (l *GetPlatformStatsLogic) getCompanyEmployeeDistribution() ([]types.CompanyEmployeeCount, error) {
-	// 获取所有公司
-	companies, _, err := l.svcCtx.CompanyModel.FindByPage(l.ctx, 1, 100)
+// getCompanyEmployeeDistribution 读取当天公司快照中成员数最多的公司
+func (l *GetPlatformStatsLogic) getCompanyEmployeeDistribution() []types.CompanyEmployeeCount {
+	snapshots, err := l.svcCtx.AnalyticsService.TopCompanies(l.ctx, platformDistributionLimit)
 	if err != nil {
-		return nil, err
+		logx.Errorf("获取公司员工分布失败: %v", err)
+		return []types.CompanyEmployeeCount{}
 	}
 
-	var distribution []types.CompanyEmployeeCount
-	for _, company := range companies {
-		// 获取每个公司的员工数量
-		count, err := l.svcCtx.EmployeeModel.GetEmployeeCountByCompany(l.ctx, company.Id)
-		if err != nil {
-			logx.Errorf("获取公司 %s 员工数量失败: %v", company.Id, err)
-			count = 0
+	distribution := make([]types.CompanyEmployeeCount, 0, len(snapshots))
+	for _, snap := range snapshots {
+		name := ""
+		if company, err := l.svcCtx.CompanyModel.FindOne(l.ctx, snap.ScopeId); err == nil {
+			name = company.Name
 		}
-
 		distribution = append(distribution, types.CompanyEmployeeCount{
-			CompanyID:     company.Id,
-			CompanyName:   company.Name,
-			EmployeeCount: count,
+			CompanyID:     snap.ScopeId,
+			CompanyName:   name,
+			EmployeeCount: snap.MemberCount,
 		})
 	}
-
-	return distribution, nil
-}
-
-// getUserRegistrationTrend 获取用户注册趋势
-func (l *GetPlatformStatsLogic) getUserRegistrationTrend(days int) ([]types.TrendData, error) {
-	var trend []types.TrendData
-	now := time.Now()
-
-	for i := days - 1; i >= 0; i-- {
-		date := now.AddDate(0, 0, -i)
-		dateStr := date.Format("2006-01-02")
-		startTime := fmt.Sprintf("%s 00:00:00", dateStr)
-		endTime := fmt.Sprintf("%s 23:59:59", dateStr)
-
-		count, err := l.svcCtx.UserModel.GetUserCountByDateRange(l.ctx, startTime, endTime)
-		if err != nil {
-			logx.Errorf("获取 %s 用户注册数量失败: %v", dateStr, err)
-			count = 0
-		}
-
-		trend = append(trend, types.TrendData{
-			Date:  dateStr,
-			Count: count,
-		})
-	}
-
-	return trend, nil
-}
-
-// getTaskCreationTrend 获取任务创建趋势
-func (l *GetPlatformStatsLogic) getTaskCreationTrend(days int) ([]types.TrendData, error) {
-	var trend []types.TrendData
-	now := time.Now()
-
-	for i := days - 1; i >= 0; i-- {
-		date := now.AddDate(0, 0, -i)
-		dateStr := date.Format("2006-01-02")
-		startTime := fmt.Sprintf("%s 00:00:00", dateStr)
-		endTime := fmt.Sprintf("%s 23:59:59", dateStr)
-
-		count, err := l.svcCtx.TaskModel.GetTaskCountByDateRange(l.ctx, startTime, endTime)
-		if err != nil {
-			logx.Errorf("获取 %s 任务创建数量失败: %v", dateStr, err)
-			count = 0
-		}
-
-		trend = append(trend, types.TrendData{
-			Date:  dateStr,
-			Count: count,
-		})
-	}
-
-	return trend, nil
+	return distribution
 }
@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"context"
+
+	"task_Project/model/role"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 加密配置项在接口中只返回掩码
+const maskedConfigValue = "******"
+
+// toSystemConfigInfo 转换数据库中的配置项
+func toSystemConfigInfo(svcCtx *svc.ServiceContext, row *role.SystemConfig) types.SystemConfigInfo {
+	info := types.SystemConfigInfo{
+		ID:          row.Id,
+		ConfigKey:   row.ConfigKey,
+		ConfigValue: row.ConfigValue.String,
+		ConfigType:  int(row.ConfigType),
+		ConfigGroup: row.ConfigGroup.String,
+		Description: row.Description.String,
+		IsSystem:    int(row.IsSystem),
+		IsEncrypted: int(row.IsEncrypted),
+		Status:      int(row.Status),
+		UpdateTime:  utils.Common.FormatTime(row.UpdateTime),
+	}
+	if def, ok := svcCtx.SystemConfigService.Definition(row.ConfigKey); ok {
+		info.DefaultValue = def.Default
+		if def.Encrypted {
+			info.DefaultValue = maskedConfigValue
+		}
+	}
+	if row.IsEncrypted == 1 && info.ConfigValue != "" {
+		info.ConfigValue = maskedConfigValue
+	}
+	return info
+}
+
+// defaultSystemConfigInfo 转换尚未在数据库中设置的内置配置项
+func defaultSystemConfigInfo(def svc.SettingDef) types.SystemConfigInfo {
+	info := types.SystemConfigInfo{
+		ConfigKey:    def.Key,
+		ConfigValue:  def.Default,
+		ConfigType:   int(def.Type),
+		ConfigGroup:  def.Group,
+		Description:  def.Description,
+		IsSystem:     1,
+		Status:       1,
+		IsDefault:    true,
+		DefaultValue: def.Default,
+	}
+	if def.Encrypted {
+		info.IsEncrypted = 1
+		if def.Default != "" {
+			info.ConfigValue = maskedConfigValue
+			info.DefaultValue = maskedConfigValue
+		}
+	}
+	return info
+}
+
+// saveSystemConfig 校验并保存配置项，keepValue 为 true 时保留已保存的值（加密配置项的密文）
+func saveSystemConfig(ctx context.Context, svcCtx *svc.ServiceContext, req *types.SystemConfigSaveRequest, keepValue bool) *types.BaseResponse {
+	status := 1
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if status != 0 && status != 1 {
+		return utils.Response.ValidationError("状态只能为 0 或 1")
+	}
+	if !keepValue {
+		if err := svcCtx.SystemConfigService.Validate(req.ConfigKey, int64(req.ConfigType), req.ConfigValue); err != nil {
+			return utils.Response.ValidationError(err.Error())
+		}
+	}
+	err := svcCtx.SystemConfigService.Set(ctx, svc.SettingUpdate{
+		Key:         req.ConfigKey,
+		Value:       req.ConfigValue,
+		Type:        int64(req.ConfigType),
+		Group:       req.ConfigGroup,
+		Description: req.Description,
+		Encrypted:   req.IsEncrypted == 1,
+		Status:      int64(status),
+		KeepValue:   keepValue,
+	})
+	if err != nil {
+		logx.WithContext(ctx).Errorf("保存系统配置失败: key=%s, err=%v", req.ConfigKey, err)
+		return utils.Response.InternalError("保存系统配置失败")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SystemConfigListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 系统配置列表
+func NewSystemConfigListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SystemConfigListLogic {
+	return &SystemConfigListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SystemConfigList 查询系统配置，包含尚未设置的内置配置项及其默认值
+func (l *SystemConfigListLogic) SystemConfigList(req *types.SystemConfigListRequest) (*types.BaseResponse, error) {
+	list := make([]types.SystemConfigInfo, 0)
+	seen := make(map[string]bool)
+	for _, row := range l.svcCtx.SystemConfigService.Rows() {
+		seen[row.ConfigKey] = true
+		if req.Group != "" && row.ConfigGroup.String != req.Group {
+			continue
+		}
+		list = append(list, toSystemConfigInfo(l.svcCtx, row))
+	}
+	for _, def := range l.svcCtx.SystemConfigService.Definitions() {
+		if seen[def.Key] || (req.Group != "" && def.Group != req.Group) {
+			continue
+		}
+		list = append(list, defaultSystemConfigInfo(def))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ConfigGroup != list[j].ConfigGroup {
+			return list[i].ConfigGroup < list[j].ConfigGroup
+		}
+		return list[i].ConfigKey < list[j].ConfigKey
+	})
+
+	return utils.Response.Success(map[string]interface{}{
+		"list":  list,
+		"total": len(list),
+	}), nil
+}
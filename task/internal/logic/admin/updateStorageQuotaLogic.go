@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"task_Project/model/company"
+	uploadlogic "task_Project/task/internal/logic/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateStorageQuotaLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改公司存储配额
+func NewUpdateStorageQuotaLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateStorageQuotaLogic {
+	return &UpdateStorageQuotaLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateStorageQuota 设置公司的总配额和员工配额，Reset 时删除单独设置恢复系统设置。
+// 降低配额不会删除已有文件，只是超出后不能再上传
+func (l *UpdateStorageQuotaLogic) UpdateStorageQuota(req *types.UpdateStorageQuotaRequest) (*types.BaseResponse, error) {
+	if _, err := l.svcCtx.CompanyModel.FindOne(l.ctx, req.CompanyID); err != nil {
+		return utils.Response.Error(404, "公司不存在"), nil
+	}
+	adminID, _ := l.ctx.Value("adminId").(string)
+
+	var message string
+	if req.Reset {
+		if err := l.svcCtx.CompanyStorageQuotaModel.Delete(l.ctx, req.CompanyID); err != nil {
+			logx.Errorf("删除公司存储配额失败: companyId=%s, err=%v", req.CompanyID, err)
+			return utils.Response.Error(500, "修改存储配额失败"), nil
+		}
+		message = "恢复公司存储配额为系统设置: " + req.CompanyID
+	} else {
+		if req.CompanyQuotaMB < -1 || req.UserQuotaMB < -1 {
+			return utils.Response.ValidationError("配额不能小于 -1（-1 表示使用系统设置，0 表示不限制）"), nil
+		}
+		if err := l.svcCtx.CompanyStorageQuotaModel.Upsert(l.ctx, &company.CompanyStorageQuota{
+			CompanyId:      req.CompanyID,
+			CompanyQuotaMb: req.CompanyQuotaMB,
+			UserQuotaMb:    req.UserQuotaMB,
+			UpdateBy:       sql.NullString{String: adminID, Valid: adminID != ""},
+		}); err != nil {
+			logx.Errorf("保存公司存储配额失败: companyId=%s, err=%v", req.CompanyID, err)
+			return utils.Response.Error(500, "修改存储配额失败"), nil
+		}
+		message = fmt.Sprintf("修改公司存储配额: %s, 公司配额=%dMB, 员工配额=%dMB", req.CompanyID, req.CompanyQuotaMB, req.UserQuotaMB)
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "storage", "quota", message, adminID, "", "")
+	}
+
+	info, err := uploadlogic.BuildStorageUsageInfo(l.ctx, l.svcCtx, req.CompanyID, "", false)
+	if err != nil {
+		logx.Errorf("查询公司存储用量失败: companyId=%s, err=%v", req.CompanyID, err)
+		return utils.Response.Success(nil), nil
+	}
+	return utils.Response.Success(info), nil
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateSystemConfigLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改系统配置
+func NewUpdateSystemConfigLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateSystemConfigLogic {
+	return &UpdateSystemConfigLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateSystemConfig 修改配置项，内置配置项首次修改时写入数据库
+func (l *UpdateSystemConfigLogic) UpdateSystemConfig(req *types.SystemConfigSaveRequest) (*types.BaseResponse, error) {
+	row, exists := l.svcCtx.SystemConfigService.Row(req.ConfigKey)
+	if _, builtin := l.svcCtx.SystemConfigService.Definition(req.ConfigKey); !exists && !builtin {
+		return utils.Response.Error(404, "配置项不存在"), nil
+	}
+	// 未传的可选字段沿用原值；加密配置项传回掩码或空值时保留原密文
+	keepValue := false
+	if exists {
+		if req.ConfigType == 0 {
+			req.ConfigType = int(row.ConfigType)
+		}
+		if req.ConfigGroup == "" {
+			req.ConfigGroup = row.ConfigGroup.String
+		}
+		if req.Description == "" {
+			req.Description = row.Description.String
+		}
+		if req.IsEncrypted == 0 {
+			req.IsEncrypted = int(row.IsEncrypted)
+		}
+		if req.Status == nil {
+			status := int(row.Status)
+			req.Status = &status
+		}
+		keepValue = row.IsEncrypted == 1 && (req.ConfigValue == "" || req.ConfigValue == maskedConfigValue)
+	}
+	if resp := saveSystemConfig(l.ctx, l.svcCtx, req, keepValue); resp != nil {
+		return resp, nil
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		adminID, _ := l.ctx.Value("adminId").(string)
+		l.svcCtx.SystemLogService.AdminAction(l.ctx, "config", "update", "修改系统配置: "+req.ConfigKey, adminID, "", "")
+	}
+	return utils.Response.Success("配置已更新"), nil
+}
@@ -61,16 +61,7 @@ func (l *UserListLogic) UserList(req *types.AdminUserListRequest) (resp *types.B
 				CreateTime: u.CreateTime.Format("2006-01-02 15:04:05"),
 			}
 
-			// 获取用户关联的员工信息
-			employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, u.Id)
-			if err == nil && employee != nil {
-				userInfo.CompanyID = employee.CompanyId
-				// 获取公司名称
-				company, err := l.svcCtx.CompanyModel.FindOne(l.ctx, employee.CompanyId)
-				if err == nil && company != nil {
-					userInfo.CompanyName = company.Name
-				}
-			}
+			l.fillUserCompany(&userInfo, u.Id)
 
 			users = append(users, userInfo)
 		}
@@ -95,16 +86,7 @@ func (l *UserListLogic) UserList(req *types.AdminUserListRequest) (resp *types.B
 				CreateTime: u.CreateTime.Format("2006-01-02 15:04:05"),
 			}
 
-			// 获取用户关联的员工信息
-			employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, u.Id)
-			if err == nil && employee != nil {
-				userInfo.CompanyID = employee.CompanyId
-				// 获取公司名称
-				company, err := l.svcCtx.CompanyModel.FindOne(l.ctx, employee.CompanyId)
-				if err == nil && company != nil {
-					userInfo.CompanyName = company.Name
-				}
-			}
+			l.fillUserCompany(&userInfo, u.Id)
 
 			users = append(users, userInfo)
 		}
@@ -139,3 +121,16 @@ func (l *UserListLogic) UserList(req *types.AdminUserListRequest) (resp *types.B
 		"pageSize": pageSize,
 	}), nil
 }
+
+// fillUserCompany 填充用户所在公司。平台管理员的令牌不属于任何公司，没有可限定的当前公司，
+// 因此这里不按公司限定，展示用户最近加入且在职的公司
+func (l *UserListLogic) fillUserCompany(info *types.AdminUserInfo, userID string) {
+	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	if err != nil || employee == nil {
+		return
+	}
+	info.CompanyID = employee.CompanyId
+	if company, err := l.svcCtx.CompanyModel.FindOne(l.ctx, employee.CompanyId); err == nil && company != nil {
+		info.CompanyName = company.Name
+	}
+}
@@ -0,0 +1,101 @@
+package auditlog
+
+import (
+	"context"
+	"time"
+
+	"task_Project/model/role"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// loadAuditOperator 获取当前操作人，只有公司创始人、人事部门或管理人员可以查看审计日志
+func loadAuditOperator(ctx context.Context, svcCtx *svc.ServiceContext) (*user.Employee, *types.BaseResponse) {
+	employee, errResp := svcCtx.CurrentOperator(ctx)
+	if errResp != nil {
+		return nil, errResp
+	}
+	if svcCtx.IsCompanyAdmin(ctx, employee) {
+		return employee, nil
+	}
+	return nil, utils.Response.BusinessError("only_admin_can_view_audit")
+}
+
+// buildAuditFilter 将请求转换为查询条件，查询范围固定为操作人所在公司
+func buildAuditFilter(companyID string, req *types.AuditLogSearchRequest) (role.OperationLogFilter, *types.BaseResponse) {
+	filter := role.OperationLogFilter{
+		CompanyId:     companyID,
+		UserId:        req.UserId,
+		EmployeeId:    req.EmployeeId,
+		OperationType: req.OperationType,
+		EntityType:    req.EntityType,
+		EntityId:      req.EntityId,
+	}
+	if req.Status == 0 || req.Status == 1 {
+		status := int64(req.Status)
+		filter.Status = &status
+	}
+	if req.StartTime != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", req.StartTime, time.Local)
+		if err != nil {
+			return filter, utils.Response.BusinessError("audit_time_invalid")
+		}
+		filter.StartTime = t
+	}
+	if req.EndTime != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", req.EndTime, time.Local)
+		if err != nil {
+			return filter, utils.Response.BusinessError("audit_time_invalid")
+		}
+		filter.EndTime = t
+	}
+	if !filter.StartTime.IsZero() && !filter.EndTime.IsZero() && filter.EndTime.Before(filter.StartTime) {
+		return filter, utils.Response.BusinessError("audit_time_invalid")
+	}
+	return filter, nil
+}
+
+// employeeNameResolver 按员工ID查询姓名，同一次请求内缓存结果
+func employeeNameResolver(ctx context.Context, svcCtx *svc.ServiceContext) func(string) string {
+	names := make(map[string]string)
+	return func(employeeID string) string {
+		if employeeID == "" {
+			return ""
+		}
+		if name, ok := names[employeeID]; ok {
+			return name
+		}
+		name := ""
+		if emp, err := svcCtx.EmployeeModel.FindOne(ctx, employeeID); err == nil && emp != nil {
+			name = emp.RealName
+		}
+		names[employeeID] = name
+		return name
+	}
+}
+
+// toAuditLogInfo 转换审计日志
+func toAuditLogInfo(log *role.OperationLog, employeeName string) types.AuditLogInfo {
+	return types.AuditLogInfo{
+		Id:            log.Id,
+		UserId:        log.UserId.String,
+		EmployeeId:    log.EmployeeId.String,
+		EmployeeName:  employeeName,
+		OperationType: log.OperationType,
+		OperationName: log.OperationName,
+		EntityType:    log.EntityType.String,
+		EntityId:      log.EntityId.String,
+		Detail:        log.OperationDesc.String,
+		RequestMethod: log.RequestMethod.String,
+		RequestUrl:    log.RequestUrl.String,
+		RequestParams: log.RequestParams.String,
+		IpAddress:     log.IpAddress.String,
+		UserAgent:     log.UserAgent.String,
+		ExecutionTime: log.ExecutionTime.Int64,
+		Status:        int(log.Status),
+		ErrorMessage:  log.ErrorMessage.String,
+		CreateTime:    utils.Common.FormatTime(log.CreateTime),
+	}
+}
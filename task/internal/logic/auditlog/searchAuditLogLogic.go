@@ -0,0 +1,62 @@
+package auditlog
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SearchAuditLogLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 检索公司审计日志
+func NewSearchAuditLogLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchAuditLogLogic {
+	return &SearchAuditLogLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SearchAuditLogLogic) SearchAuditLog(req *types.AuditLogSearchRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := loadAuditOperator(l.ctx, l.svcCtx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	filter, errResp := buildAuditFilter(operator.CompanyId, req)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	logs, total, err := l.svcCtx.OperationLogModel.Search(l.ctx, filter, page, pageSize)
+	if err != nil {
+		l.Errorf("查询审计日志失败: %v", err)
+		return utils.Response.InternalError("查询审计日志失败"), nil
+	}
+
+	nameOf := employeeNameResolver(l.ctx, l.svcCtx)
+	list := make([]types.AuditLogInfo, 0, len(logs))
+	for _, log := range logs {
+		list = append(list, toAuditLogInfo(log, nameOf(log.EmployeeId.String)))
+	}
+	return utils.Response.Success(map[string]interface{}{
+		"list":     list,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	}), nil
+}
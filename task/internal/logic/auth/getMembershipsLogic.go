@@ -0,0 +1,48 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package auth
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetMembershipsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前用户所在的全部公司
+func NewGetMembershipsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMembershipsLogic {
+	return &GetMembershipsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetMembershipsLogic) GetMemberships() (resp *types.BaseResponse, err error) {
+	userID, ok := utils.Common.GetCurrentUserID(l.ctx)
+	if !ok {
+		return utils.Response.UnauthorizedError(), nil
+	}
+	companyID, _ := utils.Common.GetCurrentCompanyID(l.ctx)
+
+	memberships, err := l.svcCtx.CompanyMembershipService.List(l.ctx, userID)
+	if err != nil {
+		logx.Errorf("查询用户公司成员身份失败: %v", err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	return utils.Response.SuccessWithKey("query", map[string]interface{}{
+		"currentCompanyId": companyID,
+		"list":             toMembershipInfos(l.ctx, l.svcCtx, memberships, companyID),
+	}), nil
+}
@@ -122,22 +122,25 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.BaseResponse, e
 		return utils.Response.BusinessErrorWithNum(fmt.Sprintf("用户名或密码错误，还剩 %d 次尝试机会", remainingAttempts)), nil
 	}
 
-	// 如果用户已加入公司，查询员工ID并检查员工状态
+	// 如果用户已加入公司，选择进入的公司并检查员工状态
+	// 用户可在多家公司任职：优先进入最近切换的公司，否则进入最近加入的在职公司
 	var employeeID, companyID string
+	var memberships []*svc.CompanyMembership
 	if userInfo.HasJoinedCompany == 1 {
-		employee, err := l.svcCtx.EmployeeModel.FindOneByUserId(l.ctx, userInfo.Id)
-		if err == nil && employee != nil {
-			// 检查员工是否已离职（status = 0）
-			if employee.Status == 0 {
-				// 记录登录失败日志（员工已离职）
+		memberships, err = l.svcCtx.CompanyMembershipService.List(l.ctx, userInfo.Id)
+		if err != nil {
+			logx.Errorf("查询用户公司成员身份失败: %v", err)
+		} else if len(memberships) > 0 {
+			active := l.svcCtx.CompanyMembershipService.ResolveActive(userInfo.Id, memberships)
+			if active == nil {
+				// 所有公司均已离职
 				l.recordLoginLog(userInfo.Id, req.Username, 0, "员工已离职，无法登录")
 				return utils.Response.BusinessError("employee_left"), nil
 			}
-			employeeID = employee.Id
-			companyID = employee.CompanyId
+			employeeID = active.Employee.Id
+			companyID = active.Employee.CompanyId
 		}
 	}
-	fmt.Println("员工id", employeeID)
 
 	// 登录成功，生成JWT令牌（包含员工信息）
 	token, err := l.svcCtx.JWTMiddleware.GenerateTokenWithEmployee(userInfo.Id, userInfo.Username, userInfo.RealName.String, "user", employeeID, companyID)
@@ -188,6 +191,9 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.BaseResponse, e
 		Username:         userInfo.Username,
 		RealName:         userInfo.RealName.String,
 		HasJoinedCompany: userInfo.HasJoinedCompany == 1,
+		CompanyID:        companyID,
+		EmployeeID:       employeeID,
+		Memberships:      toMembershipInfos(l.ctx, l.svcCtx, memberships, companyID),
 	}
 
 	return utils.Response.SuccessWithKey("login", loginResp), nil
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// toMembershipInfos 将成员身份转换为响应格式，currentCompanyID 为当前令牌所属公司
+func toMembershipInfos(ctx context.Context, svcCtx *svc.ServiceContext, memberships []*svc.CompanyMembership, currentCompanyID string) []types.CompanyMembershipInfo {
+	list := make([]types.CompanyMembershipInfo, 0, len(memberships))
+	for _, m := range memberships {
+		emp := m.Employee
+		info := types.CompanyMembershipInfo{
+			CompanyID:    emp.CompanyId,
+			EmployeeID:   emp.Id,
+			EmployeeNo:   emp.EmployeeId,
+			DepartmentID: emp.DepartmentId.String,
+			PositionID:   emp.PositionId.String,
+			Status:       int(emp.Status),
+			Current:      emp.CompanyId == currentCompanyID,
+			JoinTime:     utils.Common.FormatTime(emp.CreateTime),
+		}
+		if m.Company != nil {
+			info.CompanyName = m.Company.Name
+			info.CompanyStatus = int(m.Company.Status)
+		}
+		if emp.DepartmentId.Valid && emp.DepartmentId.String != "" {
+			if dept, err := svcCtx.DepartmentModel.FindOne(ctx, emp.DepartmentId.String); err == nil {
+				info.DepartmentName = dept.DepartmentName
+			}
+		}
+		if emp.PositionId.Valid && emp.PositionId.String != "" {
+			if pos, err := svcCtx.PositionModel.FindOne(ctx, emp.PositionId.String); err == nil {
+				info.PositionName = pos.PositionName
+			}
+		}
+		list = append(list, info)
+	}
+	return list
+}
@@ -0,0 +1,95 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SwitchCompanyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 切换当前公司（重新签发令牌）
+func NewSwitchCompanyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SwitchCompanyLogic {
+	return &SwitchCompanyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SwitchCompanyLogic) SwitchCompany(req *types.SwitchCompanyRequest) (resp *types.BaseResponse, err error) {
+	userID, ok := utils.Common.GetCurrentUserID(l.ctx)
+	if !ok {
+		return utils.Response.UnauthorizedError(), nil
+	}
+	if utils.Validator.IsEmpty(req.CompanyID) {
+		return utils.Response.ValidationError("公司ID不能为空"), nil
+	}
+
+	userInfo, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		logx.Errorf("查询用户失败: %v", err)
+		return utils.Response.InternalError("查询用户失败"), nil
+	}
+
+	// 校验目标公司的成员身份
+	membership, err := l.svcCtx.CompanyMembershipService.Switch(l.ctx, userID, req.CompanyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, svc.ErrMembershipNotFound):
+			return utils.Response.BusinessError("membership_not_found"), nil
+		case errors.Is(err, svc.ErrMembershipLeft):
+			return utils.Response.BusinessError("membership_left"), nil
+		case errors.Is(err, svc.ErrCompanyDisabled):
+			return utils.Response.BusinessError("membership_company_disabled"), nil
+		}
+		logx.Errorf("切换公司失败: userId=%s, companyId=%s, err=%v", userID, req.CompanyID, err)
+		return utils.Response.InternalError("切换公司失败"), nil
+	}
+	employeeID := membership.Employee.Id
+	companyID := membership.Employee.CompanyId
+
+	// 重新签发限定在目标公司的令牌，替换 Redis 中的旧令牌
+	token, err := l.svcCtx.JWTMiddleware.GenerateTokenWithEmployee(userInfo.Id, userInfo.Username, userInfo.RealName.String, "user", employeeID, companyID)
+	if err != nil {
+		logx.Errorf("生成JWT令牌失败: %v", err)
+		return utils.Response.InternalError("生成JWT令牌失败"), nil
+	}
+	tokenKey := fmt.Sprintf("%s%s", TokenKeyPrefix, userInfo.Id)
+	if err := l.svcCtx.RedisClient.Setex(tokenKey, token, TokenExpire); err != nil {
+		logx.Errorf("存储Token到Redis失败: %v", err)
+	}
+
+	if l.svcCtx.SystemLogService != nil {
+		l.svcCtx.SystemLogService.UserAction(l.ctx, "auth", "switch_company", fmt.Sprintf("用户 %s 切换到公司 %s", userInfo.Username, companyID), userInfo.Id, "", "")
+	}
+
+	memberships, err := l.svcCtx.CompanyMembershipService.List(l.ctx, userID)
+	if err != nil {
+		logx.Errorf("查询用户公司成员身份失败: %v", err)
+	}
+
+	return utils.Response.SuccessWithKey("switch", types.LoginResponse{
+		Token:            token,
+		UserID:           userInfo.Id,
+		Username:         userInfo.Username,
+		RealName:         userInfo.RealName.String,
+		HasJoinedCompany: true,
+		CompanyID:        companyID,
+		EmployeeID:       employeeID,
+		Memberships:      toMembershipInfos(l.ctx, l.svcCtx, memberships, companyID),
+	}), nil
+}
@@ -0,0 +1,306 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 看板限制
+const (
+	maxBoardColumns   = 12  // 每个看板最多的列数
+	maxBoardNodes     = 500 // 部门看板最多展示的节点数（按创建时间倒序）
+	maxBoardNameRunes = 64
+	maxColumnRunes    = 32
+)
+
+// 节点状态 0-未开始 1-进行中 2-已完成 3-已逾期
+var boardNodeStatuses = []int64{0, 1, 2, 3}
+
+// checkBoardScope 校验看板范围属于当前公司并返回默认看板名称；
+// manage 为 true 时要求可以维护看板：任务看板为任务创建者或负责人，部门看板为部门经理；
+// 否则只要求可以查看：任务看板为任务成员或节点参与者，部门看板为部门成员；管理人员都可以
+func checkBoardScope(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, scopeType, scopeID string, manage bool) (string, *types.BaseResponse) {
+	if scopeID == "" {
+		return "", utils.Response.ValidationError("看板范围ID不能为空")
+	}
+	switch scopeType {
+	case taskmodel.BoardScopeTask:
+		taskInfo, err := svcCtx.TaskModel.FindOne(ctx, scopeID)
+		if err != nil || taskInfo.DeleteTime.Valid || taskInfo.CompanyId != employee.CompanyId {
+			return "", utils.Response.BusinessError("task_not_found")
+		}
+		name := taskInfo.TaskTitle + "看板"
+		if taskInfo.TaskCreator == employee.Id || (taskInfo.LeaderId.Valid && taskInfo.LeaderId.String == employee.Id) {
+			return name, nil
+		}
+		if !manage {
+			if taskInfo.TaskAssigner.String == employee.Id || utils.Common.ContainsID(taskInfo.ResponsibleEmployeeIds.String, employee.Id) {
+				return name, nil
+			}
+			if nodes, err := svcCtx.TaskNodeModel.FindByTaskID(ctx, scopeID); err == nil {
+				for _, node := range nodes {
+					if utils.Common.ContainsID(node.ExecutorId, employee.Id) || node.LeaderId == employee.Id {
+						return name, nil
+					}
+				}
+			}
+		}
+		if svcCtx.IsCompanyAdmin(ctx, employee) {
+			return name, nil
+		}
+	case taskmodel.BoardScopeDepartment:
+		dept, err := svcCtx.DepartmentModel.FindOne(ctx, scopeID)
+		if err != nil || dept.DeleteTime.Valid || dept.CompanyId != employee.CompanyId {
+			return "", utils.Response.BusinessError("department_not_found")
+		}
+		name := dept.DepartmentName + "看板"
+		if dept.ManagerId.Valid && dept.ManagerId.String == employee.Id {
+			return name, nil
+		}
+		if !manage && employee.DepartmentId.Valid && employee.DepartmentId.String == dept.Id {
+			return name, nil
+		}
+		if svcCtx.IsCompanyAdmin(ctx, employee) {
+			return name, nil
+		}
+	default:
+		return "", utils.Response.ValidationError("看板范围只支持 task 或 department")
+	}
+	return "", utils.Response.BusinessError("board_no_permission")
+}
+
+// loadBoard 查询当前公司的看板并校验权限
+func loadBoard(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, boardID string, manage bool) (*taskmodel.TaskBoard, *types.BaseResponse) {
+	if boardID == "" {
+		return nil, utils.Response.ValidationError("看板ID不能为空")
+	}
+	board, err := svcCtx.TaskBoardModel.FindOne(ctx, boardID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return nil, utils.Response.BusinessError("board_not_found")
+		}
+		return nil, utils.Response.InternalError("查询看板失败")
+	}
+	if board.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("board_not_found")
+	}
+	if _, errResp := checkBoardScope(ctx, svcCtx, employee, board.ScopeType, board.ScopeId, manage); errResp != nil {
+		return nil, errResp
+	}
+	return board, nil
+}
+
+// normalizeBoardName 校验看板名称，为空时使用默认名称
+func normalizeBoardName(name, defaultName string) (string, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultName
+	}
+	if utf8.RuneCountInString(name) > maxBoardNameRunes {
+		return "", "看板名称不能超过 64 个字符"
+	}
+	return name, ""
+}
+
+// buildBoardColumns 校验列并按输入顺序生成列；不填时按四个节点状态各建一列。
+// 带ID的列必须是看板已有的列，每个节点状态至少对应一列，保证所有节点都能显示
+func buildBoardColumns(boardID string, inputs []types.BoardColumnInput, existing []*taskmodel.TaskBoardColumn) ([]*taskmodel.TaskBoardColumn, string) {
+	now := time.Now()
+	if len(inputs) == 0 {
+		columns := make([]*taskmodel.TaskBoardColumn, 0, len(boardNodeStatuses))
+		for i, status := range boardNodeStatuses {
+			columns = append(columns, &taskmodel.TaskBoardColumn{
+				Id: utils.Common.GenId("col"), BoardId: boardID, Name: svc.NodeStatusText(status),
+				NodeStatus: status, Sort: int64(i), CreateTime: now, UpdateTime: now,
+			})
+		}
+		return columns, ""
+	}
+	if len(inputs) > maxBoardColumns {
+		return nil, fmt.Sprintf("看板最多 %d 列", maxBoardColumns)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		known[c.Id] = true
+	}
+	covered := make(map[int64]bool, len(boardNodeStatuses))
+	used := make(map[string]bool, len(inputs))
+	columns := make([]*taskmodel.TaskBoardColumn, 0, len(inputs))
+	for i, in := range inputs {
+		name := strings.TrimSpace(in.Name)
+		if name == "" {
+			return nil, "列名称不能为空"
+		}
+		if utf8.RuneCountInString(name) > maxColumnRunes {
+			return nil, "列名称不能超过 32 个字符"
+		}
+		if in.NodeStatus < 0 || in.NodeStatus > 3 {
+			return nil, fmt.Sprintf("列「%s」的节点状态无效", name)
+		}
+		if in.WipLimit < 0 {
+			return nil, fmt.Sprintf("列「%s」的在制品上限不能为负数", name)
+		}
+		id := strings.TrimSpace(in.ID)
+		if id == "" {
+			id = utils.Common.GenId("col")
+		} else if !known[id] || used[id] {
+			return nil, fmt.Sprintf("列「%s」的ID无效", name)
+		}
+		used[id] = true
+		covered[int64(in.NodeStatus)] = true
+		columns = append(columns, &taskmodel.TaskBoardColumn{
+			Id: id, BoardId: boardID, Name: name, NodeStatus: int64(in.NodeStatus),
+			WipLimit: in.WipLimit, Sort: int64(i), CreateTime: now, UpdateTime: now,
+		})
+	}
+	for _, status := range boardNodeStatuses {
+		if !covered[status] {
+			return nil, fmt.Sprintf("没有对应「%s」状态的列", svc.NodeStatusText(status))
+		}
+	}
+	return columns, ""
+}
+
+// boardCard 看板中的一张卡片，rank 为空表示还没有记录位置
+type boardCard struct {
+	node *taskmodel.TaskNode
+	rank string
+}
+
+// boardLayout 看板各列的卡片
+type boardLayout struct {
+	columns  []*taskmodel.TaskBoardColumn
+	cards    map[string][]*boardCard // 列ID -> 按顺序排列的卡片
+	columnOf map[string]string       // 节点ID -> 列ID
+}
+
+// loadBoardLayout 查询看板的列、节点和卡片位置并排列：
+// 已记录位置且所在列与节点状态一致的卡片按排序值排列，
+// 其余节点（新节点或在看板外修改了状态的节点）放在对应状态第一列的末尾，按创建时间排列
+func loadBoardLayout(ctx context.Context, svcCtx *svc.ServiceContext, board *taskmodel.TaskBoard) (*boardLayout, error) {
+	return loadBoardLayoutWith(ctx, svcCtx.TaskBoardModel, svcCtx.TaskNodeModel, board)
+}
+
+// loadBoardLayoutWith 使用指定的模型（如事务会话中的模型）查询并排列看板
+func loadBoardLayoutWith(ctx context.Context, boardModel taskmodel.TaskBoardModel, nodeModel taskmodel.TaskNodeModel, board *taskmodel.TaskBoard) (*boardLayout, error) {
+	columns, err := boardModel.FindColumns(ctx, board.Id)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*taskmodel.TaskNode
+	if board.ScopeType == taskmodel.BoardScopeTask {
+		nodes, err = nodeModel.FindByTaskID(ctx, board.ScopeId)
+	} else {
+		nodes, _, err = nodeModel.FindByDepartment(ctx, board.ScopeId, 1, maxBoardNodes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved, err := boardModel.FindCards(ctx, board.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	columnByID := make(map[string]*taskmodel.TaskBoardColumn, len(columns))
+	firstOfStatus := make(map[int64]string)
+	for _, c := range columns {
+		columnByID[c.Id] = c
+		if _, ok := firstOfStatus[c.NodeStatus]; !ok {
+			firstOfStatus[c.NodeStatus] = c.Id
+		}
+	}
+	positions := make(map[string]*taskmodel.TaskBoardCard, len(saved))
+	for _, c := range saved {
+		positions[c.TaskNodeId] = c
+	}
+
+	layout := &boardLayout{columns: columns, cards: make(map[string][]*boardCard), columnOf: make(map[string]string)}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].CreateTime.Before(nodes[j].CreateTime) })
+	for _, node := range nodes {
+		card := &boardCard{node: node}
+		columnID := ""
+		if pos, ok := positions[node.TaskNodeId]; ok {
+			if c, ok := columnByID[pos.ColumnId]; ok && c.NodeStatus == node.NodeStatus {
+				columnID, card.rank = c.Id, pos.Rank
+			}
+		}
+		if columnID == "" {
+			if columnID = firstOfStatus[node.NodeStatus]; columnID == "" {
+				continue
+			}
+		}
+		layout.cards[columnID] = append(layout.cards[columnID], card)
+		layout.columnOf[node.TaskNodeId] = columnID
+	}
+	for _, cards := range layout.cards {
+		sort.SliceStable(cards, func(i, j int) bool {
+			a, b := cards[i].rank, cards[j].rank
+			if a == "" || b == "" {
+				return a != "" && b == ""
+			}
+			return a < b
+		})
+	}
+	return layout, nil
+}
+
+func (l *boardLayout) column(id string) *taskmodel.TaskBoardColumn {
+	for _, c := range l.columns {
+		if c.Id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// toBoardInfo 转换看板及各列的卡片
+func toBoardInfo(board *taskmodel.TaskBoard, layout *boardLayout) types.BoardInfo {
+	info := types.BoardInfo{
+		ID:         board.Id,
+		ScopeType:  board.ScopeType,
+		ScopeID:    board.ScopeId,
+		Name:       board.Name,
+		Columns:    make([]types.BoardColumnInfo, 0, len(layout.columns)),
+		CreateTime: utils.Common.FormatTime(board.CreateTime),
+	}
+	for _, c := range layout.columns {
+		cards := layout.cards[c.Id]
+		column := types.BoardColumnInfo{
+			ID:         c.Id,
+			Name:       c.Name,
+			NodeStatus: c.NodeStatus,
+			WipLimit:   c.WipLimit,
+			CardCount:  len(cards),
+			OverLimit:  c.WipLimit > 0 && int64(len(cards)) > c.WipLimit,
+			Cards:      make([]types.BoardCardInfo, 0, len(cards)),
+		}
+		for _, card := range cards {
+			node := card.node
+			column.Cards = append(column.Cards, types.BoardCardInfo{
+				NodeID:       node.TaskNodeId,
+				TaskID:       node.TaskId,
+				NodeName:     node.NodeName,
+				NodeStatus:   node.NodeStatus,
+				NodePriority: node.NodePriority,
+				Progress:     node.Progress,
+				ExecutorID:   node.ExecutorId,
+				LeaderID:     node.LeaderId,
+				NodeDeadline: utils.Common.FormatTime(node.NodeDeadline),
+				Rank:         card.rank,
+			})
+		}
+		info.Columns = append(info.Columns, column)
+	}
+	return info
+}
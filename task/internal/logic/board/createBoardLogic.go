@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package board
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateBoardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建任务或部门看板
+func NewCreateBoardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateBoardLogic {
+	return &CreateBoardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateBoardLogic) CreateBoard(req *types.CreateBoardRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	defaultName, errResp := checkBoardScope(l.ctx, l.svcCtx, employee, req.ScopeType, req.ScopeID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if _, err := l.svcCtx.TaskBoardModel.FindByScope(l.ctx, req.ScopeType, req.ScopeID); err == nil {
+		return utils.Response.BusinessError("board_exists"), nil
+	} else if !errors.Is(err, taskmodel.ErrNotFound) {
+		l.Logger.Errorf("查询看板失败: %v", err)
+		return utils.Response.InternalError("创建看板失败"), nil
+	}
+	name, msg := normalizeBoardName(req.Name, defaultName)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+
+	now := time.Now()
+	board := &taskmodel.TaskBoard{
+		Id:         utils.Common.GenId("board"),
+		CompanyId:  employee.CompanyId,
+		ScopeType:  req.ScopeType,
+		ScopeId:    req.ScopeID,
+		Name:       name,
+		CreatorId:  employee.Id,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	columns, msg := buildBoardColumns(board.Id, req.Columns, nil)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if err := l.svcCtx.TaskBoardModel.Insert(l.ctx, board, columns); err != nil {
+		l.Logger.Errorf("创建看板失败: %v", err)
+		return utils.Response.InternalError("创建看板失败"), nil
+	}
+
+	layout, err := loadBoardLayout(l.ctx, l.svcCtx, board)
+	if err != nil {
+		l.Logger.Errorf("查询看板卡片失败: %v", err)
+		return utils.Response.InternalError("查询看板失败"), nil
+	}
+	return utils.Response.Success(toBoardInfo(board, layout)), nil
+}
@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package board
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteBoardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除看板
+func NewDeleteBoardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteBoardLogic {
+	return &DeleteBoardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteBoardLogic) DeleteBoard(req *types.DeleteBoardRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	board, errResp := loadBoard(l.ctx, l.svcCtx, employee, req.BoardID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+	// 只删除看板的列和卡片位置，节点本身不受影响
+	if err := l.svcCtx.TaskBoardModel.Delete(l.ctx, board.Id); err != nil {
+		l.Logger.Errorf("删除看板失败: %v", err)
+		return utils.Response.InternalError("删除看板失败"), nil
+	}
+	return utils.Response.Success("看板已删除"), nil
+}
@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package board
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetBoardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询看板及各列的卡片
+func NewGetBoardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetBoardLogic {
+	return &GetBoardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetBoardLogic) GetBoard(req *types.GetBoardRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	boardID := req.BoardID
+	if boardID == "" {
+		if _, errResp := checkBoardScope(l.ctx, l.svcCtx, employee, req.ScopeType, req.ScopeID, false); errResp != nil {
+			return errResp, nil
+		}
+		board, err := l.svcCtx.TaskBoardModel.FindByScope(l.ctx, req.ScopeType, req.ScopeID)
+		if err != nil {
+			if errors.Is(err, taskmodel.ErrNotFound) {
+				return utils.Response.BusinessError("board_not_found"), nil
+			}
+			l.Logger.Errorf("查询看板失败: %v", err)
+			return utils.Response.InternalError("查询看板失败"), nil
+		}
+		boardID = board.Id
+	}
+	board, errResp := loadBoard(l.ctx, l.svcCtx, employee, boardID, false)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	layout, err := loadBoardLayout(l.ctx, l.svcCtx, board)
+	if err != nil {
+		l.Logger.Errorf("查询看板卡片失败: %v", err)
+		return utils.Response.InternalError("查询看板失败"), nil
+	}
+	return utils.Response.Success(toBoardInfo(board, layout)), nil
+}
@@ -0,0 +1,200 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/logic/tasknode"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type MoveBoardCardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 拖动卡片到指定列和位置
+func NewMoveBoardCardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *MoveBoardCardLogic {
+	return &MoveBoardCardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *MoveBoardCardLogic) MoveBoardCard(req *types.MoveBoardCardRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	board, errResp := loadBoard(l.ctx, l.svcCtx, employee, req.BoardID, false)
+	if errResp != nil {
+		return errResp, nil
+	}
+	layout, err := loadBoardLayout(l.ctx, l.svcCtx, board)
+	if err != nil {
+		l.Logger.Errorf("查询看板卡片失败: %v", err)
+		return utils.Response.InternalError("移动卡片失败"), nil
+	}
+	fromID, ok := layout.columnOf[req.NodeID]
+	if !ok {
+		return utils.Response.BusinessError("board_node_not_found"), nil
+	}
+	target := layout.column(req.ColumnID)
+	if target == nil {
+		return utils.Response.BusinessError("board_column_not_found"), nil
+	}
+	var node *taskmodel.TaskNode
+	for _, card := range layout.cards[fromID] {
+		if card.node.TaskNodeId == req.NodeID {
+			node = card.node
+		}
+	}
+
+	// 与节点更新相同的权限：节点负责人、执行人、任务负责人或任务创建者
+	taskInfo, err := l.svcCtx.TaskModel.FindOne(l.ctx, node.TaskId)
+	if err != nil {
+		return utils.Response.BusinessError("task_not_found"), nil
+	}
+	if node.LeaderId != employee.Id && !utils.Common.ContainsID(node.ExecutorId, employee.Id) &&
+		taskInfo.TaskCreator != employee.Id && (!taskInfo.LeaderId.Valid || taskInfo.LeaderId.String != employee.Id) {
+		return utils.Response.BusinessError("permission_denied"), nil
+	}
+
+	// 卡片位置在锁住看板后按最新的卡片重新计算，并发移动时在制品上限和排序值不会被另一个请求覆盖
+	var rejected *types.BaseResponse
+	saveCards := func(ctx context.Context, session sqlx.Session) error {
+		boardModel := l.svcCtx.TransactionHelper.GetTaskBoardModelWithSession(session)
+		if _, err := boardModel.LockOne(ctx, board.Id); err != nil {
+			return err
+		}
+		current, err := loadBoardLayoutWith(ctx, boardModel, l.svcCtx.TransactionHelper.GetTaskNodeModelWithSession(session), board)
+		if err != nil {
+			return err
+		}
+		cards, errResp := planCardMove(current, board.Id, req.NodeID, req.AfterNodeID, target, fromID != target.Id)
+		if errResp != nil {
+			rejected = errResp
+			return errBoardMoveRejected
+		}
+		return boardModel.SaveCards(ctx, cards)
+	}
+
+	if target.NodeStatus != node.NodeStatus {
+		// 改变节点状态时走节点更新流程：同样的校验、进度同步、状态历史和任务日志，节点状态与卡片位置在同一事务中写入
+		updateResp, err := tasknode.NewUpdateTaskNodeLogic(l.ctx, l.svcCtx).UpdateTaskNodeInTx(&types.UpdateTaskNodeRequest{
+			NodeID:     node.TaskNodeId,
+			NodeStatus: []int{int(target.NodeStatus)},
+		}, saveCards)
+		if rejected != nil {
+			return rejected, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if updateResp.Code != utils.SUCCESS {
+			return updateResp, nil
+		}
+	} else {
+		err := l.svcCtx.TransactionService.TransactCtx(l.ctx, saveCards)
+		if rejected != nil {
+			return rejected, nil
+		}
+		if err != nil {
+			l.Logger.Errorf("保存卡片位置失败: %v", err)
+			return utils.Response.InternalError("移动卡片失败"), nil
+		}
+		if fromID != target.Id {
+			// 同一状态的列之间移动，只记录任务日志
+			from := layout.column(fromID)
+			taskLog := &taskmodel.TaskLog{
+				LogId:      utils.Common.GenerateID(),
+				TaskId:     node.TaskId,
+				LogType:    2, // 更新类型
+				LogContent: fmt.Sprintf("任务节点 %s 在看板「%s」中从「%s」移动到「%s」", node.NodeName, board.Name, from.Name, target.Name),
+				EmployeeId: employee.Id,
+				CreateTime: time.Now(),
+			}
+			if _, err := l.svcCtx.TaskLogModel.Insert(l.ctx, taskLog); err != nil {
+				l.Logger.Errorf("创建任务日志失败: %v", err)
+			}
+		}
+	}
+
+	layout, err = loadBoardLayout(l.ctx, l.svcCtx, board)
+	if err != nil {
+		l.Logger.Errorf("查询看板卡片失败: %v", err)
+		return utils.Response.InternalError("查询看板失败"), nil
+	}
+	return utils.Response.Success(toBoardInfo(board, layout)), nil
+}
+
+// errBoardMoveRejected 事务内校验卡片移动未通过，回滚事务，具体原因通过响应返回
+var errBoardMoveRejected = errors.New("board card move rejected")
+
+// planCardMove 计算卡片移动到目标列指定位置后需要写入的卡片位置；checkWip 为 true（跨列移动）时校验目标列的在制品上限。
+// 前后卡片都有排序值时只更新当前卡片，否则（或排序值过长时）重新分配整列的排序值
+func planCardMove(layout *boardLayout, boardID, nodeID, afterNodeID string, target *taskmodel.TaskBoardColumn, checkWip bool) ([]*taskmodel.TaskBoardCard, *types.BaseResponse) {
+	// 目标列中除当前节点外的卡片，确定插入位置
+	others := make([]*boardCard, 0, len(layout.cards[target.Id]))
+	for _, card := range layout.cards[target.Id] {
+		if card.node.TaskNodeId != nodeID {
+			others = append(others, card)
+		}
+	}
+	pos := 0
+	if afterNodeID != "" {
+		pos = -1
+		for i, card := range others {
+			if card.node.TaskNodeId == afterNodeID {
+				pos = i + 1
+			}
+		}
+		if pos < 0 {
+			return nil, utils.Response.ValidationError("afterNodeId 不在目标列中")
+		}
+	}
+	if checkWip && target.WipLimit > 0 && int64(len(others)) >= target.WipLimit {
+		return nil, utils.Response.BusinessError("board_wip_limit_exceeded")
+	}
+
+	prev, next := "", ""
+	if pos > 0 {
+		prev = others[pos-1].rank
+	}
+	if pos < len(others) {
+		next = others[pos].rank
+	}
+	rank := ""
+	if (pos == 0 || prev != "") && (pos == len(others) || next != "") {
+		rank = svc.BoardRankBetween(prev, next)
+	}
+	if rank != "" && len(rank) <= svc.MaxBoardRankLength {
+		return []*taskmodel.TaskBoardCard{{BoardId: boardID, TaskNodeId: nodeID, ColumnId: target.Id, Rank: rank}}, nil
+	}
+	ordered := make([]string, 0, len(others)+1)
+	for _, card := range others[:pos] {
+		ordered = append(ordered, card.node.TaskNodeId)
+	}
+	ordered = append(ordered, nodeID)
+	for _, card := range others[pos:] {
+		ordered = append(ordered, card.node.TaskNodeId)
+	}
+	cards := make([]*taskmodel.TaskBoardCard, 0, len(ordered))
+	for i, r := range svc.BoardRankSequence(len(ordered)) {
+		cards = append(cards, &taskmodel.TaskBoardCard{BoardId: boardID, TaskNodeId: ordered[i], ColumnId: target.Id, Rank: r})
+	}
+	return cards, nil
+}
@@ -0,0 +1,71 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package board
+
+import (
+	"context"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateBoardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新看板名称和列
+func NewUpdateBoardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateBoardLogic {
+	return &UpdateBoardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateBoardLogic) UpdateBoard(req *types.UpdateBoardRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	board, errResp := loadBoard(l.ctx, l.svcCtx, employee, req.BoardID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if req.Name != "" {
+		name, msg := normalizeBoardName(req.Name, board.Name)
+		if msg != "" {
+			return utils.Response.ValidationError(msg), nil
+		}
+		board.Name = name
+	}
+	var columns []*taskmodel.TaskBoardColumn
+	if len(req.Columns) > 0 {
+		existing, err := l.svcCtx.TaskBoardModel.FindColumns(l.ctx, board.Id)
+		if err != nil {
+			l.Logger.Errorf("查询看板列失败: %v", err)
+			return utils.Response.InternalError("更新看板失败"), nil
+		}
+		var msg string
+		if columns, msg = buildBoardColumns(board.Id, req.Columns, existing); msg != "" {
+			return utils.Response.ValidationError(msg), nil
+		}
+	}
+	if err := l.svcCtx.TaskBoardModel.Update(l.ctx, board, columns); err != nil {
+		l.Logger.Errorf("更新看板失败: %v", err)
+		return utils.Response.InternalError("更新看板失败"), nil
+	}
+
+	layout, err := loadBoardLayout(l.ctx, l.svcCtx, board)
+	if err != nil {
+		l.Logger.Errorf("查询看板卡片失败: %v", err)
+		return utils.Response.InternalError("查询看板失败"), nil
+	}
+	return utils.Response.Success(toBoardInfo(board, layout)), nil
+}
@@ -65,9 +65,13 @@ func (l *ApproveTaskNodeCompletionLogic) ApproveTaskNodeCompletion(req *types.Ap
 		return utils.Response.BusinessError("approval_already_done"), nil
 	}
 
-	// 6. 验证权限：只有审批人（项目负责人）可以审批
+	// 6. 验证权限：只有审批人（项目负责人）或其外出期间的代理人可以审批
 	if approval.ApproverId != employeeId {
-		return utils.Response.BusinessError("approval_permission_denied"), nil
+		delegate, ok := l.svcCtx.OutOfOfficeService.ResolveDelegate(l.ctx, approval.ApproverId)
+		if !ok || delegate.Id != employeeId {
+			return utils.Response.BusinessError("approval_permission_denied"), nil
+		}
+		approval.ApproverId = employeeId
 	}
 
 	// 7. 获取任务节点ID
@@ -119,6 +123,7 @@ func (l *ApproveTaskNodeCompletionLogic) ApproveTaskNodeCompletion(req *types.Ap
 			l.Logger.WithContext(l.ctx).Errorf("更新任务节点失败: %v", err)
 			return nil, err
 		}
+		l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskNode.TaskId, taskNodeId, taskNode.NodeName, employeeId, taskNode.NodeStatus, updatedNode.NodeStatus)
 
 		// 获取当前任务的所有节点
 		current, err := l.svcCtx.TaskNodeModel.FindOne(l.ctx, taskNodeId)
@@ -157,7 +162,9 @@ func (l *ApproveTaskNodeCompletionLogic) ApproveTaskNodeCompletion(req *types.Ap
 
 							// 只有当所有前置节点都已完成时，才将该节点状态更新为进行中
 							if allPrerequisitesCompleted {
-								l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, node.TaskNodeId, 1)
+								if err := l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, node.TaskNodeId, 1); err == nil {
+									l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, node.TaskId, node.TaskNodeId, node.NodeName, employeeId, node.NodeStatus, 1)
+								}
 								l.Logger.WithContext(l.ctx).Infof("节点 %s 的所有前置节点已完成，状态更新为进行中", node.TaskNodeId)
 							}
 							break
@@ -211,6 +218,7 @@ func (l *ApproveTaskNodeCompletionLogic) ApproveTaskNodeCompletion(req *types.Ap
 			l.Logger.WithContext(l.ctx).Errorf("更新任务节点状态失败: %v", err)
 			return nil, err
 		}
+		l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskNode.TaskId, taskNodeId, taskNode.NodeName, employeeId, taskNode.NodeStatus, updatedNode.NodeStatus)
 
 		// 发送通知给节点执行人（支持多执行人）
 		if l.svcCtx.NotificationMQService != nil && taskNode.ExecutorId != "" {
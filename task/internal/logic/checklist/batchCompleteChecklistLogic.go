@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"task_Project/model/task"
 	"task_Project/task/internal/svc"
 	"task_Project/task/internal/types"
 	"task_Project/task/internal/utils"
@@ -40,6 +41,7 @@ func (l *BatchCompleteChecklistLogic) BatchCompleteChecklist(req *types.BatchCom
 	// 3. 验证权限并收集需要更新的节点ID
 	nodeIds := make(map[string]bool)
 	validChecklistIds := make([]string, 0)
+	changedChecklists := make([]*task.TaskChecklist, 0)
 
 	for _, checklistId := range req.ChecklistIDs {
 		checklist, err := l.svcCtx.TaskChecklistModel.FindOne(l.ctx, checklistId)
@@ -60,6 +62,9 @@ func (l *BatchCompleteChecklistLogic) BatchCompleteChecklist(req *types.BatchCom
 
 		validChecklistIds = append(validChecklistIds, checklistId)
 		nodeIds[checklist.TaskNodeId] = true
+		if checklist.IsCompleted != req.IsCompleted {
+			changedChecklists = append(changedChecklists, checklist)
+		}
 	}
 
 	if len(validChecklistIds) == 0 {
@@ -73,6 +78,21 @@ func (l *BatchCompleteChecklistLogic) BatchCompleteChecklist(req *types.BatchCom
 		return nil, errors.New("批量更新清单状态失败")
 	}
 
+	// 记录完成状态发生变化的清单
+	nodeTaskIds := make(map[string]string)
+	for _, checklist := range changedChecklists {
+		taskId, ok := nodeTaskIds[checklist.TaskNodeId]
+		if !ok {
+			if node, err := l.svcCtx.TaskNodeModel.FindOne(l.ctx, checklist.TaskNodeId); err == nil {
+				taskId = node.TaskId
+			}
+			nodeTaskIds[checklist.TaskNodeId] = taskId
+		}
+		if taskId != "" {
+			l.svcCtx.TaskHistoryService.RecordChecklistStatus(l.ctx, taskId, checklist.TaskNodeId, checklist.ChecklistId, checklist.Content, employeeId, req.IsCompleted == 1)
+		}
+	}
+
 	// 5. 更新相关任务节点的清单统计和进度
 	for nodeId := range nodeIds {
 		err = l.updateNodeChecklistCount(nodeId)
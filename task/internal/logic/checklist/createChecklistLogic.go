@@ -79,9 +79,10 @@ func (l *CreateChecklistLogic) CreateChecklist(req *types.CreateChecklistRequest
 		l.Logger.WithContext(l.ctx).Errorf("创建任务清单失败: %v", err)
 		return nil, errors.New("创建任务清单失败")
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocChecklist, checklist.ChecklistId)
 
 	// 6. 更新任务节点的清单统计
-	err = l.updateNodeChecklistCount(req.TaskNodeID)
+	err = l.updateNodeChecklistCount(taskNode, employeeId)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("更新任务节点清单统计失败: %v", err)
 		// 不影响主流程，只记录日志
@@ -124,13 +125,15 @@ func (l *CreateChecklistLogic) CreateChecklist(req *types.CreateChecklistRequest
 }
 
 // updateNodeChecklistCount 更新任务节点的清单统计
-func (l *CreateChecklistLogic) updateNodeChecklistCount(taskNodeId string) error {
-	total, completed, err := l.svcCtx.TaskChecklistModel.CountByTaskNodeId(l.ctx, taskNodeId)
+func (l *CreateChecklistLogic) updateNodeChecklistCount(taskNode *task.TaskNode, employeeId string) error {
+	total, completed, err := l.svcCtx.TaskChecklistModel.CountByTaskNodeId(l.ctx, taskNode.TaskNodeId)
 	if err != nil {
 		return err
 	}
-	_ = l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, taskNodeId, 1)
-	return l.svcCtx.TaskNodeModel.UpdateChecklistCount(l.ctx, taskNodeId, total, completed)
+	if err := l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, taskNode.TaskNodeId, 1); err == nil {
+		l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskNode.TaskId, taskNode.TaskNodeId, taskNode.NodeName, employeeId, taskNode.NodeStatus, 1)
+	}
+	return l.svcCtx.TaskNodeModel.UpdateChecklistCount(l.ctx, taskNode.TaskNodeId, total, completed)
 }
 
 // isExecutor 检查员工是否是任务节点的执行人
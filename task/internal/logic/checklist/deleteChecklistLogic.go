@@ -55,6 +55,7 @@ func (l *DeleteChecklistLogic) DeleteChecklist(req *types.DeleteChecklistRequest
 		l.Logger.WithContext(l.ctx).Errorf("删除清单失败: %v", err)
 		return nil, errors.New("删除清单失败")
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocChecklist, req.ChecklistID)
 
 	// 5. 更新任务节点的清单统计
 	err = l.updateNodeChecklistCount(taskNodeId)
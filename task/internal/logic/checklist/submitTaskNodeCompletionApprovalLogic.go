@@ -119,6 +119,14 @@ func (l *SubmitTaskNodeCompletionApprovalLogic) SubmitTaskNodeCompletionApproval
 		return nil, errors.New("该任务节点未设置负责人，无法提交审批")
 	}
 
+	// 负责人外出时转给其代理人审批（代理人是提交人自己时仍由负责人审批）
+	delegatedFromId := ""
+	if delegate, ok := l.svcCtx.OutOfOfficeService.ResolveDelegate(l.ctx, approverId); ok && delegate.Id != employeeId {
+		l.Logger.WithContext(l.ctx).Infof("[审批人查找] 节点负责人 %s 外出中，转给代理人: %s", approverId, delegate.Id)
+		delegatedFromId = approverId
+		approverId = delegate.Id
+	}
+
 	// 获取审批人姓名
 	if approverId != "" {
 		employee, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, approverId)
@@ -146,6 +154,7 @@ func (l *SubmitTaskNodeCompletionApprovalLogic) SubmitTaskNodeCompletionApproval
 			l.Logger.WithContext(l.ctx).Errorf("更新任务节点失败: %v", err)
 			return nil, err
 		}
+		l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskNode.TaskId, taskNode.TaskNodeId, taskNode.NodeName, employeeId, taskNode.NodeStatus, updatedNode.NodeStatus)
 
 		// 创建已通过的审批记录
 		approvalId := utils.Common.GenId("approval")
@@ -217,6 +226,10 @@ func (l *SubmitTaskNodeCompletionApprovalLogic) SubmitTaskNodeCompletionApproval
 		return nil, err
 	}
 
+	if delegatedFromId != "" {
+		l.svcCtx.OutOfOfficeService.RecordRoutedItem(l.ctx, delegatedFromId, approverId, svc.OutOfOfficeItemTaskNodeApproval, approvalId, "节点完成审批："+taskNode.NodeName)
+	}
+
 	// 10. 注意：节点状态保持为进行中（状态1），不改为待审批状态
 	// 审批状态通过审批记录（HandoverApproval）来管理，而不是节点状态
 
@@ -293,7 +306,10 @@ func (l *SubmitTaskNodeCompletionApprovalLogic) activateSubsequentNodes(complete
 
 					// 只有当所有前置节点都已完成时，才将该节点状态更新为进行中
 					if allPrerequisitesCompleted {
-						l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, node.TaskNodeId, 1)
+						if err := l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, node.TaskNodeId, 1); err == nil {
+							operatorId, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
+							l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskId, node.TaskNodeId, node.NodeName, operatorId, node.NodeStatus, 1)
+						}
 						l.Logger.WithContext(l.ctx).Infof("节点 %s 的所有前置节点已完成，状态更新为进行中", node.TaskNodeId)
 					}
 					break
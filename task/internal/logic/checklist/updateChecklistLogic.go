@@ -76,6 +76,7 @@ func (l *UpdateChecklistLogic) UpdateChecklist(req *types.UpdateChecklistRequest
 		l.Logger.WithContext(l.ctx).Errorf("更新清单失败: %v", err)
 		return nil, errors.New("更新清单失败")
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocChecklist, checklist.ChecklistId)
 
 	// 6. 如果完成状态发生变化，更新任务节点的清单统计
 	if oldCompletedStatus != checklist.IsCompleted {
@@ -103,6 +104,9 @@ func (l *UpdateChecklistLogic) UpdateChecklist(req *types.UpdateChecklistRequest
 		l.Logger.WithContext(l.ctx).Errorf("查询任务失败: %v", err)
 		return nil, errors.New("任务不存在")
 	}
+	if oldCompletedStatus != checklist.IsCompleted {
+		l.svcCtx.TaskHistoryService.RecordChecklistStatus(l.ctx, one.TaskId, one.TaskNodeId, checklist.ChecklistId, checklist.Content, employeeId, checklist.IsCompleted == 1)
+	}
 	// 创建任务日志
 	// 7. 创建任务日志
 	taskLog := &task.TaskLog{
@@ -54,12 +54,6 @@ func (l *CreateCompanyLogic) CreateCompany(req *types.CreateCompanyRequest) (res
 		return utils.Response.InternalError("查询用户失败"), nil
 	}
 
-	// 检查用户是否已经加入其他公司
-	existingEmployee, _ := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
-	if existingEmployee != nil {
-		return utils.Response.BusinessError("user_already_in_company"), nil
-	}
-
 	// 检查公司名称是否已存在
 	existingCompanies, err := l.svcCtx.CompanyModel.FindByOwner(l.ctx, userID)
 	if err != nil {
@@ -293,6 +287,8 @@ func (l *CreateCompanyLogic) CreateCompany(req *types.CreateCompanyRequest) (res
 		} else {
 			logx.Infof("已更新Token: userId=%s, employeeId=%s, companyId=%s", userID, employeeID, companyID)
 		}
+		// 新令牌已切换到新公司，下次登录同样进入该公司
+		l.svcCtx.CompanyMembershipService.Remember(userID, companyID)
 	}
 
 	// 发送创建成功通知邮件
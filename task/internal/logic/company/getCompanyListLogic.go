@@ -6,7 +6,6 @@ package company
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 
 	"task_Project/model/company"
@@ -62,25 +61,33 @@ func (l *GetCompanyListLogic) GetCompanyList(req *types.CompanyListRequest) (res
 	}
 	// 过滤用户自己的公司（如果不是管理员）
 	// 这里可以根据用户角色来决定是否只显示自己的公司
-	emInfo, err := l.svcCtx.EmployeeModel.FindOneByUserId(l.ctx, userID)
+	// 用户可在多家公司任职，逐个展示其所在的公司
+	memberships, err := l.svcCtx.EmployeeModel.FindAllByUserID(l.ctx, userID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("查找该用户公司信息失败: %v", err)
 		return nil, err
 	}
-	companyInfo, err := l.svcCtx.CompanyModel.FindOne(l.ctx, emInfo.CompanyId)
-	if err != nil && !errors.Is(err, sqlx.ErrNotFound) {
-		l.Logger.WithContext(l.ctx).Errorf("查找公司错误: %v", err)
-		return nil, err
-	}
 
 	//如果是某公司的员工也要展示
 	filteredCompanies := []*company.Company{}
-	if companyInfo != nil {
-		filteredCompanies = append(filteredCompanies, companyInfo)
+	seen := make(map[string]bool)
+	for _, emInfo := range memberships {
+		if seen[emInfo.CompanyId] {
+			continue
+		}
+		companyInfo, err := l.svcCtx.CompanyModel.FindOne(l.ctx, emInfo.CompanyId)
+		if err != nil && !errors.Is(err, sqlx.ErrNotFound) {
+			l.Logger.WithContext(l.ctx).Errorf("查找公司错误: %v", err)
+			return nil, err
+		}
+		if companyInfo != nil {
+			seen[companyInfo.Id] = true
+			filteredCompanies = append(filteredCompanies, companyInfo)
+		}
 	}
 	for _, comp := range companies {
-		fmt.Println("公司", comp.Owner)
-		if comp.Owner == userID {
+		if comp.Owner == userID && !seen[comp.Id] {
+			seen[comp.Id] = true
 			filteredCompanies = append(filteredCompanies, comp)
 		}
 	}
@@ -34,7 +34,7 @@ func (l *GetInviteCodeListLogic) GetInviteCodeList(req *types.GetInviteCodeListR
 	}
 
 	// 获取当前员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil || employee == nil {
 		return utils.Response.BusinessError("employee_not_in_company"), nil
 	}
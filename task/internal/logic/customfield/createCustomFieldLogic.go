@@ -0,0 +1,91 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateCustomFieldLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建自定义字段
+func NewCreateCustomFieldLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateCustomFieldLogic {
+	return &CreateCustomFieldLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateCustomFieldLogic) CreateCustomField(req *types.CreateCustomFieldRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("custom_field_no_permission"), nil
+	}
+	if req.EntityType != taskmodel.CustomFieldEntityTask && req.EntityType != taskmodel.CustomFieldEntityNode {
+		return utils.Response.ValidationError("适用对象只支持 task 或 node"), nil
+	}
+	if !svc.IsCustomFieldType(req.FieldType) {
+		return utils.Response.ValidationError("不支持的字段类型"), nil
+	}
+	name, msg := normalizeFieldName(req.Name)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	options, msg := buildFieldOptions(req.FieldType, req.Options, nil)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if _, err := l.svcCtx.CustomFieldModel.FindByName(l.ctx, employee.CompanyId, req.EntityType, name); err == nil {
+		return utils.Response.BusinessError("custom_field_name_exists"), nil
+	} else if !errors.Is(err, taskmodel.ErrNotFound) {
+		l.Logger.Errorf("查询自定义字段失败: %v", err)
+		return utils.Response.InternalError("创建自定义字段失败"), nil
+	}
+	count, err := l.svcCtx.CustomFieldModel.CountByCompany(l.ctx, employee.CompanyId)
+	if err != nil {
+		l.Logger.Errorf("统计自定义字段数量失败: %v", err)
+		return utils.Response.InternalError("创建自定义字段失败"), nil
+	}
+	if count >= maxCompanyCustomFields {
+		return utils.Response.BusinessError("custom_field_limit"), nil
+	}
+
+	now := time.Now()
+	field := &taskmodel.CustomField{
+		Id:         utils.Common.GenId("cf"),
+		CompanyId:  employee.CompanyId,
+		EntityType: req.EntityType,
+		Name:       name,
+		FieldType:  req.FieldType,
+		Options:    options,
+		Sort:       req.Sort,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if req.Required {
+		field.Required = 1
+	}
+	if _, err := l.svcCtx.CustomFieldModel.Insert(l.ctx, field); err != nil {
+		l.Logger.Errorf("创建自定义字段失败: %v", err)
+		return utils.Response.InternalError("创建自定义字段失败"), nil
+	}
+	return utils.Response.Success(toCustomFieldInfo(field)), nil
+}
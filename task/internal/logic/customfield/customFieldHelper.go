@@ -0,0 +1,170 @@
+package customfield
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 自定义字段数量和长度限制
+const (
+	maxCompanyCustomFields  = 50 // 每个公司最多的字段数
+	maxCustomFieldNameRunes = 32
+	maxOptionLabelRunes     = 32
+)
+
+// loadCompanyField 查询当前公司的字段
+func loadCompanyField(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, fieldID string) (*taskmodel.CustomField, *types.BaseResponse) {
+	if fieldID == "" {
+		return nil, utils.Response.ValidationError("字段ID不能为空")
+	}
+	field, err := svcCtx.CustomFieldModel.FindOne(ctx, fieldID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return nil, utils.Response.BusinessError("custom_field_not_found")
+		}
+		return nil, utils.Response.InternalError("查询自定义字段失败")
+	}
+	if field.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("custom_field_not_found")
+	}
+	return field, nil
+}
+
+// normalizeFieldName 校验字段名称
+func normalizeFieldName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "字段名称不能为空"
+	}
+	if utf8.RuneCountInString(name) > maxCustomFieldNameRunes {
+		return "", "字段名称不能超过 32 个字符"
+	}
+	return name, ""
+}
+
+// buildFieldOptions 校验单选/多选的选项并序列化；未带ID的选项生成新ID，
+// 带ID的选项必须是字段已有的选项（existing 为空表示新建字段）
+func buildFieldOptions(fieldType string, input []types.CustomFieldOptionInfo, existing []svc.CustomFieldOption) (sql.NullString, string) {
+	if !svc.HasCustomFieldOptions(fieldType) {
+		if len(input) > 0 {
+			return sql.NullString{}, "只有单选和多选字段可以设置选项"
+		}
+		return sql.NullString{}, ""
+	}
+	if len(input) == 0 {
+		return sql.NullString{}, "单选和多选字段至少需要一个选项"
+	}
+	if len(input) > svc.MaxCustomFieldOptions {
+		return sql.NullString{}, fmt.Sprintf("选项不能超过 %d 个", svc.MaxCustomFieldOptions)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, o := range existing {
+		known[o.ID] = true
+	}
+	labels := make(map[string]bool, len(input))
+	options := make([]svc.CustomFieldOption, 0, len(input))
+	for _, o := range input {
+		label := strings.TrimSpace(o.Label)
+		if label == "" {
+			return sql.NullString{}, "选项名称不能为空"
+		}
+		if utf8.RuneCountInString(label) > maxOptionLabelRunes {
+			return sql.NullString{}, "选项名称不能超过 32 个字符"
+		}
+		if labels[label] {
+			return sql.NullString{}, fmt.Sprintf("选项「%s」重复", label)
+		}
+		labels[label] = true
+		id := strings.TrimSpace(o.ID)
+		if id == "" {
+			id = utils.Common.GenId("opt")
+		} else if !known[id] {
+			return sql.NullString{}, fmt.Sprintf("选项「%s」的ID不存在", label)
+		}
+		delete(known, id)
+		options = append(options, svc.CustomFieldOption{ID: id, Label: label, Color: strings.TrimSpace(o.Color)})
+	}
+	data, _ := json.Marshal(options)
+	return sql.NullString{String: string(data), Valid: true}, ""
+}
+
+func toCustomFieldInfo(field *taskmodel.CustomField) types.CustomFieldInfo {
+	options := make([]types.CustomFieldOptionInfo, 0)
+	for _, o := range svc.ParseCustomFieldOptions(field) {
+		options = append(options, types.CustomFieldOptionInfo{ID: o.ID, Label: o.Label, Color: o.Color})
+	}
+	return types.CustomFieldInfo{
+		ID:            field.Id,
+		EntityType:    field.EntityType,
+		Name:          field.Name,
+		FieldType:     field.FieldType,
+		FieldTypeName: svc.CustomFieldTypeName(field.FieldType),
+		Options:       options,
+		Required:      field.Required == 1,
+		Sort:          field.Sort,
+		CreateTime:    utils.Common.FormatTime(field.CreateTime),
+	}
+}
+
+// resolveEntity 校验任务或任务节点属于当前公司，并返回当前员工是否可以填写字段值：
+// 任务为创建者、负责人；节点另外包括节点负责人和执行人；管理人员都可以填写
+func resolveEntity(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, entityType, entityID string) (bool, *types.BaseResponse) {
+	if entityID == "" {
+		return false, utils.Response.ValidationError("对象ID不能为空")
+	}
+	taskID := entityID
+	editors := map[string]bool{}
+	switch entityType {
+	case taskmodel.CustomFieldEntityTask:
+	case taskmodel.CustomFieldEntityNode:
+		node, err := svcCtx.TaskNodeModel.FindOne(ctx, entityID)
+		if err != nil || node.DeleteTime.Valid {
+			return false, utils.Response.BusinessError("task_node_not_found")
+		}
+		taskID = node.TaskId
+		editors[node.LeaderId] = true
+		editors[node.ExecutorId] = true
+	default:
+		return false, utils.Response.ValidationError("适用对象只支持 task 或 node")
+	}
+	taskInfo, err := svcCtx.TaskModel.FindOne(ctx, taskID)
+	if err != nil || taskInfo.DeleteTime.Valid || taskInfo.CompanyId != employee.CompanyId {
+		if entityType == taskmodel.CustomFieldEntityNode {
+			return false, utils.Response.BusinessError("task_node_not_found")
+		}
+		return false, utils.Response.BusinessError("task_not_found")
+	}
+	editors[taskInfo.TaskCreator] = true
+	if taskInfo.LeaderId.Valid {
+		editors[taskInfo.LeaderId.String] = true
+	}
+	return editors[employee.Id] || svcCtx.IsCompanyAdmin(ctx, employee), nil
+}
+
+// buildValueInfos 对象在各字段上的值，没有填写的字段返回空值
+func buildValueInfos(ctx context.Context, svcCtx *svc.ServiceContext, fields []*taskmodel.CustomField, values map[string]string) []types.CustomFieldValueInfo {
+	nameOf := svcCtx.CustomFieldService.EmployeeNamer(ctx)
+	list := make([]types.CustomFieldValueInfo, 0, len(fields))
+	for _, field := range fields {
+		value := values[field.Id]
+		list = append(list, types.CustomFieldValueInfo{
+			FieldID:   field.Id,
+			Name:      field.Name,
+			FieldType: field.FieldType,
+			Values:    svc.SplitCustomFieldValue(field, value),
+			Display:   svcCtx.CustomFieldService.FormatValue(field, value, nameOf),
+		})
+	}
+	return list
+}
@@ -0,0 +1,50 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CustomFieldListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 公司的自定义字段
+func NewCustomFieldListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CustomFieldListLogic {
+	return &CustomFieldListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CustomFieldListLogic) CustomFieldList(req *types.CustomFieldListRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if req.EntityType != "" && req.EntityType != taskmodel.CustomFieldEntityTask && req.EntityType != taskmodel.CustomFieldEntityNode {
+		return utils.Response.ValidationError("适用对象只支持 task 或 node"), nil
+	}
+	fields, err := l.svcCtx.CustomFieldModel.FindByCompany(l.ctx, employee.CompanyId, req.EntityType)
+	if err != nil {
+		l.Logger.Errorf("查询自定义字段失败: %v", err)
+		return utils.Response.InternalError("查询自定义字段失败"), nil
+	}
+	list := make([]types.CustomFieldInfo, 0, len(fields))
+	for _, field := range fields {
+		list = append(list, toCustomFieldInfo(field))
+	}
+	return utils.Response.Success(list), nil
+}
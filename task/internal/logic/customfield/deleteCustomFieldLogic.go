@@ -0,0 +1,48 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteCustomFieldLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除自定义字段及其全部值
+func NewDeleteCustomFieldLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteCustomFieldLogic {
+	return &DeleteCustomFieldLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteCustomFieldLogic) DeleteCustomField(req *types.DeleteCustomFieldRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("custom_field_no_permission"), nil
+	}
+	field, errResp := loadCompanyField(l.ctx, l.svcCtx, employee, req.FieldID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := l.svcCtx.CustomFieldModel.Delete(l.ctx, field.Id); err != nil {
+		l.Logger.Errorf("删除自定义字段失败: %v", err)
+		return utils.Response.InternalError("删除自定义字段失败"), nil
+	}
+	return utils.Response.Success("自定义字段已删除"), nil
+}
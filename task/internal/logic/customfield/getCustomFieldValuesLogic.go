@@ -0,0 +1,51 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetCustomFieldValuesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 任务或任务节点的自定义字段值
+func NewGetCustomFieldValuesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCustomFieldValuesLogic {
+	return &GetCustomFieldValuesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetCustomFieldValuesLogic) GetCustomFieldValues(req *types.GetCustomFieldValuesRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if _, errResp := resolveEntity(l.ctx, l.svcCtx, employee, req.EntityType, req.EntityID); errResp != nil {
+		return errResp, nil
+	}
+	fields, err := l.svcCtx.CustomFieldModel.FindByCompany(l.ctx, employee.CompanyId, req.EntityType)
+	if err != nil {
+		l.Logger.Errorf("查询自定义字段失败: %v", err)
+		return utils.Response.InternalError("查询自定义字段失败"), nil
+	}
+	ids := []string{req.EntityID}
+	values, err := l.svcCtx.CustomFieldService.LoadValues(l.ctx, ids)
+	if err != nil {
+		l.Logger.Errorf("查询自定义字段值失败: %v", err)
+		return utils.Response.InternalError("查询自定义字段值失败"), nil
+	}
+	return utils.Response.Success(buildValueInfos(l.ctx, l.svcCtx, fields, values[req.EntityID])), nil
+}
@@ -0,0 +1,86 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetCustomFieldValuesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 填写任务或任务节点的自定义字段值
+func NewSetCustomFieldValuesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetCustomFieldValuesLogic {
+	return &SetCustomFieldValuesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetCustomFieldValuesLogic) SetCustomFieldValues(req *types.SetCustomFieldValuesRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	canEdit, errResp := resolveEntity(l.ctx, l.svcCtx, employee, req.EntityType, req.EntityID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !canEdit {
+		return utils.Response.BusinessError("custom_field_value_denied"), nil
+	}
+	if len(req.Values) == 0 {
+		return utils.Response.ValidationError("请填写字段值"), nil
+	}
+	if len(req.Values) > maxCompanyCustomFields {
+		return utils.Response.ValidationError("一次最多填写 50 个字段"), nil
+	}
+
+	values := make(map[string]string, len(req.Values))
+	for _, input := range req.Values {
+		field, errResp := loadCompanyField(l.ctx, l.svcCtx, employee, input.FieldID)
+		if errResp != nil {
+			return errResp, nil
+		}
+		if field.EntityType != req.EntityType {
+			return utils.Response.BusinessError("custom_field_not_found"), nil
+		}
+		raw := input.Values
+		if field.FieldType == taskmodel.CustomFieldEmployee && len(raw) == 1 && raw[0] == "me" {
+			raw = []string{employee.Id}
+		}
+		stored, msg := l.svcCtx.CustomFieldService.NormalizeValue(l.ctx, field, raw)
+		if msg != "" {
+			return utils.Response.ValidationError(msg), nil
+		}
+		values[field.Id] = stored
+	}
+	if err := l.svcCtx.CustomFieldModel.SetValues(l.ctx, req.EntityID, values); err != nil {
+		l.Logger.Errorf("保存自定义字段值失败: %v", err)
+		return utils.Response.InternalError("保存自定义字段值失败"), nil
+	}
+
+	fields, err := l.svcCtx.CustomFieldModel.FindByCompany(l.ctx, employee.CompanyId, req.EntityType)
+	if err != nil {
+		l.Logger.Errorf("查询自定义字段失败: %v", err)
+		return utils.Response.InternalError("查询自定义字段失败"), nil
+	}
+	current, err := l.svcCtx.CustomFieldService.LoadValues(l.ctx, []string{req.EntityID})
+	if err != nil {
+		l.Logger.Errorf("查询自定义字段值失败: %v", err)
+		return utils.Response.InternalError("查询自定义字段值失败"), nil
+	}
+	return utils.Response.Success(buildValueInfos(l.ctx, l.svcCtx, fields, current[req.EntityID])), nil
+}
@@ -0,0 +1,73 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package customfield
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateCustomFieldLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新自定义字段
+func NewUpdateCustomFieldLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateCustomFieldLogic {
+	return &UpdateCustomFieldLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateCustomFieldLogic) UpdateCustomField(req *types.UpdateCustomFieldRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("custom_field_no_permission"), nil
+	}
+	field, errResp := loadCompanyField(l.ctx, l.svcCtx, employee, req.FieldID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	name, msg := normalizeFieldName(req.Name)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	options, msg := buildFieldOptions(field.FieldType, req.Options, svc.ParseCustomFieldOptions(field))
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if name != field.Name {
+		if _, err := l.svcCtx.CustomFieldModel.FindByName(l.ctx, employee.CompanyId, field.EntityType, name); err == nil {
+			return utils.Response.BusinessError("custom_field_name_exists"), nil
+		} else if !errors.Is(err, taskmodel.ErrNotFound) {
+			l.Logger.Errorf("查询自定义字段失败: %v", err)
+			return utils.Response.InternalError("更新自定义字段失败"), nil
+		}
+	}
+
+	// 已删除的选项在已填写的值中保留，显示时忽略
+	field.Name, field.Options, field.Sort = name, options, req.Sort
+	field.Required = 0
+	if req.Required {
+		field.Required = 1
+	}
+	if err := l.svcCtx.CustomFieldModel.Update(l.ctx, field); err != nil {
+		l.Logger.Errorf("更新自定义字段失败: %v", err)
+		return utils.Response.InternalError("更新自定义字段失败"), nil
+	}
+	return utils.Response.Success(toCustomFieldInfo(field)), nil
+}
@@ -0,0 +1,96 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ApplyImportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 执行导入（中断后从断点继续）
+func NewApplyImportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApplyImportLogic {
+	return &ApplyImportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ApplyImportLogic) ApplyImport(req *types.ApplyImportRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	job, errResp := loadImportJob(l.ctx, l.svcCtx, operator, req.JobID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if errResp := checkImportPermission(l.ctx, l.svcCtx, operator, job.ImportType); errResp != nil {
+		return errResp, nil
+	}
+	switch job.Status {
+	case task.ImportStatusInvalid:
+		return utils.Response.BusinessError("import_has_errors"), nil
+	case task.ImportStatusRunning:
+		return utils.Response.BusinessError("import_running"), nil
+	case task.ImportStatusDone:
+		return utils.Response.BusinessError("import_finished"), nil
+	}
+
+	records, err := svc.DecodeImportRecords(job.Records)
+	if err != nil {
+		l.Logger.Errorf("读取导入数据失败: jobId=%s, err=%v", job.Id, err)
+		return utils.Response.InternalError("读取导入数据失败"), nil
+	}
+
+	// 从上传校验到执行之间数据可能已经变化，重新校验尚未写入的记录
+	records, err = validateImportRecords(l.ctx, l.svcCtx, operator, job.ImportType, records, int(job.AppliedRows))
+	if err != nil {
+		l.Logger.Errorf("校验导入数据失败: %v", err)
+		return utils.Response.InternalError("校验导入数据失败"), nil
+	}
+	encoded, err := svc.EncodeImportRecords(records)
+	if err != nil {
+		return utils.Response.InternalError("保存导入数据失败"), nil
+	}
+	errorCount := countImportErrors(records)
+	if errorCount > 0 {
+		// 尚未写入任何记录时退回校验未通过；已部分写入的保持已中断，处理冲突数据后可以继续
+		if job.AppliedRows == 0 {
+			job.Status = task.ImportStatusInvalid
+		}
+		if err := l.svcCtx.ImportJobModel.UpdateRecords(l.ctx, job.Id, job.Status, job.TotalRows, int64(errorCount), encoded); err != nil {
+			l.Logger.Errorf("保存校验结果失败: %v", err)
+		}
+		return utils.Response.BusinessError("import_has_errors"), nil
+	}
+	if err := l.svcCtx.ImportJobModel.UpdateRecords(l.ctx, job.Id, job.Status, job.TotalRows, 0, encoded); err != nil {
+		l.Logger.Errorf("保存导入数据失败: %v", err)
+		return utils.Response.InternalError("保存导入数据失败"), nil
+	}
+
+	if err := l.svcCtx.ImportService.Start(l.ctx, job, records, newImportApplier(l.svcCtx, operator, job.ImportType)); err != nil {
+		if errors.Is(err, svc.ErrImportRunning) {
+			return utils.Response.BusinessError("import_running"), nil
+		}
+		l.Logger.Errorf("启动导入失败: %v", err)
+		return utils.Response.InternalError("启动导入失败"), nil
+	}
+	job.ErrorCount = 0
+	job.ErrorMessage.Valid = false
+	return utils.Response.Success(toImportJobInfo(job)), nil
+}
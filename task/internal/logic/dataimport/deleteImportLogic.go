@@ -0,0 +1,50 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteImportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除导入记录
+func NewDeleteImportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteImportLogic {
+	return &DeleteImportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteImportLogic) DeleteImport(req *types.DeleteImportRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	job, errResp := loadImportJob(l.ctx, l.svcCtx, operator, req.JobID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if job.Status == task.ImportStatusRunning {
+		return utils.Response.BusinessError("import_running"), nil
+	}
+	// 只删除导入记录，已写入的数据保留
+	if err := l.svcCtx.ImportJobModel.Delete(l.ctx, job.Id); err != nil {
+		l.Logger.Errorf("删除导入记录失败: %v", err)
+		return utils.Response.InternalError("删除导入记录失败"), nil
+	}
+	return utils.Response.Success(nil), nil
+}
@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetImportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询导入任务及校验报告
+func NewGetImportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetImportLogic {
+	return &GetImportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetImportLogic) GetImport(req *types.GetImportRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	job, errResp := loadImportJob(l.ctx, l.svcCtx, operator, req.JobID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	records, err := svc.DecodeImportRecords(job.Records)
+	if err != nil {
+		l.Logger.Errorf("读取导入数据失败: jobId=%s, err=%v", job.Id, err)
+		return utils.Response.InternalError("读取导入数据失败"), nil
+	}
+	return utils.Response.Success(toImportReport(job, records)), nil
+}
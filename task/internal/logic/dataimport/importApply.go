@@ -0,0 +1,357 @@
+package dataimport
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"task_Project/model/company"
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// importApplier 按导入内容写入记录，所有写入都使用事务会话
+type importApplier struct {
+	svcCtx     *svc.ServiceContext
+	operator   *user.Employee
+	importType string
+}
+
+func newImportApplier(svcCtx *svc.ServiceContext, operator *user.Employee, importType string) svc.ImportApplier {
+	return &importApplier{svcCtx: svcCtx, operator: operator, importType: importType}
+}
+
+func (a *importApplier) Apply(ctx context.Context, session sqlx.Session, record *svc.ImportRecord) error {
+	switch a.importType {
+	case svc.ImportTypeDepartment:
+		return a.applyDepartment(ctx, session, record)
+	case svc.ImportTypePosition:
+		return a.applyPosition(ctx, session, record)
+	case svc.ImportTypeEmployee:
+		return a.applyEmployee(ctx, session, record)
+	case svc.ImportTypeTask:
+		return a.applyTask(ctx, session, record)
+	}
+	return fmt.Errorf("unknown import type %s", a.importType)
+}
+
+func (a *importApplier) applyDepartment(ctx context.Context, session sqlx.Session, record *svc.ImportRecord) error {
+	priority, _ := parseImportInt(record.Values["priority"], 0, 6)
+	now := time.Now()
+	_, err := a.svcCtx.TransactionHelper.GetDepartmentModelWithSession(session).Insert(ctx, &company.Department{
+		Id:                 record.ID,
+		CompanyId:          a.operator.CompanyId,
+		ParentId:           utils.Common.ToSqlNullString(record.Ref("parentId")),
+		DepartmentName:     record.Values["name"],
+		DepartmentCode:     utils.Common.ToSqlNullString(record.Values["code"]),
+		DepartmentPriority: priority,
+		ManagerId:          utils.Common.ToSqlNullString(record.Ref("managerId")),
+		Description:        utils.Common.ToSqlNullString(record.Values["description"]),
+		Status:             1, // 正常状态
+		CreateTime:         now,
+		UpdateTime:         now,
+	})
+	return err
+}
+
+func (a *importApplier) applyPosition(ctx context.Context, session sqlx.Session, record *svc.ImportRecord) error {
+	level := int64(1)
+	if v := record.Values["level"]; v != "" {
+		level, _ = parseImportInt(v, 1, 5)
+	}
+	var management, maxEmployees int64
+	if ok, _ := parseImportBool(record.Values["management"]); ok {
+		management = 1
+	}
+	if v := record.Values["maxEmployees"]; v != "" {
+		maxEmployees, _ = parseImportInt(v, 0, 100000)
+	}
+	now := time.Now()
+	_, err := a.svcCtx.TransactionHelper.GetPositionModelWithSession(session).Insert(ctx, &company.Position{
+		Id:             record.ID,
+		DepartmentId:   record.Ref("departmentId"),
+		PositionName:   record.Values["name"],
+		PositionCode:   utils.Common.ToSqlNullString(record.Values["code"]),
+		PositionLevel:  level,
+		JobDescription: utils.Common.ToSqlNullString(record.Values["description"]),
+		IsManagement:   management,
+		MaxEmployees:   maxEmployees,
+		Status:         1, // 正常状态
+		CreateTime:     now,
+		UpdateTime:     now,
+	})
+	return err
+}
+
+// applyEmployee 关联或创建用户账号后写入员工，并更新职位在岗人数
+func (a *importApplier) applyEmployee(ctx context.Context, session sqlx.Session, record *svc.ImportRecord) error {
+	userModel := a.svcCtx.TransactionHelper.GetUserModelWithSession(session)
+	userID := record.Ref("userId")
+	if newUserID := record.Ref("newUserId"); newUserID != "" {
+		passwordHash, err := randomPasswordHash()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if _, err := userModel.Insert(ctx, &user.User{
+			Id:               newUserID,
+			Username:         record.Values["email"],
+			PasswordHash:     passwordHash,
+			Email:            utils.Common.ToSqlNullString(record.Values["email"]),
+			RealName:         utils.Common.ToSqlNullString(record.Values["realName"]),
+			Status:           1, // 正常状态
+			HasJoinedCompany: 1,
+			CreateTime:       now,
+			UpdateTime:       now,
+		}); err != nil {
+			return err
+		}
+		userID = newUserID
+	} else if err := userModel.UpdateHasJoinedCompany(ctx, userID, true); err != nil {
+		return err
+	}
+
+	hireDate := sql.NullTime{Time: time.Now(), Valid: true}
+	if v := record.Values["hireDate"]; v != "" {
+		if t, err := parseImportDate(v); err == nil {
+			hireDate.Time = t
+		}
+	}
+	if _, err := a.svcCtx.TransactionHelper.GetEmployeeModelWithSession(session).Insert(ctx, &user.Employee{
+		Id:           record.ID,
+		UserId:       userID,
+		CompanyId:    a.operator.CompanyId,
+		DepartmentId: utils.Common.ToSqlNullString(record.Ref("departmentId")),
+		PositionId:   utils.Common.ToSqlNullString(record.Ref("positionId")),
+		SupervisorId: utils.Common.ToSqlNullString(record.Ref("supervisorId")),
+		EmployeeId:   record.Values["employeeId"],
+		RealName:     record.Values["realName"],
+		Email:        utils.Common.ToSqlNullString(record.Values["email"]),
+		Phone:        utils.Common.ToSqlNullString(record.Values["phone"]),
+		Skills:       utils.Common.ToSqlNullString(strings.Join(splitImportList(record.Values["skills"]), ",")),
+		HireDate:     hireDate,
+		Status:       1, // 在职
+	}); err != nil {
+		return err
+	}
+
+	if positionID := record.Ref("positionId"); positionID != "" {
+		positionModel := a.svcCtx.TransactionHelper.GetPositionModelWithSession(session)
+		pos, err := positionModel.FindOne(ctx, positionID)
+		if err != nil {
+			return err
+		}
+		return positionModel.UpdateCurrentEmployees(ctx, positionID, int(pos.CurrentEmployees)+1)
+	}
+	return nil
+}
+
+// randomPasswordHash 为导入创建的账号生成随机密码，员工通过找回密码设置自己的密码
+func randomPasswordHash() (string, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// applyTask 写入任务及其节点，任务状态、进度和节点统计由节点汇总得出
+func (a *importApplier) applyTask(ctx context.Context, session sqlx.Session, record *svc.ImportRecord) error {
+	now := time.Now()
+	start := now
+	if v := record.Values["startDate"]; v != "" {
+		start, _ = parseImportDate(v)
+	}
+	deadline, _ := parseImportDate(record.Values["deadline"])
+	priority, _ := parseImportInt(record.Values["priority"], 0, 3)
+
+	nodes := make([]*task.TaskNode, 0, len(record.Nodes))
+	employeeSet := make(map[string]bool)
+	var nodeEmployeeIDs []string
+	var completed, started, progressSum int64
+	for _, nodeRecord := range record.Nodes {
+		node := buildImportNode(record.ID, nodeRecord, start, deadline, now)
+		nodes = append(nodes, node)
+		for _, id := range splitImportList(node.ExecutorId) {
+			if !employeeSet[id] {
+				employeeSet[id] = true
+				nodeEmployeeIDs = append(nodeEmployeeIDs, id)
+			}
+		}
+		if node.NodeStatus == 2 {
+			completed++
+		}
+		if node.NodeStatus != 0 || node.Progress > 0 {
+			started++
+		}
+		progressSum += node.Progress
+	}
+
+	status, progress := int64(0), int64(0)
+	if len(nodes) > 0 {
+		progress = progressSum / int64(len(nodes))
+		switch {
+		case completed == int64(len(nodes)):
+			status, progress = 2, 100
+		case started > 0:
+			status = 1
+		}
+	}
+	taskType := int64(0)
+	departmentIDs := record.Ref("departmentIds")
+	if len(splitImportList(departmentIDs)) > 1 {
+		taskType = 1 // 跨部门任务
+	}
+	leaderID := record.Ref("leaderId")
+
+	if _, err := a.svcCtx.TransactionHelper.GetTaskModelWithSession(session).Insert(ctx, &task.Task{
+		TaskId:                 record.ID,
+		CompanyId:              a.operator.CompanyId,
+		TaskTitle:              record.Values["title"],
+		TaskDetail:             record.Values["detail"],
+		TaskStatus:             status,
+		TaskPriority:           priority,
+		TaskType:               taskType,
+		ResponsibleEmployeeIds: utils.Common.ToSqlNullString(leaderID),
+		NodeEmployeeIds:        utils.Common.ToSqlNullString(strings.Join(nodeEmployeeIDs, ",")),
+		DepartmentIds:          utils.Common.ToSqlNullString(departmentIDs),
+		TaskStartTime:          start,
+		TaskDeadline:           deadline,
+		TaskCreator:            a.operator.Id,
+		LeaderId:               utils.Common.ToSqlNullString(leaderID),
+		CreateTime:             now,
+		UpdateTime:             now,
+		TaskProgress:           progress,
+		TotalNodes:             int64(len(nodes)),
+		CompletedNodes:         completed,
+		TotalNodeCount:         int64(len(nodes)),
+		CompletedNodeCount:     completed,
+	}); err != nil {
+		return err
+	}
+
+	nodeModel := a.svcCtx.TransactionHelper.GetTaskNodeModelWithSession(session)
+	for _, node := range nodes {
+		if _, err := nodeModel.InsertTask(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	_, err := a.svcCtx.TransactionHelper.GetTaskLogModelWithSession(session).Insert(ctx, &task.TaskLog{
+		LogId:      utils.Common.GenId("task_log"),
+		TaskId:     record.ID,
+		LogType:    1, // 创建类型
+		LogContent: fmt.Sprintf("批量导入任务: %s（原任务编号 %s，%d 个节点）", record.Values["title"], record.Values["taskKey"], len(nodes)),
+		EmployeeId: a.operator.Id,
+		CreateTime: now,
+	})
+	return err
+}
+
+// buildImportNode 按校验后的节点记录组装任务节点，未填写的日期沿用任务的开始和截止日期
+func buildImportNode(taskID string, record *svc.ImportRecord, taskStart, taskDeadline, now time.Time) *task.TaskNode {
+	start, deadline := taskStart, taskDeadline
+	if v := record.Values["nodeStartDate"]; v != "" {
+		start, _ = parseImportDate(v)
+	}
+	if v := record.Values["nodeDeadline"]; v != "" {
+		deadline, _ = parseImportDate(v)
+	}
+	estimated := record.Values["estimatedDays"]
+	if estimated == "" {
+		estimated = record.Ref("estimatedDays")
+	}
+	estimatedDays, _ := parseImportInt(estimated, 0, 3650)
+
+	var status int64
+	if v := record.Values["nodeStatus"]; v != "" {
+		if s, ok := nodeStatusNames[v]; ok {
+			status = s
+		} else {
+			status, _ = parseImportInt(v, 0, 3)
+		}
+	}
+	progress, _ := parseImportInt(strings.TrimSuffix(record.Values["progress"], "%"), 0, 100)
+	finishTime := sql.NullTime{}
+	if status == 2 {
+		progress = 100
+		finishTime = sql.NullTime{Time: now, Valid: true}
+	}
+	return &task.TaskNode{
+		TaskNodeId:     record.ID,
+		TaskId:         taskID,
+		DepartmentId:   record.Ref("departmentId"),
+		NodeName:       record.Values["nodeName"],
+		ExNodeIds:      record.Ref("exNodeIds"),
+		NodeDeadline:   deadline,
+		NodeStartTime:  start,
+		EstimatedDays:  estimatedDays,
+		NodeStatus:     status,
+		NodeFinishTime: finishTime,
+		ExecutorId:     record.Ref("executorIds"),
+		LeaderId:       record.Ref("leaderId"),
+		Progress:       progress,
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+}
+
+// Finish 导入任务后更新搜索索引，导入员工后给新创建账号的员工发送开通邮件
+func (a *importApplier) Finish(ctx context.Context, records []*svc.ImportRecord) {
+	if a.importType == svc.ImportTypeTask {
+		a.publishSearchEvents(ctx, records)
+		return
+	}
+	if a.importType != svc.ImportTypeEmployee || a.svcCtx.EmailMQService == nil {
+		return
+	}
+	companyName := ""
+	if c, err := a.svcCtx.CompanyModel.FindOne(ctx, a.operator.CompanyId); err == nil {
+		companyName = c.Name
+	}
+	for _, record := range records {
+		if record.Ref("newUserId") == "" {
+			continue
+		}
+		event := &svc.EmailEvent{
+			EventType: svc.EmployeeCreated,
+			To:        []string{record.Values["email"]},
+			Subject:   "账号开通通知",
+			Body: fmt.Sprintf("%s，您好：\n\n%s 已为您开通企业任务系统账号，登录用户名为 %s。请在登录页使用“忘记密码”通过邮箱验证码设置登录密码。",
+				record.Values["realName"], companyName, record.Values["email"]),
+			EmployeeID: record.ID,
+		}
+		if err := a.svcCtx.EmailMQService.PublishEmailEvent(ctx, event); err != nil {
+			logx.WithContext(ctx).Errorf("[Import] 发布账号开通邮件失败: employeeId=%s, err=%v", record.ID, err)
+		}
+	}
+}
+
+// publishSearchEvents 导入的任务及其节点加入搜索索引
+func (a *importApplier) publishSearchEvents(ctx context.Context, records []*svc.ImportRecord) {
+	if a.svcCtx.SearchService == nil {
+		return
+	}
+	for _, record := range records {
+		a.svcCtx.SearchService.Publish(svc.SearchDocTask, record.ID)
+		nodes, err := a.svcCtx.TaskNodeModel.FindByTaskID(ctx, record.ID)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("[Import] 查询导入任务的节点失败: taskId=%s, err=%v", record.ID, err)
+			continue
+		}
+		for _, node := range nodes {
+			a.svcCtx.SearchService.Publish(svc.SearchDocNode, node.TaskNodeId)
+		}
+	}
+}
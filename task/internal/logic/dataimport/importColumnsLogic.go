@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ImportColumnsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 导入文件的列说明
+func NewImportColumnsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ImportColumnsLogic {
+	return &ImportColumnsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ImportColumnsLogic) ImportColumns(req *types.ImportColumnsRequest) (resp *types.BaseResponse, err error) {
+	columns, ok := importColumns[req.ImportType]
+	if !ok {
+		return utils.Response.BusinessError("import_type_invalid"), nil
+	}
+	list := make([]types.ImportColumnInfo, 0, len(columns))
+	for _, col := range columns {
+		list = append(list, types.ImportColumnInfo{
+			Key:         col.Key,
+			Title:       col.Title,
+			Required:    col.Required,
+			Description: col.Description,
+		})
+	}
+	return utils.Response.Success(list), nil
+}
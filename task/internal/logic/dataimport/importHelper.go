@@ -0,0 +1,347 @@
+package dataimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// maxReportErrors 校验报告中最多返回的错误条数
+const maxReportErrors = 500
+
+// importColumn 导入文件的一列
+type importColumn struct {
+	Key         string
+	Title       string
+	Required    bool
+	Aliases     []string
+	Description string
+}
+
+// importColumns 各导入内容的列定义，表头可以使用中文名称、key 或别名，未识别的列会被忽略
+var importColumns = map[string][]importColumn{
+	svc.ImportTypeDepartment: {
+		{Key: "name", Title: "部门名称", Required: true, Aliases: []string{"部门"}},
+		{Key: "code", Title: "部门编码", Aliases: []string{"编码"}},
+		{Key: "parent", Title: "上级部门", Description: "上级部门的名称或编码，可以是本文件中的部门"},
+		{Key: "manager", Title: "部门经理工号", Description: "已在系统中的员工工号，可在导入员工后再设置"},
+		{Key: "priority", Title: "部门优先级", Description: "0-6，默认 0"},
+		{Key: "description", Title: "部门描述", Aliases: []string{"描述"}},
+	},
+	svc.ImportTypePosition: {
+		{Key: "department", Title: "所属部门", Required: true, Aliases: []string{"部门"}, Description: "部门名称或编码"},
+		{Key: "name", Title: "职位名称", Required: true, Aliases: []string{"职位"}},
+		{Key: "code", Title: "职位编码"},
+		{Key: "level", Title: "职位级别", Description: "1-初级 2-中级 3-高级 4-专家 5-资深专家，默认 1"},
+		{Key: "management", Title: "是否管理岗", Description: "是/否"},
+		{Key: "maxEmployees", Title: "最大人数", Description: "0 表示不限制"},
+		{Key: "description", Title: "职位描述", Aliases: []string{"描述"}},
+	},
+	svc.ImportTypeEmployee: {
+		{Key: "employeeId", Title: "工号", Required: true, Aliases: []string{"员工编号"}},
+		{Key: "realName", Title: "姓名", Required: true, Aliases: []string{"真实姓名"}},
+		{Key: "email", Title: "邮箱", Required: true, Aliases: []string{"工作邮箱"}, Description: "已注册的用户按邮箱关联，未注册的会自动创建账号"},
+		{Key: "phone", Title: "电话", Aliases: []string{"手机号", "工作电话"}},
+		{Key: "department", Title: "部门", Description: "部门名称或编码"},
+		{Key: "position", Title: "职位", Description: "职位名称，同名职位较多时需要同时填写部门"},
+		{Key: "supervisor", Title: "直属上级工号", Aliases: []string{"上级工号"}, Description: "本文件或系统中的员工工号"},
+		{Key: "hireDate", Title: "入职日期", Description: "如 2024-03-01"},
+		{Key: "skills", Title: "技能标签", Aliases: []string{"技能"}, Description: "多个技能用逗号分隔"},
+	},
+	svc.ImportTypeTask: {
+		{Key: "taskKey", Title: "任务编号", Required: true, Description: "原系统中的任务编号，同一编号的多行属于同一任务"},
+		{Key: "title", Title: "任务标题", Required: true},
+		{Key: "detail", Title: "任务详情"},
+		{Key: "priority", Title: "优先级", Description: "0-不重要不紧急 1-紧急不重要 2-重要不紧急 3-重要且紧急，默认 0"},
+		{Key: "startDate", Title: "开始日期", Description: "默认导入当天"},
+		{Key: "deadline", Title: "截止日期", Required: true},
+		{Key: "leader", Title: "负责人工号", Description: "默认为导入人"},
+		{Key: "departments", Title: "涉及部门", Description: "部门名称或编码，多个用逗号分隔"},
+		{Key: "nodeName", Title: "节点名称", Description: "为空时该行只描述任务本身"},
+		{Key: "nodeDepartment", Title: "节点部门", Description: "默认为任务的第一个涉及部门"},
+		{Key: "executors", Title: "执行人工号", Description: "多个用逗号分隔"},
+		{Key: "nodeLeader", Title: "节点负责人工号", Description: "默认为任务负责人"},
+		{Key: "nodeStartDate", Title: "节点开始日期", Description: "默认为任务开始日期"},
+		{Key: "nodeDeadline", Title: "节点截止日期", Description: "默认为任务截止日期"},
+		{Key: "estimatedDays", Title: "预计天数"},
+		{Key: "nodeStatus", Title: "节点状态", Description: "未开始/进行中/已完成/已逾期 或 0-3"},
+		{Key: "progress", Title: "节点进度", Description: "0-100"},
+		{Key: "predecessors", Title: "前置节点", Description: "同一任务中需要先完成的节点名称，多个用逗号分隔"},
+	},
+}
+
+// checkImportPermission 部门、职位、员工导入需要管理权限，任务导入所有员工都可以发起
+func checkImportPermission(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, importType string) *types.BaseResponse {
+	if svc.ImportTypeTitle(importType) == "" {
+		return utils.Response.BusinessError("import_type_invalid")
+	}
+	if importType != svc.ImportTypeTask && !svcCtx.IsCompanyAdmin(ctx, employee) {
+		return utils.Response.BusinessError("import_no_permission")
+	}
+	return nil
+}
+
+// loadImportJob 查询导入任务：同公司的发起人或管理人员可以查看
+func loadImportJob(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, jobID string) (*task.ImportJob, *types.BaseResponse) {
+	job, err := svcCtx.ImportJobModel.FindOne(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, utils.Response.BusinessError("import_not_found")
+		}
+		return nil, utils.Response.InternalError("查询导入记录失败")
+	}
+	if job.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("import_not_found")
+	}
+	if job.EmployeeId != employee.Id && !svcCtx.IsCompanyAdmin(ctx, employee) {
+		return nil, utils.Response.BusinessError("import_not_found")
+	}
+	return job, nil
+}
+
+// buildImportRecords 按表头把数据行整理为记录；缺少必填列时返回错误
+func buildImportRecords(importType string, rows [][]string, maxRows int) ([]*svc.ImportRecord, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("导入文件为空")
+	}
+	columns := importColumns[importType]
+	keys := make([]string, len(rows[0]))
+	found := make(map[string]bool)
+	for i, title := range rows[0] {
+		if col := matchImportColumn(columns, title); col != nil && !found[col.Key] {
+			keys[i] = col.Key
+			found[col.Key] = true
+		}
+	}
+	var missing []string
+	for _, col := range columns {
+		if col.Required && !found[col.Key] {
+			missing = append(missing, col.Title)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("缺少必填列：%s", strings.Join(missing, "、"))
+	}
+
+	records := make([]*svc.ImportRecord, 0, len(rows)-1)
+	dataRows := 0
+	for i, row := range rows[1:] {
+		values := make(map[string]string)
+		for j, cell := range row {
+			if j < len(keys) && keys[j] != "" {
+				if cell = strings.TrimSpace(cell); cell != "" {
+					values[keys[j]] = cell
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if dataRows++; dataRows > maxRows {
+			return nil, fmt.Errorf("导入文件超过 %d 行数据，请拆分后分批导入", maxRows)
+		}
+		records = append(records, &svc.ImportRecord{Line: i + 2, Values: values})
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("导入文件中没有数据行")
+	}
+	if importType == svc.ImportTypeTask {
+		records = groupTaskRecords(records)
+	}
+	for _, record := range records {
+		assignRecordIDs(importType, record)
+	}
+	return records, nil
+}
+
+// matchImportColumn 表头匹配：忽略大小写、必填标记（*）和括号中的说明
+func matchImportColumn(columns []importColumn, title string) *importColumn {
+	title = strings.TrimSpace(strings.TrimPrefix(title, "\ufeff"))
+	for _, sep := range []string{"(", "（"} {
+		if idx := strings.Index(title, sep); idx > 0 {
+			title = title[:idx]
+		}
+	}
+	title = strings.ToLower(strings.Trim(strings.TrimSpace(title), "*"))
+	if title == "" {
+		return nil
+	}
+	for i := range columns {
+		col := &columns[i]
+		if title == strings.ToLower(col.Title) || title == strings.ToLower(col.Key) {
+			return col
+		}
+		for _, alias := range col.Aliases {
+			if title == strings.ToLower(alias) {
+				return col
+			}
+		}
+	}
+	return nil
+}
+
+// groupTaskRecords 同一任务编号的多行合并为一个任务，填写了节点名称的行作为该任务的节点
+func groupTaskRecords(rows []*svc.ImportRecord) []*svc.ImportRecord {
+	tasks := make([]*svc.ImportRecord, 0)
+	byKey := make(map[string]*svc.ImportRecord)
+	for _, row := range rows {
+		key := row.Values["taskKey"]
+		taskRecord, ok := byKey[key]
+		if !ok || key == "" {
+			taskRecord = &svc.ImportRecord{Line: row.Line, Values: make(map[string]string)}
+			tasks = append(tasks, taskRecord)
+			if key != "" {
+				byKey[key] = taskRecord
+			}
+		}
+		for k, v := range row.Values {
+			if !strings.HasPrefix(k, "node") && k != "executors" && k != "estimatedDays" && k != "progress" && k != "predecessors" {
+				if _, exists := taskRecord.Values[k]; !exists {
+					taskRecord.Values[k] = v
+				}
+			}
+		}
+		if row.Values["nodeName"] != "" {
+			taskRecord.Nodes = append(taskRecord.Nodes, row)
+		}
+	}
+	return tasks
+}
+
+// importIDPrefixes 各导入内容的记录ID前缀；批量写入时按时间生成的ID会重复，统一使用随机ID
+var importIDPrefixes = map[string]string{
+	svc.ImportTypeDepartment: "dept",
+	svc.ImportTypePosition:   "pos",
+	svc.ImportTypeEmployee:   "emp",
+	svc.ImportTypeTask:       "task",
+}
+
+// assignRecordIDs 预先分配记录ID，继续导入时沿用，保证同一文件中的相互引用有效
+func assignRecordIDs(importType string, record *svc.ImportRecord) {
+	if record.ID == "" {
+		record.ID = utils.Common.GenId(importIDPrefixes[importType])
+	}
+	for _, node := range record.Nodes {
+		if node.ID == "" {
+			node.ID = utils.Common.GenId("node")
+		}
+	}
+}
+
+// countImportErrors 统计记录的校验错误数
+func countImportErrors(records []*svc.ImportRecord) int {
+	count := 0
+	for _, record := range records {
+		count += record.ErrorCount()
+	}
+	return count
+}
+
+// toImportJobInfo 转换导入任务
+func toImportJobInfo(job *task.ImportJob) types.ImportJobInfo {
+	info := types.ImportJobInfo{
+		ID:             job.Id,
+		ImportType:     job.ImportType,
+		ImportTypeName: svc.ImportTypeTitle(job.ImportType),
+		FileName:       job.FileName,
+		Status:         job.Status,
+		TotalRows:      job.TotalRows,
+		ErrorCount:     job.ErrorCount,
+		AppliedRows:    job.AppliedRows,
+		EmployeeID:     job.EmployeeId,
+		ErrorMessage:   job.ErrorMessage.String,
+		CreateTime:     utils.Common.FormatTime(job.CreateTime),
+	}
+	if job.FinishTime.Valid {
+		info.FinishTime = utils.Common.FormatTime(job.FinishTime.Time)
+	}
+	return info
+}
+
+// toImportReport 组装导入任务及按行号排列的校验错误
+func toImportReport(job *task.ImportJob, records []*svc.ImportRecord) types.ImportReport {
+	report := types.ImportReport{Job: toImportJobInfo(job), Errors: make([]types.ImportRowError, 0)}
+	add := func(record *svc.ImportRecord) {
+		for _, message := range record.Errors {
+			if len(report.Errors) >= maxReportErrors {
+				report.Truncated = true
+				return
+			}
+			report.Errors = append(report.Errors, types.ImportRowError{Line: record.Line, Message: message})
+		}
+	}
+	for _, record := range records {
+		add(record)
+		for _, node := range record.Nodes {
+			add(node)
+		}
+	}
+	return report
+}
+
+// splitImportList 拆分逗号、顿号、分号分隔的多个值
+func splitImportList(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == ';' || r == '；' || r == '\n'
+	})
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// parseImportDate 解析日期，支持常见的日期写法和 Excel 的日期序列号
+func parseImportDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "2006/01/02", "2006-1-2", "2006/1/2", "2006.01.02", "2006年1月2日", "2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006-01-02 15:04", "2006/1/2 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	// XLSX 中未设置为文本的日期单元格保存为从 1899-12-30 起的天数
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 && serial < 2958466 {
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.Local)
+		return base.Add(time.Duration(serial * 24 * float64(time.Hour))).Truncate(time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("日期格式不正确")
+}
+
+// parseImportInt 解析整数（兼容 Excel 导出的 "3.0"），超出范围时返回错误
+func parseImportInt(value string, min, max int64) (int64, error) {
+	value = strings.TrimSpace(value)
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(value, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return 0, fmt.Errorf("应为整数")
+		}
+		n = int64(f)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("应在 %d-%d 之间", min, max)
+	}
+	return n, nil
+}
+
+// parseImportBool 解析是/否
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "是", "y", "yes", "true", "1":
+		return true, nil
+	case "否", "n", "no", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("应为 是 或 否")
+}
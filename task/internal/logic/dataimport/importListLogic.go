@@ -0,0 +1,57 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ImportListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 公司导入记录
+func NewImportListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ImportListLogic {
+	return &ImportListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ImportListLogic) ImportList(req *types.ImportListRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	validator := utils.NewValidator()
+	page, pageSize, errs := validator.ValidatePageParams(req.Page, req.PageSize)
+	if len(errs) > 0 {
+		return utils.Response.ValidationError(errs[0]), nil
+	}
+
+	// 管理人员可以看到公司全部导入记录，其他员工只看到自己发起的
+	employeeID := operator.Id
+	if l.svcCtx.IsCompanyAdmin(l.ctx, operator) {
+		employeeID = ""
+	}
+	jobs, total, err := l.svcCtx.ImportJobModel.FindByCompany(l.ctx, operator.CompanyId, employeeID, page, pageSize)
+	if err != nil {
+		l.Logger.Errorf("查询导入记录失败: %v", err)
+		return utils.Response.InternalError("查询导入记录失败"), nil
+	}
+	list := make([]types.ImportJobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, toImportJobInfo(job))
+	}
+	return utils.Response.Success(utils.NewConverter().ToPageResponse(list, int(total), page, pageSize)), nil
+}
@@ -0,0 +1,710 @@
+package dataimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"task_Project/model/company"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/utils"
+)
+
+// orgIndex 公司现有的部门、职位和员工，校验时按名称、编码、工号查找
+type orgIndex struct {
+	companyID       string
+	deptByCode      map[string]*company.Department
+	deptByName      map[string][]*company.Department
+	positionsByDept map[string][]*company.Position
+	positions       []*company.Position
+	employeesByNo   map[string]*user.Employee
+	employeeEmails  map[string]bool
+}
+
+func loadOrgIndex(ctx context.Context, svcCtx *svc.ServiceContext, companyID string) (*orgIndex, error) {
+	idx := &orgIndex{
+		companyID:       companyID,
+		deptByCode:      make(map[string]*company.Department),
+		deptByName:      make(map[string][]*company.Department),
+		positionsByDept: make(map[string][]*company.Position),
+		employeesByNo:   make(map[string]*user.Employee),
+		employeeEmails:  make(map[string]bool),
+	}
+	departments, err := svcCtx.DepartmentModel.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dept := range departments {
+		if dept.DepartmentCode.Valid && dept.DepartmentCode.String != "" {
+			idx.deptByCode[strings.ToLower(dept.DepartmentCode.String)] = dept
+		}
+		name := strings.ToLower(dept.DepartmentName)
+		idx.deptByName[name] = append(idx.deptByName[name], dept)
+		positions, err := svcCtx.PositionModel.FindByDepartmentID(ctx, dept.Id)
+		if err != nil {
+			return nil, err
+		}
+		idx.positionsByDept[dept.Id] = positions
+		idx.positions = append(idx.positions, positions...)
+	}
+	employees, err := svcCtx.EmployeeModel.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, emp := range employees {
+		idx.employeesByNo[emp.EmployeeId] = emp
+		if emp.Email.Valid && emp.Email.String != "" {
+			idx.employeeEmails[strings.ToLower(emp.Email.String)] = true
+		}
+	}
+	return idx, nil
+}
+
+// findDepartment 按编码或名称查找现有部门，名称重复时要求填写编码
+func (idx *orgIndex) findDepartment(ref string) (*company.Department, string) {
+	key := strings.ToLower(ref)
+	if dept, ok := idx.deptByCode[key]; ok {
+		return dept, ""
+	}
+	switch depts := idx.deptByName[key]; len(depts) {
+	case 0:
+		return nil, fmt.Sprintf("部门 %s 不存在", ref)
+	case 1:
+		return depts[0], ""
+	default:
+		return nil, fmt.Sprintf("存在多个名为 %s 的部门，请填写部门编码", ref)
+	}
+}
+
+// findActiveEmployee 按工号查找未离职的员工
+func (idx *orgIndex) findActiveEmployee(employeeNo string) (*user.Employee, string) {
+	emp, ok := idx.employeesByNo[employeeNo]
+	if !ok {
+		return nil, fmt.Sprintf("工号 %s 的员工不存在", employeeNo)
+	}
+	if emp.Status == 0 {
+		return nil, fmt.Sprintf("工号 %s 的员工已离职", employeeNo)
+	}
+	return emp, ""
+}
+
+// validateImportRecords 校验第 from 条及之后的记录（之前的记录已经写入，只作为文件内引用的上下文），
+// 首次校验（from 为 0）时按依赖关系排序，保证上级部门、直属上级先于下级写入
+func validateImportRecords(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, importType string, records []*svc.ImportRecord, from int) ([]*svc.ImportRecord, error) {
+	idx, err := loadOrgIndex(ctx, svcCtx, operator.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records[from:] {
+		record.Errors = nil
+		if importType != svc.ImportTypeEmployee {
+			record.Refs = nil
+		}
+		for _, node := range record.Nodes {
+			node.Errors = nil
+		}
+	}
+	switch importType {
+	case svc.ImportTypeDepartment:
+		return validateDepartments(idx, records, from), nil
+	case svc.ImportTypePosition:
+		validatePositions(idx, records, from)
+		return records, nil
+	case svc.ImportTypeEmployee:
+		return validateEmployees(ctx, svcCtx, idx, records, from)
+	case svc.ImportTypeTask:
+		validateTasks(idx, operator, records, from)
+		return records, nil
+	}
+	return records, nil
+}
+
+// validateDepartments 校验部门：名称和编码不重复，上级部门存在且不形成循环
+func validateDepartments(idx *orgIndex, records []*svc.ImportRecord, from int) []*svc.ImportRecord {
+	byName := make(map[string]*svc.ImportRecord)
+	byCode := make(map[string]*svc.ImportRecord)
+	for i, record := range records {
+		check := i >= from
+		name := strings.ToLower(record.Values["name"])
+		if name == "" {
+			if check {
+				record.AddError("部门名称不能为空")
+			}
+		} else if prev, ok := byName[name]; ok {
+			if check {
+				record.AddError("部门名称与第 %d 行重复", prev.Line)
+			}
+		} else {
+			byName[name] = record
+			if _, exists := idx.deptByName[name]; exists && check {
+				record.AddError("部门 %s 已存在", record.Values["name"])
+			}
+		}
+		if code := strings.ToLower(record.Values["code"]); code != "" {
+			if prev, ok := byCode[code]; ok {
+				if check {
+					record.AddError("部门编码与第 %d 行重复", prev.Line)
+				}
+			} else {
+				byCode[code] = record
+				if _, exists := idx.deptByCode[code]; exists && check {
+					record.AddError("部门编码 %s 已被使用", record.Values["code"])
+				}
+			}
+		}
+	}
+
+	parents := make(map[*svc.ImportRecord]*svc.ImportRecord)
+	for i, record := range records {
+		ref := record.Values["parent"]
+		if ref == "" {
+			continue
+		}
+		key := strings.ToLower(ref)
+		parent, ok := byCode[key]
+		if !ok {
+			parent, ok = byName[key]
+		}
+		if ok {
+			parents[record] = parent
+			if i < from {
+				continue
+			}
+			if parent == record {
+				record.AddError("上级部门不能是部门本身")
+			} else {
+				record.SetRef("parentId", parent.ID)
+			}
+			continue
+		}
+		if i < from {
+			continue
+		}
+		if dept, msg := idx.findDepartment(ref); dept != nil {
+			record.SetRef("parentId", dept.Id)
+		} else {
+			record.AddError("上级%s", msg)
+		}
+	}
+
+	for _, record := range records[from:] {
+		if v := record.Values["priority"]; v != "" {
+			if _, err := parseImportInt(v, 0, 6); err != nil {
+				record.AddError("部门优先级%s", err.Error())
+			}
+		}
+		if no := record.Values["manager"]; no != "" {
+			if emp, msg := idx.findActiveEmployee(no); emp != nil {
+				record.SetRef("managerId", emp.Id)
+			} else {
+				record.AddError("部门经理%s", msg)
+			}
+		}
+	}
+
+	ordered, cycles := orderByParent(records, parents)
+	for _, cycle := range cycles {
+		names := make([]string, 0, len(cycle)+1)
+		for _, r := range cycle {
+			names = append(names, r.Values["name"])
+		}
+		names = append(names, cycle[0].Values["name"])
+		for _, r := range cycle {
+			r.AddError("上级部门形成循环：%s", strings.Join(names, " → "))
+		}
+	}
+	if from == 0 {
+		return ordered
+	}
+	return records
+}
+
+// validatePositions 校验职位：所属部门存在，同一部门内职位名称不重复
+func validatePositions(idx *orgIndex, records []*svc.ImportRecord, from int) {
+	seen := make(map[string]*svc.ImportRecord)
+	for i, record := range records {
+		check := i >= from
+		var dept *company.Department
+		if ref := record.Values["department"]; ref == "" {
+			if check {
+				record.AddError("所属部门不能为空")
+			}
+		} else {
+			var msg string
+			if dept, msg = idx.findDepartment(ref); dept != nil {
+				if check {
+					record.SetRef("departmentId", dept.Id)
+				}
+			} else if check {
+				record.AddError("所属%s", msg)
+			}
+		}
+		name := record.Values["name"]
+		if name == "" {
+			if check {
+				record.AddError("职位名称不能为空")
+			}
+		} else if dept != nil {
+			key := dept.Id + "/" + strings.ToLower(name)
+			if prev, ok := seen[key]; ok {
+				if check {
+					record.AddError("职位与第 %d 行重复", prev.Line)
+				}
+			} else {
+				seen[key] = record
+				for _, pos := range idx.positionsByDept[dept.Id] {
+					if check && strings.EqualFold(pos.PositionName, name) {
+						record.AddError("部门 %s 已有职位 %s", dept.DepartmentName, name)
+						break
+					}
+				}
+			}
+		}
+		if !check {
+			continue
+		}
+		if v := record.Values["level"]; v != "" {
+			if _, err := parseImportInt(v, 1, 5); err != nil {
+				record.AddError("职位级别%s", err.Error())
+			}
+		}
+		if v := record.Values["management"]; v != "" {
+			if _, err := parseImportBool(v); err != nil {
+				record.AddError("是否管理岗%s", err.Error())
+			}
+		}
+		if v := record.Values["maxEmployees"]; v != "" {
+			if _, err := parseImportInt(v, 0, 100000); err != nil {
+				record.AddError("最大人数%s", err.Error())
+			}
+		}
+	}
+}
+
+// validateEmployees 校验员工：工号、邮箱不重复，部门和职位存在，直属上级存在且不形成循环
+func validateEmployees(ctx context.Context, svcCtx *svc.ServiceContext, idx *orgIndex, records []*svc.ImportRecord, from int) ([]*svc.ImportRecord, error) {
+	byNo := make(map[string]*svc.ImportRecord)
+	byEmail := make(map[string]*svc.ImportRecord)
+	for i, record := range records {
+		check := i >= from
+		if no := record.Values["employeeId"]; no == "" {
+			if check {
+				record.AddError("工号不能为空")
+			}
+		} else if prev, ok := byNo[no]; ok {
+			if check {
+				record.AddError("工号与第 %d 行重复", prev.Line)
+			}
+		} else {
+			byNo[no] = record
+		}
+		if email := strings.ToLower(record.Values["email"]); email != "" {
+			if prev, ok := byEmail[email]; ok {
+				if check {
+					record.AddError("邮箱与第 %d 行重复", prev.Line)
+				}
+			} else {
+				byEmail[email] = record
+			}
+		}
+	}
+
+	positionCounts := make(map[string]int64)
+	parents := make(map[*svc.ImportRecord]*svc.ImportRecord)
+	for i, record := range records {
+		if ref := record.Values["supervisor"]; ref != "" {
+			if parent, ok := byNo[ref]; ok && parent != record {
+				parents[record] = parent
+			}
+		}
+		if i < from {
+			continue
+		}
+		if err := validateEmployee(ctx, svcCtx, idx, record, byNo, positionCounts); err != nil {
+			return nil, err
+		}
+	}
+
+	ordered, cycles := orderByParent(records, parents)
+	for _, cycle := range cycles {
+		names := make([]string, 0, len(cycle)+1)
+		for _, r := range cycle {
+			names = append(names, r.Values["realName"]+"("+r.Values["employeeId"]+")")
+		}
+		names = append(names, names[0])
+		for _, r := range cycle {
+			r.AddError("直属上级形成循环：%s", strings.Join(names, " → "))
+		}
+	}
+	if from == 0 {
+		return ordered, nil
+	}
+	return records, nil
+}
+
+func validateEmployee(ctx context.Context, svcCtx *svc.ServiceContext, idx *orgIndex, record *svc.ImportRecord,
+	byNo map[string]*svc.ImportRecord, positionCounts map[string]int64) error {
+	// 新建账号的用户ID在继续导入时保持不变
+	newUserID := record.Ref("newUserId")
+	record.Refs = nil
+
+	no := record.Values["employeeId"]
+	if no != "" {
+		if _, err := svcCtx.EmployeeModel.FindByEmployeeID(ctx, no); err == nil {
+			record.AddError("工号 %s 已被使用", no)
+		} else if !errors.Is(err, user.ErrNotFound) {
+			return err
+		}
+	}
+	if record.Values["realName"] == "" {
+		record.AddError("姓名不能为空")
+	}
+
+	email := record.Values["email"]
+	switch {
+	case email == "":
+		record.AddError("邮箱不能为空")
+	case utils.Validator.ValidateEmail(email) != "":
+		record.AddError("邮箱 %s 格式不正确", email)
+	case idx.employeeEmails[strings.ToLower(email)]:
+		record.AddError("邮箱 %s 已被本公司员工使用", email)
+	default:
+		account, err := svcCtx.UserModel.FindByEmail(ctx, email)
+		switch {
+		case err == nil:
+			if _, err := svcCtx.EmployeeModel.FindByUserIDAndCompanyID(ctx, account.Id, idx.companyID); err == nil {
+				record.AddError("邮箱 %s 对应的用户已是本公司员工", email)
+			} else if !errors.Is(err, user.ErrNotFound) {
+				return err
+			} else {
+				record.SetRef("userId", account.Id)
+			}
+		case errors.Is(err, user.ErrNotFound):
+			if _, err := svcCtx.UserModel.FindByUsername(ctx, email); err == nil {
+				record.AddError("用户名 %s 已被其他账号占用，请让员工先注册后再导入", email)
+			} else if !errors.Is(err, user.ErrNotFound) {
+				return err
+			}
+			if newUserID == "" {
+				newUserID = utils.Common.GenId("user")
+			}
+			record.SetRef("newUserId", newUserID)
+		default:
+			return err
+		}
+	}
+	if phone := record.Values["phone"]; phone != "" && utils.Validator.ValidatePhone(phone) != "" {
+		record.AddError("电话 %s 格式不正确", phone)
+	}
+	if v := record.Values["hireDate"]; v != "" {
+		if _, err := parseImportDate(v); err != nil {
+			record.AddError("入职日期%s", err.Error())
+		}
+	}
+
+	var dept *company.Department
+	if ref := record.Values["department"]; ref != "" {
+		var msg string
+		if dept, msg = idx.findDepartment(ref); dept == nil {
+			record.AddError("%s", msg)
+		}
+	}
+	if name := record.Values["position"]; name != "" {
+		var candidates []*company.Position
+		if dept != nil {
+			candidates = idx.positionsByDept[dept.Id]
+		} else if record.Values["department"] == "" {
+			candidates = idx.positions
+		}
+		var matched []*company.Position
+		for _, pos := range candidates {
+			if strings.EqualFold(pos.PositionName, name) {
+				matched = append(matched, pos)
+			}
+		}
+		switch {
+		case len(matched) == 0 && (dept != nil || record.Values["department"] == ""):
+			record.AddError("职位 %s 不存在", name)
+		case len(matched) > 1:
+			record.AddError("存在多个名为 %s 的职位，请同时填写部门", name)
+		case len(matched) == 1:
+			pos := matched[0]
+			positionCounts[pos.Id]++
+			if pos.MaxEmployees > 0 && pos.CurrentEmployees+positionCounts[pos.Id] > pos.MaxEmployees {
+				record.AddError("职位 %s 已满员（最多 %d 人）", pos.PositionName, pos.MaxEmployees)
+			}
+			record.SetRef("positionId", pos.Id)
+			if dept == nil {
+				record.SetRef("departmentId", pos.DepartmentId)
+			}
+		}
+	}
+	if dept != nil {
+		record.SetRef("departmentId", dept.Id)
+	}
+
+	if ref := record.Values["supervisor"]; ref != "" {
+		if ref == no {
+			record.AddError("直属上级不能是本人")
+		} else if parent, ok := byNo[ref]; ok {
+			record.SetRef("supervisorId", parent.ID)
+		} else if emp, msg := idx.findActiveEmployee(ref); emp != nil {
+			record.SetRef("supervisorId", emp.Id)
+		} else {
+			record.AddError("直属上级%s", msg)
+		}
+	}
+	return nil
+}
+
+// 节点状态的中文写法
+var nodeStatusNames = map[string]int64{"未开始": 0, "进行中": 1, "已完成": 2, "已逾期": 3}
+
+// validateTasks 校验任务及节点：必填项、日期、人员和部门存在，前置节点存在且不形成循环
+func validateTasks(idx *orgIndex, operator *user.Employee, records []*svc.ImportRecord, from int) {
+	for _, record := range records[from:] {
+		record.Refs = nil
+		if record.Values["taskKey"] == "" {
+			record.AddError("任务编号不能为空")
+		}
+		if record.Values["title"] == "" {
+			record.AddError("任务标题不能为空")
+		}
+		if v := record.Values["priority"]; v != "" {
+			if _, err := parseImportInt(v, 0, 3); err != nil {
+				record.AddError("优先级%s", err.Error())
+			}
+		}
+		start := time.Now()
+		if v := record.Values["startDate"]; v != "" {
+			t, err := parseImportDate(v)
+			if err != nil {
+				record.AddError("开始日期%s", err.Error())
+			} else {
+				start = t
+			}
+		}
+		var deadline time.Time
+		if v := record.Values["deadline"]; v == "" {
+			record.AddError("截止日期不能为空")
+		} else if t, err := parseImportDate(v); err != nil {
+			record.AddError("截止日期%s", err.Error())
+		} else if t.Before(start) && record.Values["startDate"] != "" {
+			record.AddError("截止日期不能早于开始日期")
+		} else {
+			deadline = t
+		}
+
+		leaderID := operator.Id
+		if no := record.Values["leader"]; no != "" {
+			if emp, msg := idx.findActiveEmployee(no); emp != nil {
+				leaderID = emp.Id
+			} else {
+				record.AddError("负责人%s", msg)
+			}
+		}
+		record.SetRef("leaderId", leaderID)
+
+		var departmentIDs []string
+		for _, ref := range splitImportList(record.Values["departments"]) {
+			if dept, msg := idx.findDepartment(ref); dept != nil {
+				departmentIDs = append(departmentIDs, dept.Id)
+			} else {
+				record.AddError("涉及%s", msg)
+			}
+		}
+		record.SetRef("departmentIds", strings.Join(departmentIDs, ","))
+
+		validateTaskNodes(idx, record, leaderID, departmentIDs, start, deadline)
+	}
+}
+
+func validateTaskNodes(idx *orgIndex, record *svc.ImportRecord, leaderID string, departmentIDs []string, taskStart, taskDeadline time.Time) {
+	byName := make(map[string]*svc.ImportRecord)
+	for _, node := range record.Nodes {
+		node.Refs = nil
+		name := strings.ToLower(node.Values["nodeName"])
+		if prev, ok := byName[name]; ok {
+			node.AddError("节点名称与第 %d 行重复", prev.Line)
+		} else {
+			byName[name] = node
+		}
+
+		if ref := node.Values["nodeDepartment"]; ref != "" {
+			if dept, msg := idx.findDepartment(ref); dept != nil {
+				node.SetRef("departmentId", dept.Id)
+			} else {
+				node.AddError("节点%s", msg)
+			}
+		} else if len(departmentIDs) > 0 {
+			node.SetRef("departmentId", departmentIDs[0])
+		} else {
+			node.AddError("节点部门不能为空（任务未填写涉及部门）")
+		}
+
+		var executorIDs []string
+		for _, no := range splitImportList(node.Values["executors"]) {
+			if emp, msg := idx.findActiveEmployee(no); emp != nil {
+				executorIDs = append(executorIDs, emp.Id)
+			} else {
+				node.AddError("执行人%s", msg)
+			}
+		}
+		node.SetRef("executorIds", strings.Join(executorIDs, ","))
+
+		nodeLeader := leaderID
+		if no := node.Values["nodeLeader"]; no != "" {
+			if emp, msg := idx.findActiveEmployee(no); emp != nil {
+				nodeLeader = emp.Id
+			} else {
+				node.AddError("节点负责人%s", msg)
+			}
+		}
+		node.SetRef("leaderId", nodeLeader)
+
+		start, deadline := taskStart, taskDeadline
+		if v := node.Values["nodeStartDate"]; v != "" {
+			if t, err := parseImportDate(v); err != nil {
+				node.AddError("节点开始日期%s", err.Error())
+			} else {
+				start = t
+			}
+		}
+		if v := node.Values["nodeDeadline"]; v != "" {
+			if t, err := parseImportDate(v); err != nil {
+				node.AddError("节点截止日期%s", err.Error())
+			} else {
+				deadline = t
+			}
+		}
+		if !deadline.IsZero() && deadline.Before(start) {
+			node.AddError("节点截止日期不能早于开始日期")
+		}
+
+		if v := node.Values["estimatedDays"]; v != "" {
+			if _, err := parseImportInt(v, 0, 3650); err != nil {
+				node.AddError("预计天数%s", err.Error())
+			}
+		} else if !deadline.IsZero() {
+			days := int64(math.Ceil(deadline.Sub(start).Hours() / 24))
+			if days < 1 {
+				days = 1
+			}
+			node.SetRef("estimatedDays", fmt.Sprintf("%d", days))
+		}
+		if v := node.Values["nodeStatus"]; v != "" {
+			if _, ok := nodeStatusNames[v]; !ok {
+				if _, err := parseImportInt(v, 0, 3); err != nil {
+					node.AddError("节点状态应为 未开始/进行中/已完成/已逾期")
+				}
+			}
+		}
+		if v := strings.TrimSuffix(node.Values["progress"], "%"); v != "" {
+			if _, err := parseImportInt(v, 0, 100); err != nil {
+				node.AddError("节点进度%s", err.Error())
+			}
+		}
+	}
+
+	// 前置节点只能引用同一任务中的节点
+	deps := make(map[*svc.ImportRecord][]*svc.ImportRecord)
+	for _, node := range record.Nodes {
+		var ids []string
+		for _, name := range splitImportList(node.Values["predecessors"]) {
+			prev, ok := byName[strings.ToLower(name)]
+			switch {
+			case !ok:
+				node.AddError("前置节点 %s 不在该任务中", name)
+			case prev == node:
+				node.AddError("前置节点不能是节点本身")
+			default:
+				ids = append(ids, prev.ID)
+				deps[node] = append(deps[node], prev)
+			}
+		}
+		node.SetRef("exNodeIds", strings.Join(ids, ","))
+	}
+	for _, node := range findDependencyCycle(record.Nodes, deps) {
+		node.AddError("前置节点形成循环")
+	}
+}
+
+// orderByParent 按上级关系排序（上级在前），返回排序结果和文件中形成循环的记录
+func orderByParent(records []*svc.ImportRecord, parents map[*svc.ImportRecord]*svc.ImportRecord) ([]*svc.ImportRecord, [][]*svc.ImportRecord) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*svc.ImportRecord]int)
+	ordered := make([]*svc.ImportRecord, 0, len(records))
+	var cycles [][]*svc.ImportRecord
+	for _, record := range records {
+		var chain []*svc.ImportRecord
+		cur := record
+		for cur != nil && state[cur] == 0 {
+			state[cur] = visiting
+			chain = append(chain, cur)
+			cur = parents[cur]
+		}
+		if cur != nil && state[cur] == visiting {
+			for i, r := range chain {
+				if r == cur {
+					cycles = append(cycles, chain[i:])
+					break
+				}
+			}
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			state[chain[i]] = done
+			ordered = append(ordered, chain[i])
+		}
+	}
+	return ordered, cycles
+}
+
+// findDependencyCycle 查找依赖图中处于循环上的节点
+func findDependencyCycle(nodes []*svc.ImportRecord, deps map[*svc.ImportRecord][]*svc.ImportRecord) []*svc.ImportRecord {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*svc.ImportRecord]int)
+	inCycle := make(map[*svc.ImportRecord]bool)
+	var stack []*svc.ImportRecord
+	var visit func(node *svc.ImportRecord)
+	visit = func(node *svc.ImportRecord) {
+		state[node] = visiting
+		stack = append(stack, node)
+		for _, dep := range deps[node] {
+			switch state[dep] {
+			case 0:
+				visit(dep)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					inCycle[stack[i]] = true
+					if stack[i] == dep {
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = done
+	}
+	for _, node := range nodes {
+		if state[node] == 0 {
+			visit(node)
+		}
+	}
+	result := make([]*svc.ImportRecord, 0, len(inCycle))
+	for _, node := range nodes {
+		if inCycle[node] {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataimport
+
+import (
+	"context"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UploadImportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 上传导入文件并校验（试运行，不写入数据）
+func NewUploadImportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UploadImportLogic {
+	return &UploadImportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UploadImportLogic) UploadImport(req *types.UploadImportRequest, fileName string, data []byte) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if errResp := checkImportPermission(l.ctx, l.svcCtx, operator, req.ImportType); errResp != nil {
+		return errResp, nil
+	}
+
+	maxRows := l.svcCtx.ImportService.MaxRows()
+	rows, err := svc.ParseImportFile(fileName, data, maxRows)
+	if err != nil {
+		return utils.Response.ValidationError(err.Error()), nil
+	}
+	records, err := buildImportRecords(req.ImportType, rows, maxRows)
+	if err != nil {
+		return utils.Response.ValidationError(err.Error()), nil
+	}
+	records, err = validateImportRecords(l.ctx, l.svcCtx, operator, req.ImportType, records, 0)
+	if err != nil {
+		l.Logger.Errorf("校验导入数据失败: %v", err)
+		return utils.Response.InternalError("校验导入数据失败"), nil
+	}
+	encoded, err := svc.EncodeImportRecords(records)
+	if err != nil {
+		return utils.Response.InternalError("保存导入数据失败"), nil
+	}
+
+	errorCount := countImportErrors(records)
+	status := int64(task.ImportStatusReady)
+	if errorCount > 0 {
+		status = task.ImportStatusInvalid
+	}
+	now := time.Now()
+	job := &task.ImportJob{
+		Id:         utils.Common.GenId("import"),
+		CompanyId:  operator.CompanyId,
+		EmployeeId: operator.Id,
+		ImportType: req.ImportType,
+		FileName:   fileName,
+		Status:     status,
+		TotalRows:  int64(len(records)),
+		ErrorCount: int64(errorCount),
+		Records:    encoded,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if _, err := l.svcCtx.ImportJobModel.Insert(l.ctx, job); err != nil {
+		l.Logger.Errorf("保存导入任务失败: %v", err)
+		return utils.Response.InternalError("保存导入任务失败"), nil
+	}
+	return utils.Response.Success(toImportReport(job, records)), nil
+}
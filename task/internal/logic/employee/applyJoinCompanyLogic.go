@@ -35,12 +35,6 @@ func (l *ApplyJoinCompanyLogic) ApplyJoinCompany(req *types.ApplyJoinCompanyRequ
 		return utils.Response.UnauthorizedError(), nil
 	}
 
-	// 检查用户是否已经加入公司
-	existingEmployee, _ := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
-	if existingEmployee != nil {
-		return utils.Response.BusinessError("already_in_company"), nil
-	}
-
 	// 检查是否有待审批的申请
 	pendingApp, _ := l.svcCtx.JoinApplicationModel.FindPendingByUserId(l.ctx, userID)
 	if pendingApp != nil {
@@ -66,6 +60,12 @@ func (l *ApplyJoinCompanyLogic) ApplyJoinCompany(req *types.ApplyJoinCompanyRequ
 		return utils.Response.BusinessError("invite_company_not_found"), nil
 	}
 
+	// 检查用户是否已经加入该公司（允许同时加入多家公司）
+	existingEmployee, _ := l.svcCtx.EmployeeModel.FindByUserIDAndCompanyID(l.ctx, userID, company.Id)
+	if existingEmployee != nil {
+		return utils.Response.BusinessError("already_in_company"), nil
+	}
+
 	// 检查公司状态
 	if company.Status != 1 {
 		return utils.Response.BusinessError("company_disabled"), nil
@@ -141,7 +141,7 @@ func (l *ApplyJoinCompanyLogic) notifyApprovers(companyID, companyName, applican
 	if len(approverEmployeeIDs) == 0 {
 		company, _ := l.svcCtx.CompanyModel.FindOne(ctx, companyID)
 		if company != nil {
-			founderEmployee, _ := l.svcCtx.EmployeeModel.FindByUserID(ctx, company.Owner)
+			founderEmployee, _ := l.svcCtx.EmployeeModel.FindByUserIDAndCompanyID(ctx, company.Owner, company.Id)
 			if founderEmployee != nil {
 				approverEmployeeIDs = append(approverEmployeeIDs, founderEmployee.Id)
 			}
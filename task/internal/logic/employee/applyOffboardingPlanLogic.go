@@ -0,0 +1,143 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package employee
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ApplyOffboardingPlanLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 执行离职交接计划
+func NewApplyOffboardingPlanLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApplyOffboardingPlanLogic {
+	return &ApplyOffboardingPlanLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ApplyOffboardingPlanLogic) ApplyOffboardingPlan(req *types.OffboardingPlanRequest) (resp *types.BaseResponse, err error) {
+	oc, errResp := loadOffboardingContext(l.ctx, l.svcCtx, req.ApprovalID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+	plan, items, errResp := loadDraftOffboardingPlan(l.ctx, l.svcCtx, req.ApprovalID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 1. 重新收集事项：出现计划外的新事项时需要重新生成计划，已不存在的事项直接跳过
+	current, err := collectOffboardingItems(l.ctx, l.svcCtx, oc.leaver, req.ApprovalID)
+	if err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("收集离职交接事项失败: %v", err)
+		return utils.Response.InternalError("收集离职交接事项失败"), nil
+	}
+	currentKeys := make(map[string]bool, len(current))
+	for _, it := range current {
+		currentKeys[offboardingItemKey(it.ItemType, it.TargetId)] = true
+	}
+	planKeys := make(map[string]bool, len(items))
+	pending := make([]*task.OffboardingPlanItem, 0, len(items))
+	unassigned := 0
+	for _, it := range items {
+		key := offboardingItemKey(it.ItemType, it.TargetId)
+		planKeys[key] = true
+		if !currentKeys[key] {
+			continue
+		}
+		if it.AssigneeId == "" {
+			unassigned++
+		}
+		pending = append(pending, it)
+	}
+	for key := range currentKeys {
+		if !planKeys[key] {
+			return utils.Response.BusinessError("offboarding_plan_outdated"), nil
+		}
+	}
+	if unassigned > 0 {
+		return utils.Response.BusinessErrorWithNum(fmt.Sprintf("还有 %d 项交接事项未指定接收人", unassigned)), nil
+	}
+
+	// 2. 校验接收人仍是在职员工
+	names := make(map[string]string)
+	for _, it := range pending {
+		assignee, err := validateOffboardingAssignee(l.ctx, l.svcCtx, oc.leaver, it, it.AssigneeId)
+		if err != nil {
+			return utils.Response.BusinessErrorWithNum(fmt.Sprintf("「%s」的接收人已不可用，请重新指定", it.TargetName)), nil
+		}
+		names[assignee.Id] = assignee.RealName
+	}
+
+	// 3. 在同一事务中转移全部事项
+	if err := l.svcCtx.OffboardingPlanModel.Apply(l.ctx, plan, pending, oc.operator.Id, names); err != nil {
+		if errors.Is(err, task.ErrOffboardingPlanApplied) {
+			return utils.Response.BusinessError("offboarding_plan_applied"), nil
+		}
+		l.Logger.WithContext(l.ctx).Errorf("执行离职交接计划失败: %v", err)
+		return utils.Response.InternalError("执行离职交接计划失败"), nil
+	}
+
+	// 4. 记录任务日志
+	now := time.Now()
+	for _, it := range pending {
+		if it.TaskId == "" || it.ItemType == task.OffboardingHandoverApproval || it.ItemType == task.OffboardingSubordinate {
+			continue
+		}
+		taskLog := &task.TaskLog{
+			LogId:   utils.Common.GenerateID(),
+			TaskId:  it.TaskId,
+			LogType: 6, // 交接
+			LogContent: fmt.Sprintf("离职交接: %s「%s」由 %s 转交给 %s",
+				offboardingItemTypeNames[it.ItemType], it.TargetName, oc.leaver.RealName, names[it.AssigneeId]),
+			EmployeeId: oc.operator.Id,
+			CreateTime: now,
+		}
+		if it.ItemType == task.OffboardingNodeExecutor || it.ItemType == task.OffboardingNodeLeader {
+			taskLog.TaskNodeId = utils.Common.ToSqlNullString(it.TargetId)
+		}
+		if _, err := l.svcCtx.TaskLogModel.Insert(l.ctx, taskLog); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("创建任务日志失败: %v", err)
+		}
+	}
+
+	// 5. 通知接收人
+	counts := make(map[string]int)
+	for _, it := range pending {
+		counts[it.AssigneeId]++
+	}
+	if l.svcCtx.NotificationMQService != nil {
+		for assigneeID, count := range counts {
+			event := l.svcCtx.NotificationMQService.NewNotificationEvent(svc.HandoverNotification, []string{assigneeID}, plan.Id)
+			event.Title = "离职交接"
+			event.Content = fmt.Sprintf("%s 离职，已将 %d 项工作交接给您", oc.leaver.RealName, count)
+			event.Priority = 2
+			if err := l.svcCtx.NotificationMQService.PublishNotificationEvent(l.ctx, event); err != nil {
+				l.Logger.WithContext(l.ctx).Errorf("发布通知事件失败: %v", err)
+			}
+		}
+	}
+
+	plan.Status = task.OffboardingPlanApplied
+	plan.AppliedBy = oc.operator.Id
+	plan.AppliedTime = sql.NullTime{Time: now, Valid: true}
+	plan.UpdateTime = now
+	return utils.Response.Success(toOffboardingPlanInfo(l.ctx, l.svcCtx, plan, pending, oc.leaver)), nil
+}
@@ -39,7 +39,7 @@ func (l *ApproveJoinApplicationLogic) ApproveJoinApplication(req *types.ApproveJ
 	}
 
 	// 获取当前员工信息
-	approverEmployee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	approverEmployee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil || approverEmployee == nil {
 		return utils.Response.BusinessError("employee_not_in_company"), nil
 	}
@@ -128,7 +128,7 @@ func (l *ApproveJoinApplicationLogic) ApproveJoinApplication(req *types.ApproveJ
 		l.sendResultEmail(applicantUser, false, company.Name, req.Note, "")
 
 		// 发送拒绝通知
-		go l.notifyApplicant(application.UserId, company.Name, false, req.Note)
+		go l.notifyApplicant(application.UserId, application.CompanyId, company.Name, false, req.Note)
 
 		logx.Infof("审批拒绝: applicationId=%s, userId=%s", req.ApplicationID, application.UserId)
 		return utils.Response.Success(map[string]interface{}{
@@ -279,7 +279,7 @@ func (l *ApproveJoinApplicationLogic) approveAndCreateEmployee(application *user
 	}
 
 	// 发送通知给申请人
-	go l.notifyApplicant(application.UserId, companyName, true, "")
+	go l.notifyApplicant(application.UserId, application.CompanyId, companyName, true, "")
 
 	return empCode, nil
 }
@@ -316,12 +316,12 @@ func (l *ApproveJoinApplicationLogic) sendResultEmail(applicantUser *user.User,
 }
 
 // notifyApplicant 通知申请人审批结果
-func (l *ApproveJoinApplicationLogic) notifyApplicant(applicantUserID, companyName string, approved bool, note string) {
+func (l *ApproveJoinApplicationLogic) notifyApplicant(applicantUserID, companyID, companyName string, approved bool, note string) {
 	ctx := context.Background()
 
 	if approved {
 		// 审批通过：获取申请人的员工信息
-		employee, _ := l.svcCtx.EmployeeModel.FindByUserID(ctx, applicantUserID)
+		employee, _ := l.svcCtx.EmployeeModel.FindByUserIDAndCompanyID(ctx, applicantUserID, companyID)
 		if employee == nil {
 			// 如果没有员工记录，说明创建员工失败，跳过通知
 			logx.Infof("申请人尚无员工记录，跳过通知: userId=%s", applicantUserID)
@@ -0,0 +1,88 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package employee
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type AssignOffboardingPlanLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 指定离职交接接收人
+func NewAssignOffboardingPlanLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AssignOffboardingPlanLogic {
+	return &AssignOffboardingPlanLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AssignOffboardingPlanLogic) AssignOffboardingPlan(req *types.AssignOffboardingPlanRequest) (resp *types.BaseResponse, err error) {
+	oc, errResp := loadOffboardingContext(l.ctx, l.svcCtx, req.ApprovalID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+	plan, items, errResp := loadDraftOffboardingPlan(l.ctx, l.svcCtx, req.ApprovalID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	itemMap := make(map[string]*task.OffboardingPlanItem, len(items))
+	for _, it := range items {
+		itemMap[it.Id] = it
+	}
+
+	// 1. 逐项指定接收人，接收人为空表示取消指定
+	assignees := make(map[string]string)
+	for _, a := range req.Assignments {
+		item, ok := itemMap[a.ItemID]
+		if !ok {
+			return utils.Response.ValidationError("交接事项不存在"), nil
+		}
+		assigneeID := strings.TrimSpace(a.AssigneeID)
+		if assigneeID != "" {
+			if _, err := validateOffboardingAssignee(l.ctx, l.svcCtx, oc.leaver, item, assigneeID); err != nil {
+				return utils.Response.BusinessError("offboarding_assignee_invalid"), nil
+			}
+		}
+		assignees[item.Id] = assigneeID
+	}
+
+	// 2. 接受推荐：尚未指定接收人的事项使用推荐的接收人，推荐人已不可用时保持未指定
+	if req.AcceptSuggestions {
+		for _, it := range items {
+			if _, ok := assignees[it.Id]; ok || it.AssigneeId != "" || it.SuggestedId == "" {
+				continue
+			}
+			if _, err := validateOffboardingAssignee(l.ctx, l.svcCtx, oc.leaver, it, it.SuggestedId); err == nil {
+				assignees[it.Id] = it.SuggestedId
+			}
+		}
+	}
+
+	if len(assignees) > 0 {
+		if err := l.svcCtx.OffboardingPlanModel.UpdateAssignees(l.ctx, plan.Id, assignees); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("更新离职交接接收人失败: %v", err)
+			return utils.Response.InternalError("更新离职交接接收人失败"), nil
+		}
+		for id, assigneeID := range assignees {
+			itemMap[id].AssigneeId = assigneeID
+		}
+		plan.UpdateTime = time.Now()
+	}
+
+	return utils.Response.Success(toOffboardingPlanInfo(l.ctx, l.svcCtx, plan, items, oc.leaver)), nil
+}
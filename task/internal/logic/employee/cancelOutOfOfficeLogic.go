@@ -0,0 +1,52 @@
+package employee
+
+import (
+	"context"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CancelOutOfOfficeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 取消外出代理
+func NewCancelOutOfOfficeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CancelOutOfOfficeLogic {
+	return &CancelOutOfOfficeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CancelOutOfOfficeLogic) CancelOutOfOffice(req *types.CancelOutOfOfficeRequest) (resp *types.BaseResponse, err error) {
+	if req.ID == "" {
+		return utils.Response.BusinessError("missing_required_fields"), nil
+	}
+
+	record, err := l.svcCtx.OutOfOfficeModel.FindOne(l.ctx, req.ID)
+	if err != nil {
+		return utils.Response.BusinessError("out_of_office_not_found"), nil
+	}
+	if _, errResp := loadOutOfOfficeTarget(l.ctx, l.svcCtx, record.EmployeeId); errResp != nil {
+		return errResp, nil
+	}
+	if record.Status != user.OutOfOfficeStatusScheduled && record.Status != user.OutOfOfficeStatusActive {
+		return utils.Response.BusinessError("out_of_office_finished"), nil
+	}
+
+	// 待生效的直接取消；生效中的提前结束，未处理事项退回本人并发送汇总
+	if err := l.svcCtx.OutOfOfficeService.Cancel(l.ctx, record); err != nil {
+		l.Logger.Errorf("取消外出代理失败: id=%s, err=%v", record.Id, err)
+		return utils.Response.InternalError("取消外出代理失败"), nil
+	}
+
+	return utils.Response.SuccessWithKey("operation", nil), nil
+}
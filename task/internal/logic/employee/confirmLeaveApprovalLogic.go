@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"task_Project/model/task"
+	"task_Project/model/user"
 	"task_Project/task/internal/utils"
 	"time"
 
@@ -58,6 +59,11 @@ func (l *ConfirmLeaveApprovalLogic) ConfirmLeaveApproval(req *types.ConfirmLeave
 		return utils.Response.ErrorWithKey("employee_not_found"), nil
 	}
 
+	// 离职员工名下的工作必须全部通过离职交接计划指定接收人后才能完成离职
+	if resp := l.checkOffboardingPlan(employee, req.ApprovalID); resp != nil {
+		return resp, nil
+	}
+
 	// 5. 更新员工状态为离职
 	updateData := map[string]interface{}{
 		"status":     0, // 离职
@@ -80,9 +86,11 @@ func (l *ConfirmLeaveApprovalLogic) ConfirmLeaveApproval(req *types.ConfirmLeave
 		// 不影响主流程，只记录错误
 	}
 
-	// 8. 更新用户的 has_joined_company 为 false
+	// 8. 更新用户的 has_joined_company 为 false（仍在其他公司任职时保留）
 	if employee.UserId != "" {
-		if userErr := l.svcCtx.UserModel.UpdateHasJoinedCompany(l.ctx, employee.UserId, false); userErr != nil {
+		if hasOther, _ := l.svcCtx.EmployeeModel.HasOtherActiveMembership(l.ctx, employee.UserId, employee.Id); hasOther {
+			logx.Infof("用户 %s 仍在其他公司任职，保留 has_joined_company", employee.UserId)
+		} else if userErr := l.svcCtx.UserModel.UpdateHasJoinedCompany(l.ctx, employee.UserId, false); userErr != nil {
 			logx.Errorf("更新用户 has_joined_company 失败: %v", userErr)
 		} else {
 			logx.Infof("用户 %s 的 has_joined_company 已更新为 false", employee.UserId)
@@ -103,6 +111,38 @@ func (l *ConfirmLeaveApprovalLogic) ConfirmLeaveApproval(req *types.ConfirmLeave
 	}), nil
 }
 
+// checkOffboardingPlan 检查离职交接计划：员工名下没有待交接的事项时直接通过，否则计划必须已执行且覆盖全部事项
+func (l *ConfirmLeaveApprovalLogic) checkOffboardingPlan(employee *user.Employee, approvalID string) *types.BaseResponse {
+	items, err := collectOffboardingItems(l.ctx, l.svcCtx, employee, approvalID)
+	if err != nil {
+		logx.Errorf("收集离职交接事项失败: %v", err)
+		return utils.Response.InternalError("收集离职交接事项失败")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	plan, err := l.svcCtx.OffboardingPlanModel.FindByApprovalId(l.ctx, approvalID)
+	if err != nil {
+		return utils.Response.BusinessError("offboarding_plan_required")
+	}
+	if plan.Status == task.OffboardingPlanApplied {
+		// 计划执行后又出现了新的事项
+		return utils.Response.BusinessError("offboarding_plan_outdated")
+	}
+	planItems, err := l.svcCtx.OffboardingPlanModel.FindItems(l.ctx, plan.Id)
+	if err != nil {
+		logx.Errorf("查询离职交接事项失败: %v", err)
+		return utils.Response.InternalError("查询离职交接事项失败")
+	}
+	for _, it := range planItems {
+		if it.AssigneeId == "" {
+			return utils.Response.BusinessError("offboarding_items_unassigned")
+		}
+	}
+	return utils.Response.BusinessError("offboarding_plan_required")
+}
+
 // handleTaskRedispatch 处理任务重新派发
 func (l *ConfirmLeaveApprovalLogic) handleTaskRedispatch(employeeID string) error {
 	// 1. 查找员工当前负责的任务节点
@@ -92,8 +92,8 @@ func (l *CreateEmployeeLogic) CreateEmployee(req *types.CreateEmployeeRequest) (
 	}
 
 	// 检查用户是否已经是该公司的员工
-	existingEmployee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, req.UserID)
-	if err == nil && existingEmployee.CompanyId == req.CompanyID {
+	existingEmployee, err := l.svcCtx.EmployeeModel.FindByUserIDAndCompanyID(l.ctx, req.UserID, req.CompanyID)
+	if err == nil && existingEmployee != nil {
 		return utils.Response.BusinessError("employee_already_exists"), nil
 	}
 
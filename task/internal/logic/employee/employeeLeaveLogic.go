@@ -107,7 +107,8 @@ func (l *EmployeeLeaveLogic) handleHRInitiatedLeave(employee *user.Employee, req
 	logx.Infof("HR为员工 %s 办理离职", employee.RealName)
 
 	// 1. 使用审批人查找器查找员工的直接上级作为审批人
-	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel)
+	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel).
+		WithDelegateResolver(l.svcCtx.OutOfOfficeService.ResolveDelegate)
 	approverResult, err := approverFinder.FindApprover(l.ctx, employee.Id)
 	if err != nil {
 		logx.Errorf("查找审批人失败: %v", err)
@@ -139,6 +140,9 @@ func (l *EmployeeLeaveLogic) handleHRInitiatedLeave(employee *user.Employee, req
 		logx.Errorf("创建离职审批记录失败: %v", err)
 		return utils.Response.InternalError("创建离职审批记录失败"), err
 	}
+	if approverResult.DelegatedFromID != "" {
+		l.svcCtx.OutOfOfficeService.RecordRoutedItem(l.ctx, approverResult.DelegatedFromID, approverResult.ApproverID, svc.OutOfOfficeItemHandover, approvalID, "离职审批："+employee.RealName)
+	}
 
 	// 3. 发送通知给审批人
 	taskNodes := []string{}
@@ -168,7 +172,8 @@ func (l *EmployeeLeaveLogic) handleEmployeeInitiatedLeave(employee *user.Employe
 	logx.Infof("员工 %s 主动申请离职", employee.RealName)
 
 	// 1. 使用审批人查找器查找合适的审批人
-	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel)
+	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel).
+		WithDelegateResolver(l.svcCtx.OutOfOfficeService.ResolveDelegate)
 	approverResult, err := approverFinder.FindApprover(l.ctx, employee.Id)
 	if err != nil {
 		logx.Errorf("查找审批人失败: %v", err)
@@ -200,6 +205,9 @@ func (l *EmployeeLeaveLogic) handleEmployeeInitiatedLeave(employee *user.Employe
 		logx.Errorf("创建离职审批记录失败: %v", err)
 		return utils.Response.InternalError("创建离职审批记录失败"), err
 	}
+	if approverResult.DelegatedFromID != "" {
+		l.svcCtx.OutOfOfficeService.RecordRoutedItem(l.ctx, approverResult.DelegatedFromID, approverResult.ApproverID, svc.OutOfOfficeItemHandover, approvalID, "离职审批："+employee.RealName)
+	}
 
 	// 3. 发送通知给审批人
 	taskNodes := []string{}
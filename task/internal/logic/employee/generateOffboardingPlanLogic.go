@@ -0,0 +1,103 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package employee
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GenerateOffboardingPlanLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 生成离职交接计划
+func NewGenerateOffboardingPlanLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GenerateOffboardingPlanLogic {
+	return &GenerateOffboardingPlanLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GenerateOffboardingPlanLogic) GenerateOffboardingPlan(req *types.OffboardingPlanRequest) (resp *types.BaseResponse, err error) {
+	oc, errResp := loadOffboardingContext(l.ctx, l.svcCtx, req.ApprovalID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 1. 收集离职员工名下的全部事项并推荐接收人
+	items, err := collectOffboardingItems(l.ctx, l.svcCtx, oc.leaver, req.ApprovalID)
+	if err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("收集离职交接事项失败: %v", err)
+		return utils.Response.InternalError("收集离职交接事项失败"), nil
+	}
+	suggestOffboardingRecipients(l.ctx, l.svcCtx, oc.leaver, items)
+
+	// 2. 已有计划时重新生成事项，保留仍然存在的事项上已指定的接收人
+	plan, err := l.svcCtx.OffboardingPlanModel.FindByApprovalId(l.ctx, req.ApprovalID)
+	if err != nil && !errors.Is(err, task.ErrNotFound) {
+		l.Logger.WithContext(l.ctx).Errorf("查询离职交接计划失败: %v", err)
+		return utils.Response.InternalError("查询离职交接计划失败"), nil
+	}
+	assigned := make(map[string]string)
+	if plan != nil {
+		if plan.Status == task.OffboardingPlanApplied {
+			return utils.Response.BusinessError("offboarding_plan_applied"), nil
+		}
+		oldItems, err := l.svcCtx.OffboardingPlanModel.FindItems(l.ctx, plan.Id)
+		if err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("查询离职交接事项失败: %v", err)
+			return utils.Response.InternalError("查询离职交接事项失败"), nil
+		}
+		for _, it := range oldItems {
+			assigned[offboardingItemKey(it.ItemType, it.TargetId)] = it.AssigneeId
+		}
+	}
+
+	now := time.Now()
+	isNew := plan == nil
+	if isNew {
+		plan = &task.OffboardingPlan{
+			Id:         utils.Common.GenId("obp"),
+			CompanyId:  oc.leaver.CompanyId,
+			EmployeeId: oc.leaver.Id,
+			ApprovalId: req.ApprovalID,
+			Status:     task.OffboardingPlanDraft,
+			CreatorId:  oc.operator.Id,
+			CreateTime: now,
+			UpdateTime: now,
+		}
+	}
+	for _, it := range items {
+		it.Id = utils.Common.GenId("obi")
+		it.PlanId = plan.Id
+		it.AssigneeId = assigned[offboardingItemKey(it.ItemType, it.TargetId)]
+		it.CreateTime = now
+		it.UpdateTime = now
+	}
+
+	if isNew {
+		err = l.svcCtx.OffboardingPlanModel.Insert(l.ctx, plan, items)
+	} else {
+		err = l.svcCtx.OffboardingPlanModel.ReplaceItems(l.ctx, plan.Id, items)
+		plan.UpdateTime = now
+	}
+	if err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("保存离职交接计划失败: %v", err)
+		return utils.Response.InternalError("保存离职交接计划失败"), nil
+	}
+
+	return utils.Response.Success(toOffboardingPlanInfo(l.ctx, l.svcCtx, plan, items, oc.leaver)), nil
+}
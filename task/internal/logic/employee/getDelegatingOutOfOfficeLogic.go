@@ -0,0 +1,49 @@
+package employee
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetDelegatingOutOfOfficeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前代理中的外出记录（当前员工作为代理人）
+func NewGetDelegatingOutOfOfficeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetDelegatingOutOfOfficeLogic {
+	return &GetDelegatingOutOfOfficeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetDelegatingOutOfOfficeLogic) GetDelegatingOutOfOffice() (resp *types.BaseResponse, err error) {
+	employeeID, ok := utils.Common.GetCurrentEmployeeID(l.ctx)
+	if !ok || employeeID == "" {
+		return utils.Response.BusinessError("employee_not_in_company"), nil
+	}
+
+	records, err := l.svcCtx.OutOfOfficeModel.FindCurrentByDelegateId(l.ctx, employeeID)
+	if err != nil {
+		l.Logger.Errorf("查询代理中的外出记录失败: %v", err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	list := make([]types.OutOfOfficeInfo, 0, len(records))
+	for _, record := range records {
+		list = append(list, toOutOfOfficeInfo(l.ctx, l.svcCtx, record))
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"list":  list,
+		"total": len(list),
+	}), nil
+}
@@ -0,0 +1,54 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package employee
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetOffboardingPlanLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取离职交接计划
+func NewGetOffboardingPlanLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOffboardingPlanLogic {
+	return &GetOffboardingPlanLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetOffboardingPlanLogic) GetOffboardingPlan(req *types.OffboardingPlanRequest) (resp *types.BaseResponse, err error) {
+	oc, errResp := loadOffboardingContext(l.ctx, l.svcCtx, req.ApprovalID, false)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	plan, err := l.svcCtx.OffboardingPlanModel.FindByApprovalId(l.ctx, req.ApprovalID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return utils.Response.BusinessError("offboarding_plan_not_found"), nil
+		}
+		l.Logger.WithContext(l.ctx).Errorf("查询离职交接计划失败: %v", err)
+		return utils.Response.InternalError("查询离职交接计划失败"), nil
+	}
+	items, err := l.svcCtx.OffboardingPlanModel.FindItems(l.ctx, plan.Id)
+	if err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("查询离职交接事项失败: %v", err)
+		return utils.Response.InternalError("查询离职交接事项失败"), nil
+	}
+
+	return utils.Response.Success(toOffboardingPlanInfo(l.ctx, l.svcCtx, plan, items, oc.leaver)), nil
+}
@@ -0,0 +1,49 @@
+package employee
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetOutOfOfficeListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取外出代理列表
+func NewGetOutOfOfficeListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOutOfOfficeListLogic {
+	return &GetOutOfOfficeListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetOutOfOfficeListLogic) GetOutOfOfficeList(req *types.OutOfOfficeListRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := loadOutOfOfficeTarget(l.ctx, l.svcCtx, req.EmployeeID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	records, err := l.svcCtx.OutOfOfficeModel.FindByEmployeeId(l.ctx, employee.Id, req.IncludeFinished)
+	if err != nil {
+		l.Logger.Errorf("查询外出代理列表失败: %v", err)
+		return utils.Response.InternalError("查询外出代理列表失败"), nil
+	}
+
+	list := make([]types.OutOfOfficeInfo, 0, len(records))
+	for _, record := range records {
+		list = append(list, toOutOfOfficeInfo(l.ctx, l.svcCtx, record))
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"list":  list,
+		"total": len(list),
+	}), nil
+}
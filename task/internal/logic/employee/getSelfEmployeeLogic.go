@@ -32,7 +32,7 @@ func (l *GetSelfEmployeeLogic) GetSelfEmployee() (resp *types.BaseResponse, err
 	}
 
 	// 通过 userId 查找员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userId)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userId)
 	if err != nil {
 		logx.Errorf("查找员工失败: %v", err)
 		return utils.Response.BusinessError("employee_not_found"), nil
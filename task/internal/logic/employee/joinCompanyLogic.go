@@ -45,8 +45,8 @@ func (l *JoinCompanyLogic) JoinCompany(req *types.JoinCompanyRequest) (resp *typ
 		return utils.Response.InternalError("查询用户失败"), nil
 	}
 
-	// 检查用户是否已经加入公司
-	existingEmployee, _ := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	// 检查用户是否已经加入该公司（允许同时加入多家公司）
+	existingEmployee, _ := l.svcCtx.EmployeeModel.FindByUserIDAndCompanyID(l.ctx, userID, req.CompanyID)
 	if existingEmployee != nil {
 		return utils.Response.BusinessError("user_already_in_company"), nil
 	}
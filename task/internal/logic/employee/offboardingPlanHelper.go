@@ -0,0 +1,372 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+	tasklogic "task_Project/task/internal/logic/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// offboardingItemTypeNames 离职交接事项类型名称
+var offboardingItemTypeNames = map[string]string{
+	task.OffboardingNodeExecutor:     "节点执行人",
+	task.OffboardingNodeLeader:       "节点负责人",
+	task.OffboardingTaskLeader:       "任务负责人",
+	task.OffboardingHandoverApproval: "交接审批",
+	task.OffboardingNodeApproval:     "节点完成审批",
+	task.OffboardingChecklist:        "清单",
+	task.OffboardingSubordinate:      "直属下属",
+}
+
+// offboardingContext 离职交接计划操作的上下文
+type offboardingContext struct {
+	approval *task.TaskHandover
+	leaver   *user.Employee
+	operator *user.Employee
+}
+
+// loadOffboardingContext 加载离职审批、离职员工和当前操作人，并校验操作人是离职审批的审批人或公司管理员。
+// requirePending 为 true 时要求离职审批尚未完成
+func loadOffboardingContext(ctx context.Context, svcCtx *svc.ServiceContext, approvalID string, requirePending bool) (*offboardingContext, *types.BaseResponse) {
+	if approvalID == "" {
+		return nil, utils.Response.ValidationError("审批ID不能为空")
+	}
+	operatorID, ok := utils.Common.GetCurrentEmployeeID(ctx)
+	if !ok {
+		return nil, utils.Response.UnauthorizedError()
+	}
+	operator, err := svcCtx.EmployeeModel.FindOne(ctx, operatorID)
+	if err != nil {
+		return nil, utils.Response.ErrorWithKey("employee_not_found")
+	}
+
+	approval, err := svcCtx.TaskHandoverModel.FindOne(ctx, approvalID)
+	if err != nil || approval.TaskId != "" {
+		return nil, utils.Response.ErrorWithKey("approval_not_found")
+	}
+	if requirePending && approval.HandoverStatus != 1 {
+		return nil, utils.Response.BusinessError("offboarding_leave_closed")
+	}
+	leaver, err := svcCtx.EmployeeModel.FindOne(ctx, approval.FromEmployeeId)
+	if err != nil {
+		return nil, utils.Response.ErrorWithKey("employee_not_found")
+	}
+
+	isApprover := approval.ApproverId.Valid && approval.ApproverId.String == operator.Id
+	if operator.CompanyId != leaver.CompanyId || (!isApprover && !svcCtx.IsCompanyAdmin(ctx, operator)) {
+		return nil, utils.Response.BusinessError("offboarding_no_permission")
+	}
+	return &offboardingContext{approval: approval, leaver: leaver, operator: operator}, nil
+}
+
+// loadDraftOffboardingPlan 加载待执行的离职交接计划及其事项
+func loadDraftOffboardingPlan(ctx context.Context, svcCtx *svc.ServiceContext, approvalID string) (*task.OffboardingPlan, []*task.OffboardingPlanItem, *types.BaseResponse) {
+	plan, err := svcCtx.OffboardingPlanModel.FindByApprovalId(ctx, approvalID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, nil, utils.Response.BusinessError("offboarding_plan_not_found")
+		}
+		logx.WithContext(ctx).Errorf("查询离职交接计划失败: %v", err)
+		return nil, nil, utils.Response.InternalError("查询离职交接计划失败")
+	}
+	if plan.Status == task.OffboardingPlanApplied {
+		return nil, nil, utils.Response.BusinessError("offboarding_plan_applied")
+	}
+	items, err := svcCtx.OffboardingPlanModel.FindItems(ctx, plan.Id)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询离职交接事项失败: %v", err)
+		return nil, nil, utils.Response.InternalError("查询离职交接事项失败")
+	}
+	return plan, items, nil
+}
+
+// offboardingItemKey 事项的唯一标识，重新生成计划时用于保留已确认的接收人
+func offboardingItemKey(itemType, targetID string) string {
+	return itemType + ":" + targetID
+}
+
+// shortName 截断过长的事项名称
+func shortName(name string) string {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) <= 50 {
+		return name
+	}
+	return string([]rune(name)[:50]) + "…"
+}
+
+// collectOffboardingItems 列出员工名下需要交接的全部事项：未完成节点的执行人和负责人、未完成任务的负责人、
+// 待其审批的交接和节点完成审批、未完成的清单以及直属下属。返回的事项未设置ID和接收人
+func collectOffboardingItems(ctx context.Context, svcCtx *svc.ServiceContext, leaver *user.Employee, approvalID string) ([]*task.OffboardingPlanItem, error) {
+	var items []*task.OffboardingPlanItem
+	add := func(itemType, targetID, taskID, name string) {
+		items = append(items, &task.OffboardingPlanItem{ItemType: itemType, TargetId: targetID, TaskId: taskID, TargetName: shortName(name)})
+	}
+
+	// 未完成节点
+	executorNodes, _, err := svcCtx.TaskNodeModel.FindByExecutor(ctx, leaver.Id, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range executorNodes {
+		if n.NodeStatus != task.NodeStatusCompleted && utils.Common.ContainsID(n.ExecutorId, leaver.Id) {
+			add(task.OffboardingNodeExecutor, n.TaskNodeId, n.TaskId, n.NodeName)
+		}
+	}
+	leaderNodes, _, err := svcCtx.TaskNodeModel.FindByLeader(ctx, leaver.Id, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range leaderNodes {
+		if n.NodeStatus != task.NodeStatusCompleted && utils.Common.ContainsID(n.LeaderId, leaver.Id) {
+			add(task.OffboardingNodeLeader, n.TaskNodeId, n.TaskId, n.NodeName)
+		}
+	}
+
+	// 未完成任务的负责人
+	tasks, _, err := svcCtx.TaskModel.FindByInvolved(ctx, leaver.Id, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if t.TaskStatus == task.TaskStatusCompleted || t.TaskStatus == task.TaskStatusOverdueComplete {
+			continue
+		}
+		isLeader := t.LeaderId.Valid && t.LeaderId.String == leaver.Id
+		isResponsible := t.ResponsibleEmployeeIds.Valid && utils.Common.ContainsID(t.ResponsibleEmployeeIds.String, leaver.Id)
+		if isLeader || isResponsible {
+			add(task.OffboardingTaskLeader, t.TaskId, t.TaskId, t.TaskTitle)
+		}
+	}
+
+	// 待其审批的交接（不含本次离职审批）和节点完成审批
+	handovers, err := svcCtx.TaskHandoverModel.FindPendingByApprover(ctx, leaver.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range handovers {
+		if h.HandoverId == approvalID {
+			continue
+		}
+		name := "离职审批"
+		if h.TaskId != "" {
+			name = "任务交接"
+			if t, err := svcCtx.TaskModel.FindOne(ctx, h.TaskId); err == nil {
+				name = "任务交接：" + t.TaskTitle
+			}
+		} else if emp, err := svcCtx.EmployeeModel.FindOne(ctx, h.FromEmployeeId); err == nil {
+			name = "离职审批：" + emp.RealName
+		}
+		add(task.OffboardingHandoverApproval, h.HandoverId, h.TaskId, name)
+	}
+	nodeApprovals, _, err := svcCtx.HandoverApprovalModel.FindTaskNodeApprovalsByApprover(ctx, leaver.Id, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range nodeApprovals {
+		name, taskID := "节点完成审批", ""
+		if a.TaskNodeId.Valid && a.TaskNodeId.String != "" {
+			if n, err := svcCtx.TaskNodeModel.FindOne(ctx, a.TaskNodeId.String); err == nil {
+				name, taskID = "节点完成审批："+n.NodeName, n.TaskId
+			}
+		}
+		add(task.OffboardingNodeApproval, a.ApprovalId, taskID, name)
+	}
+
+	// 未完成的清单
+	checklists, _, err := svcCtx.TaskChecklistModel.FindByCreatorIdWithPage(ctx, leaver.Id, 0, 1, 1000)
+	if err != nil {
+		return nil, err
+	}
+	nodeTasks := make(map[string]string)
+	for _, c := range checklists {
+		taskID, ok := nodeTasks[c.TaskNodeId]
+		if !ok {
+			if n, err := svcCtx.TaskNodeModel.FindOne(ctx, c.TaskNodeId); err == nil {
+				taskID = n.TaskId
+			}
+			nodeTasks[c.TaskNodeId] = taskID
+		}
+		add(task.OffboardingChecklist, c.ChecklistId, taskID, c.Content)
+	}
+
+	// 直属下属
+	subordinates, err := svcCtx.EmployeeModel.FindSubordinates(ctx, leaver.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range subordinates {
+		add(task.OffboardingSubordinate, s.Id, "", s.RealName)
+	}
+	return items, nil
+}
+
+// suggestOffboardingRecipients 为每个事项推荐接收人：节点和任务使用自动派发的默认评分，在同一计划中
+// 已推荐的事项计入候选人负载以分散分配；清单跟随所属节点的执行人；审批和下属由离职员工的上级接管
+func suggestOffboardingRecipients(ctx context.Context, svcCtx *svc.ServiceContext, leaver *user.Employee, items []*task.OffboardingPlanItem) {
+	dispatcher := tasklogic.NewAutoDispatchLogic(ctx, svcCtx)
+	ranked := make(map[string][]svc.RecommendedEmployee) // 部门ID -> 候选人
+	planned := make(map[string]int)                      // 候选人 -> 本计划中已推荐的事项数
+	pick := func(node *task.TaskNode, exclude string) (string, string) {
+		candidates, ok := ranked[node.DepartmentId]
+		if !ok {
+			candidates = dispatcher.RankCandidates(node, leaver.CompanyId, false)
+			ranked[node.DepartmentId] = candidates
+		}
+		best, bestScore := -1, 0.0
+		for i, c := range candidates {
+			if c.EmployeeID == leaver.Id || c.EmployeeID == exclude {
+				continue
+			}
+			score := c.Score - float64(planned[c.EmployeeID]*10)
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			return "", ""
+		}
+		planned[candidates[best].EmployeeID]++
+		return candidates[best].EmployeeID, candidates[best].Reason
+	}
+
+	// 离职员工的上级（审批人查找器的结果）
+	superiorID, superiorReason := "", ""
+	finder := utils.NewApproverFinder(svcCtx.EmployeeModel, svcCtx.DepartmentModel, svcCtx.CompanyModel).
+		WithDelegateResolver(svcCtx.OutOfOfficeService.ResolveDelegate)
+	if result, err := finder.FindApprover(ctx, leaver.Id); err == nil && result != nil && result.ApproverID != leaver.Id {
+		superiorID, superiorReason = result.ApproverID, "由离职员工的上级接管"
+	}
+
+	nodes := make(map[string]*task.TaskNode)
+	findNode := func(id string) *task.TaskNode {
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+		n, err := svcCtx.TaskNodeModel.FindOne(ctx, id)
+		if err != nil {
+			n = nil
+		}
+		nodes[id] = n
+		return n
+	}
+
+	// 先处理节点，清单需要参考节点的推荐结果
+	nodeSuggestion := make(map[string]string)
+	for _, it := range items {
+		switch it.ItemType {
+		case task.OffboardingNodeExecutor, task.OffboardingNodeLeader:
+			if n := findNode(it.TargetId); n != nil {
+				it.SuggestedId, it.SuggestReason = pick(n, "")
+				if it.ItemType == task.OffboardingNodeExecutor && it.SuggestedId != "" {
+					nodeSuggestion[n.TaskNodeId] = it.SuggestedId
+				}
+			}
+		case task.OffboardingTaskLeader:
+			// 任务没有所属部门时按离职员工所在部门推荐
+			dept := ""
+			if t, err := svcCtx.TaskModel.FindOne(ctx, it.TargetId); err == nil && t.DepartmentIds.Valid {
+				dept = strings.TrimSpace(strings.Split(t.DepartmentIds.String, ",")[0])
+			}
+			if dept == "" && leaver.DepartmentId.Valid {
+				dept = leaver.DepartmentId.String
+			}
+			if dept != "" {
+				it.SuggestedId, it.SuggestReason = pick(&task.TaskNode{TaskNodeId: it.TargetId, DepartmentId: dept}, "")
+			}
+		}
+	}
+
+	for _, it := range items {
+		switch it.ItemType {
+		case task.OffboardingChecklist:
+			checklist, err := svcCtx.TaskChecklistModel.FindOne(ctx, it.TargetId)
+			if err != nil {
+				continue
+			}
+			if id, ok := nodeSuggestion[checklist.TaskNodeId]; ok {
+				it.SuggestedId, it.SuggestReason = id, "跟随节点执行人的接收人"
+				continue
+			}
+			if n := findNode(checklist.TaskNodeId); n != nil {
+				for _, id := range strings.Split(n.ExecutorId, ",") {
+					if id = strings.TrimSpace(id); id != "" && id != leaver.Id {
+						it.SuggestedId, it.SuggestReason = id, "由节点的其他执行人接管"
+						break
+					}
+				}
+			}
+		case task.OffboardingHandoverApproval, task.OffboardingNodeApproval:
+			it.SuggestedId, it.SuggestReason = superiorID, superiorReason
+		case task.OffboardingSubordinate:
+			if superiorID != it.TargetId {
+				it.SuggestedId, it.SuggestReason = superiorID, superiorReason
+			}
+		}
+	}
+}
+
+// validateOffboardingAssignee 校验接收人：必须是同公司在职员工，且不能是离职员工本人；下属不能成为自己的上级
+func validateOffboardingAssignee(ctx context.Context, svcCtx *svc.ServiceContext, leaver *user.Employee, item *task.OffboardingPlanItem, assigneeID string) (*user.Employee, error) {
+	if assigneeID == leaver.Id || (item.ItemType == task.OffboardingSubordinate && assigneeID == item.TargetId) {
+		return nil, errors.New("invalid assignee")
+	}
+	assignee, err := svcCtx.EmployeeModel.FindOne(ctx, assigneeID)
+	if err != nil || assignee.CompanyId != leaver.CompanyId || assignee.Status != 1 {
+		return nil, errors.New("invalid assignee")
+	}
+	return assignee, nil
+}
+
+// toOffboardingPlanInfo 转换离职交接计划为响应格式
+func toOffboardingPlanInfo(ctx context.Context, svcCtx *svc.ServiceContext, plan *task.OffboardingPlan, items []*task.OffboardingPlanItem, leaver *user.Employee) types.OffboardingPlanInfo {
+	nameOf := svcCtx.CustomFieldService.EmployeeNamer(ctx)
+	nameOrEmpty := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		return nameOf(id)
+	}
+
+	info := types.OffboardingPlanInfo{
+		PlanID:       plan.Id,
+		ApprovalID:   plan.ApprovalId,
+		EmployeeID:   plan.EmployeeId,
+		EmployeeName: leaver.RealName,
+		Status:       plan.Status,
+		TotalCount:   len(items),
+		AppliedBy:    plan.AppliedBy,
+		UpdateTime:   plan.UpdateTime.Format("2006-01-02 15:04:05"),
+		Items:        make([]types.OffboardingPlanItemInfo, 0, len(items)),
+	}
+	if plan.AppliedTime.Valid {
+		info.AppliedTime = plan.AppliedTime.Time.Format("2006-01-02 15:04:05")
+	}
+	for _, it := range items {
+		if it.AssigneeId == "" {
+			info.UnassignedCount++
+		}
+		info.Items = append(info.Items, types.OffboardingPlanItemInfo{
+			ID:            it.Id,
+			ItemType:      it.ItemType,
+			ItemTypeName:  offboardingItemTypeNames[it.ItemType],
+			TargetID:      it.TargetId,
+			TaskID:        it.TaskId,
+			TargetName:    it.TargetName,
+			SuggestedID:   it.SuggestedId,
+			SuggestedName: nameOrEmpty(it.SuggestedId),
+			SuggestReason: it.SuggestReason,
+			AssigneeID:    it.AssigneeId,
+			AssigneeName:  nameOrEmpty(it.AssigneeId),
+		})
+	}
+	return info
+}
@@ -0,0 +1,58 @@
+package employee
+
+import (
+	"context"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// loadOutOfOfficeTarget 获取外出代理的目标员工，只能操作自己或下属（直属上级/部门经理/创始人）
+func loadOutOfOfficeTarget(ctx context.Context, svcCtx *svc.ServiceContext, employeeID string) (*user.Employee, *types.BaseResponse) {
+	currentEmployeeID, ok := utils.Common.GetCurrentEmployeeID(ctx)
+	if !ok || currentEmployeeID == "" {
+		return nil, utils.Response.BusinessError("employee_not_in_company")
+	}
+	if employeeID == "" {
+		employeeID = currentEmployeeID
+	}
+
+	target, err := svcCtx.EmployeeModel.FindOne(ctx, employeeID)
+	if err != nil {
+		return nil, utils.Response.BusinessError("employee_not_found")
+	}
+	if target.Id == currentEmployeeID {
+		return target, nil
+	}
+
+	approverFinder := utils.NewApproverFinder(svcCtx.EmployeeModel, svcCtx.DepartmentModel, svcCtx.CompanyModel)
+	if canApprove, _ := approverFinder.CanApprove(ctx, currentEmployeeID, target.Id); !canApprove {
+		return nil, utils.Response.BusinessError("out_of_office_permission_denied")
+	}
+	return target, nil
+}
+
+// toOutOfOfficeInfo 转换外出代理记录
+func toOutOfOfficeInfo(ctx context.Context, svcCtx *svc.ServiceContext, record *user.OutOfOffice) types.OutOfOfficeInfo {
+	info := types.OutOfOfficeInfo{
+		ID:                  record.Id,
+		EmployeeID:          record.EmployeeId,
+		DelegateID:          record.DelegateId,
+		StartTime:           utils.Common.FormatTime(record.StartTime),
+		EndTime:             utils.Common.FormatTime(record.EndTime),
+		Reason:              record.Reason.String,
+		Status:              int(record.Status),
+		RoutedItemCount:     svc.CountOutOfOfficeItems(record),
+		RoutedNotifications: record.RoutedNotifications,
+		CreateTime:          utils.Common.FormatTime(record.CreateTime),
+	}
+	if emp, err := svcCtx.EmployeeModel.FindOne(ctx, record.EmployeeId); err == nil {
+		info.EmployeeName = emp.RealName
+	}
+	if delegate, err := svcCtx.EmployeeModel.FindOne(ctx, record.DelegateId); err == nil {
+		info.DelegateName = delegate.RealName
+	}
+	return info
+}
@@ -0,0 +1,100 @@
+package employee
+
+import (
+	"context"
+	"time"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetOutOfOfficeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 设置外出代理
+func NewSetOutOfOfficeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetOutOfOfficeLogic {
+	return &SetOutOfOfficeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetOutOfOfficeLogic) SetOutOfOffice(req *types.SetOutOfOfficeRequest) (resp *types.BaseResponse, err error) {
+	// 1. 参数验证
+	if req.DelegateID == "" || req.StartTime == "" || req.EndTime == "" {
+		return utils.Response.BusinessError("missing_required_fields"), nil
+	}
+	startTime, err := time.ParseInLocation("2006-01-02 15:04:05", req.StartTime, time.Local)
+	if err != nil {
+		return utils.Response.BusinessError("out_of_office_time_invalid"), nil
+	}
+	endTime, err := time.ParseInLocation("2006-01-02 15:04:05", req.EndTime, time.Local)
+	if err != nil || !endTime.After(startTime) || !endTime.After(time.Now()) {
+		return utils.Response.BusinessError("out_of_office_time_invalid"), nil
+	}
+
+	// 2. 获取外出员工并校验操作权限
+	employee, errResp := loadOutOfOfficeTarget(l.ctx, l.svcCtx, req.EmployeeID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 3. 校验代理人：在职、同公司、不能是自己
+	if req.DelegateID == employee.Id {
+		return utils.Response.BusinessError("out_of_office_delegate_self"), nil
+	}
+	delegate, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.DelegateID)
+	if err != nil || delegate.Status != 1 || delegate.CompanyId != employee.CompanyId {
+		return utils.Response.BusinessError("out_of_office_delegate_invalid"), nil
+	}
+
+	// 4. 同一时间段只能有一条外出安排
+	count, err := l.svcCtx.OutOfOfficeModel.CountOverlapping(l.ctx, employee.Id, startTime, endTime)
+	if err != nil {
+		l.Logger.Errorf("检查外出时间段失败: %v", err)
+		return utils.Response.InternalError("设置外出代理失败"), nil
+	}
+	if count > 0 {
+		return utils.Response.BusinessError("out_of_office_overlap"), nil
+	}
+
+	// 5. 创建外出记录
+	currentEmployeeID, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
+	record := &user.OutOfOffice{
+		Id:         utils.Common.GenId("ooo"),
+		CompanyId:  employee.CompanyId,
+		EmployeeId: employee.Id,
+		DelegateId: delegate.Id,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Reason:     utils.Common.ToSqlNullString(req.Reason),
+		Status:     user.OutOfOfficeStatusScheduled,
+		CreateBy:   utils.Common.ToSqlNullString(currentEmployeeID),
+		CreateTime: time.Now(),
+		UpdateTime: time.Now(),
+	}
+	if _, err := l.svcCtx.OutOfOfficeModel.Insert(l.ctx, record); err != nil {
+		l.Logger.Errorf("创建外出记录失败: %v", err)
+		return utils.Response.InternalError("设置外出代理失败"), nil
+	}
+
+	// 6. 已到开始时间的立即生效，转交待处理的审批
+	if !startTime.After(time.Now()) {
+		if err := l.svcCtx.OutOfOfficeService.Activate(l.ctx, record); err != nil {
+			// 转交失败不影响设置结果，定时任务会重试
+			l.Logger.Errorf("外出代理立即生效失败: id=%s, err=%v", record.Id, err)
+		}
+	}
+
+	l.Logger.Infof("员工 %s 设置外出代理: delegate=%s, %s ~ %s", employee.RealName, delegate.RealName, req.StartTime, req.EndTime)
+
+	return utils.Response.SuccessWithKey("operation", toOutOfOfficeInfo(l.ctx, l.svcCtx, record)), nil
+}
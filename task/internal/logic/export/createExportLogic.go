@@ -0,0 +1,65 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package export
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建报表导出任务
+func NewCreateExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateExportLogic {
+	return &CreateExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateExportLogic) CreateExport(req *types.CreateExportRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if svc.ExportTypeTitle(req.ExportType) == "" {
+		return utils.Response.BusinessError("export_type_invalid"), nil
+	}
+	if !svc.IsExportFormat(req.Format) {
+		return utils.Response.BusinessError("export_format_invalid"), nil
+	}
+
+	build, errResp := buildExportSource(l.ctx, l.svcCtx, operator, req)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	params, _ := json.Marshal(req)
+	job := &task.ExportJob{
+		Id:         utils.Common.GenId("export"),
+		CompanyId:  operator.CompanyId,
+		EmployeeId: operator.Id,
+		ExportType: req.ExportType,
+		Format:     req.Format,
+		Params:     sql.NullString{String: string(params), Valid: true},
+	}
+	if err := l.svcCtx.ExportService.Submit(l.ctx, job, build); err != nil {
+		l.Logger.Errorf("创建导出任务失败: %v", err)
+		return utils.Response.InternalError("创建导出任务失败"), nil
+	}
+	return utils.Response.SuccessWithMessage("导出任务已创建，文件生成后会通知您下载", toExportJobInfo(job)), nil
+}
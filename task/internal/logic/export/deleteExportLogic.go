@@ -0,0 +1,45 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package export
+
+import (
+	"context"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除导出记录及文件
+func NewDeleteExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteExportLogic {
+	return &DeleteExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteExportLogic) DeleteExport(req *types.DeleteExportRequest) (resp *types.BaseResponse, err error) {
+	job, errResp := loadOwnExportJob(l.ctx, l.svcCtx, req.JobID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if job.Status == task.ExportStatusPending || job.Status == task.ExportStatusRunning {
+		return utils.Response.BusinessError("export_not_finished"), nil
+	}
+	if err := l.svcCtx.ExportService.Remove(l.ctx, job); err != nil {
+		l.Logger.Errorf("删除导出记录失败: jobId=%s, err=%v", job.Id, err)
+		return utils.Response.InternalError("删除导出记录失败"), nil
+	}
+	return utils.Response.SuccessWithMessage("删除成功", nil), nil
+}
@@ -0,0 +1,234 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// loadOwnExportJob 查询当前员工发起的导出任务
+func loadOwnExportJob(ctx context.Context, svcCtx *svc.ServiceContext, jobID string) (*task.ExportJob, *types.BaseResponse) {
+	employee, errResp := svcCtx.CurrentOperator(ctx)
+	if errResp != nil {
+		return nil, errResp
+	}
+	job, err := svcCtx.ExportJobModel.FindOne(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, utils.Response.BusinessError("export_not_found")
+		}
+		return nil, utils.Response.InternalError("查询导出记录失败")
+	}
+	if job.EmployeeId != employee.Id {
+		return nil, utils.Response.BusinessError("export_not_found")
+	}
+	return job, nil
+}
+
+// checkExportDepartment 校验部门属于当前公司；requireManager 为 true 时非管理人员只能导出自己担任经理的部门
+func checkExportDepartment(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, departmentID string, requireManager bool) *types.BaseResponse {
+	dept, err := svcCtx.DepartmentModel.FindOne(ctx, departmentID)
+	if err != nil || dept.CompanyId != employee.CompanyId {
+		return utils.Response.BusinessError("department_not_found")
+	}
+	if !requireManager || svcCtx.IsCompanyAdmin(ctx, employee) {
+		return nil
+	}
+	if dept.ManagerId.Valid && dept.ManagerId.String == employee.Id {
+		return nil
+	}
+	return utils.Response.BusinessError("export_no_permission")
+}
+
+// loadExportTask 校验当前员工可以导出任务数据：同公司，且是任务成员、节点参与者或公司管理人员
+func loadExportTask(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, taskID string) (*task.Task, *types.BaseResponse) {
+	if taskID == "" {
+		return nil, utils.Response.BusinessError("export_task_required")
+	}
+	taskInfo, err := svcCtx.TaskModel.FindOne(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, utils.Response.BusinessError("task_not_found")
+		}
+		return nil, utils.Response.InternalError("获取任务信息失败")
+	}
+	if taskInfo.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("export_no_permission")
+	}
+	if taskInfo.TaskCreator == employee.Id || taskInfo.LeaderId.String == employee.Id ||
+		taskInfo.TaskAssigner.String == employee.Id || utils.Common.ContainsID(taskInfo.ResponsibleEmployeeIds.String, employee.Id) ||
+		svcCtx.IsCompanyAdmin(ctx, employee) {
+		return taskInfo, nil
+	}
+	nodes, err := svcCtx.TaskNodeModel.FindByTaskID(ctx, taskID)
+	if err == nil {
+		for _, node := range nodes {
+			if utils.Common.ContainsID(node.ExecutorId, employee.Id) || node.LeaderId == employee.Id {
+				return taskInfo, nil
+			}
+		}
+	}
+	return nil, utils.Response.BusinessError("export_no_permission")
+}
+
+// employeeNamer 按员工ID查询姓名（带缓存），支持逗号分隔的多个ID
+func employeeNamer(ctx context.Context, svcCtx *svc.ServiceContext) func(string) string {
+	names := make(map[string]string)
+	return func(ids string) string {
+		list := make([]string, 0, 1)
+		for _, id := range strings.Split(ids, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			name, ok := names[id]
+			if !ok {
+				if emp, err := svcCtx.EmployeeModel.FindOne(ctx, id); err == nil {
+					name = emp.RealName
+				}
+				names[id] = name
+			}
+			if name != "" {
+				list = append(list, name)
+			}
+		}
+		return strings.Join(list, "、")
+	}
+}
+
+// departmentNamer 按部门ID查询部门名称（带缓存）
+func departmentNamer(ctx context.Context, svcCtx *svc.ServiceContext) func(string) string {
+	names := make(map[string]string)
+	return func(id string) string {
+		if id == "" {
+			return ""
+		}
+		if name, ok := names[id]; ok {
+			return name
+		}
+		name := ""
+		if dept, err := svcCtx.DepartmentModel.FindOne(ctx, id); err == nil {
+			name = dept.DepartmentName
+		}
+		names[id] = name
+		return name
+	}
+}
+
+// positionNamer 按职位ID查询职位名称（带缓存）
+func positionNamer(ctx context.Context, svcCtx *svc.ServiceContext) func(string) string {
+	names := make(map[string]string)
+	return func(id string) string {
+		if id == "" {
+			return ""
+		}
+		if name, ok := names[id]; ok {
+			return name
+		}
+		name := ""
+		if pos, err := svcCtx.PositionModel.FindOne(ctx, id); err == nil {
+			name = pos.PositionName
+		}
+		names[id] = name
+		return name
+	}
+}
+
+// formatExportTime 格式化时间，零值返回空字符串
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return utils.Common.FormatTime(t)
+}
+
+// toExportJobInfo 转换导出任务
+func toExportJobInfo(job *task.ExportJob) types.ExportJobInfo {
+	info := types.ExportJobInfo{
+		ID:             job.Id,
+		ExportType:     job.ExportType,
+		ExportTypeName: svc.ExportTypeTitle(job.ExportType),
+		Format:         job.Format,
+		Status:         job.Status,
+		FileName:       job.FileName.String,
+		FileSize:       job.FileSize,
+		RowCount:       job.RowCount,
+		ErrorMessage:   job.ErrorMessage.String,
+		CreateTime:     formatExportTime(job.CreateTime),
+	}
+	if job.Status == task.ExportStatusDone {
+		info.FileUrl = job.FileUrl.String
+	}
+	if job.FinishTime.Valid {
+		info.FinishTime = formatExportTime(job.FinishTime.Time)
+	}
+	return info
+}
+
+// customFieldColumns 导出表格中的标签和自定义字段列
+type customFieldColumns struct {
+	fields []*task.CustomField
+	values map[string]map[string]string
+	labels map[string][]string // 为 nil 时不输出标签列
+	format func(field *task.CustomField, value string) string
+}
+
+// loadCustomFieldColumns 查询对象的自定义字段值；任务对象同时查询标签
+func loadCustomFieldColumns(ctx context.Context, svcCtx *svc.ServiceContext, companyID, entityType string, entityIDs []string) (*customFieldColumns, error) {
+	fields, err := svcCtx.CustomFieldModel.FindByCompany(ctx, companyID, entityType)
+	if err != nil {
+		return nil, err
+	}
+	values, err := svcCtx.CustomFieldService.LoadValues(ctx, entityIDs)
+	if err != nil {
+		return nil, err
+	}
+	nameOf := employeeNamer(ctx, svcCtx)
+	cols := &customFieldColumns{
+		fields: fields,
+		values: values,
+		format: func(field *task.CustomField, value string) string {
+			return svcCtx.CustomFieldService.FormatValue(field, value, nameOf)
+		},
+	}
+	if entityType == task.CustomFieldEntityTask {
+		refs, err := svcCtx.TaskLabelModel.FindByTaskIds(ctx, entityIDs)
+		if err != nil {
+			return nil, err
+		}
+		cols.labels = make(map[string][]string)
+		for _, r := range refs {
+			cols.labels[r.TaskId] = append(cols.labels[r.TaskId], r.Name)
+		}
+	}
+	return cols, nil
+}
+
+func (c *customFieldColumns) headers() []string {
+	headers := make([]string, 0, len(c.fields)+1)
+	if c.labels != nil {
+		headers = append(headers, "标签")
+	}
+	for _, field := range c.fields {
+		headers = append(headers, field.Name)
+	}
+	return headers
+}
+
+func (c *customFieldColumns) row(entityID string) []string {
+	row := make([]string, 0, len(c.fields)+1)
+	if c.labels != nil {
+		row = append(row, strings.Join(c.labels[entityID], "、"))
+	}
+	for _, field := range c.fields {
+		row = append(row, c.format(field, c.values[entityID][field.Id]))
+	}
+	return row
+}
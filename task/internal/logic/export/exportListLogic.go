@@ -0,0 +1,52 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package export
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ExportListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 我的导出记录
+func NewExportListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExportListLogic {
+	return &ExportListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ExportListLogic) ExportList(req *types.ExportListRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	validator := utils.NewValidator()
+	page, pageSize, errs := validator.ValidatePageParams(req.Page, req.PageSize)
+	if len(errs) > 0 {
+		return utils.Response.ValidationError(errs[0]), nil
+	}
+
+	jobs, total, err := l.svcCtx.ExportJobModel.FindByEmployee(l.ctx, operator.Id, page, pageSize)
+	if err != nil {
+		l.Logger.Errorf("查询导出记录失败: %v", err)
+		return utils.Response.InternalError("查询导出记录失败"), nil
+	}
+	list := make([]types.ExportJobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, toExportJobInfo(job))
+	}
+	return utils.Response.Success(utils.NewConverter().ToPageResponse(list, int(total), page, pageSize)), nil
+}
@@ -0,0 +1,559 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 仪表盘统计导出的趋势天数
+const exportTrendDays = 30
+
+// buildExportSource 按导出内容校验权限（在提交时同步完成），返回在后台执行的数据查询
+func buildExportSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	switch req.ExportType {
+	case svc.ExportTypeTaskList:
+		return taskListSource(ctx, svcCtx, operator, req)
+	case svc.ExportTypeNodeList:
+		return nodeListSource(ctx, svcCtx, operator, req)
+	case svc.ExportTypeEmployeeList:
+		return employeeListSource(ctx, svcCtx, operator, req)
+	case svc.ExportTypeHandoverList:
+		return handoverListSource(ctx, svcCtx, operator, req)
+	case svc.ExportTypeDashboardStats:
+		return dashboardStatsSource(ctx, svcCtx, operator, req)
+	case svc.ExportTypeTaskReport:
+		return taskReportSource(ctx, svcCtx, operator, req)
+	}
+	return nil, utils.Response.BusinessError("export_type_invalid")
+}
+
+// taskListSource 任务列表：默认为自己参与的任务，scope=company 导出全公司（仅管理人员），指定部门时导出部门任务
+func taskListSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	switch {
+	case req.DepartmentID != "":
+		if errResp := checkExportDepartment(ctx, svcCtx, operator, req.DepartmentID, false); errResp != nil {
+			return nil, errResp
+		}
+	case req.Scope == "company":
+		if !svcCtx.IsCompanyAdmin(ctx, operator) {
+			return nil, utils.Response.BusinessError("export_no_permission")
+		}
+	case req.Scope != "" && req.Scope != "involved":
+		return nil, utils.Response.BusinessError("export_scope_invalid")
+	}
+	limit := svcCtx.ExportService.MaxRows() + 1
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		var tasks []*task.Task
+		var err error
+		switch {
+		case req.DepartmentID != "":
+			tasks, _, err = svcCtx.TaskModel.FindByDepartment(ctx, req.DepartmentID, 1, limit)
+		case req.Scope == "company":
+			tasks, _, err = svcCtx.TaskModel.FindByCompany(ctx, operator.CompanyId, 1, limit)
+		default:
+			tasks, _, err = svcCtx.TaskModel.FindByInvolved(ctx, operator.Id, 1, limit)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		filtered := make([]*task.Task, 0, len(tasks))
+		taskIDs := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			if t.CompanyId != operator.CompanyId {
+				continue
+			}
+			if req.Status >= 0 && t.TaskStatus != int64(req.Status) {
+				continue
+			}
+			if req.Priority >= 0 && t.TaskPriority != int64(req.Priority) {
+				continue
+			}
+			if req.Keyword != "" && !strings.Contains(t.TaskTitle, req.Keyword) {
+				continue
+			}
+			filtered = append(filtered, t)
+			taskIDs = append(taskIDs, t.TaskId)
+		}
+		extra, err := loadCustomFieldColumns(ctx, svcCtx, operator.CompanyId, task.CustomFieldEntityTask, taskIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		nameOf := employeeNamer(ctx, svcCtx)
+		sheet := svc.ExportSheet{
+			Name: "任务列表",
+			Headers: append([]string{"任务ID", "任务标题", "类型", "优先级", "状态", "进度(%)", "负责人", "创建人",
+				"节点完成", "开始时间", "截止时间", "创建时间"}, extra.headers()...),
+		}
+		for _, t := range filtered {
+			sheet.Rows = append(sheet.Rows, append([]string{
+				t.TaskId, t.TaskTitle, taskTypeText(t.TaskType), taskPriorityText(t.TaskPriority), taskStatusText(t.TaskStatus),
+				strconv.FormatInt(t.TaskProgress, 10), nameOf(taskLeaderIDs(t)), nameOf(t.TaskCreator),
+				fmt.Sprintf("%d/%d", t.CompletedNodeCount, t.TotalNodeCount),
+				formatExportTime(t.TaskStartTime), formatExportTime(t.TaskDeadline), formatExportTime(t.CreateTime),
+			}, extra.row(t.TaskId)...))
+		}
+		return &svc.ExportDocument{Title: svc.ExportTypeTitle(svc.ExportTypeTaskList), Sheets: []svc.ExportSheet{sheet}}, nil
+	}, nil
+}
+
+// nodeListSource 任务的节点列表，可按部门和节点状态筛选
+func nodeListSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	taskInfo, errResp := loadExportTask(ctx, svcCtx, operator, req.TaskID)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		nodes, err := svcCtx.TaskNodeModel.FindByTaskID(ctx, taskInfo.TaskId)
+		if err != nil {
+			return nil, err
+		}
+		filtered := make([]*task.TaskNode, 0, len(nodes))
+		for _, node := range nodes {
+			if req.DepartmentID != "" && node.DepartmentId != req.DepartmentID {
+				continue
+			}
+			if req.Status >= 0 && node.NodeStatus != int64(req.Status) {
+				continue
+			}
+			filtered = append(filtered, node)
+		}
+		sheet, err := nodeSheet(ctx, svcCtx, taskInfo.CompanyId, filtered)
+		if err != nil {
+			return nil, err
+		}
+		return &svc.ExportDocument{Title: taskInfo.TaskTitle + "_" + svc.ExportTypeTitle(svc.ExportTypeNodeList), Sheets: []svc.ExportSheet{sheet}}, nil
+	}, nil
+}
+
+// employeeListSource 员工列表：管理人员可以导出全公司或任意部门，部门经理只能导出本部门
+func employeeListSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	if req.DepartmentID != "" {
+		if errResp := checkExportDepartment(ctx, svcCtx, operator, req.DepartmentID, true); errResp != nil {
+			return nil, errResp
+		}
+	} else if !svcCtx.IsCompanyAdmin(ctx, operator) {
+		return nil, utils.Response.BusinessError("export_no_permission")
+	}
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		var employees []*user.Employee
+		var err error
+		if req.DepartmentID != "" {
+			employees, err = svcCtx.EmployeeModel.FindByDepartmentID(ctx, req.DepartmentID)
+		} else {
+			employees, err = svcCtx.EmployeeModel.FindByCompanyID(ctx, operator.CompanyId)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		deptOf := departmentNamer(ctx, svcCtx)
+		positionOf := positionNamer(ctx, svcCtx)
+		sheet := svc.ExportSheet{
+			Name:    "员工列表",
+			Headers: []string{"工号", "姓名", "部门", "职位", "工作邮箱", "工作电话", "技能", "状态", "入职日期", "离职日期"},
+		}
+		for _, e := range employees {
+			if e.CompanyId != operator.CompanyId || e.DeleteTime.Valid {
+				continue
+			}
+			if req.PositionID != "" && e.PositionId.String != req.PositionID {
+				continue
+			}
+			if req.Status >= 0 && e.Status != int64(req.Status) {
+				continue
+			}
+			if req.Keyword != "" && !strings.Contains(e.RealName, req.Keyword) {
+				continue
+			}
+			row := []string{e.EmployeeId, e.RealName, deptOf(e.DepartmentId.String), positionOf(e.PositionId.String),
+				e.Email.String, e.Phone.String, e.Skills.String, employeeStatusText(e.Status), "", ""}
+			if e.HireDate.Valid {
+				row[8] = utils.Common.FormatDate(e.HireDate.Time)
+			}
+			if e.LeaveDate.Valid {
+				row[9] = utils.Common.FormatDate(e.LeaveDate.Time)
+			}
+			sheet.Rows = append(sheet.Rows, row)
+		}
+		return &svc.ExportDocument{Title: svc.ExportTypeTitle(svc.ExportTypeEmployeeList), Sheets: []svc.ExportSheet{sheet}}, nil
+	}, nil
+}
+
+// handoverListSource 与当前员工相关的交接记录（发起人、接收人或审批人），可按任务和交接状态筛选
+func handoverListSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	limit := svcCtx.ExportService.MaxRows() + 1
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		handovers, _, err := svcCtx.TaskHandoverModel.FindByEmployeeInvolved(ctx, operator.Id, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		nameOf := employeeNamer(ctx, svcCtx)
+		titles := make(map[string]string)
+		sheet := svc.ExportSheet{
+			Name:    "交接记录",
+			Headers: []string{"交接ID", "任务", "交出人", "接收人", "审批人", "交接类型", "状态", "交接原因", "交接备注", "发起时间", "审批时间"},
+		}
+		for _, h := range handovers {
+			if req.TaskID != "" && h.TaskId != req.TaskID {
+				continue
+			}
+			if req.Status >= 0 && h.HandoverStatus != int64(req.Status) {
+				continue
+			}
+			title, ok := titles[h.TaskId]
+			if !ok {
+				title = "离职审批"
+				if h.TaskId != "" {
+					title = ""
+					if t, err := svcCtx.TaskModel.FindOne(ctx, h.TaskId); err == nil {
+						title = t.TaskTitle
+					}
+				}
+				titles[h.TaskId] = title
+			}
+			approveTime := ""
+			if h.ApproveTime.Valid {
+				approveTime = formatExportTime(h.ApproveTime.Time)
+			}
+			sheet.Rows = append(sheet.Rows, []string{
+				h.HandoverId, title, nameOf(h.FromEmployeeId), nameOf(h.ToEmployeeId), nameOf(h.ApproverId.String),
+				handoverTypeText(h.HandoverType), handoverStatusText(h.HandoverStatus), h.HandoverReason.String, h.HandoverNote.String,
+				formatExportTime(h.CreateTime), approveTime,
+			})
+		}
+		return &svc.ExportDocument{Title: svc.ExportTypeTitle(svc.ExportTypeHandoverList), Sheets: []svc.ExportSheet{sheet}}, nil
+	}, nil
+}
+
+// dashboardStatsSource 仪表盘统计：个人指标（scope=department 时附带部门指标）和最近 30 天的任务趋势
+func dashboardStatsSource(_ context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	if req.Scope != "" && req.Scope != "personal" && req.Scope != "department" {
+		return nil, utils.Response.BusinessError("export_scope_invalid")
+	}
+	departmentID := ""
+	if req.Scope == "department" && operator.DepartmentId.Valid {
+		departmentID = operator.DepartmentId.String
+	}
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		personal, err := svcCtx.AnalyticsService.Snapshot(ctx, operator.CompanyId, task.StatsScopeEmployee, operator.Id)
+		if err != nil {
+			return nil, err
+		}
+		snapshots := []*task.StatsSnapshot{personal}
+		headers := []string{"指标", "个人"}
+		trendScope, trendID := task.StatsScopeEmployee, operator.Id
+		if departmentID != "" {
+			dept, err := svcCtx.AnalyticsService.Snapshot(ctx, operator.CompanyId, task.StatsScopeDepartment, departmentID)
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, dept)
+			headers = append(headers, "部门")
+			trendScope, trendID = task.StatsScopeDepartment, departmentID
+		}
+
+		metrics := []struct {
+			name  string
+			value func(s *task.StatsSnapshot) int64
+		}{
+			{"任务节点总数", func(s *task.StatsSnapshot) int64 { return s.TotalCount }},
+			{"已完成", func(s *task.StatsSnapshot) int64 { return s.TotalCompletedCount }},
+			{"未完成", func(s *task.StatsSnapshot) int64 { return s.OpenCount }},
+			{"未完成预计天数", func(s *task.StatsSnapshot) int64 { return s.OpenEstimatedDays }},
+			{"逾期未完成", func(s *task.StatsSnapshot) int64 { return s.OverdueCount }},
+			{"紧急未完成", func(s *task.StatsSnapshot) int64 { return s.CriticalCount }},
+			{"平均完成天数", func(s *task.StatsSnapshot) int64 { return s.AvgCycleDays() }},
+			{"按时完成率(%)", func(s *task.StatsSnapshot) int64 { return s.OnTimeRate() }},
+			{"活跃成员数", func(s *task.StatsSnapshot) int64 { return s.ActiveMemberCount }},
+		}
+		summary := svc.ExportSheet{Name: "统计指标", Headers: headers}
+		for _, m := range metrics {
+			row := []string{m.name}
+			for _, s := range snapshots {
+				row = append(row, strconv.FormatInt(m.value(s), 10))
+			}
+			summary.Rows = append(summary.Rows, row)
+		}
+
+		trendRows, err := svcCtx.AnalyticsService.Trend(ctx, trendScope, trendID, exportTrendDays)
+		if err != nil {
+			return nil, err
+		}
+		trend := svc.ExportSheet{Name: "任务趋势", Headers: []string{"日期", "新增", "完成", "未完成", "逾期"}}
+		for _, r := range trendRows {
+			trend.Rows = append(trend.Rows, []string{
+				utils.Common.FormatDate(r.SnapshotDate), strconv.FormatInt(r.CreatedCount, 10), strconv.FormatInt(r.CompletedCount, 10),
+				strconv.FormatInt(r.OpenCount, 10), strconv.FormatInt(r.OverdueCount, 10),
+			})
+		}
+		return &svc.ExportDocument{Title: svc.ExportTypeTitle(svc.ExportTypeDashboardStats), Sheets: []svc.ExportSheet{summary, trend}}, nil
+	}, nil
+}
+
+// taskReportSource 单个任务的状态报告：任务概览、节点、清单和任务日志
+func taskReportSource(ctx context.Context, svcCtx *svc.ServiceContext, operator *user.Employee, req *types.CreateExportRequest) (svc.ExportBuilder, *types.BaseResponse) {
+	taskInfo, errResp := loadExportTask(ctx, svcCtx, operator, req.TaskID)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	return func(ctx context.Context) (*svc.ExportDocument, error) {
+		nodes, err := svcCtx.TaskNodeModel.FindByTaskID(ctx, taskInfo.TaskId)
+		if err != nil {
+			return nil, err
+		}
+		checklists, err := svcCtx.TaskChecklistModel.FindByTaskId(ctx, taskInfo.TaskId)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := svcCtx.TaskLogModel.FindByTaskID(ctx, taskInfo.TaskId)
+		if err != nil {
+			return nil, err
+		}
+
+		nameOf := employeeNamer(ctx, svcCtx)
+		nodeNames := make(map[string]string, len(nodes))
+		for _, node := range nodes {
+			nodeNames[node.TaskNodeId] = node.NodeName
+		}
+		var doneChecklists int
+		for _, c := range checklists {
+			if c.IsCompleted == 1 {
+				doneChecklists++
+			}
+		}
+
+		overview := svc.ExportSheet{Name: "任务概览", Headers: []string{"项目", "内容"}}
+		overview.Rows = [][]string{
+			{"任务标题", taskInfo.TaskTitle},
+			{"任务ID", taskInfo.TaskId},
+			{"状态", taskStatusText(taskInfo.TaskStatus)},
+			{"优先级", taskPriorityText(taskInfo.TaskPriority)},
+			{"类型", taskTypeText(taskInfo.TaskType)},
+			{"进度(%)", strconv.FormatInt(taskInfo.TaskProgress, 10)},
+			{"负责人", nameOf(taskLeaderIDs(taskInfo))},
+			{"创建人", nameOf(taskInfo.TaskCreator)},
+			{"开始时间", formatExportTime(taskInfo.TaskStartTime)},
+			{"截止时间", formatExportTime(taskInfo.TaskDeadline)},
+			{"节点完成", fmt.Sprintf("%d/%d", taskInfo.CompletedNodeCount, taskInfo.TotalNodeCount)},
+			{"清单完成", fmt.Sprintf("%d/%d", doneChecklists, len(checklists))},
+			{"预计工时(小时)", formatHours(taskInfo.EstimatedHours.Float64, taskInfo.EstimatedHours.Valid)},
+			{"实际工时(小时)", formatHours(taskInfo.ActualHours.Float64, taskInfo.ActualHours.Valid)},
+			{"任务详情", taskInfo.TaskDetail},
+		}
+		extra, err := loadCustomFieldColumns(ctx, svcCtx, taskInfo.CompanyId, task.CustomFieldEntityTask, []string{taskInfo.TaskId})
+		if err != nil {
+			return nil, err
+		}
+		extraValues := extra.row(taskInfo.TaskId)
+		for i, header := range extra.headers() {
+			overview.Rows = append(overview.Rows, []string{header, extraValues[i]})
+		}
+		nodesSheet, err := nodeSheet(ctx, svcCtx, taskInfo.CompanyId, nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		checklistSheet := svc.ExportSheet{Name: "任务清单", Headers: []string{"节点", "清单内容", "状态", "创建人", "完成时间", "创建时间"}}
+		for _, c := range checklists {
+			status, completeTime := "未完成", ""
+			if c.IsCompleted == 1 {
+				status = "已完成"
+			}
+			if c.CompleteTime.Valid {
+				completeTime = formatExportTime(c.CompleteTime.Time)
+			}
+			checklistSheet.Rows = append(checklistSheet.Rows, []string{
+				nodeNames[c.TaskNodeId], c.Content, status, nameOf(c.CreatorId), completeTime, formatExportTime(c.CreateTime),
+			})
+		}
+
+		logSheet := svc.ExportSheet{Name: "任务日志", Headers: []string{"时间", "节点", "操作人", "类型", "内容", "进度(%)"}}
+		for _, log := range logs {
+			progress := ""
+			if log.Progress.Valid {
+				progress = strconv.FormatInt(log.Progress.Int64, 10)
+			}
+			logSheet.Rows = append(logSheet.Rows, []string{
+				formatExportTime(log.CreateTime), nodeNames[log.TaskNodeId.String], nameOf(log.EmployeeId),
+				taskLogTypeText(log.LogType), log.LogContent, progress,
+			})
+		}
+
+		return &svc.ExportDocument{
+			Title:  taskInfo.TaskTitle + "_" + svc.ExportTypeTitle(svc.ExportTypeTaskReport),
+			Sheets: []svc.ExportSheet{overview, nodesSheet, checklistSheet, logSheet},
+		}, nil
+	}, nil
+}
+
+// nodeSheet 任务节点表格，包含节点的自定义字段列
+func nodeSheet(ctx context.Context, svcCtx *svc.ServiceContext, companyID string, nodes []*task.TaskNode) (svc.ExportSheet, error) {
+	nodeIDs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, node.TaskNodeId)
+	}
+	extra, err := loadCustomFieldColumns(ctx, svcCtx, companyID, task.CustomFieldEntityNode, nodeIDs)
+	if err != nil {
+		return svc.ExportSheet{}, err
+	}
+	nameOf := employeeNamer(ctx, svcCtx)
+	deptOf := departmentNamer(ctx, svcCtx)
+	sheet := svc.ExportSheet{
+		Name: "任务节点",
+		Headers: append([]string{"节点ID", "节点名称", "部门", "执行人", "负责人", "状态", "进度(%)", "优先级",
+			"预计天数", "开始时间", "截止时间", "完成时间"}, extra.headers()...),
+	}
+	for _, node := range nodes {
+		finishTime := ""
+		if node.NodeFinishTime.Valid {
+			finishTime = formatExportTime(node.NodeFinishTime.Time)
+		}
+		sheet.Rows = append(sheet.Rows, append([]string{
+			node.TaskNodeId, node.NodeName, deptOf(node.DepartmentId), nameOf(node.ExecutorId), nameOf(node.LeaderId),
+			svc.NodeStatusText(node.NodeStatus), strconv.FormatInt(node.Progress, 10), nodePriorityText(node.NodePriority),
+			strconv.FormatInt(node.EstimatedDays, 10), formatExportTime(node.NodeStartTime), formatExportTime(node.NodeDeadline), finishTime,
+		}, extra.row(node.TaskNodeId)...))
+	}
+	return sheet, nil
+}
+
+// taskLeaderIDs 任务负责人：优先使用负责人字段，否则使用负责人员工列表
+func taskLeaderIDs(t *task.Task) string {
+	if t.LeaderId.Valid && t.LeaderId.String != "" {
+		return t.LeaderId.String
+	}
+	return t.ResponsibleEmployeeIds.String
+}
+
+func formatHours(hours float64, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return strconv.FormatFloat(hours, 'f', -1, 64)
+}
+
+func taskStatusText(status int64) string {
+	switch status {
+	case 0:
+		return "未开始"
+	case 1:
+		return "进行中"
+	case 2:
+		return "已完成"
+	case 3:
+		return "逾期完成"
+	}
+	return "未知"
+}
+
+func taskPriorityText(priority int64) string {
+	switch priority {
+	case 0:
+		return "不重要不紧急"
+	case 1:
+		return "紧急不重要"
+	case 2:
+		return "重要但不紧急"
+	case 3:
+		return "重要且紧急"
+	}
+	return "未知"
+}
+
+func taskTypeText(taskType int64) string {
+	if taskType == 1 {
+		return "跨部门任务"
+	}
+	return "单部门任务"
+}
+
+func nodePriorityText(priority int64) string {
+	switch priority {
+	case 0:
+		return "低"
+	case 1:
+		return "中"
+	case 2:
+		return "高"
+	case 3:
+		return "紧急"
+	}
+	return "未知"
+}
+
+func employeeStatusText(status int64) string {
+	switch status {
+	case 0:
+		return "离职"
+	case 1:
+		return "在职"
+	case 2:
+		return "请假"
+	}
+	return "未知"
+}
+
+func handoverTypeText(handoverType int64) string {
+	switch handoverType {
+	case 0:
+		return "提议"
+	case 1:
+		return "直接交接"
+	case 2:
+		return "系统自动"
+	}
+	return "未知"
+}
+
+func handoverStatusText(status int64) string {
+	switch status {
+	case 0:
+		return "待接收人确认"
+	case 1:
+		return "待上级审批"
+	case 2:
+		return "已通过"
+	case 3:
+		return "已拒绝"
+	}
+	return "未知"
+}
+
+func taskLogTypeText(logType int64) string {
+	switch logType {
+	case 1:
+		return "创建"
+	case 2:
+		return "更新"
+	case 3:
+		return "完成"
+	case 4:
+		return "删除"
+	case 5:
+		return "交接请求"
+	case 6:
+		return "交接审批"
+	case 7:
+		return "交接确认"
+	case task.TaskLogTypeStatus:
+		return "状态变更"
+	}
+	return "其他"
+}
@@ -0,0 +1,37 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package export
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询导出任务
+func NewGetExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetExportLogic {
+	return &GetExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetExportLogic) GetExport(req *types.GetExportRequest) (resp *types.BaseResponse, err error) {
+	job, errResp := loadOwnExportJob(l.ctx, l.svcCtx, req.JobID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	return utils.Response.Success(toExportJobInfo(job)), nil
+}
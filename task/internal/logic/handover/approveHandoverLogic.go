@@ -75,7 +75,8 @@ func (l *ApproveHandoverLogic) ApproveHandover(req *types.ApproveHandoverRequest
 
 	// 5. 验证是否有审批权限
 	// 使用审批人查找器验证权限
-	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel)
+	approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel).
+		WithDelegateResolver(l.svcCtx.OutOfOfficeService.ResolveDelegate)
 	hasApprovalPermission := false
 	var approvalRole string
 
@@ -200,7 +201,18 @@ skipInsertApproval:
 
 	// 9. 如果通过，更新任务和任务节点的相关人员
 	if newStatus == 2 {
+		var items []*taskModel.TaskHandoverItem
 		if handover.TaskId != "" {
+			items, err = l.svcCtx.TaskHandoverItemModel.FindByHandoverId(l.ctx, handover.HandoverId)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(items) > 0 {
+			// 部分交接：只转移交接事项
+			l.transferItems(handover, items, currentEmployeeID)
+		} else if handover.TaskId != "" {
 			// 普通任务交接：更新指定任务的相关人员
 			l.Logger.WithContext(l.ctx).Infof("开始更新任务相关人员: 从 %s 转移到 %s", handover.FromEmployeeId, handover.ToEmployeeId)
 
@@ -346,10 +358,12 @@ skipInsertApproval:
 				l.Logger.WithContext(l.ctx).Infof("离职员工没有需要交接的任务节点")
 			}
 
-			// 9.4 更新用户的 has_joined_company 为 0
+			// 9.4 更新用户的 has_joined_company 为 0（仍在其他公司任职时保留）
 			fromEmployee, fromEmpErr := l.svcCtx.EmployeeModel.FindOne(l.ctx, handover.FromEmployeeId)
 			if fromEmpErr == nil && fromEmployee.UserId != "" {
-				if userErr := l.svcCtx.UserModel.UpdateHasJoinedCompany(l.ctx, fromEmployee.UserId, false); userErr != nil {
+				if hasOther, _ := l.svcCtx.EmployeeModel.HasOtherActiveMembership(l.ctx, fromEmployee.UserId, fromEmployee.Id); hasOther {
+					l.Logger.WithContext(l.ctx).Infof("用户 %s 仍在其他公司任职，保留 has_joined_company", fromEmployee.UserId)
+				} else if userErr := l.svcCtx.UserModel.UpdateHasJoinedCompany(l.ctx, fromEmployee.UserId, false); userErr != nil {
 					l.Logger.WithContext(l.ctx).Errorf("更新用户 has_joined_company 失败: %v", userErr)
 				} else {
 					l.Logger.WithContext(l.ctx).Infof("用户 %s 的 has_joined_company 已更新为 0", fromEmployee.UserId)
@@ -462,3 +476,154 @@ func (l *ApproveHandoverLogic) replaceEmployeeIdInList(idList, oldId, newId stri
 
 	return strings.Join(newIds, ",")
 }
+
+// transferItems 部分交接：逐项转移交接事项，原归属已变化的事项跳过，每个已转移的事项单独记录任务日志
+func (l *ApproveHandoverLogic) transferItems(handover *taskModel.TaskHandover, items []*taskModel.TaskHandoverItem, operatorID string) {
+	fromName, toName := handover.FromEmployeeId, handover.ToEmployeeId
+	if emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, handover.FromEmployeeId); err == nil {
+		fromName = emp.RealName
+	}
+	if emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, handover.ToEmployeeId); err == nil {
+		toName = emp.RealName
+	}
+
+	nodeRoleTransferred := false
+	for _, item := range items {
+		if item.Status != taskModel.HandoverItemPending {
+			continue
+		}
+		remark := l.transferItem(handover, item)
+		status := int64(taskModel.HandoverItemTransferred)
+		if remark != "" {
+			status = taskModel.HandoverItemSkipped
+		}
+		if err := l.svcCtx.TaskHandoverItemModel.UpdateStatus(l.ctx, item.Id, status, remark); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("更新交接事项状态失败: id=%s, err=%v", item.Id, err)
+		}
+		if remark != "" {
+			l.Logger.WithContext(l.ctx).Infof("跳过交接事项 %s: %s", item.Id, remark)
+			continue
+		}
+		if item.ItemType == taskModel.HandoverItemNodeExecutor || item.ItemType == taskModel.HandoverItemNodeLeader {
+			nodeRoleTransferred = true
+		}
+
+		taskLog := &taskModel.TaskLog{
+			LogId:      utils.Common.GenerateID(),
+			TaskId:     handover.TaskId,
+			TaskNodeId: utils.Common.ToSqlNullString(item.TaskNodeId),
+			LogType:    6, // 交接审批
+			LogContent: fmt.Sprintf("交接%s: %s -> %s", describeHandoverItem(item), fromName, toName),
+			EmployeeId: operatorID,
+			CreateTime: time.Now(),
+		}
+		if _, err := l.svcCtx.TaskLogModel.Insert(l.ctx, taskLog); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("创建交接事项日志失败: %v", err)
+		}
+	}
+
+	if nodeRoleTransferred {
+		l.syncTaskNodeEmployees(handover)
+	}
+}
+
+// transferItem 转移单个交接事项，返回跳过原因，转移成功时返回空字符串
+func (l *ApproveHandoverLogic) transferItem(handover *taskModel.TaskHandover, item *taskModel.TaskHandoverItem) string {
+	from, to := handover.FromEmployeeId, handover.ToEmployeeId
+	switch item.ItemType {
+	case taskModel.HandoverItemNodeExecutor, taskModel.HandoverItemNodeLeader:
+		node, err := l.svcCtx.TaskNodeModel.FindOne(l.ctx, item.TargetId)
+		if err != nil {
+			return "节点不存在"
+		}
+		holders := &node.ExecutorId
+		roleName := "执行人"
+		if item.ItemType == taskModel.HandoverItemNodeLeader {
+			holders = &node.LeaderId
+			roleName = "负责人"
+		}
+		if !utils.Common.ContainsID(*holders, from) {
+			return "交出人已不是该节点的" + roleName
+		}
+		if utils.Common.ContainsID(*holders, to) {
+			return "接收人已是该节点的" + roleName
+		}
+		*holders = l.replaceEmployeeIdInList(*holders, from, to)
+		node.UpdateTime = time.Now()
+		if err := l.svcCtx.TaskNodeModel.Update(l.ctx, node); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("更新任务节点失败: %v", err)
+			return "更新节点失败"
+		}
+	case taskModel.HandoverItemChecklist:
+		checklist, err := l.svcCtx.TaskChecklistModel.FindOne(l.ctx, item.TargetId)
+		if err != nil || checklist.DeleteTime.Valid {
+			return "清单已删除"
+		}
+		if checklist.CreatorId != from {
+			return "清单创建人已变更"
+		}
+		checklist.CreatorId = to
+		if err := l.svcCtx.TaskChecklistModel.Update(l.ctx, checklist); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("更新清单创建人失败: %v", err)
+			return "更新清单失败"
+		}
+	case taskModel.HandoverItemAttachment:
+		if l.svcCtx.UploadFileModel == nil {
+			return "附件服务不可用"
+		}
+		file, err := l.svcCtx.UploadFileModel.FindByFileID(l.ctx, item.TargetId)
+		if err != nil {
+			return "附件已删除"
+		}
+		if file.UploaderID != from {
+			return "附件上传人已变更"
+		}
+		file.UploaderID = to
+		file.UpdateAt = time.Now()
+		if _, err := l.svcCtx.UploadFileModel.Update(l.ctx, file); err != nil {
+			l.Logger.WithContext(l.ctx).Errorf("更新附件上传人失败: %v", err)
+			return "更新附件失败"
+		}
+	default:
+		return "不支持的交接事项"
+	}
+	return ""
+}
+
+// syncTaskNodeEmployees 节点角色转移后同步任务的节点员工列表：加入接收人，交出人不再担任任何节点角色时移除
+func (l *ApproveHandoverLogic) syncTaskNodeEmployees(handover *taskModel.TaskHandover) {
+	taskInfo, err := l.svcCtx.TaskModel.FindOne(l.ctx, handover.TaskId)
+	if err != nil {
+		return
+	}
+	fromInvolved, err := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, handover.TaskId, handover.FromEmployeeId,
+		taskModel.AssignmentExecutor, taskModel.AssignmentNodeLeader)
+	if err != nil {
+		return
+	}
+
+	var ids []string
+	hasTo := false
+	if taskInfo.NodeEmployeeIds.Valid {
+		for _, id := range strings.Split(taskInfo.NodeEmployeeIds.String, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" || (id == handover.FromEmployeeId && !fromInvolved) {
+				continue
+			}
+			hasTo = hasTo || id == handover.ToEmployeeId
+			ids = append(ids, id)
+		}
+	}
+	if !hasTo {
+		ids = append(ids, handover.ToEmployeeId)
+	}
+	newIds := strings.Join(ids, ",")
+	if newIds == taskInfo.NodeEmployeeIds.String {
+		return
+	}
+	taskInfo.NodeEmployeeIds = sql.NullString{String: newIds, Valid: true}
+	taskInfo.UpdateTime = time.Now()
+	if err := l.svcCtx.TaskModel.Update(l.ctx, taskInfo); err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("更新任务节点员工失败: %v", err)
+	}
+}
@@ -87,29 +87,22 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 	// 检查是否是任务创建者
 	isCreator := taskInfo.TaskCreator == req.FromEmployeeID
 
-	// 检查是否是任务负责人（通过responsible_employee_ids字段）
-	isResponsible := false
-	if taskInfo.ResponsibleEmployeeIds.Valid && taskInfo.ResponsibleEmployeeIds.String != "" {
-		responsibleIds := strings.Split(taskInfo.ResponsibleEmployeeIds.String, ",")
-		for _, id := range responsibleIds {
-			if strings.TrimSpace(id) == req.FromEmployeeID {
-				isResponsible = true
-				break
-			}
-		}
+	// 检查是否是任务负责人（通过分配表，包含任务负责人和负责人列表）
+	isResponsible, err := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, req.TaskID, req.FromEmployeeID,
+		task.AssignmentTaskLeader, task.AssignmentResponsible)
+	if err != nil {
+		return nil, err
 	}
 
-	// 检查是否是进行中节点的执行人
-	isExecutor := false
-	for _, node := range nodes {
-		if node.ExecutorId == req.FromEmployeeID && node.NodeStatus == 2 {
-			isExecutor = true
-			break
-		}
+	// 检查是否是节点执行人（多执行人节点中的任意一人）
+	isExecutor, err := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, req.TaskID, req.FromEmployeeID, task.AssignmentExecutor)
+	if err != nil {
+		return nil, err
 	}
 
 	l.Logger.WithContext(l.ctx).Infof("权限检查: isCreator=%v, isResponsible=%v, isExecutor=%v, TaskCreator=%s", isCreator, isResponsible, isExecutor, taskInfo.TaskCreator)
-	if !isCreator && !isResponsible && !isExecutor {
+	// 指定了交接事项时，逐项校验交出人担任或拥有该事项
+	if !isCreator && !isResponsible && !isExecutor && len(req.Items) == 0 {
 		return utils.Response.ValidationError("只有任务的创建者、负责人或执行人才能发起交接"), nil
 	}
 
@@ -128,18 +121,58 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 		return utils.Response.ValidationError("接收人不在职，无法接收任务"), nil
 	}
 
-	// 6. 检查是否已有待处理的交接请求
+	// 6. 校验交接事项（部分交接），不指定事项时交接整个任务
+	handoverID := utils.Common.GenerateIDWithPrefix("handover")
+	var items []*task.TaskHandoverItem
+	if len(req.Items) > 0 {
+		var msg string
+		items, msg = resolveHandoverItems(l.ctx, l.svcCtx, handoverID, req.TaskID, req.FromEmployeeID, req.ToEmployeeID, nodes, req.Items)
+		if msg != "" {
+			return utils.Response.ValidationError(msg), nil
+		}
+	}
+
+	// 7. 检查是否已有待处理的交接请求：整个任务的交接对同一接收人不能重复，部分交接的事项不能重复
 	handovers, err := l.svcCtx.TaskHandoverModel.FindByTaskID(l.ctx, req.TaskID)
 	if err == nil {
+		var pendingIDs []string
 		for _, h := range handovers {
 			// 状态0或1都是待处理状态
-			if (h.HandoverStatus == 0 || h.HandoverStatus == 1) && h.ToEmployeeId == req.ToEmployeeID {
-				return utils.Response.ValidationError("该任务对该接收人已有待处理的交接请求"), nil
+			if h.HandoverStatus == 0 || h.HandoverStatus == 1 {
+				pendingIDs = append(pendingIDs, h.HandoverId)
+			}
+		}
+		pendingItems, itemErr := l.svcCtx.TaskHandoverItemModel.FindByHandoverIds(l.ctx, pendingIDs)
+		if itemErr != nil {
+			return nil, itemErr
+		}
+		itemsOf := make(map[string][]*task.TaskHandoverItem)
+		for _, it := range pendingItems {
+			itemsOf[it.HandoverId] = append(itemsOf[it.HandoverId], it)
+		}
+		requested := make(map[string]bool, len(items))
+		for _, it := range items {
+			requested[handoverItemKey(it.ItemType, it.TargetId)] = true
+		}
+		for _, h := range handovers {
+			if h.HandoverStatus != 0 && h.HandoverStatus != 1 {
+				continue
+			}
+			if len(items) == 0 || len(itemsOf[h.HandoverId]) == 0 {
+				if h.ToEmployeeId == req.ToEmployeeID {
+					return utils.Response.ValidationError("该任务对该接收人已有待处理的交接请求"), nil
+				}
+				continue
+			}
+			for _, it := range itemsOf[h.HandoverId] {
+				if requested[handoverItemKey(it.ItemType, it.TargetId)] {
+					return utils.Response.ValidationError(fmt.Sprintf("%s已在其他待处理的交接中", describeHandoverItem(it))), nil
+				}
 			}
 		}
 	}
 
-	// 7. 获取发起人信息，找到上级作为审批人
+	// 8. 获取发起人信息，找到上级作为审批人
 	fromEmployee, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.FromEmployeeID)
 	if err != nil {
 		return nil, err
@@ -148,18 +181,26 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 	// 使用审批人查找器获取审批人（优先级：直属上级 > 部门经理 > 公司创始人）
 	var approverID string
 	var approverType string
+	var delegatedFromID string
 	if req.ApproverID != "" {
 		// 如果指定了审批人，使用指定的
 		approverID = req.ApproverID
 		approverType = "specified"
+		// 指定的审批人外出时转给其代理人
+		if delegate, ok := l.svcCtx.OutOfOfficeService.ResolveDelegate(l.ctx, req.ApproverID); ok && delegate.Id != req.FromEmployeeID {
+			approverID = delegate.Id
+			delegatedFromID = req.ApproverID
+		}
 	} else {
 		// 使用审批人查找器
-		approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel)
+		approverFinder := utils.NewApproverFinder(l.svcCtx.EmployeeModel, l.svcCtx.DepartmentModel, l.svcCtx.CompanyModel).
+			WithDelegateResolver(l.svcCtx.OutOfOfficeService.ResolveDelegate)
 		approverResult, findErr := approverFinder.FindApproverForHandover(l.ctx, req.FromEmployeeID, req.ToEmployeeID)
 		if findErr == nil && approverResult != nil {
 			approverID = approverResult.ApproverID
 			approverType = approverResult.ApproverType
-			l.Logger.WithContext(l.ctx).Infof("找到审批人: ID=%s, Name=%s, Type=%s", approverResult.ApproverID, approverResult.ApproverName, approverType)
+			delegatedFromID = approverResult.DelegatedFromID
+			l.Logger.WithContext(l.ctx).Infof("找到审批人: ID=%s, Name=%s, Type=%s, DelegatedFrom=%s", approverResult.ApproverID, approverResult.ApproverName, approverType, delegatedFromID)
 		} else {
 			// 兼容旧逻辑：如果新方法找不到，尝试部门经理
 			if fromEmployee.DepartmentId.Valid && fromEmployee.DepartmentId.String != "" {
@@ -172,8 +213,7 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 		}
 	}
 
-	// 8. 创建交接记录，状态为0（待接收人确认）
-	handoverID := utils.Common.GenerateIDWithPrefix("handover")
+	// 9. 创建交接记录，状态为0（待接收人确认）
 	newHandover := &task.TaskHandover{
 		HandoverId:     handoverID,
 		TaskId:         req.TaskID,
@@ -181,6 +221,7 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 		ToEmployeeId:   req.ToEmployeeID,
 		HandoverReason: utils.Common.ToSqlNullString(req.HandoverReason),
 		HandoverNote:   utils.Common.ToSqlNullString(req.HandoverNote),
+		TransitionNote: utils.Common.ToSqlNullString(strings.TrimSpace(req.TransitionNote)),
 		HandoverStatus: 0, // 待接收人确认
 		ApproverId:     utils.Common.ToSqlNullString(approverID),
 		CreateTime:     time.Now(),
@@ -192,13 +233,27 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 		l.Logger.WithContext(l.ctx).Errorf("创建交接记录失败: %v", err)
 		return nil, err
 	}
+	if err := l.svcCtx.TaskHandoverItemModel.InsertBatch(l.ctx, items); err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("创建交接事项失败: %v", err)
+		_ = l.svcCtx.TaskHandoverModel.Delete(l.ctx, handoverID)
+		return nil, err
+	}
+
+	// 审批人外出时由代理人审批，记录到外出代理事项中
+	if delegatedFromID != "" {
+		l.svcCtx.OutOfOfficeService.RecordRoutedItem(l.ctx, delegatedFromID, approverID, svc.OutOfOfficeItemHandover, handoverID, "任务交接："+taskInfo.TaskTitle)
+	}
 
-	// 9. 创建任务日志
+	// 10. 创建任务日志
+	logContent := fmt.Sprintf("发起任务交接: %s -> %s, 原因: %s", req.FromEmployeeID, req.ToEmployeeID, req.HandoverReason)
+	if len(items) > 0 {
+		logContent += fmt.Sprintf(", 交接事项: %s", summarizeHandoverItems(items))
+	}
 	taskLog := &task.TaskLog{
 		LogId:      utils.Common.GenerateID(),
 		TaskId:     req.TaskID,
 		LogType:    5, // 交接请求
-		LogContent: fmt.Sprintf("发起任务交接: %s -> %s, 原因: %s", req.FromEmployeeID, req.ToEmployeeID, req.HandoverReason),
+		LogContent: logContent,
 		EmployeeId: req.FromEmployeeID,
 		CreateTime: time.Now(),
 	}
@@ -207,7 +262,7 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 		l.Logger.WithContext(l.ctx).Errorf("创建任务日志失败: %v", err)
 	}
 
-	// 10. 发送通知给接收人（通过消息队列）
+	// 11. 发送通知给接收人（通过消息队列）
 	if l.svcCtx.NotificationMQService != nil {
 		notificationEvent := l.svcCtx.NotificationMQService.NewNotificationEvent(
 			svc.HandoverNotification,
@@ -216,7 +271,14 @@ func (l *CreateHandoverLogic) CreateHandover(req *types.CreateHandoverRequest) (
 			svc.NotificationEventOptions{TaskID: req.TaskID},
 		)
 		notificationEvent.Title = "任务交接请求"
-		notificationEvent.Content = fmt.Sprintf("您收到了一个任务交接请求：%s，原因：%s，请确认是否接收", taskInfo.TaskTitle, req.HandoverReason)
+		content := fmt.Sprintf("您收到了一个任务交接请求：%s，原因：%s", taskInfo.TaskTitle, req.HandoverReason)
+		if len(items) > 0 {
+			content += fmt.Sprintf("，交接事项：%s", summarizeHandoverItems(items))
+		}
+		if note := strings.TrimSpace(req.TransitionNote); note != "" {
+			content += fmt.Sprintf("，交接说明：%s", note)
+		}
+		notificationEvent.Content = content + "，请确认是否接收"
 		notificationEvent.Priority = 2
 		if err := l.svcCtx.NotificationMQService.PublishNotificationEvent(l.ctx, notificationEvent); err != nil {
 			l.Logger.WithContext(l.ctx).Errorf("发布交接通知事件失败: %v", err)
@@ -0,0 +1,78 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handover
+
+import (
+	"context"
+	"errors"
+
+	taskModel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type GetHandoverItemsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询任务中可交接的节点角色、清单和附件
+func NewGetHandoverItemsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetHandoverItemsLogic {
+	return &GetHandoverItemsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetHandoverItemsLogic) GetHandoverItems(req *types.GetHandoverItemsRequest) (resp *types.BaseResponse, err error) {
+	if req.TaskID == "" {
+		return utils.Response.ValidationError("任务ID不能为空"), nil
+	}
+
+	currentEmployeeID, ok := utils.Common.GetCurrentEmployeeID(l.ctx)
+	if !ok {
+		return utils.Response.UnauthorizedError(), nil
+	}
+
+	taskInfo, err := l.svcCtx.TaskModel.FindOne(l.ctx, req.TaskID)
+	if err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return utils.Response.ValidationError("任务不存在"), nil
+		}
+		return nil, err
+	}
+
+	// 查询他人的可交接事项需要是任务创建者或负责人
+	fromEmployeeID := req.FromEmployeeID
+	if fromEmployeeID == "" {
+		fromEmployeeID = currentEmployeeID
+	}
+	if fromEmployeeID != currentEmployeeID && taskInfo.TaskCreator != currentEmployeeID {
+		isResponsible, err := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, req.TaskID, currentEmployeeID,
+			taskModel.AssignmentTaskLeader, taskModel.AssignmentResponsible)
+		if err != nil {
+			return nil, err
+		}
+		if !isResponsible {
+			return utils.Response.ValidationError("只能查询自己的可交接事项"), nil
+		}
+	}
+
+	nodes, err := l.svcCtx.TaskNodeModel.FindByTaskID(l.ctx, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"taskId":         req.TaskID,
+		"fromEmployeeId": fromEmployeeID,
+		"items":          handoverCandidates(l.ctx, l.svcCtx, req.TaskID, fromEmployeeID, nodes),
+	}), nil
+}
@@ -39,7 +39,7 @@ func (l *GetHandoverListLogic) GetHandoverList(req *types.HandoverListRequest) (
 	}
 
 	// 3. 获取员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("查询员工失败: %v", err)
 		return utils.Response.ValidationError("用户未绑定员工信息"), nil
@@ -68,6 +68,17 @@ func (l *GetHandoverListLogic) GetHandoverList(req *types.HandoverListRequest) (
 	}
 
 	// 6. 转换为响应格式，包含更多详细信息
+	handoverIDs := make([]string, 0, len(filteredHandovers))
+	for _, handover := range filteredHandovers {
+		handoverIDs = append(handoverIDs, handover.HandoverId)
+	}
+	itemCounts := make(map[string]int)
+	if items, itemErr := l.svcCtx.TaskHandoverItemModel.FindByHandoverIds(l.ctx, handoverIDs); itemErr == nil {
+		for _, it := range items {
+			itemCounts[it.HandoverId]++
+		}
+	}
+
 	var handoverInfos []interface{}
 	for _, handover := range filteredHandovers {
 		// 获取任务信息（离职申请的TaskId为空，不需要查询任务）
@@ -114,6 +125,10 @@ func (l *GetHandoverListLogic) GetHandoverList(req *types.HandoverListRequest) (
 		if handover.HandoverNote.Valid {
 			handoverNote = handover.HandoverNote.String
 		}
+		transitionNote := ""
+		if handover.TransitionNote.Valid {
+			transitionNote = handover.TransitionNote.String
+		}
 
 		handoverInfo := map[string]interface{}{
 			"handoverId":       handover.HandoverId,
@@ -127,6 +142,8 @@ func (l *GetHandoverListLogic) GetHandoverList(req *types.HandoverListRequest) (
 			"approverName":     approverName,
 			"handoverReason":   handoverReason,
 			"handoverNote":     handoverNote,
+			"transitionNote":   transitionNote,
+			"itemCount":        itemCounts[handover.HandoverId], // 交接事项数，0 表示交接整个任务
 			"handoverStatus":   handover.HandoverStatus,
 			"approvalType":     "handover", // 标记为交接审批
 			"createTime":       handover.CreateTime.Format("2006-01-02 15:04:05"),
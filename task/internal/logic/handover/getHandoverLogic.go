@@ -3,7 +3,6 @@ package handover
 import (
 	"context"
 	"errors"
-	"strings"
 
 	"task_Project/model/task"
 	"task_Project/task/internal/svc"
@@ -41,7 +40,7 @@ func (l *GetHandoverLogic) GetHandover(req *types.GetHandoverRequest) (resp *typ
 	}
 
 	// 3. 获取当前员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("查询员工失败: %v", err)
 		return utils.Response.ValidationError("用户未绑定员工信息"), nil
@@ -135,6 +134,17 @@ func (l *GetHandoverLogic) GetHandover(req *types.GetHandoverRequest) (resp *typ
 	if handover.HandoverNote.Valid {
 		handoverNote = handover.HandoverNote.String
 	}
+	transitionNote := ""
+	if handover.TransitionNote.Valid {
+		transitionNote = handover.TransitionNote.String
+	}
+
+	// 交接事项（部分交接），为空表示交接整个任务
+	items, err := l.svcCtx.TaskHandoverItemModel.FindByHandoverId(l.ctx, handover.HandoverId)
+	if err != nil {
+		l.Logger.WithContext(l.ctx).Errorf("获取交接事项失败: %v", err)
+		return nil, err
+	}
 
 	// 9. 获取发起人负责或执行的任务和任务节点（用于离职交接展示）
 	var involvedTasks []map[string]interface{}
@@ -150,17 +160,12 @@ func (l *GetHandoverLogic) GetHandover(req *types.GetHandoverRequest) (resp *typ
 				if t.TaskCreator == handover.FromEmployeeId {
 					role = "创建者"
 				}
-				if t.ResponsibleEmployeeIds.Valid && t.ResponsibleEmployeeIds.String != "" {
-					ids := strings.Split(t.ResponsibleEmployeeIds.String, ",")
-					for _, id := range ids {
-						if strings.TrimSpace(id) == handover.FromEmployeeId {
-							if role != "" {
-								role += "/负责人"
-							} else {
-								role = "负责人"
-							}
-							break
-						}
+				if isResponsible, _ := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, t.TaskId, handover.FromEmployeeId,
+					task.AssignmentTaskLeader, task.AssignmentResponsible); isResponsible {
+					if role != "" {
+						role += "/负责人"
+					} else {
+						role = "负责人"
 					}
 				}
 				if role == "" {
@@ -252,6 +257,8 @@ func (l *GetHandoverLogic) GetHandover(req *types.GetHandoverRequest) (resp *typ
 		"handoverStatus":         handover.HandoverStatus,
 		"handoverReason":         handoverReason,
 		"handoverNote":           handoverNote,
+		"transitionNote":         transitionNote,
+		"items":                  toHandoverItemInfos(l.ctx, l.svcCtx, items),
 		"createTime":             handover.CreateTime.Format("2006-01-02 15:04:05"),
 		"updateTime":             handover.UpdateTime.Format("2006-01-02 15:04:05"),
 		"involvedTasks":          involvedTasks,
@@ -2,9 +2,9 @@ package handover
 
 import (
 	"context"
-	"strings"
 	"time"
 
+	taskModel "task_Project/model/task"
 	"task_Project/task/internal/svc"
 	"task_Project/task/internal/types"
 	"task_Project/task/internal/utils"
@@ -45,7 +45,7 @@ func (l *GetHandoverableTasksLogic) GetHandoverableTasks(req *types.PageReq) (re
 	}
 
 	// 2. 获取员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("获取员工信息失败: %v", err)
 		return utils.Response.ValidationError("用户未绑定员工信息"), nil
@@ -76,33 +76,26 @@ func (l *GetHandoverableTasksLogic) GetHandoverableTasks(req *types.PageReq) (re
 	}
 
 	// 3.2 查找用户作为任务负责人的任务
-	allTasks, _, err := l.svcCtx.TaskModel.FindByPage(l.ctx, 1, 500)
+	responsibleTaskIDs, err := l.svcCtx.TaskAssignmentModel.FindTaskIdsByEmployee(l.ctx, employeeID,
+		taskModel.AssignmentTaskLeader, taskModel.AssignmentResponsible)
 	if err == nil {
-		for _, task := range allTasks {
-			if !l.isTaskHandoverable(task.TaskStatus, task.TaskStartTime) {
+		for _, taskID := range responsibleTaskIDs {
+			task, taskErr := l.svcCtx.TaskModel.FindOne(l.ctx, taskID)
+			if taskErr != nil || !l.isTaskHandoverable(task.TaskStatus, task.TaskStartTime) {
 				continue
 			}
-			// 检查是否是负责人
-			if task.ResponsibleEmployeeIds.Valid && task.ResponsibleEmployeeIds.String != "" {
-				responsibleIds := strings.Split(task.ResponsibleEmployeeIds.String, ",")
-				for _, id := range responsibleIds {
-					if strings.TrimSpace(id) == employeeID {
-						if existing, exists := taskMap[task.TaskId]; exists {
-							// 如果已存在，更新角色
-							existing.Role = existing.Role + ",responsible"
-							existing.RoleDisplay = existing.RoleDisplay + "/负责人"
-						} else {
-							taskMap[task.TaskId] = &HandoverableTask{
-								TaskId:      task.TaskId,
-								TaskTitle:   task.TaskTitle,
-								TaskStatus:  task.TaskStatus,
-								Deadline:    task.TaskDeadline.Format("2006-01-02"),
-								Role:        "responsible",
-								RoleDisplay: "负责人",
-							}
-						}
-						break
-					}
+			if existing, exists := taskMap[task.TaskId]; exists {
+				// 如果已存在，更新角色
+				existing.Role = existing.Role + ",responsible"
+				existing.RoleDisplay = existing.RoleDisplay + "/负责人"
+			} else {
+				taskMap[task.TaskId] = &HandoverableTask{
+					TaskId:      task.TaskId,
+					TaskTitle:   task.TaskTitle,
+					TaskStatus:  task.TaskStatus,
+					Deadline:    task.TaskDeadline.Format("2006-01-02"),
+					Role:        "responsible",
+					RoleDisplay: "负责人",
 				}
 			}
 		}
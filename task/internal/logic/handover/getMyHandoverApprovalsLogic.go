@@ -39,7 +39,7 @@ func (l *GetMyHandoverApprovalsLogic) GetMyHandoverApprovals(req *types.Handover
 	}
 
 	// 3. 获取员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("查询员工失败: %v", err)
 		return utils.Response.ValidationError("用户未绑定员工信息"), nil
@@ -56,6 +56,17 @@ func (l *GetMyHandoverApprovalsLogic) GetMyHandoverApprovals(req *types.Handover
 	l.Logger.WithContext(l.ctx).Infof("查询到 %d 条待审批交接记录, 总数: %d", len(handovers), total)
 
 	// 5. 转换为响应格式，包含更多详细信息
+	handoverIDs := make([]string, 0, len(handovers))
+	for _, handover := range handovers {
+		handoverIDs = append(handoverIDs, handover.HandoverId)
+	}
+	itemCounts := make(map[string]int)
+	if items, itemErr := l.svcCtx.TaskHandoverItemModel.FindByHandoverIds(l.ctx, handoverIDs); itemErr == nil {
+		for _, it := range items {
+			itemCounts[it.HandoverId]++
+		}
+	}
+
 	var handoverInfos []interface{}
 	for _, handover := range handovers {
 		// 获取任务信息（离职申请的TaskId为空，不需要查询任务）
@@ -102,6 +113,10 @@ func (l *GetMyHandoverApprovalsLogic) GetMyHandoverApprovals(req *types.Handover
 		if handover.HandoverNote.Valid {
 			handoverNote = handover.HandoverNote.String
 		}
+		transitionNote := ""
+		if handover.TransitionNote.Valid {
+			transitionNote = handover.TransitionNote.String
+		}
 
 		handoverInfo := map[string]interface{}{
 			"handoverId":       handover.HandoverId,
@@ -115,6 +130,8 @@ func (l *GetMyHandoverApprovalsLogic) GetMyHandoverApprovals(req *types.Handover
 			"approverName":     approverName,
 			"handoverReason":   handoverReason,
 			"handoverNote":     handoverNote,
+			"transitionNote":   transitionNote,
+			"itemCount":        itemCounts[handover.HandoverId], // 交接事项数，0 表示交接整个任务
 			"handoverStatus":   handover.HandoverStatus,
 			"status":           handover.HandoverStatus, // 添加status字段以兼容前端
 			"approvalType":     "handover",              // 标记为交接审批
@@ -0,0 +1,260 @@
+package handover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	taskModel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// maxHandoverItems 单次交接最多包含的事项数
+const maxHandoverItems = 100
+
+// handoverItemTypeNames 交接事项类型名称
+var handoverItemTypeNames = map[string]string{
+	taskModel.HandoverItemNodeExecutor: "节点执行人",
+	taskModel.HandoverItemNodeLeader:   "节点负责人",
+	taskModel.HandoverItemChecklist:    "清单",
+	taskModel.HandoverItemAttachment:   "附件",
+}
+
+// handoverItemKey 交接事项的唯一标识，用于去重和检查待处理交接中的重复事项
+func handoverItemKey(itemType, targetID string) string {
+	return itemType + ":" + targetID
+}
+
+// describeHandoverItem 交接事项的描述，用于任务日志和提示信息
+func describeHandoverItem(item *taskModel.TaskHandoverItem) string {
+	switch item.ItemType {
+	case taskModel.HandoverItemNodeExecutor:
+		return fmt.Sprintf("节点「%s」的执行人", item.TargetName)
+	case taskModel.HandoverItemNodeLeader:
+		return fmt.Sprintf("节点「%s」的负责人", item.TargetName)
+	default:
+		return fmt.Sprintf("%s「%s」", handoverItemTypeNames[item.ItemType], item.TargetName)
+	}
+}
+
+// truncateItemName 截断清单内容作为事项名称
+func truncateItemName(name string) string {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) <= 50 {
+		return name
+	}
+	return string([]rune(name)[:50]) + "…"
+}
+
+// belongsToTask 判断附件是否属于任务或任务的节点
+func belongsToTask(relatedID, taskNodeID, taskID string, nodeMap map[string]*taskModel.TaskNode) bool {
+	if taskNodeID != "" {
+		_, ok := nodeMap[taskNodeID]
+		return ok
+	}
+	return relatedID == taskID
+}
+
+// resolveHandoverItems 校验发起交接时指定的事项：事项必须属于该任务，且当前由交出人担任或拥有。
+// 返回待写入的交接事项，校验失败时返回错误提示
+func resolveHandoverItems(ctx context.Context, svcCtx *svc.ServiceContext, handoverID, taskID, fromID, toID string,
+	nodes []*taskModel.TaskNode, inputs []types.HandoverItemInput) ([]*taskModel.TaskHandoverItem, string) {
+	if len(inputs) > maxHandoverItems {
+		return nil, fmt.Sprintf("单次交接最多包含 %d 个事项", maxHandoverItems)
+	}
+	nodeMap := make(map[string]*taskModel.TaskNode, len(nodes))
+	for _, n := range nodes {
+		nodeMap[n.TaskNodeId] = n
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(inputs))
+	items := make([]*taskModel.TaskHandoverItem, 0, len(inputs))
+	for _, in := range inputs {
+		itemType := strings.TrimSpace(in.ItemType)
+		targetID := strings.TrimSpace(in.TargetID)
+		if _, ok := handoverItemTypeNames[itemType]; !ok {
+			return nil, "交接事项类型不正确"
+		}
+		if targetID == "" {
+			return nil, "交接事项不能为空"
+		}
+		key := handoverItemKey(itemType, targetID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		item := &taskModel.TaskHandoverItem{
+			Id:         utils.Common.GenId("hoi"),
+			HandoverId: handoverID,
+			ItemType:   itemType,
+			TargetId:   targetID,
+			Status:     taskModel.HandoverItemPending,
+			CreateTime: now,
+			UpdateTime: now,
+		}
+		switch itemType {
+		case taskModel.HandoverItemNodeExecutor, taskModel.HandoverItemNodeLeader:
+			node, ok := nodeMap[targetID]
+			if !ok {
+				return nil, "交接的节点不属于该任务"
+			}
+			if node.NodeStatus == 2 {
+				return nil, fmt.Sprintf("节点「%s」已完成，无需交接", node.NodeName)
+			}
+			holders, roleName := node.ExecutorId, "执行人"
+			if itemType == taskModel.HandoverItemNodeLeader {
+				holders, roleName = node.LeaderId, "负责人"
+			}
+			if !utils.Common.ContainsID(holders, fromID) {
+				return nil, fmt.Sprintf("交出人不是节点「%s」的%s", node.NodeName, roleName)
+			}
+			if utils.Common.ContainsID(holders, toID) {
+				return nil, fmt.Sprintf("接收人已是节点「%s」的%s", node.NodeName, roleName)
+			}
+			item.TaskNodeId = node.TaskNodeId
+			item.TargetName = node.NodeName
+		case taskModel.HandoverItemChecklist:
+			checklist, err := svcCtx.TaskChecklistModel.FindOne(ctx, targetID)
+			if err != nil || checklist.DeleteTime.Valid {
+				return nil, "交接的清单不存在"
+			}
+			if _, ok := nodeMap[checklist.TaskNodeId]; !ok {
+				return nil, "交接的清单不属于该任务"
+			}
+			if checklist.CreatorId != fromID {
+				return nil, "只能交接交出人创建的清单"
+			}
+			item.TaskNodeId = checklist.TaskNodeId
+			item.TargetName = truncateItemName(checklist.Content)
+		case taskModel.HandoverItemAttachment:
+			if svcCtx.UploadFileModel == nil {
+				return nil, "附件服务不可用，无法交接附件"
+			}
+			file, err := svcCtx.UploadFileModel.FindByFileID(ctx, targetID)
+			if err != nil {
+				return nil, "交接的附件不存在"
+			}
+			if !belongsToTask(file.RelatedID, file.TaskNodeID, taskID, nodeMap) {
+				return nil, "交接的附件不属于该任务"
+			}
+			if file.UploaderID != fromID {
+				return nil, "只能交接交出人上传的附件"
+			}
+			item.TaskNodeId = file.TaskNodeID
+			item.TargetName = file.FileName
+		}
+		items = append(items, item)
+	}
+	return items, ""
+}
+
+// handoverCandidates 交出人在任务中可交接的事项：担任执行人或负责人的未完成节点、创建的清单和上传的附件
+func handoverCandidates(ctx context.Context, svcCtx *svc.ServiceContext, taskID, fromID string, nodes []*taskModel.TaskNode) []types.HandoverItemInfo {
+	nodeMap := make(map[string]*taskModel.TaskNode, len(nodes))
+	list := make([]types.HandoverItemInfo, 0)
+	add := func(itemType, taskNodeID, targetID, targetName string) {
+		info := types.HandoverItemInfo{
+			ItemType:     itemType,
+			ItemTypeName: handoverItemTypeNames[itemType],
+			TaskNodeID:   taskNodeID,
+			TargetID:     targetID,
+			TargetName:   targetName,
+		}
+		if node, ok := nodeMap[taskNodeID]; ok {
+			info.NodeName = node.NodeName
+		}
+		list = append(list, info)
+	}
+
+	for _, n := range nodes {
+		nodeMap[n.TaskNodeId] = n
+	}
+	for _, n := range nodes {
+		if n.NodeStatus == 2 {
+			continue
+		}
+		if utils.Common.ContainsID(n.ExecutorId, fromID) {
+			add(taskModel.HandoverItemNodeExecutor, n.TaskNodeId, n.TaskNodeId, n.NodeName)
+		}
+		if utils.Common.ContainsID(n.LeaderId, fromID) {
+			add(taskModel.HandoverItemNodeLeader, n.TaskNodeId, n.TaskNodeId, n.NodeName)
+		}
+	}
+
+	for _, n := range nodes {
+		checklists, err := svcCtx.TaskChecklistModel.FindByTaskNodeIdAndCreator(ctx, n.TaskNodeId, fromID)
+		if err != nil {
+			continue
+		}
+		for _, c := range checklists {
+			add(taskModel.HandoverItemChecklist, c.TaskNodeId, c.ChecklistId, truncateItemName(c.Content))
+		}
+	}
+
+	if svcCtx.UploadFileModel != nil {
+		seen := make(map[string]bool)
+		files, _ := svcCtx.UploadFileModel.FindByModuleAndRelatedID(ctx, "task", taskID)
+		for _, n := range nodes {
+			if nodeFiles, err := svcCtx.UploadFileModel.FindByTaskNodeID(ctx, n.TaskNodeId); err == nil {
+				files = append(files, nodeFiles...)
+			}
+		}
+		for _, f := range files {
+			if seen[f.FileID] || f.UploaderID != fromID || !belongsToTask(f.RelatedID, f.TaskNodeID, taskID, nodeMap) {
+				continue
+			}
+			seen[f.FileID] = true
+			add(taskModel.HandoverItemAttachment, f.TaskNodeID, f.FileID, f.FileName)
+		}
+	}
+	return list
+}
+
+// toHandoverItemInfos 转换交接事项为响应格式，并补充所属节点名称
+func toHandoverItemInfos(ctx context.Context, svcCtx *svc.ServiceContext, items []*taskModel.TaskHandoverItem) []types.HandoverItemInfo {
+	nodeNames := make(map[string]string)
+	list := make([]types.HandoverItemInfo, 0, len(items))
+	for _, it := range items {
+		nodeName, ok := nodeNames[it.TaskNodeId]
+		if !ok && it.TaskNodeId != "" {
+			if node, err := svcCtx.TaskNodeModel.FindOne(ctx, it.TaskNodeId); err == nil {
+				nodeName = node.NodeName
+			}
+			nodeNames[it.TaskNodeId] = nodeName
+		}
+		list = append(list, types.HandoverItemInfo{
+			ID:           it.Id,
+			ItemType:     it.ItemType,
+			ItemTypeName: handoverItemTypeNames[it.ItemType],
+			TaskNodeID:   it.TaskNodeId,
+			NodeName:     nodeName,
+			TargetID:     it.TargetId,
+			TargetName:   it.TargetName,
+			Status:       it.Status,
+			Remark:       it.Remark,
+		})
+	}
+	return list
+}
+
+// summarizeHandoverItems 交接事项摘要，最多列出前 5 项
+func summarizeHandoverItems(items []*taskModel.TaskHandoverItem) string {
+	names := make([]string, 0, 5)
+	for i, it := range items {
+		if i == 5 {
+			break
+		}
+		names = append(names, describeHandoverItem(it))
+	}
+	summary := strings.Join(names, "、")
+	if len(items) > 5 {
+		summary += fmt.Sprintf(" 等 %d 项", len(items))
+	}
+	return summary
+}
@@ -0,0 +1,76 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package label
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateTaskLabelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建任务标签
+func NewCreateTaskLabelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateTaskLabelLogic {
+	return &CreateTaskLabelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateTaskLabelLogic) CreateTaskLabel(req *types.CreateTaskLabelRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("label_no_permission"), nil
+	}
+	name, color, description, msg := normalizeLabel(req.Name, req.Color, req.Description)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if _, err := l.svcCtx.TaskLabelModel.FindByName(l.ctx, employee.CompanyId, name); err == nil {
+		return utils.Response.BusinessError("label_name_exists"), nil
+	} else if !errors.Is(err, taskmodel.ErrNotFound) {
+		l.Logger.Errorf("查询标签失败: %v", err)
+		return utils.Response.InternalError("创建标签失败"), nil
+	}
+	count, err := l.svcCtx.TaskLabelModel.CountByCompany(l.ctx, employee.CompanyId)
+	if err != nil {
+		l.Logger.Errorf("统计标签数量失败: %v", err)
+		return utils.Response.InternalError("创建标签失败"), nil
+	}
+	if count >= maxCompanyLabels {
+		return utils.Response.BusinessError("label_limit"), nil
+	}
+
+	now := time.Now()
+	label := &taskmodel.TaskLabel{
+		Id:          utils.Common.GenId("label"),
+		CompanyId:   employee.CompanyId,
+		Name:        name,
+		Color:       color,
+		Description: description,
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	if _, err := l.svcCtx.TaskLabelModel.Insert(l.ctx, label); err != nil {
+		l.Logger.Errorf("创建标签失败: %v", err)
+		return utils.Response.InternalError("创建标签失败"), nil
+	}
+	return utils.Response.Success(toTaskLabelInfo(label, 0)), nil
+}
@@ -0,0 +1,48 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package label
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteTaskLabelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除任务标签
+func NewDeleteTaskLabelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteTaskLabelLogic {
+	return &DeleteTaskLabelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteTaskLabelLogic) DeleteTaskLabel(req *types.DeleteTaskLabelRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("label_no_permission"), nil
+	}
+	label, errResp := loadCompanyLabel(l.ctx, l.svcCtx, employee, req.LabelID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := l.svcCtx.TaskLabelModel.Delete(l.ctx, label.Id); err != nil {
+		l.Logger.Errorf("删除标签失败: %v", err)
+		return utils.Response.InternalError("删除标签失败"), nil
+	}
+	return utils.Response.Success("标签已删除"), nil
+}
@@ -0,0 +1,78 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 标签数量和长度限制
+const (
+	maxCompanyLabels      = 100 // 每个公司最多的标签数
+	maxTaskLabels         = 10  // 每个任务最多的标签数
+	maxLabelNameRunes     = 32
+	maxLabelDescRunes     = 200
+	defaultTaskLabelColor = "#1677ff"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// loadCompanyLabel 查询当前公司的标签
+func loadCompanyLabel(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, labelID string) (*taskmodel.TaskLabel, *types.BaseResponse) {
+	if labelID == "" {
+		return nil, utils.Response.ValidationError("标签ID不能为空")
+	}
+	label, err := svcCtx.TaskLabelModel.FindOne(ctx, labelID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return nil, utils.Response.BusinessError("label_not_found")
+		}
+		return nil, utils.Response.InternalError("查询标签失败")
+	}
+	if label.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("label_not_found")
+	}
+	return label, nil
+}
+
+// normalizeLabel 校验并整理标签名称、颜色和说明，返回错误提示
+func normalizeLabel(name, color, description string) (string, string, string, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", "", "标签名称不能为空"
+	}
+	if utf8.RuneCountInString(name) > maxLabelNameRunes {
+		return "", "", "", "标签名称不能超过 32 个字符"
+	}
+	color = strings.TrimSpace(color)
+	if color == "" {
+		color = defaultTaskLabelColor
+	}
+	if !labelColorPattern.MatchString(color) {
+		return "", "", "", "标签颜色格式应为 #RRGGBB"
+	}
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > maxLabelDescRunes {
+		return "", "", "", "标签说明不能超过 200 个字符"
+	}
+	return name, strings.ToLower(color), description, ""
+}
+
+func toTaskLabelInfo(label *taskmodel.TaskLabel, taskCount int64) types.TaskLabelInfo {
+	return types.TaskLabelInfo{
+		ID:          label.Id,
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+		TaskCount:   taskCount,
+		CreateTime:  utils.Common.FormatTime(label.CreateTime),
+	}
+}
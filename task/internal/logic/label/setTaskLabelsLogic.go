@@ -0,0 +1,75 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package label
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetTaskLabelsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 设置任务的标签
+func NewSetTaskLabelsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetTaskLabelsLogic {
+	return &SetTaskLabelsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetTaskLabelsLogic) SetTaskLabels(req *types.SetTaskLabelsRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if req.TaskID == "" {
+		return utils.Response.ValidationError("任务ID不能为空"), nil
+	}
+	taskInfo, err := l.svcCtx.TaskModel.FindOne(l.ctx, req.TaskID)
+	if err != nil || taskInfo.DeleteTime.Valid || taskInfo.CompanyId != employee.CompanyId {
+		return utils.Response.BusinessError("task_not_found"), nil
+	}
+	// 任务创建者、负责人或管理人员可以设置标签
+	if taskInfo.TaskCreator != employee.Id && (!taskInfo.LeaderId.Valid || taskInfo.LeaderId.String != employee.Id) &&
+		!l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("task_update_denied"), nil
+	}
+
+	seen := make(map[string]bool, len(req.LabelIDs))
+	labelIDs := make([]string, 0, len(req.LabelIDs))
+	for _, id := range req.LabelIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		labelIDs = append(labelIDs, id)
+	}
+	if len(labelIDs) > maxTaskLabels {
+		return utils.Response.BusinessError("task_label_limit"), nil
+	}
+	briefs := make([]types.TaskLabelBrief, 0, len(labelIDs))
+	for _, id := range labelIDs {
+		label, errResp := loadCompanyLabel(l.ctx, l.svcCtx, employee, id)
+		if errResp != nil {
+			return errResp, nil
+		}
+		briefs = append(briefs, types.TaskLabelBrief{ID: label.Id, Name: label.Name, Color: label.Color})
+	}
+
+	if err := l.svcCtx.TaskLabelModel.SetTaskLabels(l.ctx, taskInfo.TaskId, labelIDs); err != nil {
+		l.Logger.Errorf("设置任务标签失败: %v", err)
+		return utils.Response.InternalError("设置任务标签失败"), nil
+	}
+	return utils.Response.Success(briefs), nil
+}
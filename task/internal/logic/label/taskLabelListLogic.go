@@ -0,0 +1,55 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package label
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type TaskLabelListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 公司的任务标签
+func NewTaskLabelListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TaskLabelListLogic {
+	return &TaskLabelListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TaskLabelListLogic) TaskLabelList() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	labels, err := l.svcCtx.TaskLabelModel.FindByCompany(l.ctx, employee.CompanyId)
+	if err != nil {
+		l.Logger.Errorf("查询标签失败: %v", err)
+		return utils.Response.InternalError("查询标签失败"), nil
+	}
+	usages, err := l.svcCtx.TaskLabelModel.CountTasks(l.ctx, employee.CompanyId)
+	if err != nil {
+		l.Logger.Errorf("统计标签任务数失败: %v", err)
+		return utils.Response.InternalError("查询标签失败"), nil
+	}
+	counts := make(map[string]int64, len(usages))
+	for _, u := range usages {
+		counts[u.LabelId] = u.TaskCount
+	}
+	list := make([]types.TaskLabelInfo, 0, len(labels))
+	for _, label := range labels {
+		list = append(list, toTaskLabelInfo(label, counts[label.Id]))
+	}
+	return utils.Response.Success(list), nil
+}
@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package label
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTaskLabelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新任务标签
+func NewUpdateTaskLabelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateTaskLabelLogic {
+	return &UpdateTaskLabelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateTaskLabelLogic) UpdateTaskLabel(req *types.UpdateTaskLabelRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("label_no_permission"), nil
+	}
+	label, errResp := loadCompanyLabel(l.ctx, l.svcCtx, employee, req.LabelID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	name, color, description, msg := normalizeLabel(req.Name, req.Color, req.Description)
+	if msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if name != label.Name {
+		if _, err := l.svcCtx.TaskLabelModel.FindByName(l.ctx, employee.CompanyId, name); err == nil {
+			return utils.Response.BusinessError("label_name_exists"), nil
+		} else if !errors.Is(err, taskmodel.ErrNotFound) {
+			l.Logger.Errorf("查询标签失败: %v", err)
+			return utils.Response.InternalError("更新标签失败"), nil
+		}
+	}
+
+	label.Name, label.Color, label.Description = name, color, description
+	if err := l.svcCtx.TaskLabelModel.Update(l.ctx, label); err != nil {
+		l.Logger.Errorf("更新标签失败: %v", err)
+		return utils.Response.InternalError("更新标签失败"), nil
+	}
+	return utils.Response.Success(toTaskLabelInfo(label, 0)), nil
+}
@@ -40,7 +40,7 @@ func (l *GetNotificationListLogic) GetNotificationList(req *types.NotificationLi
 	}
 
 	// 3. 获取员工ID
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("查询员工失败: %v", err)
 		return utils.Response.BusinessError("user_not_bindemployee"), nil
@@ -0,0 +1,106 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"task_Project/model/user_auth"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DelegatePermissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 临时委派：将员工的全部权限临时授予代理人
+func NewDelegatePermissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DelegatePermissionLogic {
+	return &DelegatePermissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DelegatePermissionLogic) DelegatePermission(req *types.DelegatePermissionRequest) (resp *types.BaseResponse, err error) {
+	if utils.Validator.IsEmpty(req.FromEmployeeId) || utils.Validator.IsEmpty(req.ToEmployeeId) {
+		return utils.Response.ValidationError("fromEmployeeId/toEmployeeId required"), nil
+	}
+	if req.FromEmployeeId == req.ToEmployeeId {
+		return utils.Response.BusinessError("delegate_self_not_allowed"), nil
+	}
+	// 临时委派必须有截止时间，到期后由定时任务自动回收
+	if utils.Validator.IsEmpty(req.ExpireTime) {
+		return utils.Response.BusinessError("delegate_expire_required"), nil
+	}
+	expireAt, _, ok := parseExpireTime(req.ExpireTime)
+	if !ok {
+		return utils.Response.BusinessError("permission_expire_invalid"), nil
+	}
+
+	userID, operator, errResp := loadOperator(l.ctx, l.svcCtx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	from, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.FromEmployeeId)
+	if err != nil {
+		return utils.Response.BusinessError("employee_not_found"), nil
+	}
+	to, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.ToEmployeeId)
+	if err != nil {
+		return utils.Response.BusinessError("employee_not_found"), nil
+	}
+	if from.CompanyId != operator.CompanyId || to.CompanyId != operator.CompanyId {
+		return utils.Response.BusinessError("permission_cross_company"), nil
+	}
+
+	// 仅委派被代理人通过职位角色获得的权限，不传递其自身的临时授权
+	codes, err := l.svcCtx.PermissionGrantService.RolePermCodes(l.ctx, from.Id)
+	if err != nil {
+		l.Errorf("查询被代理员工权限失败: employeeId=%s, err=%v", from.Id, err)
+		return utils.Response.InternalError("delegate permission failed"), nil
+	}
+	if len(codes) == 0 {
+		return utils.Response.BusinessError("delegate_no_permissions"), nil
+	}
+
+	if errResp := checkOwnedPermCodes(l.ctx, l.svcCtx, userID, operator, codes); errResp != nil {
+		return errResp, nil
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("代理 %s 的职责", from.RealName)
+	}
+	granted, err := l.svcCtx.PermissionGrantService.Grant(l.ctx, svc.PermissionGrant{
+		UserID:     to.UserId,
+		CompanyID:  to.CompanyId,
+		PermCodes:  codes,
+		GrantType:  user_auth.GrantTypeDelegation,
+		ExpireTime: sql.NullTime{Time: expireAt, Valid: true},
+		GrantBy:    userID,
+		Reason:     reason,
+	})
+	if err != nil {
+		l.Errorf("临时委派失败: from=%s, to=%s, err=%v", from.Id, to.Id, err)
+		return utils.Response.InternalError("delegate permission failed"), nil
+	}
+
+	list := make([]types.UserPermissionInfo, 0, len(granted))
+	for _, p := range granted {
+		list = append(list, toUserPermissionInfo(p))
+	}
+	return utils.Response.SuccessWithKey("operation", map[string]interface{}{
+		"list": list,
+	}), nil
+}
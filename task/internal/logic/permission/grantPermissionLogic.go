@@ -0,0 +1,91 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"task_Project/model/user_auth"
+	mw "task_Project/task/internal/middleware"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GrantPermissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 直接授予员工权限
+func NewGrantPermissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GrantPermissionLogic {
+	return &GrantPermissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GrantPermissionLogic) GrantPermission(req *types.GrantPermissionRequest) (resp *types.BaseResponse, err error) {
+	if utils.Validator.IsEmpty(req.EmployeeId) {
+		return utils.Response.ValidationError("employeeId required"), nil
+	}
+	if len(req.PermCodes) == 0 {
+		return utils.Response.BusinessError("permission_codes_required"), nil
+	}
+	validPerms := mw.GetValidPermCodes()
+	for _, code := range req.PermCodes {
+		if _, ok := validPerms[code]; !ok {
+			return utils.Response.ValidationError(fmt.Sprintf("invalid permission code: %d", code)), nil
+		}
+	}
+	expireAt, hasExpire, ok := parseExpireTime(req.ExpireTime)
+	if !ok {
+		return utils.Response.BusinessError("permission_expire_invalid"), nil
+	}
+
+	userID, operator, errResp := loadOperator(l.ctx, l.svcCtx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	target, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.EmployeeId)
+	if err != nil {
+		return utils.Response.BusinessError("employee_not_found"), nil
+	}
+	if target.CompanyId != operator.CompanyId {
+		return utils.Response.BusinessError("permission_cross_company"), nil
+	}
+
+	if errResp := checkOwnedPermCodes(l.ctx, l.svcCtx, userID, operator, req.PermCodes); errResp != nil {
+		return errResp, nil
+	}
+
+	granted, err := l.svcCtx.PermissionGrantService.Grant(l.ctx, svc.PermissionGrant{
+		UserID:     target.UserId,
+		CompanyID:  target.CompanyId,
+		PermCodes:  req.PermCodes,
+		GrantType:  user_auth.GrantTypeDirect,
+		ExpireTime: sql.NullTime{Time: expireAt, Valid: hasExpire},
+		GrantBy:    userID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		l.Errorf("授予权限失败: employeeId=%s, err=%v", req.EmployeeId, err)
+		return utils.Response.InternalError("grant permission failed"), nil
+	}
+
+	list := make([]types.UserPermissionInfo, 0, len(granted))
+	for _, p := range granted {
+		list = append(list, toUserPermissionInfo(p))
+	}
+	return utils.Response.SuccessWithKey("operation", map[string]interface{}{
+		"list": list,
+	}), nil
+}
@@ -0,0 +1,88 @@
+package permission
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"task_Project/model/user"
+	"task_Project/model/user_auth"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// loadOperator 获取当前操作人的员工信息
+func loadOperator(ctx context.Context, svcCtx *svc.ServiceContext) (string, *user.Employee, *types.BaseResponse) {
+	operator, errResp := svcCtx.CurrentOperator(ctx)
+	if errResp != nil {
+		return "", nil, errResp
+	}
+	return operator.UserId, operator, nil
+}
+
+// isCompanyFounder 判断用户是否为公司创始人
+func isCompanyFounder(ctx context.Context, svcCtx *svc.ServiceContext, companyID, userID string) bool {
+	company, err := svcCtx.CompanyModel.FindOne(ctx, companyID)
+	return err == nil && company != nil && company.Owner == userID
+}
+
+// checkOwnedPermCodes 非创始人只能授予、委派或撤销自己拥有的权限，防止越权扩散；允许时返回 nil
+func checkOwnedPermCodes(ctx context.Context, svcCtx *svc.ServiceContext, userID string, operator *user.Employee, codes []int) *types.BaseResponse {
+	if isCompanyFounder(ctx, svcCtx, operator.CompanyId, userID) {
+		return nil
+	}
+	owned, err := svcCtx.PermissionGrantService.EffectivePermCodes(ctx, operator.Id, userID, operator.CompanyId)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询操作人权限失败: employeeId=%s, err=%v", operator.Id, err)
+		return utils.Response.InternalError("check operator permission failed")
+	}
+	ownedSet := make(map[int]struct{}, len(owned))
+	for _, c := range owned {
+		ownedSet[c] = struct{}{}
+	}
+	for _, code := range codes {
+		if _, ok := ownedSet[code]; !ok {
+			return utils.Response.BusinessError("permission_grant_denied")
+		}
+	}
+	return nil
+}
+
+// parseExpireTime 解析过期时间，空字符串表示长期有效，过去的时间视为无效
+func parseExpireTime(expireTime string) (time.Time, bool, bool) {
+	if expireTime == "" {
+		return time.Time{}, false, true
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", expireTime, time.Local)
+	if err != nil || !t.After(time.Now()) {
+		return time.Time{}, true, false
+	}
+	return t, true, true
+}
+
+// toUserPermissionInfo 转换授权记录
+func toUserPermissionInfo(p *user_auth.UserPermission) types.UserPermissionInfo {
+	code, _ := strconv.Atoi(p.PermissionCode)
+	info := types.UserPermissionInfo{
+		Id:             p.Id,
+		UserId:         p.UserId,
+		CompanyId:      p.ResourceId.String,
+		PermissionCode: code,
+		PermissionName: p.PermissionName,
+		GrantType:      int(p.GrantType),
+		GrantBy:        p.GrantBy.String,
+		Status:         int(p.Status),
+		CreateTime:     utils.Common.FormatTime(p.CreateTime),
+	}
+	if p.ExpireTime.Valid {
+		info.ExpireTime = utils.Common.FormatTime(p.ExpireTime.Time)
+		// 已过期但尚未被定时任务回收的授权按失效展示
+		if !p.ExpireTime.Time.After(time.Now()) {
+			info.Status = user_auth.PermissionStatusRevoked
+		}
+	}
+	return info
+}
@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package permission
+
+import (
+	"context"
+	"strconv"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokePermissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 撤销授权
+func NewRevokePermissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokePermissionLogic {
+	return &RevokePermissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokePermissionLogic) RevokePermission(req *types.RevokePermissionRequest) (resp *types.BaseResponse, err error) {
+	if utils.Validator.IsEmpty(req.Id) {
+		return utils.Response.ValidationError("id required"), nil
+	}
+
+	userID, operator, errResp := loadOperator(l.ctx, l.svcCtx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	perm, err := l.svcCtx.UserPermissionModel.FindOne(l.ctx, req.Id)
+	if err != nil {
+		return utils.Response.BusinessError("permission_not_found"), nil
+	}
+	if perm.ResourceId.String != operator.CompanyId {
+		return utils.Response.BusinessError("permission_cross_company"), nil
+	}
+	code, err := strconv.Atoi(perm.PermissionCode)
+	if err != nil {
+		return utils.Response.BusinessError("permission_not_found"), nil
+	}
+	if errResp := checkOwnedPermCodes(l.ctx, l.svcCtx, userID, operator, []int{code}); errResp != nil {
+		return errResp, nil
+	}
+
+	if err := l.svcCtx.PermissionGrantService.Revoke(l.ctx, perm, userID, req.Reason); err != nil {
+		l.Errorf("撤销授权失败: id=%s, err=%v", req.Id, err)
+		return utils.Response.InternalError("revoke permission failed"), nil
+	}
+	return utils.Response.SuccessWithKey("operation", toUserPermissionInfo(perm)), nil
+}
@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package permission
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UserPermissionListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询员工的直接授权列表
+func NewUserPermissionListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UserPermissionListLogic {
+	return &UserPermissionListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UserPermissionListLogic) UserPermissionList(req *types.UserPermissionListRequest) (resp *types.BaseResponse, err error) {
+	if utils.Validator.IsEmpty(req.EmployeeId) {
+		return utils.Response.ValidationError("employeeId required"), nil
+	}
+
+	_, operator, errResp := loadOperator(l.ctx, l.svcCtx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	target, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.EmployeeId)
+	if err != nil {
+		return utils.Response.BusinessError("employee_not_found"), nil
+	}
+	if target.CompanyId != operator.CompanyId {
+		return utils.Response.BusinessError("permission_cross_company"), nil
+	}
+
+	perms, err := l.svcCtx.UserPermissionModel.FindByUserId(l.ctx, target.UserId, target.CompanyId, req.IncludeInactive)
+	if err != nil {
+		l.Errorf("查询员工授权失败: employeeId=%s, err=%v", req.EmployeeId, err)
+		return utils.Response.InternalError("query permissions failed"), nil
+	}
+
+	list := make([]types.UserPermissionInfo, 0, len(perms))
+	for _, p := range perms {
+		list = append(list, toUserPermissionInfo(p))
+	}
+	return utils.Response.Success(map[string]interface{}{
+		"list": list,
+	}), nil
+}
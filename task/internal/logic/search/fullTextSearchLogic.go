@@ -0,0 +1,100 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type FullTextSearchLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 全文搜索任务、节点、清单、评论和附件
+func NewFullTextSearchLogic(ctx context.Context, svcCtx *svc.ServiceContext) *FullTextSearchLogic {
+	return &FullTextSearchLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *FullTextSearchLogic) FullTextSearch(req *types.FullTextSearchRequest) (resp *types.BaseResponse, err error) {
+	keyword := strings.TrimSpace(req.Keyword)
+	if keyword == "" {
+		return utils.Response.ValidationError("搜索关键字不能为空"), nil
+	}
+	if utf8.RuneCountInString(keyword) > maxSearchKeywordLength {
+		return utils.Response.ValidationError(fmt.Sprintf("搜索关键字不能超过 %d 个字符", maxSearchKeywordLength)), nil
+	}
+	for _, t := range req.Types {
+		if !svc.IsSearchDocType(t) {
+			return utils.Response.ValidationError(fmt.Sprintf("不支持的搜索类型: %s", t)), nil
+		}
+	}
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	}
+	if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+	if page*pageSize > maxSearchWindow {
+		return utils.Response.ValidationError(fmt.Sprintf("最多查看前 %d 条结果，请缩小搜索范围", maxSearchWindow)), nil
+	}
+	if l.svcCtx.SearchService == nil {
+		return utils.Response.BusinessError("search_unavailable"), nil
+	}
+
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	empty := types.FullTextSearchResponse{List: []types.FullTextSearchHit{}, Page: page, PageSize: pageSize}
+	query := svc.SearchQuery{
+		CompanyID: employee.CompanyId,
+		Keyword:   keyword,
+		DocTypes:  req.Types,
+		From:      (page - 1) * pageSize,
+		Size:      pageSize,
+	}
+	// 管理人员可搜索全公司，其他员工只能搜索自己参与的任务下的内容
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		taskIDs, err := l.svcCtx.TaskModel.FindInvolvedIDs(l.ctx, employee.CompanyId, employee.Id)
+		if err != nil {
+			l.Logger.Errorf("查询参与的任务失败: %v", err)
+			return utils.Response.InternalError("搜索失败"), nil
+		}
+		if len(taskIDs) == 0 {
+			return utils.Response.Success(empty), nil
+		}
+		query.TaskIDs = taskIDs
+	}
+
+	hits, total, err := l.svcCtx.SearchService.Search(query)
+	if err != nil {
+		l.Logger.Errorf("全文搜索失败: %v", err)
+		return utils.Response.InternalError("搜索失败"), nil
+	}
+	result := empty
+	result.Total = int64(total)
+	for _, hit := range hits {
+		result.List = append(result.List, toFullTextSearchHit(hit))
+	}
+	return utils.Response.Success(result), nil
+}
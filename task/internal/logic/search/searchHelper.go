@@ -0,0 +1,37 @@
+package search
+
+import (
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 全文搜索限制
+const (
+	maxSearchKeywordLength = 100  // 关键字最大字符数
+	defaultSearchPageSize  = 20   // 默认每页条数
+	maxSearchPageSize      = 50   // 每页最大条数
+	maxSearchWindow        = 1000 // 最多翻到的结果条数
+)
+
+// toFullTextSearchHit 转换搜索结果，附件和附件评论返回所属附件ID
+func toFullTextSearchHit(hit svc.SearchHit) types.FullTextSearchHit {
+	info := types.FullTextSearchHit{
+		Type:           hit.DocType,
+		ID:             hit.ID,
+		TaskID:         hit.TaskID,
+		TaskNodeID:     hit.NodeID,
+		Title:          hit.Title,
+		TitleHighlight: hit.TitleHTML,
+		Snippets:       hit.Snippets,
+		Score:          hit.Score,
+		CreateTime:     utils.Common.FormatTime(hit.CreateTime),
+	}
+	if hit.DocType == svc.SearchDocFile || hit.DocType == svc.SearchDocAttachmentComment {
+		info.FileID = hit.RefID
+	}
+	if info.Snippets == nil {
+		info.Snippets = []string{}
+	}
+	return info
+}
@@ -78,9 +78,12 @@ func (l *AutoDispatchLogic) AutoDispatch(req *types.AutoDispatchRequest) (resp *
 		}
 
 		// 获取候选员工
-		candidates, err := l.getCandidateEmployees(taskNode)
+		candidates, err := l.getCandidateEmployees(taskNode, taskInfo.CompanyId, req.RespectCapacity)
 		if err != nil || len(candidates) == 0 {
 			l.Logger.Infof("节点 %s 没有候选员工", taskNode.NodeName)
+			if req.RespectCapacity {
+				return utils.Response.BusinessError("no_capacity_candidates"), nil
+			}
 			return utils.Response.BusinessError("no_candidates"), nil
 		}
 
@@ -120,7 +123,7 @@ func (l *AutoDispatchLogic) AutoDispatch(req *types.AutoDispatchRequest) (resp *
 			}
 
 			// 获取候选员工
-			candidates, err := l.getCandidateEmployees(taskNode)
+			candidates, err := l.getCandidateEmployees(taskNode, taskInfo.CompanyId, req.RespectCapacity)
 			if err != nil || len(candidates) == 0 {
 				l.Logger.Infof("节点 %s 没有候选员工", taskNode.NodeName)
 				continue
@@ -159,8 +162,19 @@ func (l *AutoDispatchLogic) AutoDispatch(req *types.AutoDispatchRequest) (resp *
 	return utils.Response.Success(result), nil
 }
 
+// RankCandidates 按自动派发的默认评分（当前工作负载）对节点所属部门的候选员工排序，不调用AI服务，
+// 供离职交接计划等需要批量推荐的场景使用
+func (l *AutoDispatchLogic) RankCandidates(taskNode *task.TaskNode, companyID string, respectCapacity bool) []svc.RecommendedEmployee {
+	candidates, err := l.getCandidateEmployees(taskNode, companyID, respectCapacity)
+	if err != nil || len(candidates) == 0 {
+		return nil
+	}
+	return l.getDefaultRecommendation(taskNode, candidates).Candidates
+}
+
 // getCandidateEmployees 获取候选员工列表
-func (l *AutoDispatchLogic) getCandidateEmployees(taskNode *task.TaskNode) ([]svc.EmployeeCandidate, error) {
+// 外出中的员工不参与派发，由其代理人代替进入候选列表；respectCapacity 为 true 时排除承接后超负荷的员工
+func (l *AutoDispatchLogic) getCandidateEmployees(taskNode *task.TaskNode, companyID string, respectCapacity bool) ([]svc.EmployeeCandidate, error) {
 	var candidates []svc.EmployeeCandidate
 
 	// 根据部门获取员工
@@ -169,55 +183,130 @@ func (l *AutoDispatchLogic) getCandidateEmployees(taskNode *task.TaskNode) ([]sv
 		return nil, err
 	}
 
+	added := make(map[string]bool, len(employees))
+	var delegates []*user.Employee
 	for _, emp := range employees {
 		// 排除非在职员工
 		if emp.Status != 1 {
 			continue
 		}
 
-		// 获取员工统计信息
-		activeTasks := l.getActiveTaskCount(emp.Id)
-		completedTasks := l.getCompletedTaskCount(emp.Id)
-		tenureMonths := l.calculateTenureMonths(emp)
+		// 外出中的员工替换为代理人
+		if delegate, ok := l.svcCtx.OutOfOfficeService.ResolveDelegate(l.ctx, emp.Id); ok {
+			delegates = append(delegates, delegate)
+			continue
+		}
+
+		added[emp.Id] = true
+		candidates = append(candidates, l.buildCandidate(emp))
+	}
 
-		// 解析技能
-		var skills []string
-		if emp.Skills.Valid && emp.Skills.String != "" {
-			skills = strings.Split(emp.Skills.String, ",")
+	for _, delegate := range delegates {
+		if added[delegate.Id] {
+			continue
 		}
+		added[delegate.Id] = true
+		candidates = append(candidates, l.buildCandidate(delegate))
+	}
+
+	return l.applyCapacity(taskNode, companyID, candidates, respectCapacity), nil
+}
 
-		// 获取职位名称
-		positionName := ""
-		if emp.PositionId.Valid {
-			pos, err := l.svcCtx.PositionModel.FindOne(l.ctx, emp.PositionId.String)
-			if err == nil {
-				positionName = pos.PositionName
+// applyCapacity 填充候选员工承接节点后的工时负载，respectCapacity 为 true 时作为硬性约束排除超负荷的员工
+func (l *AutoDispatchLogic) applyCapacity(taskNode *task.TaskNode, companyID string, candidates []svc.EmployeeCandidate, respectCapacity bool) []svc.EmployeeCandidate {
+	if len(candidates) == 0 {
+		return candidates
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.EmployeeID)
+	}
+	workload := &task.NodeWorkload{
+		TaskNodeId:    taskNode.TaskNodeId,
+		EstimatedDays: taskNode.EstimatedDays,
+		Progress:      taskNode.Progress,
+		NodeStartTime: taskNode.NodeStartTime,
+		NodeDeadline:  taskNode.NodeDeadline,
+	}
+	fits, err := l.svcCtx.CapacityService.Evaluate(l.ctx, companyID, workload, ids, false)
+	if err != nil {
+		l.Logger.Errorf("计算候选员工负载失败: %v", err)
+		return candidates
+	}
+
+	weekly := l.svcCtx.CapacityService.DefaultWeeklyHours()
+	if capacities, err := l.svcCtx.EmployeeCapacityModel.FindByEmployeeIds(l.ctx, ids); err == nil {
+		custom := make(map[string]float64, len(capacities))
+		for _, c := range capacities {
+			custom[c.EmployeeId] = c.WeeklyHours
+		}
+		for i := range candidates {
+			candidates[i].WeeklyHours = weekly
+			if h, ok := custom[candidates[i].EmployeeID]; ok {
+				candidates[i].WeeklyHours = h
 			}
 		}
+	}
 
-		// 获取部门名称
-		deptName := ""
-		if emp.DepartmentId.Valid {
-			dept, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, emp.DepartmentId.String)
-			if err == nil {
-				deptName = dept.DepartmentName
-			}
+	result := make([]svc.EmployeeCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if fit, ok := fits[c.EmployeeID]; ok {
+			c.AvailableHours = fit.AvailableHours
+			c.AssignedHours = fit.AssignedHours
+			c.Utilization = fit.Utilization
+			c.Overloaded = fit.Over
+		}
+		if respectCapacity && c.Overloaded {
+			l.Logger.Infof("候选员工 %s 承接节点后超负荷（利用率 %.1f%%），不参与派发", c.Name, c.Utilization)
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// buildCandidate 构建候选员工信息
+func (l *AutoDispatchLogic) buildCandidate(emp *user.Employee) svc.EmployeeCandidate {
+	// 获取员工统计信息
+	activeTasks := l.getActiveTaskCount(emp.Id)
+	completedTasks := l.getCompletedTaskCount(emp.Id)
+	tenureMonths := l.calculateTenureMonths(emp)
+
+	// 解析技能
+	var skills []string
+	if emp.Skills.Valid && emp.Skills.String != "" {
+		skills = strings.Split(emp.Skills.String, ",")
+	}
+
+	// 获取职位名称
+	positionName := ""
+	if emp.PositionId.Valid {
+		pos, err := l.svcCtx.PositionModel.FindOne(l.ctx, emp.PositionId.String)
+		if err == nil {
+			positionName = pos.PositionName
 		}
+	}
 
-		candidates = append(candidates, svc.EmployeeCandidate{
-			EmployeeID:     emp.Id,
-			Name:           emp.RealName,
-			Department:     deptName,
-			Position:       positionName,
-			Skills:         skills,
-			TenureMonths:   tenureMonths,
-			ActiveTasks:    activeTasks,
-			CompletedTasks: completedTasks,
-			AvgCompletion:  0.85,
-		})
+	// 获取部门名称
+	deptName := ""
+	if emp.DepartmentId.Valid {
+		dept, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, emp.DepartmentId.String)
+		if err == nil {
+			deptName = dept.DepartmentName
+		}
 	}
 
-	return candidates, nil
+	return svc.EmployeeCandidate{
+		EmployeeID:     emp.Id,
+		Name:           emp.RealName,
+		Department:     deptName,
+		Position:       positionName,
+		Skills:         skills,
+		TenureMonths:   tenureMonths,
+		ActiveTasks:    activeTasks,
+		CompletedTasks: completedTasks,
+		AvgCompletion:  0.85,
+	}
 }
 
 // getActiveTaskCount 获取活跃任务数（包括作为执行人和负责人的任务）
@@ -112,6 +112,8 @@ func (l *CreateTaskLogic) CreateTask(req *types.CreateTaskRequest) (resp *types.
 		l.Logger.WithContext(l.ctx).Errorf("创建任务失败: %v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocTask, taskID)
+	l.svcCtx.ContentService.SaveDetailQuietly(l.ctx, newTask.CompanyId, taskID, "", newTask.TaskDetail)
 
 	// 这里进行通知（通过消息队列）
 	content := fmt.Sprintf("您现在为%s:%s任务的节点负责人，请登录系统进行查看，如无误，请尽快安排人手进行处理", taskID, newTask.TaskTitle)
@@ -0,0 +1,71 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateTaskViewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建任务视图
+func NewCreateTaskViewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateTaskViewLogic {
+	return &CreateTaskViewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateTaskViewLogic) CreateTaskView(req *types.CreateTaskViewRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if msg := validateTaskView(req.Name, &req.Filter, req.Sort); msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	count, err := l.svcCtx.TaskViewModel.CountByEmployee(l.ctx, employee.Id)
+	if err != nil {
+		l.Logger.Errorf("统计任务视图失败: %v", err)
+		return utils.Response.InternalError("创建视图失败"), nil
+	}
+	if count >= maxTaskViews {
+		return utils.Response.BusinessError("saved_view_limit"), nil
+	}
+
+	filterJSON, sortJSON := encodeTaskView(&req.Filter, req.Sort)
+	now := time.Now()
+	view := &taskmodel.TaskView{
+		Id:         utils.Common.GenId("view"),
+		CompanyId:  employee.CompanyId,
+		EmployeeId: employee.Id,
+		Name:       strings.TrimSpace(req.Name),
+		Filter:     filterJSON,
+		Sort:       sortJSON,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if req.Shared {
+		view.Shared = 1
+	}
+	if _, err := l.svcCtx.TaskViewModel.Insert(l.ctx, view); err != nil {
+		l.Logger.Errorf("创建任务视图失败: %v", err)
+		return utils.Response.InternalError("创建视图失败"), nil
+	}
+	return utils.Response.Success(toTaskViewInfo(view, employee.RealName, employee.Id)), nil
+}
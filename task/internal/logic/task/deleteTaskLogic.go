@@ -78,6 +78,7 @@ func (l *DeleteTaskLogic) DeleteTask(req *types.DeleteTaskRequest) (resp *types.
 		l.Logger.WithContext(l.ctx).Errorf("删除任务失败: %v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocTask, req.TaskID) // 任务下的节点、清单、评论和附件一并移出索引
 
 	// 8. 软删除相关任务节点
 	for _, node := range taskNodes {
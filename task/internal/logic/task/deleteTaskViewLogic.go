@@ -0,0 +1,49 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteTaskViewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除任务视图
+func NewDeleteTaskViewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteTaskViewLogic {
+	return &DeleteTaskViewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteTaskViewLogic) DeleteTaskView(req *types.DeleteTaskViewRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	view, errResp := loadTaskView(l.ctx, l.svcCtx, employee, req.ViewID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	// 创建人可以删除自己的视图，管理人员可以删除共享视图
+	if view.EmployeeId != employee.Id && !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.BusinessError("saved_view_denied"), nil
+	}
+	if err := l.svcCtx.TaskViewModel.Delete(l.ctx, view.Id); err != nil {
+		l.Logger.Errorf("删除任务视图失败: viewId=%s, err=%v", view.Id, err)
+		return utils.Response.InternalError("删除视图失败"), nil
+	}
+	return utils.Response.SuccessWithMessage("删除成功", nil), nil
+}
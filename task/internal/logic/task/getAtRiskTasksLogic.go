@@ -0,0 +1,85 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+	"sort"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetAtRiskTasksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 延期风险任务列表
+func NewGetAtRiskTasksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAtRiskTasksLogic {
+	return &GetAtRiskTasksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAtRiskTasksLogic) GetAtRiskTasks(req *types.AtRiskTasksRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxAtRiskTasks {
+		limit = defaultAtRiskTasks
+	}
+
+	// 管理人员查看全公司未完成任务，其他员工查看自己参与的未完成任务
+	var tasks []*taskmodel.Task
+	if l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		tasks, err = l.svcCtx.TaskModel.FindOpenByCompany(l.ctx, employee.CompanyId, maxAtRiskTasks)
+	} else {
+		var involved []*taskmodel.Task
+		involved, _, err = l.svcCtx.TaskModel.FindByInvolved(l.ctx, employee.Id, 1, maxAtRiskTasks)
+		for _, t := range involved {
+			if t.CompanyId == employee.CompanyId && (t.TaskStatus == 0 || t.TaskStatus == 1) {
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	if err != nil {
+		l.Logger.Errorf("查询未完成任务失败: %v", err)
+		return utils.Response.InternalError("获取延期风险任务失败"), nil
+	}
+
+	list := make([]types.TaskForecastInfo, 0)
+	for _, t := range tasks {
+		forecast, err := l.svcCtx.TaskHistoryService.Forecast(l.ctx, t, req.Unit)
+		if err != nil {
+			l.Logger.Errorf("预测任务完成时间失败: taskId=%s, err=%v", t.TaskId, err)
+			continue
+		}
+		if forecast.AtRisk {
+			list = append(list, toForecastInfo(t, forecast))
+		}
+	}
+	// 已逾期的排在前面，其余按截止时间升序
+	sort.SliceStable(list, func(i, j int) bool {
+		oi, oj := list[i].RiskReason == svc.TaskRiskOverdue, list[j].RiskReason == svc.TaskRiskOverdue
+		if oi != oj {
+			return oi
+		}
+		return list[i].Deadline < list[j].Deadline
+	})
+	total := len(list)
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return utils.Response.Success(types.AtRiskTasksResponse{List: list, Total: total}), nil
+}
@@ -0,0 +1,44 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskBurndownLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 任务燃尽图
+func NewGetTaskBurndownLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskBurndownLogic {
+	return &GetTaskBurndownLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskBurndownLogic) GetTaskBurndown(req *types.TaskChartRequest) (resp *types.BaseResponse, err error) {
+	taskInfo, errResp := loadChartTask(l.ctx, l.svcCtx, req.TaskID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	unit := svc.NormalizeBurnUnit(req.Unit)
+	points, err := l.svcCtx.TaskHistoryService.Burn(l.ctx, taskInfo, unit)
+	if err != nil {
+		l.Logger.Errorf("生成燃尽图失败: taskId=%s, err=%v", req.TaskID, err)
+		return utils.Response.InternalError("获取燃尽图失败"), nil
+	}
+	return utils.Response.Success(toBurnResponse(taskInfo, unit, points, false)), nil
+}
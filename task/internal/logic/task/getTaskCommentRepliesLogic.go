@@ -0,0 +1,34 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskCommentRepliesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 分页获取任务评论的回复
+func NewGetTaskCommentRepliesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskCommentRepliesLogic {
+	return &GetTaskCommentRepliesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskCommentRepliesLogic) GetTaskCommentReplies(req *types.GetTaskCommentRepliesRequest) (resp *types.BaseResponse, err error) {
+	// 使用 TaskCommentLogic 来处理分页查询回复
+	commentLogic := NewTaskCommentLogic(l.ctx, l.svcCtx)
+	return commentLogic.GetReplies(req)
+}
@@ -0,0 +1,34 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskCommentRevisionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取任务评论的历史版本
+func NewGetTaskCommentRevisionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskCommentRevisionsLogic {
+	return &GetTaskCommentRevisionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskCommentRevisionsLogic) GetTaskCommentRevisions(req *types.GetTaskCommentRevisionsRequest) (resp *types.BaseResponse, err error) {
+	// 使用 TaskCommentLogic 来处理查询历史版本
+	commentLogic := NewTaskCommentLogic(l.ctx, l.svcCtx)
+	return commentLogic.GetRevisions(req)
+}
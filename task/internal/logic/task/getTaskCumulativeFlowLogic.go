@@ -0,0 +1,45 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskCumulativeFlowLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 任务累积流图
+func NewGetTaskCumulativeFlowLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskCumulativeFlowLogic {
+	return &GetTaskCumulativeFlowLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskCumulativeFlowLogic) GetTaskCumulativeFlow(req *types.GetTaskRequest) (resp *types.BaseResponse, err error) {
+	taskInfo, errResp := loadChartTask(l.ctx, l.svcCtx, req.TaskID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	points, err := l.svcCtx.TaskHistoryService.CumulativeFlow(l.ctx, taskInfo)
+	if err != nil {
+		l.Logger.Errorf("生成累积流图失败: taskId=%s, err=%v", req.TaskID, err)
+		return utils.Response.InternalError("获取累积流图失败"), nil
+	}
+
+	resp = utils.Response.Success(types.TaskFlowResponse{TaskID: taskInfo.TaskId, Points: toFlowPoints(points)})
+	return resp, nil
+}
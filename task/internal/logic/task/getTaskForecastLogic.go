@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskForecastLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 任务完成预测
+func NewGetTaskForecastLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskForecastLogic {
+	return &GetTaskForecastLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskForecastLogic) GetTaskForecast(req *types.TaskChartRequest) (resp *types.BaseResponse, err error) {
+	taskInfo, errResp := loadChartTask(l.ctx, l.svcCtx, req.TaskID)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	forecast, err := l.svcCtx.TaskHistoryService.Forecast(l.ctx, taskInfo, req.Unit)
+	if err != nil {
+		l.Logger.Errorf("预测任务完成时间失败: taskId=%s, err=%v", req.TaskID, err)
+		return utils.Response.InternalError("获取完成预测失败"), nil
+	}
+	return utils.Response.Success(toForecastInfo(taskInfo, forecast)), nil
+}
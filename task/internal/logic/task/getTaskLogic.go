@@ -119,8 +119,25 @@ func (l *GetTaskLogic) GetTask(req *types.GetTaskRequest) (resp *types.BaseRespo
 	// 6. 转换为响应格式
 	converter := utils.NewConverter()
 	taskInfoResp := converter.ToTaskInfo(taskInfo)
+	// 任务和节点详情的 HTML 在写入时渲染保存，这里一次读取
+	detailSources := map[string]string{"": taskInfo.TaskDetail}
+	for _, node := range taskNodes {
+		detailSources[node.TaskNodeId] = node.NodeDetail.String
+	}
+	detailHTMLs := l.svcCtx.ContentService.DetailHTMLs(l.ctx, taskInfo.CompanyId, taskInfo.TaskId, detailSources)
+	taskInfoResp.TaskDescriptionHTML = detailHTMLs[""]
 	// 设置节点数据
 	taskInfoResp.Nodes = converter.ToTaskNodeInfoList(taskNodes)
+	for i, node := range taskNodes {
+		taskInfoResp.Nodes[i].NodeDetailHTML = detailHTMLs[node.TaskNodeId]
+	}
+	// 设置标签和自定义字段
+	withExtras := []types.TaskInfo{taskInfoResp}
+	if err := fillTaskLabelsAndFields(l.ctx, l.svcCtx, taskInfo.CompanyId, withExtras); err != nil {
+		l.Logger.Errorf("查询任务标签和自定义字段失败: %v", err)
+	} else {
+		taskInfoResp = withExtras[0]
+	}
 
 	// 添加权限标识，前端可以根据此字段决定显示哪些操作按钮
 	taskDetail := &types.TaskDetailInfo{
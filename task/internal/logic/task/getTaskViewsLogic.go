@@ -0,0 +1,55 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskViewsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取可用的任务视图
+func NewGetTaskViewsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskViewsLogic {
+	return &GetTaskViewsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskViewsLogic) GetTaskViews() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	views, err := l.svcCtx.TaskViewModel.FindVisible(l.ctx, employee.CompanyId, employee.Id)
+	if err != nil {
+		l.Logger.Errorf("查询任务视图失败: %v", err)
+		return utils.Response.InternalError("获取视图失败"), nil
+	}
+
+	names := map[string]string{employee.Id: employee.RealName}
+	list := make([]types.TaskViewInfo, 0, len(views))
+	for _, view := range views {
+		name, ok := names[view.EmployeeId]
+		if !ok {
+			if owner, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, view.EmployeeId); err == nil {
+				name = owner.RealName
+			}
+			names[view.EmployeeId] = name
+		}
+		list = append(list, toTaskViewInfo(view, name, employee.Id))
+	}
+	return utils.Response.Success(list), nil
+}
@@ -0,0 +1,67 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTaskWatchersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取任务或节点的关注者
+func NewGetTaskWatchersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTaskWatchersLogic {
+	return &GetTaskWatchersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTaskWatchersLogic) GetTaskWatchers(req *types.TaskWatchRequest) (resp *types.BaseResponse, err error) {
+	employeeID, errResp := loadWatchTarget(l.ctx, l.svcCtx, req)
+	if errResp != nil {
+		return errResp, nil
+	}
+	// 被分配的员工自动关注，查询前按当前分配补齐关注记录
+	if err := l.svcCtx.TaskWatcherModel.WatchAssignees(l.ctx, req.TaskID); err != nil {
+		l.Logger.Errorf("同步分配人员关注失败: taskId=%s, err=%v", req.TaskID, err)
+	}
+	watchers, err := l.svcCtx.TaskWatcherModel.FindWatching(l.ctx, req.TaskID, req.TaskNodeID)
+	if err != nil {
+		l.Logger.Errorf("查询任务关注者失败: taskId=%s, taskNodeId=%s, err=%v", req.TaskID, req.TaskNodeID, err)
+		return utils.Response.InternalError("获取关注者失败"), nil
+	}
+
+	watching := false
+	list := make([]types.TaskWatcherInfo, 0, len(watchers))
+	for _, w := range watchers {
+		if w.EmployeeId == employeeID {
+			watching = true
+		}
+		info := types.TaskWatcherInfo{
+			EmployeeID: w.EmployeeId,
+			Source:     w.Source,
+			WatchedAt:  w.UpdateTime.Format("2006-01-02 15:04:05"),
+		}
+		if emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, w.EmployeeId); err == nil {
+			info.EmployeeName = emp.RealName
+		}
+		list = append(list, info)
+	}
+	return utils.Response.Success(map[string]interface{}{
+		"watching": watching,
+		"list":     list,
+		"total":    len(list),
+	}), nil
+}
@@ -0,0 +1,34 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ReactTaskCommentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 切换任务评论表情回应
+func NewReactTaskCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReactTaskCommentLogic {
+	return &ReactTaskCommentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReactTaskCommentLogic) ReactTaskComment(req *types.ReactTaskCommentRequest) (resp *types.BaseResponse, err error) {
+	// 使用 TaskCommentLogic 来处理切换表情回应
+	commentLogic := NewTaskCommentLogic(l.ctx, l.svcCtx)
+	return commentLogic.ReactComment(req)
+}
@@ -0,0 +1,96 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SearchTasksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 任务高级搜索
+func NewSearchTasksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchTasksLogic {
+	return &SearchTasksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SearchTasksLogic) SearchTasks(req *types.TaskSearchRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 使用保存的视图时，以视图中的条件和排序为准
+	filter, sorts := req.Filter, req.Sort
+	if req.ViewID != "" {
+		view, errResp := loadTaskView(l.ctx, l.svcCtx, employee, req.ViewID)
+		if errResp != nil {
+			return errResp, nil
+		}
+		info := toTaskViewInfo(view, "", employee.Id)
+		filter, sorts = info.Filter, info.Sort
+	}
+	if msg := validateSearchFilter(&filter); msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	if msg := validateSearchSorts(sorts); msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	} else if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	isAdmin := filter.Scope != searchScopeInvolved && l.svcCtx.IsCompanyAdmin(l.ctx, employee)
+	modelFilter := toModelSearchFilter(&filter, employee, isAdmin)
+	modelFilter.CustomFields, errResp = resolveCustomFieldConditions(l.ctx, l.svcCtx, employee, filter.CustomFields)
+	if errResp != nil {
+		return errResp, nil
+	}
+	modelSorts := toModelSorts(sorts)
+
+	// 多查一条用于判断是否还有下一页
+	tasks, err := l.svcCtx.TaskModel.SearchByFilter(l.ctx, modelFilter, modelSorts, req.Cursor, limit+1)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrInvalidCursor) {
+			return utils.Response.BusinessError("task_search_cursor_invalid"), nil
+		}
+		l.Logger.Errorf("搜索任务失败: %v", err)
+		return utils.Response.InternalError("搜索任务失败"), nil
+	}
+	total, err := l.svcCtx.TaskModel.CountByFilter(l.ctx, modelFilter)
+	if err != nil {
+		l.Logger.Errorf("统计搜索结果失败: %v", err)
+		return utils.Response.InternalError("搜索任务失败"), nil
+	}
+
+	result := types.TaskSearchResponse{Total: total}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		result.HasMore = true
+		result.NextCursor = taskmodel.EncodeTaskCursor(tasks[len(tasks)-1], modelSorts)
+	}
+	result.List = utils.NewConverter().ToTaskInfoList(tasks)
+	if err := fillTaskLabelsAndFields(l.ctx, l.svcCtx, employee.CompanyId, result.List); err != nil {
+		l.Logger.Errorf("查询任务标签和自定义字段失败: %v", err)
+	}
+	return utils.Response.Success(result), nil
+}
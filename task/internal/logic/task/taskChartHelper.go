@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 延期风险任务列表默认返回数和最多检查的未完成任务数
+const (
+	defaultAtRiskTasks = 50
+	maxAtRiskTasks     = 200
+)
+
+// loadChartTask 校验当前员工可以查看任务图表：同公司，且是任务创建者/负责人/分配者、节点参与者或公司管理人员
+func loadChartTask(ctx context.Context, svcCtx *svc.ServiceContext, taskID string) (*taskmodel.Task, *types.BaseResponse) {
+	if taskID == "" {
+		return nil, utils.Response.BusinessError("task_id_required")
+	}
+	employee, errResp := svcCtx.CurrentOperator(ctx)
+	if errResp != nil {
+		return nil, errResp
+	}
+	taskInfo, err := svcCtx.TaskModel.FindOne(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return nil, utils.Response.BusinessError("task_not_found")
+		}
+		return nil, utils.Response.InternalError("获取任务信息失败")
+	}
+	if taskInfo.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("task_view_denied")
+	}
+	if isTaskMember(taskInfo, employee.Id) || svcCtx.IsCompanyAdmin(ctx, employee) {
+		return taskInfo, nil
+	}
+	nodes, err := svcCtx.TaskNodeModel.FindByTaskID(ctx, taskID)
+	if err == nil {
+		for _, node := range nodes {
+			if utils.Common.ContainsID(node.ExecutorId, employee.Id) || node.LeaderId == employee.Id {
+				return taskInfo, nil
+			}
+		}
+	}
+	return nil, utils.Response.BusinessError("task_view_denied")
+}
+
+// isTaskMember 任务创建者、负责人、分配者或负责人列表中的员工
+func isTaskMember(taskInfo *taskmodel.Task, employeeID string) bool {
+	return taskInfo.TaskCreator == employeeID ||
+		taskInfo.LeaderId.String == employeeID ||
+		taskInfo.TaskAssigner.String == employeeID ||
+		utils.Common.ContainsID(taskInfo.ResponsibleEmployeeIds.String, employeeID)
+}
+
+// toBurnResponse 转换燃尽/燃起图数据，burnup 为 true 时理想线为理想完成量
+func toBurnResponse(taskInfo *taskmodel.Task, unit string, points []svc.TaskBurnPoint, burnup bool) types.TaskBurnResponse {
+	resp := types.TaskBurnResponse{
+		TaskID:   taskInfo.TaskId,
+		Unit:     unit,
+		Deadline: utils.Common.FormatTime(taskInfo.TaskDeadline),
+		Points:   make([]types.TaskBurnPoint, 0, len(points)),
+	}
+	var scopeNow int64
+	if len(points) > 0 {
+		scopeNow = points[len(points)-1].Scope
+	}
+	for _, p := range points {
+		ideal := p.IdealRemaining
+		if burnup {
+			ideal = float64(scopeNow) - p.IdealRemaining
+		}
+		resp.Points = append(resp.Points, types.TaskBurnPoint{
+			Date:      p.Date.Format("2006-01-02"),
+			Scope:     p.Scope,
+			Completed: p.Completed,
+			Remaining: p.Remaining,
+			Ideal:     ideal,
+		})
+	}
+	return resp
+}
+
+// toFlowPoints 转换累积流图数据
+func toFlowPoints(points []svc.TaskFlowPoint) []types.TaskFlowPoint {
+	list := make([]types.TaskFlowPoint, 0, len(points))
+	for _, p := range points {
+		list = append(list, types.TaskFlowPoint{
+			Date:       p.Date.Format("2006-01-02"),
+			NotStarted: p.NotStarted,
+			InProgress: p.InProgress,
+			Overdue:    p.Overdue,
+			Completed:  p.Completed,
+		})
+	}
+	return list
+}
+
+// toForecastInfo 转换完成预测
+func toForecastInfo(taskInfo *taskmodel.Task, f *svc.TaskForecast) types.TaskForecastInfo {
+	info := types.TaskForecastInfo{
+		TaskID:           taskInfo.TaskId,
+		TaskTitle:        taskInfo.TaskTitle,
+		Unit:             f.Unit,
+		Scope:            f.Scope,
+		Completed:        f.Completed,
+		Remaining:        f.Remaining,
+		Velocity:         f.Velocity,
+		RequiredVelocity: f.RequiredVelocity,
+		Deadline:         utils.Common.FormatTime(f.Deadline),
+		AtRisk:           f.AtRisk,
+		RiskReason:       f.RiskReason,
+	}
+	if f.ProjectedDate.Valid {
+		info.ProjectedDate = f.ProjectedDate.Time.Format("2006-01-02")
+	}
+	return info
+}
@@ -0,0 +1,126 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// resolveCustomFieldConditions 按字段类型将自定义字段条件转换为查询条件：
+// 文本为包含，数字和日期支持等于或范围，单选和员工为等于任一值，多选为包含任一选项；
+// 只指定字段不指定值时匹配已填写该字段的任务
+func resolveCustomFieldConditions(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, filters []types.CustomFieldFilter) ([]taskmodel.CustomFieldCondition, *types.BaseResponse) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	fields, err := svcCtx.CustomFieldModel.FindByCompany(ctx, employee.CompanyId, "")
+	if err != nil {
+		return nil, utils.Response.InternalError("查询自定义字段失败")
+	}
+	byID := make(map[string]*taskmodel.CustomField, len(fields))
+	for _, field := range fields {
+		byID[field.Id] = field
+	}
+
+	conds := make([]taskmodel.CustomFieldCondition, 0, len(filters))
+	for _, f := range filters {
+		field, ok := byID[f.FieldID]
+		if !ok {
+			return nil, utils.Response.BusinessError("custom_field_not_found")
+		}
+		cond := taskmodel.CustomFieldCondition{FieldId: field.Id, Node: field.EntityType == taskmodel.CustomFieldEntityNode}
+		values := compactIDs(f.Values)
+		switch field.FieldType {
+		case taskmodel.CustomFieldText:
+			if len(values) > 0 {
+				cond.Contains = values[0]
+			}
+		case taskmodel.CustomFieldNumber, taskmodel.CustomFieldDate:
+			parse := svc.ParseCustomFieldDate
+			if field.FieldType == taskmodel.CustomFieldNumber {
+				parse = svc.ParseCustomFieldNumber
+				cond.Numeric = true
+			}
+			for _, v := range values {
+				normalized, ok := parse(v)
+				if !ok {
+					return nil, utils.Response.ValidationError(fmt.Sprintf("字段「%s」的条件值格式错误", field.Name))
+				}
+				cond.Values = append(cond.Values, normalized)
+			}
+			for _, bound := range []struct {
+				raw    string
+				target *string
+			}{{f.Min, &cond.Min}, {f.Max, &cond.Max}} {
+				if bound.raw == "" {
+					continue
+				}
+				normalized, ok := parse(bound.raw)
+				if !ok {
+					return nil, utils.Response.ValidationError(fmt.Sprintf("字段「%s」的范围格式错误", field.Name))
+				}
+				*bound.target = normalized
+			}
+		case taskmodel.CustomFieldSelect:
+			cond.Values = values
+		case taskmodel.CustomFieldMultiSelect:
+			cond.AnyOf = values
+		case taskmodel.CustomFieldEmployee:
+			cond.Values = resolveEmployeeIDs(values, employee.Id)
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// fillTaskLabelsAndFields 填充任务的标签和已填写的任务自定义字段
+func fillTaskLabelsAndFields(ctx context.Context, svcCtx *svc.ServiceContext, companyID string, list []types.TaskInfo) error {
+	if len(list) == 0 {
+		return nil
+	}
+	taskIDs := make([]string, 0, len(list))
+	for _, t := range list {
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	refs, err := svcCtx.TaskLabelModel.FindByTaskIds(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	labels := make(map[string][]types.TaskLabelBrief)
+	for _, r := range refs {
+		labels[r.TaskId] = append(labels[r.TaskId], types.TaskLabelBrief{ID: r.Id, Name: r.Name, Color: r.Color})
+	}
+
+	fields, err := svcCtx.CustomFieldModel.FindByCompany(ctx, companyID, taskmodel.CustomFieldEntityTask)
+	if err != nil {
+		return err
+	}
+	values, err := svcCtx.CustomFieldService.LoadValues(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	nameOf := svcCtx.CustomFieldService.EmployeeNamer(ctx)
+	for i := range list {
+		list[i].Labels = labels[list[i].ID]
+		for _, field := range fields {
+			value := values[list[i].ID][field.Id]
+			if value == "" {
+				continue
+			}
+			list[i].CustomFields = append(list[i].CustomFields, types.CustomFieldValueInfo{
+				FieldID:   field.Id,
+				Name:      field.Name,
+				FieldType: field.FieldType,
+				Values:    svc.SplitCustomFieldValue(field, value),
+				Display:   svcCtx.CustomFieldService.FormatValue(field, value, nameOf),
+			})
+		}
+	}
+	return nil
+}
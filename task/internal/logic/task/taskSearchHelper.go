@@ -0,0 +1,297 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 高级搜索分页和条件数量限制
+const (
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 100
+	maxSearchSortFields   = 3
+	maxSearchValues       = 50 // 单个列表条件最多的值个数
+	maxTaskViews          = 50 // 每个员工最多创建的视图数
+	maxSearchCustomFields = 10 // 最多的自定义字段条件数
+)
+
+// 标签匹配方式
+const (
+	labelMatchAny = "any"
+	labelMatchAll = "all"
+)
+
+// currentEmployeeToken 员工条件中表示当前员工，便于共享“我负责的”之类的视图
+const currentEmployeeToken = "me"
+
+// 搜索范围
+const (
+	searchScopeInvolved = "involved"
+	searchScopeCompany  = "company"
+)
+
+// validateSearchFilter 校验搜索条件，返回错误提示
+func validateSearchFilter(f *types.TaskSearchFilter) string {
+	switch f.Scope {
+	case "", searchScopeInvolved, searchScopeCompany:
+	default:
+		return "搜索范围只支持 involved 或 company"
+	}
+	for _, ids := range [][]string{f.AssigneeIDs, f.LeaderIDs, f.CreatorIDs, f.DepartmentIDs} {
+		if len(ids) > maxSearchValues {
+			return fmt.Sprintf("单个条件最多指定 %d 个值", maxSearchValues)
+		}
+	}
+	for _, v := range f.Statuses {
+		if v < 0 || v > 3 {
+			return "任务状态无效"
+		}
+	}
+	for _, v := range f.Priorities {
+		if v < 0 || v > 3 {
+			return "任务优先级无效"
+		}
+	}
+	for _, v := range f.TaskTypes {
+		if v < 0 || v > 1 {
+			return "任务类型无效"
+		}
+	}
+	if _, _, err := parseSearchDate(f.DeadlineFrom); err != nil {
+		return "截止时间下限格式错误"
+	}
+	if _, _, err := parseSearchDate(f.DeadlineTo); err != nil {
+		return "截止时间上限格式错误"
+	}
+	if f.Overdue < taskmodel.OverdueAny || f.Overdue > taskmodel.OverdueNo {
+		return "逾期条件无效"
+	}
+	for _, p := range []*int{f.ProgressMin, f.ProgressMax} {
+		if p != nil && (*p < 0 || *p > 100) {
+			return "进度范围为 0-100"
+		}
+	}
+	if f.ProgressMin != nil && f.ProgressMax != nil && *f.ProgressMin > *f.ProgressMax {
+		return "进度下限不能大于上限"
+	}
+	switch f.Prerequisite {
+	case "", taskmodel.PrerequisiteBlocked, taskmodel.PrerequisiteReady:
+	default:
+		return "前置节点条件只支持 blocked 或 ready"
+	}
+	if len(f.LabelIDs) > maxSearchValues {
+		return fmt.Sprintf("单个条件最多指定 %d 个值", maxSearchValues)
+	}
+	switch f.LabelMatch {
+	case "", labelMatchAny, labelMatchAll:
+	default:
+		return "标签匹配方式只支持 any 或 all"
+	}
+	if len(f.CustomFields) > maxSearchCustomFields {
+		return fmt.Sprintf("最多指定 %d 个自定义字段条件", maxSearchCustomFields)
+	}
+	for _, c := range f.CustomFields {
+		if c.FieldID == "" {
+			return "自定义字段ID不能为空"
+		}
+		if len(c.Values) > maxSearchValues {
+			return fmt.Sprintf("单个条件最多指定 %d 个值", maxSearchValues)
+		}
+	}
+	return ""
+}
+
+// validateSearchSorts 校验排序字段，返回错误提示
+func validateSearchSorts(sorts []types.TaskSortField) string {
+	if len(sorts) > maxSearchSortFields {
+		return fmt.Sprintf("最多按 %d 个字段排序", maxSearchSortFields)
+	}
+	seen := make(map[string]bool, len(sorts))
+	for _, s := range sorts {
+		if !taskmodel.IsTaskSortField(s.Field) {
+			return fmt.Sprintf("不支持按 %s 排序", s.Field)
+		}
+		if seen[s.Field] {
+			return fmt.Sprintf("排序字段 %s 重复", s.Field)
+		}
+		seen[s.Field] = true
+		if s.Order != "" && s.Order != "asc" && s.Order != "desc" {
+			return "排序方向只支持 asc 或 desc"
+		}
+	}
+	return ""
+}
+
+// toModelSearchFilter 将已校验的搜索条件转换为查询条件；管理人员可查询全公司，其他员工只能查询自己参与的任务
+func toModelSearchFilter(f *types.TaskSearchFilter, employee *user.Employee, isAdmin bool) *taskmodel.TaskSearchFilter {
+	filter := &taskmodel.TaskSearchFilter{
+		CompanyId:     employee.CompanyId,
+		Keyword:       strings.TrimSpace(f.Keyword),
+		Statuses:      toInt64s(f.Statuses),
+		Priorities:    toInt64s(f.Priorities),
+		TaskTypes:     toInt64s(f.TaskTypes),
+		AssigneeIds:   resolveEmployeeIDs(f.AssigneeIDs, employee.Id),
+		LeaderIds:     resolveEmployeeIDs(f.LeaderIDs, employee.Id),
+		CreatorIds:    resolveEmployeeIDs(f.CreatorIDs, employee.Id),
+		DepartmentIds: compactIDs(f.DepartmentIDs),
+		Overdue:       f.Overdue,
+		Prerequisite:  f.Prerequisite,
+		LabelIds:      compactIDs(f.LabelIDs),
+		LabelMatchAll: f.LabelMatch == labelMatchAll,
+	}
+	if f.Scope == searchScopeInvolved || !isAdmin {
+		filter.InvolvedEmployeeId = employee.Id
+	}
+	if t, _, _ := parseSearchDate(f.DeadlineFrom); !t.IsZero() {
+		filter.DeadlineFrom = &t
+	}
+	if t, dateOnly, _ := parseSearchDate(f.DeadlineTo); !t.IsZero() {
+		// 只填日期时包含当天
+		if dateOnly {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		filter.DeadlineTo = &t
+	}
+	if f.ProgressMin != nil {
+		v := int64(*f.ProgressMin)
+		filter.ProgressMin = &v
+	}
+	if f.ProgressMax != nil {
+		v := int64(*f.ProgressMax)
+		filter.ProgressMax = &v
+	}
+	return filter
+}
+
+// toModelSorts 转换排序字段，未指定时按创建时间倒序
+func toModelSorts(sorts []types.TaskSortField) []taskmodel.TaskSortField {
+	if len(sorts) == 0 {
+		return []taskmodel.TaskSortField{{Field: "createTime", Desc: true}}
+	}
+	result := make([]taskmodel.TaskSortField, 0, len(sorts))
+	for _, s := range sorts {
+		result = append(result, taskmodel.TaskSortField{Field: s.Field, Desc: s.Order == "desc"})
+	}
+	return result
+}
+
+// parseSearchDate 解析 YYYY-MM-DD 或 YYYY-MM-DD HH:mm:ss，空字符串返回零值
+func parseSearchDate(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	return t, false, err
+}
+
+// resolveEmployeeIDs 去除空值并将 me 替换为当前员工ID
+func resolveEmployeeIDs(ids []string, employeeID string) []string {
+	result := compactIDs(ids)
+	for i, id := range result {
+		if id == currentEmployeeToken {
+			result[i] = employeeID
+		}
+	}
+	return result
+}
+
+// compactIDs 去除空值和重复值
+func compactIDs(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func toInt64s(values []int) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		result = append(result, int64(v))
+	}
+	return result
+}
+
+// loadTaskView 获取当前员工可用的视图：同公司，且是自己创建的或共享的
+func loadTaskView(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, viewID string) (*taskmodel.TaskView, *types.BaseResponse) {
+	if viewID == "" {
+		return nil, utils.Response.ValidationError("视图ID不能为空")
+	}
+	view, err := svcCtx.TaskViewModel.FindOne(ctx, viewID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return nil, utils.Response.BusinessError("saved_view_not_found")
+		}
+		return nil, utils.Response.InternalError("获取视图失败")
+	}
+	if view.CompanyId != employee.CompanyId || (view.EmployeeId != employee.Id && view.Shared != 1) {
+		return nil, utils.Response.BusinessError("saved_view_not_found")
+	}
+	return view, nil
+}
+
+// toTaskViewInfo 转换保存的视图，条件解析失败时返回空条件
+func toTaskViewInfo(view *taskmodel.TaskView, ownerName, employeeID string) types.TaskViewInfo {
+	info := types.TaskViewInfo{
+		ID:         view.Id,
+		Name:       view.Name,
+		Shared:     view.Shared == 1,
+		OwnerID:    view.EmployeeId,
+		OwnerName:  ownerName,
+		IsOwner:    view.EmployeeId == employeeID,
+		Sort:       []types.TaskSortField{},
+		CreateTime: utils.Common.FormatTime(view.CreateTime),
+		UpdateTime: utils.Common.FormatTime(view.UpdateTime),
+	}
+	_ = json.Unmarshal([]byte(view.Filter), &info.Filter)
+	if view.Sort != "" {
+		_ = json.Unmarshal([]byte(view.Sort), &info.Sort)
+	}
+	return info
+}
+
+// validateTaskView 校验视图名称、条件和排序，返回错误提示
+func validateTaskView(name string, filter *types.TaskSearchFilter, sorts []types.TaskSortField) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "视图名称不能为空"
+	}
+	if len([]rune(name)) > 64 {
+		return "视图名称不能超过 64 个字符"
+	}
+	if msg := validateSearchFilter(filter); msg != "" {
+		return msg
+	}
+	return validateSearchSorts(sorts)
+}
+
+// encodeTaskView 序列化视图的条件和排序
+func encodeTaskView(filter *types.TaskSearchFilter, sorts []types.TaskSortField) (string, string) {
+	filterJSON, _ := json.Marshal(filter)
+	sortJSON := ""
+	if len(sorts) > 0 {
+		data, _ := json.Marshal(sorts)
+		sortJSON = string(data)
+	}
+	return string(filterJSON), sortJSON
+}
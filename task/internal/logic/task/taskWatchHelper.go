@@ -0,0 +1,55 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	taskmodel "task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// loadWatchTarget 校验关注目标：任务属于当前公司且未删除，指定节点时节点属于该任务。
+// 关注不要求是任务参与者，同公司的员工都可以关注
+func loadWatchTarget(ctx context.Context, svcCtx *svc.ServiceContext, req *types.TaskWatchRequest) (string, *types.BaseResponse) {
+	if req.TaskID == "" {
+		return "", utils.Response.BusinessError("task_id_required")
+	}
+	employeeID, ok := utils.Common.GetCurrentEmployeeID(ctx)
+	if !ok || employeeID == "" {
+		return "", utils.Response.UnauthorizedError()
+	}
+	companyID, _ := utils.Common.GetCurrentCompanyID(ctx)
+
+	taskInfo, err := svcCtx.TaskModel.FindOne(ctx, req.TaskID)
+	if err != nil {
+		if errors.Is(err, taskmodel.ErrNotFound) {
+			return "", utils.Response.BusinessError("task_not_found")
+		}
+		return "", utils.Response.InternalError("获取任务信息失败")
+	}
+	if taskInfo.DeleteTime.Valid {
+		return "", utils.Response.BusinessError("task_not_found")
+	}
+	if taskInfo.CompanyId != companyID {
+		return "", utils.Response.BusinessError("task_view_denied")
+	}
+
+	if req.TaskNodeID != "" {
+		node, err := svcCtx.TaskNodeModel.FindOne(ctx, req.TaskNodeID)
+		if err != nil {
+			if errors.Is(err, taskmodel.ErrNotFound) {
+				return "", utils.Response.BusinessError("task_node_not_found")
+			}
+			return "", utils.Response.InternalError("获取任务节点失败")
+		}
+		if node.DeleteTime.Valid {
+			return "", utils.Response.BusinessError("task_node_not_found")
+		}
+		if node.TaskId != req.TaskID {
+			return "", utils.Response.BusinessError("watch_node_mismatch")
+		}
+	}
+	return employeeID, nil
+}
@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"task_Project/model/task"
@@ -27,6 +28,14 @@ func NewTaskCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TaskC
 	}
 }
 
+const (
+	defaultCommentReplyLimit = 3  // 评论列表中每个评论附带的回复数
+	maxCommentReplyLimit     = 20 // 评论列表中每个评论最多附带的回复数
+)
+
+// commentReactionEmojis 支持的表情回应，按展示顺序排列
+var commentReactionEmojis = []string{"👍", "👎", "❤️", "🎉", "😄", "😕", "👀", "🚀"}
+
 // CreateComment 创建任务评论
 func (l *TaskCommentLogic) CreateComment(req *types.CreateTaskCommentRequest) (resp *types.BaseResponse, err error) {
 	if req.TaskID == "" {
@@ -44,24 +53,32 @@ func (l *TaskCommentLogic) CreateComment(req *types.CreateTaskCommentRequest) (r
 	employeeID, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
 	realName, _ := utils.Common.GetCurrentRealName(l.ctx)
 
-	// 获取@的员工姓名列表
-	var atEmployeeNames []string
-	if len(req.AtEmployeeIDs) > 0 {
-		for _, empID := range req.AtEmployeeIDs {
-			emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, empID)
-			if err == nil && emp != nil {
-				atEmployeeNames = append(atEmployeeNames, emp.RealName)
-			}
-		}
+	// 渲染并净化内容，解析@提及和任务、节点引用
+	companyID, _ := utils.Common.GetCurrentCompanyID(l.ctx)
+	rendered := l.svcCtx.ContentService.RenderInput(l.ctx, companyID, req.Content, req.ContentHTML, req.AtEmployeeIDs)
+	if rendered.IsEmpty() && len(req.AttachmentIDs) == 0 {
+		return utils.Response.ValidationError("评论内容不能为空"), nil
 	}
 
-	// 获取回复目标信息
+	// 获取@的员工姓名列表
+	atEmployeeNames := l.atEmployeeNames(rendered.MentionIDs)
+
+	// 获取回复目标信息，回复的回复挂到顶级评论下，保持两层线程
 	var replyToUserID, replyToName string
-	if req.ParentID != "" {
-		parentComment, err := l.svcCtx.TaskCommentModel.FindByCommentID(l.ctx, req.ParentID)
-		if err == nil && parentComment != nil {
-			replyToUserID = parentComment.UserID
-			replyToName = parentComment.EmployeeName
+	parentID := req.ParentID
+	if parentID != "" {
+		parentComment, err := l.svcCtx.TaskCommentModel.FindByCommentID(l.ctx, parentID)
+		if err != nil {
+			if errors.Is(err, task.ErrNotFound) {
+				return utils.Response.NotFoundError("comment_not_found"), nil
+			}
+			logx.Errorf("查询父评论失败: %v", err)
+			return utils.Response.InternalError("创建评论失败"), nil
+		}
+		replyToUserID = parentComment.UserID
+		replyToName = parentComment.EmployeeName
+		if parentComment.ParentID != "" {
+			parentID = parentComment.ParentID
 		}
 	}
 
@@ -87,10 +104,12 @@ func (l *TaskCommentLogic) CreateComment(req *types.CreateTaskCommentRequest) (r
 		EmployeeID:      employeeID,
 		EmployeeName:    realName,
 		Content:         req.Content,
-		ContentHTML:     req.ContentHTML,
-		AtEmployeeIDs:   req.AtEmployeeIDs,
+		ContentHTML:     rendered.HTML,
+		ContentText:     rendered.Text,
+		AtEmployeeIDs:   rendered.MentionIDs,
 		AtEmployeeNames: atEmployeeNames,
-		ParentID:        req.ParentID,
+		AtGroupIDs:      rendered.GroupIDs,
+		ParentID:        parentID,
 		ReplyToUserID:   replyToUserID,
 		ReplyToName:     replyToName,
 		AttachmentIDs:   req.AttachmentIDs,
@@ -105,35 +124,20 @@ func (l *TaskCommentLogic) CreateComment(req *types.CreateTaskCommentRequest) (r
 		logx.Errorf("创建评论失败: %v", err)
 		return utils.Response.InternalError("创建评论失败"), nil
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocComment, commentID)
 
-	// 发送@通知
-	if len(req.AtEmployeeIDs) > 0 && l.svcCtx.NotificationMQService != nil {
-		go func() {
-			event := &svc.NotificationEvent{
-				EventType:   "comment.mention",
-				EmployeeIDs: req.AtEmployeeIDs,
-				Title:       "评论中被@提及",
-				Content:     realName + "在任务评论中@了你: " + req.Content,
-				Type:        3, // 类型: 系统通知
-				Category:    "comment",
-				Priority:    2, // 优先级: 普通
-				RelatedID:   commentID,
-				RelatedType: "task_comment",
-				TaskID:      req.TaskID,
-				NodeID:      req.TaskNodeID,
-			}
-			if err := l.svcCtx.NotificationMQService.PublishNotificationEvent(l.ctx, event); err != nil {
-				logx.Errorf("发送@通知失败: %v", err)
-			}
-		}()
-	}
+	// 发送@通知，评论作者和被@的员工自动关注，再通知其他关注者
+	mentioned := l.mentionRecipients(rendered.MentionIDs, rendered.MemberIDs, employeeID)
+	l.notifyMentions(comment, mentioned, realName)
+	l.watchComment(comment, rendered.MentionIDs)
+	l.notifyWatchers(comment, mentioned, realName)
 
 	return utils.Response.Success(map[string]interface{}{
 		"commentId": commentID,
 	}), nil
 }
 
-// GetComments 获取任务评论列表
+// GetComments 获取任务评论列表，按顶级评论分页，每个评论附带最早的几条回复和回复总数
 func (l *TaskCommentLogic) GetComments(req *types.GetTaskCommentsRequest) (resp *types.BaseResponse, err error) {
 	page := int64(req.Page)
 	pageSize := int64(req.PageSize)
@@ -143,43 +147,48 @@ func (l *TaskCommentLogic) GetComments(req *types.GetTaskCommentsRequest) (resp
 	if pageSize <= 0 {
 		pageSize = 20
 	}
-
-	var comments []*task.Task_comment
-	var total int64
-
-	if req.TaskNodeID != "" {
-		comments, total, err = l.svcCtx.TaskCommentModel.FindByTaskNodeID(l.ctx, req.TaskNodeID, page, pageSize)
-	} else if req.TaskID != "" {
-		comments, total, err = l.svcCtx.TaskCommentModel.FindByTaskID(l.ctx, req.TaskID, page, pageSize)
-	} else {
+	replyLimit := int64(req.ReplyLimit)
+	if replyLimit <= 0 {
+		replyLimit = defaultCommentReplyLimit
+	}
+	if replyLimit > maxCommentReplyLimit {
+		replyLimit = maxCommentReplyLimit
+	}
+	if req.TaskID == "" && req.TaskNodeID == "" {
 		return utils.Response.ValidationError("任务ID或任务节点ID不能同时为空"), nil
 	}
 
+	comments, total, err := l.svcCtx.TaskCommentModel.FindThreads(l.ctx, req.TaskID, req.TaskNodeID, page, pageSize)
 	if err != nil {
 		logx.Errorf("查询评论失败: %v", err)
 		return utils.Response.InternalError("查询评论失败"), nil
 	}
 
-	// 获取当前用户ID（用于判断是否已点赞）
+	// 获取当前用户ID（用于判断是否已点赞和能否编辑）
 	currentUserID, _ := utils.Common.GetCurrentUserID(l.ctx)
+	manageable := make(map[string]bool)
+
+	// 一次查询本页全部顶级评论的回复
+	parentIDs := make([]string, 0, len(comments))
+	for _, c := range comments {
+		parentIDs = append(parentIDs, c.CommentID)
+	}
+	replies, replyCounts, err := l.svcCtx.TaskCommentModel.FindRepliesByParents(l.ctx, parentIDs, replyLimit)
+	if err != nil {
+		logx.Errorf("查询评论回复失败: %v", err)
+	}
 
 	// 转换为响应格式
 	list := make([]types.TaskCommentInfo, 0, len(comments))
 	for _, c := range comments {
-		// 只返回顶级评论
-		if c.ParentID != "" {
-			continue
+		replyList := make([]types.TaskCommentInfo, 0, len(replies[c.CommentID]))
+		for _, r := range replies[c.CommentID] {
+			replyList = append(replyList, l.commentInfoFor(r, currentUserID, manageable))
 		}
 
-		// 获取回复列表
-		replies, _ := l.svcCtx.TaskCommentModel.FindReplies(l.ctx, c.CommentID)
-		replyList := make([]types.TaskCommentInfo, 0, len(replies))
-		for _, r := range replies {
-			replyList = append(replyList, l.convertToCommentInfo(r, currentUserID))
-		}
-
-		info := l.convertToCommentInfo(c, currentUserID)
+		info := l.commentInfoFor(c, currentUserID, manageable)
 		info.Replies = replyList
+		info.ReplyCount = replyCounts[c.CommentID]
 		list = append(list, info)
 	}
 
@@ -191,6 +200,207 @@ func (l *TaskCommentLogic) GetComments(req *types.GetTaskCommentsRequest) (resp
 	}), nil
 }
 
+// GetReplies 分页获取评论的回复
+func (l *TaskCommentLogic) GetReplies(req *types.GetTaskCommentRepliesRequest) (resp *types.BaseResponse, err error) {
+	if req.CommentID == "" {
+		return utils.Response.ValidationError("评论ID不能为空"), nil
+	}
+	page := int64(req.Page)
+	pageSize := int64(req.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	comment, resp := l.findComment(req.CommentID)
+	if resp != nil {
+		return resp, nil
+	}
+	parentID := comment.CommentID
+	if comment.ParentID != "" {
+		parentID = comment.ParentID
+	}
+
+	replies, total, err := l.svcCtx.TaskCommentModel.FindRepliesPage(l.ctx, parentID, page, pageSize)
+	if err != nil {
+		logx.Errorf("查询评论回复失败: %v", err)
+		return utils.Response.InternalError("查询评论回复失败"), nil
+	}
+
+	currentUserID, _ := utils.Common.GetCurrentUserID(l.ctx)
+	manageable := make(map[string]bool)
+	list := make([]types.TaskCommentInfo, 0, len(replies))
+	for _, r := range replies {
+		list = append(list, l.commentInfoFor(r, currentUserID, manageable))
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"list":     list,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	}), nil
+}
+
+// UpdateComment 编辑评论，旧内容保存为历史版本，只有评论作者和任务负责人可以编辑
+func (l *TaskCommentLogic) UpdateComment(req *types.UpdateTaskCommentRequest) (resp *types.BaseResponse, err error) {
+	if req.CommentID == "" {
+		return utils.Response.ValidationError("评论ID不能为空"), nil
+	}
+	if req.Content == "" {
+		return utils.Response.ValidationError("评论内容不能为空"), nil
+	}
+
+	userID, ok := utils.Common.GetCurrentUserID(l.ctx)
+	if !ok {
+		return utils.Response.UnauthorizedError(), nil
+	}
+	employeeID, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
+	realName, _ := utils.Common.GetCurrentRealName(l.ctx)
+
+	comment, resp := l.findComment(req.CommentID)
+	if resp != nil {
+		return resp, nil
+	}
+	if !l.canManage(comment, userID, employeeID, nil) {
+		return utils.Response.BusinessError("comment_no_permission"), nil
+	}
+
+	companyID, _ := utils.Common.GetCurrentCompanyID(l.ctx)
+	rendered := l.svcCtx.ContentService.RenderInput(l.ctx, companyID, req.Content, req.ContentHTML, req.AtEmployeeIDs)
+	if rendered.IsEmpty() && len(comment.AttachmentIDs) == 0 {
+		return utils.Response.ValidationError("评论内容不能为空"), nil
+	}
+	atEmployeeIDs := rendered.MentionIDs
+	if atEmployeeIDs == nil {
+		atEmployeeIDs = []string{}
+	}
+	if req.Content == comment.Content && rendered.HTML == comment.ContentHTML && sameStringSet(atEmployeeIDs, comment.AtEmployeeIDs) &&
+		sameStringSet(rendered.GroupIDs, comment.AtGroupIDs) {
+		return utils.Response.Success(map[string]interface{}{
+			"commentId": comment.CommentID,
+			"editCount": comment.EditCount,
+		}), nil
+	}
+
+	revision := task.Task_commentRevision{
+		Content:     comment.Content,
+		ContentHTML: comment.ContentHTML,
+		EditedBy:    employeeID,
+		EditorName:  realName,
+		EditedAt:    time.Now(),
+	}
+	previousMentions := comment.AtEmployeeIDs
+	previousGroups := comment.AtGroupIDs
+	editCount := comment.EditCount
+	comment.Content = req.Content
+	comment.ContentHTML = rendered.HTML
+	comment.ContentText = rendered.Text
+	comment.AtEmployeeIDs = atEmployeeIDs
+	comment.AtEmployeeNames = l.atEmployeeNames(atEmployeeIDs)
+	comment.AtGroupIDs = rendered.GroupIDs
+
+	updated, err := l.svcCtx.TaskCommentModel.EditContent(l.ctx, comment, editCount, revision)
+	if err != nil {
+		logx.Errorf("编辑评论失败: %v", err)
+		return utils.Response.InternalError("编辑评论失败"), nil
+	}
+	if !updated {
+		return utils.Response.BusinessError("comment_edit_conflict"), nil
+	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocComment, comment.CommentID)
+
+	// 只通知本次编辑新增@的员工；新增了@部门或@角色时通知其成员（不含已被@过的员工）
+	var newMentions []string
+	for _, id := range atEmployeeIDs {
+		if !containsString(previousMentions, id) {
+			newMentions = append(newMentions, id)
+		}
+	}
+	var newMembers []string
+	for _, id := range rendered.GroupIDs {
+		if !containsString(previousGroups, id) {
+			newMembers = rendered.MemberIDs
+			break
+		}
+	}
+	var members []string
+	for _, id := range newMembers {
+		if !containsString(previousMentions, id) {
+			members = append(members, id)
+		}
+	}
+	l.notifyMentions(comment, l.mentionRecipients(newMentions, members, employeeID), realName)
+	l.watchComment(comment, newMentions)
+
+	return utils.Response.Success(map[string]interface{}{
+		"commentId": comment.CommentID,
+		"editCount": editCount + 1,
+	}), nil
+}
+
+// GetRevisions 获取评论的历史版本，最新的在前
+func (l *TaskCommentLogic) GetRevisions(req *types.GetTaskCommentRevisionsRequest) (resp *types.BaseResponse, err error) {
+	if req.CommentID == "" {
+		return utils.Response.ValidationError("评论ID不能为空"), nil
+	}
+
+	comment, resp := l.findComment(req.CommentID)
+	if resp != nil {
+		return resp, nil
+	}
+
+	list := make([]types.TaskCommentRevisionInfo, 0, len(comment.Revisions))
+	for i := len(comment.Revisions) - 1; i >= 0; i-- {
+		r := comment.Revisions[i]
+		list = append(list, types.TaskCommentRevisionInfo{
+			Content:     r.Content,
+			ContentHTML: utils.SanitizeHTML(r.ContentHTML),
+			EditedBy:    r.EditedBy,
+			EditorName:  r.EditorName,
+			EditedAt:    r.EditedAt.Format(time.RFC3339),
+		})
+	}
+
+	currentUserID, _ := utils.Common.GetCurrentUserID(l.ctx)
+	return utils.Response.Success(map[string]interface{}{
+		"current":   l.commentInfoFor(comment, currentUserID, nil),
+		"revisions": list,
+		"editCount": comment.EditCount,
+	}), nil
+}
+
+// ReactComment 切换当前用户对评论的表情回应
+func (l *TaskCommentLogic) ReactComment(req *types.ReactTaskCommentRequest) (resp *types.BaseResponse, err error) {
+	if req.CommentID == "" {
+		return utils.Response.ValidationError("评论ID不能为空"), nil
+	}
+	if !containsString(commentReactionEmojis, req.Emoji) {
+		return utils.Response.BusinessError("comment_reaction_invalid"), nil
+	}
+
+	userID, ok := utils.Common.GetCurrentUserID(l.ctx)
+	if !ok {
+		return utils.Response.UnauthorizedError(), nil
+	}
+
+	reacted, err := l.svcCtx.TaskCommentModel.ToggleReaction(l.ctx, req.CommentID, req.Emoji, userID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return utils.Response.NotFoundError("comment_not_found"), nil
+		}
+		logx.Errorf("表情回应失败: %v", err)
+		return utils.Response.InternalError("操作失败"), nil
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"emoji":   req.Emoji,
+		"reacted": reacted,
+	}), nil
+}
+
 // LikeComment 点赞/取消点赞评论
 func (l *TaskCommentLogic) LikeComment(req *types.LikeCommentRequest) (resp *types.BaseResponse, err error) {
 	if req.CommentID == "" {
@@ -222,15 +432,16 @@ func (l *TaskCommentLogic) DeleteComment(req *types.DeleteTaskCommentRequest) (r
 		return utils.Response.ValidationError("评论ID不能为空"), nil
 	}
 
-	// 验证是否是评论作者
-	comment, err := l.svcCtx.TaskCommentModel.FindByCommentID(l.ctx, req.CommentID)
-	if err != nil {
-		return utils.Response.NotFoundError("评论不存在"), nil
+	comment, resp := l.findComment(req.CommentID)
+	if resp != nil {
+		return resp, nil
 	}
 
+	// 评论作者和任务负责人可以删除
 	currentUserID, _ := utils.Common.GetCurrentUserID(l.ctx)
-	if comment.UserID != currentUserID {
-		return utils.Response.ForbiddenError("无权删除此评论"), nil
+	employeeID, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
+	if !l.canManage(comment, currentUserID, employeeID, nil) {
+		return utils.Response.BusinessError("comment_no_permission"), nil
 	}
 
 	err = l.svcCtx.TaskCommentModel.SoftDelete(l.ctx, comment.ID.Hex())
@@ -238,6 +449,7 @@ func (l *TaskCommentLogic) DeleteComment(req *types.DeleteTaskCommentRequest) (r
 		logx.Errorf("删除评论失败: %v", err)
 		return utils.Response.InternalError("删除评论失败"), nil
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocComment, comment.CommentID)
 
 	return utils.Response.Success(nil), nil
 }
@@ -252,6 +464,11 @@ func (l *TaskCommentLogic) convertToCommentInfo(c *task.Task_comment, currentUse
 		}
 	}
 
+	var editedAt string
+	if c.Edited {
+		editedAt = c.EditedAt.Format(time.RFC3339)
+	}
+
 	return types.TaskCommentInfo{
 		ID:              c.ID.Hex(),
 		CommentID:       c.CommentID,
@@ -261,7 +478,7 @@ func (l *TaskCommentLogic) convertToCommentInfo(c *task.Task_comment, currentUse
 		EmployeeID:      c.EmployeeID,
 		EmployeeName:    c.EmployeeName,
 		Content:         c.Content,
-		ContentHTML:     c.ContentHTML,
+		ContentHTML:     commentHTML(c),
 		AtEmployeeIDs:   c.AtEmployeeIDs,
 		AtEmployeeNames: c.AtEmployeeNames,
 		ParentID:        c.ParentID,
@@ -271,7 +488,216 @@ func (l *TaskCommentLogic) convertToCommentInfo(c *task.Task_comment, currentUse
 		AttachmentURLs:  c.AttachmentURLs,
 		LikeCount:       int64(c.LikeCount),
 		IsLiked:         isLiked,
+		Reactions:       convertCommentReactions(c.Reactions, currentUserID),
+		Edited:          c.Edited,
+		EditedAt:        editedAt,
 		CreateTime:      c.CreateAt.Format(time.RFC3339),
 		UpdateTime:      c.UpdateAt.Format(time.RFC3339),
 	}
 }
+
+// commentInfoFor 转换评论信息并标记当前用户能否编辑，manageable 缓存当前员工能否管理各任务的评论
+func (l *TaskCommentLogic) commentInfoFor(c *task.Task_comment, currentUserID string, manageable map[string]bool) types.TaskCommentInfo {
+	info := l.convertToCommentInfo(c, currentUserID)
+	employeeID, _ := utils.Common.GetCurrentEmployeeID(l.ctx)
+	info.CanEdit = l.canManage(c, currentUserID, employeeID, manageable)
+	return info
+}
+
+// findComment 按评论ID查询未删除的评论，查询失败时返回错误响应
+func (l *TaskCommentLogic) findComment(commentID string) (*task.Task_comment, *types.BaseResponse) {
+	comment, err := l.svcCtx.TaskCommentModel.FindByCommentID(l.ctx, commentID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, utils.Response.NotFoundError("comment_not_found")
+		}
+		logx.Errorf("查询评论失败: commentId=%s, err=%v", commentID, err)
+		return nil, utils.Response.InternalError("查询评论失败")
+	}
+	return comment, nil
+}
+
+// canManage 评论作者、评论所属任务的负责人和任务所属公司的管理人员可以编辑、删除评论
+func (l *TaskCommentLogic) canManage(c *task.Task_comment, userID, employeeID string, manageable map[string]bool) bool {
+	if c.UserID == userID {
+		return true
+	}
+	if employeeID == "" || c.TaskID == "" {
+		return false
+	}
+	if allowed, ok := manageable[c.TaskID]; ok {
+		return allowed
+	}
+	allowed, err := l.svcCtx.TaskAssignmentModel.HasRole(l.ctx, c.TaskID, employeeID, task.AssignmentTaskLeader)
+	if err != nil {
+		logx.Errorf("查询任务负责人失败: taskId=%s, err=%v", c.TaskID, err)
+		return false
+	}
+	if !allowed {
+		allowed = l.isTaskCompanyAdmin(c.TaskID, employeeID)
+	}
+	if manageable != nil {
+		manageable[c.TaskID] = allowed
+	}
+	return allowed
+}
+
+// isTaskCompanyAdmin 员工是否为任务所属公司的管理人员
+func (l *TaskCommentLogic) isTaskCompanyAdmin(taskID, employeeID string) bool {
+	employee, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, employeeID)
+	if err != nil {
+		return false
+	}
+	taskInfo, err := l.svcCtx.TaskModel.FindOne(l.ctx, taskID)
+	if err != nil || taskInfo.CompanyId != employee.CompanyId {
+		return false
+	}
+	return l.svcCtx.IsCompanyAdmin(l.ctx, employee)
+}
+
+// atEmployeeNames 查询@的员工姓名列表
+func (l *TaskCommentLogic) atEmployeeNames(employeeIDs []string) []string {
+	var names []string
+	for _, empID := range employeeIDs {
+		emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, empID)
+		if err == nil && emp != nil {
+			names = append(names, emp.RealName)
+		}
+	}
+	return names
+}
+
+// notifyMentions 异步通知评论中被@的员工
+func (l *TaskCommentLogic) notifyMentions(c *task.Task_comment, employeeIDs []string, realName string) {
+	if len(employeeIDs) == 0 || l.svcCtx.NotificationMQService == nil {
+		return
+	}
+	go func() {
+		event := &svc.NotificationEvent{
+			EventType:   "comment.mention",
+			EmployeeIDs: employeeIDs,
+			Title:       "评论中被@提及",
+			Content:     realName + "在任务评论中@了你: " + commentNotifyText(c),
+			Type:        3, // 类型: 系统通知
+			Category:    "comment",
+			Priority:    2, // 优先级: 普通
+			RelatedID:   c.CommentID,
+			RelatedType: "task_comment",
+			TaskID:      c.TaskID,
+			NodeID:      c.TaskNodeID,
+		}
+		if err := l.svcCtx.NotificationMQService.PublishNotificationEvent(l.ctx, event); err != nil {
+			logx.Errorf("发送@通知失败: %v", err)
+		}
+	}()
+}
+
+// mentionRecipients 收到@通知的员工：被@的员工和通过@部门、@角色提及的员工，不含评论作者
+func (l *TaskCommentLogic) mentionRecipients(mentionIDs, memberIDs []string, authorID string) []string {
+	recipients := make([]string, 0, len(mentionIDs)+len(memberIDs))
+	for _, id := range mentionIDs {
+		if id != authorID && !containsString(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	for _, id := range memberIDs {
+		if id != authorID {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients
+}
+
+// watchComment 评论作者和被@的员工自动关注评论所在的任务或节点；
+// @部门、@角色的成员只收到通知，不自动关注
+func (l *TaskCommentLogic) watchComment(c *task.Task_comment, mentionIDs []string) {
+	if c.EmployeeID != "" {
+		if err := l.svcCtx.TaskWatcherModel.AutoWatch(l.ctx, c.TaskID, c.TaskNodeID, task.WatchSourceComment, c.EmployeeID); err != nil {
+			logx.Errorf("评论作者自动关注失败: commentId=%s, err=%v", c.CommentID, err)
+		}
+	}
+	if err := l.svcCtx.TaskWatcherModel.AutoWatch(l.ctx, c.TaskID, c.TaskNodeID, task.WatchSourceMention, mentionIDs...); err != nil {
+		logx.Errorf("被@员工自动关注失败: commentId=%s, err=%v", c.CommentID, err)
+	}
+}
+
+// notifyWatchers 异步通知任务或节点的关注者有新评论，不通知作者和已收到@通知的员工
+func (l *TaskCommentLogic) notifyWatchers(c *task.Task_comment, notified []string, realName string) {
+	if l.svcCtx.NotificationMQService == nil {
+		return
+	}
+	go func() {
+		event := l.svcCtx.NotificationMQService.NewNotificationEvent(svc.TaskCommentCreated, nil, c.CommentID,
+			svc.NotificationEventOptions{TaskID: c.TaskID, NodeID: c.TaskNodeID})
+		event.Title = "任务有新评论"
+		event.Content = realName + "发表了评论: " + commentNotifyText(c)
+		event.Type = 3 // 类型: 系统通知
+		event.Category = "comment"
+		event.Priority = 1
+		event.RelatedType = "task_comment"
+		event.ExcludeEmployeeIDs = append([]string{c.EmployeeID}, notified...)
+		if err := l.svcCtx.NotificationMQService.PublishNotificationEvent(l.ctx, event); err != nil {
+			logx.Errorf("发送评论关注通知失败: %v", err)
+		}
+	}()
+}
+
+// convertCommentReactions 按支持的表情顺序汇总表情回应，忽略无人回应的表情
+func convertCommentReactions(reactions map[string][]string, currentUserID string) []types.TaskCommentReaction {
+	list := make([]types.TaskCommentReaction, 0, len(reactions))
+	for _, emoji := range commentReactionEmojis {
+		users := reactions[emoji]
+		if len(users) == 0 {
+			continue
+		}
+		list = append(list, types.TaskCommentReaction{
+			Emoji:   emoji,
+			Count:   int64(len(users)),
+			Reacted: containsString(users, currentUserID),
+		})
+	}
+	return list
+}
+
+// sameStringSet 两个ID列表是否包含相同的元素
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		if !containsString(b, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString 列表中是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// commentHTML 评论的HTML。服务端渲染之前的旧评论保存的是客户端提交的HTML，读取时净化；
+// 没有HTML时按 Markdown 渲染原文
+func commentHTML(c *task.Task_comment) string {
+	if c.ContentText != "" {
+		return c.ContentHTML
+	}
+	if c.ContentHTML != "" {
+		return utils.SanitizeHTML(c.ContentHTML)
+	}
+	return utils.RenderRichText(utils.ContentFormatMarkdown, c.Content, nil, nil).HTML
+}
+
+// commentNotifyText 通知中展示的评论文本，旧评论没有纯文本时使用原文
+func commentNotifyText(c *task.Task_comment) string {
+	if c.ContentText != "" {
+		return c.ContentText
+	}
+	return c.Content
+}
@@ -0,0 +1,44 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UnwatchTaskLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 取消关注任务或节点
+func NewUnwatchTaskLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UnwatchTaskLogic {
+	return &UnwatchTaskLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UnwatchTaskLogic) UnwatchTask(req *types.TaskWatchRequest) (resp *types.BaseResponse, err error) {
+	employeeID, errResp := loadWatchTarget(l.ctx, l.svcCtx, req)
+	if errResp != nil {
+		return errResp, nil
+	}
+	// 取消后保留记录，之后评论、被@或被分配时不再自动关注
+	if err := l.svcCtx.TaskWatcherModel.Unwatch(l.ctx, req.TaskID, req.TaskNodeID, employeeID); err != nil {
+		l.Logger.Errorf("取消关注任务失败: taskId=%s, taskNodeId=%s, err=%v", req.TaskID, req.TaskNodeID, err)
+		return utils.Response.InternalError("取消关注失败"), nil
+	}
+	return utils.Response.Success(map[string]interface{}{
+		"watching": false,
+	}), nil
+}
@@ -0,0 +1,34 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTaskCommentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 编辑任务评论
+func NewUpdateTaskCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateTaskCommentLogic {
+	return &UpdateTaskCommentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateTaskCommentLogic) UpdateTaskComment(req *types.UpdateTaskCommentRequest) (resp *types.BaseResponse, err error) {
+	// 使用 TaskCommentLogic 来处理编辑评论
+	commentLogic := NewTaskCommentLogic(l.ctx, l.svcCtx)
+	return commentLogic.UpdateComment(req)
+}
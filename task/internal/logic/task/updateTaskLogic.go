@@ -138,6 +138,10 @@ func (l *UpdateTaskLogic) UpdateTask(req *types.UpdateTaskRequest) (resp *types.
 		l.Logger.WithContext(l.ctx).Errorf("更新任务失败: %v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocTask, updatedTask.TaskId)
+	if req.TaskDescription != "" {
+		l.svcCtx.ContentService.SaveDetailQuietly(l.ctx, updatedTask.CompanyId, updatedTask.TaskId, "", updatedTask.TaskDetail)
+	}
 
 	// 8. 创建任务日志
 	logContent := "任务信息已更新"
@@ -87,13 +87,9 @@ func (l *UpdateTaskProgressLogic) UpdateTaskProgress(req *types.UpdateTaskProgre
 		return utils.Response.InternalError("更新进度失败"), nil
 	}
 
-	// 6. 如果提供了实际工时，更新实际工时
+	// 6. 如果提供了实际工时，将超出已记录工时的部分记为今天的手工工时（节点实际工时由工时记录汇总）
 	if req.ActualHours > 0 {
-		err = l.svcCtx.TaskNodeModel.UpdateActualHours(l.ctx, req.TaskNodeID, req.ActualHours)
-		if err != nil {
-			l.Logger.WithContext(l.ctx).Errorf("更新任务节点实际工时失败: %v", err)
-			// 不影响主流程，继续执行
-		}
+		l.recordActualHours(taskNode, employeeId, req.ActualHours)
 	}
 
 	// 7. 注意：进度100%时不自动改为已完成，需要员工手动提交审批，审批通过后才改为已完成
@@ -198,6 +194,29 @@ func (l *UpdateTaskProgressLogic) UpdateTaskProgress(req *types.UpdateTaskProgre
 	}), nil
 }
 
+// recordActualHours 按进度更新时填写的累计实际工时补记工时记录
+func (l *UpdateTaskProgressLogic) recordActualHours(taskNode *taskmodel.TaskNode, employeeId string, actualHours int) {
+	logged, err := l.svcCtx.TimeEntryModel.SumMinutesByNode(l.ctx, taskNode.TaskNodeId)
+	if err != nil {
+		l.Errorf("汇总任务节点工时失败: %v", err)
+		return
+	}
+	delta := int64(actualHours)*60 - logged
+	if delta <= 0 {
+		return
+	}
+	employee, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, employeeId)
+	if err != nil {
+		l.Errorf("查询员工信息失败: %v", err)
+		return
+	}
+	today, _ := time.ParseInLocation("2006-01-02", time.Now().Format("2006-01-02"), time.Local)
+	if _, err := l.svcCtx.TimeTrackingService.AddManual(l.ctx, employee, taskNode, today, delta, "", "进度更新时填报"); err != nil {
+		// 本周工时单已提交时不再补记，不影响主流程
+		l.Errorf("记录任务节点实际工时失败: %v", err)
+	}
+}
+
 // updateTaskProgress 根据所有任务节点进度更新任务整体进度（与 updateChecklistLogic 中的实现保持一致）
 func (l *UpdateTaskProgressLogic) updateTaskProgress(taskNodeId string) error {
 	// 获取任务节点信息
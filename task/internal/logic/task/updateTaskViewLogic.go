@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTaskViewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新任务视图
+func NewUpdateTaskViewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateTaskViewLogic {
+	return &UpdateTaskViewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateTaskViewLogic) UpdateTaskView(req *types.UpdateTaskViewRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	view, errResp := loadTaskView(l.ctx, l.svcCtx, employee, req.ViewID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	// 共享视图只有创建人可以修改
+	if view.EmployeeId != employee.Id {
+		return utils.Response.BusinessError("saved_view_denied"), nil
+	}
+	if msg := validateTaskView(req.Name, &req.Filter, req.Sort); msg != "" {
+		return utils.Response.ValidationError(msg), nil
+	}
+
+	view.Name = strings.TrimSpace(req.Name)
+	view.Filter, view.Sort = encodeTaskView(&req.Filter, req.Sort)
+	view.Shared = 0
+	if req.Shared {
+		view.Shared = 1
+	}
+	view.UpdateTime = time.Now()
+	if err := l.svcCtx.TaskViewModel.Update(l.ctx, view); err != nil {
+		l.Logger.Errorf("更新任务视图失败: viewId=%s, err=%v", view.Id, err)
+		return utils.Response.InternalError("更新视图失败"), nil
+	}
+	return utils.Response.Success(toTaskViewInfo(view, employee.RealName, employee.Id)), nil
+}
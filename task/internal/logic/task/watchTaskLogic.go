@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package task
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type WatchTaskLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 关注任务或节点
+func NewWatchTaskLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WatchTaskLogic {
+	return &WatchTaskLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WatchTaskLogic) WatchTask(req *types.TaskWatchRequest) (resp *types.BaseResponse, err error) {
+	employeeID, errResp := loadWatchTarget(l.ctx, l.svcCtx, req)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := l.svcCtx.TaskWatcherModel.Watch(l.ctx, req.TaskID, req.TaskNodeID, employeeID); err != nil {
+		l.Logger.Errorf("关注任务失败: taskId=%s, taskNodeId=%s, err=%v", req.TaskID, req.TaskNodeID, err)
+		return utils.Response.InternalError("关注失败"), nil
+	}
+	return utils.Response.Success(map[string]interface{}{
+		"watching": true,
+	}), nil
+}
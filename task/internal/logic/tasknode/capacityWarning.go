@@ -0,0 +1,55 @@
+package tasknode
+
+import (
+	"context"
+	"fmt"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+)
+
+// capacityWarnings 检查节点执行人承接节点后是否超负荷，返回超负荷提醒（不阻止分配）
+func capacityWarnings(ctx context.Context, svcCtx *svc.ServiceContext, companyID string, node *task.TaskNode, executorIDs []string) []types.CapacityWarning {
+	if len(executorIDs) == 0 || node.EstimatedDays <= 0 {
+		return nil
+	}
+	workload := &task.NodeWorkload{
+		TaskNodeId:    node.TaskNodeId,
+		EstimatedDays: node.EstimatedDays,
+		Progress:      node.Progress,
+		NodeStartTime: node.NodeStartTime,
+		NodeDeadline:  node.NodeDeadline,
+	}
+	fits, err := svcCtx.CapacityService.Evaluate(ctx, companyID, workload, executorIDs, true)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []types.CapacityWarning
+	for _, id := range executorIDs {
+		fit, ok := fits[id]
+		if !ok || !fit.Over {
+			continue
+		}
+		name := ""
+		if emp, err := svcCtx.EmployeeModel.FindOne(ctx, id); err == nil {
+			name = emp.RealName
+		}
+		message := fmt.Sprintf("%s 在节点周期内可用 %.1f 小时，已分配 %.1f 小时，承接本节点后利用率 %.1f%%", name, fit.AvailableHours, fit.AssignedHours, fit.Utilization)
+		if fit.OnLeave {
+			message = fmt.Sprintf("%s 当前处于请假状态", name)
+		}
+		warnings = append(warnings, types.CapacityWarning{
+			EmployeeID:     id,
+			EmployeeName:   name,
+			AvailableHours: fit.AvailableHours,
+			AssignedHours:  fit.AssignedHours,
+			NodeHours:      fit.NodeHours,
+			Utilization:    fit.Utilization,
+			OnLeave:        fit.OnLeave,
+			Message:        message,
+		})
+	}
+	return warnings
+}
@@ -137,6 +137,8 @@ func (l *CreateTaskNodeLogic) CreateTaskNode(req *types.CreateTaskNodeRequest) (
 		l.Logger.WithContext(l.ctx).Errorf("创建任务节点失败：%v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocNode, node.TaskNodeId)
+	l.svcCtx.ContentService.SaveDetailQuietly(l.ctx, currentTask.CompanyId, node.TaskId, node.TaskNodeId, node.NodeDetail.String)
 
 	// 更新任务的 node_employee_ids（去重）
 	if len(req.ExecutorIDs) > 0 {
@@ -258,7 +260,12 @@ func (l *CreateTaskNodeLogic) CreateTaskNode(req *types.CreateTaskNodeRequest) (
 		l.Logger.WithContext(l.ctx).Errorf("创建任务失败")
 		return utils.Response.BusinessError("task_log_error"), nil
 	}
-	return utils.Response.Success(node), nil
+
+	// 执行人承接后超负荷时返回提醒，不阻止创建
+	return utils.Response.Success(struct {
+		*task.TaskNode
+		CapacityWarnings []types.CapacityWarning `json:"capacityWarnings,omitempty"`
+	}{node, capacityWarnings(l.ctx, l.svcCtx, currentTask.CompanyId, node, req.ExecutorIDs)}), nil
 }
 
 // checkAssignmentPermission 检查任务分配权限
@@ -77,6 +77,7 @@ func (l *DeleteTaskNodeLogic) DeleteTaskNode(req *types.DeleteTaskNodeRequest) (
 		l.Logger.WithContext(l.ctx).Errorf("删除任务节点失败: %v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocNode, req.TaskNodeID)
 
 	// 8. 创建任务日志
 	taskLog := &task.TaskLog{
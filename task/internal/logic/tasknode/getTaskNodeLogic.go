@@ -142,7 +142,7 @@ func (l *GetTaskNodeLogic) GetTaskNode(req *types.GetTaskNodeRequest) (resp *typ
 
 	// 检查是否是部门负责人
 	if !hasPermission {
-		employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, currentUserID)
+		employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, currentUserID)
 		if err == nil {
 			department, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, employee.DepartmentId.String)
 			if err == nil && department.ManagerId.String == currentUserID {
@@ -166,6 +166,7 @@ func (l *GetTaskNodeLogic) GetTaskNode(req *types.GetTaskNodeRequest) (resp *typ
 	// 6. 转换为响应格式
 	converter := utils.NewConverter()
 	taskNodeInfo := converter.ToTaskNodeInfo(taskNode)
+	taskNodeInfo.NodeDetailHTML = l.svcCtx.ContentService.DetailHTML(l.ctx, taskInfo.CompanyId, taskNode.TaskId, taskNode.TaskNodeId, taskNode.NodeDetail.String)
 
 	// 7. 将审批列表转换为响应格式
 	approvalList := make([]map[string]interface{}, 0, len(approvals))
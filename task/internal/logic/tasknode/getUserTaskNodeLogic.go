@@ -59,7 +59,7 @@ func (l *GetUserTaskNodeLogic) GetUserTaskNode(req *types.PageReq) (resp *types.
 		return utils.Response.UnauthorizedError(), nil
 	}
 	// 将用户ID映射为员工ID（任务节点里存的是员工ID）
-	emp, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	emp, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("根据用户ID获取员工信息失败 userID=%s, err=%v", userID, err)
 		// 返回空列表而不是报错，避免前端异常
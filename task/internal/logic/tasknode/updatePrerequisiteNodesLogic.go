@@ -77,6 +77,9 @@ func (l *UpdatePrerequisiteNodesLogic) UpdatePrerequisiteNodes(req *types.Update
 	preNodes := strings.Split(req.PrerequisiteNodes, ",")
 	if len(preNodes) < 2 {
 		err = l.svcCtx.TaskNodeModel.UpdateStatus(l.ctx, node.TaskNodeId, 1)
+		if err == nil {
+			l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, node.TaskId, node.TaskNodeId, node.NodeName, employeeId, node.NodeStatus, 1)
+		}
 	}
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("更新任务节点状态失败: %v", err)
@@ -31,6 +31,12 @@ func NewUpdateTaskNodeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Up
 }
 
 func (l *UpdateTaskNodeLogic) UpdateTaskNode(req *types.UpdateTaskNodeRequest) (resp *types.BaseResponse, err error) {
+	return l.UpdateTaskNodeInTx(req, nil)
+}
+
+// UpdateTaskNodeInTx 更新任务节点，inTx 不为空时与节点更新在同一事务中执行，返回错误时节点更新一并回滚；
+// 看板移动卡片用它保证节点状态和卡片位置同时生效
+func (l *UpdateTaskNodeLogic) UpdateTaskNodeInTx(req *types.UpdateTaskNodeRequest, inTx func(ctx context.Context, session sqlx.Session) error) (resp *types.BaseResponse, err error) {
 	// 1. 参数验证
 	if req.NodeID == "" {
 		return utils.Response.BusinessError("task_node_not_found"), nil
@@ -230,11 +236,25 @@ func (l *UpdateTaskNodeLogic) UpdateTaskNode(req *types.UpdateTaskNodeRequest) (
 	}
 	updatedTaskNode.UpdateTime = time.Now()
 
-	err = l.svcCtx.TaskNodeModel.Update(l.ctx, &updatedTaskNode)
+	if inTx == nil {
+		err = l.svcCtx.TaskNodeModel.Update(l.ctx, &updatedTaskNode)
+	} else {
+		err = l.svcCtx.TransactionService.TransactCtx(l.ctx, func(ctx context.Context, session sqlx.Session) error {
+			if err := l.svcCtx.TransactionHelper.GetTaskNodeModelWithSession(session).Update(ctx, &updatedTaskNode); err != nil {
+				return err
+			}
+			return inTx(ctx, session)
+		})
+	}
 	if err != nil {
 		l.Logger.WithContext(l.ctx).Errorf("更新任务节点失败: %v", err)
 		return nil, err
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocNode, updatedTaskNode.TaskNodeId)
+	if _, ok := updateData["node_detail"]; ok {
+		l.svcCtx.ContentService.SaveDetailQuietly(l.ctx, taskInfo.CompanyId, updatedTaskNode.TaskId, updatedTaskNode.TaskNodeId, updatedTaskNode.NodeDetail.String)
+	}
+	l.svcCtx.TaskHistoryService.RecordNodeStatus(l.ctx, taskNode.TaskId, taskNode.TaskNodeId, updatedTaskNode.NodeName, currentEmpID, taskNode.NodeStatus, updatedTaskNode.NodeStatus)
 
 	// 6.5 如果更新了节点状态，同步更新任务整体进度
 	if len(req.NodeStatus) > 0 {
@@ -303,11 +323,18 @@ func (l *UpdateTaskNodeLogic) UpdateTaskNode(req *types.UpdateTaskNodeRequest) (
 		}
 	}
 
-	return utils.Response.Success(map[string]interface{}{
+	result := map[string]interface{}{
 		"taskNodeId":    req.NodeID,
 		"message":       "任务节点更新成功",
 		"updatedFields": updateFields,
-	}), nil
+	}
+	// 更换执行人后检查新执行人是否超负荷，只提醒不阻止
+	if newExecutorID != "" && newExecutorID != taskNode.ExecutorId {
+		if warnings := capacityWarnings(l.ctx, l.svcCtx, taskInfo.CompanyId, &updatedTaskNode, strings.Split(newExecutorID, ",")); len(warnings) > 0 {
+			result["capacityWarnings"] = warnings
+		}
+	}
+	return utils.Response.Success(result), nil
 }
 
 // updateTaskProgress 根据所有任务节点进度更新任务整体进度
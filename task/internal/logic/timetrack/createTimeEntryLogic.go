@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateTimeEntryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 手工填报工时
+func NewCreateTimeEntryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateTimeEntryLogic {
+	return &CreateTimeEntryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateTimeEntryLogic) CreateTimeEntry(req *types.CreateTimeEntryRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	workDate, ok := parseWorkDate(req.WorkDate, time.Time{})
+	if !ok || workDate.IsZero() {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+	if workDate.After(time.Now()) {
+		return utils.Response.BusinessError("time_date_future"), nil
+	}
+	if req.DurationMinutes <= 0 || req.DurationMinutes > svc.MaxEntryMinutes {
+		return utils.Response.BusinessError("time_duration_invalid"), nil
+	}
+	node, errResp := loadWorkNode(l.ctx, l.svcCtx, employee, req.TaskNodeId, req.ChecklistId)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	entry, err := l.svcCtx.TimeTrackingService.AddManual(l.ctx, employee, node, workDate, int64(req.DurationMinutes), req.ChecklistId, req.Note)
+	if err != nil {
+		if !errors.Is(err, svc.ErrTimeEntryLocked) {
+			l.Errorf("填报工时失败: employeeId=%s, nodeId=%s, err=%v", employee.Id, req.TaskNodeId, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("create", toTimeEntryInfo(entry, newTimeNameResolver(l.ctx, l.svcCtx))), nil
+}
@@ -0,0 +1,53 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteTimeEntryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除工时记录
+func NewDeleteTimeEntryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteTimeEntryLogic {
+	return &DeleteTimeEntryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteTimeEntryLogic) DeleteTimeEntry(req *types.DeleteTimeEntryRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	entry, err := l.svcCtx.TimeEntryModel.FindOne(l.ctx, req.EntryId)
+	if err != nil {
+		return utils.Response.BusinessError("time_entry_not_found"), nil
+	}
+	if entry.EmployeeId != employee.Id {
+		return utils.Response.BusinessError("time_entry_no_permission"), nil
+	}
+
+	if err := l.svcCtx.TimeTrackingService.DeleteEntry(l.ctx, entry); err != nil {
+		if !errors.Is(err, svc.ErrTimeEntryLocked) {
+			l.Errorf("删除工时记录失败: entryId=%s, err=%v", entry.Id, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("delete", nil), nil
+}
@@ -0,0 +1,49 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRunningTimerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前计时
+func NewGetRunningTimerLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRunningTimerLogic {
+	return &GetRunningTimerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetRunningTimerLogic) GetRunningTimer() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	entry, err := l.svcCtx.TimeEntryModel.FindRunningByEmployee(l.ctx, employee.Id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return utils.Response.SuccessWithKey("query", nil), nil
+		}
+		l.Errorf("查询当前计时失败: employeeId=%s, err=%v", employee.Id, err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	return utils.Response.SuccessWithKey("query", toTimeEntryInfo(entry, newTimeNameResolver(l.ctx, l.svcCtx))), nil
+}
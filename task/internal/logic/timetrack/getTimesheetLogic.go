@@ -0,0 +1,56 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTimesheetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取周工时单
+func NewGetTimesheetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTimesheetLogic {
+	return &GetTimesheetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTimesheetLogic) GetTimesheet(req *types.GetTimesheetRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	employeeID := req.EmployeeId
+	if employeeID == "" {
+		employeeID = employee.Id
+	}
+	if !canViewEmployeeTime(l.ctx, l.svcCtx, employee, employeeID) {
+		return utils.Response.BusinessError("time_entry_no_permission"), nil
+	}
+	weekStart, ok := parseWorkDate(req.WeekStart, time.Now())
+	if !ok {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+
+	info, err := buildTimesheetInfo(l.ctx, l.svcCtx, employeeID, weekStart, newTimeNameResolver(l.ctx, l.svcCtx))
+	if err != nil {
+		l.Errorf("查询周工时单失败: employeeId=%s, err=%v", employeeID, err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	return utils.Response.SuccessWithKey("query", info), nil
+}
@@ -0,0 +1,58 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PendingTimesheetsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 待我审批的工时单
+func NewPendingTimesheetsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PendingTimesheetsLogic {
+	return &PendingTimesheetsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PendingTimesheetsLogic) PendingTimesheets() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	sheets, err := l.svcCtx.TimesheetModel.FindPendingByApprover(l.ctx, employee.Id)
+	if err != nil {
+		l.Errorf("查询待审批工时单失败: approverId=%s, err=%v", employee.Id, err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	names := newTimeNameResolver(l.ctx, l.svcCtx)
+	list := make([]*types.TimesheetInfo, 0, len(sheets))
+	for _, sheet := range sheets {
+		info, err := buildTimesheetInfo(l.ctx, l.svcCtx, sheet.EmployeeId, sheet.WeekStart, names)
+		if err != nil {
+			l.Errorf("查询周工时单失败: timesheetId=%s, err=%v", sheet.Id, err)
+			continue
+		}
+		list = append(list, info)
+	}
+
+	return utils.Response.SuccessWithKey("query", map[string]interface{}{
+		"list":  list,
+		"total": len(list),
+	}), nil
+}
@@ -0,0 +1,56 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ReviewTimesheetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 审批周工时单
+func NewReviewTimesheetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReviewTimesheetLogic {
+	return &ReviewTimesheetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReviewTimesheetLogic) ReviewTimesheet(req *types.ReviewTimesheetRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if req.Result != 1 && req.Result != 2 {
+		return utils.Response.BusinessError("approval_result_invalid"), nil
+	}
+	sheet, err := l.svcCtx.TimesheetModel.FindOne(l.ctx, req.TimesheetId)
+	if err != nil || sheet.CompanyId != employee.CompanyId {
+		return utils.Response.BusinessError("timesheet_not_found"), nil
+	}
+	if sheet.EmployeeId == employee.Id {
+		return utils.Response.BusinessError("timesheet_no_permission"), nil
+	}
+
+	if err := l.svcCtx.TimeTrackingService.ReviewTimesheet(l.ctx, sheet, employee.Id, req.Result == 1, req.Note); err != nil {
+		if !errors.Is(err, svc.ErrTimesheetProcessed) && !errors.Is(err, svc.ErrTimesheetForbidden) {
+			l.Errorf("审批周工时单失败: timesheetId=%s, err=%v", sheet.Id, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("operation", nil), nil
+}
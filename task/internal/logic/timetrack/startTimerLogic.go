@@ -0,0 +1,51 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type StartTimerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 开始计时
+func NewStartTimerLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StartTimerLogic {
+	return &StartTimerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *StartTimerLogic) StartTimer(req *types.StartTimerRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	node, errResp := loadWorkNode(l.ctx, l.svcCtx, employee, req.TaskNodeId, req.ChecklistId)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	entry, err := l.svcCtx.TimeTrackingService.StartTimer(l.ctx, employee, node, req.ChecklistId, req.Note)
+	if err != nil {
+		if !errors.Is(err, svc.ErrTimerRunning) && !errors.Is(err, svc.ErrTimeEntryLocked) {
+			l.Errorf("开始计时失败: employeeId=%s, nodeId=%s, err=%v", employee.Id, req.TaskNodeId, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("create", toTimeEntryInfo(entry, newTimeNameResolver(l.ctx, l.svcCtx))), nil
+}
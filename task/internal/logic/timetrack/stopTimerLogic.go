@@ -0,0 +1,47 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type StopTimerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 停止计时
+func NewStopTimerLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StopTimerLogic {
+	return &StopTimerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *StopTimerLogic) StopTimer() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	entry, err := l.svcCtx.TimeTrackingService.StopTimer(l.ctx, employee.Id)
+	if err != nil {
+		if !errors.Is(err, svc.ErrTimerNotRunning) && !errors.Is(err, svc.ErrTimerWeekLocked) {
+			l.Errorf("停止计时失败: employeeId=%s, err=%v", employee.Id, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("update", toTimeEntryInfo(entry, newTimeNameResolver(l.ctx, l.svcCtx))), nil
+}
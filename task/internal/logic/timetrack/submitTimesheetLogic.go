@@ -0,0 +1,58 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SubmitTimesheetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 提交周工时单
+func NewSubmitTimesheetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SubmitTimesheetLogic {
+	return &SubmitTimesheetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SubmitTimesheetLogic) SubmitTimesheet(req *types.SubmitTimesheetRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	weekStart, ok := parseWorkDate(req.WeekStart, time.Now())
+	if !ok {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+
+	sheet, err := l.svcCtx.TimeTrackingService.SubmitTimesheet(l.ctx, employee, weekStart)
+	if err != nil {
+		if !errors.Is(err, svc.ErrTimesheetEmpty) && !errors.Is(err, svc.ErrTimesheetSubmitted) && !errors.Is(err, svc.ErrTimesheetRunning) {
+			l.Errorf("提交周工时单失败: employeeId=%s, err=%v", employee.Id, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	info, err := buildTimesheetInfo(l.ctx, l.svcCtx, employee.Id, sheet.WeekStart, newTimeNameResolver(l.ctx, l.svcCtx))
+	if err != nil {
+		l.Errorf("查询周工时单失败: employeeId=%s, err=%v", employee.Id, err)
+		return utils.Response.SuccessWithKey("operation", nil), nil
+	}
+
+	return utils.Response.SuccessWithKey("operation", info), nil
+}
@@ -0,0 +1,88 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type TimeEntryListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询工时记录
+func NewTimeEntryListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TimeEntryListLogic {
+	return &TimeEntryListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TimeEntryListLogic) TimeEntryList(req *types.TimeEntryListRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	filter := task.TimeEntryFilter{
+		CompanyId:  employee.CompanyId,
+		EmployeeId: req.EmployeeId,
+		TaskId:     req.TaskId,
+		TaskNodeId: req.TaskNodeId,
+	}
+	// 未指定节点或任务时默认查询本人的工时；按节点/任务查询时，节点成员和管理人员可以看到所有人的记录
+	if filter.EmployeeId == "" && filter.TaskId == "" && filter.TaskNodeId == "" {
+		filter.EmployeeId = employee.Id
+	}
+	if filter.EmployeeId != "" {
+		if !canViewEmployeeTime(l.ctx, l.svcCtx, employee, filter.EmployeeId) {
+			return utils.Response.BusinessError("time_entry_no_permission"), nil
+		}
+	} else if req.TaskNodeId != "" {
+		if _, errResp := loadWorkNode(l.ctx, l.svcCtx, employee, req.TaskNodeId, ""); errResp != nil && !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+			return utils.Response.BusinessError("time_entry_no_permission"), nil
+		}
+	} else if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		// 按任务查询所有人的工时仅限管理人员
+		filter.EmployeeId = employee.Id
+	}
+
+	var ok bool
+	if filter.StartDate, ok = parseWorkDate(req.StartDate, time.Time{}); !ok {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+	if filter.EndDate, ok = parseWorkDate(req.EndDate, time.Time{}); !ok {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+
+	entries, total, err := l.svcCtx.TimeEntryModel.Search(l.ctx, filter, req.Page, req.PageSize)
+	if err != nil {
+		l.Errorf("查询工时记录失败: %v", err)
+		return utils.Response.InternalError("查询失败"), nil
+	}
+
+	names := newTimeNameResolver(l.ctx, l.svcCtx)
+	list := make([]types.TimeEntryInfo, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, toTimeEntryInfo(e, names))
+	}
+
+	return utils.Response.SuccessWithKey("query", map[string]interface{}{
+		"list":     list,
+		"total":    total,
+		"page":     req.Page,
+		"pageSize": req.PageSize,
+	}), nil
+}
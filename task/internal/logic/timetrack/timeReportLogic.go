@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type TimeReportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 预计与实际工时对比报表
+func NewTimeReportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TimeReportLogic {
+	return &TimeReportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TimeReportLogic) TimeReport(req *types.TimeReportRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	start, ok1 := parseWorkDate(req.StartDate, time.Time{})
+	end, ok2 := parseWorkDate(req.EndDate, time.Time{})
+	if !ok1 || !ok2 || start.IsZero() || end.IsZero() || end.Before(start) {
+		return utils.Response.BusinessError("time_date_invalid"), nil
+	}
+
+	// 管理人员可以查看全公司，部门经理只能查看自己管理的部门
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		if req.DepartmentId == "" {
+			return utils.Response.BusinessError("time_report_no_permission"), nil
+		}
+		dept, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, req.DepartmentId)
+		if err != nil || dept.CompanyId != employee.CompanyId || !dept.ManagerId.Valid || dept.ManagerId.String != employee.Id {
+			return utils.Response.BusinessError("time_report_no_permission"), nil
+		}
+	}
+
+	byEmployee, byDepartment, err := l.svcCtx.TimeTrackingService.Report(l.ctx, employee.CompanyId, req.DepartmentId, start, end)
+	if err != nil {
+		l.Errorf("统计工时报表失败: companyId=%s, err=%v", employee.CompanyId, err)
+		return utils.Response.InternalError("统计失败"), nil
+	}
+
+	names := newTimeNameResolver(l.ctx, l.svcCtx)
+	employeeItems := make([]types.TimeReportItem, 0, len(byEmployee))
+	for _, row := range byEmployee {
+		employeeItems = append(employeeItems, toTimeReportItem(row, names.employee(row.Key)))
+	}
+	departmentItems := make([]types.TimeReportItem, 0, len(byDepartment))
+	for _, row := range byDepartment {
+		name := ""
+		if dept, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, row.Key); err == nil {
+			name = dept.DepartmentName
+		}
+		departmentItems = append(departmentItems, toTimeReportItem(row, name))
+	}
+
+	return utils.Response.SuccessWithKey("query", map[string]interface{}{
+		"startDate":    req.StartDate,
+		"endDate":      req.EndDate,
+		"byEmployee":   employeeItems,
+		"byDepartment": departmentItems,
+	}), nil
+}
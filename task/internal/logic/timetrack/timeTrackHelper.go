@@ -0,0 +1,248 @@
+package timetrack
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// canViewEmployeeTime 本人、公司管理人员以及员工审批链上的上级可以查看员工的工时
+func canViewEmployeeTime(ctx context.Context, svcCtx *svc.ServiceContext, viewer *user.Employee, employeeID string) bool {
+	if employeeID == "" || employeeID == viewer.Id {
+		return true
+	}
+	target, err := svcCtx.EmployeeModel.FindOne(ctx, employeeID)
+	if err != nil || target.CompanyId != viewer.CompanyId {
+		return false
+	}
+	if svcCtx.IsCompanyAdmin(ctx, viewer) {
+		return true
+	}
+	approverFinder := utils.NewApproverFinder(svcCtx.EmployeeModel, svcCtx.DepartmentModel, svcCtx.CompanyModel).
+		WithDelegateResolver(svcCtx.OutOfOfficeService.ResolveDelegate)
+	ok, _ := approverFinder.CanApprove(ctx, viewer.Id, employeeID)
+	return ok
+}
+
+// loadWorkNode 校验员工可以在节点上记录工时：节点属于当前公司，员工是节点执行人或负责人，清单属于该节点
+func loadWorkNode(ctx context.Context, svcCtx *svc.ServiceContext, employee *user.Employee, taskNodeID, checklistID string) (*task.TaskNode, *types.BaseResponse) {
+	if utils.Validator.IsEmpty(taskNodeID) {
+		return nil, utils.Response.BusinessError("task_node_not_found")
+	}
+	node, err := svcCtx.TaskNodeModel.FindOneSafe(ctx, taskNodeID)
+	if err != nil || node == nil {
+		return nil, utils.Response.BusinessError("task_node_not_found")
+	}
+	taskInfo, err := svcCtx.TaskModel.FindOne(ctx, node.TaskId)
+	if err != nil || taskInfo.CompanyId != employee.CompanyId {
+		return nil, utils.Response.BusinessError("task_node_not_found")
+	}
+	if !utils.Common.ContainsID(node.ExecutorId, employee.Id) && !utils.Common.ContainsID(node.LeaderId, employee.Id) {
+		return nil, utils.Response.BusinessError("time_node_not_member")
+	}
+	if checklistID != "" {
+		checklist, err := svcCtx.TaskChecklistModel.FindOne(ctx, checklistID)
+		if err != nil || checklist.TaskNodeId != node.TaskNodeId || checklist.DeleteTime.Valid {
+			return nil, utils.Response.BusinessError("time_checklist_invalid")
+		}
+	}
+	return node, nil
+}
+
+// parseWorkDate 解析 2006-01-02 格式的日期，空字符串返回 def
+func parseWorkDate(value string, def time.Time) (time.Time, bool) {
+	if value == "" {
+		return def, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// timeTrackError 将工时服务的错误转换为业务错误响应
+func timeTrackError(err error) *types.BaseResponse {
+	switch {
+	case errors.Is(err, svc.ErrTimerRunning):
+		return utils.Response.BusinessError("time_timer_running")
+	case errors.Is(err, svc.ErrTimerNotRunning):
+		return utils.Response.BusinessError("time_timer_not_running")
+	case errors.Is(err, svc.ErrTimeEntryLocked):
+		return utils.Response.BusinessError("time_entry_locked")
+	case errors.Is(err, svc.ErrTimerWeekLocked):
+		return utils.Response.BusinessError("time_timer_week_locked")
+	case errors.Is(err, svc.ErrTimesheetRunning):
+		return utils.Response.BusinessError("timesheet_timer_running")
+	case errors.Is(err, svc.ErrTimesheetEmpty):
+		return utils.Response.BusinessError("timesheet_empty")
+	case errors.Is(err, svc.ErrTimesheetSubmitted):
+		return utils.Response.BusinessError("timesheet_submitted")
+	case errors.Is(err, svc.ErrTimesheetProcessed):
+		return utils.Response.BusinessError("timesheet_processed")
+	case errors.Is(err, svc.ErrTimesheetForbidden):
+		return utils.Response.BusinessError("timesheet_no_permission")
+	}
+	return utils.Response.InternalError("工时操作失败")
+}
+
+// timeNameResolver 按ID查询员工姓名和节点名称，同一次请求内缓存结果
+type timeNameResolver struct {
+	ctx       context.Context
+	svcCtx    *svc.ServiceContext
+	employees map[string]string
+	nodes     map[string]string
+}
+
+func newTimeNameResolver(ctx context.Context, svcCtx *svc.ServiceContext) *timeNameResolver {
+	return &timeNameResolver{
+		ctx:       ctx,
+		svcCtx:    svcCtx,
+		employees: make(map[string]string),
+		nodes:     make(map[string]string),
+	}
+}
+
+func (r *timeNameResolver) employee(id string) string {
+	if id == "" {
+		return ""
+	}
+	if name, ok := r.employees[id]; ok {
+		return name
+	}
+	name := ""
+	if emp, err := r.svcCtx.EmployeeModel.FindOne(r.ctx, id); err == nil {
+		name = emp.RealName
+	}
+	r.employees[id] = name
+	return name
+}
+
+func (r *timeNameResolver) node(id string) string {
+	if name, ok := r.nodes[id]; ok {
+		return name
+	}
+	name := ""
+	if node, err := r.svcCtx.TaskNodeModel.FindOne(r.ctx, id); err == nil {
+		name = node.NodeName
+	}
+	r.nodes[id] = name
+	return name
+}
+
+// toTimeEntryInfo 转换为响应格式
+func toTimeEntryInfo(entry *task.TimeEntry, names *timeNameResolver) types.TimeEntryInfo {
+	info := types.TimeEntryInfo{
+		Id:              entry.Id,
+		TaskId:          entry.TaskId,
+		TaskNodeId:      entry.TaskNodeId,
+		NodeName:        names.node(entry.TaskNodeId),
+		ChecklistId:     entry.ChecklistId.String,
+		EmployeeId:      entry.EmployeeId,
+		EmployeeName:    names.employee(entry.EmployeeId),
+		EntryType:       int(entry.EntryType),
+		WorkDate:        entry.WorkDate.Format("2006-01-02"),
+		DurationMinutes: entry.DurationMinutes,
+		Hours:           svc.MinutesToHours(entry.DurationMinutes),
+		Running:         entry.Running(),
+		Note:            entry.Note.String,
+		TimesheetId:     entry.TimesheetId.String,
+		CreateTime:      utils.Common.FormatTime(entry.CreateTime),
+	}
+	if entry.StartTime.Valid {
+		info.StartTime = utils.Common.FormatTime(entry.StartTime.Time)
+	}
+	if entry.EndTime.Valid {
+		info.EndTime = utils.Common.FormatTime(entry.EndTime.Time)
+	}
+	if info.Running {
+		// 计时中的记录返回已经过的时长，便于前端展示
+		info.DurationMinutes = int64(time.Since(entry.StartTime.Time).Minutes())
+		info.Hours = svc.MinutesToHours(info.DurationMinutes)
+	}
+	return info
+}
+
+// buildTimesheetInfo 组装员工某周的工时单（含未提交的周）
+func buildTimesheetInfo(ctx context.Context, svcCtx *svc.ServiceContext, employeeID string, weekStart time.Time, names *timeNameResolver) (*types.TimesheetInfo, error) {
+	weekStart = svc.WeekStart(weekStart)
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	entries, err := svcCtx.TimeEntryModel.FindByEmployeeRange(ctx, employeeID, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.TimesheetInfo{
+		EmployeeId:   employeeID,
+		EmployeeName: names.employee(employeeID),
+		WeekStart:    weekStart.Format("2006-01-02"),
+		WeekEnd:      weekEnd.Format("2006-01-02"),
+		DailyHours:   make([]float64, 7),
+		Entries:      make([]types.TimeEntryInfo, 0, len(entries)),
+	}
+	daily := make([]int64, 7)
+	var total int64
+	for _, e := range entries {
+		info.Entries = append(info.Entries, toTimeEntryInfo(e, names))
+		if e.Running() {
+			continue
+		}
+		day := int(e.WorkDate.Sub(weekStart).Hours() / 24)
+		if day >= 0 && day < 7 {
+			daily[day] += e.DurationMinutes
+		}
+		total += e.DurationMinutes
+	}
+	for i, m := range daily {
+		info.DailyHours[i] = svc.MinutesToHours(m)
+	}
+	info.TotalHours = svc.MinutesToHours(total)
+
+	sheet, err := svcCtx.TimesheetModel.FindByEmployeeWeek(ctx, employeeID, weekStart)
+	if err != nil && !errors.Is(err, task.ErrNotFound) {
+		return nil, err
+	}
+	if sheet != nil {
+		fillTimesheetInfo(info, sheet, names)
+	}
+	return info, nil
+}
+
+// fillTimesheetInfo 填充工时单的提交与审批信息
+func fillTimesheetInfo(info *types.TimesheetInfo, sheet *task.Timesheet, names *timeNameResolver) {
+	info.Id = sheet.Id
+	info.Status = int(sheet.Status)
+	info.ApproverId = sheet.ApproverId.String
+	info.ApproverName = names.employee(sheet.ApproverId.String)
+	info.ApproveNote = sheet.ApproveNote.String
+	if sheet.SubmitTime.Valid {
+		info.SubmitTime = utils.Common.FormatTime(sheet.SubmitTime.Time)
+	}
+	if sheet.ApproveTime.Valid {
+		info.ApproveTime = utils.Common.FormatTime(sheet.ApproveTime.Time)
+	}
+}
+
+// toTimeReportItem 转换报表行，计算偏差工时与偏差率
+func toTimeReportItem(row *svc.TimeReportRow, name string) types.TimeReportItem {
+	item := types.TimeReportItem{
+		Id:             row.Key,
+		Name:           name,
+		EstimatedHours: svc.MinutesToHours(row.EstimatedMinutes),
+		ActualHours:    svc.MinutesToHours(row.ActualMinutes),
+		VarianceHours:  svc.MinutesToHours(row.ActualMinutes - row.EstimatedMinutes),
+		NodeCount:      row.NodeCount,
+	}
+	if row.EstimatedMinutes > 0 {
+		rate := float64(row.ActualMinutes-row.EstimatedMinutes) / float64(row.EstimatedMinutes) * 100
+		item.VarianceRate = math.Round(rate*100) / 100
+	}
+	return item
+}
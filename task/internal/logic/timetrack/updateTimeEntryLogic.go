@@ -0,0 +1,85 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package timetrack
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTimeEntryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改工时记录
+func NewUpdateTimeEntryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateTimeEntryLogic {
+	return &UpdateTimeEntryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateTimeEntryLogic) UpdateTimeEntry(req *types.UpdateTimeEntryRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	entry, err := l.svcCtx.TimeEntryModel.FindOne(l.ctx, req.EntryId)
+	if err != nil {
+		return utils.Response.BusinessError("time_entry_not_found"), nil
+	}
+	// 只能修改自己的工时记录，计时中的记录需先停止
+	if entry.EmployeeId != employee.Id {
+		return utils.Response.BusinessError("time_entry_no_permission"), nil
+	}
+	if entry.Running() {
+		return utils.Response.BusinessError("time_timer_running"), nil
+	}
+
+	originalDate := entry.WorkDate
+	if req.WorkDate != "" {
+		workDate, ok := parseWorkDate(req.WorkDate, time.Time{})
+		if !ok {
+			return utils.Response.BusinessError("time_date_invalid"), nil
+		}
+		if workDate.After(time.Now()) {
+			return utils.Response.BusinessError("time_date_future"), nil
+		}
+		entry.WorkDate = workDate
+	}
+	if req.DurationMinutes != 0 {
+		if req.DurationMinutes < 0 || req.DurationMinutes > svc.MaxEntryMinutes {
+			return utils.Response.BusinessError("time_duration_invalid"), nil
+		}
+		entry.DurationMinutes = int64(req.DurationMinutes)
+	}
+	if req.ChecklistId != "" {
+		if _, errResp := loadWorkNode(l.ctx, l.svcCtx, employee, entry.TaskNodeId, req.ChecklistId); errResp != nil {
+			return errResp, nil
+		}
+		entry.ChecklistId = utils.Common.ToSqlNullString(req.ChecklistId)
+	}
+	if req.Note != "" {
+		entry.Note = utils.Common.ToSqlNullString(req.Note)
+	}
+
+	if err := l.svcCtx.TimeTrackingService.SaveEntry(l.ctx, entry, originalDate); err != nil {
+		if !errors.Is(err, svc.ErrTimeEntryLocked) {
+			l.Errorf("修改工时记录失败: entryId=%s, err=%v", entry.Id, err)
+		}
+		return timeTrackError(err), nil
+	}
+
+	return utils.Response.SuccessWithKey("update", toTimeEntryInfo(entry, newTimeNameResolver(l.ctx, l.svcCtx))), nil
+}
@@ -0,0 +1,42 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type AbortUploadSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 取消上传会话并删除已上传的内容
+func NewAbortUploadSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AbortUploadSessionLogic {
+	return &AbortUploadSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AbortUploadSessionLogic) AbortUploadSession(req *types.UploadSessionRequest) (resp *types.BaseResponse, err error) {
+	sess, _, errResp := loadUploadSession(l.ctx, l.svcCtx, req.UploadID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := l.svcCtx.UploadSessionService.Abort(l.ctx, sess); err != nil {
+		return uploadSessionError(l.ctx, sess.Id, err), nil
+	}
+
+	logx.Infof("取消上传会话: uploadId=%s", sess.Id)
+	return utils.Response.Success(nil), nil
+}
@@ -0,0 +1,57 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CompleteUploadSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 完成上传：合并分片、校验并检查文件后创建附件
+func NewCompleteUploadSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CompleteUploadSessionLogic {
+	return &CompleteUploadSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CompleteUploadSessionLogic) CompleteUploadSession(req *types.UploadSessionRequest, clientIP string) (resp *types.BaseResponse, err error) {
+	sess, _, errResp := loadUploadSession(l.ctx, l.svcCtx, req.UploadID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	userID, _ := utils.Common.GetCurrentUserID(l.ctx)
+
+	file, err := l.svcCtx.UploadSessionService.Complete(l.ctx, sess, svc.UploadCompletion{
+		UserID: userID,
+		IP:     clientIP,
+		Path:   "/api/v1/upload/session/complete",
+	})
+	if err != nil {
+		return uploadSessionError(l.ctx, sess.Id, err), nil
+	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocFile, file.FileID)
+
+	logx.Infof("大文件上传完成: uploadId=%s, fileID=%s, fileName=%s, fileSize=%d", sess.Id, file.FileID, file.FileName, file.FileSize)
+	return utils.Response.Success(types.UploadInfoResponse{
+		FileID:    file.FileID,
+		FileName:  file.FileName,
+		FileURL:   file.FileURL,
+		FileType:  file.FileType,
+		FileSize:  file.FileSize,
+		RelatedID: file.RelatedID,
+	}), nil
+}
@@ -5,6 +5,7 @@ package upload
 
 import (
 	"context"
+	taskModel "task_Project/model/task"
 	uploadModel "task_Project/model/upload"
 	"task_Project/task/internal/utils"
 
@@ -49,7 +50,7 @@ func (l *CreateAttachmentCommentLogic) CreateComment(req *types.CreateAttachment
 	// 如果从上下文获取不到employeeID或realName，尝试从数据库查询
 	if employeeID == "" || realName == "" {
 		logx.Infof("上下文中缺少员工信息, userID=%s, employeeID=%s, realName=%s, 尝试从数据库查询", userID, employeeID, realName)
-		employee, dbErr := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+		employee, dbErr := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 		if dbErr != nil {
 			logx.Errorf("通过userID查询员工失败: userID=%s, error=%v", userID, dbErr)
 		} else if employee != nil {
@@ -81,10 +82,17 @@ func (l *CreateAttachmentCommentLogic) CreateComment(req *types.CreateAttachment
 		logx.Infof("无法获取用户真实姓名，使用兜底值: %s", realName)
 	}
 
+	// 渲染并净化内容，解析@提及和任务、节点引用
+	companyID, _ := utils.Common.GetCurrentCompanyID(l.ctx)
+	rendered := l.svcCtx.ContentService.Render(l.ctx, companyID, utils.ContentFormatMarkdown, req.Content, req.AtEmployeeIDs)
+	if rendered.IsEmpty() {
+		return utils.Response.ValidationError("评论内容不能为空"), nil
+	}
+
 	// 获取@的员工姓名列表
 	var atEmployeeNames []string
-	if len(req.AtEmployeeIDs) > 0 {
-		for _, empID := range req.AtEmployeeIDs {
+	if len(rendered.MentionIDs) > 0 {
+		for _, empID := range rendered.MentionIDs {
 			emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, empID)
 			if err == nil && emp != nil {
 				atEmployeeNames = append(atEmployeeNames, emp.RealName)
@@ -94,14 +102,34 @@ func (l *CreateAttachmentCommentLogic) CreateComment(req *types.CreateAttachment
 
 	// 获取回复目标信息
 	var replyToUserID, replyToName string
+	var parentComment *uploadModel.Attachment_comment
 	if req.ParentID != "" {
-		parentComment, err := l.svcCtx.AttachmentCommentModel.FindByCommentID(l.ctx, req.ParentID)
+		parentComment, err = l.svcCtx.AttachmentCommentModel.FindByCommentID(l.ctx, req.ParentID)
 		if err == nil && parentComment != nil {
 			replyToUserID = parentComment.UserID
 			replyToName = parentComment.EmployeeName
 		}
 	}
 
+	// 标注所在的附件版本：回复跟随被回复的评论，否则为指定的版本或当前版本
+	var fileVersion int64
+	if parentComment != nil {
+		fileVersion = parentComment.FileVersion
+	} else {
+		file, err := l.svcCtx.UploadFileModel.FindByFileID(l.ctx, req.FileID)
+		if err != nil {
+			logx.Errorf("查询附件失败: fileId=%s, err=%v", req.FileID, err)
+			return utils.Response.NotFoundError("file_not_found"), nil
+		}
+		fileVersion = file.CurrentVersion()
+		if req.FileVersion > 0 {
+			if _, ok := file.FindVersion(req.FileVersion); !ok {
+				return utils.Response.NotFoundError("file_version_not_found"), nil
+			}
+			fileVersion = req.FileVersion
+		}
+	}
+
 	commentID := utils.Common.GenId("attcomment")
 
 	// 转换标注数据
@@ -125,13 +153,16 @@ func (l *CreateAttachmentCommentLogic) CreateComment(req *types.CreateAttachment
 	comment := &uploadModel.Attachment_comment{
 		CommentID:       commentID,
 		FileID:          req.FileID,
+		FileVersion:     fileVersion,
 		TaskID:          req.TaskID,
 		TaskNodeID:      req.TaskNodeID,
 		UserID:          userID,
 		EmployeeID:      employeeID,
 		EmployeeName:    realName,
 		Content:         req.Content,
-		AtEmployeeIDs:   req.AtEmployeeIDs,
+		ContentHTML:     rendered.HTML,
+		ContentText:     rendered.Text,
+		AtEmployeeIDs:   rendered.MentionIDs,
 		AtEmployeeNames: atEmployeeNames,
 		AnnotationType:  req.AnnotationType,
 		AnnotationData:  annotationData,
@@ -148,15 +179,27 @@ func (l *CreateAttachmentCommentLogic) CreateComment(req *types.CreateAttachment
 		logx.Errorf("创建附件评论失败: %v", err)
 		return utils.Response.InternalError("创建评论失败"), nil
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocAttachmentComment, commentID)
+
+	// 评论作者和被@的员工自动关注附件所属的任务或节点
+	if req.TaskID != "" {
+		if err := l.svcCtx.TaskWatcherModel.AutoWatch(l.ctx, req.TaskID, req.TaskNodeID, taskModel.WatchSourceComment, employeeID); err != nil {
+			logx.Errorf("评论作者自动关注失败: commentId=%s, err=%v", commentID, err)
+		}
+		if err := l.svcCtx.TaskWatcherModel.AutoWatch(l.ctx, req.TaskID, req.TaskNodeID, taskModel.WatchSourceMention, rendered.MentionIDs...); err != nil {
+			logx.Errorf("被@员工自动关注失败: commentId=%s, err=%v", commentID, err)
+		}
+	}
 
-	// 发送@通知
-	if len(req.AtEmployeeIDs) > 0 && l.svcCtx.NotificationMQService != nil {
+	// 发送@通知，包括通过@部门、@角色提及的员工
+	mentioned := append(append([]string{}, rendered.MentionIDs...), rendered.MemberIDs...)
+	if len(mentioned) > 0 && l.svcCtx.NotificationMQService != nil {
 		go func() {
 			event := &svc.NotificationEvent{
 				EventType:   "comment.mention",
-				EmployeeIDs: req.AtEmployeeIDs,
+				EmployeeIDs: mentioned,
 				Title:       "附件评论中被@提及",
-				Content:     realName + "在附件评论中@了你: " + req.Content,
+				Content:     realName + "在附件评论中@了你: " + rendered.Text,
 				Type:        3, // 类型: 系统通知
 				Category:    "comment",
 				Priority:    2, // 优先级: 普通
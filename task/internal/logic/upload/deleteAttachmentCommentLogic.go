@@ -49,6 +49,7 @@ func (l *DeleteAttachmentCommentLogic) DeleteAttachmentComment(req *types.Delete
 		logx.Errorf("删除评论失败: %v", err)
 		return utils.Response.InternalError("删除评论失败"), nil
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocAttachmentComment, comment.CommentID)
 
 	return utils.Response.Success(nil), nil
 }
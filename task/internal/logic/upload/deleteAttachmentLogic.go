@@ -40,12 +40,10 @@ func (l *DeleteAttachmentLogic) DeleteAttachment(req *types.DeleteAttachmentRequ
 		return utils.Response.NotFoundError("附件不存在"), nil
 	}
 
-	// 删除物理文件
-	if fileInfo.FilePath != "" {
-		if err := l.svcCtx.FileStorageService.DeleteFile(fileInfo.FilePath); err != nil {
-			logx.Errorf("删除物理文件失败: %v", err)
-			// 继续删除数据库记录
-		}
+	// 删除全部版本的物理文件
+	if err := l.svcCtx.FileVersionService.Remove(l.ctx, fileInfo); err != nil {
+		logx.Errorf("删除物理文件失败: %v", err)
+		// 继续删除数据库记录
 	}
 
 	// 从MongoDB删除附件记录
@@ -54,6 +52,7 @@ func (l *DeleteAttachmentLogic) DeleteAttachment(req *types.DeleteAttachmentRequ
 		logx.Errorf("删除附件记录失败: %v", err)
 		return utils.Response.InternalError("删除附件失败"), nil
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocFile, req.FileID)
 
 	logx.Infof("附件删除成功: fileID=%s, path=%s", req.FileID, fileInfo.FilePath)
 	return utils.Response.Success(nil), nil
@@ -0,0 +1,79 @@
+package upload
+
+import (
+	"context"
+	"errors"
+
+	taskModel "task_Project/model/task"
+	uploadModel "task_Project/model/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// loadVersionedFile 查询附件并校验当前员工的版本权限：上传者可以查看和管理版本；
+// 任务附件的参与人可以管理版本，任务上有任意分配关系的员工可以查看；其他模块的附件所有员工都可以查看
+func loadVersionedFile(ctx context.Context, svcCtx *svc.ServiceContext, fileID string, manage bool) (*uploadModel.Upload_file, string, *types.BaseResponse) {
+	if fileID == "" {
+		return nil, "", utils.Response.ValidationError("文件ID不能为空")
+	}
+	employeeID, ok := utils.Common.GetCurrentEmployeeID(ctx)
+	if !ok || employeeID == "" {
+		return nil, "", utils.Response.UnauthorizedError()
+	}
+
+	file, err := svcCtx.UploadFileModel.FindByFileID(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, uploadModel.ErrNotFound) {
+			return nil, "", utils.Response.NotFoundError("file_not_found")
+		}
+		logx.WithContext(ctx).Errorf("查询附件失败: fileId=%s, err=%v", fileID, err)
+		return nil, "", utils.Response.InternalError("查询附件失败")
+	}
+	if file.Category == "avatar" {
+		return nil, "", utils.Response.BusinessError("file_version_unsupported")
+	}
+	if file.UploaderID == employeeID {
+		return file, employeeID, nil
+	}
+
+	if file.Module != "task" {
+		if manage {
+			return nil, "", utils.Response.ForbiddenError(utils.BusinessErrorMessages["file_version_no_permission"])
+		}
+		return file, employeeID, nil
+	}
+
+	var roles []string
+	if manage {
+		roles = taskModel.InvolvedAssignmentRoles
+	}
+	allowed, err := svcCtx.TaskAssignmentModel.HasRole(ctx, file.RelatedID, employeeID, roles...)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询任务分配关系失败: taskId=%s, err=%v", file.RelatedID, err)
+		return nil, "", utils.Response.InternalError("查询附件权限失败")
+	}
+	if !allowed {
+		return nil, "", utils.Response.ForbiddenError(utils.BusinessErrorMessages["file_version_no_permission"])
+	}
+	return file, employeeID, nil
+}
+
+// fileVersionError 版本写入错误对应的响应
+func fileVersionError(ctx context.Context, fileID string, err error) *types.BaseResponse {
+	switch {
+	case errors.Is(err, svc.ErrFileVersionConflict):
+		return utils.Response.ConflictError("file_version_conflict")
+	case errors.Is(err, svc.ErrFileVersionNotFound):
+		return utils.Response.NotFoundError("file_version_not_found")
+	case errors.Is(err, svc.ErrStorageQuotaExceeded):
+		return utils.Response.BusinessError("storage_quota_exceeded")
+	case errors.Is(err, svc.ErrStorageUserQuotaExceeded):
+		return utils.Response.BusinessError("storage_user_quota_exceeded")
+	default:
+		logx.WithContext(ctx).Errorf("保存附件版本失败: fileId=%s, err=%v", fileID, err)
+		return utils.Response.InternalError("保存附件版本失败")
+	}
+}
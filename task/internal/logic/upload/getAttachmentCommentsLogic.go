@@ -44,7 +44,21 @@ func (l *GetAttachmentCommentsLogic) GetAttachmentComments(req *types.GetAttachm
 		pageSize = 20
 	}
 
-	comments, total, err := l.svcCtx.AttachmentCommentModel.FindByFileID(l.ctx, req.FileID, page, pageSize)
+	// 当前版本用于标记历史版本上的标注，附件已删除时不标记
+	var currentVersion int64
+	if file, err := l.svcCtx.UploadFileModel.FindByFileID(l.ctx, req.FileID); err == nil {
+		currentVersion = file.CurrentVersion()
+	}
+
+	var (
+		comments []*uploadModel.Attachment_comment
+		total    int64
+	)
+	if req.FileVersion > 0 {
+		comments, total, err = l.svcCtx.AttachmentCommentModel.FindByFileVersion(l.ctx, req.FileID, req.FileVersion, page, pageSize)
+	} else {
+		comments, total, err = l.svcCtx.AttachmentCommentModel.FindByFileID(l.ctx, req.FileID, page, pageSize)
+	}
 	if err != nil {
 		logx.Errorf("查询附件评论失败: %v", err)
 		return utils.Response.InternalError("查询评论失败"), nil
@@ -62,10 +76,10 @@ func (l *GetAttachmentCommentsLogic) GetAttachmentComments(req *types.GetAttachm
 		replies, _ := l.svcCtx.AttachmentCommentModel.FindReplies(l.ctx, c.CommentID)
 		replyList := make([]types.AttachmentCommentInfo, 0, len(replies))
 		for _, r := range replies {
-			replyList = append(replyList, l.convertToCommentInfo(r))
+			replyList = append(replyList, l.versioned(l.convertToCommentInfo(r), currentVersion))
 		}
 
-		info := l.convertToCommentInfo(c)
+		info := l.versioned(l.convertToCommentInfo(c), currentVersion)
 		info.Replies = replyList
 		list = append(list, info)
 	}
@@ -105,12 +119,14 @@ func (l *GetAttachmentCommentsLogic) convertToCommentInfo(c *uploadModel.Attachm
 		ID:              c.ID.Hex(),
 		CommentID:       c.CommentID,
 		FileID:          c.FileID,
+		FileVersion:     c.FileVersion,
 		TaskID:          c.TaskID,
 		TaskNodeID:      c.TaskNodeID,
 		UserID:          c.UserID,
 		EmployeeID:      c.EmployeeID,
 		EmployeeName:    c.EmployeeName,
 		Content:         c.Content,
+		ContentHTML:     attachmentCommentHTML(c),
 		AtEmployeeIDs:   c.AtEmployeeIDs,
 		AtEmployeeNames: c.AtEmployeeNames,
 		AnnotationType:  c.AnnotationType,
@@ -126,3 +142,20 @@ func (l *GetAttachmentCommentsLogic) convertToCommentInfo(c *uploadModel.Attachm
 		UpdateTime:      c.UpdateAt.Format(time.RFC3339),
 	}
 }
+
+// versioned 补全旧评论的版本号，并标记不在当前版本上的标注
+func (l *GetAttachmentCommentsLogic) versioned(info types.AttachmentCommentInfo, currentVersion int64) types.AttachmentCommentInfo {
+	if info.FileVersion <= 0 {
+		info.FileVersion = 1
+	}
+	info.Outdated = currentVersion > 0 && info.FileVersion != currentVersion
+	return info
+}
+
+// attachmentCommentHTML 评论的HTML，旧评论没有保存渲染结果时按 Markdown 渲染原文
+func attachmentCommentHTML(c *uploadModel.Attachment_comment) string {
+	if c.ContentHTML != "" {
+		return c.ContentHTML
+	}
+	return utils.RenderRichText(utils.ContentFormatMarkdown, c.Content, nil, nil).HTML
+}
@@ -81,19 +81,22 @@ func (l *GetFileDetailLogic) GetFileDetail(req *types.GetFileDetailRequest) (res
 	}
 
 	return utils.Response.Success(types.AttachmentInfo{
-		FileID:      file.FileID,
-		FileName:    file.FileName,
-		FileURL:     file.FileURL,
-		FileType:    file.FileType,
-		FileSize:    file.FileSize,
-		Module:      file.Module,
-		Category:    file.Category,
-		RelatedID:   file.RelatedID,
-		TaskNodeID:  file.TaskNodeID,
-		UploaderID:  file.UploaderID,
-		Description: file.Description,
-		Tags:        file.Tags,
-		CreateTime:  file.CreateAt.Format(time.RFC3339),
-		UpdateTime:  file.UpdateAt.Format(time.RFC3339),
+		FileID:       file.FileID,
+		FileName:     file.FileName,
+		FileURL:      file.FileURL,
+		FileType:     file.FileType,
+		ThumbnailURL: file.ThumbnailURL,
+		PreviewURL:   file.PreviewURL,
+		FileSize:     file.FileSize,
+		Module:       file.Module,
+		Category:     file.Category,
+		RelatedID:    file.RelatedID,
+		TaskNodeID:   file.TaskNodeID,
+		UploaderID:   file.UploaderID,
+		Description:  file.Description,
+		Tags:         file.Tags,
+		Version:      file.CurrentVersion(),
+		CreateTime:   file.CreateAt.Format(time.RFC3339),
+		UpdateTime:   file.UpdateAt.Format(time.RFC3339),
 	}), nil
 }
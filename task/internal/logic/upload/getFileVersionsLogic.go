@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+	"time"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetFileVersionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取附件版本列表
+func NewGetFileVersionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetFileVersionsLogic {
+	return &GetFileVersionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetFileVersionsLogic) GetFileVersions(req *types.FileVersionListRequest) (resp *types.BaseResponse, err error) {
+	file, _, errResp := loadVersionedFile(l.ctx, l.svcCtx, req.FileID, false)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 各版本的评论数，查询失败时不影响版本列表
+	counts, err := l.svcCtx.AttachmentCommentModel.CountByFileVersion(l.ctx, file.FileID)
+	if err != nil {
+		logx.Errorf("统计附件各版本评论数失败: fileId=%s, err=%v", file.FileID, err)
+	}
+
+	current := file.CurrentVersion()
+	versions := file.VersionList()
+	names := make(map[string]string)
+	list := make([]types.FileVersionInfo, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		if _, ok := names[v.UploaderID]; !ok && v.UploaderID != "" {
+			if emp, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, v.UploaderID); err == nil && emp != nil {
+				names[v.UploaderID] = emp.RealName
+			} else {
+				names[v.UploaderID] = ""
+			}
+		}
+		list = append(list, types.FileVersionInfo{
+			Version:      v.Version,
+			FileName:     v.FileName,
+			FileURL:      v.FileURL,
+			FileType:     v.FileType,
+			ThumbnailURL: v.ThumbnailURL,
+			PreviewURL:   v.PreviewURL,
+			FileSize:     v.FileSize,
+			UploaderID:   v.UploaderID,
+			UploaderName: names[v.UploaderID],
+			ChangeNote:   v.ChangeNote,
+			RestoredFrom: v.RestoredFrom,
+			Current:      v.Version == current,
+			CommentCount: counts[v.Version],
+			CreateTime:   v.CreateAt.Format(time.RFC3339),
+		})
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"fileId":         file.FileID,
+		"currentVersion": current,
+		"list":           list,
+	}), nil
+}
@@ -33,7 +33,7 @@ func (l *GetMyAttachmentsLogic) GetMyAttachments(req *types.GetMyAttachmentsRequ
 	}
 
 	// 获取当前员工信息
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil || employee == nil {
 		logx.Errorf("[GetMyAttachments] 获取员工信息失败: userID=%s, err=%v", userID, err)
 		return utils.Response.BusinessError("您尚未加入任何公司"), nil
@@ -72,18 +72,19 @@ func (l *GetMyAttachmentsLogic) GetMyAttachments(req *types.GetMyAttachmentsRequ
 	var list []types.MyAttachmentInfo
 	for _, file := range files {
 		item := types.MyAttachmentInfo{
-			FileID:      file.FileID,
-			FileURL:     file.FileURL,
-			FileName:    file.FileName,
-			FileSize:    file.FileSize,
-			FileType:    file.FileType,
-			Module:      file.Module,
-			Category:    file.Category,
-			RelatedID:   file.RelatedID,
-			TaskNodeID:  file.TaskNodeID,
-			Description: file.Description,
-			Tags:        file.Tags,
-			UploadTime:  file.CreateAt.Format("2006-01-02 15:04:05"),
+			FileID:       file.FileID,
+			FileURL:      file.FileURL,
+			ThumbnailURL: file.ThumbnailURL,
+			FileName:     file.FileName,
+			FileSize:     file.FileSize,
+			FileType:     file.FileType,
+			Module:       file.Module,
+			Category:     file.Category,
+			RelatedID:    file.RelatedID,
+			TaskNodeID:   file.TaskNodeID,
+			Description:  file.Description,
+			Tags:         file.Tags,
+			UploadTime:   file.CreateAt.Format("2006-01-02 15:04:05"),
 		}
 
 		// 获取关联任务/节点名称
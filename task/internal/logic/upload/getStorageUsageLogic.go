@@ -0,0 +1,44 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetStorageUsageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询公司和当前员工的存储用量
+func NewGetStorageUsageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetStorageUsageLogic {
+	return &GetStorageUsageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetStorageUsageLogic) GetStorageUsage() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 所有员工都可以查看公司合计和自己的用量，汇总明细只对可以管理上传策略的人员开放
+	info, err := BuildStorageUsageInfo(l.ctx, l.svcCtx, employee.CompanyId, employee.Id, l.svcCtx.IsCompanyAdmin(l.ctx, employee))
+	if err != nil {
+		l.Errorf("查询存储用量失败: companyId=%s, err=%v", employee.CompanyId, err)
+		return utils.Response.InternalError("查询存储用量失败"), nil
+	}
+	return utils.Response.Success(info), nil
+}
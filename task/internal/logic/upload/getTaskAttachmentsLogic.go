@@ -77,20 +77,23 @@ func (l *GetTaskAttachmentsLogic) GetTaskAttachments(req *types.GetTaskAttachmen
 	attachments := make([]types.AttachmentInfo, 0, len(uniqueFiles))
 	for _, f := range uniqueFiles {
 		attachments = append(attachments, types.AttachmentInfo{
-			FileID:      f.FileID,
-			FileName:    f.FileName,
-			FileURL:     f.FileURL,
-			FileType:    f.FileType,
-			FileSize:    f.FileSize,
-			Module:      f.Module,
-			Category:    f.Category,
-			RelatedID:   f.RelatedID,
-			TaskNodeID:  f.TaskNodeID,
-			UploaderID:  f.UploaderID,
-			Description: f.Description,
-			Tags:        f.Tags,
-			CreateTime:  f.CreateAt.Format(time.RFC3339),
-			UpdateTime:  f.UpdateAt.Format(time.RFC3339),
+			FileID:       f.FileID,
+			FileName:     f.FileName,
+			FileURL:      f.FileURL,
+			FileType:     f.FileType,
+			ThumbnailURL: f.ThumbnailURL,
+			PreviewURL:   f.PreviewURL,
+			FileSize:     f.FileSize,
+			Module:       f.Module,
+			Category:     f.Category,
+			RelatedID:    f.RelatedID,
+			TaskNodeID:   f.TaskNodeID,
+			UploaderID:   f.UploaderID,
+			Description:  f.Description,
+			Tags:         f.Tags,
+			Version:      f.CurrentVersion(),
+			CreateTime:   f.CreateAt.Format(time.RFC3339),
+			UpdateTime:   f.UpdateAt.Format(time.RFC3339),
 		})
 	}
 
@@ -51,18 +51,21 @@ func (l *GetTaskNodeAttachmentsLogic) GetTaskNodeAttachments(req *types.GetTaskN
 	attachments := make([]types.AttachmentInfo, 0, len(files))
 	for _, f := range files {
 		attachments = append(attachments, types.AttachmentInfo{
-			FileID:      f.FileID,
-			FileName:    f.FileName,
-			FileURL:     f.FileURL,
-			FileType:    f.FileType,
-			FileSize:    f.FileSize,
-			Module:      f.Module,
-			Category:    f.Category,
-			RelatedID:   f.RelatedID,
-			Description: f.Description,
-			Tags:        f.Tags,
-			CreateTime:  f.CreateAt.Format(time.RFC3339),
-			UpdateTime:  f.UpdateAt.Format(time.RFC3339),
+			FileID:       f.FileID,
+			FileName:     f.FileName,
+			FileURL:      f.FileURL,
+			FileType:     f.FileType,
+			ThumbnailURL: f.ThumbnailURL,
+			PreviewURL:   f.PreviewURL,
+			FileSize:     f.FileSize,
+			Module:       f.Module,
+			Category:     f.Category,
+			RelatedID:    f.RelatedID,
+			Description:  f.Description,
+			Tags:         f.Tags,
+			Version:      f.CurrentVersion(),
+			CreateTime:   f.CreateAt.Format(time.RFC3339),
+			UpdateTime:   f.UpdateAt.Format(time.RFC3339),
 		})
 	}
 
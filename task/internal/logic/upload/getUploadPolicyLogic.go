@@ -0,0 +1,37 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetUploadPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取公司上传策略
+func NewGetUploadPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUploadPolicyLogic {
+	return &GetUploadPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetUploadPolicyLogic) GetUploadPolicy() (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	return utils.Response.Success(buildUploadPolicyInfo(l.ctx, l.svcCtx, employee.CompanyId)), nil
+}
@@ -0,0 +1,41 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetUploadSessionPartsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 查询上传会话和已上传的分片，用于断点续传
+func NewGetUploadSessionPartsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUploadSessionPartsLogic {
+	return &GetUploadSessionPartsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetUploadSessionPartsLogic) GetUploadSessionParts(req *types.UploadSessionRequest) (resp *types.BaseResponse, err error) {
+	sess, _, errResp := loadUploadSession(l.ctx, l.svcCtx, req.UploadID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	parts, err := l.svcCtx.UploadSessionService.Parts(l.ctx, sess)
+	if err != nil {
+		return uploadSessionError(l.ctx, sess.Id, err), nil
+	}
+	return utils.Response.Success(buildUploadSessionInfo(sess, parts, "")), nil
+}
@@ -0,0 +1,102 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	uploadModel "task_Project/model/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type InitUploadSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建大文件上传会话（分片上传或预签名直传）
+func NewInitUploadSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *InitUploadSessionLogic {
+	return &InitUploadSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *InitUploadSessionLogic) InitUploadSession(req *types.InitUploadSessionRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	fileName := strings.TrimSpace(req.FileName)
+	if fileName == "" || len([]rune(fileName)) > 255 {
+		return utils.Response.ValidationError("文件名不能为空且不能超过255个字符"), nil
+	}
+	if req.FileSize <= 0 {
+		return utils.Response.ValidationError("文件大小不正确"), nil
+	}
+	if req.Checksum != "" && !sha256HexPattern.MatchString(req.Checksum) {
+		return utils.Response.ValidationError("文件校验值必须是SHA-256的十六进制字符串"), nil
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = uploadModel.UploadModeChunked
+	}
+	if mode != uploadModel.UploadModeChunked && mode != uploadModel.UploadModeDirect {
+		return utils.Response.ValidationError("上传方式只能是 chunked 或 direct"), nil
+	}
+
+	// 与普通上传相同的默认关联
+	if req.Module == "" {
+		req.Module = "general"
+	}
+	if req.Category == "" {
+		req.Category = "attachment"
+	}
+	if req.RelatedID == "" {
+		req.RelatedID = "default"
+	}
+	if req.Module == "task" && req.Category == "attachment" && req.TaskNodeID == "" {
+		return utils.Response.ValidationError("任务附件必须关联到具体的任务节点"), nil
+	}
+	if uploadModel.IsAvatar(req.Module, req.Category) {
+		return utils.Response.ValidationError("请使用头像上传接口上传头像"), nil
+	}
+
+	// 大小在创建会话时先检查一次，文件类型和病毒扫描在完成时按实际内容检查
+	policy := l.svcCtx.FileInspectionService.LargePolicy(l.ctx, employee.CompanyId)
+	if req.FileSize > int64(policy.MaxSizeMB)*1024*1024 {
+		return utils.Response.ValidationError(fmt.Sprintf("文件大小不能超过%dMB", policy.MaxSizeMB)), nil
+	}
+
+	sess := &uploadModel.UploadSession{
+		CompanyId:   employee.CompanyId,
+		EmployeeId:  employee.Id,
+		Mode:        mode,
+		FileName:    fileName,
+		FileSize:    req.FileSize,
+		Checksum:    req.Checksum,
+		Module:      req.Module,
+		Category:    req.Category,
+		RelatedId:   req.RelatedID,
+		TaskNodeId:  req.TaskNodeID,
+		Description: req.Description,
+		Tags:        req.Tags,
+	}
+	uploadURL, err := l.svcCtx.UploadSessionService.Create(l.ctx, sess)
+	if err != nil {
+		return uploadSessionError(l.ctx, sess.Id, err), nil
+	}
+
+	logx.Infof("创建上传会话: uploadId=%s, mode=%s, fileName=%s, fileSize=%d", sess.Id, mode, fileName, req.FileSize)
+	return utils.Response.Success(buildUploadSessionInfo(sess, nil, uploadURL)), nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"task_Project/task/internal/svc"
@@ -124,15 +126,45 @@ func (l *ProxyFileLogic) ProxyFile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 从COS获取文件内容
-	// file.FilePath 是COS的Key
-	// 检查FileStorageService是否是COSStorageService
-	cosService, ok := l.svcCtx.FileStorageService.(*svc.COSStorageService)
-	if !ok {
-		http.Error(w, "存储服务类型错误", http.StatusInternalServerError)
+	// 指定 version 时下载历史版本，默认下载当前版本
+	versions := file.VersionList()
+	target := versions[len(versions)-1]
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "版本号格式错误", http.StatusBadRequest)
+			return
+		}
+		found, ok := file.FindVersion(version)
+		if !ok {
+			http.Error(w, "文件版本不存在或已被清理", http.StatusNotFound)
+			return
+		}
+		target = found
+	}
+
+	// 指定 rendition 时返回缩略图或预览图（JPEG），没有生成时返回 404
+	key, fileName, contentType := target.FilePath, target.FileName, getContentType(target.FileName)
+	switch rendition := r.URL.Query().Get("rendition"); rendition {
+	case "":
+	case "thumbnail", "preview":
+		key = target.ThumbnailPath
+		if rendition == "preview" {
+			key = target.PreviewPath
+		}
+		if key == "" {
+			http.Error(w, "该文件没有预览图", http.StatusNotFound)
+			return
+		}
+		fileName = strings.TrimSuffix(target.FileName, filepath.Ext(target.FileName)) + "_" + rendition + ".jpg"
+		contentType = "image/jpeg"
+	default:
+		http.Error(w, "不支持的预览类型", http.StatusBadRequest)
 		return
 	}
-	fileData, err := cosService.GetFile(file.FilePath)
+
+	// 从存储获取文件内容，FilePath 是存储Key
+	fileData, err := l.svcCtx.FileStorageService.GetFile(key)
 	if err != nil {
 		logx.Errorf("从COS获取文件失败: %v", err)
 		http.Error(w, "获取文件失败", http.StatusInternalServerError)
@@ -140,8 +172,8 @@ func (l *ProxyFileLogic) ProxyFile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 设置响应头
-	w.Header().Set("Content-Type", getContentType(file.FileName))
-	w.Header().Set("Content-Disposition", `inline; filename="`+file.FileName+`"`)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+fileName+`"`)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
@@ -0,0 +1,89 @@
+package upload
+
+import (
+	"context"
+
+	uploadModel "task_Project/model/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+)
+
+// storageBreakdownLimit 每种汇总最多返回的条数（按用量降序）
+const storageBreakdownLimit = 50
+
+// BuildStorageUsageInfo 公司的存储用量和配额。employeeID 不为空时包含该员工的用量；
+// breakdown 为 true 时包含按员工、任务、模块和文件类型的汇总（管理后台也使用）
+func BuildStorageUsageInfo(ctx context.Context, svcCtx *svc.ServiceContext, companyID, employeeID string, breakdown bool) (*types.StorageUsageInfo, error) {
+	quota := svcCtx.StorageQuotaService
+	limits := quota.Limits(ctx, companyID)
+	total, err := quota.UsageOf(ctx, companyID, "")
+	if err != nil {
+		return nil, err
+	}
+	info := &types.StorageUsageInfo{
+		CompanyID:      companyID,
+		Company:        types.StorageUsageItem{UsedBytes: total.UsedBytes, FileCount: total.FileCount, QuotaBytes: limits.CompanyBytes},
+		UserQuotaBytes: limits.UserBytes,
+		CompanyQuotaMB: limits.CompanyQuotaMB,
+		UserQuotaMB:    limits.UserQuotaMB,
+		Customized:     limits.Customized,
+	}
+	if employeeID != "" {
+		me, err := quota.UsageOf(ctx, companyID, employeeID)
+		if err != nil {
+			return nil, err
+		}
+		info.Me = types.StorageUsageItem{UsedBytes: me.UsedBytes, FileCount: me.FileCount, QuotaBytes: limits.UserBytes}
+	}
+	if !breakdown {
+		return info, nil
+	}
+
+	// 按员工使用用量计数，其他汇总按附件记录统计
+	usage, err := quota.Usage(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	if employees, err := svcCtx.EmployeeModel.FindAllByCompanyID(ctx, companyID); err == nil {
+		for _, e := range employees {
+			names[e.Id] = e.RealName
+		}
+	}
+	info.ByUser = []types.StorageUsageBucket{}
+	for _, u := range usage {
+		if u.EmployeeId == "" || len(info.ByUser) >= storageBreakdownLimit {
+			continue
+		}
+		info.ByUser = append(info.ByUser, types.StorageUsageBucket{Key: u.EmployeeId, Name: names[u.EmployeeId], Bytes: u.UsedBytes, Files: u.FileCount})
+	}
+
+	for _, g := range []struct {
+		groupBy string
+		dst     *[]types.StorageUsageBucket
+	}{
+		{uploadModel.StorageGroupTask, &info.ByTask},
+		{uploadModel.StorageGroupModule, &info.ByModule},
+		{uploadModel.StorageGroupFileType, &info.ByFileType},
+	} {
+		groups, err := quota.Breakdown(ctx, companyID, g.groupBy)
+		if err != nil {
+			return nil, err
+		}
+		buckets := make([]types.StorageUsageBucket, 0, len(groups))
+		for _, row := range groups {
+			if len(buckets) >= storageBreakdownLimit {
+				break
+			}
+			bucket := types.StorageUsageBucket{Key: row.Key, Bytes: row.Bytes, Files: row.Files}
+			if g.groupBy == uploadModel.StorageGroupTask {
+				if t, err := svcCtx.TaskModel.FindOne(ctx, row.Key); err == nil {
+					bucket.Name = t.TaskTitle
+				}
+			}
+			buckets = append(buckets, bucket)
+		}
+		*g.dst = buckets
+	}
+	return info, nil
+}
@@ -0,0 +1,74 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"task_Project/model/company"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateUploadPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改公司上传策略
+func NewUpdateUploadPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateUploadPolicyLogic {
+	return &UpdateUploadPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateUploadPolicyLogic) UpdateUploadPolicy(req *types.UpdateUploadPolicyRequest) (resp *types.BaseResponse, err error) {
+	employee, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, employee) {
+		return utils.Response.ForbiddenError(utils.BusinessErrorMessages["upload_policy_no_permission"]), nil
+	}
+
+	if req.Reset {
+		if err := l.svcCtx.CompanyUploadPolicyModel.Delete(l.ctx, employee.CompanyId); err != nil {
+			l.Errorf("删除公司上传策略失败: companyId=%s, err=%v", employee.CompanyId, err)
+			return utils.Response.InternalError("恢复默认上传策略失败"), nil
+		}
+		return utils.Response.Success(buildUploadPolicyInfo(l.ctx, l.svcCtx, employee.CompanyId)), nil
+	}
+
+	kinds, invalid := svc.NormalizeFileKinds(req.AllowedTypes)
+	if invalid != "" {
+		return utils.Response.BusinessError("upload_type_invalid"), nil
+	}
+	systemMax := l.svcCtx.FileInspectionService.MaxSizeMB()
+	if req.MaxFileSizeMB < 0 || req.MaxFileSizeMB > systemMax {
+		return utils.Response.ValidationError(fmt.Sprintf("文件大小上限必须在0到%dMB之间", systemMax)), nil
+	}
+
+	err = l.svcCtx.CompanyUploadPolicyModel.Upsert(l.ctx, &company.CompanyUploadPolicy{
+		CompanyId:     employee.CompanyId,
+		AllowedTypes:  strings.Join(kinds, ","),
+		MaxFileSizeMb: int64(req.MaxFileSizeMB),
+		UpdateBy:      sql.NullString{String: employee.Id, Valid: true},
+	})
+	if err != nil {
+		l.Errorf("保存公司上传策略失败: companyId=%s, err=%v", employee.CompanyId, err)
+		return utils.Response.InternalError("保存上传策略失败"), nil
+	}
+	l.Infof("公司上传策略已更新: companyId=%s, allowedTypes=%v, maxFileSizeMb=%d, operator=%s",
+		employee.CompanyId, kinds, req.MaxFileSizeMB, employee.Id)
+	return utils.Response.Success(buildUploadPolicyInfo(l.ctx, l.svcCtx, employee.CompanyId)), nil
+}
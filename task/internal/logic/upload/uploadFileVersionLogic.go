@@ -0,0 +1,114 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"strings"
+
+	uploadModel "task_Project/model/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UploadFileVersionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 上传附件新版本
+func NewUploadFileVersionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UploadFileVersionLogic {
+	return &UploadFileVersionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UploadFileVersionLogic) UploadFileVersion(req *types.UploadFileVersionRequest, handler *multipart.FileHeader, fileData multipart.File, clientIP string) (resp *types.BaseResponse, err error) {
+	file, employeeID, errResp := loadVersionedFile(l.ctx, l.svcCtx, req.FileID, true)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	// 新版本与首次上传使用相同的检查：文件内容、公司上传策略和病毒扫描
+	companyID, _ := utils.Common.GetCurrentCompanyID(l.ctx)
+	userID, _ := utils.Common.GetCurrentUserID(l.ctx)
+	fileName := handler.Filename
+	inspected, err := l.svcCtx.FileInspectionService.Inspect(l.ctx, &svc.UploadFile{
+		CompanyID: companyID,
+		UserID:    userID,
+		IP:        clientIP,
+		Path:      "/api/v1/upload/file/version",
+		FileName:  fileName,
+		Size:      handler.Size,
+		File:      fileData,
+	}, l.svcCtx.FileInspectionService.Policy(l.ctx, companyID))
+	if err != nil {
+		var rejected *svc.FileRejectedError
+		if errors.As(err, &rejected) {
+			return utils.Response.ValidationError(rejected.Message), nil
+		}
+		logx.Errorf("检查附件新版本失败: fileId=%s, err=%v", file.FileID, err)
+		return utils.Response.InternalError("文件检查失败"), nil
+	}
+
+	// 新版本占用存储配额；恢复历史版本与原版本共用存储对象，不另外占用
+	if err := l.svcCtx.StorageQuotaService.Reserve(l.ctx, companyID, employeeID, handler.Size); err != nil {
+		return fileVersionError(l.ctx, file.FileID, err), nil
+	}
+
+	// 每个版本使用独立的存储对象，历史版本不会被覆盖
+	storageID := utils.Common.GenId(file.FileID)
+	filePath, fileURL, err := l.svcCtx.FileStorageService.SaveFile(
+		file.Module,
+		file.Category,
+		file.RelatedID,
+		storageID,
+		fileName,
+		fileData,
+	)
+	if err != nil {
+		logx.Errorf("保存附件新版本失败: fileId=%s, err=%v", file.FileID, err)
+		l.svcCtx.StorageQuotaService.Release(l.ctx, companyID, employeeID, handler.Size)
+		return utils.Response.InternalError("文件保存失败"), nil
+	}
+	rendition := l.svcCtx.FileInspectionService.SaveRenditions(l.ctx, fileData, inspected.Kind, file.Module, file.RelatedID, storageID)
+
+	updated, err := l.svcCtx.FileVersionService.AddVersion(l.ctx, file, uploadModel.Upload_fileVersion{
+		FileName:   fileName,
+		FilePath:   filePath,
+		FileURL:    fileURL,
+		FileType:   inspected.Kind,
+		FileSize:   handler.Size,
+		MimeType:   inspected.MIME,
+		UploaderID: employeeID,
+		ChangeNote: strings.TrimSpace(req.ChangeNote),
+
+		Upload_fileRendition: rendition,
+	})
+	if err != nil {
+		l.svcCtx.FileStorageService.DeleteFile(filePath)
+		l.svcCtx.FileInspectionService.DeleteRenditions(rendition)
+		l.svcCtx.StorageQuotaService.Release(l.ctx, companyID, employeeID, handler.Size)
+		return fileVersionError(l.ctx, file.FileID, err), nil
+	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocFile, file.FileID)
+
+	logx.Infof("附件新版本上传成功: fileId=%s, version=%d, fileName=%s", file.FileID, updated.Version, fileName)
+	return utils.Response.Success(map[string]interface{}{
+		"fileId":   updated.FileID,
+		"version":  updated.Version,
+		"fileName": updated.FileName,
+		"fileUrl":  updated.FileURL,
+		"fileType": updated.FileType,
+		"fileSize": updated.FileSize,
+	}), nil
+}
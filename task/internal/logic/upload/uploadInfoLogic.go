@@ -8,8 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"mime/multipart"
-	"path/filepath"
-	"strings"
 	"task_Project/task/internal/utils"
 	"time"
 
@@ -35,11 +33,9 @@ func NewUploadInfoLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Upload
 	}
 }
 
-func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *multipart.FileHeader, fileData multipart.File) (resp *types.UploadInfoResponse, err error) {
+func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *multipart.FileHeader, fileData multipart.File, clientIP string) (resp *types.UploadInfoResponse, err error) {
 	// 获取文件信息
 	fileName := handler.Filename
-	fileExt := strings.ToLower(filepath.Ext(fileName))
-	fileType := l.getFileType(fileExt, handler.Header.Get("Content-Type"))
 	fileSize := handler.Size
 
 	// 获取当前用户ID
@@ -49,7 +45,7 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 	}
 
 	// 获取当前员工信息（使用 employeeID 作为 uploaderID）
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil || employee == nil {
 		return nil, errors.New("您尚未加入任何公司")
 	}
@@ -70,10 +66,29 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 	if req.Module == "task" && req.Category == "attachment" && req.TaskNodeID == "" {
 		return nil, errors.New("任务附件必须关联到具体的任务节点")
 	}
+	// 头像不计入存储配额，只能通过头像接口上传
+	if upload.IsAvatar(req.Module, req.Category) {
+		return nil, errors.New("请使用头像上传接口上传头像")
+	}
+
+	// 按文件内容校验类型和公司上传策略（大小上限、允许的类型），并扫描病毒
+	inspected, err := l.svcCtx.FileInspectionService.Inspect(l.ctx, &svc.UploadFile{
+		CompanyID: employee.CompanyId,
+		UserID:    userID,
+		IP:        clientIP,
+		Path:      "/api/v1/upload/file",
+		FileName:  fileName,
+		Size:      fileSize,
+		File:      fileData,
+	}, l.svcCtx.FileInspectionService.Policy(l.ctx, employee.CompanyId))
+	if err != nil {
+		return nil, err
+	}
+	fileType := inspected.Kind
 
-	// 文件大小限制（50MB）
-	if fileSize > 50*1024*1024 {
-		return nil, errors.New("文件大小不能超过50MB")
+	// 占用存储配额，后续保存失败时释放
+	if err := l.svcCtx.StorageQuotaService.Reserve(l.ctx, employee.CompanyId, uploaderID, fileSize); err != nil {
+		return nil, err
 	}
 
 	// 生成文件ID
@@ -90,8 +105,10 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 	)
 	if err != nil {
 		logx.Errorf("保存文件失败: %v", err)
+		l.svcCtx.StorageQuotaService.Release(l.ctx, employee.CompanyId, uploaderID, fileSize)
 		return nil, fmt.Errorf("文件保存失败: %v", err)
 	}
+	rendition := l.svcCtx.FileInspectionService.SaveRenditions(l.ctx, fileData, fileType, req.Module, req.RelatedID, fileID)
 
 	// 保存文件信息到MongoDB
 	err = l.svcCtx.UploadFileModel.Insert(l.ctx, &upload.Upload_file{
@@ -101,6 +118,7 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 		FileURL:     fileURL,
 		FileType:    fileType,
 		FileSize:    fileSize,
+		MimeType:    inspected.MIME,
 		Module:      req.Module,
 		Category:    req.Category,
 		RelatedID:   req.RelatedID,
@@ -110,13 +128,18 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 		Tags:        req.Tags,
 		CreateAt:    time.Now(),
 		UpdateAt:    time.Now(),
+
+		Upload_fileRendition: rendition,
 	})
 	if err != nil {
 		logx.Errorf("保存文件信息到MongoDB失败: %v", err)
 		// 删除已保存的文件
 		l.svcCtx.FileStorageService.DeleteFile(filePath)
+		l.svcCtx.FileInspectionService.DeleteRenditions(rendition)
+		l.svcCtx.StorageQuotaService.Release(l.ctx, employee.CompanyId, uploaderID, fileSize)
 		return nil, errors.New("保存文件信息失败")
 	}
+	l.svcCtx.SearchService.Publish(svc.SearchDocFile, fileID)
 
 	logx.Infof("文件上传成功: fileID=%s, fileName=%s, module=%s, relatedID=%s, taskNodeID=%s",
 		fileID, fileName, req.Module, req.RelatedID, req.TaskNodeID)
@@ -130,59 +153,3 @@ func (l *UploadInfoLogic) UploadInfo(req *types.UploadInfoRequest, handler *mult
 		RelatedID: req.RelatedID,
 	}, nil
 }
-
-// getFileType 根据扩展名和Content-Type获取文件类型
-func (l *UploadInfoLogic) getFileType(ext, contentType string) string {
-	// 先根据扩展名判断
-	switch ext {
-	case ".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".ico":
-		return "image"
-	case ".pdf":
-		return "pdf"
-	case ".md", ".markdown":
-		return "markdown"
-	case ".doc", ".docx":
-		return "word"
-	case ".xls", ".xlsx":
-		return "excel"
-	case ".ppt", ".pptx":
-		return "ppt"
-	case ".txt", ".log":
-		return "text"
-	case ".mp4", ".avi", ".mov", ".wmv", ".flv", ".mkv":
-		return "video"
-	case ".mp3", ".wav", ".flac", ".aac", ".ogg":
-		return "audio"
-	case ".zip", ".rar", ".7z", ".tar", ".gz":
-		return "archive"
-	case ".json", ".xml", ".yaml", ".yml":
-		return "config"
-	case ".go", ".py", ".js", ".ts", ".java", ".c", ".cpp", ".h", ".css", ".html":
-		return "code"
-	}
-
-	// 根据Content-Type判断
-	if strings.HasPrefix(contentType, "image/") {
-		return "image"
-	}
-	if strings.HasPrefix(contentType, "video/") {
-		return "video"
-	}
-	if strings.HasPrefix(contentType, "audio/") {
-		return "audio"
-	}
-	if strings.Contains(contentType, "pdf") {
-		return "pdf"
-	}
-	if strings.Contains(contentType, "word") || strings.Contains(contentType, "document") {
-		return "word"
-	}
-	if strings.Contains(contentType, "excel") || strings.Contains(contentType, "spreadsheet") {
-		return "excel"
-	}
-	if strings.Contains(contentType, "powerpoint") || strings.Contains(contentType, "presentation") {
-		return "ppt"
-	}
-
-	return "other"
-}
@@ -0,0 +1,26 @@
+package upload
+
+import (
+	"context"
+
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// buildUploadPolicyInfo 公司生效的上传策略
+func buildUploadPolicyInfo(ctx context.Context, svcCtx *svc.ServiceContext, companyID string) types.UploadPolicyInfo {
+	policy := svcCtx.FileInspectionService.Policy(ctx, companyID)
+	_, err := svcCtx.CompanyUploadPolicyModel.FindOne(ctx, companyID)
+	allowed := policy.AllowedTypes
+	if allowed == nil {
+		allowed = []string{}
+	}
+	return types.UploadPolicyInfo{
+		AllowedTypes:        allowed,
+		MaxFileSizeMB:       policy.MaxSizeMB,
+		SystemMaxFileSizeMB: svcCtx.FileInspectionService.MaxSizeMB(),
+		FileKinds:           utils.FileKinds,
+		Customized:          err == nil,
+	}
+}
@@ -0,0 +1,97 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	uploadModel "task_Project/model/upload"
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// sha256HexPattern 整个文件的 SHA-256 校验值（十六进制）
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// uploadSessionStatusNames 会话状态在接口中的名称
+var uploadSessionStatusNames = map[int64]string{
+	uploadModel.UploadSessionStatusUploading:  "uploading",
+	uploadModel.UploadSessionStatusCompleting: "completing",
+	uploadModel.UploadSessionStatusCompleted:  "completed",
+	uploadModel.UploadSessionStatusAborted:    "aborted",
+	uploadModel.UploadSessionStatusFailed:     "failed",
+}
+
+// loadUploadSession 查询当前员工的上传会话，其他员工的会话视为不存在
+func loadUploadSession(ctx context.Context, svcCtx *svc.ServiceContext, uploadID string) (*uploadModel.UploadSession, *user.Employee, *types.BaseResponse) {
+	if uploadID == "" {
+		return nil, nil, utils.Response.ValidationError("上传会话ID不能为空")
+	}
+	employee, errResp := svcCtx.CurrentOperator(ctx)
+	if errResp != nil {
+		return nil, nil, errResp
+	}
+	sess, err := svcCtx.UploadSessionService.Find(ctx, uploadID, employee.Id)
+	if err != nil {
+		return nil, nil, uploadSessionError(ctx, uploadID, err)
+	}
+	return sess, employee, nil
+}
+
+// uploadSessionError 上传会话错误对应的响应，文件检查未通过时返回检查结果
+func uploadSessionError(ctx context.Context, uploadID string, err error) *types.BaseResponse {
+	var rejected *svc.FileRejectedError
+	switch {
+	case errors.As(err, &rejected):
+		return utils.Response.ValidationError(rejected.Message)
+	case errors.Is(err, svc.ErrUploadSessionNotFound):
+		return utils.Response.NotFoundError("upload_session_not_found")
+	case errors.Is(err, svc.ErrUploadSessionClosed):
+		return utils.Response.ConflictError("upload_session_closed")
+	case errors.Is(err, svc.ErrUploadModeUnsupported):
+		return utils.Response.BusinessError("upload_mode_unsupported")
+	case errors.Is(err, svc.ErrUploadPartInvalid):
+		return utils.Response.BusinessError("upload_part_invalid")
+	case errors.Is(err, svc.ErrUploadPartChecksum):
+		return utils.Response.BusinessError("upload_part_checksum")
+	case errors.Is(err, svc.ErrUploadIncomplete):
+		return utils.Response.BusinessError("upload_incomplete")
+	case errors.Is(err, svc.ErrUploadChecksumMismatch):
+		return utils.Response.BusinessError("upload_checksum_mismatch")
+	case errors.Is(err, svc.ErrStorageQuotaExceeded):
+		return utils.Response.BusinessError("storage_quota_exceeded")
+	case errors.Is(err, svc.ErrStorageUserQuotaExceeded):
+		return utils.Response.BusinessError("storage_user_quota_exceeded")
+	case errors.Is(err, uploadModel.ErrNotFound):
+		return utils.Response.NotFoundError("file_not_found")
+	default:
+		logx.WithContext(ctx).Errorf("处理上传会话失败: uploadId=%s, err=%v", uploadID, err)
+		return utils.Response.InternalError("上传失败，请稍后重试")
+	}
+}
+
+// buildUploadSessionInfo 上传会话信息，parts 为已上传的分片
+func buildUploadSessionInfo(sess *uploadModel.UploadSession, parts []*uploadModel.UploadSessionPart, uploadURL string) types.UploadSessionInfo {
+	uploaded := make([]int64, 0, len(parts))
+	for _, p := range parts {
+		uploaded = append(uploaded, p.PartNumber)
+	}
+	return types.UploadSessionInfo{
+		UploadID:      sess.Id,
+		Mode:          sess.Mode,
+		FileName:      sess.FileName,
+		FileSize:      sess.FileSize,
+		PartSize:      sess.PartSize,
+		PartCount:     sess.PartCount,
+		UploadedParts: uploaded,
+		UploadURL:     uploadURL,
+		Status:        uploadSessionStatusNames[sess.Status],
+		FileID:        sess.FileId,
+		ErrorMessage:  sess.ErrorMessage,
+		ExpireTime:    utils.Common.FormatTime(sess.ExpireTime),
+	}
+}
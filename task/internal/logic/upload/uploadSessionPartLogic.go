@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package upload
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+
+	uploadModel "task_Project/model/upload"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UploadSessionPartLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 上传分片（multipart 的 chunk 字段）
+func NewUploadSessionPartLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UploadSessionPartLogic {
+	return &UploadSessionPartLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UploadSessionPartLogic) UploadSessionPart(req *types.UploadSessionPartRequest, handler *multipart.FileHeader, fileData multipart.File) (resp *types.BaseResponse, err error) {
+	sess, _, errResp := loadUploadSession(l.ctx, l.svcCtx, req.UploadID)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if sess.Mode != uploadModel.UploadModeChunked {
+		return utils.Response.BusinessError("upload_mode_unsupported"), nil
+	}
+	if req.PartNumber < 1 || req.PartNumber > sess.PartCount {
+		return utils.Response.BusinessError("upload_part_invalid"), nil
+	}
+	if handler.Size > sess.PartSize {
+		return utils.Response.BusinessError("upload_part_invalid"), nil
+	}
+
+	// 分片大小由会话决定，多读一个字节用于发现超出的内容
+	data, err := io.ReadAll(io.LimitReader(fileData, sess.PartSize+1))
+	if err != nil {
+		logx.Errorf("读取上传分片失败: uploadId=%s, part=%d, err=%v", sess.Id, req.PartNumber, err)
+		return utils.Response.InternalError("读取分片失败"), nil
+	}
+	if err := l.svcCtx.UploadSessionService.UploadPart(l.ctx, sess, req.PartNumber, data, req.Md5); err != nil {
+		return uploadSessionError(l.ctx, sess.Id, err), nil
+	}
+
+	return utils.Response.Success(map[string]interface{}{
+		"uploadId":   sess.Id,
+		"partNumber": req.PartNumber,
+		"size":       len(data),
+	}), nil
+}
@@ -31,7 +31,7 @@ func NewUploadAvatarLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Uplo
 	}
 }
 
-func (l *UploadAvatarLogic) UploadAvatar(req *types.UploadAvatarRequest, handler *multipart.FileHeader, fileData multipart.File) (resp *types.UploadAvatarResponse, err error) {
+func (l *UploadAvatarLogic) UploadAvatar(req *types.UploadAvatarRequest, handler *multipart.FileHeader, fileData multipart.File, clientIP string) (resp *types.UploadAvatarResponse, err error) {
 	// 获取当前用户ID
 	userID := req.UserID
 	if userID == "" {
@@ -43,7 +43,7 @@ func (l *UploadAvatarLogic) UploadAvatar(req *types.UploadAvatarRequest, handler
 	}
 
 	// 获取当前员工信息（使用 employeeID 作为 uploaderID）
-	employee, err := l.svcCtx.EmployeeModel.FindByUserID(l.ctx, userID)
+	employee, err := l.svcCtx.CompanyMembershipService.CurrentEmployee(l.ctx, userID)
 	if err != nil || employee == nil {
 		return nil, fmt.Errorf("您尚未加入任何公司")
 	}
@@ -59,12 +59,21 @@ func (l *UploadAvatarLogic) UploadAvatar(req *types.UploadAvatarRequest, handler
 		return nil, fmt.Errorf("不支持的图片格式，仅支持 jpg/jpeg/png/gif/webp")
 	}
 
-	// 验证文件大小（限制5MB）
-	if handler.Size > 5*1024*1024 {
-		return nil, fmt.Errorf("图片大小不能超过5MB")
+	// 按文件内容校验图片（限制5MB），并扫描病毒
+	inspected, err := l.svcCtx.FileInspectionService.Inspect(l.ctx, &svc.UploadFile{
+		CompanyID: employee.CompanyId,
+		UserID:    userID,
+		IP:        clientIP,
+		Path:      "/api/v1/upload/avatar",
+		FileName:  fileName,
+		Size:      handler.Size,
+		File:      fileData,
+	}, svc.UploadPolicy{AllowedTypes: []string{utils.FileKindImage}, MaxSizeMB: 5})
+	if err != nil {
+		return nil, err
 	}
 
-	fileType := "image"
+	fileType := inspected.Kind
 	fileSize := handler.Size
 	fileID := utils.Common.GenId("avatar")
 
@@ -102,6 +111,7 @@ func (l *UploadAvatarLogic) UploadAvatar(req *types.UploadAvatarRequest, handler
 		FileURL:     fileURL,
 		FileType:    fileType,
 		FileSize:    fileSize,
+		MimeType:    inspected.MIME,
 		Module:      "user",
 		Category:    "avatar",
 		RelatedID:   userID,
@@ -0,0 +1,59 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package workload
+
+import (
+	"context"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EmployeeWorkloadLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 员工工作负载
+func NewEmployeeWorkloadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *EmployeeWorkloadLogic {
+	return &EmployeeWorkloadLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *EmployeeWorkloadLogic) EmployeeWorkload(req *types.EmployeeWorkloadRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	weeks, errResp := normalizeWeeks(req.Weeks)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	target := operator
+	if req.EmployeeID != "" && req.EmployeeID != operator.Id {
+		target, err = l.svcCtx.EmployeeModel.FindOne(l.ctx, req.EmployeeID)
+		if err != nil {
+			return utils.Response.BusinessError("employee_not_found"), nil
+		}
+		if !canManageEmployee(l.ctx, l.svcCtx, operator, target) {
+			return utils.Response.BusinessError("workload_no_permission"), nil
+		}
+	}
+
+	loads, err := l.svcCtx.CapacityService.Weekly(l.ctx, target.CompanyId, []*user.Employee{target}, weeks)
+	if err != nil || len(loads) == 0 {
+		l.Logger.Errorf("计算员工工作负载失败: %v", err)
+		return utils.Response.InternalError("计算工作负载失败"), nil
+	}
+	return utils.Response.Success(toEmployeeWorkload(loads[0], positionName(l.ctx, l.svcCtx, map[string]string{}, target))), nil
+}
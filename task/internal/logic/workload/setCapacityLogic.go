@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package workload
+
+import (
+	"context"
+	"database/sql"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetCapacityLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 设置员工每周可用工时
+func NewSetCapacityLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetCapacityLogic {
+	return &SetCapacityLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetCapacityLogic) SetCapacity(req *types.SetCapacityRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if req.WeeklyHours < 0 || req.WeeklyHours > maxCapacityHours {
+		return utils.Response.BusinessError("capacity_hours_invalid"), nil
+	}
+	target, err := l.svcCtx.EmployeeModel.FindOne(l.ctx, req.EmployeeID)
+	if err != nil {
+		return utils.Response.BusinessError("employee_not_found"), nil
+	}
+	if !canManageEmployee(l.ctx, l.svcCtx, operator, target) {
+		return utils.Response.BusinessError("workload_no_permission"), nil
+	}
+
+	err = l.svcCtx.EmployeeCapacityModel.Upsert(l.ctx, &user.EmployeeCapacity{
+		EmployeeId:  target.Id,
+		CompanyId:   target.CompanyId,
+		WeeklyHours: req.WeeklyHours,
+		UpdateBy:    sql.NullString{String: operator.Id, Valid: true},
+	})
+	if err != nil {
+		l.Logger.Errorf("设置员工每周可用工时失败: %v", err)
+		return utils.Response.InternalError("设置每周可用工时失败"), nil
+	}
+	return utils.Response.SuccessWithMessage("设置成功", map[string]interface{}{
+		"employeeId":  target.Id,
+		"weeklyHours": req.WeeklyHours,
+	}), nil
+}
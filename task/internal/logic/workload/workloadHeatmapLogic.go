@@ -0,0 +1,95 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package workload
+
+import (
+	"context"
+	"time"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type WorkloadHeatmapLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 部门工作负载热力图
+func NewWorkloadHeatmapLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WorkloadHeatmapLogic {
+	return &WorkloadHeatmapLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WorkloadHeatmapLogic) WorkloadHeatmap(req *types.WorkloadHeatmapRequest) (resp *types.BaseResponse, err error) {
+	operator, errResp := l.svcCtx.CurrentOperator(l.ctx)
+	if errResp != nil {
+		return errResp, nil
+	}
+	weeks, errResp := normalizeWeeks(req.Weeks)
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	departmentID := req.DepartmentID
+	if departmentID == "" {
+		if !operator.DepartmentId.Valid || operator.DepartmentId.String == "" {
+			return utils.Response.BusinessError("department_not_found"), nil
+		}
+		departmentID = operator.DepartmentId.String
+	}
+	dept, err := l.svcCtx.DepartmentModel.FindOne(l.ctx, departmentID)
+	if err != nil || dept.CompanyId != operator.CompanyId {
+		return utils.Response.BusinessError("department_not_found"), nil
+	}
+	if !l.svcCtx.IsCompanyAdmin(l.ctx, operator) && !(dept.ManagerId.Valid && dept.ManagerId.String == operator.Id) {
+		return utils.Response.BusinessError("workload_no_permission"), nil
+	}
+
+	members, err := l.svcCtx.EmployeeModel.FindByDepartmentID(l.ctx, departmentID)
+	if err != nil {
+		l.Logger.Errorf("查询部门员工失败: %v", err)
+		return utils.Response.InternalError("查询部门员工失败"), nil
+	}
+	employees := make([]*user.Employee, 0, len(members))
+	for _, e := range members {
+		if e.Status != 0 {
+			employees = append(employees, e)
+		}
+	}
+
+	loads, err := l.svcCtx.CapacityService.Weekly(l.ctx, operator.CompanyId, employees, weeks)
+	if err != nil {
+		l.Logger.Errorf("计算部门工作负载失败: %v", err)
+		return utils.Response.InternalError("计算工作负载失败"), nil
+	}
+
+	result := types.WorkloadHeatmapResponse{
+		DepartmentID:    dept.Id,
+		DepartmentName:  dept.DepartmentName,
+		WeekStarts:      make([]string, 0, weeks),
+		OverloadPercent: l.svcCtx.CapacityService.OverloadPercent(),
+		Employees:       make([]types.EmployeeWorkload, 0, len(loads)),
+	}
+	firstWeek := svc.WeekStart(time.Now())
+	for w := 0; w < weeks; w++ {
+		result.WeekStarts = append(result.WeekStarts, firstWeek.AddDate(0, 0, w*7).Format("2006-01-02"))
+	}
+	positions := make(map[string]string)
+	for _, load := range loads {
+		result.Employees = append(result.Employees, toEmployeeWorkload(load, positionName(l.ctx, l.svcCtx, positions, load.Employee)))
+		if isOverloaded(load) {
+			result.OverloadedCount++
+		}
+	}
+	return utils.Response.Success(result), nil
+}
@@ -0,0 +1,100 @@
+package workload
+
+import (
+	"context"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/svc"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// 工作负载统计的默认周数和最大周数
+const (
+	defaultWorkloadWeeks = 4
+	maxWorkloadWeeks     = 12
+	maxCapacityHours     = 80
+)
+
+// normalizeWeeks 校验统计周数，未传时使用默认值
+func normalizeWeeks(weeks int) (int, *types.BaseResponse) {
+	if weeks == 0 {
+		return defaultWorkloadWeeks, nil
+	}
+	if weeks < 0 || weeks > maxWorkloadWeeks {
+		return 0, utils.Response.BusinessError("workload_weeks_invalid")
+	}
+	return weeks, nil
+}
+
+// isDepartmentManager 判断员工是否为指定部门的部门经理
+func isDepartmentManager(ctx context.Context, svcCtx *svc.ServiceContext, departmentID string, employee *user.Employee) bool {
+	if departmentID == "" {
+		return false
+	}
+	dept, err := svcCtx.DepartmentModel.FindOne(ctx, departmentID)
+	if err != nil || dept.CompanyId != employee.CompanyId {
+		return false
+	}
+	return dept.ManagerId.Valid && dept.ManagerId.String == employee.Id
+}
+
+// canManageEmployee 管理人员或目标员工所在部门的经理可以查看和设置该员工的工作负载
+func canManageEmployee(ctx context.Context, svcCtx *svc.ServiceContext, operator, target *user.Employee) bool {
+	if operator.CompanyId != target.CompanyId {
+		return false
+	}
+	if svcCtx.IsCompanyAdmin(ctx, operator) {
+		return true
+	}
+	return target.DepartmentId.Valid && isDepartmentManager(ctx, svcCtx, target.DepartmentId.String, operator)
+}
+
+// positionName 查询职位名称，查询失败时返回空字符串
+func positionName(ctx context.Context, svcCtx *svc.ServiceContext, cache map[string]string, employee *user.Employee) string {
+	if !employee.PositionId.Valid {
+		return ""
+	}
+	if name, ok := cache[employee.PositionId.String]; ok {
+		return name
+	}
+	name := ""
+	if pos, err := svcCtx.PositionModel.FindOne(ctx, employee.PositionId.String); err == nil {
+		name = pos.PositionName
+	}
+	cache[employee.PositionId.String] = name
+	return name
+}
+
+// toEmployeeWorkload 转换员工负载数据
+func toEmployeeWorkload(load *svc.EmployeeLoad, position string) types.EmployeeWorkload {
+	item := types.EmployeeWorkload{
+		EmployeeID:   load.Employee.Id,
+		EmployeeName: load.Employee.RealName,
+		PositionName: position,
+		WeeklyHours:  load.WeeklyHours,
+		OnLeave:      load.OnLeave,
+		OpenNodes:    load.OpenNodes,
+		Weeks:        make([]types.WorkloadWeek, 0, len(load.Weeks)),
+	}
+	for _, w := range load.Weeks {
+		item.Weeks = append(item.Weeks, types.WorkloadWeek{
+			WeekStart:      w.WeekStart.Format("2006-01-02"),
+			AvailableHours: w.AvailableHours,
+			AssignedHours:  w.AssignedHours,
+			Utilization:    w.Utilization,
+			Level:          w.Level,
+		})
+	}
+	return item
+}
+
+// isOverloaded 判断员工是否存在超负荷的周
+func isOverloaded(load *svc.EmployeeLoad) bool {
+	for _, w := range load.Weeks {
+		if w.Level == svc.CapacityLevelOver {
+			return true
+		}
+	}
+	return false
+}
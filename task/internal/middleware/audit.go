@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// AuditBodyLimit 审计记录中请求/响应正文脱敏后的最大保存长度
+	AuditBodyLimit = 64 * 1024
+	// 响应正文的最大缓存长度，超过时正文不完整，无法脱敏，不保存
+	auditCaptureLimit = 1024 * 1024
+)
+
+// AuditEntry 一次变更类请求的审计信息
+type AuditEntry struct {
+	UserID       string
+	EmployeeID   string
+	CompanyID    string
+	Method       string
+	Path         string
+	Module       string // 路径中的业务模块，如 task、tasknode
+	Action       string // 模块后的操作路径，如 update、approve/completion
+	EntityType   string
+	EntityID     string
+	RequestBody  string
+	ResponseBody string
+	StatusCode   int
+	Success      bool
+	ErrorMessage string
+	IP           string
+	UserAgent    string
+	Duration     time.Duration
+	Before       interface{} // 变更前实体快照，无法获取时为 nil
+	After        interface{} // 变更后实体快照，无法获取时为 nil
+}
+
+// AuditDeps 审计中间件依赖（以函数注入，避免 middleware 反向依赖 svc）
+type AuditDeps struct {
+	// Snapshot 读取实体当前状态，用于生成变更前后对比；不支持的实体类型返回 false
+	Snapshot func(ctx context.Context, entityType, entityID string) (interface{}, bool)
+	// Record 持久化审计记录
+	Record func(ctx context.Context, entry AuditEntry)
+}
+
+// AuditMiddleware 请求审计中间件，记录所有变更类接口调用
+type AuditMiddleware struct {
+	deps AuditDeps
+	// 只读的 POST 接口（按路径最后一段匹配），不记录审计
+	readOnly map[string]bool
+	// 各模块请求体中标识实体ID的字段，按优先级排列
+	entityKeys map[string][]string
+}
+
+func NewAuditMiddleware(deps AuditDeps) *AuditMiddleware {
+	return &AuditMiddleware{
+		deps: deps,
+		readOnly: map[string]bool{
+			"list": true, "get": true, "detail": true, "my": true, "my-approvals": true,
+			"pending": true, "logs": true, "login-records": true, "employeeRoles": true,
+			"positionRoles": true, "parse": true, "attachments": true, "search": true,
+			"export": true, "current": true, "report": true, "burndown": true, "burnup": true,
+			"cfd": true, "forecast": true, "at-risk": true, "heatmap": true, "employee": true,
+			"columns": true, "query": true, "items": true, "replies": true, "revisions": true,
+			"versions": true, "parts": true, "usage": true,
+		},
+		entityKeys: map[string][]string{
+			"task":         {"taskId", "id"},
+			"tasknode":     {"nodeId", "taskNodeId", "id"},
+			"employee":     {"id", "employeeId"},
+			"department":   {"id", "departmentId"},
+			"export":       {"jobId", "id"},
+			"import":       {"jobId", "id"},
+			"label":        {"labelId", "id"},
+			"customfield":  {"fieldId", "id"},
+			"board":        {"boardId", "id"},
+			"position":     {"id", "positionId"},
+			"company":      {"id", "companyId"},
+			"role":         {"id", "roleId"},
+			"handover":     {"handoverId", "id"},
+			"checklist":    {"checklistId", "id"},
+			"notification": {"notificationId", "id"},
+			"timetrack":    {"entryId", "timesheetId", "id"},
+			"upload":       {"fileId", "id"},
+			"user":         {"userId", "id"},
+			"workload":     {"employeeId", "departmentId"},
+		},
+	}
+}
+
+func (m *AuditMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.deps.Record == nil || !m.shouldAudit(r) {
+			next(w, r)
+			return
+		}
+		// 未登录的请求（登录、注册等）由安全日志记录
+		userID, _ := r.Context().Value("userId").(string)
+		if userID == "" {
+			next(w, r)
+			return
+		}
+
+		start := time.Now()
+		module, action := splitAuditPath(r.URL.Path)
+		entry := AuditEntry{
+			UserID:     userID,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Module:     module,
+			Action:     action,
+			EntityType: module,
+			IP:         getClientIP(r),
+			UserAgent:  r.UserAgent(),
+		}
+		entry.EmployeeID, _ = r.Context().Value("employeeId").(string)
+		entry.CompanyID, _ = r.Context().Value("companyId").(string)
+
+		// 读取请求体后回填，文件上传等 multipart 请求不保存正文；
+		// 正文保留完整内容，由记录方脱敏后再截断，避免截断后无法解析而漏掉敏感字段
+		var reqFields map[string]interface{}
+		if r.Body != nil && !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			body, err := io.ReadAll(r.Body)
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if err == nil {
+				entry.RequestBody = string(body)
+				_ = json.Unmarshal(body, &reqFields)
+			}
+		}
+		entry.EntityID = m.lookupEntityID(module, reqFields)
+
+		if entry.EntityID != "" && m.deps.Snapshot != nil {
+			if before, ok := m.deps.Snapshot(r.Context(), entry.EntityType, entry.EntityID); ok {
+				entry.Before = before
+			}
+		}
+
+		rec := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		entry.Duration = time.Since(start)
+		entry.StatusCode = rec.status
+		entry.ResponseBody = rec.body.String()
+		entry.Success, entry.ErrorMessage = parseAuditResult(rec.status, rec.body.Bytes())
+
+		// 创建类接口在请求体中没有实体ID，尝试从响应数据中获取
+		if entry.EntityID == "" {
+			var resp struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if json.Unmarshal(rec.body.Bytes(), &resp) == nil {
+				entry.EntityID = m.lookupEntityID(module, resp.Data)
+			}
+		}
+		if entry.Success && entry.EntityID != "" && m.deps.Snapshot != nil {
+			if after, ok := m.deps.Snapshot(r.Context(), entry.EntityType, entry.EntityID); ok {
+				entry.After = after
+			}
+		}
+
+		m.deps.Record(r.Context(), entry)
+	}
+}
+
+// shouldAudit 判断请求是否为变更类调用
+func (m *AuditMiddleware) shouldAudit(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/") {
+		return false
+	}
+	path := strings.TrimRight(r.URL.Path, "/")
+	last := path[strings.LastIndex(path, "/")+1:]
+	return !m.readOnly[last]
+}
+
+// lookupEntityID 按模块配置的字段顺序从 JSON 字段中取实体ID
+func (m *AuditMiddleware) lookupEntityID(module string, fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys, ok := m.entityKeys[module]
+	if !ok {
+		keys = []string{"id"}
+	}
+	for _, key := range keys {
+		if v, ok := fields[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// splitAuditPath 将 /api/v1/{module}/{action} 拆分为模块和操作
+func splitAuditPath(path string) (string, string) {
+	rest := strings.Trim(strings.TrimPrefix(path, "/api/v1/"), "/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return rest[:i], rest[i+1:]
+	}
+	return rest, ""
+}
+
+// parseAuditResult 根据 HTTP 状态码和业务响应码判断请求是否成功
+func parseAuditResult(status int, body []byte) (bool, string) {
+	if status >= http.StatusBadRequest {
+		return false, strings.TrimSpace(truncateAuditBody(body))
+	}
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &resp) != nil || resp.Code == 0 || resp.Code == http.StatusOK {
+		return true, ""
+	}
+	return false, resp.Msg
+}
+
+func truncateAuditBody(body []byte) string {
+	if len(body) > AuditBodyLimit {
+		return string(body[:AuditBodyLimit])
+	}
+	return string(body)
+}
+
+// auditResponseWriter 记录响应状态码和响应正文（超过上限的部分不缓存）
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if remain := auditCaptureLimit - w.body.Len(); remain > 0 {
+		if len(b) > remain {
+			w.body.Write(b[:remain])
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
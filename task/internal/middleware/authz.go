@@ -14,8 +14,11 @@ type AuthzDeps struct {
 	FindEmployeeByUserID func(ctx context.Context, userId string) (interface {
 		GetEmployeeId() string
 		GetId() string
+		GetCompanyId() string
 	}, error)
 	ListRolesByEmployeeId func(ctx context.Context, employeeId string) ([]interface{ GetPermissions() string }, error)
+	// ListGrantedPermCodes 查询用户在公司下生效的直接授权/临时委派权限码（user_permission），可为空
+	ListGrantedPermCodes func(ctx context.Context, userId, companyId string) ([]int, error)
 }
 
 type AuthzMiddleware struct {
@@ -59,6 +62,10 @@ func NewAuthzMiddleware(deps AuthzDeps) *AuthzMiddleware {
 		"POST /api/v1/role/delete": PermRoleDelete,
 		"POST /api/v1/role/assign": PermRoleAssign,
 		"POST /api/v1/role/revoke": PermRoleRevoke,
+		// permission（用户直接授权/临时委派）
+		"POST /api/v1/permission/grant":    PermRoleAssign,
+		"POST /api/v1/permission/delegate": PermRoleAssign,
+		"POST /api/v1/permission/revoke":   PermRoleRevoke,
 		// employee
 		"POST /api/v1/employee/create": PermEmployeeCreate,
 		"PUT /api/v1/employee/update":  PermEmployeeUpdate,
@@ -124,6 +131,12 @@ func (m *AuthzMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 			http.Error(w, "Forbidden", http.StatusForbidden)
 			return
 		}
+		// 用户可在多家公司任职，员工记录必须属于令牌中的当前公司
+		if companyId, ok := ctx.Value("companyId").(string); ok && companyId != "" && emp.GetCompanyId() != companyId {
+			logx.Errorf("AuthZ: employee company mismatch user=%s token company=%s employee company=%s", userId, companyId, emp.GetCompanyId())
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
 		// 优先使用内部主键 Id（与 employee.position_id 关联，通过职位获得角色），业务工号为备选
 		employeeId := emp.GetId()
 		if employeeId == "" {
@@ -137,11 +150,6 @@ func (m *AuthzMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 			http.Error(w, "Forbidden", http.StatusForbidden)
 			return
 		}
-		if len(roles) == 0 {
-			logx.Infof("AuthZ: no roles bound for employee=%s user=%s, needPerm=%d path=%s (员工可能没有职位或职位没有角色)", employeeId, userId, needPerm, key)
-			http.Error(w, "Forbidden: no roles assigned", http.StatusForbidden)
-			return
-		}
 		perms := make([]string, 0, 8)
 		for _, r := range roles {
 			p := strings.TrimSpace(r.GetPermissions())
@@ -151,14 +159,35 @@ func (m *AuthzMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 			perms = append(perms, p)
 		}
 
-		if len(perms) == 0 {
-			logx.Infof("AuthZ: no permissions found for employee=%s user=%s, roles=%d but all permissions empty", employeeId, userId, len(roles))
-			http.Error(w, "Forbidden: no permissions", http.StatusForbidden)
+		if allowByDict(perms, needPerm) {
+			next(w, r)
 			return
 		}
 
-		if allowByDict(perms, needPerm) {
-			next(w, r)
+		// 角色未覆盖时，检查直接授权/临时委派（已过期的授权不会被查出）
+		companyId := emp.GetCompanyId()
+		if v, ok := ctx.Value("companyId").(string); ok && v != "" {
+			companyId = v
+		}
+		if m.deps.ListGrantedPermCodes != nil {
+			granted, err := m.deps.ListGrantedPermCodes(ctx, userId, companyId)
+			if err != nil {
+				logx.Errorf("AuthZ: list granted permissions failed user=%s company=%s: %v", userId, companyId, err)
+			} else if allowByCodes(granted, needPerm) {
+				logx.Infof("AuthZ: allowed by direct grant user=%s employee=%s needPerm=%d path=%s", userId, employeeId, needPerm, key)
+				next(w, r)
+				return
+			}
+		}
+
+		if len(roles) == 0 {
+			logx.Infof("AuthZ: no roles bound for employee=%s user=%s, needPerm=%d path=%s (员工可能没有职位或职位没有角色)", employeeId, userId, needPerm, key)
+			http.Error(w, "Forbidden: no roles assigned", http.StatusForbidden)
+			return
+		}
+		if len(perms) == 0 {
+			logx.Infof("AuthZ: no permissions found for employee=%s user=%s, roles=%d but all permissions empty", employeeId, userId, len(roles))
+			http.Error(w, "Forbidden: no permissions", http.StatusForbidden)
 			return
 		}
 		logx.Infof("AuthZ: forbidden user=%s employee=%s needPerm=%d path=%s perms_raw=%v", userId, employeeId, needPerm, key, perms)
@@ -195,3 +224,16 @@ func allowByDict(perms []string, need int) bool {
 	}
 	return false
 }
+
+// allowByCodes 按权限码集合校验（直接授权）
+func allowByCodes(codes []int, need int) bool {
+	if need == 0 {
+		return true
+	}
+	for _, c := range codes {
+		if c == need {
+			return true
+		}
+	}
+	return false
+}
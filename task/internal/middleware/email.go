@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/smtp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -33,6 +34,7 @@ type EmailMessage struct {
 
 // EmailMiddleware 邮件中间件
 type EmailMiddleware struct {
+	mu     sync.RWMutex
 	config EmailConfig
 }
 
@@ -43,10 +45,24 @@ func NewEmailMiddleware(config EmailConfig) *EmailMiddleware {
 	}
 }
 
+// UpdateConfig 运行时修改邮件配置（系统配置热更新）
+func (e *EmailMiddleware) UpdateConfig(fn func(cfg *EmailConfig)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fn(&e.config)
+}
+
+// cfg 返回当前邮件配置的副本
+func (e *EmailMiddleware) cfg() EmailConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config
+}
+
 // SendEmail 发送邮件
 func (e *EmailMiddleware) SendEmail(ctx context.Context, msg EmailMessage) error {
 	// 检查邮件功能是否启用
-	if !e.config.Enabled {
+	if !e.cfg().Enabled {
 		logx.Infof("[EmailMiddleware] Email sending is disabled, skipping: subject=%s, to=%v", msg.Subject, msg.To)
 		return nil
 	}
@@ -56,7 +72,7 @@ func (e *EmailMiddleware) SendEmail(ctx context.Context, msg EmailMessage) error
 	}
 	// 构建邮件头
 	headers := make(map[string]string)
-	headers["From"] = fmt.Sprintf("%s <%s>", e.config.From, e.config.Username)
+	headers["From"] = fmt.Sprintf("%s <%s>", e.cfg().From, e.cfg().Username)
 	headers["To"] = msg.To[0]
 	headers["Subject"] = msg.Subject
 	headers["Date"] = time.Now().Format(time.RFC1123Z)
@@ -76,39 +92,39 @@ func (e *EmailMiddleware) SendEmail(ctx context.Context, msg EmailMessage) error
 	emailBody += "\r\n" + msg.Body
 
 	// 设置SMTP认证
-	auth := smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	auth := smtp.PlainAuth("", e.cfg().Username, e.cfg().Password, e.cfg().Host)
 
 	// 构建SMTP地址
-	addr := fmt.Sprintf("%s:%d", e.config.Host, e.config.Port)
+	addr := fmt.Sprintf("%s:%d", e.cfg().Host, e.cfg().Port)
 
 	var err error
 	switch {
-	case e.config.UseTLS && e.config.Port == 465:
+	case e.cfg().UseTLS && e.cfg().Port == 465:
 		// SMTPS implicit TLS
 		logx.Infof("[EmailMiddleware] SMTP send mode=SMTPS addr=%s from=%s to=%v subject=%s",
-			addr, e.config.Username, msg.To, msg.Subject)
-		err = e.sendMailWithTLS(addr, auth, e.config.Username, msg.To, []byte(emailBody))
+			addr, e.cfg().Username, msg.To, msg.Subject)
+		err = e.sendMailWithTLS(addr, auth, e.cfg().Username, msg.To, []byte(emailBody))
 		if err != nil {
 			logx.Errorf("[EmailMiddleware] SMTPS send failed: error=%v, addr=%s, from=%s, to=%v",
-				err, addr, e.config.Username, msg.To)
+				err, addr, e.cfg().Username, msg.To)
 		}
-	case e.config.UseTLS && e.config.Port == 587:
+	case e.cfg().UseTLS && e.cfg().Port == 587:
 		// STARTTLS on submission port
 		logx.Infof("[EmailMiddleware] SMTP send mode=STARTTLS addr=%s from=%s to=%v subject=%s",
-			addr, e.config.Username, msg.To, msg.Subject)
-		err = e.sendMailWithSTARTTLS(addr, auth, e.config.Host, e.config.Username, msg.To, []byte(emailBody))
+			addr, e.cfg().Username, msg.To, msg.Subject)
+		err = e.sendMailWithSTARTTLS(addr, auth, e.cfg().Host, e.cfg().Username, msg.To, []byte(emailBody))
 		if err != nil {
 			logx.Errorf("[EmailMiddleware] STARTTLS send failed: error=%v, addr=%s, from=%s, to=%v",
-				err, addr, e.config.Username, msg.To)
+				err, addr, e.cfg().Username, msg.To)
 		}
 	default:
 		// Plain SMTP (for debug environments)
 		logx.Infof("[EmailMiddleware] SMTP send mode=PLAIN addr=%s from=%s to=%v subject=%s",
-			addr, e.config.Username, msg.To, msg.Subject)
-		err = e.sendMailPlain(addr, auth, e.config.Username, msg.To, []byte(emailBody))
+			addr, e.cfg().Username, msg.To, msg.Subject)
+		err = e.sendMailPlain(addr, auth, e.cfg().Username, msg.To, []byte(emailBody))
 		if err != nil {
 			logx.Errorf("[EmailMiddleware] Plain SMTP send failed: error=%v, addr=%s, from=%s, to=%v",
-				err, addr, e.config.Username, msg.To)
+				err, addr, e.cfg().Username, msg.To)
 		}
 	}
 	if err != nil {
@@ -116,20 +132,20 @@ func (e *EmailMiddleware) SendEmail(ctx context.Context, msg EmailMessage) error
 		return err
 	}
 
-	logx.Infof("[EmailMiddleware] 邮件发送成功: subject=%s, from=%s, to=%v", msg.Subject, e.config.Username, msg.To)
+	logx.Infof("[EmailMiddleware] 邮件发送成功: subject=%s, from=%s, to=%v", msg.Subject, e.cfg().Username, msg.To)
 	return nil
 }
 
 // sendMailWithTLS 使用TLS发送邮件
 func (e *EmailMiddleware) sendMailWithTLS(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
-	logx.Infof("[EmailMiddleware] Establishing TLS connection to %s (host=%s)", addr, e.config.Host)
+	logx.Infof("[EmailMiddleware] Establishing TLS connection to %s (host=%s)", addr, e.cfg().Host)
 
 	// 增加超时时间到 60 秒，QQ 邮箱可能响应较慢
 	dialTimeout := 60 * time.Second
 
 	// 方法1: 尝试直接使用 tls.Dial（QQ 邮箱可能更适合这种方式）
 	tlsConfig := &tls.Config{
-		ServerName:         e.config.Host,
+		ServerName:         e.cfg().Host,
 		InsecureSkipVerify: true, // QQ 邮箱可能需要跳过证书验证
 		MinVersion:         tls.VersionTLS10,
 		MaxVersion:         tls.VersionTLS13,
@@ -144,7 +160,7 @@ func (e *EmailMiddleware) sendMailWithTLS(addr string, auth smtp.Auth, from stri
 
 	if err != nil {
 		logx.Errorf("[EmailMiddleware] Direct TLS dial failed: error=%v, addr=%s, host=%s, timeout=%v, duration=%v",
-			err, addr, e.config.Host, dialTimeout, dialDuration)
+			err, addr, e.cfg().Host, dialTimeout, dialDuration)
 		logx.Errorf("[EmailMiddleware] TROUBLESHOOTING: This error might be caused by:")
 		logx.Errorf("[EmailMiddleware]   1. VPN interference - Try disabling VPN or adding SMTP exception")
 		logx.Errorf("[EmailMiddleware]   2. Firewall blocking port 465")
@@ -207,20 +223,20 @@ func (e *EmailMiddleware) sendMailWithTLS(addr string, auth smtp.Auth, from stri
 	defer conn.Close()
 
 	// 创建SMTP客户端
-	logx.Infof("[EmailMiddleware] Creating SMTP client for host=%s", e.config.Host)
-	client, err := smtp.NewClient(conn, e.config.Host)
+	logx.Infof("[EmailMiddleware] Creating SMTP client for host=%s", e.cfg().Host)
+	client, err := smtp.NewClient(conn, e.cfg().Host)
 	if err != nil {
-		logx.Errorf("[EmailMiddleware] Failed to create SMTP client: error=%v, host=%s", err, e.config.Host)
+		logx.Errorf("[EmailMiddleware] Failed to create SMTP client: error=%v, host=%s", err, e.cfg().Host)
 		return fmt.Errorf("create SMTP client failed: %w", err)
 	}
 	defer client.Quit()
 	logx.Infof("[EmailMiddleware] SMTP client created successfully")
 
 	// 认证
-	logx.Infof("[EmailMiddleware] Authenticating with SMTP server, username=%s", e.config.Username)
+	logx.Infof("[EmailMiddleware] Authenticating with SMTP server, username=%s", e.cfg().Username)
 	if err = client.Auth(auth); err != nil {
 		logx.Errorf("[EmailMiddleware] SMTP authentication failed: error=%v, username=%s, host=%s",
-			err, e.config.Username, e.config.Host)
+			err, e.cfg().Username, e.cfg().Host)
 		return fmt.Errorf("SMTP authentication failed: %w", err)
 	}
 	logx.Infof("[EmailMiddleware] SMTP authentication successful")
@@ -313,9 +329,9 @@ func (e *EmailMiddleware) sendMailWithSTARTTLS(addr string, auth smtp.Auth, serv
 	}
 
 	// 认证
-	logx.Infof("[EmailMiddleware] Authenticating, username=%s", e.config.Username)
+	logx.Infof("[EmailMiddleware] Authenticating, username=%s", e.cfg().Username)
 	if err := c.Auth(auth); err != nil {
-		logx.Errorf("[EmailMiddleware] Authentication failed: error=%v, username=%s", err, e.config.Username)
+		logx.Errorf("[EmailMiddleware] Authentication failed: error=%v, username=%s", err, e.cfg().Username)
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 	logx.Infof("[EmailMiddleware] Authentication successful")
@@ -362,7 +378,7 @@ func (e *EmailMiddleware) sendMailWithStandardLibrary(addr string, auth smtp.Aut
 
 	// 构建邮件头
 	headers := make(map[string]string)
-	headers["From"] = fmt.Sprintf("%s <%s>", e.config.From, e.config.Username)
+	headers["From"] = fmt.Sprintf("%s <%s>", e.cfg().From, e.cfg().Username)
 	headers["To"] = to[0]
 	headers["Subject"] = ""
 	headers["Date"] = time.Now().Format(time.RFC1123Z)
@@ -80,3 +80,21 @@ func GetValidPermCodes() map[int]struct{} {
 	}
 	return valid
 }
+
+// PermNames 权限码对应的中文名称（用于授权记录展示）
+var PermNames = map[int]string{
+	PermTaskRead: "查看任务", PermTaskCreate: "创建任务", PermTaskUpdate: "更新任务", PermTaskDelete: "删除任务", PermTaskApprove: "审批任务",
+	PermTaskNodeRead: "查看任务节点", PermTaskNodeUpdate: "更新任务节点", PermTaskNodeCreate: "创建任务节点", PermTaskNodeDelete: "删除任务节点",
+	PermHandoverRead: "查看交接", PermHandoverCreate: "发起交接", PermHandoverApprove: "审批交接", PermHandoverReject: "拒绝交接",
+	PermNotificationRead: "查看通知", PermNotificationCreate: "创建通知", PermNotificationDelete: "删除通知",
+	PermCompanyRead: "查看公司", PermCompanyCreate: "创建公司", PermCompanyUpdate: "更新公司", PermCompanyDelete: "删除公司",
+	PermDepartmentRead: "查看部门", PermDepartmentCreate: "创建部门", PermDepartmentUpdate: "更新部门", PermDepartmentDelete: "删除部门",
+	PermPositionRead: "查看职位", PermPositionCreate: "创建职位", PermPositionUpdate: "更新职位", PermPositionDelete: "删除职位",
+	PermRoleRead: "查看角色", PermRoleCreate: "创建角色", PermRoleUpdate: "更新角色", PermRoleDelete: "删除角色", PermRoleAssign: "分配角色/授权", PermRoleRevoke: "撤销角色/授权",
+	PermEmployeeRead: "查看员工", PermEmployeeCreate: "创建员工", PermEmployeeUpdate: "更新员工", PermEmployeeDelete: "删除员工", PermEmployeeLeave: "员工离职",
+}
+
+// GetPermName 获取权限码名称，未知权限码返回空字符串
+func GetPermName(code int) string {
+	return PermNames[code]
+}
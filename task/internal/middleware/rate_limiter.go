@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -36,6 +37,7 @@ func DefaultRateLimiterConfig() RateLimiterConfig {
 // RateLimiter 限流中间件
 type RateLimiter struct {
 	redisClient    *redis.Redis
+	mu             sync.RWMutex
 	config         RateLimiterConfig
 	securityLogger SecurityLogger
 }
@@ -53,6 +55,20 @@ func NewRateLimiter(redisClient *redis.Redis, config RateLimiterConfig) *RateLim
 	}
 }
 
+// UpdateConfig 运行时修改限流配置（系统配置热更新）
+func (r *RateLimiter) UpdateConfig(fn func(cfg *RateLimiterConfig)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.config)
+}
+
+// cfg 返回当前限流配置的副本
+func (r *RateLimiter) cfg() RateLimiterConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.config
+}
+
 // SetSecurityLogger 设置安全日志记录器
 func (r *RateLimiter) SetSecurityLogger(logger SecurityLogger) {
 	r.securityLogger = logger
@@ -65,6 +81,11 @@ type RateLimitResponse struct {
 	RetryAfter int    `json:"retryAfter"` // 重试等待秒数
 }
 
+// ClientIP 获取客户端真实IP，供需要记录安全日志的业务逻辑使用
+func ClientIP(r *http.Request) string {
+	return getClientIP(r)
+}
+
 // getClientIP 获取客户端真实IP
 func getClientIP(r *http.Request) string {
 	// 优先从X-Forwarded-For获取
@@ -174,8 +195,8 @@ func (r *RateLimiter) blockIP(ip string) {
 	}
 
 	key := fmt.Sprintf("rate:blocked:%s", ip)
-	r.redisClient.Setex(key, "1", int(r.config.BlockDuration.Seconds()))
-	logx.Infof("[RateLimiter] IP已被封禁: %s, 时长: %v", ip, r.config.BlockDuration)
+	r.redisClient.Setex(key, "1", int(r.cfg().BlockDuration.Seconds()))
+	logx.Infof("[RateLimiter] IP已被封禁: %s, 时长: %v", ip, r.cfg().BlockDuration)
 }
 
 // Handle 限流中间件处理函数
@@ -186,7 +207,7 @@ func (r *RateLimiter) Handle(next http.HandlerFunc) http.HandlerFunc {
 
 		// 检查IP是否被封禁
 		if r.isBlocked(ip) {
-			r.writeRateLimitResponse(w, int(r.config.BlockDuration.Seconds()), "IP已被临时封禁，请稍后重试")
+			r.writeRateLimitResponse(w, int(r.cfg().BlockDuration.Seconds()), "IP已被临时封禁，请稍后重试")
 			return
 		}
 
@@ -197,8 +218,8 @@ func (r *RateLimiter) Handle(next http.HandlerFunc) http.HandlerFunc {
 		if isLoginPath(path) {
 			// 登录接口使用IP限流
 			key = fmt.Sprintf("rate:login:%s", ip)
-			limit = r.config.LoginLimit
-			window = r.config.LoginWindow
+			limit = r.cfg().LoginLimit
+			window = r.cfg().LoginWindow
 		} else {
 			// 普通API使用IP限流（如果有用户ID则使用用户ID）
 			userID, ok := GetUserID(req.Context())
@@ -207,8 +228,8 @@ func (r *RateLimiter) Handle(next http.HandlerFunc) http.HandlerFunc {
 			} else {
 				key = fmt.Sprintf("rate:api:ip:%s", ip)
 			}
-			limit = r.config.APILimit
-			window = r.config.APIWindow
+			limit = r.cfg().APILimit
+			window = r.cfg().APIWindow
 		}
 
 		allowed, retryAfter, err := r.checkRateLimit(key, limit, window)
@@ -270,19 +291,19 @@ func (r *RateLimiter) LoginRateLimiter(next http.HandlerFunc) http.HandlerFunc {
 
 		// 检查IP是否被封禁
 		if r.isBlocked(ip) {
-			r.writeRateLimitResponse(w, int(r.config.BlockDuration.Seconds()), "IP已被临时封禁，请稍后重试")
+			r.writeRateLimitResponse(w, int(r.cfg().BlockDuration.Seconds()), "IP已被临时封禁，请稍后重试")
 			return
 		}
 
 		key := fmt.Sprintf("rate:login:%s", ip)
-		allowed, retryAfter, _ := r.checkRateLimit(key, r.config.LoginLimit, r.config.LoginWindow)
+		allowed, retryAfter, _ := r.checkRateLimit(key, r.cfg().LoginLimit, r.cfg().LoginWindow)
 
 		if !allowed {
 			logx.Infof("[RateLimiter] 登录限流触发 - IP: %s", ip)
 
 			// 记录安全日志
 			if r.securityLogger != nil {
-				r.securityLogger.LogRateLimitExceeded(ip, "", req.URL.Path, r.config.LoginLimit)
+				r.securityLogger.LogRateLimitExceeded(ip, "", req.URL.Path, r.cfg().LoginLimit)
 			}
 
 			r.writeRateLimitResponse(w, retryAfter, "登录请求过于频繁，请稍后重试")
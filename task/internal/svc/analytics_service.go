@@ -0,0 +1,333 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	adminModel "task_Project/model/admin"
+	"task_Project/model/company"
+	"task_Project/model/task"
+	"task_Project/model/user"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/syncx"
+)
+
+// 回填一次最多覆盖的天数
+const MaxStatsBackfillDays = 366
+
+// 紧急任务节点的优先级（与仪表盘原有口径一致）
+const statsCriticalPriority = 1
+
+// 活跃成员的统计窗口
+const statsActiveWindow = 30 * 24 * time.Hour
+
+var (
+	ErrStatsBackfillRunning = errors.New("stats backfill running")
+	ErrStatsRangeInvalid    = errors.New("stats range invalid")
+)
+
+// AnalyticsService 统计快照服务：按天把任务节点聚合到员工 / 部门 / 公司三个维度，
+// 仪表盘和平台统计直接读快照，定时任务刷新当天数据，历史数据可按日期回填
+type AnalyticsService struct {
+	snapshotModel task.StatsSnapshotModel
+	platformModel adminModel.PlatformStatsModel
+	companyModel  company.CompanyModel
+	employeeModel user.EmployeeModel
+	flight        syncx.SingleFlight
+	backfilling   atomic.Bool
+}
+
+// NewAnalyticsService 创建统计快照服务
+func NewAnalyticsService(snapshotModel task.StatsSnapshotModel, platformModel adminModel.PlatformStatsModel, companyModel company.CompanyModel, employeeModel user.EmployeeModel) *AnalyticsService {
+	return &AnalyticsService{
+		snapshotModel: snapshotModel,
+		platformModel: platformModel,
+		companyModel:  companyModel,
+		employeeModel: employeeModel,
+		flight:        syncx.NewSingleFlight(),
+	}
+}
+
+// StatsDay 返回日期所在天的零点
+func StatsDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// RefreshCompany 重新聚合公司在日期范围内每天的快照，节点和员工只查询一次
+func (s *AnalyticsService) RefreshCompany(ctx context.Context, companyID string, start, end time.Time) (int, error) {
+	nodes, err := s.snapshotModel.ListNodeFacts(ctx, companyID)
+	if err != nil {
+		return 0, err
+	}
+	employees, err := s.employeeModel.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for day := StatsDay(start); !day.After(StatsDay(end)); day = day.AddDate(0, 0, 1) {
+		rows := aggregateStatsDay(companyID, day, nodes, employees)
+		if err := s.snapshotModel.Upsert(ctx, rows); err != nil {
+			return written, err
+		}
+		written += len(rows)
+	}
+	return written, nil
+}
+
+// RefreshPlatform 重新统计日期范围内每天的平台数据
+func (s *AnalyticsService) RefreshPlatform(ctx context.Context, start, end time.Time) error {
+	for day := StatsDay(start); !day.After(StatsDay(end)); day = day.AddDate(0, 0, 1) {
+		stat, err := s.platformModel.Compute(ctx, day)
+		if err != nil {
+			return err
+		}
+		if err := s.platformModel.Upsert(ctx, stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshAll 刷新全部公司和平台在日期范围内的快照，单个公司失败不影响其它公司
+func (s *AnalyticsService) RefreshAll(ctx context.Context, start, end time.Time) (int, error) {
+	companies := 0
+	for page := 1; ; page++ {
+		list, _, err := s.companyModel.FindByPage(ctx, page, 200)
+		if err != nil {
+			return companies, err
+		}
+		for _, c := range list {
+			if _, err := s.RefreshCompany(ctx, c.Id, start, end); err != nil {
+				logx.WithContext(ctx).Errorf("[Analytics] 聚合公司统计失败: companyId=%s, err=%v", c.Id, err)
+				continue
+			}
+			companies++
+		}
+		if len(list) < 200 {
+			break
+		}
+	}
+	return companies, s.RefreshPlatform(ctx, start, end)
+}
+
+// Backfill 在后台回填日期范围内的快照，companyID 为空时回填全部公司和平台数据；同一时间只允许一个回填任务
+func (s *AnalyticsService) Backfill(companyID string, start, end time.Time) error {
+	start, end = StatsDay(start), StatsDay(end)
+	if end.Before(start) || end.After(StatsDay(time.Now())) || end.Sub(start) > MaxStatsBackfillDays*24*time.Hour {
+		return ErrStatsRangeInvalid
+	}
+	if !s.backfilling.CompareAndSwap(false, true) {
+		return ErrStatsBackfillRunning
+	}
+
+	go func() {
+		defer s.backfilling.Store(false)
+		ctx := context.Background()
+		begin := time.Now()
+		var err error
+		if companyID != "" {
+			_, err = s.RefreshCompany(ctx, companyID, start, end)
+		} else {
+			_, err = s.RefreshAll(ctx, start, end)
+		}
+		if err != nil {
+			logx.Errorf("[Analytics] 回填统计快照失败: companyId=%s, %s~%s, err=%v", companyID, start.Format("2006-01-02"), end.Format("2006-01-02"), err)
+			return
+		}
+		logx.Infof("[Analytics] 回填统计快照完成: companyId=%s, %s~%s, 耗时 %v", companyID, start.Format("2006-01-02"), end.Format("2006-01-02"), time.Since(begin))
+	}()
+	return nil
+}
+
+// Snapshot 读取维度当天的快照；公司当天还没有聚合过时先聚合一次（并发请求只触发一次），
+// 维度没有任何任务节点时返回空快照
+func (s *AnalyticsService) Snapshot(ctx context.Context, companyID, scopeType, scopeID string) (*task.StatsSnapshot, error) {
+	today := StatsDay(time.Now())
+	if err := s.ensureCompanyDay(ctx, companyID, today); err != nil {
+		return nil, err
+	}
+	snap, err := s.snapshotModel.FindOne(ctx, scopeType, scopeID, today)
+	if errors.Is(err, task.ErrNotFound) {
+		return &task.StatsSnapshot{SnapshotDate: today, ScopeType: scopeType, ScopeId: scopeID, CompanyId: companyID}, nil
+	}
+	return snap, err
+}
+
+// Trend 读取维度最近 days 天的快照，缺失的日期补零
+func (s *AnalyticsService) Trend(ctx context.Context, scopeType, scopeID string, days int) ([]*task.StatsSnapshot, error) {
+	end := StatsDay(time.Now())
+	start := end.AddDate(0, 0, -(days - 1))
+	rows, err := s.snapshotModel.FindRange(ctx, scopeType, scopeID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]*task.StatsSnapshot, len(rows))
+	for _, r := range rows {
+		byDate[r.SnapshotDate.Format("2006-01-02")] = r
+	}
+	trend := make([]*task.StatsSnapshot, 0, days)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if r, ok := byDate[day.Format("2006-01-02")]; ok {
+			trend = append(trend, r)
+			continue
+		}
+		trend = append(trend, &task.StatsSnapshot{SnapshotDate: day, ScopeType: scopeType, ScopeId: scopeID})
+	}
+	return trend, nil
+}
+
+// PlatformTrend 读取最近 days 天的平台统计，当天没有数据时先统计一次
+func (s *AnalyticsService) PlatformTrend(ctx context.Context, days int) ([]*adminModel.PlatformDailyStat, error) {
+	end := StatsDay(time.Now())
+	start := end.AddDate(0, 0, -(days - 1))
+	rows, err := s.platformModel.FindRange(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || !StatsDay(rows[len(rows)-1].SnapshotDate).Equal(end) {
+		if _, _, err := s.flight.DoEx("platform", func() (interface{}, error) {
+			return nil, s.RefreshPlatform(ctx, end, end)
+		}); err != nil {
+			return nil, err
+		}
+		if rows, err = s.platformModel.FindRange(ctx, start, end); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// TopCompanies 读取当天成员数最多的公司快照
+func (s *AnalyticsService) TopCompanies(ctx context.Context, limit int) ([]*task.StatsSnapshot, error) {
+	return s.snapshotModel.FindTopCompanies(ctx, StatsDay(time.Now()), limit)
+}
+
+func (s *AnalyticsService) ensureCompanyDay(ctx context.Context, companyID string, day time.Time) error {
+	_, err := s.snapshotModel.FindOne(ctx, task.StatsScopeCompany, companyID, day)
+	if err == nil || !errors.Is(err, task.ErrNotFound) {
+		return err
+	}
+	_, _, err = s.flight.DoEx("company:"+companyID, func() (interface{}, error) {
+		return s.RefreshCompany(ctx, companyID, day, day)
+	})
+	return err
+}
+
+// aggregateStatsDay 计算公司某一天各维度的快照；当天的逾期按当前时间判断，历史日期按当天结束时判断
+func aggregateStatsDay(companyID string, day time.Time, nodes []*task.StatsNodeFact, employees []*user.Employee) []*task.StatsSnapshot {
+	dayEnd := day.AddDate(0, 0, 1)
+	overdueAt := dayEnd
+	if now := time.Now(); now.Before(dayEnd) {
+		overdueAt = now
+	}
+
+	rows := make(map[string]*task.StatsSnapshot)
+	get := func(scopeType, scopeID string) *task.StatsSnapshot {
+		key := scopeType + ":" + scopeID
+		r, ok := rows[key]
+		if !ok {
+			r = &task.StatsSnapshot{SnapshotDate: day, ScopeType: scopeType, ScopeId: scopeID, CompanyId: companyID}
+			rows[key] = r
+		}
+		return r
+	}
+	company := get(task.StatsScopeCompany, companyID)
+	activeEmployees := make(map[string]bool)
+
+	for _, n := range nodes {
+		if !n.CreateTime.Before(dayEnd) {
+			continue
+		}
+		targets := []*task.StatsSnapshot{company}
+		if n.DepartmentId != "" {
+			targets = append(targets, get(task.StatsScopeDepartment, n.DepartmentId))
+		}
+		assignees := statsAssignees(n)
+		active := !n.UpdateTime.Before(dayEnd.Add(-statsActiveWindow)) && n.UpdateTime.Before(dayEnd)
+		for _, id := range assignees {
+			targets = append(targets, get(task.StatsScopeEmployee, id))
+			if active {
+				activeEmployees[id] = true
+			}
+		}
+
+		finished := n.FinishTime.Valid && n.FinishTime.Time.Before(dayEnd)
+		for _, r := range targets {
+			r.TotalCount++
+			if !n.CreateTime.Before(day) {
+				r.CreatedCount++
+			}
+			if finished {
+				r.TotalCompletedCount++
+				if !n.FinishTime.Time.Before(day) {
+					r.CompletedCount++
+				}
+				if hours := int64(n.FinishTime.Time.Sub(n.CreateTime).Hours()); hours > 0 {
+					r.CycleHoursTotal += hours
+				}
+				if !n.NodeDeadline.IsZero() {
+					r.DeadlineCompletedCount++
+					if !n.FinishTime.Time.After(n.NodeDeadline) {
+						r.OnTimeCount++
+					}
+				}
+				continue
+			}
+			r.OpenCount++
+			r.OpenEstimatedDays += n.EstimatedDays
+			if !n.NodeDeadline.IsZero() && n.NodeDeadline.Before(overdueAt) {
+				r.OverdueCount++
+			}
+			if n.NodePriority == statsCriticalPriority {
+				r.CriticalCount++
+			}
+		}
+	}
+
+	// 成员数：当天已加入且未离职的员工
+	for _, emp := range employees {
+		if !emp.CreateTime.Before(dayEnd) {
+			continue
+		}
+		if emp.Status == 0 && (!emp.LeaveDate.Valid || emp.LeaveDate.Time.Before(dayEnd)) {
+			continue
+		}
+		targets := []*task.StatsSnapshot{company}
+		if emp.DepartmentId.Valid && emp.DepartmentId.String != "" {
+			targets = append(targets, get(task.StatsScopeDepartment, emp.DepartmentId.String))
+		}
+		for _, r := range targets {
+			r.MemberCount++
+			if activeEmployees[emp.Id] {
+				r.ActiveMemberCount++
+			}
+		}
+	}
+
+	result := make([]*task.StatsSnapshot, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, r)
+	}
+	return result
+}
+
+// statsAssignees 节点的执行人和负责人（去重）
+func statsAssignees(n *task.StatsNodeFact) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, list := range []string{n.ExecutorId, n.LeaderId} {
+		for _, id := range strings.Split(list, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
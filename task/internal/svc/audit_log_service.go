@@ -0,0 +1,268 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"task_Project/model/role"
+	"task_Project/task/internal/middleware"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// OperationTypeAPI 请求审计的操作类型（写入 operation_log.operation_type）
+const OperationTypeAPI = "api"
+
+// 审计中需要脱敏的字段名关键字（小写匹配）
+// configvalue：系统配置可能包含加密存储的密钥，审计中一律不保存明文
+var auditSensitiveKeys = []string{"password", "token", "secret", "captcha", "verifycode", "apikey", "credential", "configvalue"}
+
+// 对比变更时忽略的字段
+var auditIgnoredFields = map[string]bool{"update_time": true, "updated_at": true}
+
+// AuditChange 单个字段的变更
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditDetail 写入 operation_desc 的变更详情
+type AuditDetail struct {
+	Before  map[string]interface{} `json:"before,omitempty"`
+	After   map[string]interface{} `json:"after,omitempty"`
+	Changes map[string]AuditChange `json:"changes,omitempty"`
+}
+
+// AuditLogService 请求审计服务，负责实体快照、变更对比、脱敏和落库
+type AuditLogService struct {
+	operationLogModel role.OperationLogModel
+	snapshots         map[string]func(ctx context.Context, id string) (interface{}, error)
+}
+
+// NewAuditLogService 创建请求审计服务
+func NewAuditLogService(operationLogModel role.OperationLogModel, svcCtx *ServiceContext) *AuditLogService {
+	s := &AuditLogService{operationLogModel: operationLogModel}
+	// 实体类型与路径中的模块名一致
+	s.snapshots = map[string]func(ctx context.Context, id string) (interface{}, error){
+		"task": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskModel.FindOne(ctx, id)
+		},
+		"tasknode": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskNodeModel.FindOne(ctx, id)
+		},
+		"employee": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.EmployeeModel.FindOne(ctx, id)
+		},
+		"company": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.CompanyModel.FindOne(ctx, id)
+		},
+		"department": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.DepartmentModel.FindOne(ctx, id)
+		},
+		"position": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.PositionModel.FindOne(ctx, id)
+		},
+		"role": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.RoleModel.FindOne(ctx, id)
+		},
+		"handover": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskHandoverModel.FindOne(ctx, id)
+		},
+		"checklist": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskChecklistModel.FindOne(ctx, id)
+		},
+		"label": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskLabelModel.FindOne(ctx, id)
+		},
+		"customfield": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.CustomFieldModel.FindOne(ctx, id)
+		},
+		"board": func(ctx context.Context, id string) (interface{}, error) {
+			return svcCtx.TaskBoardModel.FindOne(ctx, id)
+		},
+	}
+	return s
+}
+
+// Snapshot 读取实体当前状态，返回按数据库字段名展开的快照
+func (s *AuditLogService) Snapshot(ctx context.Context, entityType, entityID string) (interface{}, bool) {
+	if s == nil {
+		return nil, false
+	}
+	find, ok := s.snapshots[entityType]
+	if !ok || entityID == "" {
+		return nil, false
+	}
+	entity, err := find(ctx, entityID)
+	if err != nil || entity == nil || reflect.ValueOf(entity).IsNil() {
+		return nil, false
+	}
+	return maskAuditFields(snapshotFields(entity)), true
+}
+
+// Record 异步写入审计记录，不阻塞请求
+func (s *AuditLogService) Record(ctx context.Context, entry middleware.AuditEntry) {
+	if s == nil || s.operationLogModel == nil {
+		return
+	}
+
+	detail := AuditDetail{}
+	if before, ok := entry.Before.(map[string]interface{}); ok {
+		detail.Before = before
+	}
+	if after, ok := entry.After.(map[string]interface{}); ok {
+		detail.After = after
+	}
+	if detail.Before != nil && detail.After != nil {
+		detail.Changes = diffAuditFields(detail.Before, detail.After)
+	}
+	desc, _ := json.Marshal(detail)
+
+	status := int64(1)
+	if !entry.Success {
+		status = 0
+	}
+	log := &role.OperationLog{
+		Id:            utils.Common.GenId("oplog"),
+		UserId:        utils.Common.ToSqlNullString(entry.UserID),
+		EmployeeId:    utils.Common.ToSqlNullString(entry.EmployeeID),
+		CompanyId:     utils.Common.ToSqlNullString(entry.CompanyID),
+		OperationType: OperationTypeAPI,
+		OperationName: strings.TrimPrefix(entry.Module+"/"+entry.Action, "/"),
+		OperationDesc: sql.NullString{String: string(desc), Valid: true},
+		EntityType:    utils.Common.ToSqlNullString(entry.EntityType),
+		EntityId:      utils.Common.ToSqlNullString(entry.EntityID),
+		RequestMethod: utils.Common.ToSqlNullString(entry.Method),
+		RequestUrl:    utils.Common.ToSqlNullString(entry.Path),
+		RequestParams: utils.Common.ToSqlNullString(maskAuditJSON(entry.RequestBody)),
+		ResponseData:  utils.Common.ToSqlNullString(maskAuditJSON(entry.ResponseBody)),
+		IpAddress:     utils.Common.ToSqlNullString(entry.IP),
+		UserAgent:     utils.Common.ToSqlNullString(entry.UserAgent),
+		ExecutionTime: sql.NullInt64{Int64: entry.Duration.Milliseconds(), Valid: true},
+		Status:        status,
+		ErrorMessage:  utils.Common.ToSqlNullString(entry.ErrorMessage),
+		CreateTime:    time.Now(),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := s.operationLogModel.Insert(ctx, log); err != nil {
+			logx.Errorf("[AuditLog] 写入审计日志失败: %s %s, err=%v", entry.Method, entry.Path, err)
+		}
+	}()
+}
+
+// snapshotFields 将模型结构体按 db 标签展开为字段映射，sql.Null* 转为值或 nil
+func snapshotFields(entity interface{}) map[string]interface{} {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	out := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("db")
+		if name == "" || name == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		val := v.Field(i).Interface()
+		if valuer, ok := val.(driver.Valuer); ok {
+			val, _ = valuer.Value()
+		}
+		if tm, ok := val.(time.Time); ok {
+			val = utils.Common.FormatTime(tm)
+		}
+		out[name] = val
+	}
+	return out
+}
+
+// diffAuditFields 对比快照，返回发生变化的字段
+func diffAuditFields(before, after map[string]interface{}) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for key, to := range after {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		from := before[key]
+		if !reflect.DeepEqual(from, to) {
+			changes[key] = AuditChange{From: from, To: to}
+		}
+	}
+	for key, from := range before {
+		if _, ok := after[key]; !ok && !auditIgnoredFields[key] {
+			changes[key] = AuditChange{From: from, To: nil}
+		}
+	}
+	return changes
+}
+
+// auditBodyOmitted 正文无法按 JSON 解析（表单、不完整的正文等）时保存的占位内容，避免原文中的敏感字段落库
+const auditBodyOmitted = "[非 JSON 正文，未保存]"
+
+// maskAuditJSON 对 JSON 正文中的敏感字段脱敏后再截断，无法解析的正文只保存占位内容
+func maskAuditJSON(body string) string {
+	if body == "" {
+		return body
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return auditBodyOmitted
+	}
+	masked, err := json.Marshal(maskAuditValue(data))
+	if err != nil {
+		return auditBodyOmitted
+	}
+	if len(masked) > middleware.AuditBodyLimit {
+		masked = masked[:middleware.AuditBodyLimit]
+	}
+	return string(masked)
+}
+
+// maskAuditValue 递归脱敏
+func maskAuditValue(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return maskAuditFields(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = maskAuditValue(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// maskAuditFields 对字段映射中的敏感字段脱敏
+func maskAuditFields(fields map[string]interface{}) map[string]interface{} {
+	for key, val := range fields {
+		if !isAuditSensitiveKey(key) {
+			fields[key] = maskAuditValue(val)
+			continue
+		}
+		if val == nil || val == "" {
+			continue
+		}
+		// 敏感字段整体隐藏，不保留任何片段
+		fields[key] = "******"
+	}
+	return fields
+}
+
+func isAuditSensitiveKey(key string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	for _, k := range auditSensitiveKeys {
+		if strings.Contains(lower, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package svc
+
+import "strings"
+
+// 看板卡片排序值：由 0-9a-z 组成、按字典序比较的字符串（LexoRank 风格），
+// 移动卡片时只需为它生成一个介于前后两张卡片之间的新值，不影响其他卡片
+const (
+	rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	rankBase   = len(rankDigits)
+
+	// MaxBoardRankLength 排序值超过该长度时重新分配整列的排序值
+	MaxBoardRankLength = 32
+)
+
+// BoardRankBetween 生成介于 prev 和 next 之间的排序值，prev 为空表示列首，next 为空表示列尾。
+// 生成的值不会以 0 结尾，因此任意两个值之间总能再插入新值
+func BoardRankBetween(prev, next string) string {
+	if next != "" && prev >= next {
+		next = ""
+	}
+	var sb strings.Builder
+	for i := 0; ; i++ {
+		p := 0
+		if i < len(prev) {
+			p = strings.IndexByte(rankDigits, prev[i])
+		}
+		n := rankBase
+		if next != "" && i < len(next) {
+			n = strings.IndexByte(rankDigits, next[i])
+		}
+		switch {
+		case p == n:
+			sb.WriteByte(rankDigits[p])
+		case n-p > 1:
+			sb.WriteByte(rankDigits[(p+n)/2])
+			return sb.String()
+		default:
+			// 当前位相邻，保留 prev 的这一位，之后只需大于 prev 的剩余部分
+			sb.WriteByte(rankDigits[p])
+			next = ""
+		}
+	}
+}
+
+// BoardRankSequence 生成 n 个等距递增的排序值，用于初始化或重新分配整列的排序值
+func BoardRankSequence(n int) []string {
+	width, space := 1, rankBase
+	for space <= n*4 {
+		width++
+		space *= rankBase
+	}
+	step := space / (n + 1)
+	ranks := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		v := step * i
+		buf := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			buf[j] = rankDigits[v%rankBase]
+			v /= rankBase
+		}
+		ranks = append(ranks, string(buf))
+	}
+	return ranks
+}
@@ -0,0 +1,354 @@
+package svc
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+)
+
+// 负载等级
+const (
+	CapacityLevelIdle   = "idle"   // 空闲：利用率低于 30%
+	CapacityLevelNormal = "normal" // 正常
+	CapacityLevelHigh   = "high"   // 偏高：达到超负荷阈值的 80%
+	CapacityLevelOver   = "over"   // 超负荷：超过阈值
+	CapacityLevelLeave  = "leave"  // 请假/外出，没有可用工时
+)
+
+const (
+	maxCapacityUtilization  = 999.0 // 利用率上限（没有可用工时但有工作量时）
+	capacityWorkdaysPerWeek = 5     // 每周工作日数
+)
+
+// CapacityWeek 员工某一周的产能与负载
+type CapacityWeek struct {
+	WeekStart      time.Time
+	AvailableHours float64 // 可用工时（扣除请假/外出，当前周只计今天及以后）
+	AssignedHours  float64 // 分摊到该周的未完成节点工作量
+	Utilization    float64 // 利用率（百分比）
+	Level          string
+}
+
+// EmployeeLoad 员工在一段时间内的产能与负载
+type EmployeeLoad struct {
+	Employee    *user.Employee
+	WeeklyHours float64 // 每周可用工时配置
+	OnLeave     bool    // 员工状态为请假
+	OpenNodes   int     // 参与的未完成节点数
+	Weeks       []CapacityWeek
+}
+
+// CapacityFit 员工在某个节点时间窗口内承接该节点后的负载
+type CapacityFit struct {
+	EmployeeID     string
+	AvailableHours float64 // 窗口内可用工时
+	AssignedHours  float64 // 窗口内已有的工作量（不含该节点）
+	NodeHours      float64 // 该节点分摊给员工的工作量
+	Utilization    float64 // 承接后的利用率（百分比）
+	OnLeave        bool
+	Over           bool // 承接后超过超负荷阈值
+}
+
+// employeeDays 员工按天的可用工时和已分配工作量
+type employeeDays struct {
+	employee  *user.Employee
+	weekly    float64
+	openNodes int
+	available map[time.Time]float64
+	assigned  map[time.Time]float64
+}
+
+// CapacityService 员工产能与工作负载：每周可用工时、请假/外出、未完成节点的预计工作量
+type CapacityService struct {
+	employeeModel       user.EmployeeModel
+	capacityModel       user.EmployeeCapacityModel
+	outOfOfficeModel    user.OutOfOfficeModel
+	taskNodeModel       task.TaskNodeModel
+	systemConfigService *SystemConfigService
+}
+
+// NewCapacityService 创建产能服务
+func NewCapacityService(employeeModel user.EmployeeModel, capacityModel user.EmployeeCapacityModel, outOfOfficeModel user.OutOfOfficeModel,
+	taskNodeModel task.TaskNodeModel, systemConfigService *SystemConfigService) *CapacityService {
+	return &CapacityService{
+		employeeModel:       employeeModel,
+		capacityModel:       capacityModel,
+		outOfOfficeModel:    outOfOfficeModel,
+		taskNodeModel:       taskNodeModel,
+		systemConfigService: systemConfigService,
+	}
+}
+
+// DefaultWeeklyHours 未单独配置的员工每周可用工时
+func (s *CapacityService) DefaultWeeklyHours() float64 {
+	return float64(s.systemConfigService.GetInt(SettingCapacityWeeklyHours, 40))
+}
+
+// OverloadPercent 超负荷阈值（利用率百分比）
+func (s *CapacityService) OverloadPercent() float64 {
+	return float64(s.systemConfigService.GetInt(SettingCapacityOverload, 100))
+}
+
+// NodeEffortHours 节点剩余的预计工作量（小时）：预计天数折算工时后按未完成进度计算
+func NodeEffortHours(estimatedDays, progress int64) float64 {
+	if progress >= 100 || estimatedDays <= 0 {
+		return 0
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	return float64(estimatedDays*hoursPerEstimatedDay) * float64(100-progress) / 100
+}
+
+// splitExecutors 拆分逗号分隔的执行人ID
+func splitExecutors(ids string) []string {
+	var list []string
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+// capacityDay 统一换算为本地时区零点，用作按天统计的键
+func capacityDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// isWorkday 周一到周五为工作日
+func isWorkday(day time.Time) bool {
+	wd := day.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// nodeWindow 节点工作量的分摊区间：从今天或节点开始日（取较晚者）到截止日；已逾期的节点全部计入今天
+func nodeWindow(start, deadline, today time.Time) (time.Time, time.Time) {
+	from := today
+	if !start.IsZero() && capacityDay(start).After(from) {
+		from = capacityDay(start)
+	}
+	if deadline.IsZero() {
+		return from, from.AddDate(0, 0, 6)
+	}
+	to := capacityDay(deadline)
+	if to.Before(today) {
+		return today, today
+	}
+	if to.Before(from) {
+		to = from
+	}
+	return from, to
+}
+
+// spreadHours 将工作量平均分摊到区间内的工作日（区间内没有工作日时计入第一天），只累加 [rangeFrom, rangeTo] 内的部分
+func spreadHours(target map[time.Time]float64, hours float64, from, to, rangeFrom, rangeTo time.Time) {
+	if hours <= 0 {
+		return
+	}
+	var days []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if isWorkday(d) {
+			days = append(days, d)
+		}
+	}
+	if len(days) == 0 {
+		days = []time.Time{from}
+	}
+	perDay := hours / float64(len(days))
+	for _, d := range days {
+		if d.Before(rangeFrom) || d.After(rangeTo) {
+			continue
+		}
+		target[d] += perDay
+	}
+}
+
+// loadDays 计算员工在 [from, to] 内按天的可用工时和已分配工作量；excludeNodeID 不为空时不计该节点
+func (s *CapacityService) loadDays(ctx context.Context, companyID string, employees []*user.Employee, from, to time.Time, excludeNodeID string) (map[string]*employeeDays, error) {
+	result := make(map[string]*employeeDays, len(employees))
+	ids := make([]string, 0, len(employees))
+	for _, e := range employees {
+		ids = append(ids, e.Id)
+		result[e.Id] = &employeeDays{
+			employee:  e,
+			weekly:    s.DefaultWeeklyHours(),
+			available: make(map[time.Time]float64),
+			assigned:  make(map[time.Time]float64),
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	capacities, err := s.capacityModel.FindByEmployeeIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range capacities {
+		if d, ok := result[c.EmployeeId]; ok {
+			d.weekly = c.WeeklyHours
+		}
+	}
+
+	// 请假/外出的日期没有可用工时
+	absent := make(map[string]map[time.Time]bool)
+	leaves, err := s.outOfOfficeModel.FindOverlappingByCompany(ctx, companyID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range leaves {
+		if _, ok := result[l.EmployeeId]; !ok {
+			continue
+		}
+		if absent[l.EmployeeId] == nil {
+			absent[l.EmployeeId] = make(map[time.Time]bool)
+		}
+		for d := capacityDay(l.StartTime); d.Before(l.EndTime) && !d.After(to); d = d.AddDate(0, 0, 1) {
+			absent[l.EmployeeId][d] = true
+		}
+	}
+	for id, d := range result {
+		if d.employee.Status == 2 {
+			continue
+		}
+		daily := d.weekly / capacityWorkdaysPerWeek
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if isWorkday(day) && !absent[id][day] {
+				d.available[day] = daily
+			}
+		}
+	}
+
+	// 未完成节点的剩余工作量按执行人平摊，再分摊到节点区间内的工作日
+	nodes, err := s.taskNodeModel.FindOpenWorkloadByCompany(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	today := capacityDay(time.Now())
+	for _, n := range nodes {
+		if n.TaskNodeId == excludeNodeID {
+			continue
+		}
+		executors := splitExecutors(n.ExecutorId)
+		share := NodeEffortHours(n.EstimatedDays, n.Progress) / float64(len(executors))
+		nodeFrom, nodeTo := nodeWindow(n.NodeStartTime, n.NodeDeadline, today)
+		for _, id := range executors {
+			d, ok := result[id]
+			if !ok {
+				continue
+			}
+			d.openNodes++
+			spreadHours(d.assigned, share, nodeFrom, nodeTo, from, to)
+		}
+	}
+	return result, nil
+}
+
+// level 根据利用率计算负载等级
+func (s *CapacityService) level(available, assigned float64) (float64, string) {
+	if available <= 0 {
+		if assigned > 0 {
+			return maxCapacityUtilization, CapacityLevelOver
+		}
+		return 0, CapacityLevelLeave
+	}
+	utilization := math.Min(math.Round(assigned/available*1000)/10, maxCapacityUtilization)
+	overload := s.OverloadPercent()
+	switch {
+	case utilization > overload:
+		return utilization, CapacityLevelOver
+	case utilization >= overload*0.8:
+		return utilization, CapacityLevelHigh
+	case utilization < 30:
+		return utilization, CapacityLevelIdle
+	}
+	return utilization, CapacityLevelNormal
+}
+
+// Weekly 计算员工从本周开始 weeks 周的产能与负载，当前周只计算今天及以后
+func (s *CapacityService) Weekly(ctx context.Context, companyID string, employees []*user.Employee, weeks int) ([]*EmployeeLoad, error) {
+	today := capacityDay(time.Now())
+	firstWeek := WeekStart(today)
+	end := firstWeek.AddDate(0, 0, weeks*7-1)
+	days, err := s.loadDays(ctx, companyID, employees, today, end, "")
+	if err != nil {
+		return nil, err
+	}
+
+	loads := make([]*EmployeeLoad, 0, len(employees))
+	for _, e := range employees {
+		d := days[e.Id]
+		load := &EmployeeLoad{
+			Employee:    e,
+			WeeklyHours: d.weekly,
+			OnLeave:     e.Status == 2,
+			OpenNodes:   d.openNodes,
+			Weeks:       make([]CapacityWeek, 0, weeks),
+		}
+		for w := 0; w < weeks; w++ {
+			week := CapacityWeek{WeekStart: firstWeek.AddDate(0, 0, w*7)}
+			for i := 0; i < 7; i++ {
+				day := week.WeekStart.AddDate(0, 0, i)
+				week.AvailableHours += d.available[day]
+				week.AssignedHours += d.assigned[day]
+			}
+			week.AvailableHours = roundHours(week.AvailableHours)
+			week.AssignedHours = roundHours(week.AssignedHours)
+			week.Utilization, week.Level = s.level(week.AvailableHours, week.AssignedHours)
+			load.Weeks = append(load.Weeks, week)
+		}
+		loads = append(loads, load)
+	}
+	return loads, nil
+}
+
+// Evaluate 计算员工承接节点后在节点时间窗口内的负载。
+// shared 为 true 时节点工作量由 employeeIDs 平摊（已指定的多个执行人），否则每个员工承担全部工作量（派发候选人）。
+func (s *CapacityService) Evaluate(ctx context.Context, companyID string, node *task.NodeWorkload, employeeIDs []string, shared bool) (map[string]*CapacityFit, error) {
+	employees := make([]*user.Employee, 0, len(employeeIDs))
+	for _, id := range employeeIDs {
+		emp, err := s.employeeModel.FindOne(ctx, id)
+		if err != nil {
+			continue
+		}
+		employees = append(employees, emp)
+	}
+	today := capacityDay(time.Now())
+	from, to := nodeWindow(node.NodeStartTime, node.NodeDeadline, today)
+	days, err := s.loadDays(ctx, companyID, employees, from, to, node.TaskNodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	effort := NodeEffortHours(node.EstimatedDays, node.Progress)
+	if shared && len(employees) > 0 {
+		effort = effort / float64(len(employees))
+	}
+	overload := s.OverloadPercent()
+	fits := make(map[string]*CapacityFit, len(employees))
+	for _, e := range employees {
+		d := days[e.Id]
+		fit := &CapacityFit{EmployeeID: e.Id, NodeHours: roundHours(effort), OnLeave: e.Status == 2}
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			fit.AvailableHours += d.available[day]
+			fit.AssignedHours += d.assigned[day]
+		}
+		fit.AvailableHours = roundHours(fit.AvailableHours)
+		fit.AssignedHours = roundHours(fit.AssignedHours)
+		fit.Utilization, _ = s.level(fit.AvailableHours, fit.AssignedHours+fit.NodeHours)
+		fit.Over = fit.OnLeave || fit.Utilization > overload
+		fits[e.Id] = fit
+	}
+	return fits, nil
+}
+
+// roundHours 工时保留一位小数
+func roundHours(h float64) float64 {
+	return math.Round(h*10) / 10
+}
@@ -0,0 +1,29 @@
+package svc
+
+import (
+	"context"
+
+	"task_Project/model/user"
+)
+
+// IsCompanyAdmin 员工是否为公司管理人员：公司创始人、人事部门（部门编码 HR）成员或管理岗位员工。
+// 看板、标签、自定义字段、报表、导入导出、搜索、离职交接和上传策略等公司级管理功能使用同一判断
+func (s *ServiceContext) IsCompanyAdmin(ctx context.Context, employee *user.Employee) bool {
+	company, _ := s.CompanyModel.FindOne(ctx, employee.CompanyId)
+	if company != nil && company.Owner == employee.UserId {
+		return true
+	}
+	if employee.DepartmentId.Valid {
+		dept, _ := s.DepartmentModel.FindOne(ctx, employee.DepartmentId.String)
+		if dept != nil && dept.DepartmentCode.Valid && dept.DepartmentCode.String == "HR" {
+			return true
+		}
+	}
+	if employee.PositionId.Valid {
+		pos, _ := s.PositionModel.FindOne(ctx, employee.PositionId.String)
+		if pos != nil && pos.IsManagement == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"task_Project/model/company"
+	"task_Project/model/user"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// ActiveCompanyKeyPrefix 用户最近切换到的公司（Redis），登录时优先进入该公司
+const ActiveCompanyKeyPrefix = "auth:active_company:"
+
+// 记住当前公司的有效期（30天）
+const activeCompanyExpire = 30 * 86400
+
+var (
+	ErrMembershipNotFound = errors.New("membership not found")
+	ErrMembershipLeft     = errors.New("membership left")
+	ErrCompanyDisabled    = errors.New("company disabled")
+)
+
+// CompanyMembership 用户在某家公司的成员身份
+type CompanyMembership struct {
+	Employee *user.Employee
+	Company  *company.Company
+}
+
+// Usable 成员身份是否可用于登录（员工在职且公司未禁用）
+func (m *CompanyMembership) Usable() bool {
+	return m.Employee.Status != 0 && (m.Company == nil || m.Company.Status != 0)
+}
+
+// CompanyMembershipService 多公司成员身份服务：查询成员身份、选择当前公司
+type CompanyMembershipService struct {
+	employeeModel user.EmployeeModel
+	companyModel  company.CompanyModel
+	redisClient   *redis.Redis
+}
+
+// NewCompanyMembershipService 创建成员身份服务
+func NewCompanyMembershipService(employeeModel user.EmployeeModel, companyModel company.CompanyModel, redisClient *redis.Redis) *CompanyMembershipService {
+	return &CompanyMembershipService{
+		employeeModel: employeeModel,
+		companyModel:  companyModel,
+		redisClient:   redisClient,
+	}
+}
+
+// List 查询用户的全部成员身份，按加入时间倒序
+func (s *CompanyMembershipService) List(ctx context.Context, userID string) ([]*CompanyMembership, error) {
+	employees, err := s.employeeModel.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]*CompanyMembership, 0, len(employees))
+	for _, emp := range employees {
+		m := &CompanyMembership{Employee: emp}
+		if companyInfo, err := s.companyModel.FindOne(ctx, emp.CompanyId); err == nil {
+			m.Company = companyInfo
+		} else if !errors.Is(err, company.ErrNotFound) {
+			logx.Errorf("[Membership] 查询公司失败: companyId=%s, err=%v", emp.CompanyId, err)
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, nil
+}
+
+// ResolveActive 选择登录时进入的公司：优先最近切换的公司，否则为最近加入的可用公司；
+// 在职公司均被禁用时仍返回在职身份（由 JWT 中间件提示公司已禁用），全部离职时返回 nil
+func (s *CompanyMembershipService) ResolveActive(userID string, memberships []*CompanyMembership) *CompanyMembership {
+	if preferred := s.preferredCompany(userID); preferred != "" {
+		for _, m := range memberships {
+			if m.Employee.CompanyId == preferred && m.Usable() {
+				return m
+			}
+		}
+	}
+	for _, m := range memberships {
+		if m.Usable() {
+			return m
+		}
+	}
+	for _, m := range memberships {
+		if m.Employee.Status != 0 {
+			return m
+		}
+	}
+	return nil
+}
+
+// CurrentEmployee 当前请求用户在令牌所属公司的员工记录，不在该公司任职时返回 user.ErrNotFound；
+// 令牌中没有公司（加入公司后尚未重新登录）时返回最近加入且在职的员工记录
+func (s *CompanyMembershipService) CurrentEmployee(ctx context.Context, userID string) (*user.Employee, error) {
+	if companyID, ok := utils.Common.GetCurrentCompanyID(ctx); ok && companyID != "" {
+		return s.employeeModel.FindByUserIDAndCompanyID(ctx, userID, companyID)
+	}
+	return s.employeeModel.FindByUserID(ctx, userID)
+}
+
+// Switch 校验用户在目标公司的成员身份并记住为当前公司
+func (s *CompanyMembershipService) Switch(ctx context.Context, userID, companyID string) (*CompanyMembership, error) {
+	emp, err := s.employeeModel.FindByUserIDAndCompanyID(ctx, userID, companyID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, err
+	}
+	m := &CompanyMembership{Employee: emp}
+	if companyInfo, err := s.companyModel.FindOne(ctx, companyID); err == nil {
+		m.Company = companyInfo
+	} else if errors.Is(err, company.ErrNotFound) {
+		return nil, ErrMembershipNotFound
+	} else {
+		return nil, err
+	}
+	if emp.Status == 0 {
+		return nil, ErrMembershipLeft
+	}
+	if m.Company.Status == 0 {
+		return nil, ErrCompanyDisabled
+	}
+	s.Remember(userID, companyID)
+	return m, nil
+}
+
+// Remember 记住用户当前所在公司，下次登录优先进入
+func (s *CompanyMembershipService) Remember(userID, companyID string) {
+	if s.redisClient == nil || userID == "" || companyID == "" {
+		return
+	}
+	if err := s.redisClient.Setex(fmt.Sprintf("%s%s", ActiveCompanyKeyPrefix, userID), companyID, activeCompanyExpire); err != nil {
+		logx.Errorf("[Membership] 记录当前公司失败: userId=%s, err=%v", userID, err)
+	}
+}
+
+func (s *CompanyMembershipService) preferredCompany(userID string) string {
+	if s.redisClient == nil {
+		return ""
+	}
+	companyID, err := s.redisClient.Get(fmt.Sprintf("%s%s", ActiveCompanyKeyPrefix, userID))
+	if err != nil {
+		return ""
+	}
+	return companyID
+}
@@ -0,0 +1,299 @@
+package svc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"task_Project/model/company"
+	"task_Project/model/role"
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/utils"
+)
+
+// 引用链接地址，前端按路由打开员工、任务和节点
+const (
+	contentEmployeeHref   = "/employee/detail?employeeId="
+	contentDepartmentHref = "/department/detail?departmentId="
+	contentTaskHref       = "/task/detail?taskId="
+	contentNodeHref       = "/task/detail?taskId=%s&taskNodeId=%s"
+)
+
+// maxGroupMentionMembers 一次@部门或@角色最多通知的员工数
+const maxGroupMentionMembers = 500
+
+// RenderedContent 渲染后的用户内容
+type RenderedContent struct {
+	HTML       string   // 净化后的 HTML
+	Text       string   // 纯文本，用于搜索和通知
+	MentionIDs []string // @的员工ID，包括显式传入的和正文中解析出的
+	GroupIDs   []string // @的部门和角色ID
+	MemberIDs  []string // 通过@部门、@角色提及的在职员工ID，不含已在 MentionIDs 中的
+	TaskIDs    []string // 正文中引用的任务ID
+	NodeIDs    []string // 正文中引用的节点ID
+}
+
+// ContentService 用户内容处理：Markdown 渲染、HTML 净化、@提及和任务、节点引用解析。
+// 引用只解析为同一公司的员工、部门、角色、任务和节点，其他公司的ID保持原文
+type ContentService struct {
+	employeeModel   user.EmployeeModel
+	departmentModel company.DepartmentModel
+	roleModel       role.RoleModel
+	taskModel       task.TaskModel
+	taskNodeModel   task.TaskNodeModel
+	detailModel     task.TaskDetailContentModel
+}
+
+func NewContentService(employeeModel user.EmployeeModel, departmentModel company.DepartmentModel, roleModel role.RoleModel,
+	taskModel task.TaskModel, taskNodeModel task.TaskNodeModel, detailModel task.TaskDetailContentModel) *ContentService {
+	return &ContentService{
+		employeeModel:   employeeModel,
+		departmentModel: departmentModel,
+		roleModel:       roleModel,
+		taskModel:       taskModel,
+		taskNodeModel:   taskNodeModel,
+		detailModel:     detailModel,
+	}
+}
+
+// Render 渲染用户内容，format 为 utils.ContentFormatMarkdown 或 utils.ContentFormatHTML；
+// mentionIDs 为客户端选择的@员工，正文中的 @姓名 按这些员工识别
+func (s *ContentService) Render(ctx context.Context, companyID, format, source string, mentionIDs []string) *RenderedContent {
+	return s.render(ctx, companyID, format, source, mentionIDs, true)
+}
+
+// render expandGroups 为 true 时把@部门、@角色展开为需要通知的员工（MemberIDs）
+func (s *ContentService) render(ctx context.Context, companyID, format, source string, mentionIDs []string, expandGroups bool) *RenderedContent {
+	resolver := &contentResolver{ctx: ctx, svc: s, companyID: companyID, cache: make(map[string]*utils.ContentRef)}
+
+	out := &RenderedContent{}
+	seen := make(map[string]bool)
+	mentionNames := make(map[string]string)
+	for _, id := range mentionIDs {
+		ref := resolver.resolveEmployee(id)
+		resolver.cache["@"+id] = ref
+		if ref == nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out.MentionIDs = append(out.MentionIDs, id)
+		mentionNames[ref.Label] = id
+	}
+
+	rich := utils.RenderRichText(format, source, mentionNames, resolver)
+	out.HTML = rich.HTML
+	out.Text = rich.Text
+	for _, ref := range rich.Refs {
+		switch ref.Kind {
+		case utils.ContentRefMention:
+			if !seen[ref.ID] {
+				seen[ref.ID] = true
+				out.MentionIDs = append(out.MentionIDs, ref.ID)
+			}
+		case utils.ContentRefDepartment, utils.ContentRefRole:
+			out.GroupIDs = append(out.GroupIDs, ref.ID)
+		case utils.ContentRefTask:
+			out.TaskIDs = append(out.TaskIDs, ref.ID)
+		case utils.ContentRefNode:
+			out.NodeIDs = append(out.NodeIDs, ref.ID)
+		}
+	}
+	if expandGroups {
+		for _, ref := range rich.Refs {
+			if ref.Kind != utils.ContentRefDepartment && ref.Kind != utils.ContentRefRole {
+				continue
+			}
+			for _, id := range resolver.groupMembers(ref) {
+				if len(out.MemberIDs) >= maxGroupMentionMembers {
+					break
+				}
+				if !seen[id] {
+					seen[id] = true
+					out.MemberIDs = append(out.MemberIDs, id)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// RenderInput 渲染评论等用户输入：提交了富文本编辑器的 HTML 时净化 HTML，否则把 content 作为 Markdown 渲染
+func (s *ContentService) RenderInput(ctx context.Context, companyID, content, contentHTML string, mentionIDs []string) *RenderedContent {
+	if strings.TrimSpace(contentHTML) != "" {
+		return s.Render(ctx, companyID, utils.ContentFormatHTML, contentHTML, mentionIDs)
+	}
+	return s.Render(ctx, companyID, utils.ContentFormatMarkdown, content, mentionIDs)
+}
+
+// IsEmpty 渲染结果是否没有可显示的内容
+func (c *RenderedContent) IsEmpty() bool {
+	return c.Text == "" && !strings.Contains(c.HTML, "<img")
+}
+
+// SaveDetail 渲染任务详情（taskNodeID 为空）或节点详情并保存渲染结果，任务、节点写入详情后调用。
+// 详情不需要通知，@部门、@角色只渲染为文本，不展开成员
+func (s *ContentService) SaveDetail(ctx context.Context, companyID, taskID, taskNodeID, source string) (*task.TaskDetailContent, error) {
+	content := &task.TaskDetailContent{TaskId: taskID, TaskNodeId: taskNodeID, SourceHash: detailSourceHash(source)}
+	if strings.TrimSpace(source) != "" {
+		rendered := s.render(ctx, companyID, utils.ContentFormatMarkdown, source, nil, false)
+		content.ContentHtml, content.ContentText = rendered.HTML, rendered.Text
+	}
+	return content, s.detailModel.Save(ctx, content)
+}
+
+// SaveDetailQuietly 同 SaveDetail，保存失败只记录日志：读取详情时会发现缺失的渲染结果并重新渲染
+func (s *ContentService) SaveDetailQuietly(ctx context.Context, companyID, taskID, taskNodeID, source string) {
+	if _, err := s.SaveDetail(ctx, companyID, taskID, taskNodeID, source); err != nil {
+		logx.WithContext(ctx).Errorf("保存详情渲染结果失败: taskId=%s, taskNodeId=%s, err=%v", taskID, taskNodeID, err)
+	}
+}
+
+// DetailHTMLs 任务详情及其节点详情的 HTML，sources 为节点ID（任务详情为空）-> Markdown 原文。
+// 一次读取写入时保存的渲染结果；没有记录或原文已变化（导入、模板等未经过渲染的写入）时重新渲染并保存
+func (s *ContentService) DetailHTMLs(ctx context.Context, companyID, taskID string, sources map[string]string) map[string]string {
+	saved := make(map[string]*task.TaskDetailContent)
+	if rows, err := s.detailModel.FindByTask(ctx, taskID); err != nil {
+		logx.WithContext(ctx).Errorf("查询详情渲染结果失败: taskId=%s, err=%v", taskID, err)
+	} else {
+		for _, row := range rows {
+			saved[row.TaskNodeId] = row
+		}
+	}
+	result := make(map[string]string, len(sources))
+	for nodeID, source := range sources {
+		result[nodeID] = s.detailHTML(ctx, companyID, taskID, nodeID, source, saved[nodeID])
+	}
+	return result
+}
+
+// DetailHTML 单个任务详情或节点详情的 HTML，规则同 DetailHTMLs
+func (s *ContentService) DetailHTML(ctx context.Context, companyID, taskID, taskNodeID, source string) string {
+	saved, err := s.detailModel.FindOne(ctx, taskID, taskNodeID)
+	if err != nil && !errors.Is(err, task.ErrNotFound) {
+		logx.WithContext(ctx).Errorf("查询详情渲染结果失败: taskId=%s, taskNodeId=%s, err=%v", taskID, taskNodeID, err)
+	}
+	return s.detailHTML(ctx, companyID, taskID, taskNodeID, source, saved)
+}
+
+func (s *ContentService) detailHTML(ctx context.Context, companyID, taskID, taskNodeID, source string, saved *task.TaskDetailContent) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+	if saved != nil && saved.SourceHash == detailSourceHash(source) {
+		return saved.ContentHtml
+	}
+	content, err := s.SaveDetail(ctx, companyID, taskID, taskNodeID, source)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("保存详情渲染结果失败: taskId=%s, taskNodeId=%s, err=%v", taskID, taskNodeID, err)
+	}
+	return content.ContentHtml
+}
+
+// detailSourceHash 详情原文的 SHA-256，用于判断保存的渲染结果是否对应当前原文
+func detailSourceHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentResolver 按ID前缀查询员工、部门、角色、任务和节点，同一次渲染内缓存查询结果。
+// 显式传入的@员工预先放入缓存，@姓名 通过缓存解析，不受ID前缀限制
+type contentResolver struct {
+	ctx       context.Context
+	svc       *ContentService
+	companyID string
+	cache     map[string]*utils.ContentRef
+}
+
+func (r *contentResolver) ResolveRef(sigil byte, token string) *utils.ContentRef {
+	key := string(sigil) + token
+	if ref, ok := r.cache[key]; ok {
+		return ref
+	}
+	var ref *utils.ContentRef
+	switch {
+	case sigil == '@' && strings.HasPrefix(token, "emp_"):
+		ref = r.resolveEmployee(token)
+	case sigil == '@' && strings.HasPrefix(token, "dept_"):
+		ref = r.resolveDepartment(token)
+	case sigil == '@' && strings.HasPrefix(token, "role_"):
+		ref = r.resolveRole(token)
+	case sigil == '#' && strings.HasPrefix(token, "node_"):
+		ref = r.resolveNode(token)
+	case sigil == '#' && strings.HasPrefix(token, "task"):
+		ref = r.resolveTask(token)
+	}
+	r.cache[key] = ref
+	return ref
+}
+
+func (r *contentResolver) resolveEmployee(employeeID string) *utils.ContentRef {
+	emp, err := r.svc.employeeModel.FindOne(r.ctx, employeeID)
+	if err != nil || emp == nil || emp.CompanyId != r.companyID {
+		return nil
+	}
+	return &utils.ContentRef{Kind: utils.ContentRefMention, ID: emp.Id, Label: emp.RealName, Href: contentEmployeeHref + emp.Id}
+}
+
+func (r *contentResolver) resolveDepartment(departmentID string) *utils.ContentRef {
+	dept, err := r.svc.departmentModel.FindOne(r.ctx, departmentID)
+	if err != nil || dept == nil || dept.CompanyId != r.companyID || dept.DeleteTime.Valid {
+		return nil
+	}
+	return &utils.ContentRef{Kind: utils.ContentRefDepartment, ID: dept.Id, Label: dept.DepartmentName, Href: contentDepartmentHref + dept.Id}
+}
+
+func (r *contentResolver) resolveRole(roleID string) *utils.ContentRef {
+	ro, err := r.svc.roleModel.FindOne(r.ctx, roleID)
+	if err != nil || ro == nil || ro.CompanyId != r.companyID || ro.DeleteTime.Valid || ro.Status != 1 {
+		return nil
+	}
+	return &utils.ContentRef{Kind: utils.ContentRefRole, ID: ro.Id, Label: ro.RoleName}
+}
+
+// groupMembers @部门或@角色对应的同公司在职员工
+func (r *contentResolver) groupMembers(ref utils.ContentRef) []string {
+	var (
+		employees []*user.Employee
+		err       error
+	)
+	if ref.Kind == utils.ContentRefDepartment {
+		employees, err = r.svc.employeeModel.FindByDepartmentID(r.ctx, ref.ID)
+	} else {
+		employees, err = r.svc.employeeModel.FindByRoleID(r.ctx, ref.ID)
+	}
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(employees))
+	for _, emp := range employees {
+		if emp.CompanyId == r.companyID && emp.Status != 0 {
+			ids = append(ids, emp.Id)
+		}
+	}
+	return ids
+}
+
+func (r *contentResolver) resolveTask(taskID string) *utils.ContentRef {
+	t, err := r.svc.taskModel.FindOne(r.ctx, taskID)
+	if err != nil || t == nil || t.CompanyId != r.companyID || t.DeleteTime.Valid {
+		return nil
+	}
+	return &utils.ContentRef{Kind: utils.ContentRefTask, ID: t.TaskId, Label: t.TaskTitle, Href: contentTaskHref + t.TaskId}
+}
+
+func (r *contentResolver) resolveNode(nodeID string) *utils.ContentRef {
+	n, err := r.svc.taskNodeModel.FindOne(r.ctx, nodeID)
+	if err != nil || n == nil || n.DeleteTime.Valid {
+		return nil
+	}
+	if r.resolveTask(n.TaskId) == nil {
+		return nil
+	}
+	return &utils.ContentRef{Kind: utils.ContentRefNode, ID: n.TaskNodeId, Label: n.NodeName, Href: fmt.Sprintf(contentNodeHref, n.TaskId, n.TaskNodeId)}
+}
@@ -1,7 +1,10 @@
 package svc
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -21,11 +24,39 @@ type FileStorageInterface interface {
 	SaveFileFromBytes(module, category, relatedID, fileID, fileName string, data []byte) (string, string, error)
 	DeleteFile(key string) error
 	GetFileURL(key string) string
+	GetFile(key string) ([]byte, error)
+	OpenFile(key string) (io.ReadCloser, error)
+	ObjectKey(module, category, relatedID, fileID, fileName string) string
+}
+
+// ErrStorageFileNotFound 存储中不存在该文件
+var ErrStorageFileNotFound = errors.New("文件不存在")
+
+// StoragePart 分片上传中已上传的分片
+type StoragePart struct {
+	PartNumber int
+	ETag       string
+}
+
+// MultipartStorage 支持分片上传的存储，分片在存储端合并，服务端不需要缓存整个文件
+type MultipartStorage interface {
+	InitMultipart(ctx context.Context, key string) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int, data []byte, md5 []byte) (string, error)
+	CompleteMultipart(ctx context.Context, key, uploadID string, parts []StoragePart) error
+	AbortMultipart(ctx context.Context, key, uploadID string) error
+}
+
+// PresignStorage 支持预签名URL直传的存储，客户端直接把文件上传到存储
+type PresignStorage interface {
+	PresignPut(ctx context.Context, key string, expire time.Duration) (string, error)
+	StatFile(ctx context.Context, key string) (int64, error)
 }
 
 // COSStorageService 腾讯云COS存储服务
 type COSStorageService struct {
 	client    *cos.Client
+	secretId  string
+	secretKey string
 	bucket    string
 	region    string
 	urlPrefix string
@@ -67,6 +98,8 @@ func NewCOSStorageService(secretId, secretKey, bucket, region, urlPrefix string)
 
 	return &COSStorageService{
 		client:    client,
+		secretId:  secretId,
+		secretKey: secretKey,
 		bucket:    bucket,
 		region:    region,
 		urlPrefix: urlPrefix,
@@ -83,15 +116,7 @@ func NewCOSStorageService(secretId, secretKey, bucket, region, urlPrefix string)
 // 返回: 文件保存路径(COS Key), 访问URL, 错误
 func (s *COSStorageService) SaveFile(module, category, relatedID, fileID, fileName string, file multipart.File) (string, string, error) {
 	// 生成COS对象键（Key）
-	ext := filepath.Ext(fileName)
-	timestamp := time.Now().Format("20060102150405")
-	cleanName := sanitizeFileName(fileName)
-
-	// 构建COS Key: module/category/relatedID/fileID_timestamp_filename.ext
-	key := fmt.Sprintf("%s/%s/%s/%s_%s%s", module, category, relatedID, fileID, timestamp, ext)
-	if cleanName != "" && len(cleanName) <= 50 {
-		key = fmt.Sprintf("%s/%s/%s/%s_%s_%s%s", module, category, relatedID, fileID, timestamp, strings.TrimSuffix(cleanName, ext), ext)
-	}
+	key := s.ObjectKey(module, category, relatedID, fileID, fileName)
 
 	// 上传文件到COS
 	ctx := context.Background()
@@ -108,6 +133,20 @@ func (s *COSStorageService) SaveFile(module, category, relatedID, fileID, fileNa
 	return key, fileURL, nil
 }
 
+// ObjectKey 生成COS对象键: module/category/relatedID/fileID_timestamp_filename.ext，
+// 文件名过长时省略文件名部分
+func (s *COSStorageService) ObjectKey(module, category, relatedID, fileID, fileName string) string {
+	ext := filepath.Ext(fileName)
+	timestamp := time.Now().Format("20060102150405")
+	cleanName := sanitizeFileName(fileName)
+
+	key := fmt.Sprintf("%s/%s/%s/%s_%s%s", module, category, relatedID, fileID, timestamp, ext)
+	if cleanName != "" && len(cleanName) <= 50 {
+		key = fmt.Sprintf("%s/%s/%s/%s_%s_%s%s", module, category, relatedID, fileID, timestamp, strings.TrimSuffix(cleanName, ext), ext)
+	}
+	return key
+}
+
 // SaveFileFromBytes 从字节数据保存文件到COS
 func (s *COSStorageService) SaveFileFromBytes(module, category, relatedID, fileID, fileName string, data []byte) (string, string, error) {
 	// 生成COS对象键（Key）
@@ -181,6 +220,87 @@ func (s *COSStorageService) GetFile(key string) ([]byte, error) {
 	return data, nil
 }
 
+// OpenFile 以流的方式读取COS文件，调用方负责关闭；大文件使用该方法避免整个读入内存
+func (s *COSStorageService) OpenFile(key string) (io.ReadCloser, error) {
+	if key == "" {
+		return nil, fmt.Errorf("文件Key不能为空")
+	}
+	resp, err := s.client.Object.Get(context.Background(), key, nil)
+	if err != nil {
+		logx.Errorf("从COS获取文件失败: key=%s, error=%v", key, err)
+		return nil, fmt.Errorf("获取文件失败: %v", err)
+	}
+	return resp.Body, nil
+}
+
+// InitMultipart 初始化COS分片上传，返回分片上传ID
+func (s *COSStorageService) InitMultipart(ctx context.Context, key string) (string, error) {
+	result, _, err := s.client.Object.InitiateMultipartUpload(ctx, key, nil)
+	if err != nil {
+		logx.Errorf("初始化COS分片上传失败: key=%s, error=%v", key, err)
+		return "", fmt.Errorf("初始化分片上传失败: %v", err)
+	}
+	return result.UploadID, nil
+}
+
+// UploadPart 上传一个分片，携带 Content-MD5 由COS校验传输完整性，返回分片的 ETag
+func (s *COSStorageService) UploadPart(ctx context.Context, key, uploadID string, partNumber int, data []byte, md5 []byte) (string, error) {
+	opt := &cos.ObjectUploadPartOptions{
+		ContentLength: int64(len(data)),
+		ContentMD5:    base64.StdEncoding.EncodeToString(md5),
+	}
+	resp, err := s.client.Object.UploadPart(ctx, key, uploadID, partNumber, bytes.NewReader(data), opt)
+	if err != nil {
+		logx.Errorf("上传COS分片失败: key=%s, part=%d, error=%v", key, partNumber, err)
+		return "", fmt.Errorf("上传分片失败: %v", err)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// CompleteMultipart 合并已上传的分片
+func (s *COSStorageService) CompleteMultipart(ctx context.Context, key, uploadID string, parts []StoragePart) error {
+	opt := &cos.CompleteMultipartUploadOptions{}
+	for _, p := range parts {
+		opt.Parts = append(opt.Parts, cos.Object{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	if _, _, err := s.client.Object.CompleteMultipartUpload(ctx, key, uploadID, opt); err != nil {
+		logx.Errorf("合并COS分片失败: key=%s, error=%v", key, err)
+		return fmt.Errorf("合并分片失败: %v", err)
+	}
+	return nil
+}
+
+// AbortMultipart 取消分片上传并删除已上传的分片
+func (s *COSStorageService) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	if _, err := s.client.Object.AbortMultipartUpload(ctx, key, uploadID); err != nil {
+		logx.Errorf("取消COS分片上传失败: key=%s, error=%v", key, err)
+		return fmt.Errorf("取消分片上传失败: %v", err)
+	}
+	return nil
+}
+
+// PresignPut 生成上传到指定Key的预签名URL，客户端用 PUT 方法上传文件内容
+func (s *COSStorageService) PresignPut(ctx context.Context, key string, expire time.Duration) (string, error) {
+	u, err := s.client.Object.GetPresignedURL(ctx, http.MethodPut, key, s.secretId, s.secretKey, expire, nil)
+	if err != nil {
+		logx.Errorf("生成COS预签名URL失败: key=%s, error=%v", key, err)
+		return "", fmt.Errorf("生成上传地址失败: %v", err)
+	}
+	return u.String(), nil
+}
+
+// StatFile 查询COS文件大小，文件不存在时返回 ErrStorageFileNotFound
+func (s *COSStorageService) StatFile(ctx context.Context, key string) (int64, error) {
+	resp, err := s.client.Object.Head(ctx, key, nil)
+	if err != nil {
+		if cos.IsNotFoundError(err) {
+			return 0, ErrStorageFileNotFound
+		}
+		return 0, fmt.Errorf("查询文件失败: %v", err)
+	}
+	return resp.ContentLength, nil
+}
+
 // sanitizeFileName 清理文件名，移除特殊字符
 func sanitizeFileName(fileName string) string {
 	// 移除路径分隔符和特殊字符
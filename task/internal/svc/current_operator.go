@@ -0,0 +1,23 @@
+package svc
+
+import (
+	"context"
+
+	"task_Project/model/user"
+	"task_Project/task/internal/types"
+	"task_Project/task/internal/utils"
+)
+
+// CurrentOperator 当前请求用户在令牌所属公司的员工记录，作为各模块接口的操作人；
+// 未登录时返回未授权响应，不在该公司任职时返回 employee_not_in_company
+func (s *ServiceContext) CurrentOperator(ctx context.Context) (*user.Employee, *types.BaseResponse) {
+	userID, ok := utils.Common.GetCurrentUserID(ctx)
+	if !ok || userID == "" {
+		return nil, utils.Response.UnauthorizedError()
+	}
+	employee, err := s.CompanyMembershipService.CurrentEmployee(ctx, userID)
+	if err != nil || employee == nil {
+		return nil, utils.Response.BusinessError("employee_not_in_company")
+	}
+	return employee, nil
+}
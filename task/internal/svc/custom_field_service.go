@@ -0,0 +1,229 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+)
+
+// 自定义字段限制
+const (
+	MaxCustomFieldOptions   = 50  // 单选/多选最多的选项数
+	MaxCustomFieldTextRunes = 500 // 文本字段最大字符数
+)
+
+// customFieldTypeNames 支持的字段类型
+var customFieldTypeNames = map[string]string{
+	task.CustomFieldText:        "文本",
+	task.CustomFieldNumber:      "数字",
+	task.CustomFieldDate:        "日期",
+	task.CustomFieldSelect:      "单选",
+	task.CustomFieldMultiSelect: "多选",
+	task.CustomFieldEmployee:    "员工",
+}
+
+// CustomFieldOption 单选/多选字段的选项，值中保存选项ID，选项改名后已填写的值不受影响
+type CustomFieldOption struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Color string `json:"color,omitempty"`
+}
+
+// IsCustomFieldType 判断是否为支持的字段类型
+func IsCustomFieldType(fieldType string) bool {
+	_, ok := customFieldTypeNames[fieldType]
+	return ok
+}
+
+// CustomFieldTypeName 字段类型的中文名称
+func CustomFieldTypeName(fieldType string) string {
+	return customFieldTypeNames[fieldType]
+}
+
+// HasCustomFieldOptions 字段类型是否需要选项
+func HasCustomFieldOptions(fieldType string) bool {
+	return fieldType == task.CustomFieldSelect || fieldType == task.CustomFieldMultiSelect
+}
+
+// ParseCustomFieldOptions 解析字段的选项，解析失败时返回空列表
+func ParseCustomFieldOptions(field *task.CustomField) []CustomFieldOption {
+	options := []CustomFieldOption{}
+	if field.Options.Valid && field.Options.String != "" {
+		_ = json.Unmarshal([]byte(field.Options.String), &options)
+	}
+	return options
+}
+
+// CustomFieldService 自定义字段值的校验、存储格式转换和显示
+type CustomFieldService struct {
+	customFieldModel task.CustomFieldModel
+	employeeModel    user.EmployeeModel
+}
+
+func NewCustomFieldService(customFieldModel task.CustomFieldModel, employeeModel user.EmployeeModel) *CustomFieldService {
+	return &CustomFieldService{customFieldModel: customFieldModel, employeeModel: employeeModel}
+}
+
+// NormalizeValue 校验字段值并转换为存储格式，多选字段可传多个值，其他字段只取第一个值；
+// 没有值时返回空字符串（表示清空），校验失败时返回错误提示
+func (s *CustomFieldService) NormalizeValue(ctx context.Context, field *task.CustomField, values []string) (string, string) {
+	cleaned := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			cleaned = append(cleaned, v)
+		}
+	}
+	if len(cleaned) == 0 {
+		if field.Required == 1 {
+			return "", fmt.Sprintf("字段「%s」为必填项", field.Name)
+		}
+		return "", ""
+	}
+	if field.FieldType != task.CustomFieldMultiSelect && len(cleaned) > 1 {
+		return "", fmt.Sprintf("字段「%s」只能填写一个值", field.Name)
+	}
+	value := cleaned[0]
+
+	switch field.FieldType {
+	case task.CustomFieldText:
+		if utf8.RuneCountInString(value) > MaxCustomFieldTextRunes {
+			return "", fmt.Sprintf("字段「%s」不能超过 %d 个字符", field.Name, MaxCustomFieldTextRunes)
+		}
+		return value, ""
+	case task.CustomFieldNumber:
+		n, ok := ParseCustomFieldNumber(value)
+		if !ok {
+			return "", fmt.Sprintf("字段「%s」应为数字", field.Name)
+		}
+		return n, ""
+	case task.CustomFieldDate:
+		d, ok := ParseCustomFieldDate(value)
+		if !ok {
+			return "", fmt.Sprintf("字段「%s」应为 YYYY-MM-DD 格式的日期", field.Name)
+		}
+		return d, ""
+	case task.CustomFieldSelect, task.CustomFieldMultiSelect:
+		options := make(map[string]bool)
+		for _, o := range ParseCustomFieldOptions(field) {
+			options[o.ID] = true
+		}
+		seen := make(map[string]bool, len(cleaned))
+		ids := make([]string, 0, len(cleaned))
+		for _, v := range cleaned {
+			if !options[v] {
+				return "", fmt.Sprintf("字段「%s」的选项不存在", field.Name)
+			}
+			if !seen[v] {
+				seen[v] = true
+				ids = append(ids, v)
+			}
+		}
+		return strings.Join(ids, ","), ""
+	case task.CustomFieldEmployee:
+		emp, err := s.employeeModel.FindOne(ctx, value)
+		if err != nil || emp.CompanyId != field.CompanyId {
+			return "", fmt.Sprintf("字段「%s」的员工不存在", field.Name)
+		}
+		return value, ""
+	}
+	return "", fmt.Sprintf("字段「%s」的类型不支持", field.Name)
+}
+
+// SplitCustomFieldValue 将存储的值拆分为列表（多选为多个选项ID）
+func SplitCustomFieldValue(field *task.CustomField, value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	if field.FieldType == task.CustomFieldMultiSelect {
+		return strings.Split(value, ",")
+	}
+	return []string{value}
+}
+
+// FormatValue 字段值的显示文本：选项显示名称，员工显示姓名
+func (s *CustomFieldService) FormatValue(field *task.CustomField, value string, nameOf func(string) string) string {
+	if value == "" {
+		return ""
+	}
+	switch field.FieldType {
+	case task.CustomFieldSelect, task.CustomFieldMultiSelect:
+		labels := make(map[string]string)
+		for _, o := range ParseCustomFieldOptions(field) {
+			labels[o.ID] = o.Label
+		}
+		var names []string
+		for _, id := range SplitCustomFieldValue(field, value) {
+			// 已删除的选项不显示
+			if label, ok := labels[id]; ok {
+				names = append(names, label)
+			}
+		}
+		return strings.Join(names, "、")
+	case task.CustomFieldEmployee:
+		if nameOf != nil {
+			return nameOf(value)
+		}
+	}
+	return value
+}
+
+// EmployeeNamer 返回带缓存的员工姓名查询函数，用于显示员工字段，员工不存在时显示员工ID
+func (s *CustomFieldService) EmployeeNamer(ctx context.Context) func(string) string {
+	names := make(map[string]string)
+	return func(employeeID string) string {
+		if name, ok := names[employeeID]; ok {
+			return name
+		}
+		name := employeeID
+		if emp, err := s.employeeModel.FindOne(ctx, employeeID); err == nil {
+			name = emp.RealName
+		}
+		names[employeeID] = name
+		return name
+	}
+}
+
+// LoadValues 批量查询对象的字段值，返回 对象ID -> 字段ID -> 值
+func (s *CustomFieldService) LoadValues(ctx context.Context, entityIDs []string) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string, len(entityIDs))
+	if len(entityIDs) == 0 {
+		return result, nil
+	}
+	values, err := s.customFieldModel.FindValues(ctx, entityIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if result[v.EntityId] == nil {
+			result[v.EntityId] = make(map[string]string)
+		}
+		result[v.EntityId][v.FieldId] = v.Value
+	}
+	return result, nil
+}
+
+// ParseCustomFieldNumber 解析数字并转换为统一的十进制格式
+func ParseCustomFieldNumber(value string) (string, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) || math.Abs(n) >= 1e24 {
+		return "", false
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64), true
+}
+
+// ParseCustomFieldDate 校验 YYYY-MM-DD 格式的日期
+func ParseCustomFieldDate(value string) (string, bool) {
+	t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return "", false
+	}
+	return t.Format("2006-01-02"), true
+}
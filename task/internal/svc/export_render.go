@@ -0,0 +1,574 @@
+package svc
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 导出文件格式
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+)
+
+// ExportDocument 导出文档，一个文档包含一个或多个表格（XLSX 中每个表格为一个工作表）
+type ExportDocument struct {
+	Title       string
+	GeneratedAt time.Time
+	Sheets      []ExportSheet
+}
+
+// ExportSheet 导出表格
+type ExportSheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]string
+}
+
+// RowCount 文档中所有表格的数据行数
+func (d *ExportDocument) RowCount() int {
+	count := 0
+	for _, sheet := range d.Sheets {
+		count += len(sheet.Rows)
+	}
+	return count
+}
+
+// IsExportFormat 判断是否为支持的导出格式
+func IsExportFormat(format string) bool {
+	switch format {
+	case ExportFormatCSV, ExportFormatXLSX, ExportFormatPDF:
+		return true
+	}
+	return false
+}
+
+// ExportContentType 导出格式对应的 Content-Type
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ExportFormatPDF:
+		return "application/pdf"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// RenderExport 按格式生成导出文件内容
+func RenderExport(doc *ExportDocument, format string) ([]byte, error) {
+	switch format {
+	case ExportFormatCSV:
+		return renderCSV(doc)
+	case ExportFormatXLSX:
+		return renderXLSX(doc)
+	case ExportFormatPDF:
+		return renderPDF(doc)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// SpreadsheetSafe 单元格内容以 = + - @ 或制表符、回车开头时加上单引号前缀，
+// 防止用户填写的任务标题、姓名等内容在 Excel 中被当作公式执行（CSV/公式注入）
+func SpreadsheetSafe(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+// SpreadsheetSafeRow 对一行单元格逐个调用 SpreadsheetSafe
+func SpreadsheetSafeRow(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, cell := range cells {
+		out[i] = SpreadsheetSafe(cell)
+	}
+	return out
+}
+
+// renderCSV 生成 CSV，多个表格依次输出，表格之间空一行并以表格名称开头
+func renderCSV(doc *ExportDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，保证 Excel 正确识别中文
+	buf.WriteString("\xEF\xBB\xBF")
+	writer := csv.NewWriter(&buf)
+	for i, sheet := range doc.Sheets {
+		if len(doc.Sheets) > 1 {
+			if i > 0 {
+				_ = writer.Write([]string{""})
+			}
+			_ = writer.Write([]string{SpreadsheetSafe(sheet.Name)})
+		}
+		_ = writer.Write(SpreadsheetSafeRow(sheet.Headers))
+		for _, row := range sheet.Rows {
+			_ = writer.Write(SpreadsheetSafeRow(row))
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ---------- XLSX ----------
+
+const (
+	xlsxMainNS = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelNS  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xmlHeader  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+// renderXLSX 生成最小化的 Office Open XML 工作簿：每个表格一个工作表，单元格使用内联字符串，表头加粗并冻结
+func renderXLSX(doc *ExportDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names := xlsxSheetNames(doc.Sheets)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes(len(doc.Sheets))},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + xlsxRelNS + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", xlsxWorkbook(names)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(doc.Sheets))},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range doc.Sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheet(sheet)})
+	}
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func xlsxContentTypes(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func xlsxWorkbook(names []string) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="` + xlsxMainNS + `" xmlns:r="` + xlsxRelNS + `"><sheets>`)
+	for i, name := range names {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func xlsxWorkbookRels(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i, xlsxRelNS, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/>`, sheetCount+1, xlsxRelNS)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// xlsxStyles 两种单元格样式：0-默认，1-加粗（表头）
+const xlsxStyles = xmlHeader + `<styleSheet xmlns="` + xlsxMainNS + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+func xlsxWorksheet(sheet ExportSheet) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="` + xlsxMainNS + `">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	widths := exportColumnWidths(sheet)
+	if len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, w := range widths {
+			chars := w + 2
+			if chars < 8 {
+				chars = 8
+			}
+			if chars > 60 {
+				chars = 60
+			}
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%.1f" customWidth="1"/>`, i+1, i+1, chars)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	writeRow := func(r int, cells []string, style int) {
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for c, value := range cells {
+			ref := xlsxColumnName(c) + fmt.Sprint(r)
+			if style > 0 {
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr">`, ref, style)
+			} else {
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr">`, ref)
+			}
+			b.WriteString(`<is><t xml:space="preserve">` + xmlEscape(SpreadsheetSafe(value)) + `</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+	writeRow(1, sheet.Headers, 1)
+	for i, row := range sheet.Rows {
+		writeRow(i+2, row, 0)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// xlsxColumnName 列序号（从 0 开始）转换为 A、B、…、AA 形式的列名
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetNames 工作表名称去除非法字符、截断到 31 个字符并去重
+func xlsxSheetNames(sheets []ExportSheet) []string {
+	replacer := strings.NewReplacer("[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "")
+	used := make(map[string]bool, len(sheets))
+	names := make([]string, 0, len(sheets))
+	for i, sheet := range sheets {
+		name := strings.TrimSpace(replacer.Replace(sheet.Name))
+		if name == "" {
+			name = fmt.Sprintf("Sheet%d", i+1)
+		}
+		if utf8.RuneCountInString(name) > 28 {
+			name = string([]rune(name)[:28])
+		}
+		base := name
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s(%d)", base, n)
+		}
+		used[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// exportColumnWidths 按表头和内容估算每列的显示宽度（半角字符数，全角字符计 2）
+func exportColumnWidths(sheet ExportSheet) []float64 {
+	widths := make([]float64, len(sheet.Headers))
+	measure := func(cells []string) {
+		for i, cell := range cells {
+			if i >= len(widths) {
+				break
+			}
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	measure(sheet.Headers)
+	for _, row := range sheet.Rows {
+		measure(row)
+	}
+	return widths
+}
+
+// displayWidth 字符串的显示宽度，ASCII 字符计 1，其余计 2
+func displayWidth(s string) float64 {
+	var w float64
+	for _, r := range s {
+		if r < 0x80 {
+			w++
+		} else {
+			w += 2
+		}
+	}
+	return w
+}
+
+// ---------- PDF ----------
+
+// PDF 页面布局（A4 横向，单位为点）
+const (
+	pdfPageWidth   = 842.0
+	pdfPageHeight  = 595.0
+	pdfMargin      = 36.0
+	pdfFontSize    = 8.0
+	pdfRowHeight   = 14.0
+	pdfCellPadding = 3.0
+	pdfMinColWidth = 36.0
+)
+
+// pdfWriter 逐页生成 PDF 内容流。
+// 字体使用 PDF 阅读器内置的 STSong-Light（UniGB-UCS2-H 编码），无需嵌入字体即可显示中文
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (w *pdfWriter) newPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+	w.y = pdfPageHeight - pdfMargin
+	w.text(pdfPageWidth-pdfMargin-60, pdfMargin/2, pdfFontSize, fmt.Sprintf("第 %d 页", len(w.pages)))
+}
+
+// ensure 当前页剩余空间不足 height 时换页，返回是否换页
+func (w *pdfWriter) ensure(height float64) bool {
+	if w.page != nil && w.y-height >= pdfMargin {
+		return false
+	}
+	w.newPage()
+	return true
+}
+
+func (w *pdfWriter) text(x, y, size float64, s string) {
+	fmt.Fprintf(w.page, "BT /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, y, pdfUCS2(s))
+}
+
+func (w *pdfWriter) fillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(w.page, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", gray, x, y, width, height)
+}
+
+func (w *pdfWriter) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(w.page, "0.5 w 0.75 G %.2f %.2f m %.2f %.2f l S 0 G\n", x1, y1, x2, y2)
+}
+
+// row 输出一行表格，单元格内容超出列宽时截断
+func (w *pdfWriter) row(widths []float64, cells []string, header bool) {
+	top := w.y
+	bottom := top - pdfRowHeight
+	tableWidth := 0.0
+	for _, cw := range widths {
+		tableWidth += cw
+	}
+	if header {
+		w.fillRect(pdfMargin, bottom, tableWidth, pdfRowHeight, 0.9)
+	}
+	x := pdfMargin
+	for i, cw := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		cell = pdfTruncate(strings.Join(strings.Fields(cell), " "), cw-2*pdfCellPadding, pdfFontSize)
+		if cell != "" {
+			w.text(x+pdfCellPadding, bottom+4, pdfFontSize, cell)
+		}
+		x += cw
+	}
+	w.line(pdfMargin, bottom, pdfMargin+tableWidth, bottom)
+	w.y = bottom
+}
+
+// renderPDF 生成 PDF：标题、导出时间，然后依次输出每个表格，换页时重复表头
+func renderPDF(doc *ExportDocument) ([]byte, error) {
+	w := &pdfWriter{}
+	w.newPage()
+	w.text(pdfMargin, w.y-16, 16, doc.Title)
+	w.y -= 24
+	if !doc.GeneratedAt.IsZero() {
+		w.text(pdfMargin, w.y-10, pdfFontSize, "导出时间："+doc.GeneratedAt.Format("2006-01-02 15:04:05"))
+		w.y -= 18
+	}
+
+	for _, sheet := range doc.Sheets {
+		widths := pdfColumnWidths(sheet)
+		w.ensure(pdfRowHeight*3 + 20)
+		w.y -= 8
+		w.text(pdfMargin, w.y-12, 11, fmt.Sprintf("%s（%d 条）", sheet.Name, len(sheet.Rows)))
+		w.y -= 18
+		w.row(widths, sheet.Headers, true)
+		for _, row := range sheet.Rows {
+			if w.ensure(pdfRowHeight) {
+				w.row(widths, sheet.Headers, true)
+			}
+			w.row(widths, row, false)
+		}
+	}
+	return w.bytes(doc.Title)
+}
+
+// bytes 组装 PDF 对象：1-目录 2-页面树 3-字体 4-CID 字体 5-字体描述 6-文档信息，之后每页一个页面对象和一个内容流
+func (w *pdfWriter) bytes(title string) ([]byte, error) {
+	var objects []string
+	pageCount := len(w.pages)
+	kids := make([]string, 0, pageCount)
+	for i := 0; i < pageCount; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 7+i*2))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light-UniGB-UCS2-H /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> "+
+			"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500 814 939 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 "+
+			"/Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+		fmt.Sprintf("<< /Title <FEFF%s> /Producer (task_Project) /CreationDate (D:%s) >>", pdfUCS2(title), time.Now().Format("20060102150405")),
+	)
+	for i, page := range w.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 8+i*2),
+			fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes(), nil
+}
+
+// pdfUCS2 将字符串编码为 UCS-2（大端）十六进制，基本平面以外的字符替换为问号
+func pdfUCS2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF || r < 0x20 {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// pdfTextWidth 文本宽度（点），半角字符按半个字宽计算
+func pdfTextWidth(s string, size float64) float64 {
+	return displayWidth(s) * size / 2
+}
+
+// pdfTruncate 截断文本使其不超过指定宽度，被截断时以省略号结尾
+func pdfTruncate(s string, width, size float64) string {
+	if pdfTextWidth(s, size) <= width {
+		return s
+	}
+	limit := width - size
+	var b strings.Builder
+	used := 0.0
+	for _, r := range s {
+		rw := size
+		if r < 0x80 {
+			rw = size / 2
+		}
+		if used+rw > limit {
+			break
+		}
+		used += rw
+		b.WriteRune(r)
+	}
+	return b.String() + "…"
+}
+
+// pdfColumnWidths 按内容宽度分配列宽：内容较窄的列按实际宽度，剩余空间由较宽的列平分
+func pdfColumnWidths(sheet ExportSheet) []float64 {
+	available := pdfPageWidth - 2*pdfMargin
+	natural := exportColumnWidths(sheet)
+	if len(natural) == 0 {
+		return nil
+	}
+	widths := make([]float64, len(natural))
+	for i, chars := range natural {
+		widths[i] = chars*pdfFontSize/2 + 2*pdfCellPadding
+		if widths[i] < pdfMinColWidth {
+			widths[i] = pdfMinColWidth
+		}
+	}
+
+	// 逐步固定不超过平均宽度的列，其余列平分剩余空间
+	fixed := make([]bool, len(widths))
+	remaining := available
+	open := len(widths)
+	for changed := true; changed && open > 0; {
+		changed = false
+		share := remaining / float64(open)
+		for i := range widths {
+			if !fixed[i] && widths[i] <= share {
+				fixed[i] = true
+				remaining -= widths[i]
+				open--
+				changed = true
+			}
+		}
+	}
+	if open > 0 {
+		share := remaining / float64(open)
+		if share < pdfMinColWidth {
+			share = pdfMinColWidth
+		}
+		for i := range widths {
+			if !fixed[i] {
+				widths[i] = share
+			}
+		}
+	}
+	return widths
+}
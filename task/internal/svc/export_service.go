@@ -0,0 +1,209 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task_Project/model/task"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 导出内容
+const (
+	ExportTypeTaskList       = "task_list"       // 任务列表
+	ExportTypeNodeList       = "node_list"       // 任务节点列表
+	ExportTypeEmployeeList   = "employee_list"   // 员工列表
+	ExportTypeHandoverList   = "handover_list"   // 交接记录
+	ExportTypeDashboardStats = "dashboard_stats" // 仪表盘统计
+	ExportTypeTaskReport     = "task_report"     // 单个任务的状态报告（节点、清单、日志）
+)
+
+// exportTypeTitles 导出内容的中文名称，用于文件名和通知
+var exportTypeTitles = map[string]string{
+	ExportTypeTaskList:       "任务列表",
+	ExportTypeNodeList:       "任务节点列表",
+	ExportTypeEmployeeList:   "员工列表",
+	ExportTypeHandoverList:   "交接记录",
+	ExportTypeDashboardStats: "仪表盘统计",
+	ExportTypeTaskReport:     "任务状态报告",
+}
+
+// 同时生成的导出任务数、单个导出的超时时间和每次清理的过期任务数
+const (
+	exportConcurrency  = 2
+	exportTimeout      = 10 * time.Minute
+	exportCleanupBatch = 200
+)
+
+// ExportTypeTitle 导出内容的中文名称，未知类型返回空字符串
+func ExportTypeTitle(exportType string) string {
+	return exportTypeTitles[exportType]
+}
+
+// ExportBuilder 在后台查询数据并组装导出文档
+type ExportBuilder func(ctx context.Context) (*ExportDocument, error)
+
+// ExportService 报表导出服务：记录导出任务，在后台生成文件写入文件存储，完成后通知发起人
+type ExportService struct {
+	exportJobModel        task.ExportJobModel
+	fileStorage           FileStorageInterface
+	notificationMQService *NotificationMQService
+	systemConfigService   *SystemConfigService
+	slots                 chan struct{}
+}
+
+// NewExportService 创建报表导出服务
+func NewExportService(exportJobModel task.ExportJobModel, fileStorage FileStorageInterface,
+	notificationMQService *NotificationMQService, systemConfigService *SystemConfigService) *ExportService {
+	return &ExportService{
+		exportJobModel:        exportJobModel,
+		fileStorage:           fileStorage,
+		notificationMQService: notificationMQService,
+		systemConfigService:   systemConfigService,
+		slots:                 make(chan struct{}, exportConcurrency),
+	}
+}
+
+// MaxRows 单次导出的最大数据行数
+func (s *ExportService) MaxRows() int {
+	return s.systemConfigService.GetInt(SettingExportMaxRows, 50000)
+}
+
+// Submit 保存导出任务并在后台生成文件；build 在后台执行，不能依赖请求上下文
+func (s *ExportService) Submit(ctx context.Context, job *task.ExportJob, build ExportBuilder) error {
+	now := time.Now()
+	job.Status = task.ExportStatusPending
+	job.CreateTime = now
+	job.UpdateTime = now
+	if _, err := s.exportJobModel.Insert(ctx, job); err != nil {
+		return err
+	}
+	go s.run(job, build)
+	return nil
+}
+
+// run 排队等待空闲的导出槽位后生成文件
+func (s *ExportService) run(job *task.ExportJob, build ExportBuilder) {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf("[Export] 导出任务异常: jobId=%s, panic=%v", job.Id, r)
+			s.fail(context.Background(), job, "导出过程中发生内部错误")
+		}
+	}()
+
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	begin := time.Now()
+	if err := s.exportJobModel.UpdateStatus(ctx, job.Id, task.ExportStatusRunning); err != nil {
+		logx.Errorf("[Export] 更新导出任务状态失败: jobId=%s, err=%v", job.Id, err)
+	}
+
+	doc, err := build(ctx)
+	if err != nil {
+		logx.Errorf("[Export] 查询导出数据失败: jobId=%s, type=%s, err=%v", job.Id, job.ExportType, err)
+		s.fail(ctx, job, "查询导出数据失败")
+		return
+	}
+	if maxRows := s.MaxRows(); doc.RowCount() > maxRows {
+		s.fail(ctx, job, fmt.Sprintf("导出数据共 %d 行，超过单次导出上限 %d 行，请缩小导出范围", doc.RowCount(), maxRows))
+		return
+	}
+	doc.GeneratedAt = begin
+
+	data, err := RenderExport(doc, job.Format)
+	if err != nil {
+		logx.Errorf("[Export] 生成导出文件失败: jobId=%s, format=%s, err=%v", job.Id, job.Format, err)
+		s.fail(ctx, job, "生成导出文件失败")
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_%s.%s", doc.Title, begin.Format("20060102150405"), job.Format)
+	key, url, err := s.fileStorage.SaveFileFromBytes("export", job.ExportType, job.CompanyId, job.Id, fileName, data)
+	if err != nil {
+		logx.Errorf("[Export] 保存导出文件失败: jobId=%s, err=%v", job.Id, err)
+		s.fail(ctx, job, "保存导出文件失败")
+		return
+	}
+
+	job.Status = task.ExportStatusDone
+	job.FileName = sql.NullString{String: fileName, Valid: true}
+	job.FileKey = sql.NullString{String: key, Valid: true}
+	job.FileUrl = sql.NullString{String: url, Valid: true}
+	job.FileSize = int64(len(data))
+	job.RowCount = int64(doc.RowCount())
+	job.FinishTime = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.exportJobModel.Finish(ctx, job); err != nil {
+		logx.Errorf("[Export] 保存导出结果失败: jobId=%s, err=%v", job.Id, err)
+	}
+	logx.Infof("[Export] 导出完成: jobId=%s, type=%s, format=%s, rows=%d, size=%d, 耗时 %v",
+		job.Id, job.ExportType, job.Format, job.RowCount, job.FileSize, time.Since(begin))
+	s.notify(ctx, ExportReady, job, fmt.Sprintf("您导出的%s已生成（%s，共 %d 行），请在导出记录中下载。", ExportTypeTitle(job.ExportType), fileName, job.RowCount))
+}
+
+// fail 记录导出失败并通知发起人
+func (s *ExportService) fail(ctx context.Context, job *task.ExportJob, message string) {
+	job.Status = task.ExportStatusFailed
+	job.ErrorMessage = sql.NullString{String: message, Valid: true}
+	job.FinishTime = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.exportJobModel.Finish(ctx, job); err != nil {
+		logx.Errorf("[Export] 保存导出失败原因失败: jobId=%s, err=%v", job.Id, err)
+	}
+	s.notify(ctx, ExportFailed, job, fmt.Sprintf("您导出的%s生成失败：%s", ExportTypeTitle(job.ExportType), message))
+}
+
+func (s *ExportService) notify(ctx context.Context, eventType string, job *task.ExportJob, content string) {
+	if s.notificationMQService == nil {
+		return
+	}
+	event := s.notificationMQService.NewNotificationEvent(eventType, []string{job.EmployeeId}, job.Id)
+	event.Content = content
+	if err := s.notificationMQService.PublishNotificationEvent(ctx, event); err != nil {
+		logx.WithContext(ctx).Errorf("[Export] 发布通知失败: jobId=%s, err=%v", job.Id, err)
+	}
+}
+
+// Remove 删除导出任务及其文件
+func (s *ExportService) Remove(ctx context.Context, job *task.ExportJob) error {
+	if job.FileKey.Valid && job.FileKey.String != "" {
+		if err := s.fileStorage.DeleteFile(job.FileKey.String); err != nil {
+			return err
+		}
+	}
+	return s.exportJobModel.Delete(ctx, job.Id)
+}
+
+// FailInterrupted 服务启动时将上次未完成的导出任务标记为失败（后台任务不会在重启后继续）
+func (s *ExportService) FailInterrupted(ctx context.Context) {
+	count, err := s.exportJobModel.FailUnfinished(ctx, "服务重启，导出已中断，请重新导出")
+	if err != nil {
+		logx.Errorf("[Export] 标记中断的导出任务失败: %v", err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("[Export] 已将 %d 个中断的导出任务标记为失败", count)
+	}
+}
+
+// Cleanup 删除超过保留天数的导出任务及其文件
+func (s *ExportService) Cleanup(ctx context.Context) (int, error) {
+	days := s.systemConfigService.GetInt(SettingExportRetentionDays, 7)
+	jobs, err := s.exportJobModel.FindFinishedBefore(ctx, time.Now().AddDate(0, 0, -days), exportCleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, job := range jobs {
+		if err := s.Remove(ctx, job); err != nil {
+			logx.Errorf("[Export] 清理过期导出文件失败: jobId=%s, err=%v", job.Id, err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
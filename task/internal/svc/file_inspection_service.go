@@ -0,0 +1,263 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"task_Project/model/company"
+	"task_Project/model/upload"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 上传文件被拦截的原因，写入安全日志
+const (
+	FileRejectTooLarge       = "too_large"
+	FileRejectExecutable     = "executable"
+	FileRejectTypeMismatch   = "type_mismatch"
+	FileRejectTypeNotAllowed = "type_not_allowed"
+	FileRejectInfected       = "infected"
+	FileRejectScanFailed     = "scan_failed"
+)
+
+// FileRejectedError 上传文件未通过检查，Message 可以直接返回给用户
+type FileRejectedError struct {
+	Reason  string
+	Message string
+}
+
+func (e *FileRejectedError) Error() string {
+	return e.Message
+}
+
+// UploadPolicy 生效的上传策略
+type UploadPolicy struct {
+	AllowedTypes []string // 允许的文件类型，为空时允许全部类型
+	MaxSizeMB    int      // 单个文件大小上限（MB）
+}
+
+// Allows 是否允许上传该类型的文件
+func (p UploadPolicy) Allows(kind string) bool {
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	for _, t := range p.AllowedTypes {
+		if t == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadFile 待检查的上传文件，File 需要支持随机读取（multipart.File 满足）
+type UploadFile struct {
+	CompanyID string
+	UserID    string
+	IP        string
+	Path      string
+	FileName  string
+	Size      int64
+	File      interface {
+		io.Reader
+		io.ReaderAt
+		io.Seeker
+	}
+}
+
+// InspectedFile 检查通过的文件
+type InspectedFile struct {
+	Kind string // 按内容识别的文件类型，见 utils.FileKind*
+	MIME string // 按内容识别的 MIME 类型
+}
+
+// FileInspectionService 上传文件检查：按文件头识别类型并与扩展名、公司允许的类型比对，
+// 再交给病毒扫描器扫描；被拦截的文件记录到安全日志。检查通过的图片和PDF生成缩略图和预览图
+type FileInspectionService struct {
+	policyModel         company.CompanyUploadPolicyModel
+	systemConfigService *SystemConfigService
+	securityLogService  *SecurityLogService
+	scanner             FileScanner
+	failOpen            bool
+	previewer           *FilePreviewer
+	fileStorage         FileStorageInterface
+}
+
+// NewFileInspectionService 创建上传文件检查服务，securityLogService 为 nil 时只写标准日志
+func NewFileInspectionService(policyModel company.CompanyUploadPolicyModel, systemConfigService *SystemConfigService,
+	securityLogService *SecurityLogService, scanner FileScanner, failOpen bool, previewer *FilePreviewer,
+	fileStorage FileStorageInterface) *FileInspectionService {
+	if scanner == nil {
+		scanner = NopFileScanner{}
+	}
+	return &FileInspectionService{
+		policyModel:         policyModel,
+		systemConfigService: systemConfigService,
+		securityLogService:  securityLogService,
+		scanner:             scanner,
+		failOpen:            failOpen,
+		previewer:           previewer,
+		fileStorage:         fileStorage,
+	}
+}
+
+// MaxSizeMB 系统设置的单个文件大小上限（MB），公司策略不能超过该值
+func (s *FileInspectionService) MaxSizeMB() int {
+	return s.systemConfigService.GetInt(SettingUploadMaxSizeMB, 50)
+}
+
+// LargeMaxSizeMB 系统设置的分片上传和直传的单个文件大小上限（MB）
+func (s *FileInspectionService) LargeMaxSizeMB() int {
+	return s.systemConfigService.GetInt(SettingUploadLargeMaxMB, 2048)
+}
+
+// Policy 公司生效的上传策略，没有配置时允许全部类型、使用系统大小上限
+func (s *FileInspectionService) Policy(ctx context.Context, companyID string) UploadPolicy {
+	return s.policy(ctx, companyID, s.MaxSizeMB())
+}
+
+// LargePolicy 分片上传和直传生效的上传策略，公司配置的大小上限同样适用
+func (s *FileInspectionService) LargePolicy(ctx context.Context, companyID string) UploadPolicy {
+	return s.policy(ctx, companyID, s.LargeMaxSizeMB())
+}
+
+func (s *FileInspectionService) policy(ctx context.Context, companyID string, systemMax int) UploadPolicy {
+	policy := UploadPolicy{MaxSizeMB: systemMax}
+	if companyID == "" {
+		return policy
+	}
+	p, err := s.policyModel.FindOne(ctx, companyID)
+	if err != nil {
+		if !errors.Is(err, company.ErrNotFound) {
+			logx.WithContext(ctx).Errorf("[FileInspection] 查询公司上传策略失败: companyId=%s, err=%v", companyID, err)
+		}
+		return policy
+	}
+	policy.AllowedTypes = p.AllowedTypeList()
+	if p.MaxFileSizeMb > 0 && int(p.MaxFileSizeMb) < policy.MaxSizeMB {
+		policy.MaxSizeMB = int(p.MaxFileSizeMb)
+	}
+	return policy
+}
+
+// Inspect 检查上传文件，未通过时返回 *FileRejectedError。检查结束后文件读取位置重置到开头
+func (s *FileInspectionService) Inspect(ctx context.Context, in *UploadFile, policy UploadPolicy) (*InspectedFile, error) {
+	if policy.MaxSizeMB > 0 && in.Size > int64(policy.MaxSizeMB)*1024*1024 {
+		return nil, &FileRejectedError{Reason: FileRejectTooLarge, Message: fmt.Sprintf("文件大小不能超过%dMB", policy.MaxSizeMB)}
+	}
+
+	sniff, err := utils.SniffFile(in.FileName, in.File, in.Size)
+	switch {
+	case errors.Is(err, utils.ErrFileExecutable):
+		return nil, s.reject(in, FileRejectExecutable, err.Error(), nil)
+	case errors.Is(err, utils.ErrFileTypeMismatch):
+		return nil, s.reject(in, FileRejectTypeMismatch, err.Error(), nil)
+	case err != nil:
+		return nil, err
+	}
+	if !policy.Allows(sniff.Kind) {
+		return nil, s.reject(in, FileRejectTypeNotAllowed, "公司不允许上传该类型的文件", map[string]any{"kind": sniff.Kind})
+	}
+
+	if _, err := in.File.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	result, err := s.scanner.Scan(ctx, in.File)
+	if _, seekErr := in.File.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, seekErr
+	}
+	if err != nil {
+		logx.WithContext(ctx).Errorf("[FileInspection] 病毒扫描失败: fileName=%s, err=%v", in.FileName, err)
+		if !s.failOpen {
+			return nil, s.reject(in, FileRejectScanFailed, "文件安全扫描暂不可用，请稍后重试", map[string]any{"error": err.Error()})
+		}
+		return &InspectedFile{Kind: sniff.Kind, MIME: sniff.MIME}, nil
+	}
+	if result.Infected {
+		return nil, s.reject(in, FileRejectInfected, "文件未通过安全扫描", map[string]any{"signature": result.Signature})
+	}
+	return &InspectedFile{Kind: sniff.Kind, MIME: sniff.MIME}, nil
+}
+
+// reject 记录安全日志并返回拦截错误
+func (s *FileInspectionService) reject(in *UploadFile, reason, message string, extra map[string]any) error {
+	if extra == nil {
+		extra = map[string]any{}
+	}
+	extra["reason"] = reason
+	extra["fileName"] = in.FileName
+	extra["fileSize"] = in.Size
+	extra["companyId"] = in.CompanyID
+	if s.securityLogService != nil {
+		s.securityLogService.LogFileRejected(in.IP, in.UserID, in.Path, message, extra)
+	} else {
+		logx.Infof("[FileInspection] 上传文件被拦截: reason=%s, userId=%s, fileName=%s", reason, in.UserID, in.FileName)
+	}
+	return &FileRejectedError{Reason: reason, Message: message}
+}
+
+// SaveRenditions 生成并保存缩略图和预览图，失败时只记录日志，返回空的 Rendition。
+// 保存后文件读取位置重置到开头
+func (s *FileInspectionService) SaveRenditions(ctx context.Context, file io.ReadSeeker, kind, module, relatedID, storageID string) upload.Upload_fileRendition {
+	var rendition upload.Upload_fileRendition
+	if s.previewer == nil {
+		return rendition
+	}
+	defer file.Seek(0, io.SeekStart)
+
+	out, err := s.previewer.Render(ctx, kind, file)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("[FileInspection] 生成预览图失败: fileId=%s, err=%v", storageID, err)
+		return rendition
+	}
+	if out == nil {
+		return rendition
+	}
+
+	thumbPath, thumbURL, err := s.fileStorage.SaveFileFromBytes(module, "thumbnail", relatedID, storageID, "thumbnail.jpg", out.Thumbnail)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("[FileInspection] 保存缩略图失败: fileId=%s, err=%v", storageID, err)
+		return rendition
+	}
+	previewPath, previewURL, err := s.fileStorage.SaveFileFromBytes(module, "preview", relatedID, storageID, "preview.jpg", out.Preview)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("[FileInspection] 保存预览图失败: fileId=%s, err=%v", storageID, err)
+		s.fileStorage.DeleteFile(thumbPath)
+		return rendition
+	}
+	rendition.ThumbnailPath, rendition.ThumbnailURL = thumbPath, thumbURL
+	rendition.PreviewPath, rendition.PreviewURL = previewPath, previewURL
+	return rendition
+}
+
+// DeleteRenditions 删除缩略图和预览图，用于附件记录写入失败时回滚
+func (s *FileInspectionService) DeleteRenditions(rendition upload.Upload_fileRendition) {
+	for _, path := range rendition.Paths() {
+		s.fileStorage.DeleteFile(path)
+	}
+}
+
+// NormalizeFileKinds 校验并去重文件类型，返回规范化后的列表和第一个无效的类型
+func NormalizeFileKinds(kinds []string) ([]string, string) {
+	valid := make(map[string]bool, len(utils.FileKinds))
+	for _, k := range utils.FileKinds {
+		valid[k] = true
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, k := range kinds {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" || seen[k] {
+			continue
+		}
+		if !valid[k] {
+			return nil, k
+		}
+		seen[k] = true
+		out = append(out, k)
+	}
+	return out, ""
+}
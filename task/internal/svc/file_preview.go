@@ -0,0 +1,193 @@
+package svc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"task_Project/task/internal/utils"
+)
+
+// 缩略图和预览图的最长边、编码质量，以及可以解码的最大像素数（防止解压炸弹）
+const (
+	thumbnailMaxSide  = 320
+	previewMaxSide    = 1600
+	renditionQuality  = 82
+	renditionMaxPixel = 50_000_000
+	pdfRenderTimeout  = 30 * time.Second
+)
+
+// FileRenditions 缩略图和预览图，JPEG 编码
+type FileRenditions struct {
+	Thumbnail []byte
+	Preview   []byte
+}
+
+// FilePreviewer 为图片和PDF生成缩略图和预览图。图片使用标准库解码（jpeg/png/gif），
+// PDF 通过外部的 pdftoppm 渲染首页，未配置 pdftoppm 时不生成PDF预览
+type FilePreviewer struct {
+	pdfRenderer string
+}
+
+// NewFilePreviewer 创建预览图生成器，pdfRenderer 为 pdftoppm 可执行文件路径，可为空
+func NewFilePreviewer(pdfRenderer string) *FilePreviewer {
+	return &FilePreviewer{pdfRenderer: pdfRenderer}
+}
+
+// Render 生成缩略图和预览图，不支持的类型和格式返回 nil
+func (p *FilePreviewer) Render(ctx context.Context, kind string, r io.ReadSeeker) (*FileRenditions, error) {
+	var (
+		img image.Image
+		err error
+	)
+	switch kind {
+	case utils.FileKindImage:
+		img, err = decodeImage(r)
+	case utils.FileKindPDF:
+		if p.pdfRenderer == "" {
+			return nil, nil
+		}
+		img, err = p.renderPDF(ctx, r)
+	default:
+		return nil, nil
+	}
+	if err != nil || img == nil {
+		return nil, err
+	}
+
+	thumbnail, err := encodeRendition(img, thumbnailMaxSide)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := encodeRendition(img, previewMaxSide)
+	if err != nil {
+		return nil, err
+	}
+	return &FileRenditions{Thumbnail: thumbnail, Preview: preview}, nil
+}
+
+// decodeImage 解码图片，标准库不支持的格式（webp/bmp/svg/ico）和超大图片返回 nil
+func decodeImage(r io.ReadSeeker) (image.Image, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, nil
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > renditionMaxPixel {
+		return nil, nil
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, nil
+	}
+	return img, nil
+}
+
+// renderPDF 用 pdftoppm 把PDF首页渲染为PNG
+func (p *FilePreviewer) renderPDF(ctx context.Context, r io.ReadSeeker) (image.Image, error) {
+	dir, err := os.MkdirTemp("", "pdf-preview-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "source.pdf")
+	f, err := os.Create(src)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, pdfRenderTimeout)
+	defer cancel()
+	out := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, p.pdfRenderer, "-f", "1", "-l", "1", "-png", "-singlefile",
+		"-scale-to", fmt.Sprint(previewMaxSide), src, out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("渲染PDF首页失败: %v, %s", err, bytes.TrimSpace(output))
+	}
+
+	page, err := os.Open(out + ".png")
+	if err != nil {
+		return nil, err
+	}
+	defer page.Close()
+	return decodeImage(page)
+}
+
+// encodeRendition 按最长边等比缩小（不放大），透明区域填充白色后编码为 JPEG
+func encodeRendition(src image.Image, maxSide int) ([]byte, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			h = max(1, h*maxSide/w)
+			w = maxSide
+		} else {
+			w = max(1, w*maxSide/h)
+			h = maxSide
+		}
+	}
+	dst := scaleImage(src, w, h)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: renditionQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleImage 按区域平均缩放到指定尺寸，叠加在白色背景上
+func scaleImage(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*sh/h
+		y1 := max(y0+1, b.Min.Y+(y+1)*sh/h)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*sw/w
+			x1 := max(x0+1, b.Min.X+(x+1)*sw/w)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// 预乘 alpha 的颜色加上白色背景的剩余部分
+			white := (0xffff*n - a)
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white) / n >> 8),
+				G: uint8((g + white) / n >> 8),
+				B: uint8((bl + white) / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,209 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"task_Project/model/upload"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// fileVersionCleanupBatch 每次按天数清理历史版本时处理的附件数
+const fileVersionCleanupBatch = 200
+
+var (
+	// ErrFileVersionNotFound 版本不存在或已被保留策略清理
+	ErrFileVersionNotFound = errors.New("文件版本不存在或已被清理")
+	// ErrFileVersionConflict 读取附件之后其他请求已经写入了新版本
+	ErrFileVersionConflict = errors.New("附件已被其他人更新，请刷新后重试")
+)
+
+// FileVersionService 附件版本链：上传新版本、恢复历史版本，并按运行时配置清理旧版本。
+// 恢复历史版本不复制存储对象，新版本与原版本共用同一个存储Key（包括缩略图和预览图），
+// 只有不再被任何保留的版本引用的存储对象才会被删除，删除后释放占用的存储配额
+type FileVersionService struct {
+	uploadFileModel     upload.Upload_fileModel
+	fileStorage         FileStorageInterface
+	storageQuotaService *StorageQuotaService
+	systemConfigService *SystemConfigService
+}
+
+// NewFileVersionService 创建附件版本服务
+func NewFileVersionService(uploadFileModel upload.Upload_fileModel, fileStorage FileStorageInterface,
+	storageQuotaService *StorageQuotaService, systemConfigService *SystemConfigService) *FileVersionService {
+	return &FileVersionService{
+		uploadFileModel:     uploadFileModel,
+		fileStorage:         fileStorage,
+		storageQuotaService: storageQuotaService,
+		systemConfigService: systemConfigService,
+	}
+}
+
+// AddVersion 把已保存到文件存储的内容追加为附件的新版本并设为当前版本，返回写入后的附件。
+// 版本号由服务分配；附件在读取后被其他请求修改时返回 ErrFileVersionConflict，调用方负责删除刚保存的存储对象。
+// 新版本占用的存储配额由调用方在保存文件前占用
+func (s *FileVersionService) AddVersion(ctx context.Context, file *upload.Upload_file, v upload.Upload_fileVersion) (*upload.Upload_file, error) {
+	versions := append([]upload.Upload_fileVersion(nil), file.VersionList()...)
+	v.Version = versions[len(versions)-1].Version + 1
+	v.CreateAt = time.Now()
+	versions = append(versions, v)
+
+	keep := s.systemConfigService.GetInt(SettingUploadVersionKeep, 20)
+	if keep < 1 {
+		keep = 1
+	}
+	var removed []upload.Upload_fileVersion
+	if len(versions) > keep {
+		removed = versions[:len(versions)-keep]
+		versions = versions[len(versions)-keep:]
+	}
+
+	ok, err := s.uploadFileModel.ReplaceVersions(ctx, file.FileID, file.Version, v, versions)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrFileVersionConflict
+	}
+	s.deleteUnreferenced(ctx, file, removed, versions)
+
+	updated := *file
+	updated.FileName = v.FileName
+	updated.FilePath = v.FilePath
+	updated.FileURL = v.FileURL
+	updated.FileType = v.FileType
+	updated.FileSize = v.FileSize
+	updated.MimeType = v.MimeType
+	updated.Upload_fileRendition = v.Upload_fileRendition
+	updated.Version = v.Version
+	updated.Versions = versions
+	updated.UpdateAt = v.CreateAt
+	return &updated, nil
+}
+
+// Restore 以历史版本的内容创建新版本，历史版本本身保持不变
+func (s *FileVersionService) Restore(ctx context.Context, file *upload.Upload_file, version int64, uploaderID, changeNote string) (*upload.Upload_file, error) {
+	old, ok := file.FindVersion(version)
+	if !ok {
+		return nil, ErrFileVersionNotFound
+	}
+	if version == file.CurrentVersion() {
+		return file, nil
+	}
+	return s.AddVersion(ctx, file, upload.Upload_fileVersion{
+		FileName:     old.FileName,
+		FilePath:     old.FilePath,
+		FileURL:      old.FileURL,
+		FileType:     old.FileType,
+		FileSize:     old.FileSize,
+		MimeType:     old.MimeType,
+		UploaderID:   uploaderID,
+		ChangeNote:   changeNote,
+		RestoredFrom: old.Version,
+
+		Upload_fileRendition: old.Upload_fileRendition,
+	})
+}
+
+// Remove 删除附件全部版本的存储对象并释放占用的存储配额，附件记录由调用方删除
+func (s *FileVersionService) Remove(ctx context.Context, file *upload.Upload_file) error {
+	var firstErr error
+	for _, path := range versionPaths(file.VersionList()) {
+		if err := s.fileStorage.DeleteFile(path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.storageQuotaService.ReleaseFile(ctx, file)
+	return firstErr
+}
+
+// Cleanup 删除超过保留天数的历史版本，当前版本始终保留；保留天数为 0 时不按天数清理。
+// 返回清理的版本数
+func (s *FileVersionService) Cleanup(ctx context.Context) (int, error) {
+	days := s.systemConfigService.GetInt(SettingUploadVersionDays, 0)
+	if days <= 0 {
+		return 0, nil
+	}
+	before := time.Now().AddDate(0, 0, -days)
+	files, err := s.uploadFileModel.FindWithVersionsBefore(ctx, before, fileVersionCleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		current := file.CurrentVersion()
+		var keep, expired []upload.Upload_fileVersion
+		for _, v := range file.Versions {
+			if v.Version == current || !v.CreateAt.Before(before) {
+				keep = append(keep, v)
+			} else {
+				expired = append(expired, v)
+			}
+		}
+		if len(expired) == 0 || len(keep) == 0 {
+			continue
+		}
+		cur, _ := file.FindVersion(current)
+		ok, err := s.uploadFileModel.ReplaceVersions(ctx, file.FileID, file.Version, cur, keep)
+		if err != nil {
+			logx.Errorf("[FileVersion] 清理历史版本失败: fileId=%s, err=%v", file.FileID, err)
+			continue
+		}
+		if !ok {
+			// 清理期间上传了新版本，下次清理时再处理
+			continue
+		}
+		s.deleteUnreferenced(ctx, file, expired, keep)
+		removed += len(expired)
+	}
+	return removed, nil
+}
+
+// deleteUnreferenced 删除被移除的版本中不再被保留版本引用的存储对象，并释放这些文件占用的存储配额
+func (s *FileVersionService) deleteUnreferenced(ctx context.Context, file *upload.Upload_file, removed, kept []upload.Upload_fileVersion) {
+	if len(removed) == 0 {
+		return
+	}
+	inUse := make(map[string]bool, len(kept))
+	for _, path := range versionPaths(kept) {
+		inUse[path] = true
+	}
+	for _, path := range versionPaths(removed) {
+		if inUse[path] {
+			continue
+		}
+		if err := s.fileStorage.DeleteFile(path); err != nil {
+			logx.Errorf("[FileVersion] 删除历史版本文件失败: path=%s, err=%v", path, err)
+		}
+	}
+
+	if !file.StorageAccounted() {
+		return
+	}
+	var released []upload.Upload_fileVersion
+	for _, v := range upload.StorageObjects(removed) {
+		if !inUse[v.FilePath] {
+			released = append(released, v)
+		}
+	}
+	s.storageQuotaService.ReleaseObjects(ctx, released)
+}
+
+// versionPaths 版本引用的存储Key（包括缩略图和预览图），去重并忽略空Key
+func versionPaths(versions []upload.Upload_fileVersion) []string {
+	seen := make(map[string]bool, len(versions))
+	paths := make([]string, 0, len(versions))
+	for _, v := range versions {
+		for _, path := range append([]string{v.FilePath}, v.Paths()...) {
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
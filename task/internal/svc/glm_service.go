@@ -83,6 +83,11 @@ type EmployeeCandidate struct {
 	ActiveTasks    int      `json:"activeTasks"`
 	CompletedTasks int      `json:"completedTasks"`
 	AvgCompletion  float64  `json:"avgCompletionRate"` // 平均完成率
+	WeeklyHours    float64  `json:"weeklyHours"`       // 每周可用工时
+	AvailableHours float64  `json:"availableHours"`    // 节点时间窗口内的可用工时
+	AssignedHours  float64  `json:"assignedHours"`     // 节点时间窗口内已分配的工作量
+	Utilization    float64  `json:"utilization"`       // 承接该节点后的利用率（百分比）
+	Overloaded     bool     `json:"overloaded"`        // 承接该节点后是否超负荷
 }
 
 // TaskNodeInfo 任务节点信息
@@ -156,7 +161,7 @@ func (s *GLMService) buildDispatchPrompt(taskNode TaskNodeInfo, candidates []Emp
 
 ## 评估标准
 1. 技能匹配度：员工技能与任务需求的匹配程度
-2. 工作负载：当前活跃任务数量，避免过度分配
+2. 工作负载：当前活跃任务数量和承接后的工时利用率（utilization），避免过度分配，overloaded 为 true 的员工尽量不推荐
 3. 历史表现：已完成任务数量和平均完成率
 4. 经验资历：任职时长
 5. 任务优先级：高优先级任务需要更有经验的员工
@@ -0,0 +1,318 @@
+package svc
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// ErrImportFormat 不支持的导入文件格式
+var ErrImportFormat = errors.New("仅支持 CSV 和 XLSX 格式的文件")
+
+const (
+	// XLSX 中单个 XML 部件（工作表、共享字符串等）解压后的最大读取长度
+	xlsxPartLimit = 64 << 20
+	// Excel 工作表的最大行数和列数，文件中声明的行号、列号超出时拒绝，避免按声明补齐空行时占用大量内存
+	xlsxMaxRows = 1048576
+	xlsxMaxCols = 16384
+)
+
+// ImportFormat 根据文件名判断导入文件格式，不支持时返回空字符串
+func ImportFormat(fileName string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return "csv"
+	case ".xlsx":
+		return "xlsx"
+	}
+	return ""
+}
+
+// ParseImportFile 解析上传的 CSV/XLSX 文件，返回第一个工作表的所有行（第一行为表头），去掉末尾的空行。
+// maxRows 为允许的最大数据行数，XLSX 中行号超出表头加 maxRows 的非空行直接拒绝
+func ParseImportFile(fileName string, data []byte, maxRows int) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch ImportFormat(fileName) {
+	case "csv":
+		rows, err = parseImportCSV(data)
+	case "xlsx":
+		rows, err = parseImportXLSX(data, maxRows)
+	default:
+		return nil, ErrImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	for len(rows) > 0 && isBlankRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportCSV 解析 CSV，兼容 UTF-8 BOM 和 Excel 在中文系统下另存的 GBK 编码
+func parseImportCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	if !utf8.Valid(data) {
+		decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("无法识别文件编码，请另存为 UTF-8 编码的 CSV")
+		}
+		data = decoded
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV 解析失败: %v", err)
+	}
+	return rows, nil
+}
+
+// parseImportXLSX 解析 XLSX 的第一个工作表，支持共享字符串、内联字符串和数值单元格
+func parseImportXLSX(data []byte, maxRows int) ([][]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("XLSX 文件已损坏或不是有效的 Excel 文件")
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		files[f.Name] = f
+	}
+
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if sharedStrings, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet := firstWorksheet(files)
+	if sheet == nil {
+		return nil, fmt.Errorf("XLSX 文件中没有工作表")
+	}
+	return readWorksheet(sheet, sharedStrings, maxRows)
+}
+
+// firstWorksheet 按工作簿中的顺序找到第一个工作表
+func firstWorksheet(files map[string]*zip.File) *zip.File {
+	if sheet := resolveFirstSheet(files); sheet != nil {
+		return sheet
+	}
+	// 工作簿关系解析失败时退回到按文件名排序
+	names := make([]string, 0)
+	for name := range files {
+		if strings.HasPrefix(name, "xl/worksheets/") && strings.HasSuffix(name, ".xml") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return files[names[0]]
+}
+
+func resolveFirstSheet(files map[string]*zip.File) *zip.File {
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, ok2 := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !ok2 {
+		return nil
+	}
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if decodeZipXML(workbookFile, &workbook) != nil || decodeZipXML(relsFile, &rels) != nil || len(workbook.Sheets) == 0 {
+		return nil
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		return files[target]
+	}
+	return nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(io.LimitReader(rc, xlsxPartLimit)).Decode(v)
+}
+
+// xlsxText 富文本由多段 <r><t> 组成，普通文本只有一个 <t>
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := decodeZipXML(f, &sst); err != nil {
+		return nil, fmt.Errorf("XLSX 共享字符串解析失败: %v", err)
+	}
+	result := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		result[i] = item.String()
+	}
+	return result, nil
+}
+
+func readWorksheet(f *zip.File, sharedStrings []string, maxRows int) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, xlsxPartLimit)).Decode(&sheet); err != nil {
+		return nil, fmt.Errorf("XLSX 工作表解析失败: %v", err)
+	}
+
+	// 表头一行加最多 maxRows 行数据
+	rowLimit := xlsxMaxRows
+	if maxRows > 0 && maxRows+1 < rowLimit {
+		rowLimit = maxRows + 1
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		// 空行不会写入 sheetData，按行号补齐以保证报告中的行号与 Excel 一致
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = len(rows) + 1
+		}
+		if rowNum < 0 || rowNum > rowLimit {
+			// 只有格式没有单元格的行不影响导入，忽略
+			if len(row.Cells) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("XLSX 数据超过 %d 行", rowLimit-1)
+		}
+		for len(rows) < rowNum-1 {
+			rows = append(rows, nil)
+		}
+		values := make([]string, 0, len(row.Cells))
+		for j, cell := range row.Cells {
+			col := columnIndex(cell.Ref)
+			if col < 0 {
+				col = j
+			}
+			if col >= xlsxMaxCols {
+				return nil, fmt.Errorf("XLSX 第 %d 行的列数超过 %d 列", rowNum, xlsxMaxCols)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || idx < 0 || idx >= len(sharedStrings) {
+					return nil, fmt.Errorf("XLSX 第 %d 行单元格引用了不存在的字符串", i+1)
+				}
+				value = sharedStrings[idx]
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				if cell.Value == "1" {
+					value = "是"
+				} else {
+					value = "否"
+				}
+			default:
+				value = cell.Value
+			}
+			if col < len(values) {
+				values[col] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// columnIndex 将单元格引用（如 "AB12"）转换为从 0 开始的列号
+func columnIndex(ref string) int {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+		// 超出 Excel 最大列数后不再累加，避免超长的列名溢出
+		if col > xlsxMaxCols {
+			return xlsxMaxCols
+		}
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
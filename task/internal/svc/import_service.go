@@ -0,0 +1,244 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"task_Project/model/task"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 导入内容
+const (
+	ImportTypeDepartment = "department" // 部门
+	ImportTypePosition   = "position"   // 职位
+	ImportTypeEmployee   = "employee"   // 员工
+	ImportTypeTask       = "task"       // 任务及节点（从其他工具迁移项目）
+)
+
+// importTypeTitles 导入内容的中文名称
+var importTypeTitles = map[string]string{
+	ImportTypeDepartment: "部门",
+	ImportTypePosition:   "职位",
+	ImportTypeEmployee:   "员工",
+	ImportTypeTask:       "任务",
+}
+
+// 同时执行的导入任务数、单个导入的超时时间和每个事务写入的记录数
+const (
+	importConcurrency = 2
+	importTimeout     = 30 * time.Minute
+	importBatchSize   = 50
+	// 保存导入结果和发送通知使用独立的超时，导入超时后仍能记录中断原因
+	importFinishTimeout = 30 * time.Second
+)
+
+// ErrImportRunning 导入任务已在执行或已完成
+var ErrImportRunning = errors.New("导入任务正在执行或已完成")
+
+// ImportTypeTitle 导入内容的中文名称，未知类型返回空字符串
+func ImportTypeTitle(importType string) string {
+	return importTypeTitles[importType]
+}
+
+// ImportRecord 一条待导入的记录及其校验结果
+type ImportRecord struct {
+	Line   int               `json:"line"`             // 文件中的行号（表头为第 1 行）
+	ID     string            `json:"id"`               // 预先分配的记录ID，继续导入时保持不变
+	Values map[string]string `json:"values"`           // 按列标识整理后的单元格内容
+	Refs   map[string]string `json:"refs,omitempty"`   // 校验时解析出的关联ID（部门、职位、上级等）
+	Nodes  []*ImportRecord   `json:"nodes,omitempty"`  // 任务导入时该任务下的节点
+	Errors []string          `json:"errors,omitempty"` // 校验错误
+}
+
+// Ref 读取关联ID
+func (r *ImportRecord) Ref(key string) string {
+	return r.Refs[key]
+}
+
+// SetRef 写入关联ID，空值不写入
+func (r *ImportRecord) SetRef(key, value string) {
+	if value == "" {
+		return
+	}
+	if r.Refs == nil {
+		r.Refs = make(map[string]string)
+	}
+	r.Refs[key] = value
+}
+
+// AddError 记录校验错误
+func (r *ImportRecord) AddError(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// ErrorCount 统计记录（含节点）的校验错误数
+func (r *ImportRecord) ErrorCount() int {
+	count := len(r.Errors)
+	for _, node := range r.Nodes {
+		count += len(node.Errors)
+	}
+	return count
+}
+
+// EncodeImportRecords 序列化记录用于保存到导入任务
+func EncodeImportRecords(records []*ImportRecord) (string, error) {
+	data, err := json.Marshal(records)
+	return string(data), err
+}
+
+// DecodeImportRecords 读取导入任务中保存的记录
+func DecodeImportRecords(data string) ([]*ImportRecord, error) {
+	var records []*ImportRecord
+	if data == "" {
+		return records, nil
+	}
+	err := json.Unmarshal([]byte(data), &records)
+	return records, err
+}
+
+// ImportApplier 将记录写入数据库
+type ImportApplier interface {
+	// Apply 在事务中写入一条记录，返回错误时该批次整体回滚
+	Apply(ctx context.Context, session sqlx.Session, record *ImportRecord) error
+	// Finish 全部记录写入后执行（发送通知等），只在导入完成时调用一次
+	Finish(ctx context.Context, records []*ImportRecord)
+}
+
+// ImportService 批量导入服务：按批次在事务中写入记录，断点与数据在同一事务提交，中断后可以从断点继续
+type ImportService struct {
+	importJobModel        task.ImportJobModel
+	transactionService    *TransactionService
+	transactionHelper     *TransactionHelper
+	notificationMQService *NotificationMQService
+	systemConfigService   *SystemConfigService
+	slots                 chan struct{}
+}
+
+// NewImportService 创建批量导入服务
+func NewImportService(importJobModel task.ImportJobModel, transactionService *TransactionService, transactionHelper *TransactionHelper,
+	notificationMQService *NotificationMQService, systemConfigService *SystemConfigService) *ImportService {
+	return &ImportService{
+		importJobModel:        importJobModel,
+		transactionService:    transactionService,
+		transactionHelper:     transactionHelper,
+		notificationMQService: notificationMQService,
+		systemConfigService:   systemConfigService,
+		slots:                 make(chan struct{}, importConcurrency),
+	}
+}
+
+// MaxRows 单个文件允许导入的最大数据行数
+func (s *ImportService) MaxRows() int {
+	return s.systemConfigService.GetInt(SettingImportMaxRows, 5000)
+}
+
+// Start 从断点开始在后台写入记录；任务已在执行时返回 ErrImportRunning
+func (s *ImportService) Start(ctx context.Context, job *task.ImportJob, records []*ImportRecord, applier ImportApplier) error {
+	ok, err := s.importJobModel.MarkRunning(ctx, job.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrImportRunning
+	}
+	job.Status = task.ImportStatusRunning
+	go s.run(job, records, applier)
+	return nil
+}
+
+// run 排队等待空闲的导入槽位后分批写入
+func (s *ImportService) run(job *task.ImportJob, records []*ImportRecord, applier ImportApplier) {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf("[Import] 导入任务异常: jobId=%s, panic=%v", job.Id, r)
+			s.interrupt(job, "导入过程中发生内部错误，请稍后继续导入")
+		}
+	}()
+
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+	begin := time.Now()
+
+	for start := int(job.AppliedRows); start < len(records); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		failed := -1
+		err := s.transactionService.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+			for i := start; i < end; i++ {
+				if err := applier.Apply(ctx, session, records[i]); err != nil {
+					failed = i
+					return err
+				}
+			}
+			return s.transactionHelper.GetImportJobModelWithSession(session).UpdateProgress(ctx, job.Id, int64(end))
+		})
+		if err != nil {
+			message := fmt.Sprintf("第 %d-%d 条记录写入失败，已回滚该批次，请稍后继续导入", start+1, end)
+			if failed >= 0 {
+				logx.Errorf("[Import] 写入记录失败: jobId=%s, line=%d, err=%v", job.Id, records[failed].Line, err)
+				message = fmt.Sprintf("第 %d 行写入失败，已回滚该批次（第 %d-%d 条记录），请检查后继续导入", records[failed].Line, start+1, end)
+			} else {
+				logx.Errorf("[Import] 提交导入批次失败: jobId=%s, err=%v", job.Id, err)
+			}
+			s.interrupt(job, message)
+			return
+		}
+		job.AppliedRows = int64(end)
+	}
+
+	ctx, cancelFinish := context.WithTimeout(context.Background(), importFinishTimeout)
+	defer cancelFinish()
+	if err := s.importJobModel.Finish(ctx, job.Id, task.ImportStatusDone, ""); err != nil {
+		logx.Errorf("[Import] 保存导入结果失败: jobId=%s, err=%v", job.Id, err)
+	}
+	job.Status = task.ImportStatusDone
+	applier.Finish(ctx, records)
+	logx.Infof("[Import] 导入完成: jobId=%s, type=%s, records=%d, 耗时 %v", job.Id, job.ImportType, len(records), time.Since(begin))
+	s.notify(ctx, ImportCompleted, job, fmt.Sprintf("%s导入已完成（%s），共写入 %d 条记录。", ImportTypeTitle(job.ImportType), job.FileName, len(records)))
+}
+
+// interrupt 记录中断原因并通知发起人，已提交的批次保留。
+// 批次可能因导入超时而失败，这里使用新的上下文，避免中断原因写入失败导致任务一直处于导入中
+func (s *ImportService) interrupt(job *task.ImportJob, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), importFinishTimeout)
+	defer cancel()
+	job.Status = task.ImportStatusInterrupted
+	if err := s.importJobModel.Finish(ctx, job.Id, task.ImportStatusInterrupted, message); err != nil {
+		logx.Errorf("[Import] 保存中断原因失败: jobId=%s, err=%v", job.Id, err)
+	}
+	s.notify(ctx, ImportInterrupted, job, fmt.Sprintf("%s导入（%s）已中断：%s", ImportTypeTitle(job.ImportType), job.FileName, message))
+}
+
+func (s *ImportService) notify(ctx context.Context, eventType string, job *task.ImportJob, content string) {
+	if s.notificationMQService == nil {
+		return
+	}
+	event := s.notificationMQService.NewNotificationEvent(eventType, []string{job.EmployeeId}, job.Id)
+	event.Content = content
+	if err := s.notificationMQService.PublishNotificationEvent(ctx, event); err != nil {
+		logx.WithContext(ctx).Errorf("[Import] 发布通知失败: jobId=%s, err=%v", job.Id, err)
+	}
+}
+
+// InterruptRunning 服务启动时将上次未完成的导入任务标记为已中断，发起人可以从断点继续
+func (s *ImportService) InterruptRunning(ctx context.Context) {
+	count, err := s.importJobModel.InterruptRunning(ctx, "服务重启，导入已中断，可以从断点继续导入")
+	if err != nil {
+		logx.Errorf("[Import] 标记中断的导入任务失败: %v", err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("[Import] 已将 %d 个未完成的导入任务标记为已中断", count)
+	}
+}
@@ -30,16 +30,35 @@ const (
 	TaskDeadlineReminder       = "task.deadline.reminder"
 	TaskSlowProgress           = "task.slow.progress"
 	TaskNodeExecutorLeft       = "task.node.executor.left"
+	TaskCommentCreated         = "task.comment.created"     // 任务或节点有新评论，通知关注者
+	TaskNodeStatusChanged      = "task.node.status.changed" // 节点状态变更，通知关注者
+	TaskChecklistChanged       = "task.checklist.changed"   // 清单完成状态变更，通知关注者
 
 	// 员工相关
 	EmployeeCreated = "employee.created"
 	EmployeeLeave   = "employee.leave"
 
+	// 外出代理相关
+	EmployeeOutOfOfficeStart   = "employee.outofoffice.start"   // 外出开始，通知代理人
+	EmployeeOutOfOfficeSummary = "employee.outofoffice.summary" // 外出结束，向本人发送事项汇总
+
+	// 工时单相关
+	TimesheetSubmitted = "timesheet.submitted" // 工时单提交，通知审批人
+	TimesheetReviewed  = "timesheet.reviewed"  // 工时单审批完成，通知提交人
+
 	// 部门相关
 	DepartmentCreated = "department.created"
 
 	// 交接相关
 	HandoverNotification = "handover.notification"
+
+	// 报表导出相关
+	ExportReady  = "export.ready"  // 导出文件已生成，可以下载
+	ExportFailed = "export.failed" // 导出失败
+
+	// 批量导入相关
+	ImportCompleted   = "import.completed"   // 导入完成
+	ImportInterrupted = "import.interrupted" // 导入中断，可以继续导入
 )
 
 // NotificationEvent 通知事件消息结构
@@ -56,6 +75,16 @@ type NotificationEvent struct {
 	// 业务相关字段（用于查询需要通知的员工）
 	TaskID string `json:"taskId"` // 任务ID（用于查询任务相关人员）
 	NodeID string `json:"nodeId"` // 节点ID（用于查询节点相关人员）
+	// ExcludeEmployeeIDs 不通知的关注者，如操作人本人和已单独通知过的员工
+	ExcludeEmployeeIDs []string `json:"excludeEmployeeIds,omitempty"`
+}
+
+// watchedEvents 同时通知任务和节点关注者的变更事件
+var watchedEvents = map[string]bool{
+	TaskCreated: true, TaskUpdated: true, TaskDeleted: true, TaskCompleted: true,
+	TaskNodeCreated: true, TaskNodeDeleted: true, TaskNodeCompleted: true,
+	TaskNodeExecutorChanged: true, TaskNodeExecutorLeft: true, TaskCommentCreated: true,
+	TaskNodeStatusChanged: true, TaskChecklistChanged: true, HandoverNotification: true,
 }
 
 // NotificationMQService 通知消息队列服务（用于发布通知事件）
@@ -170,13 +199,9 @@ func handleNotificationMessage(msg amqp.Delivery, svcCtx *ServiceContext) {
 
 	logx.Infof("[NotificationMQ Consumer] Parsed event: eventType=%s, employeeIds=%v, relatedId=%s", event.EventType, event.EmployeeIDs, event.RelatedID)
 
-	// 如果 EmployeeIDs 为空，根据业务ID查询需要通知的员工
-	employeeIDs := event.EmployeeIDs
-	if len(employeeIDs) == 0 {
-		logx.Infof("[NotificationMQ Consumer] EmployeeIDs is empty, resolving recipients...")
-		employeeIDs = resolveNotificationRecipients(ctx, svcCtx, &event)
-		logx.Infof("[NotificationMQ Consumer] Resolved %d recipients", len(employeeIDs))
-	}
+	// 解析需要通知的员工：EmployeeIDs 为空时根据业务ID查询，变更事件再加上关注者
+	employeeIDs, watcherOnly := resolveNotificationRecipients(ctx, svcCtx, &event)
+	logx.Infof("[NotificationMQ Consumer] Resolved %d recipients, %d of them watchers", len(employeeIDs), len(watcherOnly))
 
 	// 如果 Title 或 Content 为空，根据事件类型生成
 	if event.Title == "" || event.Content == "" {
@@ -190,10 +215,24 @@ func handleNotificationMessage(msg amqp.Delivery, svcCtx *ServiceContext) {
 		}
 		logx.Infof("[NotificationMQ Consumer] Generated title=%s, content=%s", event.Title, event.Content)
 	}
+	var watcherEvent NotificationEvent
+	if len(watcherOnly) > 0 {
+		watcherEvent = watcherNotification(ctx, svcCtx, &event)
+	}
 
 	// 为每个员工创建通知
+	// 收件人处于外出期间时，同时抄送一份给其代理人（本人保留原通知，便于返回后查看）
+	recipientSet := make(map[string]bool, len(employeeIDs))
+	for _, id := range employeeIDs {
+		recipientSet[id] = true
+	}
 	successCount := 0
 	for _, employeeID := range employeeIDs {
+		// 仅因关注而收到的通知使用关注者版本的标题和内容
+		e := &event
+		if watcherOnly[employeeID] {
+			e = &watcherEvent
+		}
 		// 确保使用员工主键 Id（通知表使用员工主键存储）
 		actualEmployeeID := employeeID
 		// 验证员工是否存在
@@ -206,23 +245,7 @@ func handleNotificationMessage(msg amqp.Delivery, svcCtx *ServiceContext) {
 		actualEmployeeID = emp.Id
 		logx.Infof("[NotificationMQ Consumer] Creating notification for employee: %s (ID: %s)", emp.RealName, actualEmployeeID)
 
-		notification := &user_auth.Notification{
-			Id:          utils.Common.GenId("notification"),
-			EmployeeId:  actualEmployeeID,
-			Title:       event.Title,
-			Content:     event.Content,
-			Type:        int64(event.Type),
-			Category:    sql.NullString{String: event.Category, Valid: event.Category != ""},
-			IsRead:      0,
-			ReadTime:    sql.NullTime{}, // 未读时为空
-			Priority:    int64(event.Priority),
-			SenderId:    sql.NullString{String: "system", Valid: true}, // 系统发送
-			RelatedId:   sql.NullString{String: event.RelatedID, Valid: event.RelatedID != ""},
-			RelatedType: sql.NullString{String: event.RelatedType, Valid: event.RelatedType != ""},
-			CreateTime:  time.Now(),
-			UpdateTime:  time.Now(),
-		}
-
+		notification := newNotificationRecord(actualEmployeeID, e, e.Title)
 		_, insertErr := svcCtx.NotificationModel.Insert(ctx, notification)
 		if insertErr != nil {
 			logx.Errorf("[NotificationMQ Consumer] Failed to create notification for employee %s: %v", actualEmployeeID, insertErr)
@@ -230,6 +253,19 @@ func handleNotificationMessage(msg amqp.Delivery, svcCtx *ServiceContext) {
 		}
 		logx.Infof("[NotificationMQ Consumer] Created notification for employee %s, notificationId=%s", actualEmployeeID, notification.Id)
 		successCount++
+
+		// 外出代理抄送
+		delegate, ok := svcCtx.OutOfOfficeService.RouteNotification(ctx, actualEmployeeID)
+		if !ok || recipientSet[delegate.Id] {
+			continue
+		}
+		recipientSet[delegate.Id] = true
+		delegateNotification := newNotificationRecord(delegate.Id, e, fmt.Sprintf("【代%s】%s", emp.RealName, e.Title))
+		if _, err := svcCtx.NotificationModel.Insert(ctx, delegateNotification); err != nil {
+			logx.Errorf("[NotificationMQ Consumer] Failed to create delegate notification for employee %s: %v", delegate.Id, err)
+			continue
+		}
+		logx.Infof("[NotificationMQ Consumer] Routed notification of %s to delegate %s, notificationId=%s", actualEmployeeID, delegate.Id, delegateNotification.Id)
 	}
 
 	logx.Infof("[NotificationMQ Consumer] Created %d/%d notifications for event: %s", successCount, len(employeeIDs), event.EventType)
@@ -238,8 +274,115 @@ func handleNotificationMessage(msg amqp.Delivery, svcCtx *ServiceContext) {
 	msg.Ack(false)
 }
 
-// resolveNotificationRecipients 根据业务ID解析需要通知的员工
-func resolveNotificationRecipients(ctx context.Context, svcCtx *ServiceContext, event *NotificationEvent) []string {
+// newNotificationRecord 根据通知事件构造通知记录
+func newNotificationRecord(employeeID string, event *NotificationEvent, title string) *user_auth.Notification {
+	return &user_auth.Notification{
+		Id:          utils.Common.GenId("notification"),
+		EmployeeId:  employeeID,
+		Title:       title,
+		Content:     event.Content,
+		Type:        int64(event.Type),
+		Category:    sql.NullString{String: event.Category, Valid: event.Category != ""},
+		IsRead:      0,
+		ReadTime:    sql.NullTime{}, // 未读时为空
+		Priority:    int64(event.Priority),
+		SenderId:    sql.NullString{String: "system", Valid: true}, // 系统发送
+		RelatedId:   sql.NullString{String: event.RelatedID, Valid: event.RelatedID != ""},
+		RelatedType: sql.NullString{String: event.RelatedType, Valid: event.RelatedType != ""},
+		CreateTime:  time.Now(),
+		UpdateTime:  time.Now(),
+	}
+}
+
+// resolveNotificationRecipients 解析需要通知的员工：事件指定了员工时使用指定的员工，否则根据业务ID查询；
+// 变更事件再加上任务和节点的关注者（排除 ExcludeEmployeeIDs）。watcherOnly 为仅因关注而收到通知的员工
+func resolveNotificationRecipients(ctx context.Context, svcCtx *ServiceContext, event *NotificationEvent) (employeeIDs []string, watcherOnly map[string]bool) {
+	employeeIDs = append(employeeIDs, event.EmployeeIDs...)
+	if len(employeeIDs) == 0 {
+		employeeIDs = resolveAssignedRecipients(ctx, svcCtx, event)
+	}
+	watcherOnly = make(map[string]bool)
+	if !watchedEvents[event.EventType] {
+		return employeeIDs, watcherOnly
+	}
+
+	skip := make(map[string]bool, len(employeeIDs)+len(event.ExcludeEmployeeIDs))
+	for _, id := range employeeIDs {
+		skip[id] = true
+	}
+	for _, id := range event.ExcludeEmployeeIDs {
+		skip[id] = true
+	}
+	for _, id := range resolveWatchers(ctx, svcCtx, event) {
+		if skip[id] {
+			continue
+		}
+		skip[id] = true
+		watcherOnly[id] = true
+		employeeIDs = append(employeeIDs, id)
+	}
+	return employeeIDs, watcherOnly
+}
+
+// resolveWatchers 事件对应任务或节点的关注者：节点事件为该节点和整个任务的关注者，任务事件为任务及其全部节点的关注者
+func resolveWatchers(ctx context.Context, svcCtx *ServiceContext, event *NotificationEvent) []string {
+	taskID := event.TaskID
+	if taskID == "" && event.NodeID != "" {
+		node, err := svcCtx.TaskNodeModel.FindOne(ctx, event.NodeID)
+		if err != nil {
+			logx.Errorf("[NotificationMQ Consumer] Failed to find node %s for watchers: %v", event.NodeID, err)
+			return nil
+		}
+		taskID = node.TaskId
+	}
+	if taskID == "" {
+		return nil
+	}
+	// 被分配到任务或节点的员工自动关注，分配关系由模型维护，发送前按当前分配补齐关注记录
+	if err := svcCtx.TaskWatcherModel.WatchAssignees(ctx, taskID); err != nil {
+		logx.Errorf("[NotificationMQ Consumer] Failed to watch assignees of task %s: %v", taskID, err)
+	}
+	ids, err := svcCtx.TaskWatcherModel.FindRecipientIds(ctx, taskID, event.NodeID)
+	if err != nil {
+		logx.Errorf("[NotificationMQ Consumer] Failed to find watchers of task %s: %v", taskID, err)
+		return nil
+	}
+	return ids
+}
+
+// watcherNotification 发给关注者的通知：标题加【关注】前缀，原内容是写给被分配人的事件改为中性描述
+func watcherNotification(ctx context.Context, svcCtx *ServiceContext, event *NotificationEvent) NotificationEvent {
+	w := *event
+	w.Title = "【关注】" + event.Title
+	switch event.EventType {
+	case TaskCreated:
+		if taskInfo, err := svcCtx.TaskModel.FindOne(ctx, event.TaskID); err == nil {
+			w.Content = fmt.Sprintf("您关注的任务 %s 已创建", taskInfo.TaskTitle)
+		}
+	case TaskNodeCreated:
+		w.Content = "您关注的任务新增了节点"
+		if event.NodeID != "" {
+			if node, err := svcCtx.TaskNodeModel.FindOne(ctx, event.NodeID); err == nil {
+				w.Content = fmt.Sprintf("您关注的任务新增了节点 %s", node.NodeName)
+			}
+		} else if taskInfo, err := svcCtx.TaskModel.FindOne(ctx, event.TaskID); err == nil {
+			w.Content = fmt.Sprintf("您关注的任务 %s 新增了节点", taskInfo.TaskTitle)
+		}
+	case TaskNodeExecutorChanged:
+		if node, err := svcCtx.TaskNodeModel.FindOne(ctx, event.NodeID); err == nil {
+			w.Content = fmt.Sprintf("您关注的任务节点 %s 更换了执行人", node.NodeName)
+		}
+	case HandoverNotification:
+		// 交接通知正文是写给交接双方的，关注者只提示任务有交接动态
+		if taskInfo, err := svcCtx.TaskModel.FindOne(ctx, event.TaskID); err == nil {
+			w.Content = fmt.Sprintf("您关注的任务 %s 有新的交接动态", taskInfo.TaskTitle)
+		}
+	}
+	return w
+}
+
+// resolveAssignedRecipients 根据业务ID解析需要通知的任务和节点相关人员
+func resolveAssignedRecipients(ctx context.Context, svcCtx *ServiceContext, event *NotificationEvent) []string {
 	employeeIDSet := make(map[string]bool)
 
 	switch event.EventType {
@@ -526,12 +669,18 @@ type NotificationEventOptions struct {
 func (s *NotificationMQService) NewNotificationEvent(eventType string, employeeIds []string, relatedID string, extras ...NotificationEventOptions) *NotificationEvent {
 	var category string
 	switch eventType {
-	case EmployeeLeave:
+	case EmployeeLeave, EmployeeOutOfOfficeStart, EmployeeOutOfOfficeSummary:
 		category = "employee"
 	case HandoverNotification:
 		category = "handover"
 	case TaskNodeCompletionApproval:
 		category = "task_approval"
+	case TimesheetSubmitted, TimesheetReviewed:
+		category = "timesheet"
+	case ExportReady, ExportFailed:
+		category = "export"
+	case ImportCompleted, ImportInterrupted:
+		category = "import"
 	default:
 		category = "task"
 	}
@@ -585,8 +734,24 @@ func (s *NotificationMQService) NewNotificationEvent(eventType string, employeeI
 		title = "任务节点执行人离职通知"
 	case EmployeeLeave:
 		title = "员工离职通知"
+	case EmployeeOutOfOfficeStart:
+		title = "外出代理提醒"
+	case EmployeeOutOfOfficeSummary:
+		title = "外出期间事项汇总"
 	case HandoverNotification:
 		title = "任务交接通知"
+	case TimesheetSubmitted:
+		title = "工时单待审批"
+	case TimesheetReviewed:
+		title = "工时单审批结果"
+	case ExportReady:
+		title = "报表导出完成"
+	case ExportFailed:
+		title = "报表导出失败"
+	case ImportCompleted:
+		title = "批量导入完成"
+	case ImportInterrupted:
+		title = "批量导入中断"
 	default:
 		title = "系统通知"
 	}
@@ -602,6 +767,14 @@ func (s *NotificationMQService) NewNotificationEvent(eventType string, employeeI
 		relatedType = "handover"
 	case EmployeeLeave:
 		relatedType = "employee"
+	case EmployeeOutOfOfficeStart, EmployeeOutOfOfficeSummary:
+		relatedType = "out_of_office"
+	case TimesheetSubmitted, TimesheetReviewed:
+		relatedType = "timesheet"
+	case ExportReady, ExportFailed:
+		relatedType = "export"
+	case ImportCompleted, ImportInterrupted:
+		relatedType = "import"
 	default:
 		if len(eventType) >= 5 && eventType[:5] == "task." {
 			relatedType = "task"
@@ -0,0 +1,408 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/user"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 外出代理转交事项类型
+const (
+	OutOfOfficeItemHandover         = "handover"           // 交接/离职审批
+	OutOfOfficeItemTaskNodeApproval = "task_node_approval" // 任务节点完成审批
+
+	OutOfOfficeSourceReassigned = "reassigned" // 外出开始时从本人名下转交
+	OutOfOfficeSourceRouted     = "routed"     // 外出期间新产生并直接路由给代理人
+)
+
+// maxDelegateHops 代理链最大跳数（代理人本身也外出时继续向下查找）
+const maxDelegateHops = 5
+
+// OutOfOfficeItem 代理期间转交给代理人的事项
+type OutOfOfficeItem struct {
+	Type       string `json:"type"`       // 事项类型，见 OutOfOfficeItem*
+	ID         string `json:"id"`         // 交接ID或审批ID
+	Title      string `json:"title"`      // 事项标题
+	Source     string `json:"source"`     // 来源，见 OutOfOfficeSource*
+	AssignedTo string `json:"assignedTo"` // 实际处理的代理人员工ID
+	RoutedAt   string `json:"routedAt"`   // 转交时间
+}
+
+// OutOfOfficeSummary 外出结束时的事项汇总
+type OutOfOfficeSummary struct {
+	Total         int // 转交事项总数
+	Handled       int // 代理人已处理
+	Returned      int // 未处理并退回本人
+	Notifications int // 转发给代理人的通知数
+}
+
+// OutOfOfficeService 员工外出代理服务
+// 外出期间审批人查找、通知投递、智能派发候选人都会通过 ResolveDelegate 转给代理人
+type OutOfOfficeService struct {
+	outOfOfficeModel      user.OutOfOfficeModel
+	employeeModel         user.EmployeeModel
+	taskModel             task.TaskModel
+	taskNodeModel         task.TaskNodeModel
+	taskHandoverModel     task.TaskHandoverModel
+	handoverApprovalModel task.HandoverApprovalModel
+	notificationMQService *NotificationMQService
+}
+
+// NewOutOfOfficeService 创建外出代理服务
+func NewOutOfOfficeService(outOfOfficeModel user.OutOfOfficeModel, employeeModel user.EmployeeModel, taskModel task.TaskModel, taskNodeModel task.TaskNodeModel,
+	taskHandoverModel task.TaskHandoverModel, handoverApprovalModel task.HandoverApprovalModel, notificationMQService *NotificationMQService) *OutOfOfficeService {
+	return &OutOfOfficeService{
+		outOfOfficeModel:      outOfOfficeModel,
+		employeeModel:         employeeModel,
+		taskModel:             taskModel,
+		taskNodeModel:         taskNodeModel,
+		taskHandoverModel:     taskHandoverModel,
+		handoverApprovalModel: handoverApprovalModel,
+		notificationMQService: notificationMQService,
+	}
+}
+
+// CurrentPeriod 获取员工当前所处的外出记录
+func (s *OutOfOfficeService) CurrentPeriod(ctx context.Context, employeeID string) (*user.OutOfOffice, bool) {
+	if s == nil || employeeID == "" {
+		return nil, false
+	}
+	record, err := s.outOfOfficeModel.FindCurrentByEmployeeId(ctx, employeeID)
+	if err != nil {
+		if !errors.Is(err, user.ErrNotFound) {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 查询外出记录失败: employeeId=%s, err=%v", employeeID, err)
+		}
+		return nil, false
+	}
+	return record, true
+}
+
+// ResolveDelegate 解析员工当前的代理人，未外出时返回 false
+// 代理人也处于外出期间时沿代理链继续查找，遇到循环或已离职的代理人时停止
+func (s *OutOfOfficeService) ResolveDelegate(ctx context.Context, employeeID string) (*user.Employee, bool) {
+	if s == nil || employeeID == "" {
+		return nil, false
+	}
+
+	visited := map[string]bool{employeeID: true}
+	current := employeeID
+	var delegate *user.Employee
+	for i := 0; i < maxDelegateHops; i++ {
+		record, ok := s.CurrentPeriod(ctx, current)
+		if !ok || visited[record.DelegateId] {
+			break
+		}
+		emp, err := s.employeeModel.FindOne(ctx, record.DelegateId)
+		if err != nil || emp.Status != 1 {
+			break
+		}
+		visited[emp.Id] = true
+		delegate = emp
+		current = emp.Id
+	}
+	return delegate, delegate != nil
+}
+
+// RouteNotification 解析通知应抄送的代理人，并累计外出记录上的转发通知数
+func (s *OutOfOfficeService) RouteNotification(ctx context.Context, employeeID string) (*user.Employee, bool) {
+	delegate, ok := s.ResolveDelegate(ctx, employeeID)
+	if !ok {
+		return nil, false
+	}
+	if record, found := s.CurrentPeriod(ctx, employeeID); found {
+		if err := s.outOfOfficeModel.IncrRoutedNotifications(ctx, record.Id); err != nil {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 更新转发通知数失败: id=%s, err=%v", record.Id, err)
+		}
+	}
+	return delegate, true
+}
+
+// RecordRoutedItem 记录外出期间直接路由给代理人的新事项，结束时用于汇总
+func (s *OutOfOfficeService) RecordRoutedItem(ctx context.Context, employeeID, delegateID, itemType, itemID, title string) {
+	record, ok := s.CurrentPeriod(ctx, employeeID)
+	if !ok {
+		return
+	}
+	items := decodeOutOfOfficeItems(record)
+	items = append(items, OutOfOfficeItem{
+		Type:       itemType,
+		ID:         itemID,
+		Title:      title,
+		Source:     OutOfOfficeSourceRouted,
+		AssignedTo: delegateID,
+		RoutedAt:   time.Now().Format("2006-01-02 15:04:05"),
+	})
+	record.RoutedItems = encodeOutOfOfficeItems(items)
+	if err := s.outOfOfficeModel.Update(ctx, record); err != nil {
+		logx.WithContext(ctx).Errorf("[OutOfOffice] 记录代理事项失败: id=%s, item=%s, err=%v", record.Id, itemID, err)
+	}
+}
+
+// Activate 外出开始：将本人名下待处理的审批转交给代理人，并通知代理人
+func (s *OutOfOfficeService) Activate(ctx context.Context, record *user.OutOfOffice) error {
+	if record.Status != user.OutOfOfficeStatusScheduled {
+		return nil
+	}
+
+	items := decodeOutOfOfficeItems(record)
+	delegate, ok := s.ResolveDelegate(ctx, record.EmployeeId)
+	if ok {
+		now := time.Now()
+		routedAt := now.Format("2006-01-02 15:04:05")
+
+		// 1. 交接/离职审批
+		handovers, err := s.taskHandoverModel.FindPendingByApprover(ctx, record.EmployeeId)
+		if err != nil {
+			return err
+		}
+		for _, h := range handovers {
+			if h.FromEmployeeId == delegate.Id {
+				// 不能让代理人审批自己发起的交接
+				continue
+			}
+			if err := s.taskHandoverModel.UpdateApprover(ctx, h.HandoverId, delegate.Id); err != nil {
+				logx.WithContext(ctx).Errorf("[OutOfOffice] 转交交接审批失败: handoverId=%s, err=%v", h.HandoverId, err)
+				continue
+			}
+			items = append(items, OutOfOfficeItem{
+				Type:       OutOfOfficeItemHandover,
+				ID:         h.HandoverId,
+				Title:      s.handoverTitle(ctx, h),
+				Source:     OutOfOfficeSourceReassigned,
+				AssignedTo: delegate.Id,
+				RoutedAt:   routedAt,
+			})
+		}
+
+		// 2. 任务节点完成审批
+		approvals, _, err := s.handoverApprovalModel.FindTaskNodeApprovalsByApprover(ctx, record.EmployeeId, 1, 1000)
+		if err != nil {
+			return err
+		}
+		for _, a := range approvals {
+			a.ApproverId = delegate.Id
+			a.ApproverName = delegate.RealName
+			a.UpdateTime.Time, a.UpdateTime.Valid = now, true
+			if err := s.handoverApprovalModel.Update(ctx, a); err != nil {
+				logx.WithContext(ctx).Errorf("[OutOfOffice] 转交节点审批失败: approvalId=%s, err=%v", a.ApprovalId, err)
+				continue
+			}
+			items = append(items, OutOfOfficeItem{
+				Type:       OutOfOfficeItemTaskNodeApproval,
+				ID:         a.ApprovalId,
+				Title:      s.taskNodeTitle(ctx, a.TaskNodeId.String),
+				Source:     OutOfOfficeSourceReassigned,
+				AssignedTo: delegate.Id,
+				RoutedAt:   routedAt,
+			})
+		}
+	} else {
+		logx.WithContext(ctx).Infof("[OutOfOffice] 代理人不可用，跳过事项转交: id=%s, delegateId=%s", record.Id, record.DelegateId)
+	}
+
+	record.Status = user.OutOfOfficeStatusActive
+	record.RoutedItems = encodeOutOfOfficeItems(items)
+	if err := s.outOfOfficeModel.Update(ctx, record); err != nil {
+		return err
+	}
+
+	if ok {
+		employeeName := record.EmployeeId
+		if emp, err := s.employeeModel.FindOne(ctx, record.EmployeeId); err == nil {
+			employeeName = emp.RealName
+		}
+		s.notify(ctx, EmployeeOutOfOfficeStart, delegate.Id, record.Id, "外出代理提醒",
+			fmt.Sprintf("%s 于 %s 至 %s 外出，期间的审批和通知将由您代为处理，已转交 %d 项待处理审批",
+				employeeName, record.StartTime.Format("2006-01-02 15:04"), record.EndTime.Format("2006-01-02 15:04"), len(items)))
+	}
+	return nil
+}
+
+// Finish 外出结束：未处理的事项退回本人，并向本人发送代理期间的事项汇总
+func (s *OutOfOfficeService) Finish(ctx context.Context, record *user.OutOfOffice) (*OutOfOfficeSummary, error) {
+	if record.Status != user.OutOfOfficeStatusActive {
+		return nil, nil
+	}
+
+	owner, err := s.employeeModel.FindOne(ctx, record.EmployeeId)
+	if err != nil {
+		return nil, err
+	}
+	// 本人已离职时事项继续留在代理人名下
+	canReturn := owner.Status != 0
+
+	items := decodeOutOfOfficeItems(record)
+	summary := &OutOfOfficeSummary{Total: len(items), Notifications: int(record.RoutedNotifications)}
+	for _, item := range items {
+		pending, returned := s.returnItem(ctx, item, record.EmployeeId, owner.RealName, canReturn)
+		switch {
+		case returned:
+			summary.Returned++
+		case !pending:
+			summary.Handled++
+		}
+	}
+
+	record.Status = user.OutOfOfficeStatusEnded
+	if err := s.outOfOfficeModel.Update(ctx, record); err != nil {
+		return nil, err
+	}
+
+	delegateName := record.DelegateId
+	if delegate, err := s.employeeModel.FindOne(ctx, record.DelegateId); err == nil {
+		delegateName = delegate.RealName
+	}
+	s.notify(ctx, EmployeeOutOfOfficeSummary, record.EmployeeId, record.Id, "外出期间事项汇总",
+		fmt.Sprintf("外出期间（%s 至 %s）由 %s 代理：共转交 %d 项审批，已处理 %d 项，%d 项未处理已退回给您；代收通知 %d 条",
+			record.StartTime.Format("2006-01-02 15:04"), record.EndTime.Format("2006-01-02 15:04"), delegateName,
+			summary.Total, summary.Handled, summary.Returned, summary.Notifications))
+	return summary, nil
+}
+
+// Cancel 取消外出：待生效的直接取消，生效中的提前结束并退回事项
+func (s *OutOfOfficeService) Cancel(ctx context.Context, record *user.OutOfOffice) error {
+	switch record.Status {
+	case user.OutOfOfficeStatusScheduled:
+		return s.outOfOfficeModel.UpdateStatus(ctx, record.Id, user.OutOfOfficeStatusCanceled)
+	case user.OutOfOfficeStatusActive:
+		record.EndTime = time.Now()
+		_, err := s.Finish(ctx, record)
+		return err
+	default:
+		return nil
+	}
+}
+
+// ProcessDue 处理到期的外出记录（由定时任务调用），返回激活数和结束数
+func (s *OutOfOfficeService) ProcessDue(ctx context.Context) (int, int, error) {
+	started, ended := 0, 0
+
+	dueToStart, err := s.outOfOfficeModel.FindDueToStart(ctx, 100)
+	if err != nil {
+		return started, ended, err
+	}
+	for _, record := range dueToStart {
+		if err := s.Activate(ctx, record); err != nil {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 激活外出记录失败: id=%s, err=%v", record.Id, err)
+			continue
+		}
+		started++
+	}
+
+	dueToEnd, err := s.outOfOfficeModel.FindDueToEnd(ctx, 100)
+	if err != nil {
+		return started, ended, err
+	}
+	for _, record := range dueToEnd {
+		if _, err := s.Finish(ctx, record); err != nil {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 结束外出记录失败: id=%s, err=%v", record.Id, err)
+			continue
+		}
+		ended++
+	}
+	return started, ended, nil
+}
+
+// returnItem 检查事项是否仍待处理，仍由代理人持有时退回本人
+func (s *OutOfOfficeService) returnItem(ctx context.Context, item OutOfOfficeItem, ownerID, ownerName string, canReturn bool) (pending bool, returned bool) {
+	switch item.Type {
+	case OutOfOfficeItemHandover:
+		h, err := s.taskHandoverModel.FindOne(ctx, item.ID)
+		if err != nil || (h.HandoverStatus != task.HandoverStatusPendingReceiver && h.HandoverStatus != task.HandoverStatusPendingApprover) {
+			return false, false
+		}
+		if !canReturn || h.ApproverId.String != item.AssignedTo {
+			return true, false
+		}
+		if err := s.taskHandoverModel.UpdateApprover(ctx, h.HandoverId, ownerID); err != nil {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 退回交接审批失败: handoverId=%s, err=%v", h.HandoverId, err)
+			return true, false
+		}
+		return true, true
+	case OutOfOfficeItemTaskNodeApproval:
+		a, err := s.handoverApprovalModel.FindOne(ctx, item.ID)
+		if err != nil || a.ApprovalType != task.ApprovalTypePending {
+			return false, false
+		}
+		if !canReturn || a.ApproverId != item.AssignedTo {
+			return true, false
+		}
+		a.ApproverId = ownerID
+		a.ApproverName = ownerName
+		a.UpdateTime.Time, a.UpdateTime.Valid = time.Now(), true
+		if err := s.handoverApprovalModel.Update(ctx, a); err != nil {
+			logx.WithContext(ctx).Errorf("[OutOfOffice] 退回节点审批失败: approvalId=%s, err=%v", a.ApprovalId, err)
+			return true, false
+		}
+		return true, true
+	}
+	return false, false
+}
+
+// handoverTitle 交接事项标题
+func (s *OutOfOfficeService) handoverTitle(ctx context.Context, h *task.TaskHandover) string {
+	if h.TaskId == "" {
+		return "离职审批"
+	}
+	if t, err := s.taskModel.FindOne(ctx, h.TaskId); err == nil {
+		return "任务交接：" + t.TaskTitle
+	}
+	return "任务交接"
+}
+
+// taskNodeTitle 节点审批事项标题
+func (s *OutOfOfficeService) taskNodeTitle(ctx context.Context, taskNodeID string) string {
+	if taskNodeID != "" {
+		if node, err := s.taskNodeModel.FindOne(ctx, taskNodeID); err == nil {
+			return "节点完成审批：" + node.NodeName
+		}
+	}
+	return "节点完成审批"
+}
+
+// notify 发送外出代理相关通知
+func (s *OutOfOfficeService) notify(ctx context.Context, eventType, employeeID, relatedID, title, content string) {
+	if s.notificationMQService == nil {
+		return
+	}
+	event := s.notificationMQService.NewNotificationEvent(eventType, []string{employeeID}, relatedID)
+	event.Title = title
+	event.Content = content
+	event.Priority = 1
+	if err := s.notificationMQService.PublishNotificationEvent(ctx, event); err != nil {
+		logx.WithContext(ctx).Errorf("[OutOfOffice] 发布通知失败: eventType=%s, employeeId=%s, err=%v", eventType, employeeID, err)
+	}
+}
+
+// decodeOutOfOfficeItems 解析外出记录上的转交事项
+func decodeOutOfOfficeItems(record *user.OutOfOffice) []OutOfOfficeItem {
+	var items []OutOfOfficeItem
+	if record.RoutedItems.Valid && record.RoutedItems.String != "" {
+		if err := json.Unmarshal([]byte(record.RoutedItems.String), &items); err != nil {
+			logx.Errorf("[OutOfOffice] 解析转交事项失败: id=%s, err=%v", record.Id, err)
+		}
+	}
+	return items
+}
+
+// encodeOutOfOfficeItems 序列化转交事项
+func encodeOutOfOfficeItems(items []OutOfOfficeItem) sql.NullString {
+	if len(items) == 0 {
+		return sql.NullString{}
+	}
+	data, _ := json.Marshal(items)
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// CountOutOfOfficeItems 统计外出记录上的转交事项数
+func CountOutOfOfficeItems(record *user.OutOfOffice) int {
+	return len(decodeOutOfOfficeItems(record))
+}
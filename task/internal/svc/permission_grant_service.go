@@ -0,0 +1,249 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"task_Project/model/role"
+	"task_Project/model/user_auth"
+	"task_Project/task/internal/middleware"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 授权审计的操作类型（写入 operation_log.operation_type）
+const (
+	OperationTypePermission = "permission"
+
+	PermissionActionGrant  = "grant"  // 授权/续期
+	PermissionActionRevoke = "revoke" // 手动撤销
+	PermissionActionExpire = "expire" // 到期自动撤销
+)
+
+// PermissionGrant 授权请求参数
+type PermissionGrant struct {
+	UserID     string       // 被授权用户ID
+	CompanyID  string       // 授权所属公司ID（存入 resource_id）
+	PermCodes  []int        // 权限码
+	GrantType  int64        // 授权类型，见 user_auth.GrantType*
+	ExpireTime sql.NullTime // 过期时间，为空表示长期有效
+	GrantBy    string       // 授权人用户ID
+	Reason     string       // 授权原因（仅记录在审计日志中）
+}
+
+// PermissionGrantService 用户直接授权/临时委派服务
+// 权限来源：职位->角色（role.permissions）以及 user_permission 中的直接授权
+type PermissionGrantService struct {
+	userPermissionModel user_auth.UserPermissionModel
+	positionRoleModel   role.PositionRoleModel
+	operationLogModel   role.OperationLogModel
+}
+
+// NewPermissionGrantService 创建授权服务
+func NewPermissionGrantService(userPermissionModel user_auth.UserPermissionModel, positionRoleModel role.PositionRoleModel, operationLogModel role.OperationLogModel) *PermissionGrantService {
+	return &PermissionGrantService{
+		userPermissionModel: userPermissionModel,
+		positionRoleModel:   positionRoleModel,
+		operationLogModel:   operationLogModel,
+	}
+}
+
+// Grant 授予权限。直接授权和临时委派分别保存，撤销或到期回收委派不影响直接授权；
+// 同一类型已存在生效授权时续期，长期有效的授权不会被改为有期限
+func (s *PermissionGrantService) Grant(ctx context.Context, g PermissionGrant) ([]*user_auth.UserPermission, error) {
+	if g.UserID == "" || g.CompanyID == "" || len(g.PermCodes) == 0 {
+		return nil, errors.New("userId/companyId/permCodes required")
+	}
+
+	granted := make([]*user_auth.UserPermission, 0, len(g.PermCodes))
+	for _, code := range uniquePermCodes(g.PermCodes) {
+		codeStr := strconv.Itoa(code)
+		existing, err := s.userPermissionModel.FindActiveByUserIdAndCode(ctx, g.UserID, g.CompanyID, codeStr, g.GrantType)
+		if err != nil && !errors.Is(err, user_auth.ErrNotFound) {
+			return granted, err
+		}
+
+		if existing != nil {
+			existing.GrantBy = utils.Common.ToSqlNullString(g.GrantBy)
+			if existing.ExpireTime.Valid {
+				existing.ExpireTime = g.ExpireTime
+			}
+			if err := s.userPermissionModel.Update(ctx, existing); err != nil {
+				return granted, err
+			}
+			s.audit(ctx, PermissionActionGrant, g.GrantBy, existing, g.Reason)
+			granted = append(granted, existing)
+			continue
+		}
+
+		data := &user_auth.UserPermission{
+			Id:             utils.Common.GenId("up"),
+			UserId:         g.UserID,
+			PermissionCode: codeStr,
+			PermissionName: middleware.GetPermName(code),
+			ResourceType:   user_auth.ResourceTypeAPI,
+			ResourceId:     utils.Common.ToSqlNullString(g.CompanyID),
+			GrantType:      g.GrantType,
+			GrantBy:        utils.Common.ToSqlNullString(g.GrantBy),
+			ExpireTime:     g.ExpireTime,
+			Status:         user_auth.PermissionStatusActive,
+			CreateTime:     time.Now(),
+			UpdateTime:     time.Now(),
+		}
+		if _, err := s.userPermissionModel.Insert(ctx, data); err != nil {
+			return granted, err
+		}
+		s.audit(ctx, PermissionActionGrant, g.GrantBy, data, g.Reason)
+		granted = append(granted, data)
+	}
+	return granted, nil
+}
+
+// Revoke 撤销授权
+func (s *PermissionGrantService) Revoke(ctx context.Context, perm *user_auth.UserPermission, operatorUserID, reason string) error {
+	if perm.Status == user_auth.PermissionStatusRevoked {
+		return nil
+	}
+	if err := s.userPermissionModel.UpdateStatus(ctx, perm.Id, user_auth.PermissionStatusRevoked); err != nil {
+		return err
+	}
+	perm.Status = user_auth.PermissionStatusRevoked
+	s.audit(ctx, PermissionActionRevoke, operatorUserID, perm, reason)
+	return nil
+}
+
+// RevokeExpired 撤销所有已过期的授权，返回撤销数量
+func (s *PermissionGrantService) RevokeExpired(ctx context.Context) (int, error) {
+	revoked := 0
+	for {
+		expired, err := s.userPermissionModel.FindExpired(ctx, 200)
+		if err != nil {
+			return revoked, err
+		}
+		if len(expired) == 0 {
+			return revoked, nil
+		}
+		for _, perm := range expired {
+			if err := s.userPermissionModel.UpdateStatus(ctx, perm.Id, user_auth.PermissionStatusRevoked); err != nil {
+				return revoked, err
+			}
+			perm.Status = user_auth.PermissionStatusRevoked
+			s.audit(ctx, PermissionActionExpire, "system", perm, "授权已到期")
+			revoked++
+		}
+	}
+}
+
+// ActivePermCodes 获取用户在指定公司下通过直接授权获得的权限码
+func (s *PermissionGrantService) ActivePermCodes(ctx context.Context, userID, companyID string) ([]int, error) {
+	if userID == "" || companyID == "" {
+		return nil, nil
+	}
+	perms, err := s.userPermissionModel.FindActiveByUserId(ctx, userID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]int, 0, len(perms))
+	for _, p := range perms {
+		code, err := strconv.Atoi(strings.TrimSpace(p.PermissionCode))
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	return uniquePermCodes(codes), nil
+}
+
+// RolePermCodes 获取员工通过职位->角色获得的权限码
+func (s *PermissionGrantService) RolePermCodes(ctx context.Context, employeeID string) ([]int, error) {
+	roles, err := s.positionRoleModel.ListRolesByEmployeeId(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]int, 0, 16)
+	for _, r := range roles {
+		if !r.Permissions.Valid || strings.TrimSpace(r.Permissions.String) == "" {
+			continue
+		}
+		var arr []int
+		if json.Unmarshal([]byte(r.Permissions.String), &arr) == nil {
+			codes = append(codes, arr...)
+		}
+	}
+	return uniquePermCodes(codes), nil
+}
+
+// EffectivePermCodes 获取员工的全部有效权限码（角色权限 + 直接授权）
+func (s *PermissionGrantService) EffectivePermCodes(ctx context.Context, employeeID, userID, companyID string) ([]int, error) {
+	roleCodes, err := s.RolePermCodes(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	grantCodes, err := s.ActivePermCodes(ctx, userID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return uniquePermCodes(append(roleCodes, grantCodes...)), nil
+}
+
+// audit 将授权变更写入操作日志
+func (s *PermissionGrantService) audit(ctx context.Context, action, operatorUserID string, perm *user_auth.UserPermission, reason string) {
+	if s.operationLogModel == nil {
+		return
+	}
+	detail := map[string]interface{}{
+		"permissionId":   perm.Id,
+		"targetUserId":   perm.UserId,
+		"companyId":      perm.ResourceId.String,
+		"permissionCode": perm.PermissionCode,
+		"permissionName": perm.PermissionName,
+		"grantType":      perm.GrantType,
+		"status":         perm.Status,
+		"reason":         reason,
+	}
+	if perm.ExpireTime.Valid {
+		detail["expireTime"] = perm.ExpireTime.Time.Format("2006-01-02 15:04:05")
+	}
+	desc, _ := json.Marshal(detail)
+
+	log := &role.OperationLog{
+		Id:            utils.Common.GenId("oplog"),
+		UserId:        utils.Common.ToSqlNullString(operatorUserID),
+		CompanyId:     perm.ResourceId,
+		OperationType: OperationTypePermission,
+		OperationName: action,
+		OperationDesc: sql.NullString{String: string(desc), Valid: true},
+		EntityType:    sql.NullString{String: "user_permission", Valid: true},
+		EntityId:      sql.NullString{String: perm.Id, Valid: true},
+		Status:        1,
+		CreateTime:    time.Now(),
+	}
+	if employeeID, ok := utils.Common.GetCurrentEmployeeID(ctx); ok && employeeID != "" {
+		log.EmployeeId = sql.NullString{String: employeeID, Valid: true}
+	}
+	if _, err := s.operationLogModel.Insert(ctx, log); err != nil {
+		logx.Errorf("[PermissionGrant] 写入授权审计日志失败: action=%s, permissionId=%s, err=%v", action, perm.Id, err)
+	}
+}
+
+// uniquePermCodes 去重并排序权限码
+func uniquePermCodes(codes []int) []int {
+	seen := make(map[int]struct{}, len(codes))
+	out := make([]int, 0, len(codes))
+	for _, c := range codes {
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		out = append(out, c)
+	}
+	sort.Ints(out)
+	return out
+}
@@ -56,6 +56,34 @@ func (s *SchedulerService) StartScheduler() {
 
 	// 启动任务节点闲置检查定时任务
 	go s.startTaskNodeIdleCheck()
+
+	// 启动过期授权回收定时任务
+	go s.startPermissionExpiryCheck()
+
+	// 启动外出代理生效/结束检查
+	go s.startOutOfOfficeCheck()
+
+	// 启动统计快照刷新
+	go s.startAnalyticsRefresh()
+
+	// 启动过期导出文件清理
+	go s.startExportCleanup()
+
+	// 启动附件历史版本清理
+	go s.startFileVersionCleanup()
+
+	// 启动过期上传会话清理
+	go s.startUploadSessionCleanup()
+}
+
+// inWorkHours 判断是否在系统配置的工作时间内，配置无效时使用默认的 9:00-18:00
+func (s *SchedulerService) inWorkHours(hour int) bool {
+	start := s.svcCtx.SystemConfigService.GetInt(SettingSchedulerWorkStart, 9)
+	end := s.svcCtx.SystemConfigService.GetInt(SettingSchedulerWorkEnd, 18)
+	if start >= end {
+		start, end = 9, 18
+	}
+	return hour >= start && hour < end
 }
 
 // 任务截止提醒定时任务
@@ -68,9 +96,9 @@ func (s *SchedulerService) startDeadlineReminder() {
 		case <-s.stopCh:
 			return
 		case <-ticker.C:
-			// 检查是否在工作时间（9:00-18:00）
+			// 检查是否在工作时间（默认9:00-18:00，可通过系统配置调整）
 			hour := time.Now().Hour()
-			if hour >= 9 && hour < 18 {
+			if s.inWorkHours(hour) {
 				s.checkTaskDeadlines()
 			}
 		}
@@ -138,8 +166,8 @@ func (s *SchedulerService) startDailyReportReminder() {
 		case <-ticker.C:
 			now := time.Now()
 			hour := now.Hour()
-			// 检查是否在工作时间（9:00-18:00）且在下午5点到6点之间
-			if hour >= 9 && hour < 18 && hour == 17 {
+			// 检查是否在工作时间且在配置的汇报提醒时段（默认下午5点到6点之间）
+			if s.inWorkHours(hour) && hour == s.svcCtx.SystemConfigService.GetInt(SettingSchedulerReportHour, 17) {
 				s.sendDailyReportReminders()
 			}
 		}
@@ -183,9 +211,9 @@ func (s *SchedulerService) startSlowProgressDetection() {
 		case <-s.stopCh:
 			return
 		case <-ticker.C:
-			// 检查是否在工作时间（9:00-18:00）
+			// 检查是否在工作时间（默认9:00-18:00，可通过系统配置调整）
 			hour := time.Now().Hour()
-			if hour >= 9 && hour < 18 {
+			if s.inWorkHours(hour) {
 				s.checkSlowProgress()
 			}
 		}
@@ -289,9 +317,9 @@ func (s *SchedulerService) startTaskNodeIdleCheck() {
 		case <-s.stopCh:
 			return
 		case <-ticker.C:
-			// 检查是否在工作时间（9:00-18:00）
+			// 检查是否在工作时间（默认9:00-18:00，可通过系统配置调整）
 			hour := time.Now().Hour()
-			if hour >= 9 && hour < 18 {
+			if s.inWorkHours(hour) {
 				s.checkTaskNodeIdle()
 			}
 		}
@@ -338,3 +366,214 @@ func (s *SchedulerService) validateTaskNodeExecutor(ctx context.Context, node *t
 	// 已有执行人，无需处理
 	return nil
 }
+
+// 过期授权回收定时任务（直接授权/临时委派到期后自动撤销）
+func (s *SchedulerService) startPermissionExpiryCheck() {
+	ticker := time.NewTicker(10 * time.Minute) // 每10分钟检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.revokeExpiredPermissions()
+		}
+	}
+}
+
+// 撤销已过期的授权
+func (s *SchedulerService) revokeExpiredPermissions() {
+	if s.svcCtx.PermissionGrantService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	count, err := s.svcCtx.PermissionGrantService.RevokeExpired(ctx)
+	if err != nil {
+		logx.Errorf("回收过期授权失败: revoked=%d, err=%v", count, err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("已回收过期授权: %d 条", count)
+	}
+}
+
+// 外出代理定时任务：到开始时间转交待审批事项，到结束时间退回事项并发送汇总
+func (s *SchedulerService) startOutOfOfficeCheck() {
+	ticker := time.NewTicker(1 * time.Minute) // 每1分钟检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.processOutOfOffice()
+		}
+	}
+}
+
+// 处理到期的外出代理记录
+func (s *SchedulerService) processOutOfOffice() {
+	if s.svcCtx.OutOfOfficeService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	started, ended, err := s.svcCtx.OutOfOfficeService.ProcessDue(ctx)
+	if err != nil {
+		logx.Errorf("处理外出代理记录失败: started=%d, ended=%d, err=%v", started, ended, err)
+		return
+	}
+	if started > 0 || ended > 0 {
+		logx.Infof("外出代理记录处理完成: 生效 %d 条, 结束 %d 条", started, ended)
+	}
+}
+
+// 统计快照定时任务：按系统配置的间隔刷新当天快照，跨天后补一次前一天的最终数据
+func (s *SchedulerService) startAnalyticsRefresh() {
+	ticker := time.NewTicker(5 * time.Minute) // 每5分钟检查一次是否到刷新间隔
+	defer ticker.Stop()
+
+	var lastRun time.Time
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case now := <-ticker.C:
+			interval := time.Duration(s.svcCtx.SystemConfigService.GetInt(SettingAnalyticsRefresh, 15)) * time.Minute
+			crossedDay := !lastRun.IsZero() && !StatsDay(now).Equal(StatsDay(lastRun))
+			if !lastRun.IsZero() && !crossedDay && now.Sub(lastRun) < interval {
+				continue
+			}
+			start := StatsDay(now)
+			if lastRun.IsZero() {
+				// 启动后首次刷新时补齐最近一周，保证仪表盘趋势有数据
+				start = start.AddDate(0, 0, -6)
+			} else if crossedDay {
+				start = StatsDay(lastRun)
+			}
+			s.refreshAnalytics(start, now)
+			lastRun = now
+		}
+	}
+}
+
+// 刷新全部公司和平台的统计快照
+func (s *SchedulerService) refreshAnalytics(start, end time.Time) {
+	if s.svcCtx.AnalyticsService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	begin := time.Now()
+	companies, err := s.svcCtx.AnalyticsService.RefreshAll(ctx, start, end)
+	if err != nil {
+		logx.Errorf("刷新统计快照失败: companies=%d, err=%v", companies, err)
+		return
+	}
+	logx.Infof("统计快照刷新完成: 公司 %d 家, 耗时 %v", companies, time.Since(begin))
+}
+
+// 过期导出文件清理定时任务
+func (s *SchedulerService) startExportCleanup() {
+	ticker := time.NewTicker(time.Hour) // 每小时检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.cleanupExports()
+		}
+	}
+}
+
+// 删除超过保留天数的导出任务及其文件
+func (s *SchedulerService) cleanupExports() {
+	if s.svcCtx.ExportService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	count, err := s.svcCtx.ExportService.Cleanup(ctx)
+	if err != nil {
+		logx.Errorf("清理过期导出文件失败: removed=%d, err=%v", count, err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("已清理过期导出文件: %d 个", count)
+	}
+}
+
+// 附件历史版本清理定时任务
+func (s *SchedulerService) startFileVersionCleanup() {
+	ticker := time.NewTicker(time.Hour) // 每小时检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.cleanupFileVersions()
+		}
+	}
+}
+
+// 删除超过保留天数的附件历史版本
+func (s *SchedulerService) cleanupFileVersions() {
+	if s.svcCtx.FileVersionService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	count, err := s.svcCtx.FileVersionService.Cleanup(ctx)
+	if err != nil {
+		logx.Errorf("清理附件历史版本失败: removed=%d, err=%v", count, err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("已清理附件历史版本: %d 个", count)
+	}
+}
+
+// 过期上传会话清理定时任务
+func (s *SchedulerService) startUploadSessionCleanup() {
+	ticker := time.NewTicker(30 * time.Minute) // 每30分钟检查一次
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.cleanupUploadSessions()
+		}
+	}
+}
+
+// 取消过期的上传会话并释放已上传的分片
+func (s *SchedulerService) cleanupUploadSessions() {
+	if s.svcCtx.UploadSessionService == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	count, err := s.svcCtx.UploadSessionService.Cleanup(ctx)
+	if err != nil {
+		logx.Errorf("清理过期上传会话失败: removed=%d, err=%v", count, err)
+		return
+	}
+	if count > 0 {
+		logx.Infof("已清理过期上传会话: %d 个", count)
+	}
+}
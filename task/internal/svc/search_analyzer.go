@@ -0,0 +1,141 @@
+package svc
+
+import (
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/go-ego/gse"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 中文分词器和分析器在 bleve 中注册的名称
+const (
+	searchTokenizerName = "gse"
+	searchAnalyzerName  = "zh"
+)
+
+var (
+	searchSegmenter     gse.Segmenter
+	searchSegmenterOnce sync.Once
+)
+
+func init() {
+	if err := registry.RegisterTokenizer(searchTokenizerName, func(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+		return &gseTokenizer{seg: loadSearchSegmenter()}, nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// loadSearchSegmenter 加载内置的简体中文词典，只在第一次使用时加载
+func loadSearchSegmenter() *gse.Segmenter {
+	searchSegmenterOnce.Do(func() {
+		if err := searchSegmenter.LoadDictEmbed("zh_s"); err != nil {
+			logx.Errorf("[Search] 加载中文分词词典失败: %v", err)
+		}
+	})
+	return &searchSegmenter
+}
+
+// gseTokenizer 基于词典的中文分词，英文和数字按连续字符切分；
+// 长词额外输出词典中的子词（搜索引擎模式），使“任务管理系统”也能被“任务”“管理”搜索到
+type gseTokenizer struct {
+	seg *gse.Segmenter
+}
+
+func (t *gseTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input)/3)
+	position := 1
+	for _, s := range t.seg.Segment(input) {
+		term := input[s.Start():s.End()]
+		if !isSearchTerm(term) {
+			continue
+		}
+		rv = append(rv, newSearchToken(term, s.Start(), s.End(), position))
+		rv = appendSubTokens(rv, input, s.Start(), s.Token(), position)
+		position++
+	}
+	return rv
+}
+
+// appendSubTokens 递归输出长词的子词，子词与原词位于同一位置
+func appendSubTokens(rv analysis.TokenStream, input []byte, offset int, token *gse.Token, position int) analysis.TokenStream {
+	if token == nil {
+		return rv
+	}
+	for _, sub := range token.Segments() {
+		start, end := offset+sub.Start(), offset+sub.End()
+		term := input[start:end]
+		if utf8.RuneCount(term) > 1 && isSearchTerm(term) {
+			rv = append(rv, newSearchToken(term, start, end, position))
+		}
+		rv = appendSubTokens(rv, input, start, sub.Token(), position)
+	}
+	return rv
+}
+
+func newSearchToken(term []byte, start, end, position int) *analysis.Token {
+	tokenType := analysis.AlphaNumeric
+	if r, _ := utf8.DecodeRune(term); unicode.Is(unicode.Han, r) {
+		tokenType = analysis.Ideographic
+	}
+	return &analysis.Token{
+		Term:     append([]byte(nil), term...),
+		Start:    start,
+		End:      end,
+		Position: position,
+		Type:     tokenType,
+	}
+}
+
+// isSearchTerm 过滤空白和标点，只保留包含文字或数字的词
+func isSearchTerm(term []byte) bool {
+	for _, r := range string(term) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// newSearchIndexMapping 搜索索引的字段映射：标题和正文使用中文分析器并保存原文用于高亮，其余字段不分词
+func newSearchIndexMapping() (mapping.IndexMapping, error) {
+	indexMapping := mapping.NewIndexMapping()
+	if err := indexMapping.AddCustomAnalyzer(searchAnalyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     searchTokenizerName,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		return nil, err
+	}
+
+	textField := mapping.NewTextFieldMapping()
+	textField.Analyzer = searchAnalyzerName
+	textField.Store = true
+	textField.IncludeTermVectors = true
+
+	keywordField := mapping.NewKeywordFieldMapping()
+	keywordField.Store = true
+	keywordField.IncludeInAll = false
+
+	timeField := mapping.NewDateTimeFieldMapping()
+	timeField.IncludeInAll = false
+
+	doc := mapping.NewDocumentStaticMapping()
+	for _, name := range []string{"type", "companyId", "taskId", "nodeId", "refId"} {
+		doc.AddFieldMappingsAt(name, keywordField)
+	}
+	doc.AddFieldMappingsAt("title", textField)
+	doc.AddFieldMappingsAt("content", textField)
+	doc.AddFieldMappingsAt("createTime", timeField)
+
+	indexMapping.DefaultMapping = doc
+	indexMapping.DefaultAnalyzer = searchAnalyzerName
+	return indexMapping, nil
+}
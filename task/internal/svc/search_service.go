@@ -0,0 +1,665 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/model/upload"
+	"task_Project/task/internal/utils"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 搜索文档类型
+const (
+	SearchDocTask              = "task"               // 任务
+	SearchDocNode              = "node"               // 任务节点
+	SearchDocChecklist         = "checklist"          // 任务清单
+	SearchDocComment           = "comment"            // 任务评论
+	SearchDocAttachmentComment = "attachment_comment" // 附件评论
+	SearchDocFile              = "file"               // 附件
+)
+
+// searchDocTypes 支持搜索的文档类型
+var searchDocTypes = map[string]bool{
+	SearchDocTask: true, SearchDocNode: true, SearchDocChecklist: true,
+	SearchDocComment: true, SearchDocAttachmentComment: true, SearchDocFile: true,
+}
+
+// 事件队列长度、重建索引时每批处理的任务数和单个事件的处理超时
+const (
+	searchEventBuffer  = 2048
+	searchRebuildBatch = 100
+	searchEventTimeout = 30 * time.Second
+	searchMongoPage    = 500
+)
+
+var (
+	// ErrSearchUnavailable 搜索索引未打开
+	ErrSearchUnavailable = errors.New("search index unavailable")
+	// ErrSearchRebuildRunning 已有重建任务正在执行
+	ErrSearchRebuildRunning = errors.New("search index rebuild already running")
+)
+
+// IsSearchDocType 判断是否为支持搜索的文档类型
+func IsSearchDocType(docType string) bool {
+	return searchDocTypes[docType]
+}
+
+// SearchEvent 领域事件：实体新增、修改或删除后由业务逻辑发布，只携带实体ID，
+// 处理时重新读取实体，已删除的从索引中移除
+type SearchEvent struct {
+	DocType string
+	ID      string
+}
+
+// searchDocument 索引中的文档
+type searchDocument struct {
+	Type       string    `json:"type"`
+	CompanyID  string    `json:"companyId"`
+	TaskID     string    `json:"taskId"`
+	NodeID     string    `json:"nodeId"`
+	RefID      string    `json:"refId"` // 关联实体ID，附件和附件评论为附件ID
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// SearchQuery 搜索条件
+type SearchQuery struct {
+	CompanyID string
+	Keyword   string
+	TaskIDs   []string // 非空时只搜索这些任务下的内容
+	DocTypes  []string // 为空时搜索全部类型
+	From      int
+	Size      int
+}
+
+// SearchHit 搜索结果
+type SearchHit struct {
+	DocType    string
+	ID         string
+	TaskID     string
+	NodeID     string
+	RefID      string
+	Title      string
+	Snippets   []string // 命中的片段，已转义 HTML，关键字用 <mark> 标记
+	TitleHTML  string   // 标题高亮
+	Score      float64
+	CreateTime time.Time
+}
+
+// SearchService 全文搜索服务：使用内嵌的 bleve 倒排索引，
+// 由业务逻辑发布的领域事件增量更新，索引为空时从 MySQL/MongoDB 全量重建
+type SearchService struct {
+	index                  bleve.Index
+	taskModel              task.TaskModel
+	taskNodeModel          task.TaskNodeModel
+	taskChecklistModel     task.TaskChecklistModel
+	taskCommentModel       task.Task_commentModel
+	attachmentCommentModel upload.Attachment_commentModel
+	uploadFileModel        upload.Upload_fileModel
+	events                 chan SearchEvent
+	rebuilding             atomic.Bool
+}
+
+// NewSearchService 打开（不存在时创建）索引目录并启动事件处理；MongoDB 未配置时评论和附件不参与索引
+func NewSearchService(indexPath string, taskModel task.TaskModel, taskNodeModel task.TaskNodeModel,
+	taskChecklistModel task.TaskChecklistModel, taskCommentModel task.Task_commentModel,
+	attachmentCommentModel upload.Attachment_commentModel, uploadFileModel upload.Upload_fileModel) (*SearchService, error) {
+	start := time.Now()
+	index, err := bleve.Open(indexPath)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		indexMapping, mappingErr := newSearchIndexMapping()
+		if mappingErr != nil {
+			return nil, mappingErr
+		}
+		index, err = bleve.New(indexPath, indexMapping)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开搜索索引失败: %w", err)
+	}
+	logx.Infof("[Search] 搜索索引已打开: path=%s, 耗时=%v", indexPath, time.Since(start))
+
+	s := &SearchService{
+		index:                  index,
+		taskModel:              taskModel,
+		taskNodeModel:          taskNodeModel,
+		taskChecklistModel:     taskChecklistModel,
+		taskCommentModel:       taskCommentModel,
+		attachmentCommentModel: attachmentCommentModel,
+		uploadFileModel:        uploadFileModel,
+		events:                 make(chan SearchEvent, searchEventBuffer),
+	}
+	go s.consume()
+	return s, nil
+}
+
+// Publish 发布领域事件，队列已满时丢弃（可通过重建索引恢复），不阻塞业务请求
+func (s *SearchService) Publish(docType, id string) {
+	if s == nil || id == "" {
+		return
+	}
+	select {
+	case s.events <- SearchEvent{DocType: docType, ID: id}:
+	default:
+		logx.Errorf("[Search] 索引事件队列已满，丢弃事件: type=%s, id=%s", docType, id)
+	}
+}
+
+// consume 顺序处理领域事件
+func (s *SearchService) consume() {
+	for event := range s.events {
+		ctx, cancel := context.WithTimeout(context.Background(), searchEventTimeout)
+		if err := s.apply(ctx, event); err != nil {
+			logx.Errorf("[Search] 更新索引失败: type=%s, id=%s, err=%v", event.DocType, event.ID, err)
+		}
+		cancel()
+	}
+}
+
+// apply 重新读取实体并写入索引，实体不存在或已删除时从索引移除
+func (s *SearchService) apply(ctx context.Context, event SearchEvent) error {
+	var doc *searchDocument
+	var err error
+	switch event.DocType {
+	case SearchDocTask:
+		doc, err = s.taskDocument(ctx, event.ID)
+		if err == nil && doc == nil {
+			// 任务删除后其下的节点、清单、评论和附件一并移除
+			return s.removeTask(event.ID)
+		}
+	case SearchDocNode:
+		doc, err = s.nodeDocument(ctx, event.ID)
+	case SearchDocChecklist:
+		doc, err = s.checklistDocument(ctx, event.ID)
+	case SearchDocComment:
+		doc, err = s.commentDocument(ctx, event.ID)
+	case SearchDocAttachmentComment:
+		doc, err = s.attachmentCommentDocument(ctx, event.ID)
+	case SearchDocFile:
+		doc, err = s.fileDocument(ctx, event.ID)
+	default:
+		return fmt.Errorf("unknown search document type %q", event.DocType)
+	}
+	if err != nil {
+		return err
+	}
+	docID := searchDocID(event.DocType, event.ID)
+	if doc == nil {
+		if event.DocType == SearchDocFile {
+			// 附件删除后其评论不再可见
+			if err := s.removeMatching(bleve.NewConjunctionQuery(
+				termQuery("type", SearchDocAttachmentComment), termQuery("refId", event.ID))); err != nil {
+				return err
+			}
+		}
+		return s.index.Delete(docID)
+	}
+	return s.index.Index(docID, doc)
+}
+
+// Search 按关键字搜索，结果按相关度排序
+func (s *SearchService) Search(q SearchQuery) ([]SearchHit, uint64, error) {
+	if s == nil {
+		return nil, 0, ErrSearchUnavailable
+	}
+	titleQuery := bleve.NewMatchQuery(q.Keyword)
+	titleQuery.SetField("title")
+	titleQuery.Analyzer = searchAnalyzerName
+	titleQuery.SetBoost(2)
+	contentQuery := bleve.NewMatchQuery(q.Keyword)
+	contentQuery.SetField("content")
+	contentQuery.Analyzer = searchAnalyzerName
+
+	conjuncts := []query.Query{
+		bleve.NewDisjunctionQuery(titleQuery, contentQuery),
+		termQuery("companyId", q.CompanyID),
+	}
+	if len(q.TaskIDs) > 0 {
+		conjuncts = append(conjuncts, termsQuery("taskId", q.TaskIDs))
+	}
+	if len(q.DocTypes) > 0 {
+		conjuncts = append(conjuncts, termsQuery("type", q.DocTypes))
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), q.Size, q.From, false)
+	req.Fields = []string{"type", "taskId", "nodeId", "refId", "title", "createTime"}
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	req.Highlight.AddField("title")
+	req.Highlight.AddField("content")
+	result, err := s.index.Search(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]SearchHit, 0, len(result.Hits))
+	for _, h := range result.Hits {
+		hit := SearchHit{
+			DocType:  fieldString(h.Fields, "type"),
+			TaskID:   fieldString(h.Fields, "taskId"),
+			NodeID:   fieldString(h.Fields, "nodeId"),
+			RefID:    fieldString(h.Fields, "refId"),
+			Title:    fieldString(h.Fields, "title"),
+			Snippets: h.Fragments["content"],
+			Score:    h.Score,
+		}
+		if _, id, ok := strings.Cut(h.ID, ":"); ok {
+			hit.ID = id
+		}
+		if titles := h.Fragments["title"]; len(titles) > 0 {
+			hit.TitleHTML = titles[0]
+		}
+		if t, err := time.Parse(time.RFC3339, fieldString(h.Fields, "createTime")); err == nil {
+			hit.CreateTime = t.Local()
+		}
+		hits = append(hits, hit)
+	}
+	return hits, result.Total, nil
+}
+
+// Rebuilding 是否正在重建索引
+func (s *SearchService) Rebuilding() bool {
+	return s != nil && s.rebuilding.Load()
+}
+
+// RebuildIfEmpty 索引中没有文档时（首次部署或索引目录被清空）在后台全量重建
+func (s *SearchService) RebuildIfEmpty() {
+	if s == nil {
+		return
+	}
+	if count, err := s.index.DocCount(); err != nil || count > 0 {
+		return
+	}
+	go func() {
+		if _, err := s.Rebuild(context.Background()); err != nil {
+			logx.Errorf("[Search] 重建索引失败: %v", err)
+		}
+	}()
+}
+
+// Rebuild 清空索引后遍历全部任务重新写入，返回写入的文档数；同一时间只执行一次
+func (s *SearchService) Rebuild(ctx context.Context) (int, error) {
+	if s == nil {
+		return 0, ErrSearchUnavailable
+	}
+	if !s.rebuilding.CompareAndSwap(false, true) {
+		return 0, ErrSearchRebuildRunning
+	}
+	defer s.rebuilding.Store(false)
+
+	start := time.Now()
+	// 先清空索引，丢失删除事件留下的文档不会残留
+	if err := s.removeMatching(bleve.NewMatchAllQuery()); err != nil {
+		return 0, err
+	}
+	total := 0
+	for page := 1; ; page++ {
+		tasks, _, err := s.taskModel.FindByPage(ctx, page, searchRebuildBatch)
+		if err != nil {
+			return total, err
+		}
+		for _, t := range tasks {
+			count, err := s.indexTaskTree(ctx, t)
+			if err != nil {
+				logx.Errorf("[Search] 重建任务索引失败: taskId=%s, err=%v", t.TaskId, err)
+				continue
+			}
+			total += count
+		}
+		if len(tasks) < searchRebuildBatch {
+			break
+		}
+	}
+	logx.Infof("[Search] 索引重建完成: 文档数=%d, 耗时=%v", total, time.Since(start))
+	return total, nil
+}
+
+// indexTaskTree 写入任务及其节点、清单、评论和附件
+func (s *SearchService) indexTaskTree(ctx context.Context, t *task.Task) (int, error) {
+	batch := s.index.NewBatch()
+	add := func(docType, id string, doc *searchDocument) {
+		if doc != nil {
+			_ = batch.Index(searchDocID(docType, id), doc)
+		}
+	}
+	add(SearchDocTask, t.TaskId, toTaskDocument(t))
+	// 只索引仍存在的附件下的评论
+	files := make(map[string]bool)
+	addFile := func(f *upload.Upload_file) {
+		files[f.FileID] = true
+		add(SearchDocFile, f.FileID, toFileDocument(t.CompanyId, t.TaskId, f))
+	}
+
+	nodes, err := s.taskNodeModel.FindByTaskID(ctx, t.TaskId)
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range nodes {
+		add(SearchDocNode, n.TaskNodeId, toNodeDocument(t.CompanyId, n))
+		if s.uploadFileModel != nil {
+			nodeFiles, _ := s.uploadFileModel.FindByTaskNodeID(ctx, n.TaskNodeId)
+			for _, f := range nodeFiles {
+				addFile(f)
+			}
+		}
+	}
+	checklists, err := s.taskChecklistModel.FindByTaskId(ctx, t.TaskId)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range checklists {
+		add(SearchDocChecklist, c.ChecklistId, toChecklistDocument(t.CompanyId, t.TaskId, c))
+	}
+
+	if s.uploadFileModel != nil {
+		taskFiles, _ := s.uploadFileModel.FindByModuleAndRelatedID(ctx, "task", t.TaskId)
+		for _, f := range taskFiles {
+			addFile(f)
+		}
+	}
+	if s.taskCommentModel != nil {
+		for page := int64(1); ; page++ {
+			comments, _, err := s.taskCommentModel.FindByTaskID(ctx, t.TaskId, page, searchMongoPage)
+			if err != nil {
+				return 0, err
+			}
+			for _, c := range comments {
+				add(SearchDocComment, c.CommentID, toCommentDocument(t.CompanyId, c))
+			}
+			if len(comments) < searchMongoPage {
+				break
+			}
+		}
+	}
+	if s.attachmentCommentModel != nil {
+		for page := int64(1); ; page++ {
+			comments, _, err := s.attachmentCommentModel.FindByTaskID(ctx, t.TaskId, page, searchMongoPage)
+			if err != nil {
+				return 0, err
+			}
+			for _, c := range comments {
+				if files[c.FileID] {
+					add(SearchDocAttachmentComment, c.CommentID, toAttachmentCommentDocument(t.CompanyId, c))
+				}
+			}
+			if len(comments) < searchMongoPage {
+				break
+			}
+		}
+	}
+	count := batch.Size()
+	return count, s.index.Batch(batch)
+}
+
+// removeTask 移除任务下的全部文档
+func (s *SearchService) removeTask(taskID string) error {
+	return s.removeMatching(termQuery("taskId", taskID))
+}
+
+// removeMatching 分批移除满足条件的文档
+func (s *SearchService) removeMatching(q query.Query) error {
+	for {
+		req := bleve.NewSearchRequestOptions(q, 1000, 0, false)
+		result, err := s.index.Search(req)
+		if err != nil {
+			return err
+		}
+		if len(result.Hits) == 0 {
+			return nil
+		}
+		batch := s.index.NewBatch()
+		for _, h := range result.Hits {
+			batch.Delete(h.ID)
+		}
+		if err := s.index.Batch(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// Close 关闭索引
+func (s *SearchService) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.index.Close()
+}
+
+func (s *SearchService) taskDocument(ctx context.Context, taskID string) (*searchDocument, error) {
+	t, err := s.taskModel.FindOne(ctx, taskID)
+	if errors.Is(err, task.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toTaskDocument(t), nil
+}
+
+func (s *SearchService) nodeDocument(ctx context.Context, nodeID string) (*searchDocument, error) {
+	n, err := s.taskNodeModel.FindOne(ctx, nodeID)
+	if errors.Is(err, task.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	companyID, err := s.companyOfTask(ctx, n.TaskId)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	return toNodeDocument(companyID, n), nil
+}
+
+func (s *SearchService) checklistDocument(ctx context.Context, checklistID string) (*searchDocument, error) {
+	c, err := s.taskChecklistModel.FindOne(ctx, checklistID)
+	if errors.Is(err, task.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.taskNodeModel.FindOne(ctx, c.TaskNodeId)
+	if errors.Is(err, task.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	companyID, err := s.companyOfTask(ctx, n.TaskId)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	return toChecklistDocument(companyID, n.TaskId, c), nil
+}
+
+func (s *SearchService) commentDocument(ctx context.Context, commentID string) (*searchDocument, error) {
+	if s.taskCommentModel == nil {
+		return nil, nil
+	}
+	c, err := s.taskCommentModel.FindByCommentID(ctx, commentID)
+	if err != nil {
+		// 评论不存在时 MongoDB 返回 ErrNotFound，按删除处理
+		return nil, nil
+	}
+	companyID, err := s.companyOfTask(ctx, c.TaskID)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	return toCommentDocument(companyID, c), nil
+}
+
+func (s *SearchService) attachmentCommentDocument(ctx context.Context, commentID string) (*searchDocument, error) {
+	if s.attachmentCommentModel == nil {
+		return nil, nil
+	}
+	c, err := s.attachmentCommentModel.FindByCommentID(ctx, commentID)
+	if err != nil {
+		return nil, nil
+	}
+	if s.uploadFileModel != nil {
+		if _, err := s.uploadFileModel.FindByFileID(ctx, c.FileID); err != nil {
+			return nil, nil
+		}
+	}
+	companyID, err := s.companyOfTask(ctx, c.TaskID)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	return toAttachmentCommentDocument(companyID, c), nil
+}
+
+func (s *SearchService) fileDocument(ctx context.Context, fileID string) (*searchDocument, error) {
+	if s.uploadFileModel == nil {
+		return nil, nil
+	}
+	f, err := s.uploadFileModel.FindByFileID(ctx, fileID)
+	if err != nil {
+		return nil, nil
+	}
+	taskID := ""
+	switch {
+	case f.Module == "task":
+		taskID = f.RelatedID
+	case f.TaskNodeID != "" || f.Module == "tasknode":
+		nodeID := f.TaskNodeID
+		if nodeID == "" {
+			nodeID = f.RelatedID
+		}
+		if n, err := s.taskNodeModel.FindOne(ctx, nodeID); err == nil {
+			taskID = n.TaskId
+		}
+	}
+	if taskID == "" {
+		// 头像等与任务无关的文件不参与搜索
+		return nil, nil
+	}
+	companyID, err := s.companyOfTask(ctx, taskID)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	return toFileDocument(companyID, taskID, f), nil
+}
+
+// companyOfTask 获取任务所属公司，任务不存在或已删除时返回空字符串
+func (s *SearchService) companyOfTask(ctx context.Context, taskID string) (string, error) {
+	t, err := s.taskModel.FindOne(ctx, taskID)
+	if errors.Is(err, task.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if t.DeleteTime.Valid {
+		return "", nil
+	}
+	return t.CompanyId, nil
+}
+
+func toTaskDocument(t *task.Task) *searchDocument {
+	if t.DeleteTime.Valid {
+		return nil
+	}
+	return &searchDocument{
+		Type: SearchDocTask, CompanyID: t.CompanyId, TaskID: t.TaskId, RefID: t.TaskId,
+		Title: t.TaskTitle, Content: utils.PlainText(t.TaskDetail), CreateTime: t.CreateTime,
+	}
+}
+
+func toNodeDocument(companyID string, n *task.TaskNode) *searchDocument {
+	if n.DeleteTime.Valid {
+		return nil
+	}
+	return &searchDocument{
+		Type: SearchDocNode, CompanyID: companyID, TaskID: n.TaskId, NodeID: n.TaskNodeId, RefID: n.TaskNodeId,
+		Title: n.NodeName, Content: utils.PlainText(n.NodeDetail.String), CreateTime: n.CreateTime,
+	}
+}
+
+func toChecklistDocument(companyID, taskID string, c *task.TaskChecklist) *searchDocument {
+	if c.DeleteTime.Valid {
+		return nil
+	}
+	return &searchDocument{
+		Type: SearchDocChecklist, CompanyID: companyID, TaskID: taskID, NodeID: c.TaskNodeId, RefID: c.ChecklistId,
+		Content: c.Content, CreateTime: c.CreateTime,
+	}
+}
+
+func toCommentDocument(companyID string, c *task.Task_comment) *searchDocument {
+	if c.IsDeleted {
+		return nil
+	}
+	return &searchDocument{
+		Type: SearchDocComment, CompanyID: companyID, TaskID: c.TaskID, NodeID: c.TaskNodeID, RefID: c.CommentID,
+		Title: c.EmployeeName, Content: commentSearchText(c.ContentText, c.Content), CreateTime: c.CreateAt,
+	}
+}
+
+func toAttachmentCommentDocument(companyID string, c *upload.Attachment_comment) *searchDocument {
+	if c.IsDeleted {
+		return nil
+	}
+	content := commentSearchText(c.ContentText, c.Content)
+	if c.AnnotationData != nil && c.AnnotationData.Text != "" {
+		content += "\n" + c.AnnotationData.Text
+	}
+	// 附件评论的 refId 为附件ID，便于前端打开附件并定位评论
+	return &searchDocument{
+		Type: SearchDocAttachmentComment, CompanyID: companyID, TaskID: c.TaskID, NodeID: c.TaskNodeID, RefID: c.FileID,
+		Title: c.EmployeeName, Content: content, CreateTime: c.CreateAt,
+	}
+}
+
+func toFileDocument(companyID, taskID string, f *upload.Upload_file) *searchDocument {
+	content := f.Description
+	if f.Tags != "" {
+		content += "\n" + f.Tags
+	}
+	return &searchDocument{
+		Type: SearchDocFile, CompanyID: companyID, TaskID: taskID, NodeID: f.TaskNodeID, RefID: f.FileID,
+		Title: f.FileName, Content: content, CreateTime: f.CreateAt,
+	}
+}
+
+// commentSearchText 评论的索引文本，旧评论没有纯文本时提取原文的纯文本
+func commentSearchText(text, content string) string {
+	if text != "" {
+		return text
+	}
+	return utils.PlainText(content)
+}
+
+func searchDocID(docType, id string) string {
+	return docType + ":" + id
+}
+
+func termQuery(field, value string) query.Query {
+	q := bleve.NewTermQuery(value)
+	q.SetField(field)
+	return q
+}
+
+func termsQuery(field string, values []string) query.Query {
+	queries := make([]query.Query, 0, len(values))
+	for _, v := range values {
+		queries = append(queries, termQuery(field, v))
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+func fieldString(fields map[string]interface{}, name string) string {
+	if v, ok := fields[name].(string); ok {
+		return v
+	}
+	return ""
+}
@@ -23,6 +23,7 @@ const (
 	EventSuspiciousActivity   SecurityEventType = "suspicious_activity"
 	EventIPBlocked            SecurityEventType = "ip_blocked"
 	EventUnauthorizedAccess   SecurityEventType = "unauthorized_access"
+	EventFileRejected         SecurityEventType = "file_rejected"
 )
 
 // SecuritySeverity 安全事件严重程度
@@ -192,6 +193,19 @@ func (s *SecurityLogService) LogUnauthorizedAccess(ip, userID, path string) {
 	})
 }
 
+// LogFileRejected 记录被拦截的上传文件（内容与扩展名不符、可执行文件、检出病毒等）
+func (s *SecurityLogService) LogFileRejected(ip, userID, path, description string, extra map[string]any) {
+	s.Log(context.Background(), &SecurityLog{
+		EventType:   EventFileRejected,
+		Severity:    SeverityWarning,
+		IP:          ip,
+		UserID:      userID,
+		RequestPath: path,
+		Description: description,
+		Extra:       extra,
+	})
+}
+
 // Query 查询安全日志
 func (s *SecurityLogService) Query(ctx context.Context, filter SecurityLogFilter) ([]*SecurityLog, int64, error) {
 	query := bson.M{}
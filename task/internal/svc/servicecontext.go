@@ -56,20 +56,34 @@ type ServiceContext struct {
 	SecurityLogService *SecurityLogService
 
 	// 公司相关模型
-	CompanyModel    company.CompanyModel
-	DepartmentModel company.DepartmentModel
-	PositionModel   company.PositionModel
+	CompanyModel             company.CompanyModel
+	DepartmentModel          company.DepartmentModel
+	PositionModel            company.PositionModel
+	CompanyUploadPolicyModel company.CompanyUploadPolicyModel
+	CompanyStorageQuotaModel company.CompanyStorageQuotaModel
 
 	// 角色相关模型
 	RoleModel         role.RoleModel
 	PositionRoleModel role.PositionRoleModel
+	OperationLogModel role.OperationLogModel
+
+	// 请求审计服务（写入 operation_log）
+	AuditLogService *AuditLogService
+
+	// 运行时系统配置（system_config）
+	SystemConfigModel   role.SystemConfigModel
+	SystemConfigService *SystemConfigService
 
 	// 任务相关模型
 	TaskModel             task.TaskModel
 	TaskNodeModel         task.TaskNodeModel
+	TaskAssignmentModel   task.TaskAssignmentModel
+	TaskWatcherModel      task.TaskWatcherModel
 	TaskLogModel          task.TaskLogModel
 	TaskHandoverModel     task.TaskHandoverModel
 	HandoverApprovalModel task.HandoverApprovalModel
+	TaskHandoverItemModel task.TaskHandoverItemModel
+	OffboardingPlanModel  task.OffboardingPlanModel
 	TaskChecklistModel    task.TaskChecklistModel
 
 	// 通知相关模型
@@ -78,10 +92,62 @@ type ServiceContext struct {
 	// 权限相关模型
 	UserPermissionModel user_auth.UserPermissionModel
 
+	// 用户直接授权/临时委派服务
+	PermissionGrantService *PermissionGrantService
+
+	// 外出代理相关
+	OutOfOfficeModel   user.OutOfOfficeModel
+	OutOfOfficeService *OutOfOfficeService
+
 	// 加入公司相关
 	JoinApplicationModel user.JoinApplicationModel
 	InviteCodeService    *InviteCodeService
 
+	// 多公司成员身份（公司切换）
+	CompanyMembershipService *CompanyMembershipService
+
+	// 工时记录相关
+	TimeEntryModel      task.TimeEntryModel
+	TimesheetModel      task.TimesheetModel
+	TimeTrackingService *TimeTrackingService
+
+	// 统计快照（仪表盘 / 平台统计）
+	StatsSnapshotModel task.StatsSnapshotModel
+	PlatformStatsModel adminModel.PlatformStatsModel
+	AnalyticsService   *AnalyticsService
+
+	// 任务进度历史（燃尽图 / 累积流图 / 完成预测）
+	TaskHistoryService *TaskHistoryService
+
+	// 员工产能与工作负载
+	EmployeeCapacityModel user.EmployeeCapacityModel
+	CapacityService       *CapacityService
+
+	// 报表导出
+	ExportJobModel task.ExportJobModel
+	ExportService  *ExportService
+
+	// 批量导入
+	ImportJobModel task.ImportJobModel
+	ImportService  *ImportService
+
+	// 任务视图
+	TaskViewModel task.TaskViewModel
+
+	// 任务标签和自定义字段
+	TaskLabelModel     task.TaskLabelModel
+	CustomFieldModel   task.CustomFieldModel
+	CustomFieldService *CustomFieldService
+
+	// 看板
+	TaskBoardModel task.TaskBoardModel
+
+	// 全文搜索（索引打开失败时为 nil，搜索接口不可用）
+	SearchService *SearchService
+
+	// 用户内容的 Markdown 渲染、HTML 净化和引用解析
+	ContentService *ContentService
+
 	// MongoDB 相关模型
 	MongoURL               string                         // MongoDB 连接 URL
 	MongoDB                string                         // MongoDB 数据库名
@@ -89,6 +155,10 @@ type ServiceContext struct {
 	TaskProjectDetailModel task.Task_project_detailModel  // 任务详情模型
 	TaskCommentModel       task.Task_commentModel         // 任务评论模型(MongoDB)
 	AttachmentCommentModel upload.Attachment_commentModel // 附件评论标注模型(MongoDB)
+	FileVersionService     *FileVersionService            // 附件版本链
+	FileInspectionService  *FileInspectionService         // 上传文件类型校验、病毒扫描和预览图
+	UploadSessionService   *UploadSessionService          // 大文件分片上传和预签名直传
+	StorageQuotaService    *StorageQuotaService           // 公司和员工的存储配额与用量
 
 	// RabbitMQ 相关
 	MQClient              *MQClient              // RabbitMQ 客户端
@@ -229,8 +299,22 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	companyModel := company.NewCompanyModel(conn)
 	departmentModel := company.NewDepartmentModel(conn)
 	positionModel := company.NewPositionModel(conn)
+	companyUploadPolicyModel := company.NewCompanyUploadPolicyModel(conn)
+	uploadSessionModel := upload.NewUploadSessionModel(conn)
+	storageUsageModel := upload.NewStorageUsageModel(conn)
+	companyStorageQuotaModel := company.NewCompanyStorageQuotaModel(conn)
 	roleModel := role.NewRoleModel(conn)
 	positionRoleModel := role.NewPositionRoleModel(conn)
+	operationLogModel := role.NewOperationLogModel(conn)
+	systemConfigModel := role.NewSystemConfigModel(conn)
+	// 系统配置加密密钥，未单独配置时使用 JWT 密钥
+	configSecret := c.System.ConfigSecret
+	if configSecret == "" {
+		configSecret = c.JWT.SecretKey
+	}
+	userPermissionModel := user_auth.NewUserPermissionModel(conn)
+	outOfOfficeModel := user.NewOutOfOfficeModel(conn)
+	employeeCapacityModel := user.NewEmployeeCapacityModel(conn)
 
 	// 管理员相关模型
 	adminModelInstance := adminModel.NewAdminModel(conn)
@@ -248,10 +332,21 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	// 任务相关模型
 	taskModel := task.NewTaskModel(conn)
 	taskNodeModel := task.NewTaskNodeModel(conn)
+	taskAssignmentModel := task.NewTaskAssignmentModel(conn)
+	taskWatcherModel := task.NewTaskWatcherModel(conn)
 	taskLogModel := task.NewTaskLogModel(conn)
 	taskHandoverModel := task.NewTaskHandoverModel(conn)
 	handoverApprovalModel := task.NewHandoverApprovalModel(conn)
+	taskHandoverItemModel := task.NewTaskHandoverItemModel(conn)
+	offboardingPlanModel := task.NewOffboardingPlanModel(conn)
 	taskChecklistModel := task.NewTaskChecklistModel(conn)
+	timeEntryModel := task.NewTimeEntryModel(conn)
+	timesheetModel := task.NewTimesheetModel(conn)
+	exportJobModel := task.NewExportJobModel(conn)
+	importJobModel := task.NewImportJobModel(conn)
+	statsSnapshotModel := task.NewStatsSnapshotModel(conn)
+	customFieldModel := task.NewCustomFieldModel(conn)
+	platformStatsModel := adminModel.NewPlatformStatsModel(conn)
 
 	// 初始化 RabbitMQ
 	var mqClient *MQClient
@@ -387,32 +482,88 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		SecurityLogService: securityLogService,
 
 		// 公司相关模型
-		CompanyModel:    companyModel,
-		DepartmentModel: departmentModel,
-		PositionModel:   positionModel,
+		CompanyModel:             companyModel,
+		DepartmentModel:          departmentModel,
+		PositionModel:            positionModel,
+		CompanyUploadPolicyModel: companyUploadPolicyModel,
+		CompanyStorageQuotaModel: companyStorageQuotaModel,
 
 		// 角色相关模型
 		RoleModel:         roleModel,
 		PositionRoleModel: positionRoleModel,
+		OperationLogModel: operationLogModel,
+
+		// 运行时系统配置
+		SystemConfigModel:   systemConfigModel,
+		SystemConfigService: NewSystemConfigService(systemConfigModel, redisClient, configSecret),
 
 		// 任务相关模型
 		TaskModel:             taskModel,
 		TaskNodeModel:         taskNodeModel,
+		TaskAssignmentModel:   taskAssignmentModel,
+		TaskWatcherModel:      taskWatcherModel,
 		TaskLogModel:          taskLogModel,
 		TaskHandoverModel:     taskHandoverModel,
 		HandoverApprovalModel: handoverApprovalModel,
+		TaskHandoverItemModel: taskHandoverItemModel,
+		OffboardingPlanModel:  offboardingPlanModel,
 		TaskChecklistModel:    taskChecklistModel,
 
 		// 通知相关模型
 		NotificationModel: user_auth.NewNotificationModel(conn),
 
 		// 权限相关模型
-		UserPermissionModel: user_auth.NewUserPermissionModel(conn),
+		UserPermissionModel: userPermissionModel,
+
+		// 用户直接授权/临时委派服务
+		PermissionGrantService: NewPermissionGrantService(userPermissionModel, positionRoleModel, operationLogModel),
+
+		// 外出代理相关
+		OutOfOfficeModel:   outOfOfficeModel,
+		OutOfOfficeService: NewOutOfOfficeService(outOfOfficeModel, employeeModel, taskModel, taskNodeModel, taskHandoverModel, handoverApprovalModel, notificationMQService),
 
 		// 加入公司相关
 		JoinApplicationModel: user.NewJoinApplicationModel(conn),
 		InviteCodeService:    NewInviteCodeService(redisClient),
 
+		// 多公司成员身份（公司切换）
+		CompanyMembershipService: NewCompanyMembershipService(employeeModel, companyModel, redisClient),
+
+		// 工时记录相关
+		TimeEntryModel: timeEntryModel,
+		TimesheetModel: timesheetModel,
+
+		// 统计快照
+		StatsSnapshotModel: statsSnapshotModel,
+		PlatformStatsModel: platformStatsModel,
+		AnalyticsService:   NewAnalyticsService(statsSnapshotModel, platformStatsModel, companyModel, employeeModel),
+
+		// 任务进度历史
+		TaskHistoryService: NewTaskHistoryService(taskNodeModel, taskChecklistModel, taskLogModel, notificationMQService),
+
+		// 员工产能
+		EmployeeCapacityModel: employeeCapacityModel,
+
+		// 报表导出
+		ExportJobModel: exportJobModel,
+
+		// 批量导入
+		ImportJobModel: importJobModel,
+
+		// 任务视图
+		TaskViewModel: task.NewTaskViewModel(conn),
+
+		// 任务标签和自定义字段
+		TaskLabelModel:     task.NewTaskLabelModel(conn),
+		CustomFieldModel:   customFieldModel,
+		CustomFieldService: NewCustomFieldService(customFieldModel, employeeModel),
+
+		// 看板
+		TaskBoardModel: task.NewTaskBoardModel(conn),
+
+		// 用户内容
+		ContentService: NewContentService(employeeModel, departmentModel, roleModel, taskModel, taskNodeModel, task.NewTaskDetailContentModel(conn)),
+
 		// MongoDB 相关
 		MongoURL:               mongoURL,
 		MongoDB:                mongoDB,
@@ -437,6 +588,54 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		SQLExecutorService: NewSQLExecutorService(conn, "./model/sql"),
 	}
 
+	// 请求审计服务需要读取各业务实体快照，在模型初始化完成后创建
+	s.AuditLogService = NewAuditLogService(operationLogModel, s)
+
+	// 工时记录服务依赖外出代理服务（审批人外出时转给代理人）
+	s.TimeTrackingService = NewTimeTrackingService(timeEntryModel, timesheetModel, taskModel, taskNodeModel, employeeModel, departmentModel, companyModel, s.OutOfOfficeService, notificationMQService)
+
+	// 产能服务读取运行时配置（默认每周工时、超负荷阈值）
+	s.CapacityService = NewCapacityService(employeeModel, employeeCapacityModel, outOfOfficeModel, taskNodeModel, s.SystemConfigService)
+
+	// 导出服务把文件写入文件存储，并读取运行时配置（导出行数上限、文件保留天数）
+	s.ExportService = NewExportService(exportJobModel, fileStorageService, notificationMQService, s.SystemConfigService)
+
+	// 存储配额：公司单独设置的配额优先，否则使用系统设置
+	s.StorageQuotaService = NewStorageQuotaService(storageUsageModel, companyStorageQuotaModel, employeeModel, uploadFileModel, s.SystemConfigService)
+
+	// 附件版本服务读取运行时配置（保留版本数、历史版本保留天数）
+	s.FileVersionService = NewFileVersionService(uploadFileModel, fileStorageService, s.StorageQuotaService, s.SystemConfigService)
+
+	// 上传文件检查服务：公司上传策略、病毒扫描（按配置选择扫描器）和缩略图
+	var fileScanner FileScanner = NopFileScanner{}
+	switch c.FileStorage.Scanner.Type {
+	case "clamav":
+		fileScanner = NewClamAVScanner(c.FileStorage.Scanner.Address, c.FileStorage.Scanner.Timeout)
+		logx.Infof("[ServiceContext] 上传文件病毒扫描使用ClamAV: address=%s", c.FileStorage.Scanner.Address)
+	case "stub":
+		fileScanner = StubFileScanner{}
+		logx.Info("[ServiceContext] 上传文件病毒扫描使用测试扫描器（只检出EICAR测试文件）")
+	default:
+		logx.Info("[ServiceContext] 上传文件病毒扫描未启用")
+	}
+	s.FileInspectionService = NewFileInspectionService(companyUploadPolicyModel, s.SystemConfigService, securityLogService,
+		fileScanner, c.FileStorage.Scanner.FailOpen, NewFilePreviewer(c.FileStorage.Preview.PDFRenderer), fileStorageService)
+
+	// 大文件上传会话，完成时复用上传文件检查
+	s.UploadSessionService = NewUploadSessionService(uploadSessionModel, uploadFileModel, fileStorageService, s.FileInspectionService,
+		s.StorageQuotaService, s.SystemConfigService)
+
+	// 导入服务分批在事务中写入记录，并读取运行时配置（单个文件的行数上限）
+	s.ImportService = NewImportService(importJobModel, s.TransactionService, s.TransactionHelper, notificationMQService, s.SystemConfigService)
+
+	// 全文搜索索引由业务逻辑发布的事件增量更新
+	searchService, err := NewSearchService(c.Search.IndexPath, taskModel, taskNodeModel, taskChecklistModel, taskCommentModel, attachmentCommentModel, uploadFileModel)
+	if err != nil {
+		logx.Errorf("[ServiceContext] 全文搜索服务初始化失败: %v", err)
+	} else {
+		s.SearchService = searchService
+	}
+
 	// 初始化GLM服务
 	if c.GLM.APIKey != "" {
 		s.GLMService = NewGLMService(GLMConfig{
@@ -455,13 +654,26 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	} else {
 		logx.Info("[ServiceContext] 数据库迁移完成")
 	}
+
+	// 加载运行时系统配置并订阅热更新
+	registerBuiltinSettings(s.SystemConfigService, c)
+	s.SystemConfigService.Start(context.Background())
+	bindSystemSettings(s)
+
+	// 上次运行时未完成的导出任务不会继续执行
+	s.ExportService.FailInterrupted(context.Background())
+	// 上次运行时未完成的导入任务标记为已中断，可以从断点继续
+	s.ImportService.InterruptRunning(context.Background())
+	// 首次部署或索引目录被清空时在后台重建搜索索引
+	s.SearchService.RebuildIfEmpty()
+
 	s.Scheduler = NewSchedulerService(s)
 
 	// 设置Redis客户端给JWT中间件（用于Token验证）
 	jwtMiddleware.SetRedisClient(redisClient)
 
 	// 设置状态检查器给JWT中间件（用于实时检查用户/公司状态）
-	statusChecker := NewStatusCheckerService(userModel, companyModel)
+	statusChecker := NewStatusCheckerService(userModel, companyModel, employeeModel)
 	jwtMiddleware.SetStatusChecker(statusChecker)
 
 	// 启动消息队列消费者（在 ServiceContext 完全初始化后）
@@ -46,6 +46,28 @@ func (s *SQLExecutorService) AutoMigrate(ctx context.Context) error {
 		"add_has_joine_company.sql", // 注意：实际文件名是 add_has_joine_company.sql（少了一个d）
 		"task_node_completion_approval.sql",
 		"admin.sql",
+		"user_permission_grant.sql",
+		"employee_out_of_office.sql",
+		"operation_log_audit.sql",
+		"employee_multi_company.sql",
+		"time_tracking.sql",
+		"analytics_snapshot.sql",
+		"task_log_history.sql",
+		"employee_capacity.sql",
+		"export_job.sql",
+		"import_job.sql",
+		"task_view.sql",
+		"task_label.sql",
+		"custom_field.sql",
+		"task_board.sql",
+		"task_handover_item.sql",
+		"offboarding_plan.sql",
+		"task_assignment.sql",
+		"task_watcher.sql",
+		"company_upload_policy.sql",
+		"upload_session.sql",
+		"storage_quota.sql",
+		"task_detail_content.sql",
 	}
 
 	successCount := 0
@@ -146,7 +168,8 @@ func (s *SQLExecutorService) executeScriptFile(ctx context.Context, filePath, fi
 				strings.Contains(errStr, "Duplicate entry") ||
 				strings.Contains(errStr, "1060") || // Duplicate column name
 				strings.Contains(errStr, "1061") || // Duplicate key name
-				strings.Contains(errStr, "1050") { // Table already exists
+				strings.Contains(errStr, "1050") || // Table already exists
+				strings.Contains(errStr, "1091") { // Can't DROP（索引/字段已删除）
 				skipCount++
 				continue
 			}
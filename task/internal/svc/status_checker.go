@@ -12,15 +12,17 @@ import (
 
 // StatusCheckerService 用户和公司状态检查服务
 type StatusCheckerService struct {
-	userModel    user.UserModel
-	companyModel company.CompanyModel
+	userModel     user.UserModel
+	companyModel  company.CompanyModel
+	employeeModel user.EmployeeModel
 }
 
 // NewStatusCheckerService 创建状态检查服务
-func NewStatusCheckerService(userModel user.UserModel, companyModel company.CompanyModel) *StatusCheckerService {
+func NewStatusCheckerService(userModel user.UserModel, companyModel company.CompanyModel, employeeModel user.EmployeeModel) *StatusCheckerService {
 	return &StatusCheckerService{
-		userModel:    userModel,
-		companyModel: companyModel,
+		userModel:     userModel,
+		companyModel:  companyModel,
+		employeeModel: employeeModel,
 	}
 }
 
@@ -80,22 +82,25 @@ func (s *StatusCheckerService) CheckCompanyStatus(ctx context.Context, companyID
 }
 
 // CheckEmployeeStatus 检查员工是否已离职
+// 用户可在多家公司任职，按令牌中的当前公司检查对应的员工记录
 // 返回 nil 表示员工状态正常，返回 error 表示员工已离职
 func (s *StatusCheckerService) CheckEmployeeStatus(ctx context.Context, userID string, companyID string) error {
 	if userID == "" || companyID == "" {
 		return nil
 	}
 
-	userInfo, err := s.userModel.FindOne(ctx, userID)
+	employee, err := s.employeeModel.FindByUserIDAndCompanyID(ctx, userID, companyID)
 	if err != nil {
-		logx.Errorf("查询用户信息失败: %v, userId=%s", err, userID)
+		if errors.Is(err, user.ErrNotFound) {
+			return errors.New("您已离职，无法访问公司资源")
+		}
+		logx.Errorf("查询员工信息失败: %v, userId=%s, companyId=%s", err, userID, companyID)
 		// 查询失败时不阻止请求，避免数据库问题影响正常使用
 		return nil
 	}
 
-	// 检查用户是否已离职
-	// has_joined_company = 0 表示已离职或未加入公司
-	if userInfo.HasJoinedCompany == 0 {
+	// status = 0 表示已离职
+	if employee.Status == 0 {
 		return errors.New("您已离职，无法访问公司资源")
 	}
 
@@ -0,0 +1,229 @@
+package svc
+
+import (
+	"context"
+	"errors"
+
+	"task_Project/model/company"
+	"task_Project/model/upload"
+	"task_Project/model/user"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var (
+	// ErrStorageQuotaExceeded 公司存储配额已用完
+	ErrStorageQuotaExceeded = errors.New("公司存储空间不足，请清理附件或联系管理员调整配额")
+	// ErrStorageUserQuotaExceeded 员工存储配额已用完
+	ErrStorageUserQuotaExceeded = errors.New("您的存储空间不足，请清理附件或联系管理员调整配额")
+)
+
+// StorageLimits 公司生效的存储配额（字节），0 表示不限制
+type StorageLimits struct {
+	CompanyBytes int64
+	UserBytes    int64
+	// 公司单独设置的配额（MB），-1 表示使用系统设置；没有单独设置时 Customized 为 false
+	CompanyQuotaMB int64
+	UserQuotaMB    int64
+	Customized     bool
+}
+
+// StorageQuotaService 存储配额和用量。用量按附件的存储对象计算（见 Upload_file.StorageObjects），
+// 上传前占用配额，删除存储对象后释放；头像、缩略图和预览图不计入。
+// 公司第一次用到计数时按已有的附件记录统计一次，之后计数与实际文件出现偏差时可以用 Recalculate 重新统计
+type StorageQuotaService struct {
+	usageModel          upload.StorageUsageModel
+	quotaModel          company.CompanyStorageQuotaModel
+	employeeModel       user.EmployeeModel
+	uploadFileModel     upload.Upload_fileModel
+	systemConfigService *SystemConfigService
+}
+
+// NewStorageQuotaService 创建存储配额服务
+func NewStorageQuotaService(usageModel upload.StorageUsageModel, quotaModel company.CompanyStorageQuotaModel, employeeModel user.EmployeeModel,
+	uploadFileModel upload.Upload_fileModel, systemConfigService *SystemConfigService) *StorageQuotaService {
+	return &StorageQuotaService{
+		usageModel:          usageModel,
+		quotaModel:          quotaModel,
+		employeeModel:       employeeModel,
+		uploadFileModel:     uploadFileModel,
+		systemConfigService: systemConfigService,
+	}
+}
+
+// Limits 公司生效的存储配额：公司单独设置的优先，否则使用系统设置
+func (s *StorageQuotaService) Limits(ctx context.Context, companyID string) StorageLimits {
+	limits := StorageLimits{CompanyQuotaMB: -1, UserQuotaMB: -1}
+	q, err := s.quotaModel.FindOne(ctx, companyID)
+	switch {
+	case err == nil:
+		limits.CompanyQuotaMB = q.CompanyQuotaMb
+		limits.UserQuotaMB = q.UserQuotaMb
+		limits.Customized = true
+	case !errors.Is(err, company.ErrNotFound):
+		logx.WithContext(ctx).Errorf("[StorageQuota] 查询公司存储配额失败: companyId=%s, err=%v", companyID, err)
+	}
+	limits.CompanyBytes = quotaBytes(limits.CompanyQuotaMB, s.systemConfigService.GetInt(SettingStorageCompanyQuotaMB, 0))
+	limits.UserBytes = quotaBytes(limits.UserQuotaMB, s.systemConfigService.GetInt(SettingStorageUserQuotaMB, 0))
+	return limits
+}
+
+// quotaBytes 配额（MB）换算为字节，-1 时使用系统设置
+func quotaBytes(quotaMB int64, systemMB int) int64 {
+	if quotaMB < 0 {
+		quotaMB = int64(systemMB)
+	}
+	if quotaMB <= 0 {
+		return 0
+	}
+	return quotaMB * 1024 * 1024
+}
+
+// Check 检查是否还能上传 size 字节，不占用配额；用于开始大文件上传前提前提示
+func (s *StorageQuotaService) Check(ctx context.Context, companyID, employeeID string, size int64) error {
+	s.ensureCounted(ctx, companyID)
+	limits := s.Limits(ctx, companyID)
+	for _, c := range []struct {
+		employeeID string
+		limit      int64
+		err        error
+	}{
+		{"", limits.CompanyBytes, ErrStorageQuotaExceeded},
+		{employeeID, limits.UserBytes, ErrStorageUserQuotaExceeded},
+	} {
+		if c.limit == 0 {
+			continue
+		}
+		var used int64
+		usage, err := s.usageModel.FindOne(ctx, companyID, c.employeeID)
+		switch {
+		case err == nil:
+			used = usage.UsedBytes
+		case !errors.Is(err, upload.ErrNotFound):
+			return err
+		}
+		if used+size > c.limit {
+			return c.err
+		}
+	}
+	return nil
+}
+
+// Reserve 占用 size 字节的配额，超出时返回 ErrStorageQuotaExceeded 或 ErrStorageUserQuotaExceeded。
+// 占用后保存文件或写入附件记录失败时，调用方需要用 Release 释放
+func (s *StorageQuotaService) Reserve(ctx context.Context, companyID, employeeID string, size int64) error {
+	s.ensureCounted(ctx, companyID)
+	limits := s.Limits(ctx, companyID)
+	exceeded, err := s.usageModel.Charge(ctx, companyID, employeeID, size, limits.CompanyBytes, limits.UserBytes)
+	if err != nil {
+		return err
+	}
+	switch exceeded {
+	case upload.StorageScopeCompany:
+		return ErrStorageQuotaExceeded
+	case upload.StorageScopeUser:
+		return ErrStorageUserQuotaExceeded
+	}
+	return nil
+}
+
+// Release 释放一个存储对象占用的配额
+func (s *StorageQuotaService) Release(ctx context.Context, companyID, employeeID string, size int64) {
+	if err := s.usageModel.Release(ctx, companyID, employeeID, size, 1); err != nil {
+		logx.WithContext(ctx).Errorf("[StorageQuota] 释放存储用量失败: companyId=%s, employeeId=%s, size=%d, err=%v", companyID, employeeID, size, err)
+	}
+}
+
+// ReleaseFile 附件的全部存储对象被删除后释放占用的配额
+func (s *StorageQuotaService) ReleaseFile(ctx context.Context, file *upload.Upload_file) {
+	if file.StorageAccounted() {
+		s.ReleaseObjects(ctx, file.StorageObjects())
+	}
+}
+
+// ReleaseObjects 释放已删除的存储对象占用的配额，用量归属于对象的上传人
+func (s *StorageQuotaService) ReleaseObjects(ctx context.Context, objects []upload.Upload_fileVersion) {
+	type owner struct {
+		bytes int64
+		files int64
+	}
+	owners := make(map[string]*owner)
+	for _, o := range objects {
+		if owners[o.UploaderID] == nil {
+			owners[o.UploaderID] = &owner{}
+		}
+		owners[o.UploaderID].bytes += o.FileSize
+		owners[o.UploaderID].files++
+	}
+	for employeeID, o := range owners {
+		employee, err := s.employeeModel.FindOne(ctx, employeeID)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("[StorageQuota] 查询上传人失败，未释放存储用量: employeeId=%s, err=%v", employeeID, err)
+			continue
+		}
+		if err := s.usageModel.Release(ctx, employee.CompanyId, employeeID, o.bytes, o.files); err != nil {
+			logx.WithContext(ctx).Errorf("[StorageQuota] 释放存储用量失败: employeeId=%s, size=%d, err=%v", employeeID, o.bytes, err)
+		}
+	}
+}
+
+// Usage 公司合计（EmployeeId 为空）和各员工的用量计数
+func (s *StorageQuotaService) Usage(ctx context.Context, companyID string) ([]*upload.StorageUsage, error) {
+	s.ensureCounted(ctx, companyID)
+	return s.usageModel.FindByCompany(ctx, companyID)
+}
+
+// UsageOf 公司合计或一个员工的用量计数，没有记录时为 0
+func (s *StorageQuotaService) UsageOf(ctx context.Context, companyID, employeeID string) (*upload.StorageUsage, error) {
+	s.ensureCounted(ctx, companyID)
+	usage, err := s.usageModel.FindOne(ctx, companyID, employeeID)
+	if errors.Is(err, upload.ErrNotFound) {
+		return &upload.StorageUsage{CompanyId: companyID, EmployeeId: employeeID}, nil
+	}
+	return usage, err
+}
+
+// ensureCounted 公司还没有用量计数（启用配额前上传的附件没有计入）时按附件记录统计一次
+func (s *StorageQuotaService) ensureCounted(ctx context.Context, companyID string) {
+	_, err := s.usageModel.FindOne(ctx, companyID, "")
+	if !errors.Is(err, upload.ErrNotFound) {
+		return
+	}
+	if total, err := s.Recalculate(ctx, companyID); err != nil {
+		logx.WithContext(ctx).Errorf("[StorageQuota] 统计公司存储用量失败: companyId=%s, err=%v", companyID, err)
+	} else if total.FileCount > 0 {
+		logx.WithContext(ctx).Infof("[StorageQuota] 已统计公司存储用量: companyId=%s, bytes=%d, files=%d", companyID, total.UsedBytes, total.FileCount)
+	}
+}
+
+// Breakdown 按附件记录汇总公司的存储用量，groupBy 见 upload.StorageGroup*
+func (s *StorageQuotaService) Breakdown(ctx context.Context, companyID, groupBy string) ([]*upload.StorageUsageGroup, error) {
+	employees, err := s.employeeModel.FindAllByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(employees))
+	for _, e := range employees {
+		ids = append(ids, e.Id)
+	}
+	return s.uploadFileModel.StorageUsage(ctx, ids, groupBy)
+}
+
+// Recalculate 按附件记录重新统计公司的用量计数，返回公司合计
+func (s *StorageQuotaService) Recalculate(ctx context.Context, companyID string) (*upload.StorageUsage, error) {
+	groups, err := s.Breakdown(ctx, companyID, upload.StorageGroupUploader)
+	if err != nil {
+		return nil, err
+	}
+	total := &upload.StorageUsage{CompanyId: companyID}
+	rows := []*upload.StorageUsage{total}
+	for _, g := range groups {
+		rows = append(rows, &upload.StorageUsage{CompanyId: companyID, EmployeeId: g.Key, UsedBytes: g.Bytes, FileCount: g.Files})
+		total.UsedBytes += g.Bytes
+		total.FileCount += g.Files
+	}
+	if err := s.usageModel.Replace(ctx, companyID, rows); err != nil {
+		return nil, err
+	}
+	return total, nil
+}
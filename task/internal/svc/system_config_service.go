@@ -0,0 +1,626 @@
+package svc
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"task_Project/model/role"
+	"task_Project/task/internal/config"
+	"task_Project/task/internal/middleware"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+const (
+	systemConfigCacheKey      = "system_config:cache"   // 配置缓存（JSON，加密项保持密文）
+	systemConfigVersionKey    = "system_config:version" // 配置版本号，任一实例修改配置后递增
+	systemConfigCacheTTL      = 3600
+	systemConfigWatchInterval = 10 * time.Second
+	encryptedValuePrefix      = "enc:"
+)
+
+// 内置配置项的键
+const (
+	SettingRateLimitLogin        = "ratelimit.login_limit"
+	SettingRateLimitAPI          = "ratelimit.api_limit"
+	SettingRateLimitBurst        = "ratelimit.burst_size"
+	SettingRateLimitBlock        = "ratelimit.block_minutes"
+	SettingUploadMaxSizeMB       = "upload.max_file_size_mb"
+	SettingUploadVersionKeep     = "upload.version_retention_count"
+	SettingUploadVersionDays     = "upload.version_retention_days"
+	SettingUploadLargeMaxMB      = "upload.large_file_max_size_mb"
+	SettingUploadChunkSizeMB     = "upload.chunk_size_mb"
+	SettingUploadSessionHours    = "upload.session_expire_hours"
+	SettingStorageCompanyQuotaMB = "storage.company_quota_mb"
+	SettingStorageUserQuotaMB    = "storage.user_quota_mb"
+	SettingSchedulerWorkStart    = "scheduler.work_start_hour"
+	SettingSchedulerWorkEnd      = "scheduler.work_end_hour"
+	SettingSchedulerReportHour   = "scheduler.daily_report_hour"
+	SettingAnalyticsRefresh      = "scheduler.analytics_refresh_minutes"
+	SettingCapacityWeeklyHours   = "capacity.default_weekly_hours"
+	SettingCapacityOverload      = "capacity.overload_percent"
+	SettingExportMaxRows         = "export.max_rows"
+	SettingExportRetentionDays   = "export.retention_days"
+	SettingImportMaxRows         = "import.max_rows"
+	SettingEmailEnabled          = "email.enabled"
+	SettingEmailPassword         = "email.password"
+)
+
+// ErrSettingNotFound 配置项不存在
+var ErrSettingNotFound = errors.New("配置项不存在")
+
+// SettingDef 内置配置项定义
+type SettingDef struct {
+	Key         string
+	Type        int64 // 见 role.ConfigType*
+	Group       string
+	Description string
+	Default     string
+	Encrypted   bool                     // 是否加密存储
+	Validate    func(value string) error // 额外校验，可为空
+}
+
+// SettingUpdate 配置写入参数
+type SettingUpdate struct {
+	Key         string
+	Value       string
+	Type        int64
+	Group       string
+	Description string
+	Encrypted   bool
+	Status      int64
+	// KeepValue 保留已保存的值（加密配置项修改时只传了掩码或空值）
+	KeepValue bool
+}
+
+// SystemConfigService 运行时系统配置
+// 配置存储在 system_config 表，Redis 缓存全部配置并维护版本号，各实例轮询版本号实现热更新；
+// 服务通过 Subscribe 订阅配置变化，无需重新部署即可调整参数
+type SystemConfigService struct {
+	model       role.SystemConfigModel
+	redisClient *redis.Redis
+	aead        cipher.AEAD
+
+	defs map[string]*SettingDef
+
+	mu      sync.RWMutex
+	rows    map[string]*role.SystemConfig // 按配置键索引，值已解密
+	version string
+
+	subMu sync.Mutex
+	subs  map[string][]func(value string)
+
+	stopCh chan struct{}
+}
+
+// NewSystemConfigService 创建系统配置服务，secret 用于加密配置项
+func NewSystemConfigService(model role.SystemConfigModel, redisClient *redis.Redis, secret string) *SystemConfigService {
+	s := &SystemConfigService{
+		model:       model,
+		redisClient: redisClient,
+		defs:        make(map[string]*SettingDef),
+		rows:        make(map[string]*role.SystemConfig),
+		subs:        make(map[string][]func(value string)),
+		stopCh:      make(chan struct{}),
+	}
+	key := sha256.Sum256([]byte(secret))
+	if block, err := aes.NewCipher(key[:]); err == nil {
+		s.aead, _ = cipher.NewGCM(block)
+	}
+	return s
+}
+
+// Register 注册内置配置项
+func (s *SystemConfigService) Register(defs ...SettingDef) {
+	for i := range defs {
+		def := defs[i]
+		s.defs[def.Key] = &def
+	}
+}
+
+// Definition 获取内置配置项定义
+func (s *SystemConfigService) Definition(key string) (*SettingDef, bool) {
+	def, ok := s.defs[key]
+	return def, ok
+}
+
+// Definitions 获取全部内置配置项定义，按分组和键排序
+func (s *SystemConfigService) Definitions() []SettingDef {
+	out := make([]SettingDef, 0, len(s.defs))
+	for _, def := range s.defs {
+		out = append(out, *def)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Group != out[j].Group {
+			return out[i].Group < out[j].Group
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// Get 获取配置的生效值：已启用的数据库配置优先，否则为内置默认值
+func (s *SystemConfigService) Get(key string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	row, ok := s.rows[key]
+	s.mu.RUnlock()
+	if ok && row.Status == 1 {
+		return row.ConfigValue.String
+	}
+	if def, ok := s.defs[key]; ok {
+		return def.Default
+	}
+	return ""
+}
+
+// GetInt 获取整数配置，无法解析时返回 fallback
+func (s *SystemConfigService) GetInt(key string, fallback int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(s.Get(key)))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetBool 获取布尔配置，无法解析时返回 fallback
+func (s *SystemConfigService) GetBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(s.Get(key)))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// Subscribe 订阅配置变化，订阅时立即以当前生效值回调一次
+func (s *SystemConfigService) Subscribe(key string, fn func(value string)) {
+	if s == nil {
+		return
+	}
+	s.subMu.Lock()
+	s.subs[key] = append(s.subs[key], fn)
+	s.subMu.Unlock()
+	fn(s.Get(key))
+}
+
+// Rows 获取数据库中的全部配置（值已解密）
+func (s *SystemConfigService) Rows() []*role.SystemConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]*role.SystemConfig, 0, len(s.rows))
+	for _, row := range s.rows {
+		cp := *row
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ConfigKey < out[j].ConfigKey })
+	return out
+}
+
+// Row 获取数据库中的单个配置（值已解密）
+func (s *SystemConfigService) Row(key string) (*role.SystemConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	row, ok := s.rows[key]
+	if !ok {
+		return nil, false
+	}
+	cp := *row
+	return &cp, true
+}
+
+// Validate 校验配置值是否符合类型及内置规则
+func (s *SystemConfigService) Validate(key string, configType int64, value string) error {
+	if def, ok := s.defs[key]; ok {
+		configType = def.Type
+	}
+	switch configType {
+	case role.ConfigTypeString:
+	case role.ConfigTypeNumber:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+			return fmt.Errorf("配置 %s 必须是数字", key)
+		}
+	case role.ConfigTypeBool:
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("配置 %s 必须是 true 或 false", key)
+		}
+	case role.ConfigTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("配置 %s 必须是合法的 JSON", key)
+		}
+	default:
+		return fmt.Errorf("不支持的配置类型: %d", configType)
+	}
+	if def, ok := s.defs[key]; ok && def.Validate != nil {
+		return def.Validate(value)
+	}
+	return nil
+}
+
+// Set 新增或修改配置，内置配置项的类型、分组和加密方式以定义为准
+func (s *SystemConfigService) Set(ctx context.Context, u SettingUpdate) error {
+	isSystem := int64(0)
+	if def, ok := s.defs[u.Key]; ok {
+		u.Type, u.Encrypted, isSystem = def.Type, def.Encrypted, 1
+		if u.Group == "" {
+			u.Group = def.Group
+		}
+		if u.Description == "" {
+			u.Description = def.Description
+		}
+	}
+	if !u.KeepValue {
+		if err := s.Validate(u.Key, u.Type, u.Value); err != nil {
+			return err
+		}
+	}
+
+	stored := u.Value
+	if u.Encrypted && !u.KeepValue {
+		enc, err := s.encrypt(u.Value)
+		if err != nil {
+			return err
+		}
+		stored = enc
+	}
+	encrypted := int64(0)
+	if u.Encrypted {
+		encrypted = 1
+	}
+
+	existing, err := s.model.FindOneByConfigKey(ctx, u.Key)
+	switch {
+	case err == nil:
+		if !u.KeepValue {
+			existing.ConfigValue = sql.NullString{String: stored, Valid: true}
+		}
+		existing.ConfigType = u.Type
+		existing.ConfigGroup = utils.Common.ToSqlNullString(u.Group)
+		existing.Description = utils.Common.ToSqlNullString(u.Description)
+		existing.IsSystem = isSystem
+		existing.IsEncrypted = encrypted
+		existing.Status = u.Status
+		err = s.model.Update(ctx, existing)
+	case errors.Is(err, role.ErrNotFound):
+		if u.KeepValue {
+			return errors.New("配置项没有可保留的值")
+		}
+		_, err = s.model.Insert(ctx, &role.SystemConfig{
+			Id:          utils.Common.GenId("cfg"),
+			ConfigKey:   u.Key,
+			ConfigValue: sql.NullString{String: stored, Valid: true},
+			ConfigType:  u.Type,
+			ConfigGroup: utils.Common.ToSqlNullString(u.Group),
+			Description: utils.Common.ToSqlNullString(u.Description),
+			IsSystem:    isSystem,
+			IsEncrypted: encrypted,
+			Status:      u.Status,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return s.publish(ctx)
+}
+
+// Delete 删除配置，内置配置项删除后恢复默认值
+func (s *SystemConfigService) Delete(ctx context.Context, key string) error {
+	existing, err := s.model.FindOneByConfigKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, role.ErrNotFound) {
+			return ErrSettingNotFound
+		}
+		return err
+	}
+	if err := s.model.Delete(ctx, existing.Id); err != nil {
+		return err
+	}
+	return s.publish(ctx)
+}
+
+// Load 加载全部配置（优先读取 Redis 缓存），并通知生效值发生变化的订阅者
+func (s *SystemConfigService) Load(ctx context.Context) error {
+	rows, err := s.loadRows(ctx)
+	if err != nil {
+		return err
+	}
+	next := make(map[string]*role.SystemConfig, len(rows))
+	for _, row := range rows {
+		if row.IsEncrypted == 1 {
+			plain, err := s.decrypt(row.ConfigValue.String)
+			if err != nil {
+				logx.Errorf("[SystemConfig] 解密配置失败: key=%s, err=%v", row.ConfigKey, err)
+				continue
+			}
+			row.ConfigValue = sql.NullString{String: plain, Valid: true}
+		}
+		next[row.ConfigKey] = row
+	}
+
+	// 记录替换前的生效值，用于比较
+	s.subMu.Lock()
+	keys := make([]string, 0, len(s.subs))
+	for key := range s.subs {
+		keys = append(keys, key)
+	}
+	s.subMu.Unlock()
+	before := make(map[string]string, len(keys))
+	for _, key := range keys {
+		before[key] = s.Get(key)
+	}
+
+	s.mu.Lock()
+	s.rows = next
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if value := s.Get(key); value != before[key] {
+			logx.Infof("[SystemConfig] 配置已更新: key=%s", key)
+			s.notify(key, value)
+		}
+	}
+	return nil
+}
+
+// Start 首次加载配置并启动热更新轮询
+func (s *SystemConfigService) Start(ctx context.Context) {
+	if s.redisClient != nil {
+		if version, err := s.redisClient.Get(systemConfigVersionKey); err == nil {
+			s.version = version
+		}
+	}
+	if err := s.Load(ctx); err != nil {
+		logx.Errorf("[SystemConfig] 加载系统配置失败，使用默认值: %v", err)
+	}
+	go s.Watch()
+}
+
+// Watch 轮询配置版本号，其他实例修改配置后重新加载
+func (s *SystemConfigService) Watch() {
+	if s == nil || s.redisClient == nil {
+		return
+	}
+	ticker := time.NewTicker(systemConfigWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			version, err := s.redisClient.Get(systemConfigVersionKey)
+			if err != nil {
+				continue
+			}
+			s.mu.RLock()
+			changed := version != s.version
+			s.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := s.Load(ctx); err != nil {
+				logx.Errorf("[SystemConfig] 重新加载配置失败: %v", err)
+			} else {
+				s.mu.Lock()
+				s.version = version
+				s.mu.Unlock()
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop 停止配置轮询
+func (s *SystemConfigService) Stop() {
+	select {
+	case <-s.stopCh:
+	default:
+		close(s.stopCh)
+	}
+}
+
+// publish 配置写入后清除缓存、递增版本号并在本实例立即生效
+func (s *SystemConfigService) publish(ctx context.Context) error {
+	if s.redisClient != nil {
+		if _, err := s.redisClient.Del(systemConfigCacheKey); err != nil {
+			logx.Errorf("[SystemConfig] 清除配置缓存失败: %v", err)
+		}
+		if version, err := s.redisClient.Incr(systemConfigVersionKey); err == nil {
+			s.mu.Lock()
+			s.version = strconv.FormatInt(version, 10)
+			s.mu.Unlock()
+		}
+	}
+	return s.Load(ctx)
+}
+
+// loadRows 读取配置行，Redis 缓存未命中时查询数据库并回写缓存
+func (s *SystemConfigService) loadRows(ctx context.Context) ([]*role.SystemConfig, error) {
+	if s.redisClient != nil {
+		if cached, err := s.redisClient.Get(systemConfigCacheKey); err == nil && cached != "" {
+			var rows []*role.SystemConfig
+			if json.Unmarshal([]byte(cached), &rows) == nil {
+				return rows, nil
+			}
+		}
+	}
+	rows, err := s.model.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.redisClient != nil {
+		if data, err := json.Marshal(rows); err == nil {
+			if err := s.redisClient.Setex(systemConfigCacheKey, string(data), systemConfigCacheTTL); err != nil {
+				logx.Errorf("[SystemConfig] 写入配置缓存失败: %v", err)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (s *SystemConfigService) notify(key, value string) {
+	s.subMu.Lock()
+	fns := append([]func(string){}, s.subs[key]...)
+	s.subMu.Unlock()
+	for _, fn := range fns {
+		fn(value)
+	}
+}
+
+// encrypt 使用 AES-GCM 加密配置值
+func (s *SystemConfigService) encrypt(plain string) (string, error) {
+	if s.aead == nil {
+		return "", errors.New("配置加密不可用")
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt 解密配置值
+func (s *SystemConfigService) decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return value, nil
+	}
+	if s.aead == nil {
+		return "", errors.New("配置加密不可用")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedValuePrefix))
+	if err != nil {
+		return "", err
+	}
+	size := s.aead.NonceSize()
+	if len(data) < size {
+		return "", errors.New("密文长度错误")
+	}
+	plain, err := s.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// registerBuiltinSettings 注册内置配置项，默认值取自配置文件/环境变量
+func registerBuiltinSettings(s *SystemConfigService, c config.Config) {
+	_, loginLimit, apiLimit, burstSize, blockDuration := c.GetRateLimitConfig()
+	s.Register(
+		SettingDef{Key: SettingRateLimitLogin, Type: role.ConfigTypeNumber, Group: "ratelimit", Description: "登录接口限流（次/分钟）",
+			Default: strconv.Itoa(loginLimit), Validate: intRange(1, 10000)},
+		SettingDef{Key: SettingRateLimitAPI, Type: role.ConfigTypeNumber, Group: "ratelimit", Description: "普通接口限流（次/分钟）",
+			Default: strconv.Itoa(apiLimit), Validate: intRange(1, 100000)},
+		SettingDef{Key: SettingRateLimitBurst, Type: role.ConfigTypeNumber, Group: "ratelimit", Description: "突发容量",
+			Default: strconv.Itoa(burstSize), Validate: intRange(0, 10000)},
+		SettingDef{Key: SettingRateLimitBlock, Type: role.ConfigTypeNumber, Group: "ratelimit", Description: "超限封禁时长（分钟）",
+			Default: strconv.Itoa(blockDuration), Validate: intRange(1, 1440)},
+		SettingDef{Key: SettingUploadMaxSizeMB, Type: role.ConfigTypeNumber, Group: "upload", Description: "单个文件上传大小上限（MB）",
+			Default: "50", Validate: intRange(1, 1024)},
+		SettingDef{Key: SettingUploadVersionKeep, Type: role.ConfigTypeNumber, Group: "upload", Description: "每个附件保留的版本数（含当前版本），超出时删除最早的版本",
+			Default: "20", Validate: intRange(1, 200)},
+		SettingDef{Key: SettingUploadVersionDays, Type: role.ConfigTypeNumber, Group: "upload", Description: "附件历史版本保留天数，0 表示不按天数清理",
+			Default: "0", Validate: intRange(0, 3650)},
+		SettingDef{Key: SettingUploadLargeMaxMB, Type: role.ConfigTypeNumber, Group: "upload", Description: "分片上传和直传的单个文件大小上限（MB）",
+			Default: "2048", Validate: intRange(1, 51200)},
+		SettingDef{Key: SettingUploadChunkSizeMB, Type: role.ConfigTypeNumber, Group: "upload", Description: "分片上传的分片大小（MB）",
+			Default: "8", Validate: intRange(1, 64)},
+		SettingDef{Key: SettingUploadSessionHours, Type: role.ConfigTypeNumber, Group: "upload", Description: "分片上传和直传会话的有效期（小时），过期未完成的上传会被清理",
+			Default: "24", Validate: intRange(1, 168)},
+		SettingDef{Key: SettingStorageCompanyQuotaMB, Type: role.ConfigTypeNumber, Group: "storage", Description: "每个公司的存储配额（MB），0 表示不限制，公司可以单独设置",
+			Default: "0", Validate: intRange(0, 104857600)},
+		SettingDef{Key: SettingStorageUserQuotaMB, Type: role.ConfigTypeNumber, Group: "storage", Description: "每个员工的存储配额（MB），0 表示不限制，公司可以单独设置",
+			Default: "0", Validate: intRange(0, 104857600)},
+		SettingDef{Key: SettingSchedulerWorkStart, Type: role.ConfigTypeNumber, Group: "scheduler", Description: "定时提醒工作时间开始（时）",
+			Default: "9", Validate: intRange(0, 23)},
+		SettingDef{Key: SettingSchedulerWorkEnd, Type: role.ConfigTypeNumber, Group: "scheduler", Description: "定时提醒工作时间结束（时）",
+			Default: "18", Validate: intRange(1, 24)},
+		SettingDef{Key: SettingSchedulerReportHour, Type: role.ConfigTypeNumber, Group: "scheduler", Description: "每日汇报提醒时间（时）",
+			Default: "17", Validate: intRange(0, 23)},
+		SettingDef{Key: SettingAnalyticsRefresh, Type: role.ConfigTypeNumber, Group: "scheduler", Description: "统计快照刷新间隔（分钟）",
+			Default: "15", Validate: intRange(5, 1440)},
+		SettingDef{Key: SettingCapacityWeeklyHours, Type: role.ConfigTypeNumber, Group: "capacity", Description: "员工默认每周可用工时（小时）",
+			Default: "40", Validate: intRange(1, 80)},
+		SettingDef{Key: SettingCapacityOverload, Type: role.ConfigTypeNumber, Group: "capacity", Description: "超负荷阈值（利用率百分比）",
+			Default: "100", Validate: intRange(50, 300)},
+		SettingDef{Key: SettingExportMaxRows, Type: role.ConfigTypeNumber, Group: "export", Description: "单次报表导出的最大数据行数",
+			Default: "50000", Validate: intRange(100, 200000)},
+		SettingDef{Key: SettingExportRetentionDays, Type: role.ConfigTypeNumber, Group: "export", Description: "导出文件保留天数，过期后自动删除",
+			Default: "7", Validate: intRange(1, 90)},
+		SettingDef{Key: SettingImportMaxRows, Type: role.ConfigTypeNumber, Group: "import", Description: "单个导入文件的最大数据行数",
+			Default: "5000", Validate: intRange(10, 50000)},
+		SettingDef{Key: SettingEmailEnabled, Type: role.ConfigTypeBool, Group: "email", Description: "是否启用邮件发送",
+			Default: strconv.FormatBool(c.Email.Enabled)},
+		SettingDef{Key: SettingEmailPassword, Type: role.ConfigTypeString, Group: "email", Description: "SMTP 密码或授权码",
+			Default: c.Email.Password, Encrypted: true},
+	)
+}
+
+// bindSystemSettings 订阅需要热更新的配置项
+func bindSystemSettings(svcCtx *ServiceContext) {
+	settings := svcCtx.SystemConfigService
+	if limiter := svcCtx.RateLimiter; limiter != nil {
+		settings.Subscribe(SettingRateLimitLogin, func(string) {
+			limiter.UpdateConfig(func(cfg *middleware.RateLimiterConfig) {
+				cfg.LoginLimit = settings.GetInt(SettingRateLimitLogin, cfg.LoginLimit)
+			})
+		})
+		settings.Subscribe(SettingRateLimitAPI, func(string) {
+			limiter.UpdateConfig(func(cfg *middleware.RateLimiterConfig) {
+				cfg.APILimit = settings.GetInt(SettingRateLimitAPI, cfg.APILimit)
+			})
+		})
+		settings.Subscribe(SettingRateLimitBurst, func(string) {
+			limiter.UpdateConfig(func(cfg *middleware.RateLimiterConfig) {
+				cfg.BurstSize = settings.GetInt(SettingRateLimitBurst, cfg.BurstSize)
+			})
+		})
+		settings.Subscribe(SettingRateLimitBlock, func(string) {
+			limiter.UpdateConfig(func(cfg *middleware.RateLimiterConfig) {
+				minutes := settings.GetInt(SettingRateLimitBlock, int(cfg.BlockDuration/time.Minute))
+				cfg.BlockDuration = time.Duration(minutes) * time.Minute
+			})
+		})
+	}
+	if mailer := svcCtx.EmailMiddleware; mailer != nil {
+		settings.Subscribe(SettingEmailEnabled, func(string) {
+			mailer.UpdateConfig(func(cfg *middleware.EmailConfig) {
+				cfg.Enabled = settings.GetBool(SettingEmailEnabled, cfg.Enabled)
+			})
+		})
+		settings.Subscribe(SettingEmailPassword, func(value string) {
+			mailer.UpdateConfig(func(cfg *middleware.EmailConfig) {
+				cfg.Password = value
+			})
+		})
+	}
+}
+
+// intRange 生成整数范围校验函数
+func intRange(min, max int) func(string) error {
+	return func(value string) error {
+		v, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || v < min || v > max {
+			return fmt.Errorf("取值范围为 %d-%d 的整数", min, max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,499 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"task_Project/model/task"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 燃尽图统计口径
+const (
+	BurnUnitNode      = "node"      // 按任务节点
+	BurnUnitChecklist = "checklist" // 按节点清单
+)
+
+// 风险原因
+const (
+	TaskRiskNoProgress     = "no_progress"     // 近期没有完成任何工作项
+	TaskRiskBehindSchedule = "behind_schedule" // 按当前速度预计晚于截止时间完成
+	TaskRiskOverdue        = "overdue"         // 已过截止时间仍未完成
+)
+
+const (
+	taskVelocityWindowDays = 14  // 计算当前速度的窗口天数
+	maxTaskHistoryDays     = 366 // 时间序列的最大天数
+)
+
+// 节点状态 0--未开始 1--进行中 2--已完成 3--已逾期；清单 0--未完成 1--已完成
+const (
+	taskHistoryNodeNew      = 0
+	taskHistoryNodeProgress = 1
+	taskHistoryNodeComplete = 2
+	taskHistoryNodeOverdue  = 3
+	taskHistoryChecklistNew = 0
+	taskHistoryChecklistEnd = 1
+)
+
+// TaskBurnPoint 燃尽/燃起图中某一天的数据
+type TaskBurnPoint struct {
+	Date           time.Time
+	Scope          int64   // 当天结束时的工作项总数
+	Completed      int64   // 当天结束时已完成的工作项数
+	Remaining      int64   // 当天结束时剩余的工作项数
+	IdealRemaining float64 // 理想剩余量（从开始到截止时间线性燃尽）
+}
+
+// TaskFlowPoint 累积流图中某一天各节点状态的数量
+type TaskFlowPoint struct {
+	Date       time.Time
+	NotStarted int64
+	InProgress int64
+	Overdue    int64 // 未完成且已过节点截止时间
+	Completed  int64
+}
+
+// TaskForecast 按当前速度预测任务完成时间
+type TaskForecast struct {
+	Unit             string
+	Scope            int64
+	Completed        int64
+	Remaining        int64
+	Velocity         float64      // 近期平均每天完成的工作项数
+	RequiredVelocity float64      // 按期完成需要的每天完成数
+	ProjectedDate    sql.NullTime // 预计完成日期，速度为 0 时无法预测
+	Deadline         time.Time
+	AtRisk           bool
+	RiskReason       string
+}
+
+// taskStatusChange 工作项的一次状态变化
+type taskStatusChange struct {
+	at     time.Time
+	status int64
+}
+
+// taskItemTimeline 单个工作项（节点或清单）的状态历史
+type taskItemTimeline struct {
+	created  time.Time
+	deadline time.Time
+	changes  []taskStatusChange // 按时间升序，第一条为创建时的状态
+}
+
+// statusAt 返回某个时间点的状态，工作项尚未创建时返回 false
+func (t *taskItemTimeline) statusAt(at time.Time) (int64, bool) {
+	if t.created.After(at) {
+		return 0, false
+	}
+	status := t.changes[0].status
+	for _, c := range t.changes[1:] {
+		if c.at.After(at) {
+			break
+		}
+		status = c.status
+	}
+	return status, true
+}
+
+// taskHistory 任务节点和清单的状态历史
+type taskHistory struct {
+	task       *task.Task
+	nodes      []*taskItemTimeline
+	checklists []*taskItemTimeline
+}
+
+// items 按统计口径返回工作项及其完成状态值
+func (h *taskHistory) items(unit string) ([]*taskItemTimeline, int64) {
+	if unit == BurnUnitChecklist {
+		return h.checklists, taskHistoryChecklistEnd
+	}
+	return h.nodes, taskHistoryNodeComplete
+}
+
+// countAt 统计某个时间点的工作项总数和已完成数
+func (h *taskHistory) countAt(unit string, at time.Time) (scope, completed int64) {
+	items, done := h.items(unit)
+	for _, item := range items {
+		status, ok := item.statusAt(at)
+		if !ok {
+			continue
+		}
+		scope++
+		if status == done {
+			completed++
+		}
+	}
+	return scope, completed
+}
+
+// TaskHistoryService 任务进度历史：记录节点与清单的状态变更，重建燃尽图、燃起图、累积流图并预测完成时间
+type TaskHistoryService struct {
+	taskNodeModel         task.TaskNodeModel
+	taskChecklistModel    task.TaskChecklistModel
+	taskLogModel          task.TaskLogModel
+	notificationMQService *NotificationMQService
+}
+
+// NewTaskHistoryService 创建任务进度历史服务
+func NewTaskHistoryService(taskNodeModel task.TaskNodeModel, taskChecklistModel task.TaskChecklistModel, taskLogModel task.TaskLogModel,
+	notificationMQService *NotificationMQService) *TaskHistoryService {
+	return &TaskHistoryService{
+		taskNodeModel:         taskNodeModel,
+		taskChecklistModel:    taskChecklistModel,
+		taskLogModel:          taskLogModel,
+		notificationMQService: notificationMQService,
+	}
+}
+
+// NodeStatusText 节点状态名称
+func NodeStatusText(status int64) string {
+	switch status {
+	case taskHistoryNodeNew:
+		return "未开始"
+	case taskHistoryNodeProgress:
+		return "进行中"
+	case taskHistoryNodeComplete:
+		return "已完成"
+	case taskHistoryNodeOverdue:
+		return "已逾期"
+	}
+	return "未知"
+}
+
+// RecordNodeStatus 记录节点状态变更，状态未变化时忽略；记录失败只写日志，不影响业务流程
+func (s *TaskHistoryService) RecordNodeStatus(ctx context.Context, taskID, nodeID, nodeName, employeeID string, from, to int64) {
+	if from == to {
+		return
+	}
+	log := &task.TaskLog{
+		LogId:      utils.NewCommon().GenerateIDWithPrefix("task_log"),
+		TaskId:     taskID,
+		TaskNodeId: sql.NullString{String: nodeID, Valid: nodeID != ""},
+		EmployeeId: employeeID,
+		LogType:    task.TaskLogTypeStatus,
+		LogContent: fmt.Sprintf("节点「%s」状态变更：%s → %s", nodeName, NodeStatusText(from), NodeStatusText(to)),
+	}
+	event := &task.TaskLogEvent{
+		EventType:  task.TaskLogEventNodeStatus,
+		FromStatus: sql.NullInt64{Int64: from, Valid: true},
+		ToStatus:   to,
+	}
+	if err := s.taskLogModel.InsertEvent(ctx, log, event); err != nil {
+		logx.WithContext(ctx).Errorf("记录节点状态变更失败: taskNodeId=%s, err=%v", nodeID, err)
+	}
+	// 节点完成另有 TaskNodeCompleted 通知
+	if to != taskHistoryNodeComplete {
+		s.notifyWatchers(ctx, TaskNodeStatusChanged, taskID, nodeID, employeeID, "节点状态变更",
+			fmt.Sprintf("您关注的任务节点 %s 状态变更：%s → %s", nodeName, NodeStatusText(from), NodeStatusText(to)))
+	}
+}
+
+// RecordChecklistStatus 记录清单完成状态变更；记录失败只写日志，不影响业务流程
+func (s *TaskHistoryService) RecordChecklistStatus(ctx context.Context, taskID, nodeID, checklistID, content, employeeID string, done bool) {
+	from, to := int64(taskHistoryChecklistEnd), int64(taskHistoryChecklistNew)
+	text := "未完成"
+	if done {
+		from, to = taskHistoryChecklistNew, taskHistoryChecklistEnd
+		text = "已完成"
+	}
+	log := &task.TaskLog{
+		LogId:      utils.NewCommon().GenerateIDWithPrefix("task_log"),
+		TaskId:     taskID,
+		TaskNodeId: sql.NullString{String: nodeID, Valid: nodeID != ""},
+		EmployeeId: employeeID,
+		LogType:    task.TaskLogTypeStatus,
+		LogContent: fmt.Sprintf("清单「%s」标记为%s", content, text),
+	}
+	event := &task.TaskLogEvent{
+		EventType:   task.TaskLogEventChecklistStatus,
+		ChecklistId: sql.NullString{String: checklistID, Valid: true},
+		FromStatus:  sql.NullInt64{Int64: from, Valid: true},
+		ToStatus:    to,
+	}
+	if err := s.taskLogModel.InsertEvent(ctx, log, event); err != nil {
+		logx.WithContext(ctx).Errorf("记录清单状态变更失败: checklistId=%s, err=%v", checklistID, err)
+	}
+	s.notifyWatchers(ctx, TaskChecklistChanged, taskID, nodeID, employeeID, "清单状态变更",
+		fmt.Sprintf("您关注的任务节点中的清单「%s」标记为%s", content, text))
+}
+
+// notifyWatchers 通知任务和节点的关注者（不通知操作人本人）
+func (s *TaskHistoryService) notifyWatchers(ctx context.Context, eventType, taskID, nodeID, employeeID, title, content string) {
+	if s.notificationMQService == nil {
+		return
+	}
+	event := s.notificationMQService.NewNotificationEvent(eventType, nil, nodeID, NotificationEventOptions{TaskID: taskID, NodeID: nodeID})
+	event.Title = title
+	event.Content = content
+	event.Type = 3 // 类型: 系统通知
+	event.Priority = 1
+	event.RelatedType = "tasknode"
+	event.ExcludeEmployeeIDs = []string{employeeID}
+	if err := s.notificationMQService.PublishNotificationEvent(ctx, event); err != nil {
+		logx.WithContext(ctx).Errorf("发送关注通知失败: eventType=%s, taskNodeId=%s, err=%v", eventType, nodeID, err)
+	}
+}
+
+// loadHistory 加载任务的节点、清单和状态变更记录，重建每个工作项的状态历史。
+// 功能上线前的数据没有状态变更记录，按节点开始/完成时间和清单完成时间推算。
+func (s *TaskHistoryService) loadHistory(ctx context.Context, taskInfo *task.Task) (*taskHistory, error) {
+	nodes, err := s.taskNodeModel.FindByTaskID(ctx, taskInfo.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	checklists, err := s.taskChecklistModel.FindByTaskId(ctx, taskInfo.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.taskLogModel.FindEventsByTask(ctx, taskInfo.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeEvents := make(map[string][]*task.TaskLogEvent)
+	checklistEvents := make(map[string][]*task.TaskLogEvent)
+	for _, e := range events {
+		switch e.EventType {
+		case task.TaskLogEventNodeStatus:
+			if e.TaskNodeId.Valid {
+				nodeEvents[e.TaskNodeId.String] = append(nodeEvents[e.TaskNodeId.String], e)
+			}
+		case task.TaskLogEventChecklistStatus:
+			if e.ChecklistId.Valid {
+				checklistEvents[e.ChecklistId.String] = append(checklistEvents[e.ChecklistId.String], e)
+			}
+		}
+	}
+
+	h := &taskHistory{task: taskInfo}
+	for _, n := range nodes {
+		created := n.CreateTime
+		if created.IsZero() {
+			created = n.NodeStartTime
+		}
+		item := &taskItemTimeline{created: created, deadline: n.NodeDeadline}
+		if list := nodeEvents[n.TaskNodeId]; len(list) > 0 {
+			initial := int64(taskHistoryNodeNew)
+			if list[0].FromStatus.Valid {
+				initial = list[0].FromStatus.Int64
+			}
+			item.changes = append(item.changes, taskStatusChange{at: created, status: initial})
+			for _, e := range list {
+				item.changes = append(item.changes, taskStatusChange{at: e.CreateTime, status: e.ToStatus})
+			}
+		} else {
+			item.changes = legacyNodeChanges(n, created)
+		}
+		h.nodes = append(h.nodes, item)
+	}
+	for _, c := range checklists {
+		item := &taskItemTimeline{created: c.CreateTime}
+		item.changes = append(item.changes, taskStatusChange{at: c.CreateTime, status: taskHistoryChecklistNew})
+		if list := checklistEvents[c.ChecklistId]; len(list) > 0 {
+			item.changes[0].status = list[0].FromStatus.Int64
+			for _, e := range list {
+				item.changes = append(item.changes, taskStatusChange{at: e.CreateTime, status: e.ToStatus})
+			}
+		} else if c.IsCompleted == 1 {
+			at := c.UpdateTime
+			if c.CompleteTime.Valid {
+				at = c.CompleteTime.Time
+			}
+			item.changes = append(item.changes, taskStatusChange{at: at, status: taskHistoryChecklistEnd})
+		}
+		h.checklists = append(h.checklists, item)
+	}
+	return h, nil
+}
+
+// legacyNodeChanges 没有状态变更记录的节点：按开始时间进入进行中，按完成时间（无则取更新时间）完成
+func legacyNodeChanges(n *task.TaskNode, created time.Time) []taskStatusChange {
+	changes := []taskStatusChange{{at: created, status: taskHistoryNodeNew}}
+	switch n.NodeStatus {
+	case taskHistoryNodeProgress, taskHistoryNodeOverdue:
+		at := n.NodeStartTime
+		if at.Before(created) {
+			at = created
+		}
+		changes = append(changes, taskStatusChange{at: at, status: n.NodeStatus})
+	case taskHistoryNodeComplete:
+		at := n.UpdateTime
+		if n.NodeFinishTime.Valid {
+			at = n.NodeFinishTime.Time
+		}
+		if at.Before(created) {
+			at = created
+		}
+		changes = append(changes, taskStatusChange{at: at, status: taskHistoryNodeComplete})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.Before(changes[j].at) })
+	return changes
+}
+
+// historyDays 返回时间序列的日期：从任务开始日到今天（最多 maxTaskHistoryDays 天）
+func historyDays(taskInfo *task.Task, now time.Time) []time.Time {
+	end := StatsDay(now)
+	start := StatsDay(taskInfo.TaskStartTime)
+	if !taskInfo.CreateTime.IsZero() && StatsDay(taskInfo.CreateTime).Before(start) {
+		start = StatsDay(taskInfo.CreateTime)
+	}
+	if start.After(end) {
+		start = end
+	}
+	if earliest := end.AddDate(0, 0, -(maxTaskHistoryDays - 1)); start.Before(earliest) {
+		start = earliest
+	}
+	days := make([]time.Time, 0, int(end.Sub(start).Hours()/24)+1)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+// NormalizeBurnUnit 统计口径默认按节点
+func NormalizeBurnUnit(unit string) string {
+	if unit == BurnUnitChecklist {
+		return BurnUnitChecklist
+	}
+	return BurnUnitNode
+}
+
+// Burn 返回任务每天的工作项总数、已完成数、剩余数和理想剩余量，用于燃尽图和燃起图
+func (s *TaskHistoryService) Burn(ctx context.Context, taskInfo *task.Task, unit string) ([]TaskBurnPoint, error) {
+	h, err := s.loadHistory(ctx, taskInfo)
+	if err != nil {
+		return nil, err
+	}
+	unit = NormalizeBurnUnit(unit)
+	now := time.Now()
+	days := historyDays(taskInfo, now)
+	scopeNow, _ := h.countAt(unit, now)
+
+	// 理想线：按当前总量从第一天线性燃尽到截止日
+	idealStart := days[0]
+	idealEnd := StatsDay(taskInfo.TaskDeadline)
+	span := idealEnd.Sub(idealStart).Hours() / 24
+
+	points := make([]TaskBurnPoint, 0, len(days))
+	for _, d := range days {
+		dayEnd := d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if dayEnd.After(now) {
+			dayEnd = now
+		}
+		scope, completed := h.countAt(unit, dayEnd)
+		ideal := 0.0
+		if span > 0 {
+			left := idealEnd.Sub(d).Hours() / 24
+			ideal = math.Max(0, float64(scopeNow)*left/span)
+		}
+		points = append(points, TaskBurnPoint{
+			Date:           d,
+			Scope:          scope,
+			Completed:      completed,
+			Remaining:      scope - completed,
+			IdealRemaining: math.Round(ideal*100) / 100,
+		})
+	}
+	return points, nil
+}
+
+// CumulativeFlow 返回任务每天各节点状态的数量，用于累积流图
+func (s *TaskHistoryService) CumulativeFlow(ctx context.Context, taskInfo *task.Task) ([]TaskFlowPoint, error) {
+	h, err := s.loadHistory(ctx, taskInfo)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	days := historyDays(taskInfo, now)
+	points := make([]TaskFlowPoint, 0, len(days))
+	for _, d := range days {
+		dayEnd := d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if dayEnd.After(now) {
+			dayEnd = now
+		}
+		point := TaskFlowPoint{Date: d}
+		for _, n := range h.nodes {
+			status, ok := n.statusAt(dayEnd)
+			if !ok {
+				continue
+			}
+			switch {
+			case status == taskHistoryNodeComplete:
+				point.Completed++
+			case !n.deadline.IsZero() && n.deadline.Before(dayEnd):
+				point.Overdue++
+			case status == taskHistoryNodeNew:
+				point.NotStarted++
+			default:
+				point.InProgress++
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// Forecast 按近期完成速度预测任务完成日期，并与截止时间比较判断是否存在延期风险
+func (s *TaskHistoryService) Forecast(ctx context.Context, taskInfo *task.Task, unit string) (*TaskForecast, error) {
+	h, err := s.loadHistory(ctx, taskInfo)
+	if err != nil {
+		return nil, err
+	}
+	return forecastHistory(h, NormalizeBurnUnit(unit), time.Now()), nil
+}
+
+// forecastHistory 根据状态历史计算预测结果
+func forecastHistory(h *taskHistory, unit string, now time.Time) *TaskForecast {
+	scope, completed := h.countAt(unit, now)
+	f := &TaskForecast{
+		Unit:      unit,
+		Scope:     scope,
+		Completed: completed,
+		Remaining: scope - completed,
+		Deadline:  h.task.TaskDeadline,
+	}
+
+	// 速度窗口：最近 14 天，任务开始不足 14 天时按实际天数
+	days := historyDays(h.task, now)
+	window := taskVelocityWindowDays
+	if len(days) < window {
+		window = len(days)
+	}
+	_, before := h.countAt(unit, StatsDay(now).AddDate(0, 0, -window+1).Add(-time.Nanosecond))
+	f.Velocity = math.Round(float64(completed-before)/float64(window)*100) / 100
+
+	if f.Remaining <= 0 {
+		f.ProjectedDate = sql.NullTime{Time: StatsDay(now), Valid: true}
+		return f
+	}
+
+	today := StatsDay(now)
+	deadlineDay := StatsDay(h.task.TaskDeadline)
+	daysLeft := deadlineDay.Sub(today).Hours()/24 + 1
+	if daysLeft < 1 {
+		daysLeft = 1
+	}
+	f.RequiredVelocity = math.Round(float64(f.Remaining)/daysLeft*100) / 100
+
+	if f.Velocity > 0 {
+		need := int(math.Ceil(float64(f.Remaining)/f.Velocity)) - 1
+		f.ProjectedDate = sql.NullTime{Time: today.AddDate(0, 0, need), Valid: true}
+	}
+
+	switch {
+	case h.task.TaskDeadline.Before(now):
+		f.AtRisk, f.RiskReason = true, TaskRiskOverdue
+	case f.Velocity == 0 && h.task.TaskStartTime.Before(today):
+		f.AtRisk, f.RiskReason = true, TaskRiskNoProgress
+	case f.ProjectedDate.Time.After(deadlineDay):
+		f.AtRisk, f.RiskReason = true, TaskRiskBehindSchedule
+	}
+	return f
+}
@@ -0,0 +1,420 @@
+package svc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"task_Project/model/company"
+	"task_Project/model/task"
+	"task_Project/model/user"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 每个预计工作日折算的工时（小时），与 Converter 中节点工时的折算一致
+const hoursPerEstimatedDay = 8
+
+// MaxEntryMinutes 单条工时记录的最大时长（分钟），忘记停止的计时按该时长记录
+const MaxEntryMinutes = 24 * 60
+
+var (
+	ErrTimerRunning       = errors.New("timer already running")
+	ErrTimerNotRunning    = errors.New("no running timer")
+	ErrTimeEntryLocked    = errors.New("timesheet of the week is submitted")
+	ErrTimerWeekLocked    = errors.New("timesheet of the timer week is submitted, timer discarded")
+	ErrTimesheetRunning   = errors.New("timer of the week is still running")
+	ErrTimesheetEmpty     = errors.New("no time entries in the week")
+	ErrTimesheetSubmitted = errors.New("timesheet already submitted")
+	ErrTimesheetProcessed = errors.New("timesheet already processed")
+	ErrTimesheetForbidden = errors.New("no permission to review timesheet")
+)
+
+// TimeReportRow 预计与实际工时对比的一行（按员工或部门）
+type TimeReportRow struct {
+	Key              string // 员工ID或部门ID
+	EstimatedMinutes int64  // 参与节点的预计工时（分钟）
+	ActualMinutes    int64  // 已记录工时（分钟）
+	NodeCount        int    // 涉及的节点数
+}
+
+// TimeTrackingService 工时记录服务：计时器、手工填报、工时汇总、周工时单审批和工时报表
+type TimeTrackingService struct {
+	timeEntryModel        task.TimeEntryModel
+	timesheetModel        task.TimesheetModel
+	taskModel             task.TaskModel
+	taskNodeModel         task.TaskNodeModel
+	employeeModel         user.EmployeeModel
+	departmentModel       company.DepartmentModel
+	companyModel          company.CompanyModel
+	outOfOfficeService    *OutOfOfficeService
+	notificationMQService *NotificationMQService
+}
+
+// NewTimeTrackingService 创建工时记录服务
+func NewTimeTrackingService(timeEntryModel task.TimeEntryModel, timesheetModel task.TimesheetModel, taskModel task.TaskModel, taskNodeModel task.TaskNodeModel,
+	employeeModel user.EmployeeModel, departmentModel company.DepartmentModel, companyModel company.CompanyModel,
+	outOfOfficeService *OutOfOfficeService, notificationMQService *NotificationMQService) *TimeTrackingService {
+	return &TimeTrackingService{
+		timeEntryModel:        timeEntryModel,
+		timesheetModel:        timesheetModel,
+		taskModel:             taskModel,
+		taskNodeModel:         taskNodeModel,
+		employeeModel:         employeeModel,
+		departmentModel:       departmentModel,
+		companyModel:          companyModel,
+		outOfOfficeService:    outOfOfficeService,
+		notificationMQService: notificationMQService,
+	}
+}
+
+// WeekStart 返回日期所在周的周一（本地时区零点）
+func WeekStart(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// WeekLocked 判断员工某天所在周的工时单是否已提交（待审批或已通过）
+func (s *TimeTrackingService) WeekLocked(ctx context.Context, employeeID string, date time.Time) (bool, error) {
+	sheet, err := s.timesheetModel.FindByEmployeeWeek(ctx, employeeID, WeekStart(date))
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return sheet.Locked(), nil
+}
+
+// StartTimer 为员工在节点上开始计时，同一时间只允许一个计时器
+func (s *TimeTrackingService) StartTimer(ctx context.Context, employee *user.Employee, node *task.TaskNode, checklistID, note string) (*task.TimeEntry, error) {
+	if running, err := s.timeEntryModel.FindRunningByEmployee(ctx, employee.Id); err == nil && running != nil {
+		return running, ErrTimerRunning
+	} else if err != nil && !errors.Is(err, task.ErrNotFound) {
+		return nil, err
+	}
+	now := time.Now()
+	if locked, err := s.WeekLocked(ctx, employee.Id, now); err != nil {
+		return nil, err
+	} else if locked {
+		return nil, ErrTimeEntryLocked
+	}
+
+	entry := &task.TimeEntry{
+		Id:          utils.Common.GenId("te"),
+		CompanyId:   employee.CompanyId,
+		TaskId:      node.TaskId,
+		TaskNodeId:  node.TaskNodeId,
+		ChecklistId: utils.Common.ToSqlNullString(checklistID),
+		EmployeeId:  employee.Id,
+		EntryType:   task.TimeEntryTypeTimer,
+		WorkDate:    now,
+		StartTime:   sql.NullTime{Time: now, Valid: true},
+		Note:        utils.Common.ToSqlNullString(note),
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	if _, err := s.timeEntryModel.Insert(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer 停止员工正在运行的计时器，按实际经过时间（向上取整到分钟）记录时长，最长记录 MaxEntryMinutes。
+// 计时所在周的工时单已提交时不能再计入，计时作废并返回 ErrTimerWeekLocked
+func (s *TimeTrackingService) StopTimer(ctx context.Context, employeeID string) (*task.TimeEntry, error) {
+	entry, err := s.timeEntryModel.FindRunningByEmployee(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, ErrTimerNotRunning
+		}
+		return nil, err
+	}
+	if locked, err := s.WeekLocked(ctx, employeeID, entry.WorkDate); err != nil {
+		return nil, err
+	} else if locked {
+		if err := s.timeEntryModel.SoftDelete(ctx, entry.Id); err != nil {
+			return nil, err
+		}
+		return nil, ErrTimerWeekLocked
+	}
+	end := time.Now()
+	minutes := int64(math.Ceil(end.Sub(entry.StartTime.Time).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes > MaxEntryMinutes {
+		minutes = MaxEntryMinutes
+		end = entry.StartTime.Time.Add(MaxEntryMinutes * time.Minute)
+	}
+	entry.EndTime = sql.NullTime{Time: end, Valid: true}
+	entry.DurationMinutes = minutes
+	if err := s.timeEntryModel.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.RollUp(ctx, entry.TaskId, entry.TaskNodeId)
+	return entry, nil
+}
+
+// AddManual 手工填报工时
+func (s *TimeTrackingService) AddManual(ctx context.Context, employee *user.Employee, node *task.TaskNode, workDate time.Time, minutes int64, checklistID, note string) (*task.TimeEntry, error) {
+	if locked, err := s.WeekLocked(ctx, employee.Id, workDate); err != nil {
+		return nil, err
+	} else if locked {
+		return nil, ErrTimeEntryLocked
+	}
+	now := time.Now()
+	entry := &task.TimeEntry{
+		Id:              utils.Common.GenId("te"),
+		CompanyId:       employee.CompanyId,
+		TaskId:          node.TaskId,
+		TaskNodeId:      node.TaskNodeId,
+		ChecklistId:     utils.Common.ToSqlNullString(checklistID),
+		EmployeeId:      employee.Id,
+		EntryType:       task.TimeEntryTypeManual,
+		WorkDate:        workDate,
+		DurationMinutes: minutes,
+		Note:            utils.Common.ToSqlNullString(note),
+		CreateTime:      now,
+		UpdateTime:      now,
+	}
+	if _, err := s.timeEntryModel.Insert(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.RollUp(ctx, entry.TaskId, entry.TaskNodeId)
+	return entry, nil
+}
+
+// SaveEntry 保存修改后的工时记录，原日期和新日期所在周都不能已提交
+func (s *TimeTrackingService) SaveEntry(ctx context.Context, entry *task.TimeEntry, originalDate time.Time) error {
+	for _, d := range []time.Time{originalDate, entry.WorkDate} {
+		if locked, err := s.WeekLocked(ctx, entry.EmployeeId, d); err != nil {
+			return err
+		} else if locked {
+			return ErrTimeEntryLocked
+		}
+	}
+	// 移出原工时单，重新提交时再归入
+	entry.TimesheetId = sql.NullString{}
+	if err := s.timeEntryModel.Update(ctx, entry); err != nil {
+		return err
+	}
+	s.RollUp(ctx, entry.TaskId, entry.TaskNodeId)
+	return nil
+}
+
+// DeleteEntry 删除工时记录
+func (s *TimeTrackingService) DeleteEntry(ctx context.Context, entry *task.TimeEntry) error {
+	if locked, err := s.WeekLocked(ctx, entry.EmployeeId, entry.WorkDate); err != nil {
+		return err
+	} else if locked {
+		return ErrTimeEntryLocked
+	}
+	if err := s.timeEntryModel.SoftDelete(ctx, entry.Id); err != nil {
+		return err
+	}
+	s.RollUp(ctx, entry.TaskId, entry.TaskNodeId)
+	return nil
+}
+
+// RollUp 将工时记录汇总到节点和任务的 actual_hours
+func (s *TimeTrackingService) RollUp(ctx context.Context, taskID, taskNodeID string) {
+	if taskNodeID != "" {
+		if minutes, err := s.timeEntryModel.SumMinutesByNode(ctx, taskNodeID); err != nil {
+			logx.WithContext(ctx).Errorf("[TimeTracking] 汇总节点工时失败: nodeId=%s, err=%v", taskNodeID, err)
+		} else if err := s.taskNodeModel.UpdateActualHours(ctx, taskNodeID, MinutesToHours(minutes)); err != nil {
+			logx.WithContext(ctx).Errorf("[TimeTracking] 更新节点实际工时失败: nodeId=%s, err=%v", taskNodeID, err)
+		}
+	}
+	if taskID != "" {
+		if minutes, err := s.timeEntryModel.SumMinutesByTask(ctx, taskID); err != nil {
+			logx.WithContext(ctx).Errorf("[TimeTracking] 汇总任务工时失败: taskId=%s, err=%v", taskID, err)
+		} else if err := s.taskModel.UpdateActualHours(ctx, taskID, MinutesToHours(minutes)); err != nil {
+			logx.WithContext(ctx).Errorf("[TimeTracking] 更新任务实际工时失败: taskId=%s, err=%v", taskID, err)
+		}
+	}
+}
+
+// SubmitTimesheet 提交员工某周的工时单，审批人由 ApproverFinder 推断（外出时转给代理人）
+// 找不到上级的员工（如公司创始人）直接通过
+func (s *TimeTrackingService) SubmitTimesheet(ctx context.Context, employee *user.Employee, weekStart time.Time) (*task.Timesheet, error) {
+	weekStart = WeekStart(weekStart)
+	weekEnd := weekStart.AddDate(0, 0, 6)
+
+	sheet, err := s.timesheetModel.FindByEmployeeWeek(ctx, employee.Id, weekStart)
+	if err != nil && !errors.Is(err, task.ErrNotFound) {
+		return nil, err
+	}
+	if sheet != nil && sheet.Locked() {
+		return sheet, ErrTimesheetSubmitted
+	}
+
+	entries, err := s.timeEntryModel.FindByEmployeeRange(ctx, employee.Id, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	// 计时未停止时不能提交，避免提交后计时无法计入
+	var total int64
+	for _, e := range entries {
+		if e.Running() {
+			return nil, ErrTimesheetRunning
+		}
+		total += e.DurationMinutes
+	}
+	if total == 0 {
+		return nil, ErrTimesheetEmpty
+	}
+
+	now := time.Now()
+	isNew := sheet == nil
+	if isNew {
+		sheet = &task.Timesheet{
+			Id:         utils.Common.GenId("ts"),
+			CompanyId:  employee.CompanyId,
+			EmployeeId: employee.Id,
+			WeekStart:  weekStart,
+			CreateTime: now,
+			UpdateTime: now,
+		}
+	}
+	sheet.TotalMinutes = total
+	sheet.Status = task.TimesheetStatusPending
+	sheet.ApproveNote = sql.NullString{}
+	sheet.ApproveTime = sql.NullTime{}
+	sheet.SubmitTime = sql.NullTime{Time: now, Valid: true}
+
+	finder := utils.NewApproverFinder(s.employeeModel, s.departmentModel, s.companyModel)
+	if s.outOfOfficeService != nil {
+		finder.WithDelegateResolver(s.outOfOfficeService.ResolveDelegate)
+	}
+	approver, findErr := finder.FindApprover(ctx, employee.Id)
+	if findErr != nil || approver == nil || approver.ApproverID == employee.Id {
+		sheet.Status = task.TimesheetStatusApproved
+		sheet.ApproverId = sql.NullString{}
+		sheet.ApproveNote = sql.NullString{String: "无上级审批人，自动通过", Valid: true}
+		sheet.ApproveTime = sql.NullTime{Time: now, Valid: true}
+	} else {
+		sheet.ApproverId = sql.NullString{String: approver.ApproverID, Valid: true}
+	}
+
+	if isNew {
+		_, err = s.timesheetModel.Insert(ctx, sheet)
+	} else {
+		err = s.timesheetModel.Update(ctx, sheet)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.timeEntryModel.BindTimesheet(ctx, employee.Id, weekStart, weekEnd, sheet.Id); err != nil {
+		logx.WithContext(ctx).Errorf("[TimeTracking] 工时记录归入工时单失败: timesheetId=%s, err=%v", sheet.Id, err)
+	}
+
+	if sheet.Status == task.TimesheetStatusPending {
+		s.notify(ctx, TimesheetSubmitted, approver.ApproverID, sheet.Id, "工时单待审批",
+			fmt.Sprintf("%s 提交了 %s 所在周的工时单（%.2f 小时），请审批", employee.RealName, weekStart.Format("2006-01-02"), MinutesToHours(total)))
+	}
+	return sheet, nil
+}
+
+// ReviewTimesheet 审批工时单，审批人须为指定审批人或员工审批链上的上级（含外出代理人）
+func (s *TimeTrackingService) ReviewTimesheet(ctx context.Context, sheet *task.Timesheet, approverID string, approved bool, note string) error {
+	if sheet.Status != task.TimesheetStatusPending {
+		return ErrTimesheetProcessed
+	}
+	if !sheet.ApproverId.Valid || sheet.ApproverId.String != approverID {
+		finder := utils.NewApproverFinder(s.employeeModel, s.departmentModel, s.companyModel)
+		if s.outOfOfficeService != nil {
+			finder.WithDelegateResolver(s.outOfOfficeService.ResolveDelegate)
+		}
+		if ok, _ := finder.CanApprove(ctx, approverID, sheet.EmployeeId); !ok {
+			return ErrTimesheetForbidden
+		}
+	}
+
+	now := time.Now()
+	sheet.Status = task.TimesheetStatusRejected
+	result := "已驳回"
+	if approved {
+		sheet.Status = task.TimesheetStatusApproved
+		result = "已通过"
+	}
+	sheet.ApproverId = sql.NullString{String: approverID, Valid: true}
+	sheet.ApproveNote = utils.Common.ToSqlNullString(note)
+	sheet.ApproveTime = sql.NullTime{Time: now, Valid: true}
+	if err := s.timesheetModel.Update(ctx, sheet); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("您 %s 所在周的工时单%s", sheet.WeekStart.Format("2006-01-02"), result)
+	if note != "" {
+		content += "，审批意见：" + note
+	}
+	s.notify(ctx, TimesheetReviewed, sheet.EmployeeId, sheet.Id, "工时单审批结果", content)
+	return nil
+}
+
+// Report 统计公司在日期范围内按员工和按部门的预计与实际工时
+// departmentID 不为空时只统计该部门节点上的工时
+// 员工维度：预计工时为其记录过工时的节点的预计工时之和；部门维度按节点所属部门汇总，每个节点只计一次预计工时
+func (s *TimeTrackingService) Report(ctx context.Context, companyID, departmentID string, start, end time.Time) (byEmployee, byDepartment []*TimeReportRow, err error) {
+	stats, err := s.timeEntryModel.StatByNode(ctx, companyID, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	employees := make(map[string]*TimeReportRow)
+	departments := make(map[string]*TimeReportRow)
+	deptNodes := make(map[string]bool)
+	for _, st := range stats {
+		if departmentID != "" && st.DepartmentId != departmentID {
+			continue
+		}
+		estimated := st.EstimatedDays * hoursPerEstimatedDay * 60
+
+		emp, ok := employees[st.EmployeeId]
+		if !ok {
+			emp = &TimeReportRow{Key: st.EmployeeId}
+			employees[st.EmployeeId] = emp
+			byEmployee = append(byEmployee, emp)
+		}
+		emp.EstimatedMinutes += estimated
+		emp.ActualMinutes += st.Minutes
+		emp.NodeCount++
+
+		dept, ok := departments[st.DepartmentId]
+		if !ok {
+			dept = &TimeReportRow{Key: st.DepartmentId}
+			departments[st.DepartmentId] = dept
+			byDepartment = append(byDepartment, dept)
+		}
+		dept.ActualMinutes += st.Minutes
+		if !deptNodes[st.TaskNodeId] {
+			deptNodes[st.TaskNodeId] = true
+			dept.EstimatedMinutes += estimated
+			dept.NodeCount++
+		}
+	}
+	return byEmployee, byDepartment, nil
+}
+
+func (s *TimeTrackingService) notify(ctx context.Context, eventType, employeeID, relatedID, title, content string) {
+	if s.notificationMQService == nil || employeeID == "" {
+		return
+	}
+	event := s.notificationMQService.NewNotificationEvent(eventType, []string{employeeID}, relatedID)
+	event.Title = title
+	event.Content = content
+	if err := s.notificationMQService.PublishNotificationEvent(ctx, event); err != nil {
+		logx.WithContext(ctx).Errorf("[TimeTracking] 发布通知失败: eventType=%s, employeeId=%s, err=%v", eventType, employeeID, err)
+	}
+}
+
+// MinutesToHours 分钟换算为小时，保留两位小数
+func MinutesToHours(minutes int64) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
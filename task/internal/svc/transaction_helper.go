@@ -77,6 +77,16 @@ func (h *TransactionHelper) GetTaskHandoverModelWithSession(session sqlx.Session
 	return task.NewTaskHandoverModel(sqlx.NewSqlConnFromSession(session))
 }
 
+// GetTaskBoardModelWithSession 获取带会话的看板模型
+func (h *TransactionHelper) GetTaskBoardModelWithSession(session sqlx.Session) task.TaskBoardModel {
+	return task.NewTaskBoardModel(sqlx.NewSqlConnFromSession(session))
+}
+
+// GetImportJobModelWithSession 获取带会话的导入任务模型
+func (h *TransactionHelper) GetImportJobModelWithSession(session sqlx.Session) task.ImportJobModel {
+	return task.NewImportJobModel(sqlx.NewSqlConnFromSession(session))
+}
+
 // GetNotificationModelWithSession 获取带会话的通知模型
 func (h *TransactionHelper) GetNotificationModelWithSession(session sqlx.Session) user_auth.NotificationModel {
 	return user_auth.NewNotificationModel(sqlx.NewSqlConnFromSession(session))
@@ -0,0 +1,417 @@
+package svc
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"task_Project/model/upload"
+	"task_Project/task/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// uploadSessionCleanupBatch 每次清理的过期会话数
+	uploadSessionCleanupBatch = 200
+	// uploadSessionRetention 已结束的会话记录保留时间，期间重复提交完成请求返回同一个附件
+	uploadSessionRetention = 7 * 24 * time.Hour
+	// uploadCompleteTimeout 合并、校验和扫描大文件的最长时间
+	uploadCompleteTimeout = time.Hour
+	// maxUploadParts 存储端允许的最大分片数（COS/S3 为 10000）
+	maxUploadParts = 10000
+)
+
+var (
+	// ErrUploadSessionNotFound 上传会话不存在、已过期或不属于当前员工
+	ErrUploadSessionNotFound = errors.New("上传会话不存在或已过期")
+	// ErrUploadSessionClosed 会话已完成、取消或失败，或者正在合并
+	ErrUploadSessionClosed = errors.New("上传会话已结束")
+	// ErrUploadModeUnsupported 当前文件存储不支持该上传方式
+	ErrUploadModeUnsupported = errors.New("当前文件存储不支持该上传方式")
+	// ErrUploadPartInvalid 分片序号超出范围或分片大小与会话不符
+	ErrUploadPartInvalid = errors.New("分片序号或大小不正确")
+	// ErrUploadPartChecksum 分片内容与客户端提供的 MD5 不符
+	ErrUploadPartChecksum = errors.New("分片校验失败，请重新上传该分片")
+	// ErrUploadIncomplete 还有分片未上传，或直传的文件尚未写入存储
+	ErrUploadIncomplete = errors.New("文件尚未上传完成")
+	// ErrUploadChecksumMismatch 合并后的文件大小或 SHA-256 与初始化时声明的不符
+	ErrUploadChecksumMismatch = errors.New("文件校验失败，请重新上传")
+)
+
+// UploadSessionService 大文件上传：分片上传（可断点续传，分片在存储端合并）和预签名URL直传。
+// 完成时把文件从存储读到临时文件，校验大小和 SHA-256，经 FileInspectionService 检查并占用存储配额后创建普通的附件记录；
+// 过期未完成的会话由定时任务清理，释放存储端的分片和直传的对象
+type UploadSessionService struct {
+	sessionModel        upload.UploadSessionModel
+	uploadFileModel     upload.Upload_fileModel
+	fileStorage         FileStorageInterface
+	inspection          *FileInspectionService
+	storageQuotaService *StorageQuotaService
+	systemConfigService *SystemConfigService
+}
+
+// NewUploadSessionService 创建大文件上传服务
+func NewUploadSessionService(sessionModel upload.UploadSessionModel, uploadFileModel upload.Upload_fileModel,
+	fileStorage FileStorageInterface, inspection *FileInspectionService, storageQuotaService *StorageQuotaService,
+	systemConfigService *SystemConfigService) *UploadSessionService {
+	return &UploadSessionService{
+		sessionModel:        sessionModel,
+		uploadFileModel:     uploadFileModel,
+		fileStorage:         fileStorage,
+		inspection:          inspection,
+		storageQuotaService: storageQuotaService,
+		systemConfigService: systemConfigService,
+	}
+}
+
+// SupportsMode 当前文件存储是否支持该上传方式
+func (s *UploadSessionService) SupportsMode(mode string) bool {
+	switch mode {
+	case upload.UploadModeChunked:
+		_, ok := s.fileStorage.(MultipartStorage)
+		return ok
+	case upload.UploadModeDirect:
+		_, ok := s.fileStorage.(PresignStorage)
+		return ok
+	}
+	return false
+}
+
+// Create 创建上传会话。调用方填写上传人、文件和附件关联信息，其余字段由服务分配；
+// 直传时返回预签名的上传地址。存储配额在创建时只做检查，完成时才占用
+func (s *UploadSessionService) Create(ctx context.Context, sess *upload.UploadSession) (string, error) {
+	if !s.SupportsMode(sess.Mode) {
+		return "", ErrUploadModeUnsupported
+	}
+	if err := s.storageQuotaService.Check(ctx, sess.CompanyId, sess.EmployeeId, sess.FileSize); err != nil {
+		return "", err
+	}
+	hours := s.systemConfigService.GetInt(SettingUploadSessionHours, 24)
+	sess.Id = utils.Common.GenId("upload")
+	sess.Status = upload.UploadSessionStatusUploading
+	sess.Checksum = strings.ToLower(sess.Checksum)
+	sess.ExpireTime = time.Now().Add(time.Duration(hours) * time.Hour)
+	sess.StorageKey = s.fileStorage.ObjectKey(sess.Module, sess.Category, sess.RelatedId, sess.Id, sess.FileName)
+
+	var uploadURL string
+	switch sess.Mode {
+	case upload.UploadModeChunked:
+		sess.PartSize, sess.PartCount = s.partLayout(sess.FileSize)
+		uploadID, err := s.fileStorage.(MultipartStorage).InitMultipart(ctx, sess.StorageKey)
+		if err != nil {
+			return "", err
+		}
+		sess.StorageUploadId = uploadID
+	case upload.UploadModeDirect:
+		url, err := s.fileStorage.(PresignStorage).PresignPut(ctx, sess.StorageKey, time.Duration(hours)*time.Hour)
+		if err != nil {
+			return "", err
+		}
+		uploadURL = url
+	}
+
+	if err := s.sessionModel.Insert(ctx, sess); err != nil {
+		s.release(ctx, sess)
+		return "", err
+	}
+	return uploadURL, nil
+}
+
+// partLayout 按配置的分片大小切分文件；超过存储允许的最大分片数时按 MB 增大分片
+func (s *UploadSessionService) partLayout(fileSize int64) (int64, int64) {
+	const mb = 1024 * 1024
+	partSize := int64(s.systemConfigService.GetInt(SettingUploadChunkSizeMB, 8)) * mb
+	if partSize <= 0 {
+		partSize = 8 * mb
+	}
+	if fileSize > partSize*maxUploadParts {
+		partSize = (fileSize/maxUploadParts/mb + 1) * mb
+	}
+	count := (fileSize + partSize - 1) / partSize
+	if count < 1 {
+		count = 1
+	}
+	return partSize, count
+}
+
+// Find 查询当前员工的上传会话
+func (s *UploadSessionService) Find(ctx context.Context, id, employeeID string) (*upload.UploadSession, error) {
+	sess, err := s.sessionModel.FindOne(ctx, id)
+	if err != nil {
+		if errors.Is(err, upload.ErrNotFound) {
+			return nil, ErrUploadSessionNotFound
+		}
+		return nil, err
+	}
+	if sess.EmployeeId != employeeID {
+		return nil, ErrUploadSessionNotFound
+	}
+	return sess, nil
+}
+
+// Parts 已上传的分片
+func (s *UploadSessionService) Parts(ctx context.Context, sess *upload.UploadSession) ([]*upload.UploadSessionPart, error) {
+	if sess.Mode != upload.UploadModeChunked {
+		return nil, nil
+	}
+	return s.sessionModel.FindParts(ctx, sess.Id)
+}
+
+// UploadPart 上传一个分片，重传同一分片时覆盖。md5Hex 为客户端计算的分片 MD5，为空时只由存储端校验传输
+func (s *UploadSessionService) UploadPart(ctx context.Context, sess *upload.UploadSession, partNumber int64, data []byte, md5Hex string) error {
+	if sess.Mode != upload.UploadModeChunked {
+		return ErrUploadModeUnsupported
+	}
+	if sess.Status != upload.UploadSessionStatusUploading {
+		return ErrUploadSessionClosed
+	}
+	if time.Now().After(sess.ExpireTime) {
+		return ErrUploadSessionNotFound
+	}
+	if partNumber < 1 || partNumber > sess.PartCount || int64(len(data)) != s.expectedPartSize(sess, partNumber) {
+		return ErrUploadPartInvalid
+	}
+
+	sum := md5.Sum(data)
+	actual := hex.EncodeToString(sum[:])
+	if md5Hex != "" && !strings.EqualFold(md5Hex, actual) {
+		return ErrUploadPartChecksum
+	}
+	etag, err := s.fileStorage.(MultipartStorage).UploadPart(ctx, sess.StorageKey, sess.StorageUploadId, int(partNumber), data, sum[:])
+	if err != nil {
+		return err
+	}
+	return s.sessionModel.UpsertPart(ctx, &upload.UploadSessionPart{
+		SessionId:  sess.Id,
+		PartNumber: partNumber,
+		Size:       int64(len(data)),
+		Md5:        actual,
+		Etag:       etag,
+	})
+}
+
+// expectedPartSize 分片的应有大小，最后一个分片为剩余部分
+func (s *UploadSessionService) expectedPartSize(sess *upload.UploadSession, partNumber int64) int64 {
+	if partNumber < sess.PartCount {
+		return sess.PartSize
+	}
+	return sess.FileSize - sess.PartSize*(sess.PartCount-1)
+}
+
+// UploadCompletion 完成上传时写入安全日志的请求信息
+type UploadCompletion struct {
+	UserID string
+	IP     string
+	Path   string
+}
+
+// Complete 合并分片（直传时确认对象已写入），校验并检查文件后创建附件记录。
+// 已完成的会话直接返回创建的附件；分片未传完时会话保持上传中，可以继续上传；
+// 校验或检查未通过时删除文件并把会话标记为失败
+func (s *UploadSessionService) Complete(ctx context.Context, sess *upload.UploadSession, req UploadCompletion) (*upload.Upload_file, error) {
+	if sess.Status == upload.UploadSessionStatusCompleted && sess.FileId != "" {
+		return s.uploadFileModel.FindByFileID(ctx, sess.FileId)
+	}
+	if sess.Status != upload.UploadSessionStatusUploading {
+		return nil, ErrUploadSessionClosed
+	}
+	if time.Now().After(sess.ExpireTime) {
+		return nil, ErrUploadSessionNotFound
+	}
+	ok, err := s.sessionModel.BeginComplete(ctx, sess.Id, time.Now().Add(uploadCompleteTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUploadSessionClosed
+	}
+
+	// 合并前的错误（分片未传完、存储暂时不可用）不影响已上传的内容，会话回到上传中
+	if err := s.assemble(ctx, sess); err != nil {
+		if _, revertErr := s.sessionModel.TransitStatus(ctx, sess.Id, upload.UploadSessionStatusCompleting, upload.UploadSessionStatusUploading); revertErr != nil {
+			logx.WithContext(ctx).Errorf("[UploadSession] 恢复会话状态失败: id=%s, err=%v", sess.Id, revertErr)
+		}
+		return nil, err
+	}
+
+	file, err := s.createFile(ctx, sess, req)
+	if err != nil {
+		s.fileStorage.DeleteFile(sess.StorageKey)
+		if failErr := s.sessionModel.Fail(ctx, sess.Id, err.Error()); failErr != nil {
+			logx.WithContext(ctx).Errorf("[UploadSession] 标记会话失败出错: id=%s, err=%v", sess.Id, failErr)
+		}
+		s.sessionModel.DeleteParts(ctx, sess.Id)
+		return nil, err
+	}
+
+	if err := s.sessionModel.Complete(ctx, sess.Id, file.FileID); err != nil {
+		logx.WithContext(ctx).Errorf("[UploadSession] 标记会话完成失败: id=%s, fileId=%s, err=%v", sess.Id, file.FileID, err)
+	}
+	s.sessionModel.DeleteParts(ctx, sess.Id)
+	return file, nil
+}
+
+// assemble 分片上传时在存储端合并全部分片；直传时确认对象已写入
+func (s *UploadSessionService) assemble(ctx context.Context, sess *upload.UploadSession) error {
+	switch sess.Mode {
+	case upload.UploadModeChunked:
+		parts, err := s.sessionModel.FindParts(ctx, sess.Id)
+		if err != nil {
+			return err
+		}
+		if int64(len(parts)) != sess.PartCount {
+			return ErrUploadIncomplete
+		}
+		storageParts := make([]StoragePart, 0, len(parts))
+		for _, p := range parts {
+			storageParts = append(storageParts, StoragePart{PartNumber: int(p.PartNumber), ETag: p.Etag})
+		}
+		return s.fileStorage.(MultipartStorage).CompleteMultipart(ctx, sess.StorageKey, sess.StorageUploadId, storageParts)
+	case upload.UploadModeDirect:
+		if _, err := s.fileStorage.(PresignStorage).StatFile(ctx, sess.StorageKey); err != nil {
+			if errors.Is(err, ErrStorageFileNotFound) {
+				return ErrUploadIncomplete
+			}
+			return err
+		}
+		return nil
+	}
+	return ErrUploadModeUnsupported
+}
+
+// createFile 把合并后的文件读到临时文件，校验大小和 SHA-256，检查内容并生成预览图后写入附件记录
+func (s *UploadSessionService) createFile(ctx context.Context, sess *upload.UploadSession, req UploadCompletion) (*upload.Upload_file, error) {
+	tmp, err := os.CreateTemp("", "upload-session-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	rc, err := s.fileStorage.OpenFile(sess.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), rc)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取上传的文件失败: %v", err)
+	}
+	if size != sess.FileSize || (sess.Checksum != "" && hex.EncodeToString(hash.Sum(nil)) != sess.Checksum) {
+		return nil, ErrUploadChecksumMismatch
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	inspected, err := s.inspection.Inspect(ctx, &UploadFile{
+		CompanyID: sess.CompanyId,
+		UserID:    req.UserID,
+		IP:        req.IP,
+		Path:      req.Path,
+		FileName:  sess.FileName,
+		Size:      size,
+		File:      tmp,
+	}, s.inspection.LargePolicy(ctx, sess.CompanyId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storageQuotaService.Reserve(ctx, sess.CompanyId, sess.EmployeeId, size); err != nil {
+		return nil, err
+	}
+
+	fileID := utils.Common.GenId("file")
+	rendition := s.inspection.SaveRenditions(ctx, tmp, inspected.Kind, sess.Module, sess.RelatedId, fileID)
+	now := time.Now()
+	file := &upload.Upload_file{
+		FileID:      fileID,
+		FileName:    sess.FileName,
+		FilePath:    sess.StorageKey,
+		FileURL:     s.fileStorage.GetFileURL(sess.StorageKey),
+		FileType:    inspected.Kind,
+		FileSize:    size,
+		MimeType:    inspected.MIME,
+		Module:      sess.Module,
+		Category:    sess.Category,
+		RelatedID:   sess.RelatedId,
+		TaskNodeID:  sess.TaskNodeId,
+		UploaderID:  sess.EmployeeId,
+		Description: sess.Description,
+		Tags:        sess.Tags,
+		CreateAt:    now,
+		UpdateAt:    now,
+
+		Upload_fileRendition: rendition,
+	}
+	if err := s.uploadFileModel.Insert(ctx, file); err != nil {
+		s.inspection.DeleteRenditions(rendition)
+		s.storageQuotaService.Release(ctx, sess.CompanyId, sess.EmployeeId, size)
+		return nil, fmt.Errorf("保存文件信息失败: %v", err)
+	}
+	return file, nil
+}
+
+// Abort 取消上传中的会话，释放存储端已上传的内容
+func (s *UploadSessionService) Abort(ctx context.Context, sess *upload.UploadSession) error {
+	ok, err := s.sessionModel.TransitStatus(ctx, sess.Id, upload.UploadSessionStatusUploading, upload.UploadSessionStatusAborted)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUploadSessionClosed
+	}
+	s.release(ctx, sess)
+	s.sessionModel.DeleteParts(ctx, sess.Id)
+	return nil
+}
+
+// Cleanup 取消过期未完成的会话并释放存储，删除结束超过保留时间的会话记录。返回取消的会话数
+func (s *UploadSessionService) Cleanup(ctx context.Context) (int, error) {
+	expired, err := s.sessionModel.FindExpired(ctx, time.Now(), uploadSessionCleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+	aborted := 0
+	for _, sess := range expired {
+		ok, err := s.sessionModel.TransitStatus(ctx, sess.Id, sess.Status, upload.UploadSessionStatusAborted)
+		if err != nil {
+			logx.Errorf("[UploadSession] 取消过期会话失败: id=%s, err=%v", sess.Id, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		s.release(ctx, sess)
+		s.sessionModel.DeleteParts(ctx, sess.Id)
+		aborted++
+	}
+
+	if _, err := s.sessionModel.DeleteFinishedBefore(ctx, time.Now().Add(-uploadSessionRetention)); err != nil {
+		logx.Errorf("[UploadSession] 删除已结束的会话记录失败: err=%v", err)
+	}
+	return aborted, nil
+}
+
+// release 释放会话在存储端占用的空间：取消分片上传，并删除已合并或直传写入的对象（对象不存在时删除也成功）
+func (s *UploadSessionService) release(ctx context.Context, sess *upload.UploadSession) {
+	if sess.Mode == upload.UploadModeChunked && sess.StorageUploadId != "" {
+		if err := s.fileStorage.(MultipartStorage).AbortMultipart(ctx, sess.StorageKey, sess.StorageUploadId); err != nil {
+			logx.Errorf("[UploadSession] 取消分片上传失败: id=%s, key=%s, err=%v", sess.Id, sess.StorageKey, err)
+		}
+	}
+	if err := s.fileStorage.DeleteFile(sess.StorageKey); err != nil {
+		logx.Errorf("[UploadSession] 删除上传会话的文件失败: id=%s, key=%s, err=%v", sess.Id, sess.StorageKey, err)
+	}
+}
@@ -18,6 +18,10 @@ type AnnotationDataReq struct {
 	Text   string  `json:"text,optional"`
 }
 
+type ApplyImportRequest struct {
+	JobID string `json:"jobId"`
+}
+
 type ApplyJoinCompanyRequest struct {
 	InviteCode  string `json:"inviteCode"`
 	ApplyReason string `json:"applyReason,optional"`
@@ -44,12 +48,28 @@ type ApproveTaskNodeCompletionRequest struct {
 	Comment    string `json:"comment,optional"`
 }
 
+type AssignOffboardingPlanRequest struct {
+	ApprovalID        string                  `json:"approvalId"`
+	Assignments       []OffboardingAssignment `json:"assignments,optional"`
+	AcceptSuggestions bool                    `json:"acceptSuggestions,optional"` // 未分配的事项采用建议的接收人
+}
+
 type AssignRoleRequest struct {
 	PositionId string `json:"positionId"` // 职位ID（改为给职位分配角色）
 	RoleId     string `json:"roleId"`
 	ExpireTime string `json:"expireTime,optional"`
 }
 
+type AtRiskTasksRequest struct {
+	Limit int    `json:"limit,optional"` // 最多返回的任务数，默认 50
+	Unit  string `json:"unit,optional"`  // 统计口径 node/checklist，默认 node
+}
+
+type AtRiskTasksResponse struct {
+	List  []TaskForecastInfo `json:"list"`
+	Total int                `json:"total"`
+}
+
 type AttachmentCommentInfo struct {
 	CommentID       string                  `json:"commentId"`
 	ID              string                  `json:"id"`
@@ -60,6 +80,7 @@ type AttachmentCommentInfo struct {
 	EmployeeID      string                  `json:"employeeId"`
 	EmployeeName    string                  `json:"employeeName"`
 	Content         string                  `json:"content"`
+	ContentHTML     string                  `json:"contentHtml"` // 服务端渲染的HTML
 	CreatorID       string                  `json:"creatorId"`
 	CreatorName     string                  `json:"creatorName"`
 	Resolved        int                     `json:"resolved"`
@@ -75,30 +96,69 @@ type AttachmentCommentInfo struct {
 	AtEmployeeIDs   []string                `json:"atEmployeeIds,optional"`
 	AtEmployeeNames []string                `json:"atEmployeeNames,optional"`
 	Replies         []AttachmentCommentInfo `json:"replies,optional"` // 回复列表
+	FileVersion     int64                   `json:"fileVersion"`      // 标注所在的附件版本
+	Outdated        bool                    `json:"outdated"`         // 标注所在的版本不是当前版本
 	CreateTime      string                  `json:"createTime"`
 	UpdateTime      string                  `json:"updateTime"`
 }
 
 type AttachmentInfo struct {
-	FileID      string `json:"fileId"`
-	FileURL     string `json:"fileUrl"`
-	FileName    string `json:"fileName"`
-	FileSize    int64  `json:"fileSize"`
-	FileType    string `json:"fileType"`
-	Module      string `json:"module"`
-	Category    string `json:"category"`
-	RelatedID   string `json:"relatedId,optional"`
-	TaskNodeID  string `json:"taskNodeId,optional"`
-	UploaderID  string `json:"uploaderId,optional"`
-	Description string `json:"description,optional"`
-	Tags        string `json:"tags,optional"`
-	CreateTime  string `json:"createTime"`
-	UpdateTime  string `json:"updateTime"`
+	FileID       string `json:"fileId"`
+	FileURL      string `json:"fileUrl"`
+	FileName     string `json:"fileName"`
+	FileSize     int64  `json:"fileSize"`
+	FileType     string `json:"fileType"`
+	Module       string `json:"module"`
+	Category     string `json:"category"`
+	RelatedID    string `json:"relatedId,optional"`
+	TaskNodeID   string `json:"taskNodeId,optional"`
+	UploaderID   string `json:"uploaderId,optional"`
+	Description  string `json:"description,optional"`
+	Tags         string `json:"tags,optional"`
+	Version      int64  `json:"version"`               // 当前版本号
+	ThumbnailURL string `json:"thumbnailUrl,optional"` // 缩略图，图片和PDF才有
+	PreviewURL   string `json:"previewUrl,optional"`   // 预览图，图片和PDF才有
+	CreateTime   string `json:"createTime"`
+	UpdateTime   string `json:"updateTime"`
+}
+
+type AuditLogInfo struct {
+	Id            string `json:"id"`
+	UserId        string `json:"userId"`
+	EmployeeId    string `json:"employeeId"`
+	EmployeeName  string `json:"employeeName"`
+	OperationType string `json:"operationType"`
+	OperationName string `json:"operationName"`
+	EntityType    string `json:"entityType"`
+	EntityId      string `json:"entityId"`
+	Detail        string `json:"detail"` // 变更详情 JSON（before/after/changes）
+	RequestMethod string `json:"requestMethod"`
+	RequestUrl    string `json:"requestUrl"`
+	RequestParams string `json:"requestParams"`
+	IpAddress     string `json:"ipAddress"`
+	UserAgent     string `json:"userAgent"`
+	ExecutionTime int64  `json:"executionTime"` // 执行时间(毫秒)
+	Status        int    `json:"status"`        // 0-失败 1-成功
+	ErrorMessage  string `json:"errorMessage"`
+	CreateTime    string `json:"createTime"`
+}
+
+type AuditLogSearchRequest struct {
+	PageReq
+	UserId        string `json:"userId,optional"`            // 操作人用户ID
+	EmployeeId    string `json:"employeeId,optional"`        // 操作人员工ID
+	OperationType string `json:"operationType,optional"`     // 操作类型：api-接口调用 permission-授权变更
+	EntityType    string `json:"entityType,optional"`        // 实体类型，如 task、tasknode、employee
+	EntityId      string `json:"entityId,optional"`          // 实体ID
+	Status        int    `json:"status,optional,default=-1"` // -1-全部 0-失败 1-成功
+	StartTime     string `json:"startTime,optional"`         // 开始时间，格式 2006-01-02 15:04:05
+	EndTime       string `json:"endTime,optional"`           // 结束时间，格式 2006-01-02 15:04:05
 }
 
 type AutoDispatchRequest struct {
-	TaskID string `json:"taskId"`
-	NodeID string `json:"nodeId,optional"` // 可选，指定节点ID时只推荐该节点
+	TaskID          string `json:"taskId"`
+	NodeID          string `json:"nodeId,optional"`          // 可选，指定节点ID时只推荐该节点
+	RespectCapacity bool   `json:"respectCapacity,optional"` // 为 true 时排除承接后超负荷或请假的员工
 }
 
 type BaseResponse struct {
@@ -112,6 +172,60 @@ type BatchCompleteChecklistRequest struct {
 	IsCompleted  int64    `json:"isCompleted"`  // 是否已完成：0-未完成，1-已完成
 }
 
+type BoardCardInfo struct {
+	NodeID       string `json:"nodeId"`
+	TaskID       string `json:"taskId"`
+	NodeName     string `json:"nodeName"`
+	NodeStatus   int64  `json:"nodeStatus"`
+	NodePriority int64  `json:"nodePriority"`
+	Progress     int64  `json:"progress"`
+	ExecutorID   string `json:"executorId"`
+	LeaderID     string `json:"leaderId"`
+	NodeDeadline string `json:"nodeDeadline"`
+	Rank         string `json:"rank"`
+}
+
+type BoardColumnInfo struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	NodeStatus int64           `json:"nodeStatus"`
+	WipLimit   int64           `json:"wipLimit"`
+	CardCount  int             `json:"cardCount"`
+	OverLimit  bool            `json:"overLimit"` // 节点状态在看板外变化后可能超过上限
+	Cards      []BoardCardInfo `json:"cards"`
+}
+
+type BoardColumnInput struct {
+	ID         string `json:"id,optional"` // 已有列的ID，新增列不填
+	Name       string `json:"name"`
+	NodeStatus int    `json:"nodeStatus"`        // 对应的节点状态 0-未开始 1-进行中 2-已完成 3-已逾期
+	WipLimit   int64  `json:"wipLimit,optional"` // 在制品上限，0 表示不限制
+}
+
+type BoardInfo struct {
+	ID         string            `json:"id"`
+	ScopeType  string            `json:"scopeType"`
+	ScopeID    string            `json:"scopeId"`
+	Name       string            `json:"name"`
+	Columns    []BoardColumnInfo `json:"columns"`
+	CreateTime string            `json:"createTime"`
+}
+
+type CancelOutOfOfficeRequest struct {
+	ID string `json:"id"`
+}
+
+type CapacityWarning struct {
+	EmployeeID     string  `json:"employeeId"`
+	EmployeeName   string  `json:"employeeName"`
+	AvailableHours float64 `json:"availableHours"` // 节点周期内的可用工时
+	AssignedHours  float64 `json:"assignedHours"`  // 节点周期内已分配的工作量
+	NodeHours      float64 `json:"nodeHours"`      // 本节点分摊的工作量
+	Utilization    float64 `json:"utilization"`    // 承接后的利用率（百分比）
+	OnLeave        bool    `json:"onLeave"`        // 是否请假
+	Message        string  `json:"message"`
+}
+
 type ChecklistInfo struct {
 	ID           string `json:"id"`
 	TaskNodeID   string `json:"taskNodeId"`
@@ -152,6 +266,21 @@ type CompanyListRequest struct {
 	Name string `json:"name,optional"`
 }
 
+type CompanyMembershipInfo struct {
+	CompanyID      string `json:"companyId"`
+	CompanyName    string `json:"companyName"`
+	CompanyStatus  int    `json:"companyStatus"`
+	EmployeeID     string `json:"employeeId"`
+	EmployeeNo     string `json:"employeeNo"`
+	DepartmentID   string `json:"departmentId"`
+	DepartmentName string `json:"departmentName"`
+	PositionID     string `json:"positionId"`
+	PositionName   string `json:"positionName"`
+	Status         int    `json:"status"`  // 员工状态 0-离职 1-在职 2-请假
+	Current        bool   `json:"current"` // 是否为当前令牌所属公司
+	JoinTime       string `json:"joinTime"`
+}
+
 type CompleteTaskRequest struct {
 	TaskID         string `json:"taskId"`
 	ActualHours    int    `json:"actualHours,optional"`
@@ -178,6 +307,14 @@ type CreateAttachmentCommentRequest struct {
 	AnnotationType string            `json:"annotationType,optional"` // 标注类型
 	PageNumber     int               `json:"pageNumber,optional"`     // 页码
 	AtEmployeeIDs  []string          `json:"atEmployeeIds,optional"`  // @的员工ID
+	FileVersion    int64             `json:"fileVersion,optional"`    // 标注所在的附件版本，不填时为当前版本（回复跟随被回复的评论）
+}
+
+type CreateBoardRequest struct {
+	ScopeType string             `json:"scopeType"` // task-任务看板 department-部门看板
+	ScopeID   string             `json:"scopeId"`
+	Name      string             `json:"name,optional"`
+	Columns   []BoardColumnInput `json:"columns,optional"` // 不填时按四个节点状态各建一列
 }
 
 type CreateChecklistRequest struct {
@@ -197,6 +334,15 @@ type CreateCompanyRequest struct {
 	UseTemplate       bool   `json:"useTemplate,optional"` // 是否使用组织结构模板
 }
 
+type CreateCustomFieldRequest struct {
+	EntityType string                  `json:"entityType"`
+	Name       string                  `json:"name"`
+	FieldType  string                  `json:"fieldType"`
+	Options    []CustomFieldOptionInfo `json:"options,optional"` // 单选/多选必填
+	Required   bool                    `json:"required,optional"`
+	Sort       int64                   `json:"sort,optional"`
+}
+
 type CreateDepartmentRequest struct {
 	CompanyID      string `json:"companyId"`
 	DepartmentName string `json:"departmentName"`
@@ -220,13 +366,27 @@ type CreateEmployeeRequest struct {
 	HireDate     string `json:"hireDate,optional"`
 }
 
+type CreateExportRequest struct {
+	ExportType   string `json:"exportType"`                   // task_list/node_list/employee_list/handover_list/dashboard_stats/task_report
+	Format       string `json:"format"`                       // csv/xlsx/pdf
+	TaskID       string `json:"taskId,optional"`              // node_list、task_report 必填；handover_list 可选
+	Scope        string `json:"scope,optional"`               // task_list: involved（默认）/company；dashboard_stats: personal（默认）/department
+	DepartmentID string `json:"departmentId,optional"`        // task_list、node_list、employee_list 按部门筛选
+	PositionID   string `json:"positionId,optional"`          // employee_list 按职位筛选
+	Keyword      string `json:"keyword,optional"`             // task_list 标题关键字 / employee_list 姓名关键字
+	Status       int    `json:"status,optional,default=-1"`   // -1-全部，其余按对应列表的状态值筛选
+	Priority     int    `json:"priority,optional,default=-1"` // task_list 优先级，-1-全部
+}
+
 type CreateHandoverRequest struct {
-	TaskID         string `json:"taskId"`
-	FromEmployeeID string `json:"fromEmployeeId"`
-	ToEmployeeID   string `json:"toEmployeeId"`
-	HandoverReason string `json:"handoverReason"`
-	HandoverNote   string `json:"handoverNote,optional"`
-	ApproverID     string `json:"approverId,optional"`
+	TaskID         string              `json:"taskId"`
+	FromEmployeeID string              `json:"fromEmployeeId"`
+	ToEmployeeID   string              `json:"toEmployeeId"`
+	HandoverReason string              `json:"handoverReason"`
+	HandoverNote   string              `json:"handoverNote,optional"`
+	ApproverID     string              `json:"approverId,optional"`
+	Items          []HandoverItemInput `json:"items,optional"`          // 交接事项，不填时交接整个任务
+	TransitionNote string              `json:"transitionNote,optional"` // 交接说明，展示给接收人
 }
 
 type CreateNotificationRequest struct {
@@ -284,6 +444,12 @@ type CreateTaskDetailComment struct {
 	AtEmployeeId     []string `json:"atEmployeeId,optional"` // @的员工userid，用于消息通知
 }
 
+type CreateTaskLabelRequest struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,optional"` // #RRGGBB，默认 #1677ff
+	Description string `json:"description,optional"`
+}
+
 type CreateTaskNodeRequest struct {
 	TaskID        string   `json:"taskId"`                  // 总任务id
 	NodeName      string   `json:"nodeName"`                // 节点名字
@@ -310,6 +476,70 @@ type CreateTaskRequest struct {
 	AttachmentURL          []string `json:"attachmentUrl,optional"`
 }
 
+type CreateTaskViewRequest struct {
+	Name   string           `json:"name"`
+	Shared bool             `json:"shared,optional"` // 是否共享给公司其他员工
+	Filter TaskSearchFilter `json:"filter,optional"`
+	Sort   []TaskSortField  `json:"sort,optional"`
+}
+
+type CreateTimeEntryRequest struct {
+	TaskNodeId      string `json:"taskNodeId"`
+	WorkDate        string `json:"workDate"`        // 工作日期，格式 2006-01-02
+	DurationMinutes int    `json:"durationMinutes"` // 时长（分钟）
+	ChecklistId     string `json:"checklistId,optional"`
+	Note            string `json:"note,optional"`
+}
+
+type CustomFieldFilter struct {
+	FieldID string   `json:"fieldId"`
+	Values  []string `json:"values,optional"`
+	Min     string   `json:"min,optional"`
+	Max     string   `json:"max,optional"`
+}
+
+type CustomFieldInfo struct {
+	ID            string                  `json:"id"`
+	EntityType    string                  `json:"entityType"` // task/node
+	Name          string                  `json:"name"`
+	FieldType     string                  `json:"fieldType"` // text/number/date/select/multi_select/employee
+	FieldTypeName string                  `json:"fieldTypeName"`
+	Options       []CustomFieldOptionInfo `json:"options"`
+	Required      bool                    `json:"required"`
+	Sort          int64                   `json:"sort"`
+	CreateTime    string                  `json:"createTime"`
+}
+
+type CustomFieldListRequest struct {
+	EntityType string `json:"entityType,optional"` // 为空时返回全部
+}
+
+type CustomFieldOptionInfo struct {
+	ID    string `json:"id,optional"` // 新增选项不填，由系统生成
+	Label string `json:"label"`
+	Color string `json:"color,optional"`
+}
+
+type CustomFieldValueInfo struct {
+	FieldID   string   `json:"fieldId"`
+	Name      string   `json:"name"`
+	FieldType string   `json:"fieldType"`
+	Values    []string `json:"values"`  // 存储的值，单选/多选为选项ID，员工为员工ID
+	Display   string   `json:"display"` // 显示文本
+}
+
+type CustomFieldValueInput struct {
+	FieldID string   `json:"fieldId"`
+	Values  []string `json:"values,optional"` // 多选可填多个选项ID，其他字段填一个值，为空时清除
+}
+
+type DelegatePermissionRequest struct {
+	FromEmployeeId string `json:"fromEmployeeId"` // 被代理员工ID（如请假的部门经理）
+	ToEmployeeId   string `json:"toEmployeeId"`   // 代理人员工ID
+	ExpireTime     string `json:"expireTime"`     // 委派截止时间，格式 2006-01-02 15:04:05
+	Reason         string `json:"reason,optional"`
+}
+
 type DeleteAttachmentCommentRequest struct {
 	CommentID string `json:"commentId"`
 }
@@ -318,6 +548,10 @@ type DeleteAttachmentRequest struct {
 	FileID string `json:"fileId"`
 }
 
+type DeleteBoardRequest struct {
+	BoardID string `json:"boardId"`
+}
+
 type DeleteChecklistRequest struct {
 	ChecklistID string `json:"checklistId"` // 清单ID
 }
@@ -326,6 +560,10 @@ type DeleteCompanyRequest struct {
 	CompanyID string `json:"companyId"`
 }
 
+type DeleteCustomFieldRequest struct {
+	FieldID string `json:"fieldId"`
+}
+
 type DeleteDepartmentRequest struct {
 	ID string `json:"id"`
 }
@@ -334,6 +572,14 @@ type DeleteEmployeeRequest struct {
 	EmployeeID string `json:"employeeId"`
 }
 
+type DeleteExportRequest struct {
+	JobID string `json:"jobId"`
+}
+
+type DeleteImportRequest struct {
+	JobID string `json:"jobId"`
+}
+
 type DeletePositionRequest struct {
 	PositionID string `json:"positionId"`
 }
@@ -346,6 +592,10 @@ type DeleteTaskCommentRequest struct {
 	CommentID string `json:"commentId"`
 }
 
+type DeleteTaskLabelRequest struct {
+	LabelID string `json:"labelId"`
+}
+
 type DeleteTaskNodeRequest struct {
 	TaskNodeID string `json:"taskNodeId"`
 }
@@ -355,6 +605,14 @@ type DeleteTaskRequest struct {
 	DeleteReason string `json:"deleteReason,optional"`
 }
 
+type DeleteTaskViewRequest struct {
+	ViewID string `json:"viewId"`
+}
+
+type DeleteTimeEntryRequest struct {
+	EntryId string `json:"entryId"`
+}
+
 type DepartmentInfo struct {
 	ID             string `json:"id"`
 	CompanyID      string `json:"companyId"`
@@ -417,6 +675,87 @@ type EmployeeRolesRequest struct {
 	EmployeeId string `json:"employeeId"` // 查询员工通过职位获得的角色
 }
 
+type EmployeeWorkload struct {
+	EmployeeID   string         `json:"employeeId"`
+	EmployeeName string         `json:"employeeName"`
+	PositionName string         `json:"positionName"`
+	WeeklyHours  float64        `json:"weeklyHours"` // 每周可用工时配置
+	OnLeave      bool           `json:"onLeave"`     // 是否请假（员工状态 2）
+	OpenNodes    int            `json:"openNodes"`   // 参与的未完成节点数
+	Weeks        []WorkloadWeek `json:"weeks"`
+}
+
+type EmployeeWorkloadRequest struct {
+	EmployeeID string `json:"employeeId,optional"` // 为空时查看自己
+	Weeks      int    `json:"weeks,optional"`      // 统计周数，默认 4，最多 12
+}
+
+type ExportJobInfo struct {
+	ID             string `json:"id"`
+	ExportType     string `json:"exportType"`
+	ExportTypeName string `json:"exportTypeName"`
+	Format         string `json:"format"`
+	Status         int64  `json:"status"` // 0-排队中 1-生成中 2-已完成 3-失败
+	FileName       string `json:"fileName"`
+	FileUrl        string `json:"fileUrl"` // 已完成时的下载地址
+	FileSize       int64  `json:"fileSize"`
+	RowCount       int64  `json:"rowCount"`
+	ErrorMessage   string `json:"errorMessage"`
+	CreateTime     string `json:"createTime"`
+	FinishTime     string `json:"finishTime"`
+}
+
+type ExportListRequest struct {
+	PageReq
+}
+
+type FileVersionInfo struct {
+	Version      int64  `json:"version"`
+	FileName     string `json:"fileName"`
+	FileURL      string `json:"fileUrl"`
+	FileType     string `json:"fileType"`
+	FileSize     int64  `json:"fileSize"`
+	UploaderID   string `json:"uploaderId"`
+	UploaderName string `json:"uploaderName"`
+	ChangeNote   string `json:"changeNote,optional"`
+	RestoredFrom int64  `json:"restoredFrom,optional"` // 从哪个历史版本恢复
+	Current      bool   `json:"current"`
+	CommentCount int64  `json:"commentCount"` // 该版本上的评论和标注数
+	ThumbnailURL string `json:"thumbnailUrl,optional"`
+	PreviewURL   string `json:"previewUrl,optional"`
+	CreateTime   string `json:"createTime"`
+}
+
+type FileVersionListRequest struct {
+	FileID string `json:"fileId"`
+}
+
+type FullTextSearchHit struct {
+	Type           string   `json:"type"`
+	ID             string   `json:"id"` // 命中实体的ID
+	TaskID         string   `json:"taskId"`
+	TaskNodeID     string   `json:"taskNodeId"`
+	FileID         string   `json:"fileId"`         // 附件和附件评论所属的附件
+	Title          string   `json:"title"`          // 任务/节点标题、附件名、评论人
+	TitleHighlight string   `json:"titleHighlight"` // 标题中的关键字用 <mark> 标记，为空时使用 title
+	Snippets       []string `json:"snippets"`       // 正文命中片段，已转义 HTML，关键字用 <mark> 标记
+	Score          float64  `json:"score"`
+	CreateTime     string   `json:"createTime"`
+}
+
+type FullTextSearchRequest struct {
+	Keyword string   `json:"keyword"`
+	Types   []string `json:"types,optional"` // task/node/checklist/comment/attachment_comment/file，为空时搜索全部
+	PageReq
+}
+
+type FullTextSearchResponse struct {
+	List     []FullTextSearchHit `json:"list"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+}
+
 type GenerateInviteCodeRequest struct {
 	ExpireDays int `json:"expireDays,optional"` // 有效期（天）
 	MaxUses    int `json:"maxUses,optional"`    // 最大使用次数，0表示不限制
@@ -426,10 +765,17 @@ type GetAiSuggestionRequest struct {
 }
 
 type GetAttachmentCommentsRequest struct {
-	FileID string `json:"fileId"`
+	FileID      string `json:"fileId"`
+	FileVersion int64  `json:"fileVersion,optional"` // 只返回该版本上的评论，不填时返回全部版本
 	PageReq
 }
 
+type GetBoardRequest struct {
+	BoardID   string `json:"boardId,optional"`
+	ScopeType string `json:"scopeType,optional"` // 不填 boardId 时按范围查询
+	ScopeID   string `json:"scopeId,optional"`
+}
+
 type GetChecklistListRequest struct {
 	PageReq
 	TaskNodeID string `json:"taskNodeId"` // 任务节点ID
@@ -443,6 +789,11 @@ type GetCompanyRequest struct {
 	CompanyID string `json:"companyId"`
 }
 
+type GetCustomFieldValuesRequest struct {
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+}
+
 type GetDashboardStatsRequest struct {
 	Scope string `form:"scope,optional"` // 范围：personal（个人）或 department（部门），默认personal
 }
@@ -455,14 +806,27 @@ type GetEmployeeRequest struct {
 	EmployeeID string `json:"employeeId"`
 }
 
+type GetExportRequest struct {
+	JobID string `json:"jobId"`
+}
+
 type GetFileDetailRequest struct {
 	FileID string `json:"fileId"`
 }
 
+type GetHandoverItemsRequest struct {
+	TaskID         string `json:"taskId"`
+	FromEmployeeID string `json:"fromEmployeeId,optional"` // 交出人，默认为当前员工
+}
+
 type GetHandoverRequest struct {
 	HandoverID string `json:"handoverId"`
 }
 
+type GetImportRequest struct {
+	JobID string `json:"jobId"`
+}
+
 type GetInviteCodeListRequest struct {
 	PageReq
 }
@@ -495,9 +859,19 @@ type GetTaskAttachmentsRequest struct {
 	PageReq
 }
 
+type GetTaskCommentRepliesRequest struct {
+	CommentID string `json:"commentId"` // 顶级评论ID
+	PageReq
+}
+
+type GetTaskCommentRevisionsRequest struct {
+	CommentID string `json:"commentId"`
+}
+
 type GetTaskCommentsRequest struct {
 	TaskID     string `json:"taskId"`
 	TaskNodeID string `json:"taskNodeId,optional"`
+	ReplyLimit int    `json:"replyLimit,optional"` // 每个评论附带的回复数，默认3
 	PageReq
 }
 
@@ -514,6 +888,18 @@ type GetTaskRequest struct {
 	TaskID string `json:"taskId"`
 }
 
+type GetTimesheetRequest struct {
+	WeekStart  string `json:"weekStart,optional"`  // 周内任意日期，默认本周
+	EmployeeId string `json:"employeeId,optional"` // 默认为当前员工
+}
+
+type GrantPermissionRequest struct {
+	EmployeeId string `json:"employeeId"`          // 被授权员工ID
+	PermCodes  []int  `json:"permCodes"`           // 权限码列表
+	ExpireTime string `json:"expireTime,optional"` // 过期时间，格式 2006-01-02 15:04:05，为空表示长期有效
+	Reason     string `json:"reason,optional"`
+}
+
 type HandoverInfo struct {
 	HandoverID     string `json:"handoverId"`
 	TaskID         string `json:"taskId"`
@@ -530,6 +916,23 @@ type HandoverInfo struct {
 	UpdateTime     string `json:"updateTime"`
 }
 
+type HandoverItemInfo struct {
+	ID           string `json:"id"` // 交接事项ID，可交接事项列表中为空
+	ItemType     string `json:"itemType"`
+	ItemTypeName string `json:"itemTypeName"`
+	TaskNodeID   string `json:"taskNodeId"`
+	NodeName     string `json:"nodeName"`
+	TargetID     string `json:"targetId"`
+	TargetName   string `json:"targetName"`
+	Status       int64  `json:"status"` // 0-待交接 1-已交接 2-已跳过
+	Remark       string `json:"remark"`
+}
+
+type HandoverItemInput struct {
+	ItemType string `json:"itemType"` // node_executor-节点执行人 node_leader-节点负责人 checklist-清单 attachment-附件
+	TargetID string `json:"targetId"` // 节点角色填节点ID，清单填清单ID，附件填文件ID
+}
+
 type HandoverListRequest struct {
 	PageReq
 	TaskID         string `json:"taskId,optional"`
@@ -545,6 +948,60 @@ type HandoverTaskRequest struct {
 	Description  string `json:"description"`
 }
 
+type ImportColumnInfo struct {
+	Key         string `json:"key"`
+	Title       string `json:"title"` // 表头名称，表头也可以直接使用 key
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+type ImportColumnsRequest struct {
+	ImportType string `json:"importType"`
+}
+
+type ImportJobInfo struct {
+	ID             string `json:"id"`
+	ImportType     string `json:"importType"`
+	ImportTypeName string `json:"importTypeName"`
+	FileName       string `json:"fileName"`
+	Status         int64  `json:"status"`    // 0-校验未通过 1-待导入 2-导入中 3-已中断 4-已完成
+	TotalRows      int64  `json:"totalRows"` // 待导入的记录数（任务导入按任务计数）
+	ErrorCount     int64  `json:"errorCount"`
+	AppliedRows    int64  `json:"appliedRows"`  // 已写入的记录数
+	EmployeeID     string `json:"employeeId"`   // 发起人
+	ErrorMessage   string `json:"errorMessage"` // 中断原因
+	CreateTime     string `json:"createTime"`
+	FinishTime     string `json:"finishTime"`
+}
+
+type ImportListRequest struct {
+	PageReq
+}
+
+type ImportReport struct {
+	Job       ImportJobInfo    `json:"job"`
+	Errors    []ImportRowError `json:"errors"`
+	Truncated bool             `json:"truncated"` // 错误过多时只返回前面的部分
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"` // 文件中的行号（表头为第 1 行）
+	Message string `json:"message"`
+}
+
+type InitUploadSessionRequest struct {
+	Mode        string `json:"mode,optional"`        // chunked 分片上传（默认）/ direct 预签名直传
+	FileName    string `json:"fileName"`             // 文件名
+	FileSize    int64  `json:"fileSize"`             // 文件大小（字节）
+	Checksum    string `json:"checksum,optional"`    // 整个文件的 SHA-256（十六进制），完成时校验
+	Module      string `json:"module,optional"`      // 所属业务模块，默认 general
+	Category    string `json:"category,optional"`    // 文件分类，默认 attachment
+	RelatedID   string `json:"relatedId,optional"`   // 关联的业务ID（如任务ID）
+	TaskNodeID  string `json:"taskNodeId,optional"`  // 任务节点ID，任务附件必填
+	Description string `json:"description,optional"` // 文件描述
+	Tags        string `json:"tags,optional"`        // 文件标签，多个标签用逗号分隔
+}
+
 type InviteCodeInfo struct {
 	InviteCode  string `json:"inviteCode"`
 	CompanyID   string `json:"companyId"`
@@ -591,31 +1048,42 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token            string `json:"token"`
-	UserID           string `json:"userId"`
-	Username         string `json:"username"`
-	RealName         string `json:"realName"`
-	HasJoinedCompany bool   `json:"hasJoinedCompany,optional"`
+	Token            string                  `json:"token"`
+	UserID           string                  `json:"userId"`
+	Username         string                  `json:"username"`
+	RealName         string                  `json:"realName"`
+	HasJoinedCompany bool                    `json:"hasJoinedCompany,optional"`
+	CompanyID        string                  `json:"companyId,optional"`   // 当前公司ID
+	EmployeeID       string                  `json:"employeeId,optional"`  // 当前公司的员工ID
+	Memberships      []CompanyMembershipInfo `json:"memberships,optional"` // 用户所在的全部公司
 }
 
 type MarkNotificationReadRequest struct {
 	NotificationID string `json:"notificationId"`
 }
 
+type MoveBoardCardRequest struct {
+	BoardID     string `json:"boardId"`
+	NodeID      string `json:"nodeId"`
+	ColumnID    string `json:"columnId"`             // 目标列
+	AfterNodeID string `json:"afterNodeId,optional"` // 放在该节点之后，不填时放在列首
+}
+
 type MyAttachmentInfo struct {
-	FileID      string `json:"fileId"`
-	FileURL     string `json:"fileUrl"`
-	FileName    string `json:"fileName"`
-	FileSize    int64  `json:"fileSize"`
-	FileType    string `json:"fileType"`
-	Module      string `json:"module"`
-	Category    string `json:"category"`
-	RelatedID   string `json:"relatedId,optional"`
-	RelatedName string `json:"relatedName,optional"` // 关联任务/节点名称
-	TaskNodeID  string `json:"taskNodeId,optional"`
-	Description string `json:"description,optional"`
-	Tags        string `json:"tags,optional"`
-	UploadTime  string `json:"uploadTime"`
+	FileID       string `json:"fileId"`
+	FileURL      string `json:"fileUrl"`
+	FileName     string `json:"fileName"`
+	FileSize     int64  `json:"fileSize"`
+	FileType     string `json:"fileType"`
+	Module       string `json:"module"`
+	Category     string `json:"category"`
+	RelatedID    string `json:"relatedId,optional"`
+	RelatedName  string `json:"relatedName,optional"` // 关联任务/节点名称
+	TaskNodeID   string `json:"taskNodeId,optional"`
+	Description  string `json:"description,optional"`
+	Tags         string `json:"tags,optional"`
+	ThumbnailURL string `json:"thumbnailUrl,optional"`
+	UploadTime   string `json:"uploadTime"`
 }
 
 type MyChecklistItem struct {
@@ -660,6 +1128,63 @@ type NotificationListRequest struct {
 	IsRead     int    `json:"isRead,optional"`
 }
 
+type OffboardingAssignment struct {
+	ItemID     string `json:"itemId"`
+	AssigneeID string `json:"assigneeId"` // 为空表示取消分配
+}
+
+type OffboardingPlanInfo struct {
+	PlanID          string                    `json:"planId"`
+	ApprovalID      string                    `json:"approvalId"`
+	EmployeeID      string                    `json:"employeeId"`
+	EmployeeName    string                    `json:"employeeName"`
+	Status          int64                     `json:"status"` // 0-待执行 1-已执行
+	TotalCount      int                       `json:"totalCount"`
+	UnassignedCount int                       `json:"unassignedCount"`
+	AppliedBy       string                    `json:"appliedBy"`
+	AppliedTime     string                    `json:"appliedTime"`
+	UpdateTime      string                    `json:"updateTime"`
+	Items           []OffboardingPlanItemInfo `json:"items"`
+}
+
+type OffboardingPlanItemInfo struct {
+	ID            string `json:"id"`
+	ItemType      string `json:"itemType"`
+	ItemTypeName  string `json:"itemTypeName"`
+	TargetID      string `json:"targetId"`
+	TaskID        string `json:"taskId"`
+	TargetName    string `json:"targetName"`
+	SuggestedID   string `json:"suggestedId"`
+	SuggestedName string `json:"suggestedName"`
+	SuggestReason string `json:"suggestReason"`
+	AssigneeID    string `json:"assigneeId"`
+	AssigneeName  string `json:"assigneeName"`
+}
+
+type OffboardingPlanRequest struct {
+	ApprovalID string `json:"approvalId"` // 离职审批ID
+}
+
+type OutOfOfficeInfo struct {
+	ID                  string `json:"id"`
+	EmployeeID          string `json:"employeeId"`
+	EmployeeName        string `json:"employeeName"`
+	DelegateID          string `json:"delegateId"`
+	DelegateName        string `json:"delegateName"`
+	StartTime           string `json:"startTime"`
+	EndTime             string `json:"endTime"`
+	Reason              string `json:"reason"`
+	Status              int    `json:"status"`              // 0-待生效 1-生效中 2-已结束 3-已取消
+	RoutedItemCount     int    `json:"routedItemCount"`     // 转交给代理人的事项数
+	RoutedNotifications int64  `json:"routedNotifications"` // 转发给代理人的通知数
+	CreateTime          string `json:"createTime"`
+}
+
+type OutOfOfficeListRequest struct {
+	EmployeeID      string `json:"employeeId,optional"`      // 不填则为当前员工
+	IncludeFinished bool   `json:"includeFinished,optional"` // 是否包含已结束/已取消的记录
+}
+
 type PageReq struct {
 	Page     int `json:"page,optional"`
 	PageSize int `json:"pageSize,optional"`
@@ -710,7 +1235,14 @@ type PositionRolesRequest struct {
 }
 
 type ProxyFileRequest struct {
-	FileID string `json:"fileId"`
+	FileID    string `json:"fileId"`
+	Version   int64  `json:"version,optional"`   // 附件版本，不填时为当前版本
+	Rendition string `json:"rendition,optional"` // thumbnail 缩略图 / preview 预览图，不填时为原文件
+}
+
+type ReactTaskCommentRequest struct {
+	CommentID string `json:"commentId"`
+	Emoji     string `json:"emoji"` // 表情，已回应时再次提交为取消
 }
 
 type RegisterRequest struct {
@@ -733,10 +1265,27 @@ type ResolveAttachmentCommentRequest struct {
 	Resolved  int    `json:"resolved"` // 1-已解决，0-未解决
 }
 
+type RestoreFileVersionRequest struct {
+	FileID     string `json:"fileId"`
+	Version    int64  `json:"version"`
+	ChangeNote string `json:"changeNote,optional"`
+}
+
+type ReviewTimesheetRequest struct {
+	TimesheetId string `json:"timesheetId"`
+	Result      int    `json:"result"` // 1-通过 2-驳回
+	Note        string `json:"note,optional"`
+}
+
 type RevokeInviteCodeRequest struct {
 	InviteCode string `json:"inviteCode"`
 }
 
+type RevokePermissionRequest struct {
+	Id     string `json:"id"` // 授权记录ID
+	Reason string `json:"reason,optional"`
+}
+
 type RevokeRoleRequest struct {
 	PositionId string `json:"positionId"` // 职位ID（改为从职位撤销角色）
 	RoleId     string `json:"roleId"`
@@ -765,32 +1314,136 @@ type SendVerificationCodeRequest struct {
 	Type  string `json:"type"` // register/reset
 }
 
+type SetCapacityRequest struct {
+	EmployeeID  string  `json:"employeeId"`
+	WeeklyHours float64 `json:"weeklyHours"` // 每周可用工时 0-80
+}
+
+type SetCustomFieldValuesRequest struct {
+	EntityType string                  `json:"entityType"`
+	EntityID   string                  `json:"entityId"` // 任务ID或任务节点ID
+	Values     []CustomFieldValueInput `json:"values"`
+}
+
+type SetOutOfOfficeRequest struct {
+	EmployeeID string `json:"employeeId,optional"` // 外出员工ID，不填则为当前员工
+	DelegateID string `json:"delegateId"`          // 代理人员工ID
+	StartTime  string `json:"startTime"`           // 开始时间，格式 2006-01-02 15:04:05
+	EndTime    string `json:"endTime"`             // 结束时间，格式 2006-01-02 15:04:05
+	Reason     string `json:"reason,optional"`
+}
+
+type SetTaskLabelsRequest struct {
+	TaskID   string   `json:"taskId"`
+	LabelIDs []string `json:"labelIds,optional"` // 替换任务的全部标签，为空时清除
+}
+
+type StartTimerRequest struct {
+	TaskNodeId  string `json:"taskNodeId"`
+	ChecklistId string `json:"checklistId,optional"` // 关联的任务清单
+	Note        string `json:"note,optional"`
+}
+
+type StorageUsageBucket struct {
+	Key   string `json:"key"`           // 员工ID、任务ID、模块或文件类型
+	Name  string `json:"name,optional"` // 员工姓名或任务标题
+	Bytes int64  `json:"bytes"`         // 存储用量（字节）
+	Files int64  `json:"files"`         // 文件数
+}
+
+type StorageUsageInfo struct {
+	CompanyID      string               `json:"companyId"`
+	Company        StorageUsageItem     `json:"company"`             // 公司合计和公司配额
+	Me             StorageUsageItem     `json:"me,optional"`         // 当前员工的用量和员工配额（管理后台查询时为空）
+	UserQuotaBytes int64                `json:"userQuotaBytes"`      // 每个员工的配额（字节），0 表示不限制
+	CompanyQuotaMB int64                `json:"companyQuotaMb"`      // 公司单独设置的总配额（MB），-1 表示使用系统设置
+	UserQuotaMB    int64                `json:"userQuotaMb"`         // 公司单独设置的员工配额（MB），-1 表示使用系统设置
+	Customized     bool                 `json:"customized"`          // 公司是否单独设置了配额
+	ByUser         []StorageUsageBucket `json:"byUser,optional"`     // 按员工，只有管理人员可见
+	ByTask         []StorageUsageBucket `json:"byTask,optional"`     // 按任务，只有管理人员可见
+	ByModule       []StorageUsageBucket `json:"byModule,optional"`   // 按业务模块，只有管理人员可见
+	ByFileType     []StorageUsageBucket `json:"byFileType,optional"` // 按文件类型，只有管理人员可见
+}
+
+type StorageUsageItem struct {
+	UsedBytes  int64 `json:"usedBytes"`  // 已使用的存储（字节）
+	FileCount  int64 `json:"fileCount"`  // 存储的文件数（附件的每个版本单独计算）
+	QuotaBytes int64 `json:"quotaBytes"` // 配额（字节），0 表示不限制
+}
+
 type SubmitTaskNodeCompletionApprovalRequest struct {
 	NodeID string `json:"nodeId"`
 }
 
+type SubmitTimesheetRequest struct {
+	WeekStart string `json:"weekStart,optional"` // 周内任意日期，默认本周
+}
+
+type SwitchCompanyRequest struct {
+	CompanyID string `json:"companyId"`
+}
+
+type TaskBurnPoint struct {
+	Date      string  `json:"date"`      // 日期 YYYY-MM-DD
+	Scope     int64   `json:"scope"`     // 当天结束时的工作项总数
+	Completed int64   `json:"completed"` // 当天结束时已完成的工作项数
+	Remaining int64   `json:"remaining"` // 当天结束时剩余的工作项数
+	Ideal     float64 `json:"ideal"`     // 理想线（燃尽图为理想剩余量，燃起图为理想完成量）
+}
+
+type TaskBurnResponse struct {
+	TaskID   string          `json:"taskId"`
+	Unit     string          `json:"unit"`     // 统计口径 node/checklist
+	Deadline string          `json:"deadline"` // 任务截止时间
+	Points   []TaskBurnPoint `json:"points"`
+}
+
+type TaskChartRequest struct {
+	TaskID string `json:"taskId"`
+	Unit   string `json:"unit,optional"` // 统计口径 node/checklist，默认 node
+}
+
 type TaskCommentInfo struct {
-	ID              string            `json:"id"`
-	CommentID       string            `json:"commentId"`
-	TaskID          string            `json:"taskId"`
-	TaskNodeID      string            `json:"taskNodeId,optional"`
-	UserID          string            `json:"userId"`
-	EmployeeID      string            `json:"employeeId"`
-	EmployeeName    string            `json:"employeeName"`
-	Content         string            `json:"content"`
-	ContentHTML     string            `json:"contentHtml,optional"`
-	AtEmployeeIDs   []string          `json:"atEmployeeIds,optional"`
-	AtEmployeeNames []string          `json:"atEmployeeNames,optional"`
-	ParentID        string            `json:"parentId,optional"`
-	ReplyToUserID   string            `json:"replyToUserId,optional"`
-	ReplyToName     string            `json:"replyToName,optional"`
-	AttachmentIDs   []string          `json:"attachmentIds,optional"`
-	AttachmentURLs  []string          `json:"attachmentUrls,optional"`
-	LikeCount       int64             `json:"likeCount"`
-	IsLiked         bool              `json:"isLiked"`          // 当前用户是否已点赞
-	Replies         []TaskCommentInfo `json:"replies,optional"` // 回复列表
-	CreateTime      string            `json:"createTime"`
-	UpdateTime      string            `json:"updateTime"`
+	ID              string                `json:"id"`
+	CommentID       string                `json:"commentId"`
+	TaskID          string                `json:"taskId"`
+	TaskNodeID      string                `json:"taskNodeId,optional"`
+	UserID          string                `json:"userId"`
+	EmployeeID      string                `json:"employeeId"`
+	EmployeeName    string                `json:"employeeName"`
+	Content         string                `json:"content"`
+	ContentHTML     string                `json:"contentHtml,optional"`
+	AtEmployeeIDs   []string              `json:"atEmployeeIds,optional"`
+	AtEmployeeNames []string              `json:"atEmployeeNames,optional"`
+	ParentID        string                `json:"parentId,optional"`
+	ReplyToUserID   string                `json:"replyToUserId,optional"`
+	ReplyToName     string                `json:"replyToName,optional"`
+	AttachmentIDs   []string              `json:"attachmentIds,optional"`
+	AttachmentURLs  []string              `json:"attachmentUrls,optional"`
+	LikeCount       int64                 `json:"likeCount"`
+	IsLiked         bool                  `json:"isLiked"`           // 当前用户是否已点赞
+	Reactions       []TaskCommentReaction `json:"reactions"`         // 表情回应
+	Edited          bool                  `json:"edited"`            // 是否编辑过
+	EditedAt        string                `json:"editedAt,optional"` // 最后编辑时间
+	CanEdit         bool                  `json:"canEdit"`           // 当前用户能否编辑和删除
+	Replies         []TaskCommentInfo     `json:"replies,optional"`  // 回复列表，列表接口只附带最早的几条
+	ReplyCount      int64                 `json:"replyCount"`        // 回复总数
+	CreateTime      string                `json:"createTime"`
+	UpdateTime      string                `json:"updateTime"`
+}
+
+type TaskCommentReaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"` // 当前用户是否已回应
+}
+
+type TaskCommentRevisionInfo struct {
+	Content     string `json:"content"`
+	ContentHTML string `json:"contentHtml,optional"`
+	EditedBy    string `json:"editedBy"`
+	EditorName  string `json:"editorName"`
+	EditedAt    string `json:"editedAt"`
 }
 
 type TaskDetailInfo struct {
@@ -803,26 +1456,72 @@ type TaskDetailRequest struct {
 	TaskID string `json:"taskId"`
 }
 
+type TaskFlowPoint struct {
+	Date       string `json:"date"`       // 日期 YYYY-MM-DD
+	NotStarted int64  `json:"notStarted"` // 未开始
+	InProgress int64  `json:"inProgress"` // 进行中
+	Overdue    int64  `json:"overdue"`    // 已过截止时间未完成
+	Completed  int64  `json:"completed"`  // 已完成
+}
+
+type TaskFlowResponse struct {
+	TaskID string          `json:"taskId"`
+	Points []TaskFlowPoint `json:"points"`
+}
+
+type TaskForecastInfo struct {
+	TaskID           string  `json:"taskId"`
+	TaskTitle        string  `json:"taskTitle"`
+	Unit             string  `json:"unit"`             // 统计口径 node/checklist
+	Scope            int64   `json:"scope"`            // 工作项总数
+	Completed        int64   `json:"completed"`        // 已完成数
+	Remaining        int64   `json:"remaining"`        // 剩余数
+	Velocity         float64 `json:"velocity"`         // 近 14 天平均每天完成数
+	RequiredVelocity float64 `json:"requiredVelocity"` // 按期完成需要的每天完成数
+	ProjectedDate    string  `json:"projectedDate"`    // 预计完成日期，无进展时为空
+	Deadline         string  `json:"deadline"`         // 任务截止时间
+	AtRisk           bool    `json:"atRisk"`           // 是否存在延期风险
+	RiskReason       string  `json:"riskReason"`       // 风险原因 no_progress/behind_schedule/overdue
+}
+
 type TaskInfo struct {
-	ID                     string         `json:"id"`
-	TaskTitle              string         `json:"taskTitle"`
-	TaskDescription        string         `json:"taskDescription"`
-	TaskType               string         `json:"taskType"`
-	Priority               int            `json:"priority"`
-	Status                 int            `json:"status"`
-	CompanyID              string         `json:"companyId"`
-	DepartmentID           string         `json:"departmentId"`
-	CreatorID              string         `json:"creatorId"`
-	LeaderId               string         `json:"leaderId"`
-	ResponsibleEmployeeIds string         `json:"responsibleEmployeeIds"`
-	StartTime              string         `json:"startTime"`
-	Deadline               string         `json:"deadline"`
-	EstimatedHours         int            `json:"estimatedHours"`
-	ActualHours            int            `json:"actualHours"`
-	Progress               int            `json:"progress"`
-	CreateTime             string         `json:"createTime"`
-	UpdateTime             string         `json:"updateTime"`
-	Nodes                  []TaskNodeInfo `json:"nodes,optional"`
+	ID                     string                 `json:"id"`
+	TaskTitle              string                 `json:"taskTitle"`
+	TaskDescription        string                 `json:"taskDescription"`
+	TaskDescriptionHTML    string                 `json:"taskDescriptionHtml,optional"` // 任务详情渲染后的HTML，仅详情接口返回
+	TaskType               string                 `json:"taskType"`
+	Priority               int                    `json:"priority"`
+	Status                 int                    `json:"status"`
+	CompanyID              string                 `json:"companyId"`
+	DepartmentID           string                 `json:"departmentId"`
+	CreatorID              string                 `json:"creatorId"`
+	LeaderId               string                 `json:"leaderId"`
+	ResponsibleEmployeeIds string                 `json:"responsibleEmployeeIds"`
+	StartTime              string                 `json:"startTime"`
+	Deadline               string                 `json:"deadline"`
+	EstimatedHours         int                    `json:"estimatedHours"`
+	ActualHours            int                    `json:"actualHours"`
+	Progress               int                    `json:"progress"`
+	CreateTime             string                 `json:"createTime"`
+	UpdateTime             string                 `json:"updateTime"`
+	Nodes                  []TaskNodeInfo         `json:"nodes,optional"`
+	Labels                 []TaskLabelBrief       `json:"labels,optional"`
+	CustomFields           []CustomFieldValueInfo `json:"customFields,optional"`
+}
+
+type TaskLabelBrief struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TaskLabelInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	TaskCount   int64  `json:"taskCount"` // 关联的任务数
+	CreateTime  string `json:"createTime"`
 }
 
 type TaskListRequest struct {
@@ -857,6 +1556,7 @@ type TaskNodeInfo struct {
 	TaskID            string `json:"taskId"`
 	NodeName          string `json:"nodeName"`
 	NodeDetail        string `json:"nodeDetail"`
+	NodeDetailHTML    string `json:"nodeDetailHtml,optional"` // 节点详情渲染后的HTML，仅详情接口返回
 	NodeType          string `json:"nodeType"`
 	Status            int    `json:"status"`
 	DepartmentID      string `json:"departmentId"`
@@ -878,6 +1578,140 @@ type TaskNodeListRequest struct {
 	Status       int    `json:"status,optional"`
 }
 
+type TaskSearchFilter struct {
+	Keyword       string              `json:"keyword,optional"`
+	Scope         string              `json:"scope,optional"`         // involved-只看我参与的；管理人员默认查询全公司，其他员工只能查询参与的任务
+	Statuses      []int               `json:"statuses,optional"`      // 任务状态
+	Priorities    []int               `json:"priorities,optional"`    // 任务优先级
+	TaskTypes     []int               `json:"taskTypes,optional"`     // 任务类型
+	AssigneeIDs   []string            `json:"assigneeIds,optional"`   // 节点执行人，me 表示当前员工
+	LeaderIDs     []string            `json:"leaderIds,optional"`     // 任务负责人，me 表示当前员工
+	CreatorIDs    []string            `json:"creatorIds,optional"`    // 任务创建者，me 表示当前员工
+	DepartmentIDs []string            `json:"departmentIds,optional"` // 涉及部门（任务涉及部门或节点所属部门）
+	DeadlineFrom  string              `json:"deadlineFrom,optional"`  // 截止时间下限 YYYY-MM-DD 或 YYYY-MM-DD HH:mm:ss
+	DeadlineTo    string              `json:"deadlineTo,optional"`    // 截止时间上限，只填日期时包含当天
+	Overdue       int                 `json:"overdue,optional"`       // 0-不限 1-已逾期未完成 2-未逾期
+	ProgressMin   *int                `json:"progressMin,optional"`   // 进度下限 0-100
+	ProgressMax   *int                `json:"progressMax,optional"`   // 进度上限 0-100
+	Prerequisite  string              `json:"prerequisite,optional"`  // blocked-有节点在等待前置节点 ready-没有被阻塞的节点
+	LabelIDs      []string            `json:"labelIds,optional"`      // 任务标签
+	LabelMatch    string              `json:"labelMatch,optional"`    // any-包含任一标签（默认） all-包含全部标签
+	CustomFields  []CustomFieldFilter `json:"customFields,optional"`  // 自定义字段条件，节点字段匹配任一节点
+}
+
+type TaskSearchRequest struct {
+	ViewID string           `json:"viewId,optional"` // 使用保存的视图，指定后忽略 filter 和 sort
+	Filter TaskSearchFilter `json:"filter,optional"`
+	Sort   []TaskSortField  `json:"sort,optional"`   // 最多 3 个字段，默认按创建时间倒序
+	Cursor string           `json:"cursor,optional"` // 上一页返回的 nextCursor
+	Limit  int              `json:"limit,optional"`  // 每页条数，默认 20，最多 100
+}
+
+type TaskSearchResponse struct {
+	List       []TaskInfo `json:"list"`
+	Total      int64      `json:"total"`
+	NextCursor string     `json:"nextCursor"`
+	HasMore    bool       `json:"hasMore"`
+}
+
+type TaskSortField struct {
+	Field string `json:"field"`          // deadline/createTime/updateTime/priority/status/progress/title
+	Order string `json:"order,optional"` // asc/desc，默认 asc
+}
+
+type TaskViewInfo struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Shared     bool             `json:"shared"`
+	OwnerID    string           `json:"ownerId"`
+	OwnerName  string           `json:"ownerName"`
+	IsOwner    bool             `json:"isOwner"`
+	Filter     TaskSearchFilter `json:"filter"`
+	Sort       []TaskSortField  `json:"sort"`
+	CreateTime string           `json:"createTime"`
+	UpdateTime string           `json:"updateTime"`
+}
+
+type TaskWatchRequest struct {
+	TaskID     string `json:"taskId"`
+	TaskNodeID string `json:"taskNodeId,optional"` // 为空时为整个任务
+}
+
+type TaskWatcherInfo struct {
+	EmployeeID   string `json:"employeeId"`
+	EmployeeName string `json:"employeeName"`
+	Source       string `json:"source"`    // manual-主动关注 comment-评论 mention-被@提及 assigned-被分配
+	WatchedAt    string `json:"watchedAt"` // 关注时间
+}
+
+type TimeEntryInfo struct {
+	Id              string  `json:"id"`
+	TaskId          string  `json:"taskId"`
+	TaskNodeId      string  `json:"taskNodeId"`
+	NodeName        string  `json:"nodeName"`
+	ChecklistId     string  `json:"checklistId"`
+	EmployeeId      string  `json:"employeeId"`
+	EmployeeName    string  `json:"employeeName"`
+	EntryType       int     `json:"entryType"` // 0-计时器 1-手工填报
+	WorkDate        string  `json:"workDate"`
+	StartTime       string  `json:"startTime"`
+	EndTime         string  `json:"endTime"`
+	DurationMinutes int64   `json:"durationMinutes"`
+	Hours           float64 `json:"hours"`
+	Running         bool    `json:"running"` // 是否计时中
+	Note            string  `json:"note"`
+	TimesheetId     string  `json:"timesheetId"`
+	CreateTime      string  `json:"createTime"`
+}
+
+type TimeEntryListRequest struct {
+	PageReq
+	EmployeeId string `json:"employeeId,optional"` // 默认为当前员工
+	TaskId     string `json:"taskId,optional"`
+	TaskNodeId string `json:"taskNodeId,optional"`
+	StartDate  string `json:"startDate,optional"` // 格式 2006-01-02
+	EndDate    string `json:"endDate,optional"`
+}
+
+type TimeReportItem struct {
+	Id             string  `json:"id"` // 员工ID或部门ID
+	Name           string  `json:"name"`
+	EstimatedHours float64 `json:"estimatedHours"`
+	ActualHours    float64 `json:"actualHours"`
+	VarianceHours  float64 `json:"varianceHours"` // 实际 - 预计
+	VarianceRate   float64 `json:"varianceRate"`  // 偏差率（%），无预计工时时为 0
+	NodeCount      int     `json:"nodeCount"`
+}
+
+type TimeReportRequest struct {
+	StartDate    string `json:"startDate"` // 格式 2006-01-02
+	EndDate      string `json:"endDate"`
+	DepartmentId string `json:"departmentId,optional"` // 只统计该部门的节点
+}
+
+type TimesheetInfo struct {
+	Id           string          `json:"id"` // 未提交时为空
+	EmployeeId   string          `json:"employeeId"`
+	EmployeeName string          `json:"employeeName"`
+	WeekStart    string          `json:"weekStart"`
+	WeekEnd      string          `json:"weekEnd"`
+	TotalHours   float64         `json:"totalHours"`
+	DailyHours   []float64       `json:"dailyHours"` // 周一至周日每天的工时
+	Status       int             `json:"status"`     // 0-未提交 1-待审批 2-已通过 3-已驳回
+	ApproverId   string          `json:"approverId"`
+	ApproverName string          `json:"approverName"`
+	ApproveNote  string          `json:"approveNote"`
+	SubmitTime   string          `json:"submitTime"`
+	ApproveTime  string          `json:"approveTime"`
+	Entries      []TimeEntryInfo `json:"entries"`
+}
+
+type UpdateBoardRequest struct {
+	BoardID string             `json:"boardId"`
+	Name    string             `json:"name,optional"`
+	Columns []BoardColumnInput `json:"columns,optional"` // 按顺序替换全部列，未带上的已有列被删除
+}
+
 type UpdateChecklistRequest struct {
 	ChecklistID string `json:"checklistId"`          // 清单ID
 	Content     string `json:"content,optional"`     // 清单内容
@@ -896,6 +1730,14 @@ type UpdateCompanyRequest struct {
 	Email             string `json:"email,optional"`
 }
 
+type UpdateCustomFieldRequest struct {
+	FieldID  string                  `json:"fieldId"` // 字段类型和适用对象创建后不能修改
+	Name     string                  `json:"name"`
+	Options  []CustomFieldOptionInfo `json:"options,optional"` // 保留的选项需带上 id，未带上的选项被删除
+	Required bool                    `json:"required,optional"`
+	Sort     int64                   `json:"sort,optional"`
+}
+
 type UpdateDepartmentRequest struct {
 	ID             string `json:"id"`
 	DepartmentName string `json:"departmentName,optional"`
@@ -961,11 +1803,25 @@ type UpdateRoleRequest struct {
 	Status      int    `json:"status,optional"`
 }
 
+type UpdateTaskCommentRequest struct {
+	CommentID     string   `json:"commentId"`
+	Content       string   `json:"content"`
+	ContentHTML   string   `json:"contentHtml,optional"`
+	AtEmployeeIDs []string `json:"atEmployeeIds,optional"`
+}
+
 type UpdateTaskDetailRequest struct {
 	TaskId string               `json:"taskId"` // 任务对应的Id
 	File   []UploadInfoResponse `json:"file"`   // 返回响应
 }
 
+type UpdateTaskLabelRequest struct {
+	LabelID     string `json:"labelId"`
+	Name        string `json:"name"`
+	Color       string `json:"color,optional"`
+	Description string `json:"description,optional"`
+}
+
 type UpdateTaskNodeRequest struct {
 	NodeID            string   `json:"nodeId"`
 	NodeName          string   `json:"nodeName,optional"`          // 任务名称
@@ -1000,6 +1856,28 @@ type UpdateTaskRequest struct {
 	UpdateNote             string `json:"updateNote,optional"`
 }
 
+type UpdateTaskViewRequest struct {
+	ViewID string           `json:"viewId"`
+	Name   string           `json:"name"`
+	Shared bool             `json:"shared,optional"`
+	Filter TaskSearchFilter `json:"filter,optional"`
+	Sort   []TaskSortField  `json:"sort,optional"`
+}
+
+type UpdateTimeEntryRequest struct {
+	EntryId         string `json:"entryId"`
+	WorkDate        string `json:"workDate,optional"`
+	DurationMinutes int    `json:"durationMinutes,optional"`
+	ChecklistId     string `json:"checklistId,optional"`
+	Note            string `json:"note,optional"`
+}
+
+type UpdateUploadPolicyRequest struct {
+	AllowedTypes  []string `json:"allowedTypes,optional"`  // 允许的文件类型，为空时允许全部类型
+	MaxFileSizeMB int      `json:"maxFileSizeMb,optional"` // 单个文件大小上限（MB），0 表示使用系统设置
+	Reset         bool     `json:"reset,optional"`         // 删除公司策略，恢复系统默认
+}
+
 type UploadAvatarRequest struct {
 	UserID string `form:"userId,optional"`
 }
@@ -1011,6 +1889,15 @@ type UploadAvatarResponse struct {
 	FileName  string `json:"fileName"`
 }
 
+type UploadFileVersionRequest struct {
+	FileID     string `form:"fileId"`
+	ChangeNote string `form:"changeNote,optional"` // 版本说明
+}
+
+type UploadImportRequest struct {
+	ImportType string `form:"importType"` // department/position/employee/task，文件通过 multipart 的 file 字段上传（csv/xlsx）
+}
+
 type UploadInfoRequest struct {
 	Module      string `form:"module"`               // 所属业务模块：task/employee/company/department/position/handover/notification/user等
 	FileType    string `form:"fileType,optional"`    // 文件类型：image/pdf/markdown/document/excel/word等（如果前端不传，后端可从文件扩展名推断）
@@ -1030,6 +1917,79 @@ type UploadInfoResponse struct {
 	FileType  string `json:"fileType"`           // 文件类型
 }
 
+type UploadPolicyInfo struct {
+	AllowedTypes        []string `json:"allowedTypes"`        // 允许的文件类型，为空时允许全部类型
+	MaxFileSizeMB       int      `json:"maxFileSizeMb"`       // 生效的单个文件大小上限（MB）
+	SystemMaxFileSizeMB int      `json:"systemMaxFileSizeMb"` // 系统设置的大小上限，公司策略不能超过
+	FileKinds           []string `json:"fileKinds"`           // 可选的文件类型
+	Customized          bool     `json:"customized"`          // 公司是否配置了上传策略
+}
+
+type UploadSessionInfo struct {
+	UploadID      string  `json:"uploadId"`
+	Mode          string  `json:"mode"`
+	FileName      string  `json:"fileName"`
+	FileSize      int64   `json:"fileSize"`
+	PartSize      int64   `json:"partSize,optional"`     // 分片大小（字节），直传为 0
+	PartCount     int64   `json:"partCount,optional"`    // 分片数，直传为 0
+	UploadedParts []int64 `json:"uploadedParts"`         // 已上传的分片序号，断点续传时跳过
+	UploadURL     string  `json:"uploadUrl,optional"`    // 直传的预签名地址，使用 PUT 上传文件内容
+	Status        string  `json:"status"`                // uploading/completing/completed/aborted/failed
+	FileID        string  `json:"fileId,optional"`       // 完成后创建的附件ID
+	ErrorMessage  string  `json:"errorMessage,optional"` // 失败原因
+	ExpireTime    string  `json:"expireTime"`
+}
+
+type UploadSessionPartRequest struct {
+	UploadID   string `form:"uploadId"`
+	PartNumber int64  `form:"partNumber"`   // 分片序号，从 1 开始
+	Md5        string `form:"md5,optional"` // 分片内容的 MD5（十六进制），服务端校验
+}
+
+type UploadSessionRequest struct {
+	UploadID string `json:"uploadId"`
+}
+
+type UserPermissionInfo struct {
+	Id             string `json:"id"`
+	UserId         string `json:"userId"`
+	CompanyId      string `json:"companyId"`
+	PermissionCode int    `json:"permissionCode"`
+	PermissionName string `json:"permissionName"`
+	GrantType      int    `json:"grantType"` // 0-直接授权 1-角色授权 2-部门授权 3-临时委派
+	GrantBy        string `json:"grantBy"`
+	ExpireTime     string `json:"expireTime"`
+	Status         int    `json:"status"` // 0-已撤销/已过期 1-正常
+	CreateTime     string `json:"createTime"`
+}
+
+type UserPermissionListRequest struct {
+	EmployeeId      string `json:"employeeId"`
+	IncludeInactive bool   `json:"includeInactive,optional"` // 是否包含已撤销/已过期的授权
+}
+
+type WorkloadHeatmapRequest struct {
+	DepartmentID string `json:"departmentId,optional"` // 为空时为当前员工所在部门
+	Weeks        int    `json:"weeks,optional"`        // 统计周数，默认 4，最多 12
+}
+
+type WorkloadHeatmapResponse struct {
+	DepartmentID    string             `json:"departmentId"`
+	DepartmentName  string             `json:"departmentName"`
+	WeekStarts      []string           `json:"weekStarts"`      // 每列的周一日期
+	OverloadPercent float64            `json:"overloadPercent"` // 超负荷阈值（利用率百分比）
+	Employees       []EmployeeWorkload `json:"employees"`
+	OverloadedCount int                `json:"overloadedCount"` // 存在超负荷周的员工数
+}
+
+type WorkloadWeek struct {
+	WeekStart      string  `json:"weekStart"`      // 周一日期
+	AvailableHours float64 `json:"availableHours"` // 可用工时
+	AssignedHours  float64 `json:"assignedHours"`  // 已分配工作量
+	Utilization    float64 `json:"utilization"`    // 利用率（百分比）
+	Level          string  `json:"level"`          // idle/normal/high/over/leave
+}
+
 type AdminLoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -1166,6 +2126,23 @@ type PlatformStatsResponse struct {
 	TaskTrend           []TrendData            `json:"taskTrend"`
 }
 
+type StatsBackfillRequest struct {
+	StartDate string `json:"startDate"`          // 开始日期 YYYY-MM-DD
+	EndDate   string `json:"endDate"`            // 结束日期 YYYY-MM-DD
+	CompanyId string `json:"companyId,optional"` // 为空时回填全部公司和平台统计
+}
+
+type AdminStorageRequest struct {
+	CompanyID string `json:"companyId"`
+}
+
+type UpdateStorageQuotaRequest struct {
+	CompanyID      string `json:"companyId"`
+	CompanyQuotaMB int64  `json:"companyQuotaMb"` // 公司总配额（MB），-1 表示使用系统设置，0 表示不限制
+	UserQuotaMB    int64  `json:"userQuotaMb"`    // 每个员工的配额（MB），-1 表示使用系统设置，0 表示不限制
+	Reset          bool   `json:"reset,optional"` // 删除公司单独设置的配额，恢复系统设置
+}
+
 type AdminUserListRequest struct {
 	Page      int    `json:"page"`
 	PageSize  int    `json:"pageSize"`
@@ -1188,6 +2165,8 @@ type AdminCompanyInfo struct {
 	EmployeeCount     int64  `json:"employeeCount"`
 	DepartmentCount   int64  `json:"departmentCount"`
 	TaskCount         int64  `json:"taskCount"`
+	StorageUsedBytes  int64  `json:"storageUsedBytes"`  // 已使用的存储（字节）
+	StorageQuotaBytes int64  `json:"storageQuotaBytes"` // 公司存储配额（字节），0 表示不限制
 	CreateTime        string `json:"createTime"`
 	UpdateTime        string `json:"updateTime"`
 }
@@ -1209,3 +2188,36 @@ type SystemLogInfo struct {
 	StackTrace string `json:"stackTrace,optional"`
 	CreateTime string `json:"createTime"`
 }
+
+type SystemConfigListRequest struct {
+	Group string `json:"group,optional"`
+}
+
+type SystemConfigInfo struct {
+	ID           string `json:"id,optional"`
+	ConfigKey    string `json:"configKey"`
+	ConfigValue  string `json:"configValue"`
+	ConfigType   int    `json:"configType"` // 0-字符串 1-数字 2-布尔 3-JSON
+	ConfigGroup  string `json:"configGroup"`
+	Description  string `json:"description"`
+	IsSystem     int    `json:"isSystem"`
+	IsEncrypted  int    `json:"isEncrypted"`
+	Status       int    `json:"status"`
+	IsDefault    bool   `json:"isDefault"` // 内置配置项未在数据库中设置，使用默认值
+	DefaultValue string `json:"defaultValue,optional"`
+	UpdateTime   string `json:"updateTime,optional"`
+}
+
+type SystemConfigSaveRequest struct {
+	ConfigKey   string `json:"configKey"`
+	ConfigValue string `json:"configValue"`
+	ConfigType  int    `json:"configType,optional"`
+	ConfigGroup string `json:"configGroup,optional"`
+	Description string `json:"description,optional"`
+	IsEncrypted int    `json:"isEncrypted,optional"`
+	Status      *int   `json:"status,optional"` // 新增时默认 1，修改时未传沿用原值
+}
+
+type SystemConfigDeleteRequest struct {
+	ConfigKey string `json:"configKey"`
+}
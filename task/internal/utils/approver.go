@@ -7,12 +7,18 @@ import (
 	"task_Project/model/user"
 )
 
+// DelegateResolver 解析员工当前的外出代理人，未外出时返回 false
+type DelegateResolver func(ctx context.Context, employeeID string) (*user.Employee, bool)
+
 // ApproverFinder 审批人查找器
 type ApproverFinder struct {
 	EmployeeModel   user.EmployeeModel
 	DepartmentModel company.DepartmentModel
 	CompanyModel    company.CompanyModel
 	PositionModel   company.PositionModel
+
+	// ResolveDelegate 可选，设置后外出中的审批人会被替换为其代理人
+	ResolveDelegate DelegateResolver
 }
 
 // NewApproverFinder 创建审批人查找器
@@ -34,11 +40,48 @@ func NewApproverFinderWithPosition(employeeModel user.EmployeeModel, departmentM
 	}
 }
 
+// WithDelegateResolver 设置外出代理解析函数
+func (f *ApproverFinder) WithDelegateResolver(resolver DelegateResolver) *ApproverFinder {
+	f.ResolveDelegate = resolver
+	return f
+}
+
 // ApproverResult 审批人查找结果
 type ApproverResult struct {
 	ApproverID   string // 审批人员工ID
 	ApproverName string // 审批人姓名
 	ApproverType string // 审批人类型: supervisor/department_manager/position_superior/founder
+
+	// 审批人外出时由代理人审批，以下为原审批人信息
+	DelegatedFromID   string
+	DelegatedFromName string
+}
+
+// pick 检查候选审批人是否可用，外出中的候选人替换为其代理人
+// 代理人就是申请人本人时视为不可用，继续查找下一级审批人
+func (f *ApproverFinder) pick(ctx context.Context, candidate *user.Employee, approverType, employeeID string) *ApproverResult {
+	if f.ResolveDelegate != nil {
+		if delegate, ok := f.ResolveDelegate(ctx, candidate.Id); ok {
+			if delegate.Id == employeeID {
+				return nil
+			}
+			return &ApproverResult{
+				ApproverID:        delegate.Id,
+				ApproverName:      delegate.RealName,
+				ApproverType:      approverType,
+				DelegatedFromID:   candidate.Id,
+				DelegatedFromName: candidate.RealName,
+			}
+		}
+	}
+	if candidate.Status != 1 {
+		return nil
+	}
+	return &ApproverResult{
+		ApproverID:   candidate.Id,
+		ApproverName: candidate.RealName,
+		ApproverType: approverType,
+	}
 }
 
 // FindApprover 查找审批人（自动推断）
@@ -51,12 +94,10 @@ func (f *ApproverFinder) FindApprover(ctx context.Context, employeeID string) (*
 	// 1. 优先查找已设置的直属上级
 	if employee.SupervisorId.Valid && employee.SupervisorId.String != "" {
 		supervisor, err := f.EmployeeModel.FindOne(ctx, employee.SupervisorId.String)
-		if err == nil && supervisor.Status == 1 {
-			return &ApproverResult{
-				ApproverID:   supervisor.Id,
-				ApproverName: supervisor.RealName,
-				ApproverType: "supervisor",
-			}, nil
+		if err == nil {
+			if result := f.pick(ctx, supervisor, "supervisor", employeeID); result != nil {
+				return result, nil
+			}
 		}
 	}
 
@@ -66,12 +107,10 @@ func (f *ApproverFinder) FindApprover(ctx context.Context, employeeID string) (*
 		if err == nil && dept.ManagerId.Valid && dept.ManagerId.String != "" {
 			if dept.ManagerId.String != employeeID {
 				manager, err := f.EmployeeModel.FindOne(ctx, dept.ManagerId.String)
-				if err == nil && manager.Status == 1 {
-					return &ApproverResult{
-						ApproverID:   manager.Id,
-						ApproverName: manager.RealName,
-						ApproverType: "department_manager",
-					}, nil
+				if err == nil {
+					if result := f.pick(ctx, manager, "department_manager", employeeID); result != nil {
+						return result, nil
+					}
 				}
 			}
 		}
@@ -82,12 +121,10 @@ func (f *ApproverFinder) FindApprover(ctx context.Context, employeeID string) (*
 			if err == nil && parentDept.ManagerId.Valid && parentDept.ManagerId.String != "" {
 				if parentDept.ManagerId.String != employeeID {
 					parentManager, err := f.EmployeeModel.FindOne(ctx, parentDept.ManagerId.String)
-					if err == nil && parentManager.Status == 1 {
-						return &ApproverResult{
-							ApproverID:   parentManager.Id,
-							ApproverName: parentManager.RealName,
-							ApproverType: "parent_department_manager",
-						}, nil
+					if err == nil {
+						if result := f.pick(ctx, parentManager, "parent_department_manager", employeeID); result != nil {
+							return result, nil
+						}
 					}
 				}
 			}
@@ -97,13 +134,11 @@ func (f *ApproverFinder) FindApprover(ctx context.Context, employeeID string) (*
 	// 4. 查找公司创始人
 	companyInfo, err := f.CompanyModel.FindOne(ctx, employee.CompanyId)
 	if err == nil && companyInfo.Owner != "" {
-		founder, err := f.EmployeeModel.FindByUserID(ctx, companyInfo.Owner)
-		if err == nil && founder.Id != employeeID && founder.Status == 1 {
-			return &ApproverResult{
-				ApproverID:   founder.Id,
-				ApproverName: founder.RealName,
-				ApproverType: "founder",
-			}, nil
+		founder, err := f.EmployeeModel.FindByUserIDAndCompanyID(ctx, companyInfo.Owner, companyInfo.Id)
+		if err == nil && founder.Id != employeeID {
+			if result := f.pick(ctx, founder, "founder", employeeID); result != nil {
+				return result, nil
+			}
 		}
 	}
 
@@ -120,43 +155,73 @@ func (f *ApproverFinder) FindApproverForHandover(ctx context.Context, fromEmploy
 }
 
 // CanApprove 检查某人是否有权审批某员工的申请
+// 上级外出期间，其代理人同样有权审批（返回的角色为 delegate_of_<原角色>）
 func (f *ApproverFinder) CanApprove(ctx context.Context, approverID, employeeID string) (bool, string) {
 	employee, err := f.EmployeeModel.FindOne(ctx, employeeID)
 	if err != nil {
 		return false, ""
 	}
 
-	// 1. 检查是否是直属上级
-	if employee.SupervisorId.Valid && employee.SupervisorId.String == approverID {
-		return true, "supervisor"
+	chain := f.approverChain(ctx, employee)
+	for _, c := range chain {
+		if c.id == approverID {
+			return true, c.role
+		}
+	}
+
+	// 5. 检查是否是外出上级的代理人
+	if f.ResolveDelegate != nil && approverID != employeeID {
+		for _, c := range chain {
+			if delegate, ok := f.ResolveDelegate(ctx, c.id); ok && delegate.Id == approverID {
+				return true, "delegate_of_" + c.role
+			}
+		}
+	}
+
+	return false, ""
+}
+
+// approverRelation 审批链上的一个上级
+type approverRelation struct {
+	id   string
+	role string
+}
+
+// approverChain 按优先级列出员工的上级：直属上级 > 部门经理 > 上级部门经理 > 公司创始人
+func (f *ApproverFinder) approverChain(ctx context.Context, employee *user.Employee) []approverRelation {
+	var chain []approverRelation
+
+	// 1. 直属上级
+	if employee.SupervisorId.Valid && employee.SupervisorId.String != "" {
+		chain = append(chain, approverRelation{employee.SupervisorId.String, "supervisor"})
 	}
 
-	// 2. 检查是否是部门经理
+	// 2. 部门经理
 	if employee.DepartmentId.Valid && employee.DepartmentId.String != "" {
 		dept, err := f.DepartmentModel.FindOne(ctx, employee.DepartmentId.String)
-		if err == nil && dept.ManagerId.Valid && dept.ManagerId.String == approverID {
-			return true, "department_manager"
+		if err == nil && dept.ManagerId.Valid && dept.ManagerId.String != "" {
+			chain = append(chain, approverRelation{dept.ManagerId.String, "department_manager"})
 		}
 
-		// 3. 检查是否是上级部门的经理
+		// 3. 上级部门的经理
 		if dept != nil && dept.ParentId.Valid && dept.ParentId.String != "" {
 			parentDept, err := f.DepartmentModel.FindOne(ctx, dept.ParentId.String)
-			if err == nil && parentDept.ManagerId.Valid && parentDept.ManagerId.String == approverID {
-				return true, "parent_department_manager"
+			if err == nil && parentDept.ManagerId.Valid && parentDept.ManagerId.String != "" {
+				chain = append(chain, approverRelation{parentDept.ManagerId.String, "parent_department_manager"})
 			}
 		}
 	}
 
-	// 4. 检查是否是公司创始人
+	// 4. 公司创始人
 	companyInfo, err := f.CompanyModel.FindOne(ctx, employee.CompanyId)
 	if err == nil {
-		founder, err := f.EmployeeModel.FindByUserID(ctx, companyInfo.Owner)
-		if err == nil && founder.Id == approverID {
-			return true, "founder"
+		founder, err := f.EmployeeModel.FindByUserIDAndCompanyID(ctx, companyInfo.Owner, companyInfo.Id)
+		if err == nil {
+			chain = append(chain, approverRelation{founder.Id, "founder"})
 		}
 	}
 
-	return false, ""
+	return chain
 }
 
 // InferSupervisor 自动推断员工的直属上级
@@ -194,7 +259,7 @@ func (f *ApproverFinder) InferSupervisor(ctx context.Context, employeeID string)
 	// 2. 查找公司创始人
 	companyInfo, err := f.CompanyModel.FindOne(ctx, employee.CompanyId)
 	if err == nil && companyInfo.Owner != "" {
-		founder, err := f.EmployeeModel.FindByUserID(ctx, companyInfo.Owner)
+		founder, err := f.EmployeeModel.FindByUserIDAndCompanyID(ctx, companyInfo.Owner, companyInfo.Id)
 		if err == nil && founder.Id != employeeID && founder.Status == 1 {
 			return founder.Id, nil
 		}
@@ -139,6 +139,16 @@ func (c *common) DefaultString(str, defaultStr string) string {
 	return str
 }
 
+// ContainsID 判断逗号分隔的ID列表（如任务的负责人、节点的执行人）中是否包含指定ID
+func (c *common) ContainsID(idList, id string) bool {
+	for _, v := range strings.Split(idList, ",") {
+		if strings.TrimSpace(v) == id {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultInt 获取默认整数值
 func (c *common) DefaultInt(value, defaultValue int) int {
 	if value == 0 {
@@ -295,6 +295,8 @@ func (c *converter) getLogTypeString(logType int64) string {
 		return "交接"
 	case 4:
 		return "评论"
+	case 8:
+		return "状态变更"
 	default:
 		return "未知"
 	}
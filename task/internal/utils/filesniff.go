@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// 文件类型，按文件内容识别后与扩展名对应的类型比对
+const (
+	FileKindImage    = "image"
+	FileKindPDF      = "pdf"
+	FileKindMarkdown = "markdown"
+	FileKindWord     = "word"
+	FileKindExcel    = "excel"
+	FileKindPPT      = "ppt"
+	FileKindText     = "text"
+	FileKindVideo    = "video"
+	FileKindAudio    = "audio"
+	FileKindArchive  = "archive"
+	FileKindConfig   = "config"
+	FileKindCode     = "code"
+	FileKindOther    = "other"
+)
+
+// FileKinds 全部文件类型，用于公司上传策略的允许列表
+var FileKinds = []string{
+	FileKindImage, FileKindPDF, FileKindMarkdown, FileKindWord, FileKindExcel, FileKindPPT, FileKindText,
+	FileKindVideo, FileKindAudio, FileKindArchive, FileKindConfig, FileKindCode, FileKindOther,
+}
+
+var (
+	// ErrFileExecutable 可执行文件和脚本，任何公司都不允许上传
+	ErrFileExecutable = errors.New("不允许上传可执行文件")
+	// ErrFileTypeMismatch 文件内容与扩展名不符
+	ErrFileTypeMismatch = errors.New("文件内容与扩展名不符")
+)
+
+// fileSniffSize 识别文件内容时读取的头部字节数
+const fileSniffSize = 8192
+
+// extKinds 扩展名对应的文件类型
+var extKinds = map[string]string{
+	".jpg": FileKindImage, ".jpeg": FileKindImage, ".png": FileKindImage, ".gif": FileKindImage, ".bmp": FileKindImage,
+	".webp": FileKindImage, ".svg": FileKindImage, ".ico": FileKindImage,
+	".pdf": FileKindPDF,
+	".md":  FileKindMarkdown, ".markdown": FileKindMarkdown,
+	".doc": FileKindWord, ".docx": FileKindWord,
+	".xls": FileKindExcel, ".xlsx": FileKindExcel,
+	".ppt": FileKindPPT, ".pptx": FileKindPPT,
+	".txt": FileKindText, ".log": FileKindText, ".csv": FileKindText,
+	".mp4": FileKindVideo, ".avi": FileKindVideo, ".mov": FileKindVideo, ".wmv": FileKindVideo, ".flv": FileKindVideo, ".mkv": FileKindVideo,
+	".mp3": FileKindAudio, ".wav": FileKindAudio, ".flac": FileKindAudio, ".aac": FileKindAudio, ".ogg": FileKindAudio, ".m4a": FileKindAudio,
+	".zip": FileKindArchive, ".rar": FileKindArchive, ".7z": FileKindArchive, ".tar": FileKindArchive, ".gz": FileKindArchive,
+	".json": FileKindConfig, ".xml": FileKindConfig, ".yaml": FileKindConfig, ".yml": FileKindConfig,
+	".go": FileKindCode, ".py": FileKindCode, ".js": FileKindCode, ".ts": FileKindCode, ".java": FileKindCode, ".c": FileKindCode,
+	".cpp": FileKindCode, ".h": FileKindCode, ".css": FileKindCode, ".html": FileKindCode,
+}
+
+// 文件内容的格式族，由文件头识别
+const (
+	familyUnknown = ""
+	familyImage   = "image"
+	familyPDF     = "pdf"
+	familyOOXML   = "ooxml" // docx/xlsx/pptx，具体类型读取压缩包目录确定
+	familyOLE     = "ole"   // doc/xls/ppt 旧版 Office 复合文档
+	familyArchive = "archive"
+	familyMedia   = "media" // 音视频容器，同一种容器可能是音频也可能是视频
+	familyText    = "text"
+)
+
+// FileSniff 文件内容识别结果
+type FileSniff struct {
+	Kind string // 文件类型，见 FileKind*
+	MIME string // 按内容识别的 MIME 类型
+}
+
+// SniffFile 按文件头识别文件内容，并与扩展名对应的类型比对。
+// 可执行文件返回 ErrFileExecutable，内容与扩展名不符返回 ErrFileTypeMismatch；
+// 扩展名未知时按内容确定类型，无法识别的二进制文件为 FileKindOther
+func SniffFile(fileName string, r io.ReaderAt, size int64) (FileSniff, error) {
+	head := make([]byte, fileSniffSize)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return FileSniff{}, err
+	}
+	head = head[:n]
+
+	if isExecutable(head) {
+		return FileSniff{}, ErrFileExecutable
+	}
+
+	family, mime := sniffFamily(head)
+	ooxmlKind := ""
+	if family == familyOOXML {
+		ooxmlKind = ooxmlFileKind(r, size)
+		if ooxmlKind == FileKindArchive {
+			family, mime = familyArchive, "application/zip"
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	kind, known := extKinds[ext]
+	if !known {
+		return FileSniff{Kind: familyKind(family, ooxmlKind), MIME: mime}, nil
+	}
+
+	ok := false
+	switch kind {
+	case FileKindImage:
+		ok = family == familyImage || (ext == ".svg" && family == familyText)
+	case FileKindPDF:
+		ok = family == familyPDF
+	case FileKindWord, FileKindExcel, FileKindPPT:
+		if strings.HasSuffix(ext, "x") {
+			ok = family == familyOOXML && ooxmlKind == kind
+		} else {
+			ok = family == familyOLE
+		}
+	case FileKindArchive:
+		ok = family == familyArchive || family == familyOOXML
+	case FileKindVideo, FileKindAudio:
+		ok = family == familyMedia
+	default:
+		// markdown/text/config/code 均为文本
+		ok = family == familyText
+	}
+	if !ok {
+		return FileSniff{}, ErrFileTypeMismatch
+	}
+	return FileSniff{Kind: kind, MIME: mime}, nil
+}
+
+// isExecutable 可执行文件头：Windows PE、ELF、Mach-O 和带解释器声明的脚本
+func isExecutable(head []byte) bool {
+	switch {
+	case bytes.HasPrefix(head, []byte("MZ")),
+		bytes.HasPrefix(head, []byte("\x7fELF")),
+		bytes.HasPrefix(head, []byte{0xFE, 0xED, 0xFA, 0xCE}),
+		bytes.HasPrefix(head, []byte{0xFE, 0xED, 0xFA, 0xCF}),
+		bytes.HasPrefix(head, []byte{0xCE, 0xFA, 0xED, 0xFE}),
+		bytes.HasPrefix(head, []byte{0xCF, 0xFA, 0xED, 0xFE}),
+		bytes.HasPrefix(head, []byte("#!")):
+		return true
+	}
+	return false
+}
+
+// sniffFamily 按文件头识别格式族和 MIME 类型
+func sniffFamily(head []byte) (string, string) {
+	has := func(offset int, sig string) bool {
+		return len(head) >= offset+len(sig) && string(head[offset:offset+len(sig)]) == sig
+	}
+	switch {
+	case has(0, "\xFF\xD8\xFF"):
+		return familyImage, "image/jpeg"
+	case has(0, "\x89PNG\r\n\x1a\n"):
+		return familyImage, "image/png"
+	case has(0, "GIF87a"), has(0, "GIF89a"):
+		return familyImage, "image/gif"
+	case has(0, "BM") && has(6, "\x00\x00\x00\x00"):
+		return familyImage, "image/bmp"
+	case has(0, "RIFF") && has(8, "WEBP"):
+		return familyImage, "image/webp"
+	case has(0, "\x00\x00\x01\x00"):
+		return familyImage, "image/x-icon"
+	case has(0, "%PDF-"):
+		return familyPDF, "application/pdf"
+	case has(0, "PK\x03\x04"):
+		return familyOOXML, "application/zip"
+	case has(0, "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1"):
+		return familyOLE, "application/x-ole-storage"
+	case has(0, "Rar!\x1A\x07"):
+		return familyArchive, "application/vnd.rar"
+	case has(0, "7z\xBC\xAF\x27\x1C"):
+		return familyArchive, "application/x-7z-compressed"
+	case has(0, "\x1F\x8B"):
+		return familyArchive, "application/gzip"
+	case has(257, "ustar"):
+		return familyArchive, "application/x-tar"
+	case has(4, "ftyp"):
+		return familyMedia, "video/mp4"
+	case has(0, "RIFF") && has(8, "AVI "):
+		return familyMedia, "video/x-msvideo"
+	case has(0, "RIFF") && has(8, "WAVE"):
+		return familyMedia, "audio/wav"
+	case has(0, "\x1A\x45\xDF\xA3"):
+		return familyMedia, "video/x-matroska"
+	case has(0, "FLV"):
+		return familyMedia, "video/x-flv"
+	case has(0, "\x30\x26\xB2\x75\x8E\x66\xCF\x11"):
+		return familyMedia, "video/x-ms-asf"
+	case has(0, "ID3"), len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0 && head[1] < 0xFE:
+		return familyMedia, "audio/mpeg"
+	case has(0, "fLaC"):
+		return familyMedia, "audio/flac"
+	case has(0, "OggS"):
+		return familyMedia, "audio/ogg"
+	}
+	if isText(head) {
+		return familyText, http.DetectContentType(head)
+	}
+	return familyUnknown, "application/octet-stream"
+}
+
+// isText 文件头是否为文本：不含 NUL 和除制表、换行、换页、ESC 以外的控制字符。
+// 不要求 UTF-8，GBK 编码的 CSV 和日志同样视为文本
+func isText(head []byte) bool {
+	for _, b := range head {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1B {
+			return false
+		}
+	}
+	return true
+}
+
+// ooxmlFileKind 读取 zip 目录区分 docx/xlsx/pptx，普通压缩包返回 FileKindArchive
+func ooxmlFileKind(r io.ReaderAt, size int64) string {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return FileKindArchive
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return FileKindWord
+		case "xl/workbook.xml":
+			return FileKindExcel
+		case "ppt/presentation.xml":
+			return FileKindPPT
+		}
+	}
+	return FileKindArchive
+}
+
+// familyKind 扩展名未知时按内容确定文件类型
+func familyKind(family, ooxmlKind string) string {
+	switch family {
+	case familyImage:
+		return FileKindImage
+	case familyPDF:
+		return FileKindPDF
+	case familyOOXML:
+		return ooxmlKind
+	case familyArchive:
+		return FileKindArchive
+	case familyText:
+		return FileKindText
+	}
+	return FileKindOther
+}
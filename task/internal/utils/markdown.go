@@ -0,0 +1,400 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown 渲染只支持评论和任务详情中常用的语法：标题、段落、列表、引用、代码块、分隔线，
+// 以及强调、删除线、行内代码、链接、图片和自动链接。段落内的换行按换行显示。
+// 原文中的 HTML 标签原样输出，渲染结果必须再经过 SanitizeHTML 净化
+
+var (
+	mdHeadingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdRulePattern     = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdFencePattern    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	mdListPattern     = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])(?:[ \t]+(.*))?$`)
+	mdQuotePattern    = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	mdHTMLBlock       = regexp.MustCompile(`(?i)^ {0,3}</?(address|article|aside|blockquote|details|div|dl|figure|footer|h[1-6]|header|hr|li|main|nav|ol|p|pre|section|summary|table|tbody|td|tfoot|th|thead|tr|ul)(?:\s|/?>|$)`)
+	mdInlineTag       = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][A-Za-z0-9_.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+	mdAutoLinkAngle   = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	mdAutoLinkBare    = regexp.MustCompile(`^https?://[^\s<]+`)
+	mdEntityPattern   = regexp.MustCompile(`^&(?:[A-Za-z][A-Za-z0-9]{1,31}|#[0-9]{1,7}|#[xX][0-9A-Fa-f]{1,6});`)
+	mdLinkDestination = regexp.MustCompile(`^\(\s*(<[^<>\n]*>|[^\s()]*(?:\([^\s()]*\)[^\s()]*)*)(?:\s+("[^"]*"|'[^']*'))?\s*\)`)
+)
+
+// RenderMarkdown 把 Markdown 渲染为 HTML
+func RenderMarkdown(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	var b strings.Builder
+	renderMarkdownBlocks(&b, strings.Split(src, "\n"), false)
+	return strings.TrimSpace(b.String())
+}
+
+// renderMarkdownBlocks 渲染块级内容，tight 为紧凑列表项，段落不包 <p>
+func renderMarkdownBlocks(b *strings.Builder, lines []string, tight bool) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		text := renderMarkdownInline(strings.Join(paragraph, "\n"))
+		text = strings.ReplaceAll(text, "\n", "<br>")
+		if tight {
+			b.WriteString(text + "\n")
+		} else {
+			b.WriteString("<p>" + text + "</p>\n")
+		}
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			flush()
+			i++
+			continue
+		}
+
+		// 围栏代码块
+		if m := mdFencePattern.FindStringSubmatch(line); m != nil {
+			flush()
+			fence := m[1]
+			var code []string
+			i++
+			for i < len(lines) {
+				trimmed := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, lines[i])
+				i++
+			}
+			b.WriteString("<pre><code")
+			if m[2] != "" {
+				b.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		// 标题
+		if m := mdHeadingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ">" + renderMarkdownInline(m[2]) + "</h" + level + ">\n")
+			i++
+			continue
+		}
+
+		// 分隔线
+		if mdRulePattern.MatchString(line) {
+			flush()
+			b.WriteString("<hr>\n")
+			i++
+			continue
+		}
+
+		// 引用：连续的 > 行，以及紧跟其后的段落续行
+		if mdQuotePattern.MatchString(line) {
+			flush()
+			var quoted []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				if m := mdQuotePattern.FindStringSubmatch(lines[i]); m != nil {
+					quoted = append(quoted, m[1])
+				} else if len(quoted) > 0 && !startsMarkdownBlock(lines[i]) {
+					quoted = append(quoted, lines[i])
+				} else {
+					break
+				}
+				i++
+			}
+			b.WriteString("<blockquote>\n")
+			renderMarkdownBlocks(b, quoted, false)
+			b.WriteString("</blockquote>\n")
+			continue
+		}
+
+		// 列表
+		if mdListPattern.MatchString(line) {
+			flush()
+			i = renderMarkdownList(b, lines, i)
+			continue
+		}
+
+		// HTML 块原样输出，直到空行
+		if len(paragraph) == 0 && mdHTMLBlock.MatchString(line) {
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				b.WriteString(lines[i] + "\n")
+				i++
+			}
+			continue
+		}
+
+		if len(paragraph) > 0 && startsMarkdownBlock(line) {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+		i++
+	}
+	flush()
+}
+
+// startsMarkdownBlock 该行是否开始一个新的块，用于结束段落
+func startsMarkdownBlock(line string) bool {
+	return mdFencePattern.MatchString(line) || mdHeadingPattern.MatchString(line) || mdRulePattern.MatchString(line) ||
+		mdQuotePattern.MatchString(line) || mdListPattern.MatchString(line) || mdHTMLBlock.MatchString(line)
+}
+
+// renderMarkdownList 渲染从 start 行开始的列表，返回列表之后的行号
+func renderMarkdownList(b *strings.Builder, lines []string, start int) int {
+	first := mdListPattern.FindStringSubmatch(lines[start])
+	ordered := first[3] != ""
+	marker := first[2][len(first[2])-1:]
+
+	type listItem struct {
+		lines []string
+	}
+	var items []*listItem
+	var current *listItem
+	tight := true
+	blank := false
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			i++
+			continue
+		}
+		// 同级列表项：缩进不超过第一项，符号类型相同；缩进更深的列表项属于当前项的子列表
+		if m := mdListPattern.FindStringSubmatch(line); m != nil && len(m[1]) <= len(first[1])+1 && (m[3] != "") == ordered && strings.HasSuffix(m[2], marker) {
+			if blank && current != nil {
+				tight = false
+			}
+			current = &listItem{lines: []string{m[4]}}
+			items = append(items, current)
+			blank = false
+			i++
+			continue
+		}
+		// 缩进的行属于当前列表项；未缩进的行在没有空行分隔时作为段落续行
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if current == nil || (indent < 2 && (blank || startsMarkdownBlock(line))) {
+			break
+		}
+		if blank {
+			current.lines = append(current.lines, "")
+			tight = false
+		}
+		if indent > 4 {
+			indent = 4
+		}
+		current.lines = append(current.lines, line[indent:])
+		blank = false
+		i++
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if first[3] != "1" {
+			b.WriteString(`<ol start="` + strings.TrimLeft(first[3], "0") + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		var inner strings.Builder
+		renderMarkdownBlocks(&inner, item.lines, tight)
+		b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderMarkdownInline 渲染行内语法，普通文本转义输出
+func renderMarkdownInline(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		rest := src[i:]
+		switch {
+		case c == '\\' && i+1 < len(src) && strings.IndexByte("\\`*_{}[]()#+-.!~<>|\"'&", src[i+1]) >= 0:
+			b.WriteString(html.EscapeString(src[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			run := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:run]
+			if end := strings.Index(rest[run:], fence); end >= 0 {
+				code := rest[run : run+end]
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += run + end + run
+				continue
+			}
+			b.WriteString(fence)
+			i += run
+			continue
+
+		case c == '<':
+			if m := mdAutoLinkAngle.FindStringSubmatch(rest); m != nil {
+				url := html.EscapeString(m[1])
+				b.WriteString(`<a href="` + url + `">` + url + "</a>")
+				i += len(m[0])
+				continue
+			}
+			if m := mdInlineTag.FindString(rest); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+
+		case c == '&':
+			if m := mdEntityPattern.FindString(rest); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if text, url, title, n, ok := parseMarkdownLink(rest[1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(url) + `" alt="` + html.EscapeString(text) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">")
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if text, url, title, n, ok := parseMarkdownLink(rest); ok {
+				b.WriteString(`<a href="` + html.EscapeString(url) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">" + renderMarkdownInline(text) + "</a>")
+				i += n
+				continue
+			}
+
+		case c == '~' && strings.HasPrefix(rest, "~~"):
+			if inner, n, ok := findMarkdownDelimited(rest, "~~"); ok {
+				b.WriteString("<del>" + renderMarkdownInline(inner) + "</del>")
+				i += n
+				continue
+			}
+
+		case c == '*' || c == '_':
+			// 下划线在单词内部不作为强调，避免 snake_case 被误解析
+			if c == '_' && i > 0 && isMarkdownWordByte(src[i-1]) {
+				break
+			}
+			for _, delim := range []string{strings.Repeat(string(c), 2), string(c)} {
+				if !strings.HasPrefix(rest, delim) {
+					continue
+				}
+				if inner, n, ok := findMarkdownDelimited(rest, delim); ok {
+					if c == '_' && i+n < len(src) && isMarkdownWordByte(src[i+n]) {
+						continue
+					}
+					tag := "em"
+					if len(delim) == 2 {
+						tag = "strong"
+					}
+					b.WriteString("<" + tag + ">" + renderMarkdownInline(inner) + "</" + tag + ">")
+					i += n
+					goto next
+				}
+			}
+
+		case c == 'h' && (i == 0 || !isMarkdownWordByte(src[i-1])):
+			if m := mdAutoLinkBare.FindString(rest); m != "" {
+				m = strings.TrimRight(m, ".,;:!?'\")，。；：！？）")
+				url := html.EscapeString(m)
+				b.WriteString(`<a href="` + url + `">` + url + "</a>")
+				i += len(m)
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(src[i : i+1]))
+		i++
+	next:
+	}
+	return b.String()
+}
+
+// parseMarkdownLink 解析 [text](url "title")，返回消耗的字节数
+func parseMarkdownLink(src string) (text, url, title string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				m := mdLinkDestination.FindStringSubmatch(src[i+1:])
+				if m == nil {
+					return "", "", "", 0, false
+				}
+				url = strings.TrimSuffix(strings.TrimPrefix(m[1], "<"), ">")
+				if len(m[2]) >= 2 {
+					title = m[2][1 : len(m[2])-1]
+				}
+				return src[1:i], url, title, i + 1 + len(m[0]), true
+			}
+		case '\n':
+			if depth == 0 {
+				return "", "", "", 0, false
+			}
+		}
+	}
+	return "", "", "", 0, false
+}
+
+// findMarkdownDelimited 查找成对的强调符号，开头后和结尾前不能是空白
+func findMarkdownDelimited(src, delim string) (string, int, bool) {
+	body := src[len(delim):]
+	if body == "" || body[0] == ' ' || body[0] == '\n' || strings.HasPrefix(body, delim[:1]) && len(delim) == 1 {
+		return "", 0, false
+	}
+	for from := 0; from < len(body); {
+		end := strings.Index(body[from:], delim)
+		if end < 0 {
+			return "", 0, false
+		}
+		end += from
+		if end > 0 && body[end-1] != ' ' && body[end-1] != '\n' && body[end-1] != '\\' {
+			// 单个符号时跳过连续的双符号，例如 *a **b** c*
+			if len(delim) == 1 && strings.HasPrefix(body[end:], delim+delim) {
+				from = end + 2
+				continue
+			}
+			return body[:end], len(delim) + end + len(delim), true
+		}
+		from = end + len(delim)
+	}
+	return "", 0, false
+}
+
+func isMarkdownWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// markdownXSSPayloads 只有 Markdown 语法才能构造的注入写法
+var markdownXSSPayloads = []string{
+	`[x](javascript:alert(1))`,
+	`[x](JAVASCRIPT:alert(1))`,
+	`[x](<javascript:alert(1)>)`,
+	`[x](java&#x09;script:alert(1))`,
+	`[x](&#106;avascript:alert(1))`,
+	`[x](vbscript:msgbox(1))`,
+	`[x](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)`,
+	`![x](javascript:alert(1))`,
+	`![x](data:image/svg+xml;base64,PHN2ZyBvbmxvYWQ9YWxlcnQoMSk+)`,
+	`[x](https://example.com "a\" onmouseover=\"alert(1)")`,
+	`[x](https://example.com 'a" onmouseover="alert(1)')`,
+	`![a" onerror="alert(1)](https://example.com/a.png)`,
+	`[<img src=x onerror=alert(1)>](https://example.com)`,
+	`[x](https://example.com/"onmouseover="alert(1))`,
+	`<javascript:alert(1)>`,
+	`https://example.com/"><script>alert(1)</script>`,
+	"```\n<script>alert(1)</script>\n```",
+	"`<script>alert(1)</script>`",
+	"# <script>alert(1)</script>",
+	"> <img src=x onerror=alert(1)>",
+	"- <a href=\"javascript:alert(1)\">x</a>",
+	"<div>\n<script>alert(1)</script>\n</div>",
+	"<p onclick=alert(1)>x</p>",
+	"**<svg onload=alert(1)>**",
+	"\\<script>alert(1)\\</script>",
+	"&lt;script&gt;alert(1)&lt;/script&gt;",
+	"<scr<script>ipt>alert(1)</script>",
+}
+
+func TestRenderRichTextMarkdownStripsXSS(t *testing.T) {
+	for _, input := range append(markdownXSSPayloads, xssPayloads...) {
+		rt := RenderRichText(ContentFormatMarkdown, input, nil, nil)
+		assertSafeHTML(t, input, rt.HTML)
+		if strings.Contains(strings.ToLower(rt.HTML), "<script") {
+			t.Errorf("input %q: output %q contains a script tag", input, rt.HTML)
+		}
+	}
+}
+
+func TestRenderMarkdownEscapesText(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{"`<b>`", `<p><code>&lt;b&gt;</code></p>`},
+		{"```html\n<script>alert(1)</script>\n```", `<pre><code class="language-html">&lt;script&gt;alert(1)&lt;/script&gt;</code></pre>`},
+		{`\<b>x`, `<p>&lt;b&gt;x</p>`},
+		{`a & b "c"`, `<p>a &amp; b &#34;c&#34;</p>`},
+		{`[x](https://e.com/?a=1&b="2")`, `<p><a href="https://e.com/?a=1&amp;b=&#34;2&#34;">x</a></p>`},
+		{`[x](https://e.com 't"x')`, `<p><a href="https://e.com" title="t&#34;x">x</a></p>`},
+		{`![a"b](https://e.com/a.png)`, `<p><img src="https://e.com/a.png" alt="a&#34;b"></p>`},
+		{`https://e.com/a"b`, `<p><a href="https://e.com/a&#34;b">https://e.com/a&#34;b</a></p>`},
+	}
+	for _, c := range cases {
+		if got := RenderMarkdown(c.input); got != c.want {
+			t.Errorf("RenderMarkdown(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestRenderRichTextMarkdownLinks(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{`[x](javascript:alert(1))`, `<p><a rel="noopener noreferrer nofollow">x</a></p>`},
+		{`![x](javascript:alert(1))`, `<p><img alt="x"></p>`},
+		{`[x](https://example.com)`, `<p><a href="https://example.com" rel="noopener noreferrer nofollow">x</a></p>`},
+		{`[x](/tasks/1)`, `<p><a href="/tasks/1" rel="noopener noreferrer nofollow">x</a></p>`},
+		{`<img src=x onerror=alert(1)>`, `<p><img src="x"></p>`},
+		{"<div>\n<script>alert(1)</script>\n</div>", "<div>\n\n</div>"},
+	}
+	for _, c := range cases {
+		if got := RenderRichText(ContentFormatMarkdown, c.input, nil, nil).HTML; got != c.want {
+			t.Errorf("RenderRichText(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestPlainTextDropsScripts(t *testing.T) {
+	got := PlainText("# 标题\n\n正文<script>alert(1)</script> **加粗** [链接](javascript:alert(1))")
+	if want := "标题\n正文 加粗 链接"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -44,6 +44,7 @@ var SuccessMessages = map[string]string{
 	"delete":    "删除成功",
 	"query":     "查询成功",
 	"operation": "操作成功",
+	"switch":    "切换成功",
 }
 
 // 业务错误消息枚举
@@ -51,7 +52,7 @@ var BusinessErrorMessages = map[string]string{
 	// 用户相关错误
 	"user_not_found":          "用户不存在",
 	"user_already_exists":     "用户已存在",
-	"user_already_in_company": "用户已加入其他公司，无法创建新公司",
+	"user_already_in_company": "用户已经是该公司的成员",
 	"username_exists":         "用户名已存在",
 	"email_exists":            "邮箱已被注册",
 	"email_not_found":         "该邮箱未注册",
@@ -95,6 +96,96 @@ var BusinessErrorMessages = map[string]string{
 	"role_name_exists":   "角色名称已存在",
 	"role_name_required": "角色名称不能为空",
 
+	// 授权相关错误
+	"permission_not_found":      "授权记录不存在",
+	"permission_grant_denied":   "只能授予、委派或撤销自己拥有的权限",
+	"permission_cross_company":  "不能跨公司授权或撤销授权",
+	"permission_expire_invalid": "过期时间格式错误或早于当前时间",
+	"permission_codes_required": "权限码不能为空",
+	"delegate_self_not_allowed": "不能将权限委派给自己",
+	"delegate_no_permissions":   "被代理员工没有可委派的权限",
+	"delegate_expire_required":  "临时委派必须设置截止时间",
+
+	// 外出代理相关错误
+	"out_of_office_not_found":         "外出代理记录不存在",
+	"out_of_office_time_invalid":      "时间格式错误，或结束时间早于开始时间/当前时间",
+	"out_of_office_overlap":           "该时间段内已有外出代理安排",
+	"out_of_office_delegate_self":     "不能将自己设为代理人",
+	"out_of_office_delegate_invalid":  "代理人不存在、已离职或不在同一公司",
+	"out_of_office_permission_denied": "只能为自己或下属设置外出代理",
+	"out_of_office_finished":          "该外出代理已结束或已取消",
+
+	// 审计日志相关
+	"only_admin_can_view_audit": "只有公司创始人、人事部门或管理人员可以查看审计日志",
+	"audit_time_invalid":        "时间格式错误或结束时间早于开始时间",
+
+	// 工时记录相关
+	"time_timer_running":        "已有正在进行的计时，请先停止",
+	"time_timer_not_running":    "当前没有正在进行的计时",
+	"time_timer_week_locked":    "计时所在周的工时单已提交，本次计时未记录",
+	"time_entry_not_found":      "工时记录不存在",
+	"time_entry_locked":         "该周工时单已提交，无法修改工时记录",
+	"time_entry_no_permission":  "无权查看或修改该员工的工时",
+	"time_node_not_member":      "只有节点执行人或负责人可以记录工时",
+	"time_checklist_invalid":    "任务清单不属于该节点",
+	"time_date_invalid":         "日期格式错误（应为 2006-01-02）或结束日期早于开始日期",
+	"time_date_future":          "不能填报未来日期的工时",
+	"time_duration_invalid":     "工时时长须在 1 到 1440 分钟之间",
+	"timesheet_not_found":       "工时单不存在",
+	"timesheet_empty":           "该周没有可提交的工时记录",
+	"timesheet_submitted":       "该周工时单已提交",
+	"timesheet_timer_running":   "该周还有正在进行的计时，请先停止计时再提交",
+	"timesheet_processed":       "该工时单已处理",
+	"timesheet_no_permission":   "您无权审批该工时单",
+	"time_report_no_permission": "只有公司创始人、人事部门、管理人员或部门经理可以查看工时报表",
+
+	// 产能与工作负载相关
+	"workload_no_permission": "只有公司创始人、人事部门、管理人员或部门经理可以查看或设置他人的工作负载",
+	"capacity_hours_invalid": "每周可用工时须在 0 到 80 小时之间",
+	"no_capacity_candidates": "没有承接后不超负荷的候选员工",
+	"workload_weeks_invalid": "统计周数须在 1 到 12 之间",
+
+	// 报表导出相关
+	"export_type_invalid":        "不支持的导出内容",
+	"export_format_invalid":      "导出格式只支持 csv、xlsx、pdf",
+	"export_task_required":       "导出任务节点或任务报告时必须指定任务",
+	"export_scope_invalid":       "导出范围无效",
+	"export_no_permission":       "您无权导出该数据",
+	"export_not_found":           "导出记录不存在",
+	"export_not_finished":        "导出任务正在生成中，请稍后再试",
+	"import_type_invalid":        "不支持的导入内容",
+	"import_no_permission":       "您无权导入组织架构数据",
+	"import_not_found":           "导入记录不存在",
+	"import_has_errors":          "导入数据存在错误，请查看校验报告",
+	"import_running":             "导入任务正在执行",
+	"import_finished":            "导入任务已完成",
+	"task_search_cursor_invalid": "分页游标无效，请从第一页重新查询",
+	"saved_view_not_found":       "视图不存在",
+	"saved_view_denied":          "无权修改该视图",
+	"saved_view_limit":           "最多只能保存 50 个视图",
+	"search_unavailable":         "搜索服务暂不可用",
+	"search_rebuild_running":     "搜索索引正在重建，请稍后再试",
+
+	// 任务标签和自定义字段
+	"label_not_found":            "标签不存在",
+	"label_name_exists":          "标签名称已存在",
+	"label_limit":                "标签数量已达上限（100 个）",
+	"label_no_permission":        "只有管理人员可以维护标签",
+	"task_label_limit":           "每个任务最多设置 10 个标签",
+	"custom_field_not_found":     "自定义字段不存在",
+	"custom_field_name_exists":   "字段名称已存在",
+	"custom_field_limit":         "自定义字段数量已达上限（50 个）",
+	"custom_field_no_permission": "只有管理人员可以维护自定义字段",
+	"custom_field_value_denied":  "无权填写该对象的自定义字段",
+
+	// 看板
+	"board_not_found":          "看板不存在",
+	"board_exists":             "该任务或部门已有看板",
+	"board_no_permission":      "无权查看或维护该看板",
+	"board_column_not_found":   "看板列不存在",
+	"board_node_not_found":     "节点不在该看板中",
+	"board_wip_limit_exceeded": "目标列已达到在制品上限",
+
 	// 通用错误
 	"invalid_params":          "参数无效",
 	"missing_required_fields": "缺少必填字段",
@@ -174,7 +265,7 @@ var BusinessErrorMessages = map[string]string{
 	"admin_disabled":             "管理员账号已禁用",
 
 	// 申请相关错误
-	"already_in_company":       "您已经加入了公司，无法再次申请",
+	"already_in_company":       "您已经是该公司的成员，无法再次申请",
 	"pending_application":      "您已有待审批的申请，请等待审批结果",
 	"invite_company_not_found": "邀请码对应的公司不存在",
 	"company_disabled":         "该公司已停用，无法申请加入",
@@ -182,6 +273,11 @@ var BusinessErrorMessages = map[string]string{
 	"application_processed":    "该申请已处理",
 	"no_permission_approve":    "您无权审批此申请",
 
+	// 公司切换相关错误
+	"membership_not_found":        "您不是该公司的成员",
+	"membership_left":             "您已从该公司离职，无法切换",
+	"membership_company_disabled": "该公司已被禁用，无法切换",
+
 	// 审批相关错误
 	"approval_id_required":       "审批ID不能为空",
 	"approval_result_invalid":    "审批结果无效，1-同意，2-拒绝",
@@ -191,6 +287,49 @@ var BusinessErrorMessages = map[string]string{
 	"approval_permission_denied": "无权限审批，只有项目负责人可以审批",
 	"approval_missing_node_id":   "审批记录缺少任务节点ID",
 
+	// 离职交接计划相关错误
+	"offboarding_plan_not_found":   "离职交接计划不存在，请先生成",
+	"offboarding_plan_applied":     "离职交接计划已执行",
+	"offboarding_plan_required":    "离职员工还有未交接的工作，请先生成并执行离职交接计划",
+	"offboarding_items_unassigned": "离职交接计划中还有事项未指定接收人",
+	"offboarding_plan_outdated":    "离职员工名下出现了计划外的新事项，请重新生成交接计划",
+	"offboarding_no_permission":    "只有离职审批人或公司创始人、人事部门、管理人员可以处理离职交接",
+	"offboarding_assignee_invalid": "接收人必须是本公司在职员工，且不能是离职员工本人",
+	"offboarding_leave_closed":     "离职审批已处理，无法修改交接计划",
+
+	// 任务评论相关错误
+	"comment_not_found":        "评论不存在或已删除",
+	"comment_no_permission":    "只有评论作者和任务负责人可以编辑或删除评论",
+	"comment_edit_conflict":    "评论已被修改，请刷新后重试",
+	"comment_reaction_invalid": "不支持的表情",
+
+	// 任务关注相关错误
+	"watch_node_mismatch": "节点不属于该任务",
+
+	// 附件版本相关错误
+	"file_not_found":             "附件不存在",
+	"file_version_not_found":     "文件版本不存在或已被清理",
+	"file_version_conflict":      "附件已被其他人更新，请刷新后重试",
+	"file_version_no_permission": "只有附件上传者和任务参与人可以管理附件版本",
+	"file_version_unsupported":   "头像不支持版本管理",
+
+	// 上传策略相关错误
+	"upload_policy_no_permission": "只有公司创始人、人事部门或管理人员可以修改上传策略",
+	"upload_type_invalid":         "不支持的文件类型",
+
+	// 大文件上传相关错误
+	"upload_session_not_found": "上传会话不存在或已过期",
+	"upload_session_closed":    "上传会话已结束",
+	"upload_mode_unsupported":  "当前文件存储不支持该上传方式",
+	"upload_part_invalid":      "分片序号或大小不正确",
+	"upload_part_checksum":     "分片校验失败，请重新上传该分片",
+	"upload_incomplete":        "文件尚未上传完成，请上传缺少的分片",
+	"upload_checksum_mismatch": "文件校验失败，请重新上传",
+
+	// 存储配额相关错误
+	"storage_quota_exceeded":      "公司存储空间不足，请清理附件或联系管理员调整配额",
+	"storage_user_quota_exceeded": "您的存储空间不足，请清理附件或联系管理员调整配额",
+
 	// 兼容旧的英文key
 	"The task deadline cannot be empty":                         "任务截止时间不能为空",
 	"Task deadline format is incorrect":                         "任务截止时间格式错误",
@@ -0,0 +1,375 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// 富文本内容格式
+const (
+	ContentFormatMarkdown = "markdown" // Markdown 原文，服务端渲染为 HTML
+	ContentFormatHTML     = "html"     // 富文本编辑器提交的 HTML，只做净化
+)
+
+// 内容中的引用类型
+const (
+	ContentRefMention    = "mention"    // @员工
+	ContentRefDepartment = "department" // @部门，通知部门全体员工
+	ContentRefRole       = "role"       // @角色，通知拥有该角色的全体员工
+	ContentRefTask       = "task"       // #任务
+	ContentRefNode       = "node"       // #任务节点
+)
+
+// ContentRef 内容中解析出的@提及或任务、节点引用
+type ContentRef struct {
+	Kind  string // 引用类型
+	ID    string // 员工、部门、角色、任务或节点ID
+	Label string // 显示文本，不含 @ 和 #
+	Href  string // 链接地址
+}
+
+// ContentResolver 解析内容中 @ 或 # 之后的标识，无法解析时返回 nil，原文保持不变
+type ContentResolver interface {
+	ResolveRef(sigil byte, token string) *ContentRef
+}
+
+// RichText 经过渲染和净化的富文本
+type RichText struct {
+	HTML string       // 净化后的 HTML，引用已替换为链接
+	Text string       // 纯文本，用于搜索和通知
+	Refs []ContentRef // 内容中的引用，按出现顺序去重
+}
+
+// sanitizeAllowedTags 允许的标签及其允许的属性
+var sanitizeAllowedTags = map[string][]string{
+	"a": {"href", "title"}, "img": {"src", "alt", "title", "width", "height"},
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": {"class"},
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "strike": nil,
+	"sup": nil, "sub": nil, "mark": nil, "code": {"class"}, "pre": {"class"}, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": {"colspan", "rowspan", "align"}, "td": {"colspan", "rowspan", "align"},
+}
+
+// sanitizeDroppedTags 连同内容一起丢弃的标签
+var sanitizeDroppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true, "object": true, "embed": true,
+	"applet": true, "noscript": true, "template": true, "textarea": true, "select": true, "svg": true, "math": true,
+	"head": true, "title": true,
+}
+
+var sanitizeVoidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// plainTextBlockTags 提取纯文本时需要换行的标签
+var plainTextBlockTags = map[string]bool{
+	"p": true, "br": true, "hr": true, "div": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true, "ol": true, "table": true,
+}
+
+var (
+	sanitizeClassPattern  = regexp.MustCompile(`^language-[A-Za-z0-9_+#-]{1,32}$`)
+	sanitizeNumberPattern = regexp.MustCompile(`^[0-9]{1,4}$`)
+	sanitizeURLSpace      = regexp.MustCompile(`[\x00-\x20\x7f]+`)
+	contentRefToken       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{2,63}`)
+	plainTextBlankLines   = regexp.MustCompile(`\n{3,}`)
+)
+
+// contentRefMarkup 各类引用生成链接时使用的 class、data 属性和前缀
+var contentRefMarkup = map[string][3]string{
+	ContentRefMention:    {"mention", "data-employee-id", "@"},
+	ContentRefDepartment: {"mention mention-group", "data-department-id", "@"},
+	ContentRefRole:       {"mention mention-group", "data-role-id", "@"},
+	ContentRefTask:       {"task-ref", "data-task-id", "#"},
+	ContentRefNode:       {"node-ref", "data-node-id", "#"},
+}
+
+// RenderRichText 渲染富文本：Markdown 先渲染为 HTML，再按白名单净化，最后把@提及和任务、节点引用替换为链接。
+// mentionNames 为姓名到员工ID的映射，用于识别 @姓名；resolver 为空时不解析引用
+func RenderRichText(format, source string, mentionNames map[string]string, resolver ContentResolver) RichText {
+	rendered := source
+	if format != ContentFormatHTML {
+		rendered = RenderMarkdown(source)
+	}
+	sanitized := SanitizeHTML(rendered)
+
+	var refs []ContentRef
+	if resolver != nil {
+		sanitized, refs = LinkContentRefs(sanitized, mentionNames, resolver)
+	}
+	return RichText{HTML: sanitized, Text: HTMLToText(sanitized), Refs: refs}
+}
+
+// PlainText 提取 Markdown 原文的纯文本，用于搜索索引等不需要解析引用的场景
+func PlainText(markdown string) string {
+	return HTMLToText(SanitizeHTML(RenderMarkdown(markdown)))
+}
+
+// SanitizeHTML 按白名单净化 HTML：不在白名单中的标签去掉标签保留文本，脚本、样式等标签连同内容删除，
+// 只保留白名单属性，链接只允许 http、https、mailto 和相对地址，并补齐未闭合的标签
+func SanitizeHTML(src string) string {
+	var b strings.Builder
+	var stack []string
+	skipDepth := 0
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		switch tt {
+		case xhtml.TextToken:
+			if skipDepth == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			tok := z.Token()
+			name := tok.Data
+			if sanitizeDroppedTags[name] {
+				if tt == xhtml.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			allowed, ok := sanitizeAllowedTags[name]
+			if !ok {
+				continue
+			}
+			b.WriteString("<" + name)
+			for _, attr := range tok.Attr {
+				if value, ok := sanitizeAttr(attr.Key, attr.Val, allowed); ok {
+					b.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+				}
+			}
+			if name == "a" {
+				b.WriteString(` rel="noopener noreferrer nofollow"`)
+			}
+			b.WriteString(">")
+			if !sanitizeVoidTags[name] && tt == xhtml.StartTagToken {
+				stack = append(stack, name)
+			}
+
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if sanitizeDroppedTags[tag] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth > 0 || sanitizeVoidTags[tag] {
+				continue
+			}
+			// 关闭到最近的同名标签，中间未闭合的标签一并关闭；没有对应开始标签的结束标签忽略
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != tag {
+					continue
+				}
+				for j := len(stack) - 1; j >= i; j-- {
+					b.WriteString("</" + stack[j] + ">")
+				}
+				stack = stack[:i]
+				break
+			}
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteString("</" + stack[i] + ">")
+	}
+	return b.String()
+}
+
+// sanitizeAttr 校验单个属性，返回净化后的值
+func sanitizeAttr(key, value string, allowed []string) (string, bool) {
+	permitted := false
+	for _, a := range allowed {
+		if a == key {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return "", false
+	}
+	switch key {
+	case "href", "src":
+		return value, isSafeContentURL(value)
+	case "class":
+		var classes []string
+		for _, c := range strings.Fields(value) {
+			if sanitizeClassPattern.MatchString(c) {
+				classes = append(classes, c)
+			}
+		}
+		return strings.Join(classes, " "), len(classes) > 0
+	case "width", "height", "colspan", "rowspan", "start":
+		return value, sanitizeNumberPattern.MatchString(value)
+	case "align":
+		value = strings.ToLower(value)
+		return value, value == "left" || value == "center" || value == "right"
+	}
+	return value, true
+}
+
+// isSafeContentURL 只允许 http、https、mailto 和不带协议的相对地址
+func isSafeContentURL(raw string) bool {
+	u := strings.ToLower(sanitizeURLSpace.ReplaceAllString(html.UnescapeString(raw), ""))
+	if u == "" {
+		return false
+	}
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "mailto:") {
+		return true
+	}
+	// 相对地址：第一个 / ? # 之前不能出现冒号
+	end := strings.IndexAny(u, "/?#")
+	if end < 0 {
+		end = len(u)
+	}
+	return !strings.Contains(u[:end], ":")
+}
+
+// HTMLToText 提取 HTML 的纯文本，块级标签换行，图片保留替代文本
+func HTMLToText(src string) string {
+	var b strings.Builder
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		switch tt {
+		case xhtml.TextToken:
+			// 标签之间的换行缩进不是内容
+			if text := z.Text(); strings.TrimSpace(string(text)) != "" || !strings.Contains(string(text), "\n") {
+				b.Write(text)
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken, xhtml.EndTagToken:
+			tok := z.Token()
+			if tok.Data == "img" {
+				for _, attr := range tok.Attr {
+					if attr.Key == "alt" && attr.Val != "" {
+						b.WriteString(attr.Val)
+					}
+				}
+			}
+			// 块级标签在结束时换行，br、hr 没有结束标签
+			if tt == xhtml.EndTagToken && plainTextBlockTags[tok.Data] || tok.Data == "br" || tok.Data == "hr" {
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text := plainTextBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// LinkContentRefs 把净化后 HTML 文本中的 @姓名、@员工ID、@部门ID、@角色ID 和 #任务ID、#节点ID 替换为链接，
+// 链接、代码和代码块中的文本不处理
+func LinkContentRefs(src string, mentionNames map[string]string, resolver ContentResolver) (string, []ContentRef) {
+	// 姓名按长度降序匹配，避免"张三"抢先匹配"张三丰"
+	names := make([]string, 0, len(mentionNames))
+	for name := range mentionNames {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	var b strings.Builder
+	var refs []ContentRef
+	seen := make(map[string]bool)
+	skipDepth := 0
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+		switch tt {
+		case xhtml.StartTagToken:
+			if name, _ := z.TagName(); isContentRefOpaque(string(name)) {
+				skipDepth++
+			}
+			b.WriteString(raw)
+			continue
+		case xhtml.EndTagToken:
+			if name, _ := z.TagName(); isContentRefOpaque(string(name)) && skipDepth > 0 {
+				skipDepth--
+			}
+			b.WriteString(raw)
+			continue
+		case xhtml.TextToken:
+			if skipDepth == 0 {
+				break
+			}
+			fallthrough
+		default:
+			b.WriteString(raw)
+			continue
+		}
+
+		text := string(z.Text())
+		last := 0
+		for i := 0; i < len(text); i++ {
+			sigil := text[i]
+			if sigil != '@' && sigil != '#' || i > 0 && isMarkdownWordByte(text[i-1]) && text[i-1] < 0x80 {
+				continue
+			}
+			rest := text[i+1:]
+			var ref *ContentRef
+			length := 0
+			if sigil == '@' {
+				for _, name := range names {
+					if strings.HasPrefix(rest, name) {
+						ref = resolver.ResolveRef(sigil, mentionNames[name])
+						length = len(name)
+						break
+					}
+				}
+			}
+			if ref == nil {
+				if token := contentRefToken.FindString(rest); token != "" {
+					ref = resolver.ResolveRef(sigil, token)
+					length = len(token)
+				}
+			}
+			if ref == nil {
+				continue
+			}
+
+			b.WriteString(html.EscapeString(text[last:i]))
+			markup := contentRefMarkup[ref.Kind]
+			b.WriteString(`<a class="` + markup[0] + `" ` + markup[1] + `="` + html.EscapeString(ref.ID) + `"`)
+			if ref.Href != "" {
+				b.WriteString(` href="` + html.EscapeString(ref.Href) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(markup[2]+ref.Label) + "</a>")
+			i += length
+			last = i + 1
+			if key := ref.Kind + ":" + ref.ID; !seen[key] {
+				seen[key] = true
+				refs = append(refs, *ref)
+			}
+		}
+		b.WriteString(html.EscapeString(text[last:]))
+	}
+	return b.String(), refs
+}
+
+// isContentRefOpaque 其中的文本不解析引用的标签
+func isContentRefOpaque(tag string) bool {
+	return tag == "a" || tag == "code" || tag == "pre"
+}
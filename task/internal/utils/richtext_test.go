@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// xssPayloads 常见的 XSS 注入写法，Markdown 和 HTML 两种格式都要能净化
+var xssPayloads = []string{
+	`<script>alert(1)</script>`,
+	`<SCRIPT SRC=//evil.example/x.js></SCRIPT>`,
+	`<img src=x onerror=alert(1)>`,
+	`<img src="javascript:alert(1)">`,
+	`<a href="javascript:alert(1)">x</a>`,
+	`<a href="JaVaScRiPt:alert(1)">x</a>`,
+	`<a href="java&#x09;script:alert(1)">x</a>`,
+	`<a href="&#106;avascript:alert(1)">x</a>`,
+	`<a href=" javascript:alert(1)">x</a>`,
+	`<a href="vbscript:msgbox(1)">x</a>`,
+	`<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`,
+	`<img src="data:image/svg+xml,<svg onload=alert(1)>">`,
+	`<svg><script>alert(1)</script></svg>`,
+	`<svg onload=alert(1)>`,
+	`<math><mi xlink:href="javascript:alert(1)">x</mi></math>`,
+	`<iframe src="https://evil.example"></iframe>`,
+	`<object data="https://evil.example/x.swf"></object>`,
+	`<embed src="https://evil.example/x.swf">`,
+	`<style>body{background:url(javascript:alert(1))}</style>`,
+	`<div style="background:url(javascript:alert(1))">x</div>`,
+	`<p onclick="alert(1)">x</p>`,
+	`<span class="x" onmouseover="alert(1)">x</span>`,
+	`<form action="javascript:alert(1)"><button>x</button></form>`,
+	`<input autofocus onfocus=alert(1)>`,
+	`<details open ontoggle=alert(1)>`,
+	`<meta http-equiv="refresh" content="0;url=javascript:alert(1)">`,
+	`<base href="javascript:alert(1)//">`,
+	`<a href="https://ok.example" target="_blank" onclick="alert(1)">x</a>`,
+	`<a title='x" onmouseover="alert(1)'>x</a>`,
+	`<img alt="x" src="https://ok.example/a.png" onload="alert(1)">`,
+	`<textarea><script>alert(1)</script></textarea>`,
+	`<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`,
+	`<!--<img src=x onerror=alert(1)>-->`,
+	`<scr<script>ipt>alert(1)</script>`,
+	`<<script>script>alert(1)<</script>/script>`,
+	`<img src=x onerror=alert(1)//`,
+	`</p><script>alert(1)</script>`,
+}
+
+// assertSafeHTML 逐个检查净化结果中的标签和属性都在白名单内，链接地址都是安全协议
+func assertSafeHTML(t *testing.T, input, out string) {
+	t.Helper()
+	z := xhtml.NewTokenizer(strings.NewReader(out))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			return
+		}
+		if tt == xhtml.CommentToken || tt == xhtml.DoctypeToken {
+			t.Errorf("input %q: unexpected %v in output %q", input, tt, out)
+			continue
+		}
+		if tt != xhtml.StartTagToken && tt != xhtml.SelfClosingTagToken && tt != xhtml.EndTagToken {
+			continue
+		}
+		tok := z.Token()
+		allowed, ok := sanitizeAllowedTags[tok.Data]
+		if !ok {
+			t.Errorf("input %q: tag <%s> survived in output %q", input, tok.Data, out)
+			continue
+		}
+		for _, attr := range tok.Attr {
+			if tok.Data == "a" && attr.Key == "rel" {
+				continue
+			}
+			if _, ok := sanitizeAttr(attr.Key, attr.Val, allowed); !ok {
+				t.Errorf("input %q: attribute %s=%q on <%s> survived in output %q", input, attr.Key, attr.Val, tok.Data, out)
+			}
+		}
+	}
+}
+
+func TestSanitizeHTMLStripsXSS(t *testing.T) {
+	for _, input := range xssPayloads {
+		out := SanitizeHTML(input)
+		assertSafeHTML(t, input, out)
+		if strings.Contains(strings.ToLower(out), "<script") {
+			t.Errorf("input %q: output %q contains a script tag", input, out)
+		}
+	}
+}
+
+func TestRenderRichTextHTMLStripsXSS(t *testing.T) {
+	for _, input := range xssPayloads {
+		rt := RenderRichText(ContentFormatHTML, input, nil, nil)
+		assertSafeHTML(t, input, rt.HTML)
+		if strings.Contains(rt.Text, "<script") {
+			t.Errorf("input %q: plain text %q contains a script tag", input, rt.Text)
+		}
+	}
+}
+
+func TestSanitizeHTMLKeepsSafeMarkup(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{`<p>a <strong>b</strong></p>`, `<p>a <strong>b</strong></p>`},
+		{`<a href="https://example.com/x?a=1&b=2" title="t">x</a>`, `<a href="https://example.com/x?a=1&amp;b=2" title="t" rel="noopener noreferrer nofollow">x</a>`},
+		{`<a href="/tasks/1#node">x</a>`, `<a href="/tasks/1#node" rel="noopener noreferrer nofollow">x</a>`},
+		{`<a href="mailto:a@example.com">x</a>`, `<a href="mailto:a@example.com" rel="noopener noreferrer nofollow">x</a>`},
+		{`<img src="https://example.com/a.png" alt="a" width="10" height="abc">`, `<img src="https://example.com/a.png" alt="a" width="10">`},
+		{`<pre class="language-go evil"><code class="language-go">x</code></pre>`, `<pre class="language-go"><code class="language-go">x</code></pre>`},
+		{`<td align="CENTER" colspan="2">x</td>`, `<td align="center" colspan="2">x</td>`},
+		{`<ul><li>a<li>b</ul>`, `<ul><li>a<li>b</li></li></ul>`},
+		{`<p><em>x</p>`, `<p><em>x</em></p>`},
+		{`a</div>b`, `ab`},
+		{`<custom>text</custom>`, `text`},
+		{`1 < 2 & 3 > 2`, `1 &lt; 2 &amp; 3 &gt; 2`},
+	}
+	for _, c := range cases {
+		if got := SanitizeHTML(c.input); got != c.want {
+			t.Errorf("SanitizeHTML(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestSanitizeHTMLEscapesAttributeValues(t *testing.T) {
+	out := SanitizeHTML(`<a href="https://example.com/&quot;onmouseover=&quot;alert(1)" title="&quot;><script>alert(1)</script>">x</a>`)
+	want := `<a href="https://example.com/&#34;onmouseover=&#34;alert(1)" title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" rel="noopener noreferrer nofollow">x</a>`
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestIsSafeContentURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://example.com":             true,
+		"HTTP://example.com":              true,
+		"mailto:a@example.com":            true,
+		"/relative/path":                  true,
+		"relative?a=b:c":                  true,
+		"#anchor":                         true,
+		"":                                false,
+		"javascript:alert(1)":             false,
+		" JavaScript:alert(1)":            false,
+		"java\tscript:alert(1)":           false,
+		"java\x00script:alert(1)":         false,
+		"&#106;avascript:alert(1)":        false,
+		"&#x6A;avascript&colon;alert(1)":  false,
+		"vbscript:msgbox(1)":              false,
+		"data:text/html,<script>":         false,
+		"file:///etc/passwd":              false,
+		"//evil.example/x.js":             true,
+		"blob:https://example.com/uuid":   false,
+		"  \n  ":                          false,
+		"jav&#x0A;ascript:alert(1)":       false,
+		"ftp://example.com/file":          false,
+		"x-javascript:alert(1)":           false,
+		"javascript&#58;alert(1)":         false,
+		"javascript&#0000058alert(1)":     false,
+		"%6Aavascript:alert(1)":           false,
+		"./javascript:alert(1)":           true,
+		"tasks/javascript:alert(1)":       true,
+		"?next=javascript:alert(1)":       true,
+		"data&colon;text/html,<script>":   false,
+		"\u200bjavascript:alert(1)":       false,
+		"https://example.com/\"onerror=x": true,
+	}
+	for raw, want := range cases {
+		if got := isSafeContentURL(raw); got != want {
+			t.Errorf("isSafeContentURL(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestHTMLToTextDropsMarkup(t *testing.T) {
+	got := HTMLToText(SanitizeHTML(`<p>a<script>alert(1)</script></p><p><img src=x alt="pic" onerror=alert(1)></p><p>b &amp; c</p>`))
+	if want := "a\npic\nb & c"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
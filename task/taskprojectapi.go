@@ -28,6 +28,7 @@ type empWrap struct{ e *userModel.Employee }
 
 func (w empWrap) GetEmployeeId() string { return w.e.EmployeeId }
 func (w empWrap) GetId() string         { return w.e.Id }
+func (w empWrap) GetCompanyId() string  { return w.e.CompanyId }
 
 type roleWrap struct{ r *roleModel.Role }
 
@@ -95,13 +96,20 @@ func main() {
 		}
 	})
 
+	// 全局请求审计中间件：在JWT之后、权限校验之前，被拒绝的变更请求同样留痕
+	server.Use(mw.NewAuditMiddleware(mw.AuditDeps{
+		Snapshot: ctx.AuditLogService.Snapshot,
+		Record:   ctx.AuditLogService.Record,
+	}).Handle)
+
 	// 全局权限校验中间件（在路由注册前注入）
 	deps := mw.AuthzDeps{
 		FindEmployeeByUserID: func(c context.Context, userId string) (interface {
 			GetEmployeeId() string
 			GetId() string
+			GetCompanyId() string
 		}, error) {
-			emp, err := ctx.EmployeeModel.FindByUserID(c, userId)
+			emp, err := ctx.CompanyMembershipService.CurrentEmployee(c, userId)
 			if err != nil || emp == nil {
 				return nil, err
 			}
@@ -119,6 +127,10 @@ func main() {
 			}
 			return out, nil
 		},
+		ListGrantedPermCodes: func(c context.Context, userId, companyId string) ([]int, error) {
+			// 用户直接授权/临时委派（user_permission）
+			return ctx.PermissionGrantService.ActivePermCodes(c, userId, companyId)
+		},
 	}
 	server.Use(mw.NewAuthzMiddleware(deps).Handle)
 